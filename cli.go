@@ -0,0 +1,380 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+
+	"github.com/krakend/mcp-server/tools"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// cliCommand maps a CLI subcommand to the MCP tool it invokes
+type cliCommand struct {
+	tool    string
+	toolset string // Only this toolset is registered to keep startup fast ("" registers all)
+	usage   string
+	// args parses the subcommand flags into the tool arguments
+	args func(fs *flag.FlagSet, argv []string) (map[string]any, error)
+}
+
+// cliCommands lists the subcommands that run tools without an MCP client
+var cliCommands = map[string]cliCommand{
+	"validate": {
+		tool:    "validate_config",
+		toolset: "validation",
+		usage:   "validate -c krakend.json",
+		args:    configArgs,
+	},
+	"audit": {
+		tool:    "audit_security",
+		toolset: "validation",
+		usage:   "audit [--fail-on high] [--rules policies.yaml] [--baseline file] [--update-baseline] -c krakend.json",
+		args:    auditArgs,
+	},
+	"search": {
+		tool:    "search_documentation",
+		toolset: "search",
+		usage:   `search [-n 5] "rate limit"`,
+		args: func(fs *flag.FlagSet, argv []string) (map[string]any, error) {
+			maxResults := fs.Int("n", 0, "maximum number of results")
+			if err := fs.Parse(argv); err != nil {
+				return nil, err
+			}
+			query := strings.Join(fs.Args(), " ")
+			if query == "" {
+				return nil, errors.New("a search query is required")
+			}
+			args := map[string]any{"query": query}
+			if *maxResults > 0 {
+				args["max_results"] = *maxResults
+			}
+			return args, nil
+		},
+	},
+	"features": {
+		tool:    "list_features",
+		toolset: "features",
+		usage:   "features [--ee] [--query cors]",
+		args: func(fs *flag.FlagSet, argv []string) (map[string]any, error) {
+			ee := fs.Bool("ee", false, "list Enterprise Edition features only")
+			query := fs.String("query", "", "filter by name or description")
+			if err := fs.Parse(argv); err != nil {
+				return nil, err
+			}
+			return map[string]any{"ee": *ee, "query": *query}, nil
+		},
+	},
+	"call": {
+		usage: `call <tool> ['{"json": "arguments"}' | -]`,
+	},
+}
+
+// configArgs parses the -c/--config flag (or a positional path) of config-based commands
+func configArgs(fs *flag.FlagSet, argv []string) (map[string]any, error) {
+	var config string
+	fs.StringVar(&config, "c", "", "KrakenD configuration file")
+	fs.StringVar(&config, "config", "", "KrakenD configuration file")
+	if err := fs.Parse(argv); err != nil {
+		return nil, err
+	}
+	if config == "" && fs.NArg() > 0 {
+		config = fs.Arg(0)
+	}
+	if config == "" {
+		return nil, errors.New("a configuration file is required (-c krakend.json)")
+	}
+	return map[string]any{"config": config}, nil
+}
+
+// auditArgs adds the --fail-on severity threshold, the --rules file and the audit baseline
+// flags to the config flags
+func auditArgs(fs *flag.FlagSet, argv []string) (map[string]any, error) {
+	failOn := fs.String("fail-on", "", "fail when an issue reaches this severity (critical, high, medium, low, info)")
+	rules := fs.String("rules", "", "custom rules file (defaults to .krakend-mcp-rules next to the config or in the working directory)")
+	baseline := fs.String("baseline", "", "audit baseline of accepted findings (defaults to .krakend-audit-baseline.json next to the config or in the working directory)")
+	updateBaseline := fs.Bool("update-baseline", false, "write the findings of this audit as the new baseline")
+	args, err := configArgs(fs, argv)
+	if err != nil {
+		return nil, err
+	}
+	if *failOn != "" {
+		args["fail_on"] = *failOn
+	}
+	if *rules != "" {
+		args["rules_file"] = *rules
+	}
+	if *baseline != "" {
+		args["baseline_file"] = *baseline
+	}
+	if *updateBaseline {
+		args["update_baseline"] = true
+	}
+	return args, nil
+}
+
+// isCLICommand reports whether the first argument is a CLI subcommand
+func isCLICommand(name string) bool {
+	if name == "tools" || name == "replay" {
+		return true
+	}
+	_, ok := cliCommands[name]
+	return ok
+}
+
+// runCLICommand runs a tool from the command line and returns the process exit code:
+// 0 on success, 1 when the tool failed or reported an invalid configuration, 2 on usage errors
+func runCLICommand(ctx context.Context, name string, argv []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if !hasFlag(argv, "--verbose") {
+		log.SetOutput(io.Discard)
+	}
+	argv = withoutFlag(argv, "--verbose")
+
+	if name == "tools" {
+		return listCLITools(ctx, stdout, stderr)
+	}
+	if name == "replay" {
+		return replaySession(ctx, argv, stdout, stderr)
+	}
+
+	cmd := cliCommands[name]
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: %s %s\n", serverName, cmd.usage)
+		fs.PrintDefaults()
+	}
+
+	var args map[string]any
+	var err error
+	if name == "call" {
+		cmd.tool, args, err = callArgs(argv, stdin)
+	} else {
+		args, err = cmd.args(fs, argv)
+	}
+	if err != nil {
+		if !errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintf(stderr, "%s: %v\n", name, err)
+			fs.Usage()
+		}
+		return 2
+	}
+
+	session, err := connectCLISession(ctx, cmd.toolset)
+	if err != nil {
+		fmt.Fprintf(stderr, "%s: %v\n", name, err)
+		return 1
+	}
+	defer session.Close()
+
+	res, err := session.CallTool(ctx, &mcp.CallToolParams{Name: cmd.tool, Arguments: args})
+	if err != nil {
+		fmt.Fprintf(stderr, "%s: %v\n", name, err)
+		return 1
+	}
+	return printToolResult(res, stdout, stderr)
+}
+
+// callArgs parses "call <tool> [json|-]"
+func callArgs(argv []string, stdin io.Reader) (string, map[string]any, error) {
+	if len(argv) == 0 {
+		return "", nil, errors.New("a tool name is required")
+	}
+	tool, raw := argv[0], "{}"
+	if len(argv) > 1 {
+		raw = argv[1]
+	}
+	if raw == "-" {
+		data, err := io.ReadAll(stdin)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to read arguments from stdin: %w", err)
+		}
+		raw = string(data)
+	}
+	args := map[string]any{}
+	if err := json.Unmarshal([]byte(raw), &args); err != nil {
+		return "", nil, fmt.Errorf("arguments must be a JSON object: %w", err)
+	}
+	return tool, args, nil
+}
+
+// connectCLISession starts an in-process server with the given toolset and connects a client to it,
+// so CLI commands go through exactly the same tool registration and schemas as MCP clients
+func connectCLISession(ctx context.Context, toolset string) (*mcp.ClientSession, error) {
+	// Generic commands honor the KRAKEND_MCP_TOOLSETS/KRAKEND_MCP_DISABLE_TOOLSETS selection
+	filter, err := parseToolsetFilter(nil)
+	if err != nil {
+		return nil, err
+	}
+	if toolset != "" {
+		filter = &toolsetFilter{enabled: map[string]bool{toolset: true}, disabled: map[string]bool{}}
+	}
+
+	// The logs of the commands are only shown with --verbose, so only the tools are configured
+	settings, err := parseSettings(nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := settings.configure(); err != nil {
+		return nil, err
+	}
+
+	server := createMCPServer()
+	if err := registerTools(server, filter, settings); err != nil {
+		return nil, err
+	}
+
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	if _, err := server.Connect(ctx, serverTransport, nil); err != nil {
+		return nil, err
+	}
+	client := mcp.NewClient(&mcp.Implementation{Name: serverName + "-cli", Version: version}, nil)
+	return client.Connect(ctx, clientTransport, nil)
+}
+
+// listCLITools prints every available tool with its description
+func listCLITools(ctx context.Context, stdout, stderr io.Writer) int {
+	session, err := connectCLISession(ctx, "")
+	if err != nil {
+		fmt.Fprintf(stderr, "tools: %v\n", err)
+		return 1
+	}
+	defer session.Close()
+
+	res, err := session.ListTools(ctx, nil)
+	if err != nil {
+		fmt.Fprintf(stderr, "tools: %v\n", err)
+		return 1
+	}
+	for _, tool := range res.Tools {
+		description, _, _ := strings.Cut(tool.Description, "\n")
+		fmt.Fprintf(stdout, "%-30s %s\n", tool.Name, description)
+	}
+	return 0
+}
+
+// replaySession re-executes a recorded trace against the current code and reports changed results
+func replaySession(ctx context.Context, argv []string, stdout, stderr io.Writer) int {
+	if len(argv) != 1 {
+		fmt.Fprintf(stderr, "Usage: %s replay <session.jsonl>\n", serverName)
+		return 2
+	}
+	entries, err := tools.LoadTrace(argv[0])
+	if err != nil {
+		fmt.Fprintf(stderr, "replay: %v\n", err)
+		return 1
+	}
+
+	session, err := connectCLISession(ctx, "")
+	if err != nil {
+		fmt.Fprintf(stderr, "replay: %v\n", err)
+		return 1
+	}
+	defer session.Close()
+
+	results := tools.ReplayTrace(ctx, entries, func(ctx context.Context, tool string, args map[string]any) (*mcp.CallToolResult, error) {
+		return session.CallTool(ctx, &mcp.CallToolParams{Name: tool, Arguments: args})
+	})
+
+	code := 0
+	for _, r := range results {
+		fmt.Fprintf(stdout, "#%-4d %-8s %s\n", r.Seq, strings.ToUpper(r.Status), r.Tool)
+		for _, d := range r.Differences {
+			fmt.Fprintf(stdout, "        %s\n", d)
+		}
+		if r.Error != "" {
+			fmt.Fprintf(stdout, "        error: %s\n", r.Error)
+		}
+		if r.Status != "same" {
+			code = 1
+		}
+	}
+	fmt.Fprintf(stdout, "\nReplayed %d call(s) from %s\n", len(results), argv[0])
+	return code
+}
+
+// printToolResult writes the structured output as indented JSON (or the text content)
+func printToolResult(res *mcp.CallToolResult, stdout, stderr io.Writer) int {
+	if res.IsError {
+		for _, c := range res.Content {
+			if text, ok := c.(*mcp.TextContent); ok {
+				fmt.Fprintln(stderr, text.Text)
+			}
+		}
+		return 1
+	}
+
+	if res.StructuredContent != nil {
+		data, err := json.Marshal(res.StructuredContent)
+		if err != nil {
+			fmt.Fprintf(stderr, "failed to encode result: %v\n", err)
+			return 1
+		}
+		var out bytes.Buffer
+		json.Indent(&out, data, "", "  ")
+		fmt.Fprintln(stdout, out.String())
+
+		// Validation and audit results fail the command so it can gate CI pipelines.
+		// An explicit severity gate takes precedence over the validity verdict.
+		var verdict struct {
+			Valid *bool `json:"valid"`
+			Gate  *struct {
+				Passed bool `json:"passed"`
+			} `json:"gate"`
+		}
+		if json.Unmarshal(data, &verdict) != nil {
+			return 0
+		}
+		if verdict.Gate != nil {
+			if !verdict.Gate.Passed {
+				return 1
+			}
+			return 0
+		}
+		if verdict.Valid != nil && !*verdict.Valid {
+			return 1
+		}
+		return 0
+	}
+
+	for _, c := range res.Content {
+		if text, ok := c.(*mcp.TextContent); ok {
+			fmt.Fprintln(stdout, text.Text)
+		}
+	}
+	return 0
+}
+
+func hasFlag(argv []string, flag string) bool {
+	for _, a := range argv {
+		if a == flag {
+			return true
+		}
+	}
+	return false
+}
+
+func withoutFlag(argv []string, flag string) []string {
+	out := make([]string, 0, len(argv))
+	for _, a := range argv {
+		if a != flag {
+			out = append(out, a)
+		}
+	}
+	return out
+}
+
+// cliUsage prints the available CLI subcommands
+func cliUsage(w io.Writer) {
+	fmt.Fprintf(w, "Usage: %s [--transport=stdio|http|sse] [--listen=addr] [--serve] [--toolsets=...] [--data-dir=...] [--log-level=...] | --version | setup | tools | replay <session.jsonl> | <command>\n\nCommands:\n", serverName)
+	for _, name := range []string{"validate", "audit", "search", "features", "call"} {
+		fmt.Fprintf(w, "  %s %s\n", serverName, cliCommands[name].usage)
+	}
+	fmt.Fprintf(w, "\nAdd --verbose to any command to show server logs.\n")
+}
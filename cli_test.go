@@ -0,0 +1,134 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/krakend/mcp-server/tools"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestConfigArgs(t *testing.T) {
+	for _, argv := range [][]string{{"-c", "krakend.json"}, {"--config=krakend.json"}, {"krakend.json"}} {
+		args, err := configArgs(flag.NewFlagSet("validate", flag.ContinueOnError), argv)
+		if err != nil {
+			t.Fatalf("configArgs(%v) error = %v", argv, err)
+		}
+		if args["config"] != "krakend.json" {
+			t.Errorf("configArgs(%v) = %v", argv, args)
+		}
+	}
+
+	fs := flag.NewFlagSet("validate", flag.ContinueOnError)
+	fs.SetOutput(&bytes.Buffer{})
+	if _, err := configArgs(fs, nil); err == nil {
+		t.Error("Expected error without a configuration")
+	}
+}
+
+func TestAuditArgs(t *testing.T) {
+	args, err := auditArgs(flag.NewFlagSet("audit", flag.ContinueOnError), []string{"--fail-on", "high", "-c", "krakend.json"})
+	if err != nil {
+		t.Fatalf("auditArgs() error = %v", err)
+	}
+	if args["config"] != "krakend.json" || args["fail_on"] != "high" {
+		t.Errorf("auditArgs() = %v", args)
+	}
+
+	args, err = auditArgs(flag.NewFlagSet("audit", flag.ContinueOnError), []string{"--rules", "policies.yaml", "krakend.json"})
+	if err != nil {
+		t.Fatalf("auditArgs() error = %v", err)
+	}
+	if args["config"] != "krakend.json" || args["rules_file"] != "policies.yaml" {
+		t.Errorf("auditArgs() = %v", args)
+	}
+
+	args, err = auditArgs(flag.NewFlagSet("audit", flag.ContinueOnError), []string{"--update-baseline", "--baseline", "baseline.json", "krakend.json"})
+	if err != nil {
+		t.Fatalf("auditArgs() error = %v", err)
+	}
+	if args["baseline_file"] != "baseline.json" || args["update_baseline"] != true {
+		t.Errorf("auditArgs() = %v", args)
+	}
+}
+
+func TestCallArgs(t *testing.T) {
+	tool, args, err := callArgs([]string{"probe_backends", "-"}, strings.NewReader(`{"config": "krakend.json"}`))
+	if err != nil {
+		t.Fatalf("callArgs() error = %v", err)
+	}
+	if tool != "probe_backends" || args["config"] != "krakend.json" {
+		t.Errorf("callArgs() = %s %v", tool, args)
+	}
+
+	if _, _, err := callArgs([]string{"probe_backends", "[1]"}, nil); err == nil {
+		t.Error("Expected error for non-object arguments")
+	}
+}
+
+func TestPrintToolResult_ExitCodes(t *testing.T) {
+	tests := []struct {
+		name string
+		res  *mcp.CallToolResult
+		want int
+	}{
+		{"valid", &mcp.CallToolResult{StructuredContent: map[string]any{"valid": true}}, 0},
+		{"invalid", &mcp.CallToolResult{StructuredContent: map[string]any{"valid": false}}, 1},
+		{"no verdict", &mcp.CallToolResult{StructuredContent: map[string]any{"results": []any{}}}, 0},
+		{"gate passed", &mcp.CallToolResult{StructuredContent: map[string]any{"valid": false, "gate": map[string]any{"passed": true}}}, 0},
+		{"gate failed", &mcp.CallToolResult{StructuredContent: map[string]any{"valid": true, "gate": map[string]any{"passed": false}}}, 1},
+		{"tool error", &mcp.CallToolResult{IsError: true, Content: []mcp.Content{&mcp.TextContent{Text: "boom"}}}, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if got := printToolResult(tt.res, &stdout, &stderr); got != tt.want {
+				t.Errorf("printToolResult() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunCLICommand_Validate(t *testing.T) {
+	config := filepath.Join(t.TempDir(), "krakend.json")
+	if err := os.WriteFile(config, []byte(`{"version": 3, "endpoints": []}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := runCLICommand(context.Background(), "validate", []string{"-c", config}, nil, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("runCLICommand() = %d, stderr: %s", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), `"valid": true`) {
+		t.Errorf("Expected valid result, got %s", stdout.String())
+	}
+}
+
+func TestRunCLICommand_StrictFromEnvironment(t *testing.T) {
+	for _, def := range serverSettings {
+		t.Setenv(def.env, "")
+	}
+	t.Setenv("KRAKEND_MCP_STRICT", "true")
+	// Neither krakend nor Docker: strict mode refuses the schema fallback
+	t.Setenv("PATH", t.TempDir())
+	t.Cleanup(func() { tools.ConfigureStrictValidation(false) })
+	config := filepath.Join(t.TempDir(), "krakend.json")
+	if err := os.WriteFile(config, []byte(`{"version": 3, "endpoints": []}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := runCLICommand(context.Background(), "validate", []string{"-c", config}, nil, &stdout, &stderr); code != 1 {
+		t.Fatalf("runCLICommand() = %d, want 1 (stdout: %s, stderr: %s)", code, stdout.String(), stderr.String())
+	}
+	if strings.Contains(stdout.String(), `"method": "schema"`) {
+		t.Errorf("Strict mode must not fall back to the schema, got %s", stdout.String())
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/blevesearch/bleve/v2"
 	"github.com/krakend/mcp-server/internal/indexing"
@@ -26,10 +27,16 @@ func main() {
 
 	// Step 1: Parse documentation
 	log.Printf("Parsing documentation: %s", docsFile)
-	chunks, err := indexing.ParseDocumentation(docsFile)
+	content, err := os.ReadFile(docsFile)
 	if err != nil {
 		log.Fatalf("Failed to parse documentation: %v", err)
 	}
+	pages := indexing.SplitPages(string(content))
+	chunks := indexing.ParsePages(pages)
+
+	// Every page of the embedded snapshot is stamped with the build date
+	manifest := indexing.BuildPageManifest(pages, chunks, &indexing.PageManifest{}, time.Now().UTC().Format(time.RFC3339))
+	indexing.StampChunks(chunks, manifest)
 
 	// Calculate statistics
 	totalTokens := 0
@@ -45,7 +52,7 @@ func main() {
 		avgTokens = totalTokens / len(chunks)
 	}
 
-	log.Printf("✓ Parsed %d chunks (avg: %d tokens, %d oversized)", len(chunks), avgTokens, oversized)
+	log.Printf("✓ Parsed %d chunks from %d pages (avg: %d tokens, %d oversized)", len(chunks), len(pages), avgTokens, oversized)
 
 	// Step 2: Remove existing index
 	if err := os.RemoveAll(indexDir); err != nil && !os.IsNotExist(err) {
@@ -102,7 +109,14 @@ func main() {
 
 	log.Printf("✓ Indexed %d chunks successfully", len(chunks))
 
-	// Step 5: Write version file
+	// Step 5: Write the page manifest next to the documentation, used by partial refreshes
+	manifestFile := filepath.Join(filepath.Dir(docsFile), indexing.PageManifestFile)
+	if err := indexing.WritePageManifest(manifestFile, manifest); err != nil {
+		log.Fatalf("Failed to write page manifest: %v", err)
+	}
+	log.Printf("✓ Page manifest: %s (%d pages)", manifestFile, len(pages))
+
+	// Step 6: Write version file
 	versionFile := filepath.Join(filepath.Dir(indexDir), ".index_version")
 	versionContent := fmt.Sprintf("%d", indexing.IndexSchemaVersion)
 	if err := os.WriteFile(versionFile, []byte(versionContent), 0644); err != nil {
@@ -118,5 +132,5 @@ func main() {
 	log.Printf("  Location:     %s", indexDir)
 	log.Printf("  Total chunks: %d", len(chunks))
 	log.Printf("  Avg size:     %d tokens (~%d chars)", avgTokens, avgTokens*indexing.CharsPerToken)
-	log.Printf("  Schema:       v%d (optimized chunking with metadata)", indexing.IndexSchemaVersion)
+	log.Printf("  Schema:       v%d (page-scoped chunks with freshness metadata)", indexing.IndexSchemaVersion)
 }
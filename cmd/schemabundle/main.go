@@ -0,0 +1,155 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// schemaBaseURL is where KrakenD publishes its JSON schemas
+const schemaBaseURL = "https://www.krakend.io/schema/"
+
+var errNotFound = errors.New("not found")
+
+func main() {
+	if len(os.Args) != 2 {
+		fmt.Fprintf(os.Stderr, "Usage: %s <bundle-dir>\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "\nDownloads the latest and every versioned KrakenD JSON schema (CE and EE),\n")
+		fmt.Fprintf(os.Stderr, "with the schemas they reference, for offline validation.\n")
+		fmt.Fprintf(os.Stderr, "\nExample:\n")
+		fmt.Fprintf(os.Stderr, "  %s tools/data/schemas\n", os.Args[0])
+		os.Exit(1)
+	}
+	dir := os.Args[1]
+
+	log.Printf("KrakenD Schema Bundler")
+	log.Printf("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+
+	// Start from scratch, keeping the placeholder that satisfies go:embed
+	entries, err := os.ReadDir(dir)
+	if err != nil && !os.IsNotExist(err) {
+		log.Fatalf("Failed to read %s: %v", dir, err)
+	}
+	for _, entry := range entries {
+		if entry.Name() != ".gitkeep" {
+			if err := os.RemoveAll(filepath.Join(dir, entry.Name())); err != nil {
+				log.Fatalf("Failed to remove old schemas: %v", err)
+			}
+		}
+	}
+
+	b := &bundler{dir: dir, client: &http.Client{Timeout: 30 * time.Second}, seen: map[string]bool{}}
+	for _, latest := range []string{"krakend.json", "ee/krakend.json"} {
+		if err := b.add(schemaBaseURL + latest); err != nil {
+			log.Fatalf("Failed to download the latest schema: %v", err)
+		}
+	}
+
+	// Versions are published without gaps since 2.0; Enterprise schemas may start later
+	versions := 0
+	for minor := 0; ; minor++ {
+		version := fmt.Sprintf("v2.%d/krakend.json", minor)
+		err := b.add(schemaBaseURL + version)
+		if errors.Is(err, errNotFound) {
+			break
+		}
+		if err != nil {
+			log.Fatalf("Failed to download %s: %v", version, err)
+		}
+		versions++
+		if err := b.add(schemaBaseURL + "ee/" + version); err != nil && !errors.Is(err, errNotFound) {
+			log.Fatalf("Failed to download ee/%s: %v", version, err)
+		}
+	}
+
+	log.Printf("✓ Bundled %d files for %d versions in %s", len(b.seen), versions, dir)
+}
+
+// bundler downloads schemas and the schemas they reference into dir
+type bundler struct {
+	dir    string
+	client *http.Client
+	seen   map[string]bool
+}
+
+// add downloads a schema, unless already bundled, and then every schema it references
+func (b *bundler) add(schemaURL string) error {
+	if b.seen[schemaURL] {
+		return nil
+	}
+	data, err := b.download(schemaURL)
+	if err != nil {
+		return err
+	}
+	var doc any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("%s is not JSON: %w", schemaURL, err)
+	}
+
+	target := filepath.Join(b.dir, filepath.FromSlash(strings.TrimPrefix(schemaURL, schemaBaseURL)))
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(target, data, 0o644); err != nil {
+		return err
+	}
+	b.seen[schemaURL] = true
+
+	base, _ := url.Parse(schemaURL)
+	for _, ref := range references(doc) {
+		refURL, err := base.Parse(ref)
+		if err != nil {
+			continue
+		}
+		refURL.Fragment = ""
+		if resolved := refURL.String(); strings.HasPrefix(resolved, schemaBaseURL) {
+			if err := b.add(resolved); err != nil {
+				return fmt.Errorf("%s referenced by %s: %w", resolved, schemaURL, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (b *bundler) download(schemaURL string) ([]byte, error) {
+	resp, err := b.client.Get(schemaURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, errNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// references returns the $ref values of a schema document
+func references(doc any) []string {
+	var refs []string
+	switch v := doc.(type) {
+	case map[string]any:
+		for key, value := range v {
+			if ref, ok := value.(string); ok && key == "$ref" {
+				refs = append(refs, ref)
+				continue
+			}
+			refs = append(refs, references(value)...)
+		}
+	case []any:
+		for _, item := range v {
+			refs = append(refs, references(item)...)
+		}
+	}
+	return refs
+}
@@ -3,11 +3,14 @@ module github.com/krakend/mcp-server
 go 1.25.0
 
 require (
+	github.com/BurntSushi/toml v1.4.0
 	github.com/blevesearch/bleve/v2 v2.5.6
 	github.com/go-contrib/uuid v1.2.0
+	github.com/google/jsonschema-go v0.4.2
 	github.com/krakend/krakend-usage/v2 v2.1.0
 	github.com/modelcontextprotocol/go-sdk v1.4.1
 	github.com/santhosh-tekuri/jsonschema/v6 v6.0.2
+	github.com/yosida95/uritemplate/v3 v3.0.2
 	gopkg.in/yaml.v3 v3.0.1
 )
 
@@ -33,12 +36,10 @@ require (
 	github.com/blevesearch/zapx/v16 v16.2.7 // indirect
 	github.com/catalinc/hashcash v0.0.0-20161205220751-e6bc29ff4de9 // indirect
 	github.com/golang/snappy v0.0.4 // indirect
-	github.com/google/jsonschema-go v0.4.2 // indirect
 	github.com/json-iterator/go v0.0.0-20171115153421-f7279a603ede // indirect
 	github.com/mschoch/smat v0.2.0 // indirect
 	github.com/segmentio/asm v1.1.3 // indirect
 	github.com/segmentio/encoding v0.5.4 // indirect
-	github.com/yosida95/uritemplate/v3 v3.0.2 // indirect
 	go.etcd.io/bbolt v1.4.0 // indirect
 	golang.org/x/oauth2 v0.34.0 // indirect
 	golang.org/x/sys v0.40.0 // indirect
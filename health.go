@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/krakend/mcp-server/internal/metrics"
+	"github.com/krakend/mcp-server/tools"
+)
+
+// readinessReport is the body of the /readyz endpoint
+type readinessReport struct {
+	Status string              `json:"status"` // ready or not_ready
+	Checks []tools.HealthCheck `json:"checks"`
+}
+
+// readinessChecks returns the checks of the components used by the enabled toolsets
+func readinessChecks(ctx context.Context, filter *toolsetFilter) []tools.HealthCheck {
+	checks := []tools.HealthCheck{}
+	if ctx.Err() != nil {
+		checks = append(checks, tools.HealthCheck{Name: "server", Status: tools.HealthFailed, Detail: "shutting down"})
+	}
+	if filter.allows("search") {
+		checks = append(checks, tools.DocIndexHealth())
+	}
+	if filter.allows("features") {
+		checks = append(checks, tools.FeatureDataHealth())
+	}
+	if filter.allows("validation") {
+		checks = append(checks, tools.ValidationHealth())
+	}
+	return checks
+}
+
+// writeHealth writes a health response, 503 when the server is not ready
+func writeHealth(w http.ResponseWriter, ready bool, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if !ready {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.NewEncoder(w).Encode(body)
+}
+
+// registerHealthEndpoints adds the probes of the HTTP transports to the mux, so orchestrators
+// manage the server like any other service:
+//   - /healthz (liveness): the process serves HTTP requests
+//   - /readyz (readiness): the documentation index, the feature data and the validation
+//     environment of the enabled toolsets are ready. Degraded components do not block readiness.
+func registerHealthEndpoints(ctx context.Context, mux *http.ServeMux, filter *toolsetFilter) {
+	started := time.Now()
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		writeHealth(w, true, map[string]interface{}{
+			"status":         "ok",
+			"version":        version,
+			"uptime_seconds": int(time.Since(started).Seconds()),
+		})
+	})
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		report := readinessReport{Status: "ready", Checks: readinessChecks(ctx, filter)}
+		for _, check := range report.Checks {
+			if !check.Ready() {
+				report.Status = "not_ready"
+			}
+		}
+		writeHealth(w, report.Status == "ready", report)
+	})
+}
+
+// registerMetricsEndpoint serves the server metrics on /metrics in the Prometheus text format,
+// only when they are collected (--metrics)
+func registerMetricsEndpoint(mux *http.ServeMux) {
+	registry := metrics.Default()
+	if registry == nil {
+		return
+	}
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		registry.WritePrometheus(w)
+	})
+}
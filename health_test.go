@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/krakend/mcp-server/internal/metrics"
+	"github.com/krakend/mcp-server/tools"
+)
+
+func TestHealthEndpoints(t *testing.T) {
+	get := func(ctx context.Context, filter *toolsetFilter, path string) (int, map[string]interface{}) {
+		mux := http.NewServeMux()
+		registerHealthEndpoints(ctx, mux, filter)
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		var body map[string]interface{}
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("%s returned invalid JSON: %s", path, rec.Body)
+		}
+		return rec.Code, body
+	}
+	fleetOnly := &toolsetFilter{enabled: map[string]bool{"fleet": true}, disabled: map[string]bool{}}
+	validationOnly := &toolsetFilter{enabled: map[string]bool{"validation": true}, disabled: map[string]bool{}}
+
+	if code, body := get(context.Background(), validationOnly, "/healthz"); code != http.StatusOK || body["status"] != "ok" || body["version"] != version {
+		t.Errorf("/healthz = %d %v", code, body)
+	}
+	if code, body := get(context.Background(), fleetOnly, "/readyz"); code != http.StatusOK || body["status"] != "ready" {
+		t.Errorf("/readyz without components to wait for = %d %v", code, body)
+	}
+
+	// Only the checks of the enabled toolsets are reported, and they decide the status code
+	code, body := get(context.Background(), validationOnly, "/readyz")
+	checks, _ := body["checks"].([]interface{})
+	if len(checks) != 1 || checks[0].(map[string]interface{})["name"] != "validation_environment" {
+		t.Fatalf("/readyz = %d %v", code, body)
+	}
+	ready := tools.HealthCheck{Status: checks[0].(map[string]interface{})["status"].(string)}.Ready()
+	if (code == http.StatusOK) != ready || (body["status"] == "ready") != ready {
+		t.Errorf("/readyz = %d %v, ready = %v", code, body, ready)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if code, body := get(ctx, fleetOnly, "/readyz"); code != http.StatusServiceUnavailable || body["status"] != "not_ready" {
+		t.Errorf("/readyz while shutting down = %d %v", code, body)
+	}
+}
+
+func TestMetricsEndpoint(t *testing.T) {
+	metrics.Disable()
+	mux := http.NewServeMux()
+	registerMetricsEndpoint(mux)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("/metrics without --metrics = %d", rec.Code)
+	}
+
+	metrics.Enable().Observe("get_capabilities", "success", time.Millisecond)
+	t.Cleanup(metrics.Disable)
+	mux = http.NewServeMux()
+	registerMetricsEndpoint(mux)
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `krakend_mcp_tool_calls_total{tool="get_capabilities",outcome="success"} 1`) {
+		t.Errorf("/metrics = %d %s", rec.Code, rec.Body)
+	}
+}
@@ -0,0 +1,121 @@
+// Package events is the internal publish/subscribe bus of the server. Subsystems publish what
+// happened (a configuration validated, the documentation index refreshed...) and the consumers
+// (webhook notifications, MCP notifications, metrics, config memory) subscribe to it, so neither
+// side knows about the other.
+package events
+
+import (
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Topic identifies a kind of event
+type Topic string
+
+// Topics published by the server
+const (
+	ConfigValidated    Topic = "config_validated"    // Data: validation.ValidationResult
+	AuditFinished      Topic = "audit_finished"      // Data: validation.AuditSecurityOutput
+	DocsRefreshed      Topic = "docs_refreshed"      // Data: the documentation refresh stats
+	EnvironmentChanged Topic = "environment_changed" // Data: Environment
+
+	// Topics of the subsystems watching files and running gateways
+	ConfigChanged  Topic = "config_changed"  // Subject: the changed configuration file
+	GatewayStarted Topic = "gateway_started" // Subject: the configuration file run, Data: the gateway URL
+)
+
+// Event is a published event
+type Event struct {
+	Topic   Topic
+	Subject string // What the event is about, e.g. the tool input (JSON string or file path) of a validation
+	Data    any
+	Time    time.Time
+}
+
+// Environment is the data of EnvironmentChanged: the execution backends available
+type Environment struct {
+	KrakenD bool
+	Docker  bool
+}
+
+// Handler consumes the events of a topic
+type Handler func(Event)
+
+type subscription struct {
+	id      uint64
+	handler Handler
+}
+
+// Bus delivers events to the handlers subscribed to their topic
+type Bus struct {
+	mu     sync.RWMutex
+	nextID uint64
+	subs   map[Topic][]subscription
+}
+
+// New creates an empty bus
+func New() *Bus {
+	return &Bus{subs: map[Topic][]subscription{}}
+}
+
+// Subscribe registers a handler for a topic and returns the function removing it
+func (b *Bus) Subscribe(topic Topic, handler Handler) (unsubscribe func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.nextID++
+	id := b.nextID
+	b.subs[topic] = append(b.subs[topic], subscription{id: id, handler: handler})
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			subs := b.subs[topic]
+			for i, s := range subs {
+				if s.id == id {
+					b.subs[topic] = append(subs[:i:i], subs[i+1:]...)
+					break
+				}
+			}
+		})
+	}
+}
+
+// Publish delivers an event to the handlers of its topic, in subscription order, before
+// returning. Handlers doing slow work (e.g. network calls) must hand it off to a goroutine.
+// A panicking handler is logged and does not prevent the delivery to the others.
+func (b *Bus) Publish(e Event) {
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	b.mu.RLock()
+	subs := b.subs[e.Topic]
+	b.mu.RUnlock()
+	for _, s := range subs {
+		deliver(s.handler, e)
+	}
+}
+
+func deliver(handler Handler, e Event) {
+	defer func() {
+		if r := recover(); r != nil {
+			slog.Error("Event handler panicked", "topic", e.Topic, "panic", r)
+		}
+	}()
+	handler(e)
+}
+
+// Default is the bus shared by the whole server
+var Default = New()
+
+// Subscribe registers a handler for a topic of the default bus
+func Subscribe(topic Topic, handler Handler) (unsubscribe func()) {
+	return Default.Subscribe(topic, handler)
+}
+
+// Publish delivers an event with the default bus
+func Publish(topic Topic, subject string, data any) {
+	Default.Publish(Event{Topic: topic, Subject: subject, Data: data})
+}
@@ -0,0 +1,36 @@
+package events
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestBus(t *testing.T) {
+	bus := New()
+	var got []string
+	bus.Subscribe(ConfigValidated, func(e Event) { got = append(got, "first:"+e.Subject) })
+	bus.Subscribe(ConfigValidated, func(Event) { panic("broken handler") })
+	unsubscribe := bus.Subscribe(ConfigValidated, func(e Event) { got = append(got, "second:"+e.Subject) })
+	bus.Subscribe(AuditFinished, func(e Event) { got = append(got, "audit:"+e.Subject) })
+
+	bus.Publish(Event{Topic: ConfigValidated, Subject: "krakend.json"})
+	unsubscribe()
+	unsubscribe()
+	bus.Publish(Event{Topic: ConfigValidated, Subject: "other.json"})
+	bus.Publish(Event{Topic: DocsRefreshed})
+
+	want := []string{"first:krakend.json", "second:krakend.json", "first:other.json"}
+	if !slices.Equal(got, want) {
+		t.Errorf("Delivered %v, want %v (in subscription order, despite the panicking handler)", got, want)
+	}
+}
+
+func TestPublish_Time(t *testing.T) {
+	bus := New()
+	var event Event
+	bus.Subscribe(GatewayStarted, func(e Event) { event = e })
+	bus.Publish(Event{Topic: GatewayStarted, Subject: "krakend.json", Data: "http://localhost:8080"})
+	if event.Time.IsZero() || event.Data != "http://localhost:8080" {
+		t.Errorf("Unexpected event %+v", event)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"unicode/utf8"
 )
 
 // ForceSplitText splits text by character count at word boundaries
@@ -27,11 +28,13 @@ func ForceSplitText(text string, maxChars, overlapChars int) []string {
 			}
 		}
 
+		// Never split a UTF-8 character
+		chunkSize = runeStart(text, chunkSize)
 		parts = append(parts, text[:chunkSize])
 
 		// Move forward with overlap
 		if chunkSize+overlapChars < len(text) {
-			text = text[chunkSize-overlapChars:]
+			text = text[runeStart(text, chunkSize-overlapChars):]
 		} else {
 			text = text[chunkSize:]
 		}
@@ -40,6 +43,15 @@ func ForceSplitText(text string, maxChars, overlapChars int) []string {
 	return parts
 }
 
+// runeStart moves i back to the start of the UTF-8 character it falls in. It moves at most
+// utf8.UTFMax-1 bytes, so invalid UTF-8 cannot stall the splitting.
+func runeStart(s string, i int) int {
+	for back := 0; back < utf8.UTFMax-1 && i > 0 && i < len(s) && !utf8.RuneStart(s[i]); back++ {
+		i--
+	}
+	return i
+}
+
 // improveSubchunkTitle extracts the first H3-H5 header from content and uses it as subcategory
 // Category (H2 parent) is kept for proper hierarchy
 // If no header found, adds a part suffix to the category name
@@ -97,7 +109,7 @@ func SubdivideChunk(chunk DocChunk, breadcrumbParts []string, baseURL string) []
 			if currentContent.Len() > 0 {
 				content := currentContent.String()
 				if previousContent != "" && len(previousContent) > overlapChars {
-					overlap := previousContent[len(previousContent)-overlapChars:]
+					overlap := previousContent[runeStart(previousContent, len(previousContent)-overlapChars):]
 					content = overlap + "\n\n" + content
 				}
 
@@ -151,7 +163,7 @@ func SubdivideChunk(chunk DocChunk, breadcrumbParts []string, baseURL string) []
 
 				// Add overlap from previous chunk
 				if previousContent != "" && len(previousContent) > overlapChars {
-					overlap := previousContent[len(previousContent)-overlapChars:]
+					overlap := previousContent[runeStart(previousContent, len(previousContent)-overlapChars):]
 					content = overlap + "\n\n" + content
 				} else if previousContent != "" {
 					content = previousContent + "\n\n" + content
@@ -189,7 +201,7 @@ func SubdivideChunk(chunk DocChunk, breadcrumbParts []string, baseURL string) []
 
 		// Add overlap from previous chunk
 		if previousContent != "" && len(previousContent) > overlapChars {
-			overlap := previousContent[len(previousContent)-overlapChars:]
+			overlap := previousContent[runeStart(previousContent, len(previousContent)-overlapChars):]
 			content = overlap + "\n\n" + content
 		} else {
 			content = previousContent + "\n\n" + content
@@ -242,8 +254,26 @@ func ParseDocumentation(docsFile string) ([]DocChunk, error) {
 		return nil, fmt.Errorf("failed to read documentation: %w", err)
 	}
 
-	text := string(content)
-	lines := strings.Split(text, "\n")
+	return ParsePages(SplitPages(string(content))), nil
+}
+
+// ParsePages parses documentation pages into chunks. Chunk IDs are prefixed with the page ID,
+// so the chunks of unchanged pages keep their IDs when other pages change.
+func ParsePages(pages []DocPage) []DocChunk {
+	var chunks []DocChunk
+	for _, page := range pages {
+		pageChunks := parsePage(page)
+		for i := range pageChunks {
+			pageChunks[i].PageID = page.ID
+		}
+		chunks = append(chunks, pageChunks...)
+	}
+	return chunks
+}
+
+// parsePage parses the text of one page into chunks
+func parsePage(page DocPage) []DocChunk {
+	lines := strings.Split(page.Text, "\n")
 
 	var finalChunks []DocChunk
 	var currentChunk *DocChunk
@@ -264,7 +294,7 @@ func ParseDocumentation(docsFile string) ([]DocChunk, error) {
 					// Keep original ID structure for subchunks
 				} else {
 					// Reindex single chunks
-					subchunks[i].ID = fmt.Sprintf("chunk_%d", chunkID)
+					subchunks[i].ID = fmt.Sprintf("%s_%d", page.ID, chunkID)
 					chunkID++
 				}
 			}
@@ -298,7 +328,7 @@ func ParseDocumentation(docsFile string) ([]DocChunk, error) {
 			breadcrumbParts = append(breadcrumbParts, title)
 
 			currentChunk = &DocChunk{
-				ID:          fmt.Sprintf("chunk_%d", chunkID),
+				ID:          fmt.Sprintf("%s_%d", page.ID, chunkID),
 				Subcategory: title,
 				Category:    title,
 			}
@@ -324,7 +354,7 @@ func ParseDocumentation(docsFile string) ([]DocChunk, error) {
 
 			// Start new chunk for category
 			currentChunk = &DocChunk{
-				ID:       fmt.Sprintf("chunk_%d", chunkID),
+				ID:       fmt.Sprintf("%s_%d", page.ID, chunkID),
 				Subcategory: category,
 				Category:  category,
 				Page: category,
@@ -343,5 +373,5 @@ func ParseDocumentation(docsFile string) ([]DocChunk, error) {
 	// Save last chunk
 	saveCurrentChunk()
 
-	return finalChunks, nil
+	return finalChunks
 }
@@ -15,6 +15,7 @@ const (
 	CharsPerToken = 4
 
 	// IndexSchemaVersion increments when chunking logic changes
-	// v1: basic chunking (line-based), v2: optimized chunking with metadata
-	IndexSchemaVersion = 2
+	// v1: basic chunking (line-based), v2: optimized chunking with metadata,
+	// v3: page-scoped chunk IDs with page freshness metadata
+	IndexSchemaVersion = 3
 )
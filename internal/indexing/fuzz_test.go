@@ -0,0 +1,46 @@
+package indexing
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+// FuzzParsePages parses documentation as ParseDocumentation does: downloaded from the web, it is untrusted
+func FuzzParsePages(f *testing.F) {
+	f.Add("# [Rate limit](https://www.krakend.io/docs/endpoints/rate-limit/)\n\nIntro\n\n## Router\n\nThe qos/ratelimit/router namespace.\n\n### Burst\n\nMore text.")
+	f.Add("# Page\n" + strings.Repeat("word ", 2000))
+	f.Add("## No page header\n\n" + strings.Repeat("Sentence one. ", 400))
+	f.Add("#\n##\n###\n# [](\n## ](x)")
+	f.Fuzz(func(t *testing.T, text string) {
+		chunks := ParsePages(SplitPages(text))
+		seen := map[string]bool{}
+		for _, chunk := range chunks {
+			if seen[chunk.ID] {
+				t.Errorf("Duplicated chunk ID %s", chunk.ID)
+			}
+			seen[chunk.ID] = true
+			if utf8.ValidString(text) && !utf8.ValidString(chunk.Content) {
+				t.Errorf("Chunk %s splits a UTF-8 character: %q", chunk.ID, chunk.Content)
+			}
+		}
+	})
+}
+
+func FuzzSubdivideChunk(f *testing.F) {
+	f.Add(strings.Repeat("word ", 1000))
+	f.Add(strings.Repeat("Paragraph text here.\n\n", 300))
+	f.Add(strings.Repeat("ñandú ", 900) + "\n\n" + strings.Repeat("x", 5000))
+	f.Add(strings.Repeat("€", 3000))
+	f.Fuzz(func(t *testing.T, content string) {
+		chunk := DocChunk{ID: "page_0", Page: "Page", Category: "Category", Subcategory: "Category", Content: content}
+		for _, sub := range SubdivideChunk(chunk, []string{"Page", "Category"}, "https://www.krakend.io/docs/") {
+			if EstimateTokens(sub.Content) > MaxChunkTokens+OverlapTokens+1 {
+				t.Errorf("Subchunk %s has %d tokens", sub.ID, EstimateTokens(sub.Content))
+			}
+			if utf8.ValidString(content) && !utf8.ValidString(sub.Content) {
+				t.Errorf("Subchunk %s splits a UTF-8 character", sub.ID)
+			}
+		}
+	})
+}
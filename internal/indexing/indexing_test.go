@@ -480,6 +480,55 @@ Details about token bucket algorithm.
 	t.Logf("Target: %d tokens, Max: %d tokens", indexing.TargetChunkTokens, indexing.MaxChunkTokens)
 }
 
+func TestPageManifest(t *testing.T) {
+	v1 := `# [Endpoints](https://www.krakend.io/docs/endpoints/)
+Endpoints declare the public API.
+
+# [CORS](https://www.krakend.io/docs/service-settings/cors/)
+Cross-origin requests.
+
+# [Timeouts](https://www.krakend.io/docs/throttling/timeouts/)
+Default timeout is 2s.
+`
+	v2 := strings.Replace(v1, "Default timeout is 2s.", "Default timeout is 3s.", 1)
+	v2 = strings.Replace(v2, "# [CORS](https://www.krakend.io/docs/service-settings/cors/)\nCross-origin requests.\n\n", "", 1)
+
+	pages := indexing.SplitPages(v1)
+	if len(pages) != 3 {
+		t.Fatalf("Expected 3 pages, got %d", len(pages))
+	}
+	chunks := indexing.ParsePages(pages)
+	manifest := indexing.BuildPageManifest(pages, chunks, &indexing.PageManifest{}, "2026-01-01T00:00:00Z")
+	for _, chunk := range chunks {
+		if !strings.HasPrefix(chunk.ID, chunk.PageID+"_") {
+			t.Errorf("Chunk %s is not scoped to its page %s", chunk.ID, chunk.PageID)
+		}
+	}
+
+	updated := indexing.SplitPages(v2)
+	if updated[0].ID != pages[0].ID || updated[1].ID != pages[2].ID {
+		t.Error("Page IDs should be stable across snapshots")
+	}
+	changes := manifest.DiffPages(updated)
+	if len(changes.Changed) != 1 || changes.Changed[0] != pages[2].ID {
+		t.Errorf("Expected only the timeouts page to change, got %v", changes.Changed)
+	}
+	if len(changes.Removed) != 1 || changes.Removed[0] != pages[1].ID {
+		t.Errorf("Expected the CORS page to be removed, got %v", changes.Removed)
+	}
+
+	next := indexing.BuildPageManifest(updated, indexing.ParsePages(updated), manifest, "2026-02-01T00:00:00Z")
+	if got := next.Pages[pages[0].ID].UpdatedAt; got != "2026-01-01T00:00:00Z" {
+		t.Errorf("Unchanged page should keep its update date, got %s", got)
+	}
+	if got := next.Pages[pages[2].ID].UpdatedAt; got != "2026-02-01T00:00:00Z" {
+		t.Errorf("Changed page should get the new update date, got %s", got)
+	}
+	if len(next.Pages[pages[2].ID].Chunks) == 0 {
+		t.Error("Manifest should list the chunks of each page")
+	}
+}
+
 // Helper functions for file operations in tests
 func saveTestFile(path, content string) error {
 	// Implementation depends on file structure
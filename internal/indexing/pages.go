@@ -0,0 +1,170 @@
+package indexing
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// PageManifestFile is the name of the page manifest, stored next to llms-full.txt
+const PageManifestFile = "pages.json"
+
+// DocPage is an H1 section of the documentation: the unit of freshness tracking
+type DocPage struct {
+	ID    string // Stable across refreshes: derived from the page URL (title without URL)
+	Title string
+	URL   string
+	Hash  string // SHA-256 of the page text
+	Text  string
+}
+
+// PageEntry is the state of a page when it was last indexed
+type PageEntry struct {
+	Title     string   `json:"title"`
+	URL       string   `json:"url,omitempty"`
+	Hash      string   `json:"hash"`
+	UpdatedAt string   `json:"updated_at"` // When the page content last changed (RFC3339)
+	Chunks    []string `json:"chunks"`
+}
+
+// PageManifest lists the indexed pages with their hashes and chunk IDs
+type PageManifest struct {
+	Pages map[string]PageEntry `json:"pages"`
+}
+
+// isPageHeader reports whether a line starts a page (H1), as ParseDocumentation detects it
+func isPageHeader(line string) bool {
+	return strings.HasPrefix(line, "#") && !strings.HasPrefix(line, "##")
+}
+
+// SplitPages splits the documentation into H1 pages. Text before the first H1 is kept as
+// an untitled page.
+func SplitPages(text string) []DocPage {
+	var pages []DocPage
+	var current *DocPage
+	var body strings.Builder
+	seen := map[string]int{}
+
+	flush := func() {
+		if current == nil {
+			return
+		}
+		current.Text = body.String()
+		sum := sha256.Sum256([]byte(current.Text))
+		current.Hash = hex.EncodeToString(sum[:])
+		if strings.TrimSpace(current.Text) != "" {
+			pages = append(pages, *current)
+		}
+	}
+
+	for _, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if isPageHeader(trimmed) || current == nil {
+			flush()
+			current = &DocPage{}
+			body.Reset()
+			if isPageHeader(trimmed) {
+				raw := strings.TrimSpace(strings.TrimLeft(trimmed, "#"))
+				current.URL = ExtractURLFromMarkdown(raw)
+				current.Title = StripMarkdownLinks(raw)
+			}
+			key := current.URL
+			if key == "" {
+				key = current.Title
+			}
+			// Pages sharing a URL or title get an occurrence suffix to keep IDs unique
+			seen[key]++
+			if seen[key] > 1 {
+				key = fmt.Sprintf("%s#%d", key, seen[key])
+			}
+			sum := sha256.Sum256([]byte(key))
+			current.ID = "page_" + hex.EncodeToString(sum[:6])
+		}
+		body.WriteString(line)
+		body.WriteString("\n")
+	}
+	flush()
+	return pages
+}
+
+// ReadPageManifest loads a page manifest; a missing file returns an empty manifest
+func ReadPageManifest(path string) (*PageManifest, error) {
+	manifest := &PageManifest{Pages: map[string]PageEntry{}}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return manifest, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, manifest); err != nil {
+		return nil, fmt.Errorf("invalid page manifest: %w", err)
+	}
+	if manifest.Pages == nil {
+		manifest.Pages = map[string]PageEntry{}
+	}
+	return manifest, nil
+}
+
+// WritePageManifest stores a page manifest
+func WritePageManifest(path string, manifest *PageManifest) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// PageChanges compares the pages of a new documentation snapshot with a manifest
+type PageChanges struct {
+	Changed []string // New pages and pages whose content changed
+	Removed []string // Pages of the manifest missing from the snapshot
+}
+
+// DiffPages returns the pages that changed since the manifest was written
+func (m *PageManifest) DiffPages(pages []DocPage) PageChanges {
+	var changes PageChanges
+	current := map[string]bool{}
+	for _, page := range pages {
+		current[page.ID] = true
+		if entry, ok := m.Pages[page.ID]; !ok || entry.Hash != page.Hash {
+			changes.Changed = append(changes.Changed, page.ID)
+		}
+	}
+	for id := range m.Pages {
+		if !current[id] {
+			changes.Removed = append(changes.Removed, id)
+		}
+	}
+	return changes
+}
+
+// BuildPageManifest records the pages and chunks of a snapshot. Unchanged pages keep the
+// update date of the previous manifest; the others are stamped with now.
+func BuildPageManifest(pages []DocPage, chunks []DocChunk, previous *PageManifest, now string) *PageManifest {
+	manifest := &PageManifest{Pages: map[string]PageEntry{}}
+	for _, page := range pages {
+		entry := PageEntry{Title: page.Title, URL: page.URL, Hash: page.Hash, UpdatedAt: now, Chunks: []string{}}
+		if old, ok := previous.Pages[page.ID]; ok && old.Hash == page.Hash && old.UpdatedAt != "" {
+			entry.UpdatedAt = old.UpdatedAt
+		}
+		manifest.Pages[page.ID] = entry
+	}
+	for _, chunk := range chunks {
+		if entry, ok := manifest.Pages[chunk.PageID]; ok {
+			entry.Chunks = append(entry.Chunks, chunk.ID)
+			manifest.Pages[chunk.PageID] = entry
+		}
+	}
+	return manifest
+}
+
+// StampChunks sets the update date of every chunk from the page manifest
+func StampChunks(chunks []DocChunk, manifest *PageManifest) {
+	for i := range chunks {
+		chunks[i].UpdatedAt = manifest.Pages[chunks[i].PageID].UpdatedAt
+	}
+}
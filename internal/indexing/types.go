@@ -11,4 +11,6 @@ type DocChunk struct {
 	Breadcrumb  string   `json:"breadcrumb,omitempty"`  // Full hierarchy: "Page > Category > Subcategory"
 	Keywords    []string `json:"keywords,omitempty"`    // Key terms extracted from content
 	TokenCount  int      `json:"token_count,omitempty"` // Estimated token count for monitoring
+	PageID      string   `json:"page_id,omitempty"`     // Page the chunk belongs to, see DocPage
+	UpdatedAt   string   `json:"updated_at,omitempty"`  // When its page content last changed (RFC3339)
 }
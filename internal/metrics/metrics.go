@@ -0,0 +1,274 @@
+// Package metrics counts tool invocations and the internal events behind them (validation
+// methods, schema cache and documentation search hits) when the server runs with --metrics.
+// Metrics are kept in memory only and never leave the server unless an HTTP client scrapes them.
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/krakend/mcp-server/internal/toolkit"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// prefix of the Prometheus metric names
+const prefix = "krakend_mcp_"
+
+// latencyBuckets are the upper bounds in seconds of the tool latency histogram: from searches
+// answered from memory to Docker validations pulling an image
+var latencyBuckets = []float64{0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+// Counter is an event counted by a single label
+type Counter struct {
+	Name  string // Prometheus name, without prefix and _total suffix
+	Label string
+	Help  string
+}
+
+// Counters of the events behind the tool calls
+var (
+	ValidationMethod = Counter{"validation_method", "method", "Validations and security audits by execution method (native, docker, schema...)"}
+	SchemaCache      = Counter{"schema_cache", "result", "JSON Schema lookups by cache result: hit, miss (downloaded) or stale (cached copy used while offline)"}
+	DocSearch        = Counter{"doc_search", "result", "Documentation searches by outcome: results, no_results or warming_up"}
+)
+
+// counters are the known counters, in report order
+var counters = []Counter{ValidationMethod, SchemaCache, DocSearch}
+
+var current atomic.Pointer[Registry]
+
+// Enable starts collecting metrics in a new registry, which it returns
+func Enable() *Registry {
+	r := NewRegistry()
+	current.Store(r)
+	return r
+}
+
+// Disable stops collecting metrics (used by tests)
+func Disable() {
+	current.Store(nil)
+}
+
+// Default returns the registry in use, nil when metrics are disabled
+func Default() *Registry {
+	return current.Load()
+}
+
+// Count increments a counter of the registry in use. It does nothing when metrics are disabled.
+func Count(c Counter, value string) {
+	if r := current.Load(); r != nil {
+		r.Add(c, value)
+	}
+}
+
+// toolStats are the counters of a tool
+type toolStats struct {
+	outcomes map[string]uint64
+	buckets  []uint64 // Calls per latency bucket, not cumulative; the last one is +Inf
+	sum      time.Duration
+	max      time.Duration
+}
+
+// Registry holds the metrics collected since the server started
+type Registry struct {
+	started  time.Time
+	mu       sync.Mutex
+	tools    map[string]*toolStats
+	counters map[string]map[string]uint64
+}
+
+// NewRegistry returns an empty registry
+func NewRegistry() *Registry {
+	return &Registry{
+		started:  time.Now(),
+		tools:    map[string]*toolStats{},
+		counters: map[string]map[string]uint64{},
+	}
+}
+
+// Observe records a tool call with its outcome (see toolkit.Outcome) and duration
+func (r *Registry) Observe(tool, outcome string, d time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stats, ok := r.tools[tool]
+	if !ok {
+		stats = &toolStats{outcomes: map[string]uint64{}, buckets: make([]uint64, len(latencyBuckets)+1)}
+		r.tools[tool] = stats
+	}
+	stats.outcomes[outcome]++
+	stats.sum += d
+	stats.max = max(stats.max, d)
+	bucket := sort.SearchFloat64s(latencyBuckets, d.Seconds())
+	stats.buckets[bucket]++
+}
+
+// Add increments a counter
+func (r *Registry) Add(c Counter, value string) {
+	if value == "" {
+		value = "unknown"
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	values, ok := r.counters[c.Name]
+	if !ok {
+		values = map[string]uint64{}
+		r.counters[c.Name] = values
+	}
+	values[value]++
+}
+
+// Middleware returns the tool middleware recording every call in the registry
+func (r *Registry) Middleware() toolkit.Middleware {
+	return func(next toolkit.ToolHandler) toolkit.ToolHandler {
+		return func(ctx context.Context, call *toolkit.ToolCall) (*mcp.CallToolResult, any, error) {
+			res, out, err := next(ctx, call)
+			r.Observe(call.Tool, toolkit.Outcome(res, err), time.Since(call.Start))
+			return res, out, err
+		}
+	}
+}
+
+// ToolStats summarizes the calls of a tool
+type ToolStats struct {
+	Tool         string            `json:"tool"`
+	Calls        uint64            `json:"calls"`
+	Errors       uint64            `json:"errors"` // Calls failing or with an error result
+	Outcomes     map[string]uint64 `json:"outcomes"`
+	AvgLatencyMs float64           `json:"avg_latency_ms"`
+	P95LatencyMs float64           `json:"p95_latency_ms"` // Upper bound of the histogram bucket
+	MaxLatencyMs float64           `json:"max_latency_ms"`
+}
+
+// Snapshot is a copy of the collected metrics
+type Snapshot struct {
+	Started  time.Time                    `json:"started"`
+	Tools    []ToolStats                  `json:"tools"` // By number of calls, most used first
+	Counters map[string]map[string]uint64 `json:"counters"`
+}
+
+// Snapshot copies the collected metrics
+func (r *Registry) Snapshot() Snapshot {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	snap := Snapshot{Started: r.started, Tools: []ToolStats{}, Counters: map[string]map[string]uint64{}}
+	for tool, stats := range r.tools {
+		s := ToolStats{Tool: tool, Outcomes: map[string]uint64{}, MaxLatencyMs: milliseconds(stats.max)}
+		for outcome, n := range stats.outcomes {
+			s.Outcomes[outcome] = n
+			s.Calls += n
+			if outcome != toolkit.OutcomeSuccess {
+				s.Errors += n
+			}
+		}
+		if s.Calls > 0 {
+			s.AvgLatencyMs = math.Round(milliseconds(stats.sum)/float64(s.Calls)*10) / 10
+			s.P95LatencyMs = percentile(stats, s.Calls, 0.95)
+		}
+		snap.Tools = append(snap.Tools, s)
+	}
+	sort.Slice(snap.Tools, func(i, j int) bool {
+		if snap.Tools[i].Calls != snap.Tools[j].Calls {
+			return snap.Tools[i].Calls > snap.Tools[j].Calls
+		}
+		return snap.Tools[i].Tool < snap.Tools[j].Tool
+	})
+	for name, values := range r.counters {
+		snap.Counters[name] = map[string]uint64{}
+		for value, n := range values {
+			snap.Counters[name][value] = n
+		}
+	}
+	return snap
+}
+
+// percentile returns the upper bound of the bucket holding the given fraction of the calls, the
+// maximum latency when it falls in the +Inf bucket
+func percentile(stats *toolStats, calls uint64, fraction float64) float64 {
+	rank := uint64(math.Ceil(float64(calls) * fraction))
+	var seen uint64
+	for i, n := range stats.buckets {
+		seen += n
+		if seen >= rank {
+			if i < len(latencyBuckets) {
+				return latencyBuckets[i] * 1000
+			}
+			break
+		}
+	}
+	return milliseconds(stats.max)
+}
+
+func milliseconds(d time.Duration) float64 {
+	return math.Round(float64(d.Microseconds())/100) / 10
+}
+
+// WritePrometheus writes the metrics in the Prometheus text exposition format
+func (r *Registry) WritePrometheus(w io.Writer) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var b strings.Builder
+	tools := make([]string, 0, len(r.tools))
+	for tool := range r.tools {
+		tools = append(tools, tool)
+	}
+	sort.Strings(tools)
+
+	fmt.Fprintf(&b, "# HELP %suptime_seconds Seconds since the server started\n", prefix)
+	fmt.Fprintf(&b, "# TYPE %suptime_seconds gauge\n", prefix)
+	fmt.Fprintf(&b, "%suptime_seconds %d\n", prefix, int(time.Since(r.started).Seconds()))
+
+	fmt.Fprintf(&b, "# HELP %stool_calls_total Tool calls by outcome\n", prefix)
+	fmt.Fprintf(&b, "# TYPE %stool_calls_total counter\n", prefix)
+	for _, tool := range tools {
+		stats := r.tools[tool]
+		for _, outcome := range sortedKeys(stats.outcomes) {
+			fmt.Fprintf(&b, "%stool_calls_total{tool=%q,outcome=%q} %d\n", prefix, tool, outcome, stats.outcomes[outcome])
+		}
+	}
+
+	fmt.Fprintf(&b, "# HELP %stool_duration_seconds Tool call latency\n", prefix)
+	fmt.Fprintf(&b, "# TYPE %stool_duration_seconds histogram\n", prefix)
+	for _, tool := range tools {
+		stats := r.tools[tool]
+		var cumulative uint64
+		for i, n := range stats.buckets {
+			cumulative += n
+			le := "+Inf"
+			if i < len(latencyBuckets) {
+				le = fmt.Sprint(latencyBuckets[i])
+			}
+			fmt.Fprintf(&b, "%stool_duration_seconds_bucket{tool=%q,le=%q} %d\n", prefix, tool, le, cumulative)
+		}
+		fmt.Fprintf(&b, "%stool_duration_seconds_sum{tool=%q} %g\n", prefix, tool, stats.sum.Seconds())
+		fmt.Fprintf(&b, "%stool_duration_seconds_count{tool=%q} %d\n", prefix, tool, cumulative)
+	}
+
+	for _, c := range counters {
+		fmt.Fprintf(&b, "# HELP %s%s_total %s\n", prefix, c.Name, c.Help)
+		fmt.Fprintf(&b, "# TYPE %s%s_total counter\n", prefix, c.Name)
+		values := r.counters[c.Name]
+		for _, value := range sortedKeys(values) {
+			fmt.Fprintf(&b, "%s%s_total{%s=%q} %d\n", prefix, c.Name, c.Label, value, values[value])
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func sortedKeys(m map[string]uint64) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
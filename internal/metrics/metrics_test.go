@@ -0,0 +1,97 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/krakend/mcp-server/internal/toolkit"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestRegistry_Snapshot(t *testing.T) {
+	r := NewRegistry()
+	for i := 0; i < 19; i++ {
+		r.Observe("validate_config", toolkit.OutcomeSuccess, 300*time.Millisecond)
+	}
+	r.Observe("validate_config", toolkit.OutcomeToolError, 4*time.Second)
+	r.Observe("search_documentation", toolkit.OutcomeError, 20*time.Millisecond)
+	r.Add(SchemaCache, "hit")
+	r.Add(SchemaCache, "hit")
+	r.Add(ValidationMethod, "")
+
+	snap := r.Snapshot()
+	if len(snap.Tools) != 2 || snap.Tools[0].Tool != "validate_config" {
+		t.Fatalf("Expected the most used tool first, got %+v", snap.Tools)
+	}
+	validate := snap.Tools[0]
+	if validate.Calls != 20 || validate.Errors != 1 || validate.Outcomes[toolkit.OutcomeSuccess] != 19 {
+		t.Errorf("Unexpected counts: %+v", validate)
+	}
+	if validate.AvgLatencyMs != 485 || validate.P95LatencyMs != 500 || validate.MaxLatencyMs != 4000 {
+		t.Errorf("Unexpected latencies: %+v", validate)
+	}
+	if snap.Tools[1].Errors != 1 || snap.Tools[1].P95LatencyMs != 50 {
+		t.Errorf("Unexpected search stats: %+v", snap.Tools[1])
+	}
+	if snap.Counters["schema_cache"]["hit"] != 2 || snap.Counters["validation_method"]["unknown"] != 1 {
+		t.Errorf("Unexpected counters: %v", snap.Counters)
+	}
+
+	// Snapshots are copies
+	snap.Counters["schema_cache"]["hit"] = 10
+	if r.Snapshot().Counters["schema_cache"]["hit"] != 2 {
+		t.Error("Snapshot must not share maps with the registry")
+	}
+}
+
+func TestRegistry_WritePrometheus(t *testing.T) {
+	r := NewRegistry()
+	r.Observe("validate_config", toolkit.OutcomeSuccess, 300*time.Millisecond)
+	r.Observe("validate_config", toolkit.OutcomeSuccess, time.Minute)
+	r.Add(DocSearch, "no_results")
+
+	var b strings.Builder
+	if err := r.WritePrometheus(&b); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`krakend_mcp_tool_calls_total{tool="validate_config",outcome="success"} 2`,
+		`krakend_mcp_tool_duration_seconds_bucket{tool="validate_config",le="0.25"} 0`,
+		`krakend_mcp_tool_duration_seconds_bucket{tool="validate_config",le="0.5"} 1`,
+		`krakend_mcp_tool_duration_seconds_bucket{tool="validate_config",le="+Inf"} 2`,
+		`krakend_mcp_tool_duration_seconds_sum{tool="validate_config"} 60.3`,
+		`krakend_mcp_tool_duration_seconds_count{tool="validate_config"} 2`,
+		`# TYPE krakend_mcp_schema_cache_total counter`,
+		`krakend_mcp_doc_search_total{result="no_results"} 1`,
+	} {
+		if !strings.Contains(b.String(), want) {
+			t.Errorf("Expected %q in:\n%s", want, b.String())
+		}
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	defer Disable()
+	Count(DocSearch, "results") // Disabled: ignored
+	r := Enable()
+	if Default() != r {
+		t.Fatal("Expected the enabled registry")
+	}
+	Count(DocSearch, "results")
+
+	handler := r.Middleware()(func(ctx context.Context, call *toolkit.ToolCall) (*mcp.CallToolResult, any, error) {
+		return nil, nil, errors.New("boom")
+	})
+	handler(context.Background(), &toolkit.ToolCall{Tool: "probe_backends", Start: time.Now()})
+
+	snap := r.Snapshot()
+	if len(snap.Tools) != 1 || snap.Tools[0].Outcomes[toolkit.OutcomeError] != 1 {
+		t.Errorf("Unexpected tools: %+v", snap.Tools)
+	}
+	if snap.Counters["doc_search"]["results"] != 1 {
+		t.Errorf("Unexpected counters: %v", snap.Counters)
+	}
+}
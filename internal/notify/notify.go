@@ -0,0 +1,164 @@
+// Package notify dispatches server events (failed validations, audit score drops, documentation
+// refreshes) to webhooks and Slack incoming webhooks.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Event types
+const (
+	EventValidationFailed  = "validation_failed"
+	EventAuditScoreDropped = "audit_score_dropped"
+	EventDocsRefreshed     = "docs_refreshed"
+)
+
+// EventTypes lists every event a webhook can subscribe to
+var EventTypes = []string{EventValidationFailed, EventAuditScoreDropped, EventDocsRefreshed}
+
+const sendTimeout = 10 * time.Second
+
+// Event is a notification sent to the webhooks
+type Event struct {
+	Type    string            `json:"type"`
+	Title   string            `json:"title"`
+	Message string            `json:"message"`
+	Fields  map[string]string `json:"fields,omitempty"`
+	Time    time.Time         `json:"time"`
+}
+
+// Webhook is a notification target of the server configuration file
+type Webhook struct {
+	URL     string            `json:"url" yaml:"url"`
+	Format  string            `json:"format,omitempty" yaml:"format,omitempty"` // "json" (default) or "slack"
+	Events  []string          `json:"events,omitempty" yaml:"events,omitempty"` // Event types sent (defaults to all)
+	Headers map[string]string `json:"headers,omitempty" yaml:"headers,omitempty"`
+}
+
+// Dispatcher sends events to the webhooks subscribed to them, in the background
+type Dispatcher struct {
+	hooks  []Webhook
+	client *http.Client
+	wg     sync.WaitGroup
+}
+
+// New validates the webhooks and creates their dispatcher. URLs and header values can
+// reference environment variables as ${NAME}, keeping tokens out of the configuration file.
+func New(hooks []Webhook) (*Dispatcher, error) {
+	d := &Dispatcher{client: &http.Client{Timeout: sendTimeout}}
+	for i, hook := range hooks {
+		hook.URL = os.ExpandEnv(hook.URL)
+		u, err := url.Parse(hook.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("webhook %d: invalid url %q", i+1, hook.URL)
+		}
+		switch hook.Format {
+		case "":
+			hook.Format = "json"
+		case "json", "slack":
+		default:
+			return nil, fmt.Errorf("webhook %d: invalid format %q (expected json or slack)", i+1, hook.Format)
+		}
+		for _, event := range hook.Events {
+			if !slices.Contains(EventTypes, event) {
+				return nil, fmt.Errorf("webhook %d: unknown event %q", i+1, event)
+			}
+		}
+		headers := make(map[string]string, len(hook.Headers))
+		for name, value := range hook.Headers {
+			headers[name] = os.ExpandEnv(value)
+		}
+		hook.Headers = headers
+		d.hooks = append(d.hooks, hook)
+	}
+	return d, nil
+}
+
+// Dispatch sends an event to every webhook subscribed to its type without blocking the caller;
+// failures are logged
+func (d *Dispatcher) Dispatch(event Event) {
+	if event.Time.IsZero() {
+		event.Time = time.Now().UTC()
+	}
+	for _, hook := range d.hooks {
+		if len(hook.Events) > 0 && !slices.Contains(hook.Events, event.Type) {
+			continue
+		}
+		d.wg.Add(1)
+		go func(hook Webhook) {
+			defer d.wg.Done()
+			ctx, cancel := context.WithTimeout(context.Background(), sendTimeout)
+			defer cancel()
+			if err := d.send(ctx, hook, event); err != nil {
+				slog.Warn("Could not send notification", "event", event.Type, "url", redactURL(hook.URL), "error", err)
+			}
+		}(hook)
+	}
+}
+
+// Wait blocks until the notifications in flight are sent
+func (d *Dispatcher) Wait() {
+	d.wg.Wait()
+}
+
+// send posts an event to a webhook in its format
+func (d *Dispatcher) send(ctx context.Context, hook Webhook, event Event) error {
+	var payload interface{} = event
+	if hook.Format == "slack" {
+		text := fmt.Sprintf("*%s*\n%s", event.Title, event.Message)
+		for _, name := range sortedNames(event.Fields) {
+			text += fmt.Sprintf("\n• %s: %s", name, event.Fields[name])
+		}
+		payload = map[string]string{"text": text}
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range hook.Headers {
+		req.Header.Set(name, value)
+	}
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
+
+// sortedNames returns the names of the event fields in a stable order
+func sortedNames(fields map[string]string) []string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// redactURL keeps the host of a webhook URL only: Slack and most webhooks carry their token in the path
+func redactURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "webhook"
+	}
+	return u.Scheme + "://" + u.Host + "/…"
+}
@@ -0,0 +1,61 @@
+package notify
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestDispatcher(t *testing.T) {
+	var mu sync.Mutex
+	received := map[string][]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		received[r.URL.Path] = append(received[r.URL.Path], r.Header.Get("Authorization")+" "+string(body))
+		mu.Unlock()
+	}))
+	defer server.Close()
+	t.Setenv("HOOK_TOKEN", "secret")
+
+	d, err := New([]Webhook{
+		{URL: server.URL + "/json", Headers: map[string]string{"Authorization": "Bearer ${HOOK_TOKEN}"}},
+		{URL: server.URL + "/slack", Format: "slack", Events: []string{EventAuditScoreDropped}},
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	d.Dispatch(Event{Type: EventValidationFailed, Title: "Validation failed", Message: "1 error", Fields: map[string]string{"config": "krakend.json"}})
+	d.Dispatch(Event{Type: EventAuditScoreDropped, Title: "Score dropped", Message: "90 to 70", Fields: map[string]string{"score": "70"}})
+	d.Wait()
+
+	if len(received["/json"]) != 2 {
+		t.Fatalf("Expected both events on the JSON webhook, got %v", received["/json"])
+	}
+	for _, raw := range received["/json"] {
+		body, ok := strings.CutPrefix(raw, "Bearer secret ")
+		var event Event
+		if !ok || json.Unmarshal([]byte(body), &event) != nil || event.Time.IsZero() {
+			t.Errorf("Expected the expanded token and a JSON event, got %q", raw)
+		}
+	}
+	if len(received["/slack"]) != 1 || !strings.Contains(received["/slack"][0], `"text":"*Score dropped*\n90 to 70\n• score: 70"`) {
+		t.Errorf("Expected only the subscribed event as a Slack message, got %v", received["/slack"])
+	}
+}
+
+func TestNew_Invalid(t *testing.T) {
+	for _, hooks := range [][]Webhook{
+		{{URL: "ftp://example.com"}},
+		{{URL: "https://example.com", Format: "teams"}},
+		{{URL: "https://example.com", Events: []string{"config_changed"}}},
+	} {
+		if _, err := New(hooks); err == nil {
+			t.Errorf("Expected %+v to be rejected", hooks)
+		}
+	}
+}
@@ -0,0 +1,188 @@
+// Package pages splits large tool results in pages clients retrieve incrementally. The first
+// call returns the first page and keeps the whole result for the session under continuation
+// tokens, so the following pages are neither recomputed nor exposed to other sessions.
+package pages
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+var (
+	// MaxBytes caps the serialized items of a page, below the message limits of MCP clients
+	MaxBytes = 256 << 10
+	// TTL is how long a result is kept after its last page was retrieved
+	TTL = 15 * time.Minute
+	// maxResults caps the results kept across sessions, the least recently used is dropped first
+	maxResults = 64
+)
+
+// ErrUnknownToken is returned for tokens of another session or tool, or expired ones
+var ErrUnknownToken = errors.New("unknown or expired page token: call the tool again without page_token")
+
+// Info describes the page returned
+type Info struct {
+	Page      int    `json:"page"` // From 1
+	Pages     int    `json:"pages"`
+	Offset    int    `json:"offset"` // Position of the first item of the page in the whole result
+	Total     int    `json:"total"`  // Items in the whole result
+	NextToken string `json:"next_page_token,omitempty"`
+}
+
+// result is a paginated result kept for a session
+type result struct {
+	session string
+	tool    string
+	output  any
+	items   any
+	bounds  []int // Offset where each page starts
+	used    time.Time
+}
+
+var store = struct {
+	mu      sync.Mutex
+	results map[string]*result
+}{results: map[string]*result{}}
+
+// Session returns the session a request belongs to, empty without one (e.g. with the stdio transport)
+func Session(req *mcp.CallToolRequest) string {
+	if req == nil || req.Session == nil {
+		return ""
+	}
+	return req.Session.ID()
+}
+
+// Paginate returns the first page of items, of at most size items (0: no limit) and MaxBytes once
+// serialized. When there are more pages, output and items are kept for the session and tool, and
+// Info.NextToken continues with the next page. Info is nil when everything fits in one page.
+func Paginate[O, T any](session, tool string, output O, items []T, size int) ([]T, *Info) {
+	bounds := split(items, size)
+	if len(bounds) <= 1 {
+		return items, nil
+	}
+
+	id := newID()
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	now := time.Now()
+	expire(now)
+	for len(store.results) >= maxResults {
+		dropOldest()
+	}
+	store.results[id] = &result{session: session, tool: tool, output: output, items: items, bounds: bounds, used: now}
+	return page(id, items, bounds, 0)
+}
+
+// Continue returns the output kept for a token with the page of items it points to
+func Continue[O, T any](session, tool, token string) (O, []T, *Info, error) {
+	var zero O
+	id, n, ok := parseToken(token)
+	if !ok {
+		return zero, nil, nil, ErrUnknownToken
+	}
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	now := time.Now()
+	expire(now)
+	r, found := store.results[id]
+	if !found || r.session != session || r.tool != tool || n >= len(r.bounds) {
+		return zero, nil, nil, ErrUnknownToken
+	}
+	output, okOutput := r.output.(O)
+	items, okItems := r.items.([]T)
+	if !okOutput || !okItems {
+		return zero, nil, nil, fmt.Errorf("page token of another result type")
+	}
+	r.used = now
+	pageItems, info := page(id, items, r.bounds, n)
+	if info.NextToken == "" {
+		// Last page: nothing left to continue
+		delete(store.results, id)
+	}
+	return output, pageItems, info, nil
+}
+
+// Live returns the number of results kept
+func Live() int {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	return len(store.results)
+}
+
+// split returns the offsets where the pages of items start
+func split[T any](items []T, size int) []int {
+	bounds := []int{0}
+	count, bytes := 0, 0
+	for i, item := range items {
+		itemBytes := MaxBytes
+		if data, err := json.Marshal(item); err == nil {
+			itemBytes = len(data) + 1 // Separator
+		}
+		// A page holds at least one item, however large
+		if count > 0 && ((size > 0 && count >= size) || bytes+itemBytes > MaxBytes) {
+			bounds = append(bounds, i)
+			count, bytes = 0, 0
+		}
+		count++
+		bytes += itemBytes
+	}
+	return bounds
+}
+
+// page returns the items of the n-th page of a result
+func page[T any](id string, items []T, bounds []int, n int) ([]T, *Info) {
+	end := len(items)
+	if n+1 < len(bounds) {
+		end = bounds[n+1]
+	}
+	info := &Info{Page: n + 1, Pages: len(bounds), Offset: bounds[n], Total: len(items)}
+	if n+1 < len(bounds) {
+		info.NextToken = id + "." + strconv.Itoa(n+1)
+	}
+	return items[bounds[n]:end], info
+}
+
+// expire drops the results not used for TTL. The store must be locked.
+func expire(now time.Time) {
+	for id, r := range store.results {
+		if now.Sub(r.used) > TTL {
+			delete(store.results, id)
+		}
+	}
+}
+
+// dropOldest drops the least recently used result. The store must be locked.
+func dropOldest() {
+	var oldest string
+	for id, r := range store.results {
+		if oldest == "" || r.used.Before(store.results[oldest].used) {
+			oldest = id
+		}
+	}
+	delete(store.results, oldest)
+}
+
+func newID() string {
+	b := make([]byte, 12)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func parseToken(token string) (id string, n int, ok bool) {
+	id, index, found := strings.Cut(token, ".")
+	if !found || id == "" {
+		return "", 0, false
+	}
+	n, err := strconv.Atoi(index)
+	return id, n, err == nil && n > 0
+}
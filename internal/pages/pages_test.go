@@ -0,0 +1,86 @@
+package pages
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testOutput struct {
+	Query string
+	Items []string
+}
+
+func TestPaginate(t *testing.T) {
+	items := []string{"a", "b", "c", "d", "e"}
+	if page, info := Paginate("s1", "search", testOutput{}, items, 0); info != nil || len(page) != 5 {
+		t.Fatalf("Expected a single page, got %v, %+v", page, info)
+	}
+
+	page, info := Paginate("s1", "search", testOutput{Query: "q", Items: items}, items, 2)
+	if info == nil || info.Pages != 3 || info.Total != 5 || strings.Join(page, "") != "ab" || info.NextToken == "" {
+		t.Fatalf("Unexpected first page %v, %+v", page, info)
+	}
+
+	if _, _, _, err := Continue[testOutput, string]("s2", "search", info.NextToken); !errors.Is(err, ErrUnknownToken) {
+		t.Errorf("Expected tokens to be scoped to the session, got %v", err)
+	}
+	if _, _, _, err := Continue[testOutput, string]("s1", "fleet", info.NextToken); !errors.Is(err, ErrUnknownToken) {
+		t.Errorf("Expected tokens to be scoped to the tool, got %v", err)
+	}
+
+	var got []string
+	token := info.NextToken
+	for token != "" {
+		output, page, info, err := Continue[testOutput, string]("s1", "search", token)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if output.Query != "q" || info.Offset != len(got)+2 {
+			t.Errorf("Unexpected page %+v of %+v", info, output)
+		}
+		got = append(got, page...)
+		token = info.NextToken
+	}
+	if strings.Join(got, "") != "cde" {
+		t.Errorf("Expected the remaining items, got %v", got)
+	}
+	if Live() != 0 {
+		t.Errorf("Expected the result to be dropped after its last page, got %d", Live())
+	}
+
+	for _, token := range []string{"", "nope", "abc.0", "abc.x"} {
+		if _, _, _, err := Continue[testOutput, string]("s1", "search", token); !errors.Is(err, ErrUnknownToken) {
+			t.Errorf("Continue(%q) expected ErrUnknownToken, got %v", token, err)
+		}
+	}
+}
+
+func TestPaginate_MaxBytes(t *testing.T) {
+	orig := MaxBytes
+	MaxBytes = 12
+	t.Cleanup(func() { MaxBytes = orig })
+
+	items := []string{"aaa", "bbb", strings.Repeat("c", 50), "d"}
+	page, info := Paginate("", "search", testOutput{}, items, 0)
+	if info == nil || info.Pages != 3 || len(page) != 2 {
+		t.Fatalf("Expected pages of at most 12 bytes, got %v, %+v", page, info)
+	}
+	_, page, _, err := Continue[testOutput, string]("", "search", info.NextToken)
+	if err != nil || len(page) != 1 || page[0] != items[2] {
+		t.Errorf("Expected an oversized item alone in its page, got %v, %v", page, err)
+	}
+}
+
+func TestContinue_Expired(t *testing.T) {
+	orig := TTL
+	TTL = time.Millisecond
+	t.Cleanup(func() { TTL = orig })
+
+	_, info := Paginate("", "search", testOutput{}, []string{"a", "b"}, 1)
+	time.Sleep(5 * time.Millisecond)
+	if _, _, _, err := Continue[testOutput, string]("", "search", info.NextToken); !errors.Is(err, ErrUnknownToken) {
+		t.Errorf("Expected an expired token, got %v", err)
+	}
+}
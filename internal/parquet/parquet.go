@@ -0,0 +1,205 @@
+// Package parquet writes tables of strings as Apache Parquet files readable by DuckDB, pandas,
+// Spark or any Parquet reader. Files have one row group with one uncompressed, PLAIN encoded data
+// page per column, and every column is a required UTF-8 string: enough for exported datasets
+// without pulling a Parquet library in.
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// magic starts and ends every Parquet file
+const magic = "PAR1"
+
+// Parquet enums, from parquet.thrift
+const (
+	typeByteArray      = 6
+	repetitionRequired = 0
+	convertedUTF8      = 0
+	encodingPlain      = 0
+	encodingRLE        = 3
+	codecUncompressed  = 0
+	pageTypeData       = 0
+)
+
+// Thrift compact protocol types
+const (
+	compactI32    = 5
+	compactI64    = 6
+	compactBinary = 8
+	compactList   = 9
+	compactStruct = 12
+)
+
+// Write writes rows of strings as a Parquet file with the given columns. Every row must have a
+// value per column.
+func Write(w io.Writer, columns []string, rows [][]string) error {
+	if len(columns) == 0 {
+		return fmt.Errorf("parquet files need at least one column")
+	}
+	for i, row := range rows {
+		if len(row) != len(columns) {
+			return fmt.Errorf("row %d has %d values, expected %d", i, len(row), len(columns))
+		}
+	}
+
+	file := bytes.NewBufferString(magic)
+	chunks := make([]columnChunk, len(columns))
+	for c := range columns {
+		var values bytes.Buffer
+		for _, row := range rows {
+			binary.Write(&values, binary.LittleEndian, uint32(len(row[c])))
+			values.WriteString(row[c])
+		}
+		var header compactWriter
+		header.pageHeader(len(rows), values.Len())
+		chunks[c] = columnChunk{offset: int64(file.Len()), size: int64(header.buf.Len() + values.Len())}
+		file.Write(header.buf.Bytes())
+		file.Write(values.Bytes())
+	}
+
+	var footer compactWriter
+	footer.fileMetaData(columns, int64(len(rows)), chunks)
+	file.Write(footer.buf.Bytes())
+	binary.Write(file, binary.LittleEndian, uint32(footer.buf.Len()))
+	file.WriteString(magic)
+	_, err := w.Write(file.Bytes())
+	return err
+}
+
+// columnChunk locates the data page of a column in the file
+type columnChunk struct {
+	offset int64
+	size   int64 // Page header included
+}
+
+// compactWriter encodes Thrift structures with the compact protocol
+type compactWriter struct {
+	buf     bytes.Buffer
+	lastIDs []int16 // Last field ID of every open struct
+}
+
+func (w *compactWriter) varint(v uint64) {
+	w.buf.Write(binary.AppendUvarint(nil, v))
+}
+
+func (w *compactWriter) zigzag(v int64) {
+	w.varint(uint64((v << 1) ^ (v >> 63)))
+}
+
+func (w *compactWriter) beginStruct() {
+	w.lastIDs = append(w.lastIDs, 0)
+}
+
+func (w *compactWriter) endStruct() {
+	w.buf.WriteByte(0)
+	w.lastIDs = w.lastIDs[:len(w.lastIDs)-1]
+}
+
+func (w *compactWriter) field(id int16, kind byte) {
+	last := &w.lastIDs[len(w.lastIDs)-1]
+	if delta := id - *last; delta > 0 && delta <= 15 {
+		w.buf.WriteByte(byte(delta)<<4 | kind)
+	} else {
+		w.buf.WriteByte(kind)
+		w.zigzag(int64(id))
+	}
+	*last = id
+}
+
+func (w *compactWriter) i32(id int16, v int32) {
+	w.field(id, compactI32)
+	w.zigzag(int64(v))
+}
+
+func (w *compactWriter) i64(id int16, v int64) {
+	w.field(id, compactI64)
+	w.zigzag(v)
+}
+
+func (w *compactWriter) str(id int16, v string) {
+	w.field(id, compactBinary)
+	w.varint(uint64(len(v)))
+	w.buf.WriteString(v)
+}
+
+func (w *compactWriter) list(id int16, kind byte, size int) {
+	w.field(id, compactList)
+	if size < 15 {
+		w.buf.WriteByte(byte(size)<<4 | kind)
+		return
+	}
+	w.buf.WriteByte(0xf0 | kind)
+	w.varint(uint64(size))
+}
+
+// pageHeader writes the PageHeader of a data page of PLAIN values
+func (w *compactWriter) pageHeader(values, size int) {
+	w.beginStruct()
+	w.i32(1, pageTypeData)
+	w.i32(2, int32(size)) // uncompressed_page_size
+	w.i32(3, int32(size)) // compressed_page_size
+	w.field(5, compactStruct)
+	w.beginStruct() // DataPageHeader
+	w.i32(1, int32(values))
+	w.i32(2, encodingPlain)
+	w.i32(3, encodingRLE) // Definition levels, none for required columns
+	w.i32(4, encodingRLE) // Repetition levels, none for flat schemas
+	w.endStruct()
+	w.endStruct()
+}
+
+// fileMetaData writes the footer: the flat schema and the single row group
+func (w *compactWriter) fileMetaData(columns []string, rows int64, chunks []columnChunk) {
+	w.beginStruct()
+	w.i32(1, 1) // version
+	w.list(2, compactStruct, len(columns)+1)
+	w.beginStruct() // Root
+	w.str(4, "schema")
+	w.i32(5, int32(len(columns)))
+	w.endStruct()
+	for _, column := range columns {
+		w.beginStruct()
+		w.i32(1, typeByteArray)
+		w.i32(3, repetitionRequired)
+		w.str(4, column)
+		w.i32(6, convertedUTF8)
+		w.endStruct()
+	}
+	w.i64(3, rows)
+
+	var total int64
+	for _, chunk := range chunks {
+		total += chunk.size
+	}
+	w.list(4, compactStruct, 1)
+	w.beginStruct() // RowGroup
+	w.list(1, compactStruct, len(columns))
+	for i, column := range columns {
+		w.beginStruct() // ColumnChunk
+		w.i64(2, chunks[i].offset)
+		w.field(3, compactStruct)
+		w.beginStruct() // ColumnMetaData
+		w.i32(1, typeByteArray)
+		w.list(2, compactI32, 1)
+		w.zigzag(encodingPlain)
+		w.list(3, compactBinary, 1)
+		w.varint(uint64(len(column)))
+		w.buf.WriteString(column)
+		w.i32(4, codecUncompressed)
+		w.i64(5, rows)
+		w.i64(6, chunks[i].size)
+		w.i64(7, chunks[i].size)
+		w.i64(9, chunks[i].offset)
+		w.endStruct()
+		w.endStruct()
+	}
+	w.i64(2, total)
+	w.i64(3, rows)
+	w.endStruct()
+	w.str(6, "krakend-mcp-server")
+	w.endStruct()
+}
@@ -0,0 +1,163 @@
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"testing"
+)
+
+// compactReader decodes the Thrift compact structures written by compactWriter, as maps of field
+// IDs to int64, string, []interface{} or nested maps
+type compactReader struct {
+	data []byte
+	pos  int
+}
+
+func (r *compactReader) varint() uint64 {
+	v, n := binary.Uvarint(r.data[r.pos:])
+	r.pos += n
+	return v
+}
+
+func (r *compactReader) zigzag() int64 {
+	v := r.varint()
+	return int64(v>>1) ^ -int64(v&1)
+}
+
+func (r *compactReader) value(kind byte) interface{} {
+	switch kind {
+	case compactI32, compactI64:
+		return r.zigzag()
+	case compactBinary:
+		size := int(r.varint())
+		r.pos += size
+		return string(r.data[r.pos-size : r.pos])
+	case compactList:
+		header := r.data[r.pos]
+		r.pos++
+		size := int(header >> 4)
+		if size == 15 {
+			size = int(r.varint())
+		}
+		list := make([]interface{}, size)
+		for i := range list {
+			list[i] = r.value(header & 0x0f)
+		}
+		return list
+	case compactStruct:
+		return r.structure()
+	}
+	panic("unexpected compact type")
+}
+
+func (r *compactReader) structure() map[int16]interface{} {
+	fields := map[int16]interface{}{}
+	var last int16
+	for {
+		header := r.data[r.pos]
+		r.pos++
+		if header == 0 {
+			return fields
+		}
+		id := last + int16(header>>4)
+		if header>>4 == 0 {
+			id = int16(r.zigzag())
+		}
+		fields[id] = r.value(header & 0x0f)
+		last = id
+	}
+}
+
+// readStrings reads a file written by Write back into its columns and rows
+func readStrings(t *testing.T, data []byte) ([]string, [][]string) {
+	t.Helper()
+	if !bytes.HasPrefix(data, []byte(magic)) || !bytes.HasSuffix(data, []byte(magic)) {
+		t.Fatalf("Missing PAR1 magic")
+	}
+	size := int(binary.LittleEndian.Uint32(data[len(data)-8:]))
+	footer := (&compactReader{data: data[len(data)-8-size : len(data)-8]}).structure()
+
+	schema := footer[2].([]interface{})
+	var columns []string
+	for _, element := range schema[1:] {
+		column := element.(map[int16]interface{})
+		if column[1] != int64(typeByteArray) || column[3] != int64(repetitionRequired) || column[6] != int64(convertedUTF8) {
+			t.Errorf("Column %v is not a required UTF-8 string", column)
+		}
+		columns = append(columns, column[4].(string))
+	}
+	numRows := int(footer[3].(int64))
+	rows := make([][]string, numRows)
+	group := footer[4].([]interface{})[0].(map[int16]interface{})
+	if group[3] != int64(numRows) {
+		t.Errorf("Row group rows = %v, want %d", group[3], numRows)
+	}
+	for _, c := range group[1].([]interface{}) {
+		meta := c.(map[int16]interface{})[3].(map[int16]interface{})
+		reader := &compactReader{data: data, pos: int(meta[9].(int64))}
+		header := reader.structure()
+		page := header[5].(map[int16]interface{})
+		if page[1] != int64(numRows) || page[2] != int64(encodingPlain) {
+			t.Errorf("Unexpected data page header %v", page)
+		}
+		end := reader.pos + int(header[3].(int64))
+		if int64(end)-meta[9].(int64) != meta[7].(int64) {
+			t.Errorf("Column chunk size %v does not match its page", meta[7])
+		}
+		for i := range rows {
+			length := int(binary.LittleEndian.Uint32(data[reader.pos:]))
+			reader.pos += 4
+			rows[i] = append(rows[i], string(data[reader.pos:reader.pos+length]))
+			reader.pos += length
+		}
+		if reader.pos != end {
+			t.Errorf("Page values end at %d, want %d", reader.pos, end)
+		}
+	}
+	return columns, rows
+}
+
+func TestWrite(t *testing.T) {
+	columns := []string{"config", "severity", "title"}
+	rows := [][]string{
+		{"krakend.json", "high", "No rate limit"},
+		{"gateway/krakend.json", "", "CORS allows every origin: *, ñ"},
+	}
+	for i := 0; i < 20; i++ {
+		rows = append(rows, []string{"bulk.json", "low", strings.Repeat("x", i*10)})
+	}
+	var out bytes.Buffer
+	if err := Write(&out, columns, rows); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	gotColumns, gotRows := readStrings(t, out.Bytes())
+	if strings.Join(gotColumns, ",") != "config,severity,title" {
+		t.Errorf("Columns = %v", gotColumns)
+	}
+	if len(gotRows) != len(rows) {
+		t.Fatalf("Read %d rows, want %d", len(gotRows), len(rows))
+	}
+	for i := range rows {
+		if strings.Join(gotRows[i], "|") != strings.Join(rows[i], "|") {
+			t.Errorf("Row %d = %v, want %v", i, gotRows[i], rows[i])
+		}
+	}
+
+	out.Reset()
+	if err := Write(&out, columns, nil); err != nil {
+		t.Fatalf("Write() without rows error = %v", err)
+	}
+	if _, gotRows := readStrings(t, out.Bytes()); len(gotRows) != 0 {
+		t.Errorf("Expected no rows, got %v", gotRows)
+	}
+}
+
+func TestWrite_Errors(t *testing.T) {
+	if err := Write(&bytes.Buffer{}, nil, nil); err == nil {
+		t.Error("Expected an error without columns")
+	}
+	if err := Write(&bytes.Buffer{}, []string{"a", "b"}, [][]string{{"1"}}); err == nil {
+		t.Error("Expected an error for a short row")
+	}
+}
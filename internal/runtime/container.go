@@ -0,0 +1,105 @@
+package runtime
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// DockerModeEnv selects how Docker runs validation containers: "auto" (default), "local", "stdin" or "off"
+const DockerModeEnv = "KRAKEND_MCP_DOCKER_MODE"
+
+// Docker execution modes
+const (
+	// DockerModeLocal bind-mounts the configuration into the container (Docker runs on this machine)
+	DockerModeLocal = "local"
+	// DockerModeStdin streams the configuration through stdin, so it works with remote daemons
+	// (DOCKER_HOST) and sibling containers that cannot see this filesystem
+	DockerModeStdin = "stdin"
+	// DockerModeOff disables the Docker tier
+	DockerModeOff = "off"
+)
+
+// Container engines running the Docker tier
+const (
+	EngineDocker = "docker"
+	// EnginePodman is Podman, through its own CLI or the docker-compatible one of podman-docker
+	EnginePodman = "podman"
+)
+
+// containerCLIs are the container CLIs looked for, in order: podman is the fallback on machines
+// without Docker, like RHEL and Fedora
+var containerCLIs = []string{"docker", "podman"}
+
+var (
+	// containerMarkers are files created by container runtimes inside their containers
+	containerMarkers = []string{"/.dockerenv", "/run/.containerenv"}
+	cgroupFile       = "/proc/1/cgroup"
+	dockerSocket     = "/var/run/docker.sock"
+)
+
+// InContainer reports whether the server itself runs inside a container (Docker, Podman, Kubernetes...)
+func InContainer() bool {
+	if os.Getenv("KUBERNETES_SERVICE_HOST") != "" || os.Getenv("container") != "" {
+		return true
+	}
+	for _, marker := range containerMarkers {
+		if _, err := os.Stat(marker); err == nil {
+			return true
+		}
+	}
+	if data, err := os.ReadFile(cgroupFile); err == nil {
+		cgroup := string(data)
+		for _, hint := range []string{"docker", "kubepods", "containerd", "libpod", "lxc"} {
+			if strings.Contains(cgroup, hint) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ResolveDockerMode returns the Docker execution mode and why it was chosen.
+// Inside a container, bind mounts refer to paths the daemon cannot see, so the configuration is
+// streamed through stdin when a daemon is reachable (DOCKER_HOST or a mounted socket) and
+// Docker is skipped otherwise, falling back to the in-process validation tiers.
+func ResolveDockerMode() (mode, reason string) {
+	switch m := strings.ToLower(os.Getenv(DockerModeEnv)); m {
+	case DockerModeLocal, DockerModeStdin, DockerModeOff:
+		return m, "set by " + DockerModeEnv
+	}
+
+	if os.Getenv("DOCKER_HOST") != "" {
+		return DockerModeStdin, "remote Docker host (DOCKER_HOST)"
+	}
+	if os.Getenv("CONTAINER_HOST") != "" {
+		return DockerModeStdin, "remote Podman host (CONTAINER_HOST)"
+	}
+	if !InContainer() {
+		return DockerModeLocal, "local Docker daemon"
+	}
+	if _, err := os.Stat(dockerSocket); err == nil {
+		return DockerModeStdin, "running in a container with the Docker socket mounted"
+	}
+	return DockerModeOff, "running in a container without access to a Docker daemon (set DOCKER_HOST to use a remote one)"
+}
+
+// DetectContainerCLI returns the first container CLI answering --version, its engine and
+// version. The docker command of podman-docker reports the Podman engine.
+func DetectContainerCLI() (binary, engine, version string, ok bool) {
+	for _, cli := range containerCLIs {
+		output, err := exec.Command(cli, "--version").CombinedOutput()
+		if err != nil {
+			continue
+		}
+		// podman-docker prints a notice before the version
+		lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+		version = strings.TrimSpace(lines[len(lines)-1])
+		engine = EngineDocker
+		if cli == EnginePodman || strings.Contains(strings.ToLower(version), EnginePodman) {
+			engine = EnginePodman
+		}
+		return cli, engine, version, true
+	}
+	return "", "", "", false
+}
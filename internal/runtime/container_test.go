@@ -0,0 +1,115 @@
+package runtime
+
+import (
+	"os"
+	"path/filepath"
+	goruntime "runtime"
+	"testing"
+)
+
+// fakeHost points container detection to files under a temporary directory
+func fakeHost(t *testing.T, container, socket bool) {
+	t.Helper()
+	dir := t.TempDir()
+	savedMarkers, savedCgroup, savedSocket := containerMarkers, cgroupFile, dockerSocket
+	t.Cleanup(func() { containerMarkers, cgroupFile, dockerSocket = savedMarkers, savedCgroup, savedSocket })
+
+	containerMarkers = []string{filepath.Join(dir, ".dockerenv")}
+	cgroupFile = filepath.Join(dir, "cgroup")
+	dockerSocket = filepath.Join(dir, "docker.sock")
+	os.WriteFile(cgroupFile, []byte("0::/init.scope\n"), 0o600)
+	if container {
+		os.WriteFile(containerMarkers[0], nil, 0o600)
+	}
+	if socket {
+		os.WriteFile(dockerSocket, nil, 0o600)
+	}
+
+	t.Setenv("KUBERNETES_SERVICE_HOST", "")
+	t.Setenv("container", "")
+	t.Setenv("DOCKER_HOST", "")
+	t.Setenv(DockerModeEnv, "")
+}
+
+func TestInContainer_Cgroup(t *testing.T) {
+	fakeHost(t, false, false)
+	if InContainer() {
+		t.Fatal("expected no container on a plain host")
+	}
+	os.WriteFile(cgroupFile, []byte("12:pids:/kubepods/besteffort/pod1234\n"), 0o600)
+	if !InContainer() {
+		t.Error("expected container detection from cgroup")
+	}
+}
+
+func TestResolveDockerMode(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		container  bool
+		socket     bool
+		dockerHost string
+		override   string
+		want       string
+	}{
+		{name: "host", want: DockerModeLocal},
+		{name: "container without daemon", container: true, want: DockerModeOff},
+		{name: "container with socket", container: true, socket: true, want: DockerModeStdin},
+		{name: "remote docker host", container: true, dockerHost: "tcp://docker:2375", want: DockerModeStdin},
+		{name: "explicit override", container: true, socket: true, override: "off", want: DockerModeOff},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			fakeHost(t, tc.container, tc.socket)
+			t.Setenv("DOCKER_HOST", tc.dockerHost)
+			t.Setenv(DockerModeEnv, tc.override)
+
+			mode, reason := ResolveDockerMode()
+			if mode != tc.want {
+				t.Errorf("mode = %q (%s), want %q", mode, reason, tc.want)
+			}
+			if reason == "" {
+				t.Error("expected a reason")
+			}
+		})
+	}
+}
+
+// fakeCLIs puts shell scripts printing their version on an otherwise empty PATH
+func fakeCLIs(t *testing.T, versions map[string]string) {
+	t.Helper()
+	dir := t.TempDir()
+	for name, output := range versions {
+		script := "#!/bin/sh\nprintf '" + output + "\\n'\n"
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(script), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("PATH", dir)
+}
+
+func TestDetectContainerCLI(t *testing.T) {
+	if goruntime.GOOS == "windows" {
+		t.Skip("shell script fakes")
+	}
+	for _, tc := range []struct {
+		name                    string
+		clis                    map[string]string
+		binary, engine, version string
+	}{
+		{"docker", map[string]string{"docker": "Docker version 27.1.1", "podman": "podman version 5.2.0"}, "docker", EngineDocker, "Docker version 27.1.1"},
+		{"podman fallback", map[string]string{"podman": "podman version 5.2.0"}, "podman", EnginePodman, "podman version 5.2.0"},
+		{"podman-docker", map[string]string{"docker": "Emulate Docker CLI using podman.\\npodman version 5.2.0"}, "docker", EnginePodman, "podman version 5.2.0"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			fakeCLIs(t, tc.clis)
+			binary, engine, version, ok := DetectContainerCLI()
+			if !ok || binary != tc.binary || engine != tc.engine || version != tc.version {
+				t.Errorf("DetectContainerCLI() = %q, %q, %q, %t", binary, engine, version, ok)
+			}
+		})
+	}
+
+	fakeCLIs(t, nil)
+	if _, _, _, ok := DetectContainerCLI(); ok {
+		t.Error("expected no container CLI")
+	}
+}
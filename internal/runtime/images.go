@@ -0,0 +1,147 @@
+package runtime
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// Environment variables that point KrakenD images to mirrored or private registries
+const (
+	imageRegistryEnv = "KRAKEND_MCP_DOCKER_REGISTRY" // Registry prefix of the official images, e.g. "registry.example.com/mirror"
+	imageCEEnv       = "KRAKEND_MCP_DOCKER_IMAGE"    // Full CE repository replacing "krakend"
+	imageEEEnv       = "KRAKEND_MCP_DOCKER_IMAGE_EE" // Full EE repository replacing "krakend/krakend-ee"
+	imageDigestsEnv  = "KRAKEND_MCP_DOCKER_DIGESTS"  // Pinned digests: "ce:2.12=sha256:...,ee:latest=sha256:..."
+)
+
+const (
+	defaultCEImage = "krakend"
+	defaultEEImage = "krakend/krakend-ee"
+)
+
+var digestPattern = regexp.MustCompile(`^sha256:[a-f0-9]{64}$`)
+
+// configuredRegistry is the registry prefix set with ConfigureRegistry
+var configuredRegistry string
+
+// ConfigureRegistry sets the registry prefix of the official images, e.g. from the server
+// settings. It takes precedence over KRAKEND_MCP_DOCKER_REGISTRY, "" falls back to it.
+func ConfigureRegistry(registry string) {
+	configuredRegistry = registry
+}
+
+// defaultRegistry returns the configured registry prefix, or the one set in the environment
+func defaultRegistry() string {
+	if configuredRegistry != "" {
+		return configuredRegistry
+	}
+	return os.Getenv(imageRegistryEnv)
+}
+
+// DockerImage returns the KrakenD image for a version ("latest" for the newest one) and edition,
+// honoring the registry, repository and digest overrides set in the environment
+func DockerImage(version string, enterprise bool) string {
+	return ImageOverride{}.Resolve(version, enterprise)
+}
+
+// ImageOverride replaces the KrakenD images for a single tool call, e.g. to validate against an
+// internal mirror
+type ImageOverride struct {
+	Image    string // Complete image reference used as is, e.g. registry.corp/krakend-ee:2.10
+	Registry string // Registry prefix of the official images, the version and edition are still resolved
+}
+
+// imageReferencePattern rejects references that docker would take as flags or that are not images
+var imageReferencePattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._/:@+-]*$`)
+
+// Validate checks the override can be handed to docker
+func (o ImageOverride) Validate() error {
+	if o.Image != "" && o.Registry != "" {
+		return fmt.Errorf("docker_image and registry are exclusive: docker_image is a complete image reference")
+	}
+	if o.Image != "" && !imageReferencePattern.MatchString(o.Image) {
+		return fmt.Errorf("invalid docker_image %q", o.Image)
+	}
+	if registry := strings.TrimSuffix(o.Registry, "/"); o.Registry != "" && (!imageReferencePattern.MatchString(registry) || strings.ContainsAny(registry, "@+")) {
+		return fmt.Errorf("invalid registry %q", o.Registry)
+	}
+	return nil
+}
+
+// Fixed reports whether the image does not depend on the version
+func (o ImageOverride) Fixed() bool {
+	return o.Image != ""
+}
+
+// Resolve returns the KrakenD image for a version and edition: the override image, or the official
+// one from the override registry, falling back to the configured registry and the overrides set in
+// the environment
+func (o ImageOverride) Resolve(version string, enterprise bool) string {
+	if o.Image != "" {
+		return o.Image
+	}
+	if version == "" {
+		version = "latest"
+	}
+
+	repository := defaultCEImage
+	override := os.Getenv(imageCEEnv)
+	if enterprise {
+		repository = defaultEEImage
+		override = os.Getenv(imageEEEnv)
+	}
+	if registry := strings.TrimSuffix(o.Registry, "/"); registry != "" {
+		// Mirrors of the official images for this call, whatever the environment says
+		repository = registry + "/" + repository
+	} else if override != "" {
+		// Overrides are complete repository names, the registry prefix does not apply
+		repository = override
+	} else if registry := strings.TrimSuffix(defaultRegistry(), "/"); registry != "" {
+		repository = registry + "/" + repository
+	}
+
+	image := fmt.Sprintf("%s:%s", repository, version)
+	if digest := pinnedDigest(version, enterprise); digest != "" {
+		image += "@" + digest
+	}
+	return image
+}
+
+// EngineImage returns image as the container engine resolves it. Podman cannot prompt for the
+// registry of short names when running validations, so they are qualified with docker.io.
+func EngineImage(engine, image string) string {
+	if engine != EnginePodman {
+		return image
+	}
+	first, rest, found := strings.Cut(image, "/")
+	if found && (strings.ContainsAny(first, ".:") || first == "localhost") {
+		return image // Already has a registry
+	}
+	if !found {
+		return "docker.io/library/" + image
+	}
+	return "docker.io/" + first + "/" + rest
+}
+
+// pinnedDigest returns the digest pinned for an edition and version in KRAKEND_MCP_DOCKER_DIGESTS
+func pinnedDigest(version string, enterprise bool) string {
+	key := "ce:" + version
+	if enterprise {
+		key = "ee:" + version
+	}
+	for _, entry := range strings.Split(os.Getenv(imageDigestsEnv), ",") {
+		name, digest, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok || strings.TrimSpace(name) != key {
+			continue
+		}
+		digest = strings.TrimSpace(digest)
+		if !digestPattern.MatchString(digest) {
+			slog.Warn("Ignoring invalid image digest", "digest", digest, "image", key, "env", imageDigestsEnv)
+			return ""
+		}
+		return digest
+	}
+	return ""
+}
@@ -0,0 +1,121 @@
+package runtime_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/krakend/mcp-server/internal/runtime"
+)
+
+func TestDockerImage_Defaults(t *testing.T) {
+	for _, tc := range []struct {
+		version    string
+		enterprise bool
+		want       string
+	}{
+		{"", false, "krakend:latest"},
+		{"2.12", false, "krakend:2.12"},
+		{"2.12", true, "krakend/krakend-ee:2.12"},
+	} {
+		if got := runtime.DockerImage(tc.version, tc.enterprise); got != tc.want {
+			t.Errorf("DockerImage(%q, %t) = %q, want %q", tc.version, tc.enterprise, got, tc.want)
+		}
+	}
+}
+
+func TestDockerImage_RegistryPrefix(t *testing.T) {
+	t.Setenv("KRAKEND_MCP_DOCKER_REGISTRY", "registry.example.com/mirror/")
+
+	if got := runtime.DockerImage("2.12", false); got != "registry.example.com/mirror/krakend:2.12" {
+		t.Errorf("unexpected CE image %q", got)
+	}
+	if got := runtime.DockerImage("2.12", true); got != "registry.example.com/mirror/krakend/krakend-ee:2.12" {
+		t.Errorf("unexpected EE image %q", got)
+	}
+}
+
+func TestDockerImage_ConfiguredRegistry(t *testing.T) {
+	t.Setenv("KRAKEND_MCP_DOCKER_REGISTRY", "registry.example.com/mirror")
+	runtime.ConfigureRegistry("settings.example.com/krakend/")
+	t.Cleanup(func() { runtime.ConfigureRegistry("") })
+
+	if got := runtime.DockerImage("2.12", false); got != "settings.example.com/krakend/krakend:2.12" {
+		t.Errorf("The configured registry must win over the environment, got %q", got)
+	}
+	runtime.ConfigureRegistry("")
+	if got := runtime.DockerImage("2.12", false); got != "registry.example.com/mirror/krakend:2.12" {
+		t.Errorf("Expected the environment registry without a configured one, got %q", got)
+	}
+}
+
+func TestDockerImage_RepositoryOverride(t *testing.T) {
+	t.Setenv("KRAKEND_MCP_DOCKER_REGISTRY", "registry.example.com/mirror")
+	t.Setenv("KRAKEND_MCP_DOCKER_IMAGE_EE", "internal.example.com/gateway/krakend-ee")
+
+	if got := runtime.DockerImage("latest", true); got != "internal.example.com/gateway/krakend-ee:latest" {
+		t.Errorf("override must replace the whole repository, got %q", got)
+	}
+	if got := runtime.DockerImage("latest", false); got != "registry.example.com/mirror/krakend:latest" {
+		t.Errorf("CE image must keep the registry prefix, got %q", got)
+	}
+}
+
+func TestDockerImage_PinnedDigest(t *testing.T) {
+	digest := "sha256:" + strings.Repeat("ab", 32)
+	t.Setenv("KRAKEND_MCP_DOCKER_DIGESTS", "ce:2.12="+digest+", ee:2.12=sha256:invalid")
+
+	if got := runtime.DockerImage("2.12", false); got != "krakend:2.12@"+digest {
+		t.Errorf("expected pinned digest, got %q", got)
+	}
+	if got := runtime.DockerImage("2.11", false); got != "krakend:2.11" {
+		t.Errorf("digest must only apply to its version, got %q", got)
+	}
+	if got := runtime.DockerImage("2.12", true); got != "krakend/krakend-ee:2.12" {
+		t.Errorf("invalid digests must be ignored, got %q", got)
+	}
+}
+
+func TestEngineImage(t *testing.T) {
+	for _, tc := range []struct {
+		engine, image, want string
+	}{
+		{runtime.EngineDocker, "krakend:2.12", "krakend:2.12"},
+		{runtime.EnginePodman, "krakend:2.12", "docker.io/library/krakend:2.12"},
+		{runtime.EnginePodman, "krakend/krakend-ee:2.12", "docker.io/krakend/krakend-ee:2.12"},
+		{runtime.EnginePodman, "registry.example.com/mirror/krakend:2.12", "registry.example.com/mirror/krakend:2.12"},
+		{runtime.EnginePodman, "localhost:5000/krakend:2.12", "localhost:5000/krakend:2.12"},
+	} {
+		if got := runtime.EngineImage(tc.engine, tc.image); got != tc.want {
+			t.Errorf("EngineImage(%q, %q) = %q, want %q", tc.engine, tc.image, got, tc.want)
+		}
+	}
+}
+
+func TestImageOverride(t *testing.T) {
+	t.Setenv("KRAKEND_MCP_DOCKER_IMAGE_EE", "registry.example.com/ee")
+
+	if got := (runtime.ImageOverride{Image: "registry.corp/krakend-ee:2.10"}).Resolve("2.12", true); got != "registry.corp/krakend-ee:2.10" {
+		t.Errorf("Expected the image as is, got %q", got)
+	}
+	// The registry of the call wins over the environment
+	if got := (runtime.ImageOverride{Registry: "registry.corp/mirror/"}).Resolve("2.12", true); got != "registry.corp/mirror/krakend/krakend-ee:2.12" {
+		t.Errorf("Expected the official image from the mirror, got %q", got)
+	}
+	if got := (runtime.ImageOverride{}).Resolve("2.12", true); got != "registry.example.com/ee:2.12" {
+		t.Errorf("Expected the environment override, got %q", got)
+	}
+
+	for _, o := range []runtime.ImageOverride{
+		{Image: "-v/:/host"},
+		{Image: "krakend 2.10"},
+		{Registry: "registry.corp/mirror@sha256"},
+		{Image: "krakend:2.10", Registry: "registry.corp"},
+	} {
+		if err := o.Validate(); err == nil {
+			t.Errorf("Expected %+v to be rejected", o)
+		}
+	}
+	if err := (runtime.ImageOverride{Image: "localhost:5000/krakend@sha256:abc"}).Validate(); err != nil {
+		t.Errorf("Unexpected error %v", err)
+	}
+}
@@ -0,0 +1,84 @@
+package runtime
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"time"
+)
+
+// licensePathEnv is the environment variable that overrides the EE license location
+const licensePathEnv = "KRAKEND_LICENSE_PATH"
+
+// licenseSearchPaths are the default locations where KrakenD EE looks for its license
+var licenseSearchPaths = []string{
+	"LICENSE",
+	"/etc/krakend/LICENSE",
+}
+
+// LicenseInfo describes the KrakenD Enterprise license available to the binary.
+// The license contents are never exposed, only its location and validity.
+type LicenseInfo struct {
+	Present   bool       `json:"present"`
+	Source    string     `json:"source,omitempty"` // File path or environment variable the license was found in
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	Expired   bool       `json:"expired"`
+	Message   string     `json:"message"`
+}
+
+// DetectLicense looks for a KrakenD EE license file and reports its presence and expiry
+func DetectLicense() *LicenseInfo {
+	if path := os.Getenv(licensePathEnv); path != "" {
+		info := inspectLicenseFile(path)
+		if info.Present {
+			info.Source = fmt.Sprintf("%s (%s)", path, licensePathEnv)
+			return info
+		}
+		info.Message = fmt.Sprintf("%s points to %s but no valid license was found there", licensePathEnv, path)
+		return info
+	}
+
+	for _, path := range licenseSearchPaths {
+		if info := inspectLicenseFile(path); info.Present {
+			return info
+		}
+	}
+
+	return &LicenseInfo{
+		Message: "No KrakenD Enterprise license found",
+	}
+}
+
+// inspectLicenseFile checks whether path holds a PEM-encoded license certificate.
+// Plain text files (e.g. an open source LICENSE) are not considered licenses.
+func inspectLicenseFile(path string) *LicenseInfo {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return &LicenseInfo{}
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return &LicenseInfo{}
+	}
+
+	info := &LicenseInfo{
+		Present: true,
+		Source:  path,
+		Message: "KrakenD Enterprise license found",
+	}
+
+	if cert, err := x509.ParseCertificate(block.Bytes); err == nil {
+		expiresAt := cert.NotAfter
+		info.ExpiresAt = &expiresAt
+		if time.Now().After(expiresAt) {
+			info.Expired = true
+			info.Message = fmt.Sprintf("KrakenD Enterprise license expired on %s", expiresAt.Format("2006-01-02"))
+		} else {
+			info.Message = fmt.Sprintf("KrakenD Enterprise license valid until %s", expiresAt.Format("2006-01-02"))
+		}
+	}
+
+	return info
+}
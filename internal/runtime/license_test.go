@@ -0,0 +1,93 @@
+package runtime_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/krakend/mcp-server/internal/runtime"
+)
+
+func writeTestLicense(t *testing.T, notAfter time.Time) string {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "krakend-license"},
+		NotBefore:    notAfter.Add(-365 * 24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "LICENSE")
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatalf("failed to write license: %v", err)
+	}
+	return path
+}
+
+func TestDetectLicense_FromEnv(t *testing.T) {
+	path := writeTestLicense(t, time.Now().Add(30*24*time.Hour))
+	t.Setenv("KRAKEND_LICENSE_PATH", path)
+
+	info := runtime.DetectLicense()
+	if !info.Present {
+		t.Fatal("Expected license to be present")
+	}
+	if info.Expired {
+		t.Error("Expected license not to be expired")
+	}
+	if info.ExpiresAt == nil {
+		t.Error("Expected expiry date to be reported")
+	}
+	if !strings.Contains(info.Source, path) {
+		t.Errorf("Source = %s, want it to contain %s", info.Source, path)
+	}
+}
+
+func TestDetectLicense_Expired(t *testing.T) {
+	path := writeTestLicense(t, time.Now().Add(-24*time.Hour))
+	t.Setenv("KRAKEND_LICENSE_PATH", path)
+
+	info := runtime.DetectLicense()
+	if !info.Present || !info.Expired {
+		t.Errorf("Expected expired license, got present=%v expired=%v", info.Present, info.Expired)
+	}
+}
+
+func TestDetectLicense_PlainTextIsIgnored(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "LICENSE")
+	os.WriteFile(path, []byte("Apache License\nVersion 2.0"), 0o644)
+	t.Setenv("KRAKEND_LICENSE_PATH", path)
+
+	info := runtime.DetectLicense()
+	if info.Present {
+		t.Error("Expected plain text LICENSE not to be detected as EE license")
+	}
+}
+
+func TestDetectRuntimeInfo_EEWithoutLicenseWarns(t *testing.T) {
+	t.Setenv("KRAKEND_LICENSE_PATH", filepath.Join(t.TempDir(), "missing"))
+
+	info, err := runtime.DetectRuntimeInfo(`{"extra_config": {"auth/api-keys": {}}}`)
+	if err != nil {
+		t.Fatalf("DetectRuntimeInfo() error = %v", err)
+	}
+	if len(info.Warnings) == 0 {
+		t.Error("Expected a warning for EE config without license")
+	}
+}
@@ -16,7 +16,12 @@ import (
 type ValidationEnvironment struct {
 	HasNativeKrakenD bool
 	HasDocker        bool
+	DockerBinary     string // CLI of the Docker tier: "docker" or "podman"
+	ContainerEngine  string // "docker" or "podman", also behind the docker command of podman-docker
 	DockerVersion    string
+	DockerMode       string // "local", "stdin" or "off", see ResolveDockerMode
+	DockerModeReason string
+	InContainer      bool
 	FlexibleConfig   *FlexibleConfigInfo
 }
 
@@ -43,7 +48,9 @@ type RuntimeInfo struct {
 	VersionMatch     bool                   `json:"version_match"`
 	RecommendedImage string                 `json:"recommended_image,omitempty"`
 	ExecutionMode    string                 `json:"execution_mode"` // "native", "docker", "docker_recommended", "unavailable"
+	License          *LicenseInfo           `json:"license"`
 	Recommendations  []Recommendation       `json:"recommendations"`
+	Warnings         []string               `json:"warnings,omitempty"`
 }
 
 // Recommendation represents an execution method recommendation
@@ -82,11 +89,7 @@ func DetectRuntimeInfo(configJSON string) (*RuntimeInfo, error) {
 	// Determine recommended image
 	recommendedImage := ""
 	if env.HasDocker {
-		if isEnterprise {
-			recommendedImage = fmt.Sprintf("krakend/krakend-ee:%s", targetVersion)
-		} else {
-			recommendedImage = fmt.Sprintf("krakend:%s", targetVersion)
-		}
+		recommendedImage = EngineImage(env.ContainerEngine, DockerImage(targetVersion, isEnterprise))
 	}
 
 	// Determine execution mode
@@ -104,6 +107,17 @@ func DetectRuntimeInfo(configJSON string) (*RuntimeInfo, error) {
 	// Build recommendations
 	recommendations := buildRecommendations(env, targetVersion, nativeVersion, versionMatch, isEnterprise)
 
+	// Check EE license availability
+	license := DetectLicense()
+	var warnings []string
+	if isEnterprise {
+		if !license.Present {
+			warnings = append(warnings, "Configuration uses Enterprise Edition features but no KrakenD EE license was found; the EE binary will not start without one")
+		} else if license.Expired {
+			warnings = append(warnings, "Configuration uses Enterprise Edition features but the KrakenD EE license has expired")
+		}
+	}
+
 	return &RuntimeInfo{
 		Environment:      env,
 		TargetVersion:    targetVersion,
@@ -113,7 +127,9 @@ func DetectRuntimeInfo(configJSON string) (*RuntimeInfo, error) {
 		VersionMatch:     versionMatch,
 		RecommendedImage: recommendedImage,
 		ExecutionMode:    executionMode,
+		License:          license,
 		Recommendations:  recommendations,
+		Warnings:         warnings,
 	}, nil
 }
 
@@ -137,7 +153,7 @@ func buildRecommendations(env *ValidationEnvironment, targetVersion, nativeVersi
 				Method:          "docker",
 				Priority:        priority,
 				Reason:          "Alternative using Docker",
-				CommandTemplate: buildDockerTemplate(targetVersion, isEnterprise, env.FlexibleConfig),
+				CommandTemplate: buildDockerTemplate(env, targetVersion, isEnterprise),
 			})
 		}
 		return recommendations
@@ -149,7 +165,7 @@ func buildRecommendations(env *ValidationEnvironment, targetVersion, nativeVersi
 			Method:          "docker",
 			Priority:        priority,
 			Reason:          fmt.Sprintf("Exact version match available (v%s)", targetVersion),
-			CommandTemplate: buildDockerTemplate(targetVersion, isEnterprise, env.FlexibleConfig),
+			CommandTemplate: buildDockerTemplate(env, targetVersion, isEnterprise),
 		})
 		priority++
 
@@ -169,7 +185,7 @@ func buildRecommendations(env *ValidationEnvironment, targetVersion, nativeVersi
 			Method:          "docker",
 			Priority:        priority,
 			Reason:          "Native KrakenD not available",
-			CommandTemplate: buildDockerTemplate(targetVersion, isEnterprise, env.FlexibleConfig),
+			CommandTemplate: buildDockerTemplate(env, targetVersion, isEnterprise),
 		})
 		return recommendations
 	}
@@ -194,14 +210,20 @@ func buildRecommendations(env *ValidationEnvironment, targetVersion, nativeVersi
 }
 
 // buildDockerTemplate builds a Docker command template
-func buildDockerTemplate(version string, isEnterprise bool, fc *FlexibleConfigInfo) string {
-	image := fmt.Sprintf("krakend:%s", version)
-	if isEnterprise {
-		image = fmt.Sprintf("krakend/krakend-ee:%s", version)
+func buildDockerTemplate(env *ValidationEnvironment, version string, isEnterprise bool) string {
+	image := EngineImage(env.ContainerEngine, DockerImage(version, isEnterprise))
+	cli := env.DockerBinary
+	if cli == "" {
+		cli = "docker"
+	}
+
+	if mode, _ := ResolveDockerMode(); mode == DockerModeStdin {
+		// The daemon cannot see local files: stream the configuration instead of mounting it
+		return fmt.Sprintf("%s run --rm -i --entrypoint /bin/sh %s -c 'cat > /tmp/krakend.json && krakend [command] -c /tmp/krakend.json' < krakend.json", cli, image)
 	}
 
 	// Simple template for now - FC handling can be added later
-	return fmt.Sprintf("docker run --rm -v $(pwd):/etc/krakend %s [command] -c /etc/krakend/krakend.json", image)
+	return fmt.Sprintf("%s run --rm -v $(pwd):/etc/krakend %s [command] -c /etc/krakend/krakend.json", cli, image)
 }
 
 // ExtractVersionFromConfig extracts the KrakenD version from $schema field
@@ -220,8 +242,8 @@ func ExtractVersionFromConfig(configJSON string) (version string, resolvedFrom s
 	if strings.Contains(schema, "/v") {
 		parts := strings.Split(schema, "/")
 		for i, part := range parts {
-			if strings.HasPrefix(part, "v") && i+1 < len(parts) {
-				return strings.TrimPrefix(part, "v"), ""
+			if strings.HasPrefix(part, "v") && i+1 < len(parts) && schemaVersionPattern.MatchString(part[1:]) {
+				return part[1:], ""
 			}
 		}
 	}
@@ -236,6 +258,10 @@ func ExtractVersionFromConfig(configJSON string) (version string, resolvedFrom s
 	return "latest", ""
 }
 
+// schemaVersionPattern matches the version of a versioned schema URL. The version ends up in
+// Docker image tags, so anything else is ignored
+var schemaVersionPattern = regexp.MustCompile(`^\d+(\.\d+){0,2}$`)
+
 // resolveLatestSchemaVersion fetches the schema and resolves the version from $ref
 func resolveLatestSchemaVersion(schemaURL string) string {
 	client := &http.Client{Timeout: 5 * time.Second}
@@ -253,8 +279,8 @@ func resolveLatestSchemaVersion(schemaURL string) string {
 	if refURL, ok := schema["$ref"].(string); ok {
 		if strings.HasPrefix(refURL, "v") {
 			parts := strings.Split(refURL, "/")
-			if len(parts) > 0 {
-				return strings.TrimPrefix(parts[0], "v")
+			if version := strings.TrimPrefix(parts[0], "v"); schemaVersionPattern.MatchString(version) {
+				return version
 			}
 		}
 	}
@@ -271,10 +297,12 @@ func DetectEnvironment() *ValidationEnvironment {
 		env.HasNativeKrakenD = true
 	}
 
-	// Check for Docker
-	if output, err := exec.Command("docker", "--version").CombinedOutput(); err == nil {
-		env.HasDocker = true
-		env.DockerVersion = strings.TrimSpace(string(output))
+	// Check for Docker (a client without a reachable daemon is not usable)
+	env.InContainer = InContainer()
+	env.DockerMode, env.DockerModeReason = ResolveDockerMode()
+	if binary, engine, version, ok := DetectContainerCLI(); ok {
+		env.HasDocker = env.DockerMode != DockerModeOff
+		env.DockerBinary, env.ContainerEngine, env.DockerVersion = binary, engine, version
 	}
 
 	// Check for Flexible Configuration (imported from tools package if needed)
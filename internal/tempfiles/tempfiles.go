@@ -0,0 +1,95 @@
+// Package tempfiles creates the temporary files and directories handed to krakend and Docker.
+// Names are unique, so concurrent calls never share a file, permissions are private, nothing is
+// left behind when writing fails, and RemoveAll deletes what calls still running at shutdown
+// did not remove yet.
+package tempfiles
+
+import (
+	"fmt"
+	"os"
+	"sync"
+)
+
+// live tracks the files and directories created and not removed yet
+var live = struct {
+	mu    sync.Mutex
+	paths map[string]struct{}
+}{paths: map[string]struct{}{}}
+
+func track(path string) {
+	live.mu.Lock()
+	defer live.mu.Unlock()
+	live.paths[path] = struct{}{}
+}
+
+// remover deletes path, once, and stops tracking it
+func remover(path string) func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			os.RemoveAll(path)
+			live.mu.Lock()
+			delete(live.paths, path)
+			live.mu.Unlock()
+		})
+	}
+}
+
+// File writes data to a new file readable only by the current user (0600), in dir (the system temporary
+// directory when empty), named after pattern like os.CreateTemp. remove deletes it and can be
+// called several times.
+func File(dir, pattern string, data []byte) (path string, remove func(), err error) {
+	f, err := os.CreateTemp(dir, pattern)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create temp file: %w", err)
+	}
+	path = f.Name()
+	track(path)
+	remove = remover(path)
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		remove()
+		return "", nil, fmt.Errorf("failed to write temp file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		remove()
+		return "", nil, fmt.Errorf("failed to close temp file: %w", err)
+	}
+	return path, remove, nil
+}
+
+// Dir creates a directory only accessible by the current user (0700), in parent (the system temporary
+// directory when empty), named after pattern like os.MkdirTemp. remove deletes it with its
+// content and can be called several times.
+func Dir(parent, pattern string) (path string, remove func(), err error) {
+	path, err = os.MkdirTemp(parent, pattern)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	track(path)
+	return path, remover(path), nil
+}
+
+// Live returns the number of files and directories not removed yet
+func Live() int {
+	live.mu.Lock()
+	defer live.mu.Unlock()
+	return len(live.paths)
+}
+
+// RemoveAll deletes the files and directories not removed yet and returns how many there were
+func RemoveAll() int {
+	live.mu.Lock()
+	paths := make([]string, 0, len(live.paths))
+	for path := range live.paths {
+		paths = append(paths, path)
+	}
+	live.paths = map[string]struct{}{}
+	live.mu.Unlock()
+
+	for _, path := range paths {
+		os.RemoveAll(path)
+	}
+	return len(paths)
+}
@@ -0,0 +1,72 @@
+package tempfiles
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestFile(t *testing.T) {
+	dir := t.TempDir()
+	first, removeFirst, err := File(dir, "krakend-*.json", []byte(`{"version":3}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, removeSecond, err := File(dir, "krakend-*.json", []byte(`{}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first == second || filepath.Dir(first) != dir {
+		t.Errorf("Expected unique files in %s, got %s and %s", dir, first, second)
+	}
+	if data, err := os.ReadFile(first); err != nil || string(data) != `{"version":3}` {
+		t.Errorf("Unexpected content %q, %v", data, err)
+	}
+	if info, err := os.Stat(first); err != nil || (runtime.GOOS != "windows" && info.Mode().Perm() != 0o600) {
+		t.Errorf("Expected a private file, got %v, %v", info.Mode(), err)
+	}
+
+	removeFirst()
+	removeFirst()
+	removeSecond()
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("Expected no leftovers, got %v", entries)
+	}
+	if Live() != 0 {
+		t.Errorf("Expected nothing tracked, got %d", Live())
+	}
+
+	if _, _, err := File(filepath.Join(dir, "missing"), "krakend-*.json", nil); err == nil {
+		t.Error("Expected an error in a missing directory")
+	}
+}
+
+func TestDir_RemoveAll(t *testing.T) {
+	parent := t.TempDir()
+	dir, remove, err := Dir(parent, "krakend-fc-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() || (runtime.GOOS != "windows" && info.Mode().Perm() != 0o700) {
+		t.Fatalf("Expected a private directory, got %v, %v", info, err)
+	}
+	if _, _, err := File(dir, "out-*.json", []byte("{}")); err != nil {
+		t.Fatal(err)
+	}
+	if Live() != 2 {
+		t.Errorf("Expected 2 tracked paths, got %d", Live())
+	}
+
+	// Left behind by a call still running at shutdown
+	if n := RemoveAll(); n != 2 {
+		t.Errorf("RemoveAll() = %d, want 2", n)
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Errorf("Expected %s to be removed", dir)
+	}
+	remove()
+	if Live() != 0 {
+		t.Errorf("Expected nothing tracked, got %d", Live())
+	}
+}
@@ -0,0 +1,132 @@
+package testutil
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// KrakenD is a fake krakend binary
+type KrakenD struct {
+	Version  string        // Printed by "krakend version", defaults to 2.12.0
+	Output   string        // Printed by every other command (check, audit...), defaults to "Syntax OK!"
+	ExitCode int           // Of every other command
+	Delay    time.Duration // Before answering every other command, e.g. to outlast a timeout
+	Compiled string        // Written to FC_OUT when the Flexible Configuration sets it
+}
+
+// Install puts the fake krakend first in PATH for the rest of the test and returns its calls
+func (k KrakenD) Install(t testing.TB) *Calls {
+	t.Helper()
+	if k.Version == "" {
+		k.Version = "2.12.0"
+	}
+	if k.Output == "" {
+		k.Output = "Syntax OK!"
+	}
+	var script strings.Builder
+	script.WriteString("[ \"$1\" = version ] && printf '%s\\n' " + quote("KrakenD Version: "+k.Version) + " && exit 0\n")
+	if k.Delay > 0 {
+		fmt.Fprintf(&script, "sleep %.3f\n", k.Delay.Seconds())
+	}
+	if k.Compiled != "" {
+		script.WriteString("[ -n \"$FC_OUT\" ] && printf '%s' " + quote(k.Compiled) + " > \"$FC_OUT\"\n")
+	}
+	fmt.Fprintf(&script, "printf '%%s\\n' %s\nexit %d\n", quote(k.Output), k.ExitCode)
+	return install(t, "krakend", script.String())
+}
+
+// Docker is a fake docker CLI. Every command but --version prints Output, as krakend in the
+// container would.
+type Docker struct {
+	Binary   string // Name of the CLI, defaults to docker (e.g. podman)
+	Version  string // Printed by --version, defaults to "Docker version 27.1.1"
+	Output   string // Defaults to "Syntax OK!"
+	ExitCode int
+}
+
+// Install puts the fake CLI first in PATH for the rest of the test, with the local Docker mode
+// so the daemon is not probed, and returns its calls
+func (d Docker) Install(t testing.TB) *Calls {
+	t.Helper()
+	if d.Binary == "" {
+		d.Binary = "docker"
+	}
+	if d.Version == "" {
+		d.Version = "Docker version 27.1.1"
+	}
+	if d.Output == "" {
+		d.Output = "Syntax OK!"
+	}
+	script := "[ \"$1\" = --version ] && printf '%s\\n' " + quote(d.Version) + " && exit 0\n" +
+		fmt.Sprintf("printf '%%s\\n' %s\nexit %d\n", quote(d.Output), d.ExitCode)
+	t.Setenv("KRAKEND_MCP_DOCKER_MODE", "local")
+	return install(t, d.Binary, script)
+}
+
+// Isolate empties PATH for the rest of the test, so neither krakend nor Docker are found
+// until fakes are installed
+func Isolate(t testing.TB) {
+	t.Helper()
+	t.Setenv("PATH", t.TempDir())
+}
+
+// Calls records the arguments a fake binary was called with
+type Calls struct {
+	log string
+}
+
+// Args returns the arguments of every call, in order
+func (c *Calls) Args() [][]string {
+	data, err := os.ReadFile(c.log)
+	if err != nil {
+		return nil
+	}
+	var calls [][]string
+	for _, line := range strings.Split(strings.TrimSuffix(string(data), "\n"), "\n") {
+		if line != "" {
+			calls = append(calls, strings.Split(line, "\x1f"))
+		}
+	}
+	return calls
+}
+
+// Contains reports whether a call had every given argument, in sequence
+func (c *Calls) Contains(args ...string) bool {
+	want := strings.Join(args, "\x1f")
+	for _, call := range c.Args() {
+		if strings.Contains("\x1f"+strings.Join(call, "\x1f")+"\x1f", "\x1f"+want+"\x1f") {
+			return true
+		}
+	}
+	return false
+}
+
+// install writes a shell script logging its arguments before body in a new directory put first in PATH
+func install(t testing.TB, name, body string) *Calls {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("shell script fakes")
+	}
+	dir := t.TempDir()
+	calls := &Calls{log: filepath.Join(dir, name+".calls")}
+	// Arguments are separated by the unit separator, which they are unlikely to contain. The
+	// system directories keep sleep available with an isolated PATH.
+	script := "#!/bin/sh\nPATH=$PATH:/usr/bin:/bin\n" +
+		"(IFS=$(printf '\\037'); printf '%s\\n' \"$*\") >> " + quote(calls.log) + "\n" +
+		body
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(script), 0o755); err != nil {
+		t.Fatalf("failed to write fake %s: %v", name, err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	return calls
+}
+
+// quote returns s as a single-quoted shell word
+func quote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,59 @@
+package testutil
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// NewServer returns an MCP server to register the tools under test with
+func NewServer() *mcp.Server {
+	return mcp.NewServer(&mcp.Implementation{Name: "test", Version: "0.0.1"}, nil)
+}
+
+// Connect connects an in-memory client to server and returns its session, closed with the test
+func Connect(t testing.TB, server *mcp.Server) *mcp.ClientSession {
+	t.Helper()
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	if _, err := server.Connect(context.Background(), serverTransport, nil); err != nil {
+		t.Fatalf("server.Connect() error = %v", err)
+	}
+	client := mcp.NewClient(&mcp.Implementation{Name: "test", Version: "0.0.1"}, nil)
+	session, err := client.Connect(context.Background(), clientTransport, nil)
+	if err != nil {
+		t.Fatalf("client.Connect() error = %v", err)
+	}
+	t.Cleanup(func() { session.Close() })
+	return session
+}
+
+// CallTool calls a tool the way a client does, with args as its JSON arguments, and decodes
+// its structured content into out (when not nil). Protocol and tool errors fail the test.
+func CallTool(t testing.TB, session *mcp.ClientSession, name string, args, out any) *mcp.CallToolResult {
+	t.Helper()
+	result, err := session.CallTool(context.Background(), &mcp.CallToolParams{Name: name, Arguments: args})
+	if err != nil {
+		t.Fatalf("%s: %v", name, err)
+	}
+	if result.IsError {
+		var message string
+		for _, content := range result.Content {
+			if text, ok := content.(*mcp.TextContent); ok {
+				message += text.Text
+			}
+		}
+		t.Fatalf("%s returned an error: %s", name, message)
+	}
+	if out != nil {
+		data, err := json.Marshal(result.StructuredContent)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := json.Unmarshal(data, out); err != nil {
+			t.Fatalf("%s: unexpected structured content %s: %v", name, data, err)
+		}
+	}
+	return result
+}
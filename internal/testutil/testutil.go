@@ -0,0 +1,71 @@
+// Package testutil provides the fixtures of the tests exercising tools end to end without
+// krakend, Docker or network access: golden configurations, fake krakend and docker binaries
+// injected in PATH, and an in-memory MCP client. It is only meant to be imported by tests.
+package testutil
+
+import (
+	"embed"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"testing"
+)
+
+// Golden configurations
+const (
+	ValidConfig      = "valid.json"       // Community Edition configuration passing every check
+	InvalidConfig    = "invalid.json"     // Well-formed JSON breaking the schema: string port, unknown method, backend without host
+	MalformedConfig  = "malformed.json"   // Truncated JSON
+	EnterpriseConfig = "enterprise.json"  // Valid configuration using the Enterprise-only auth/api-keys
+	FCCompiled       = "fc_compiled.json" // What krakend compiles from the FCProject templates
+)
+
+//go:embed golden
+var golden embed.FS
+
+// Config returns the content of a golden configuration
+func Config(t testing.TB, name string) string {
+	t.Helper()
+	data, err := golden.ReadFile(path.Join("golden", name))
+	if err != nil {
+		t.Fatalf("unknown golden configuration %s: %v", name, err)
+	}
+	return string(data)
+}
+
+// WriteConfig writes a golden configuration in a temporary directory and returns its path
+func WriteConfig(t testing.TB, name string) string {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(file, []byte(Config(t, name)), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+// FCProject copies the golden Community Edition Flexible Configuration project (krakend.tmpl,
+// settings/ and templates/) in a temporary directory and returns it. KrakenD compiles it into
+// the FCCompiled configuration.
+func FCProject(t testing.TB) string {
+	t.Helper()
+	project := t.TempDir()
+	err := fs.WalkDir(golden, "golden/fc", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(project, filepath.FromSlash(name[len("golden/fc"):]))
+		if d.IsDir() {
+			return os.MkdirAll(target, 0o755)
+		}
+		data, err := golden.ReadFile(name)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(target, data, 0o644)
+	})
+	if err != nil {
+		t.Fatalf("failed to copy the Flexible Configuration project: %v", err)
+	}
+	return project
+}
@@ -0,0 +1,112 @@
+package testutil
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestGoldenConfigs(t *testing.T) {
+	for _, name := range []string{ValidConfig, InvalidConfig, EnterpriseConfig, FCCompiled} {
+		var config map[string]any
+		if err := json.Unmarshal([]byte(Config(t, name)), &config); err != nil {
+			t.Errorf("%s must be well-formed JSON: %v", name, err)
+		}
+	}
+	if json.Valid([]byte(Config(t, MalformedConfig))) {
+		t.Error("malformed.json must not be valid JSON")
+	}
+	if data, err := os.ReadFile(WriteConfig(t, ValidConfig)); err != nil || string(data) != Config(t, ValidConfig) {
+		t.Errorf("WriteConfig() wrote %q, %v", data, err)
+	}
+
+	project := FCProject(t)
+	for _, name := range []string{"krakend.tmpl", "settings/service.json", "settings/endpoints.json", "templates/endpoints.tmpl"} {
+		if _, err := os.Stat(filepath.Join(project, name)); err != nil {
+			t.Errorf("Expected %s in the Flexible Configuration project: %v", name, err)
+		}
+	}
+}
+
+func TestKrakenD(t *testing.T) {
+	Isolate(t)
+	calls := KrakenD{Output: "it's invalid", ExitCode: 1, Compiled: `{"version":3}`}.Install(t)
+
+	out, err := exec.Command("krakend", "version").Output()
+	if err != nil || strings.TrimSpace(string(out)) != "KrakenD Version: 2.12.0" {
+		t.Errorf("krakend version = %q, %v", out, err)
+	}
+
+	compiled := filepath.Join(t.TempDir(), "out.json")
+	cmd := exec.Command("krakend", "check", "-c", "krakend tmpl.json")
+	cmd.Env = append(os.Environ(), "FC_OUT="+compiled)
+	out, err = cmd.Output()
+	if exitErr, ok := err.(*exec.ExitError); !ok || exitErr.ExitCode() != 1 || strings.TrimSpace(string(out)) != "it's invalid" {
+		t.Errorf("krakend check = %q, %v", out, err)
+	}
+	if data, _ := os.ReadFile(compiled); string(data) != `{"version":3}` {
+		t.Errorf("Expected the compiled configuration in FC_OUT, got %q", data)
+	}
+
+	if args := calls.Args(); len(args) != 2 || len(args[1]) != 3 || args[1][2] != "krakend tmpl.json" {
+		t.Errorf("Unexpected calls %q", args)
+	}
+	if !calls.Contains("-c", "krakend tmpl.json") || calls.Contains("audit") {
+		t.Error("Contains() does not match the recorded calls")
+	}
+}
+
+func TestKrakenD_Delay(t *testing.T) {
+	Isolate(t)
+	KrakenD{Delay: 100 * time.Millisecond}.Install(t)
+	start := time.Now()
+	if out, err := exec.Command("krakend", "check").Output(); err != nil || strings.TrimSpace(string(out)) != "Syntax OK!" {
+		t.Fatalf("krakend check = %q, %v", out, err)
+	}
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Errorf("Expected the delay, answered in %s", elapsed)
+	}
+}
+
+func TestDocker(t *testing.T) {
+	Isolate(t)
+	calls := Docker{Binary: "podman", Version: "podman version 5.2.0"}.Install(t)
+	if _, err := exec.LookPath("docker"); err == nil {
+		t.Error("Isolate() must hide docker")
+	}
+	if os.Getenv("KRAKEND_MCP_DOCKER_MODE") != "local" {
+		t.Error("Expected the local Docker mode")
+	}
+	if out, err := exec.Command("podman", "--version").Output(); err != nil || strings.TrimSpace(string(out)) != "podman version 5.2.0" {
+		t.Errorf("podman --version = %q, %v", out, err)
+	}
+	if out, err := exec.Command("podman", "run", "--rm", "krakend:2.12", "check").Output(); err != nil || strings.TrimSpace(string(out)) != "Syntax OK!" {
+		t.Errorf("podman run = %q, %v", out, err)
+	}
+	if !calls.Contains("krakend:2.12", "check") {
+		t.Errorf("Unexpected calls %q", calls.Args())
+	}
+}
+
+func TestCallTool(t *testing.T) {
+	type echo struct {
+		Text string `json:"text"`
+	}
+	server := NewServer()
+	mcp.AddTool(server, &mcp.Tool{Name: "echo"}, func(_ context.Context, _ *mcp.CallToolRequest, in echo) (*mcp.CallToolResult, echo, error) {
+		return nil, in, nil
+	})
+
+	var out echo
+	CallTool(t, Connect(t, server), "echo", map[string]any{"text": "hello"}, &out)
+	if out.Text != "hello" {
+		t.Errorf("Unexpected structured content %+v", out)
+	}
+}
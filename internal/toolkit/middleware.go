@@ -0,0 +1,209 @@
+package toolkit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ToolCall describes a tool invocation as seen by middlewares
+type ToolCall struct {
+	Tool      string
+	SessionID string
+	Arguments json.RawMessage // Raw arguments as received from the client
+	Input     any             // Decoded and schema-validated input of the tool
+	Start     time.Time
+}
+
+// ToolHandler executes a tool call; output is the tool's typed output
+type ToolHandler func(ctx context.Context, call *ToolCall) (res *mcp.CallToolResult, output any, err error)
+
+// Middleware wraps tool calls to implement cross-cutting concerns
+// (logging, auditing, policies, rate limiting...) once for every tool
+type Middleware func(next ToolHandler) ToolHandler
+
+var (
+	middlewareMu sync.RWMutex
+	middlewares  []Middleware
+)
+
+// Use appends middlewares to the chain applied to every tool call. The first middleware
+// is the outermost one. Tools registered before Use are also affected.
+func Use(mws ...Middleware) {
+	middlewareMu.Lock()
+	defer middlewareMu.Unlock()
+	middlewares = append(middlewares, mws...)
+}
+
+// ResetMiddlewares removes every middleware (used by tests)
+func ResetMiddlewares() {
+	middlewareMu.Lock()
+	defer middlewareMu.Unlock()
+	middlewares = nil
+}
+
+// chain wraps h with the registered middlewares
+func chain(h ToolHandler) ToolHandler {
+	middlewareMu.RLock()
+	defer middlewareMu.RUnlock()
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		h = middlewares[i](h)
+	}
+	return h
+}
+
+// newToolCall builds the ToolCall of a typed invocation
+func newToolCall(tool string, req *mcp.CallToolRequest, input any) *ToolCall {
+	call := &ToolCall{Tool: tool, Input: input, Start: time.Now()}
+	if req != nil {
+		if req.Params != nil {
+			call.Arguments = req.Params.Arguments
+		}
+		if req.Session != nil {
+			call.SessionID = req.Session.ID()
+		}
+	}
+	return call
+}
+
+// Tool call outcomes reported by the Logging middleware
+const (
+	OutcomeSuccess   = "success"
+	OutcomeError     = "error"      // The handler returned an error
+	OutcomeToolError = "tool_error" // The result is flagged as an error
+)
+
+// Outcome classifies the result of a tool call
+func Outcome(res *mcp.CallToolResult, err error) string {
+	switch {
+	case err != nil:
+		return OutcomeError
+	case res != nil && res.IsError:
+		return OutcomeToolError
+	default:
+		return OutcomeSuccess
+	}
+}
+
+// Logging logs every tool call with its duration and outcome as structured attributes
+func Logging() Middleware {
+	return func(next ToolHandler) ToolHandler {
+		return func(ctx context.Context, call *ToolCall) (*mcp.CallToolResult, any, error) {
+			res, out, err := next(ctx, call)
+			outcome := Outcome(res, err)
+			attrs := []any{
+				"tool", call.Tool,
+				"duration_ms", time.Since(call.Start).Milliseconds(),
+				"outcome", outcome,
+			}
+			if call.SessionID != "" {
+				attrs = append(attrs, "session", call.SessionID)
+			}
+			switch outcome {
+			case OutcomeError:
+				slog.WarnContext(ctx, "Tool call failed", append(attrs, "error", err)...)
+			case OutcomeToolError:
+				slog.WarnContext(ctx, "Tool call failed", attrs...)
+			default:
+				slog.InfoContext(ctx, "Tool call completed", attrs...)
+			}
+			return res, out, err
+		}
+	}
+}
+
+// Policy rejects tool calls for which allow returns an error
+func Policy(allow func(call *ToolCall) error) Middleware {
+	return func(next ToolHandler) ToolHandler {
+		return func(ctx context.Context, call *ToolCall) (*mcp.CallToolResult, any, error) {
+			if err := allow(call); err != nil {
+				return nil, nil, fmt.Errorf("tool %s rejected by policy: %w", call.Tool, err)
+			}
+			return next(ctx, call)
+		}
+	}
+}
+
+// RateLimit allows at most perMinute calls of each tool per minute (token bucket).
+// Shared servers use it to keep execution-heavy tools from being hammered.
+func RateLimit(perMinute int) Middleware {
+	type bucket struct {
+		tokens float64
+		last   time.Time
+	}
+	var mu sync.Mutex
+	buckets := map[string]*bucket{}
+	rate := float64(perMinute) / 60 // Tokens per second
+
+	return func(next ToolHandler) ToolHandler {
+		return func(ctx context.Context, call *ToolCall) (*mcp.CallToolResult, any, error) {
+			if perMinute <= 0 {
+				return next(ctx, call)
+			}
+			mu.Lock()
+			b, ok := buckets[call.Tool]
+			if !ok {
+				b = &bucket{tokens: float64(perMinute), last: call.Start}
+				buckets[call.Tool] = b
+			}
+			b.tokens += call.Start.Sub(b.last).Seconds() * rate
+			if b.tokens > float64(perMinute) {
+				b.tokens = float64(perMinute)
+			}
+			b.last = call.Start
+			allowed := b.tokens >= 1
+			if allowed {
+				b.tokens--
+			}
+			mu.Unlock()
+
+			if !allowed {
+				return nil, nil, fmt.Errorf("rate limit exceeded for tool %s (%d calls per minute), retry later", call.Tool, perMinute)
+			}
+			return next(ctx, call)
+		}
+	}
+}
+
+// AuditEvent is passed to audit hooks once a tool call finishes
+type AuditEvent struct {
+	Time      time.Time       `json:"time"`
+	Tool      string          `json:"tool"`
+	SessionID string          `json:"session_id,omitempty"`
+	Arguments json.RawMessage `json:"arguments,omitempty"` // Redacted when a redactor is given
+	Duration  time.Duration   `json:"duration_ns"`
+	IsError   bool            `json:"is_error"`
+	Error     string          `json:"error,omitempty"`
+}
+
+// Audit calls hook after every tool call. redact (optional) removes secrets from the arguments.
+func Audit(hook func(AuditEvent), redact func(json.RawMessage) json.RawMessage) Middleware {
+	return func(next ToolHandler) ToolHandler {
+		return func(ctx context.Context, call *ToolCall) (*mcp.CallToolResult, any, error) {
+			res, out, err := next(ctx, call)
+
+			event := AuditEvent{
+				Time:      call.Start,
+				Tool:      call.Tool,
+				SessionID: call.SessionID,
+				Arguments: call.Arguments,
+				Duration:  time.Since(call.Start),
+			}
+			if redact != nil {
+				event.Arguments = redact(call.Arguments)
+			}
+			if err != nil {
+				event.IsError, event.Error = true, err.Error()
+			} else if res != nil && res.IsError {
+				event.IsError = true
+			}
+			hook(event)
+			return res, out, err
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package toolkit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestMiddlewareChain_Order(t *testing.T) {
+	defer ResetMiddlewares()
+
+	var order []string
+	trace := func(name string) Middleware {
+		return func(next ToolHandler) ToolHandler {
+			return func(ctx context.Context, call *ToolCall) (*mcp.CallToolResult, any, error) {
+				order = append(order, name+">")
+				res, out, err := next(ctx, call)
+				order = append(order, "<"+name)
+				return res, out, err
+			}
+		}
+	}
+	Use(trace("outer"), trace("inner"))
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "0.0.1"}, nil)
+	AddTool(server, &mcp.Tool{Name: "echo", Description: "Echo"}, echo)
+	session := connect(t, server)
+
+	res, err := session.CallTool(context.Background(), &mcp.CallToolParams{Name: "echo", Arguments: map[string]any{"message": "hi"}})
+	if err != nil || res.IsError {
+		t.Fatalf("CallTool() = %+v, %v", res, err)
+	}
+	if got := strings.Join(order, " "); got != "outer> inner> <inner <outer" {
+		t.Errorf("Unexpected middleware order: %s", got)
+	}
+}
+
+func TestPolicy_RejectsCalls(t *testing.T) {
+	defer ResetMiddlewares()
+	Use(Policy(func(call *ToolCall) error {
+		if call.Tool == "echo" {
+			return errors.New("disabled")
+		}
+		return nil
+	}))
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "0.0.1"}, nil)
+	AddTool(server, &mcp.Tool{Name: "echo", Description: "Echo"}, echo)
+	session := connect(t, server)
+
+	res, err := session.CallTool(context.Background(), &mcp.CallToolParams{Name: "echo", Arguments: map[string]any{"message": "hi"}})
+	if err != nil {
+		t.Fatalf("CallTool() error = %v", err)
+	}
+	if !res.IsError || !strings.Contains(res.Content[0].(*mcp.TextContent).Text, "rejected by policy") {
+		t.Errorf("Expected policy rejection, got %+v", res)
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	handler := RateLimit(2)(func(ctx context.Context, call *ToolCall) (*mcp.CallToolResult, any, error) {
+		return nil, nil, nil
+	})
+
+	start := time.Now()
+	call := func(tool string, at time.Time) error {
+		_, _, err := handler(context.Background(), &ToolCall{Tool: tool, Start: at})
+		return err
+	}
+
+	if call("a", start) != nil || call("a", start) != nil {
+		t.Fatal("Expected the first two calls to be allowed")
+	}
+	if call("a", start) == nil {
+		t.Error("Expected the third call within the minute to be rejected")
+	}
+	if call("b", start) != nil {
+		t.Error("Expected limits to be tracked per tool")
+	}
+	if call("a", start.Add(30*time.Second)) != nil {
+		t.Error("Expected a token to be refilled after 30s")
+	}
+}
+
+func TestAudit_RedactsArguments(t *testing.T) {
+	var events []AuditEvent
+	redact := func(json.RawMessage) json.RawMessage { return json.RawMessage(`{"config":"<REDACTED>"}`) }
+	handler := Audit(func(e AuditEvent) { events = append(events, e) }, redact)(func(ctx context.Context, call *ToolCall) (*mcp.CallToolResult, any, error) {
+		return nil, nil, errors.New("boom")
+	})
+
+	handler(context.Background(), &ToolCall{Tool: "validate_config", Arguments: json.RawMessage(`{"config":"secret"}`), Start: time.Now()})
+
+	if len(events) != 1 {
+		t.Fatalf("Expected 1 audit event, got %d", len(events))
+	}
+	e := events[0]
+	if e.Tool != "validate_config" || !e.IsError || e.Error != "boom" || strings.Contains(string(e.Arguments), "secret") {
+		t.Errorf("Unexpected audit event: %+v", e)
+	}
+}
+
+func TestLogging_StructuredAttributes(t *testing.T) {
+	var logs bytes.Buffer
+	defer slog.SetDefault(slog.Default())
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&logs, nil)))
+
+	handler := Logging()(func(ctx context.Context, call *ToolCall) (*mcp.CallToolResult, any, error) {
+		if call.Tool == "audit_security" {
+			return &mcp.CallToolResult{IsError: true}, nil, nil
+		}
+		return nil, nil, nil
+	})
+	handler(context.Background(), &ToolCall{Tool: "validate_config", SessionID: "s1", Start: time.Now()})
+	handler(context.Background(), &ToolCall{Tool: "audit_security", Start: time.Now()})
+
+	var entries []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(logs.String()), "\n") {
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("Invalid JSON log line %q: %v", line, err)
+		}
+		entries = append(entries, entry)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("Expected a log line per call, got %v", entries)
+	}
+	if e := entries[0]; e["tool"] != "validate_config" || e["outcome"] != OutcomeSuccess || e["session"] != "s1" || e["level"] != "INFO" {
+		t.Errorf("Unexpected log entry: %v", e)
+	}
+	if _, ok := entries[0]["duration_ms"].(float64); !ok {
+		t.Errorf("Expected a numeric duration, got %v", entries[0])
+	}
+	if e := entries[1]; e["outcome"] != OutcomeToolError || e["level"] != "WARN" {
+		t.Errorf("Unexpected log entry: %v", e)
+	}
+}
@@ -0,0 +1,66 @@
+package toolkit
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+var (
+	crashMu        sync.RWMutex
+	crashReportDir string
+)
+
+// SetCrashReportDir sets the directory where crash reports are written ("" disables them)
+func SetCrashReportDir(dir string) {
+	crashMu.Lock()
+	defer crashMu.Unlock()
+	crashReportDir = dir
+}
+
+// PanicError is returned to the client when a tool handler panics
+type PanicError struct {
+	Tool   string
+	Value  interface{}
+	Report string // Crash report file, if one was written
+}
+
+func (e *PanicError) Error() string {
+	msg := fmt.Sprintf("internal error in tool %s: %v", e.Tool, e.Value)
+	if e.Report != "" {
+		msg += fmt.Sprintf(" (crash report: %s)", e.Report)
+	}
+	return msg + ". The server is still running; please report this issue."
+}
+
+// recoverPanic converts a panic in a tool handler into a PanicError, logging the stack trace
+// and writing a crash report. It must be called directly by a deferred function.
+func recoverPanic(tool string, value interface{}) error {
+	stack := debug.Stack()
+	slog.Error("Panic in tool", "tool", tool, "panic", fmt.Sprint(value), "stack", string(stack))
+
+	perr := &PanicError{Tool: tool, Value: value}
+	crashMu.RLock()
+	dir := crashReportDir
+	crashMu.RUnlock()
+	if dir == "" {
+		return perr
+	}
+
+	now := time.Now()
+	path := filepath.Join(dir, fmt.Sprintf("crash-%s-%s-%d.txt", now.Format("20060102-150405"), tool, os.Getpid()))
+	// Inputs are deliberately left out: they may contain secrets
+	report := fmt.Sprintf("time: %s\ntool: %s\npanic: %v\n\n%s", now.Format(time.RFC3339), tool, value, stack)
+	if err := os.MkdirAll(dir, 0o700); err == nil {
+		if err := os.WriteFile(path, []byte(report), 0o600); err == nil {
+			perr.Report = path
+		} else {
+			slog.Warn("Could not write crash report", "tool", tool, "error", err)
+		}
+	}
+	return perr
+}
@@ -0,0 +1,104 @@
+// Package toolkit provides the common registration path for MCP tools.
+// Every tool is registered through AddTool so all of them advertise a versioned
+// output schema, reference it in their responses and recover from panics.
+package toolkit
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// OutputSchemaVersion is bumped whenever a tool output changes in a backwards incompatible way
+const OutputSchemaVersion = "v1"
+
+// OutputSchemaMetaKey is the _meta key holding the output schema URI in tool responses
+const OutputSchemaMetaKey = "output_schema"
+
+var (
+	schemasMu     sync.RWMutex
+	outputSchemas = map[string]*jsonschema.Schema{}
+)
+
+// OutputSchemaURI returns the versioned URI identifying the output schema of a tool
+func OutputSchemaURI(tool string) string {
+	return fmt.Sprintf("krakend-mcp://schemas/output/%s/%s", tool, OutputSchemaVersion)
+}
+
+// AddTool registers a typed tool advertising an explicit, versioned output schema.
+// The schema is inferred from Out unless the tool already declares one, and every
+// successful response carries its URI under _meta.output_schema. Panics in the handler
+// are returned as tool errors (see PanicError) instead of crashing the server, and every
+// call goes through the middleware chain (see Use).
+func AddTool[In, Out any](server *mcp.Server, tool *mcp.Tool, handler mcp.ToolHandlerFor[In, Out]) {
+	if tool.OutputSchema == nil {
+		schema, err := jsonschema.For[Out](nil)
+		if err != nil {
+			// Invalid output types are programming errors, like in mcp.AddTool
+			panic(fmt.Sprintf("tool %q: output schema: %v", tool.Name, err))
+		}
+		schema.ID = OutputSchemaURI(tool.Name)
+		schema.Title = tool.Name + " output"
+		tool.OutputSchema = schema
+	}
+	if schema, ok := tool.OutputSchema.(*jsonschema.Schema); ok {
+		schemasMu.Lock()
+		outputSchemas[tool.Name] = schema
+		schemasMu.Unlock()
+	}
+
+	uri := OutputSchemaURI(tool.Name)
+	mcp.AddTool(server, tool, func(ctx context.Context, req *mcp.CallToolRequest, input In) (res *mcp.CallToolResult, out Out, err error) {
+		// A panicking tool must not take the whole server (and the user's session) down
+		defer func() {
+			if v := recover(); v != nil {
+				var zero Out
+				res, out, err = nil, zero, recoverPanic(tool.Name, v)
+			}
+		}()
+
+		final := func(ctx context.Context, call *ToolCall) (*mcp.CallToolResult, any, error) {
+			return handler(ctx, req, call.Input.(In))
+		}
+		var output any
+		res, output, err = chain(final)(ctx, newToolCall(tool.Name, req, input))
+		if output != nil {
+			out, _ = output.(Out)
+		}
+		if err != nil {
+			return res, out, err
+		}
+		if res == nil {
+			res = &mcp.CallToolResult{}
+		}
+		if res.Meta == nil {
+			res.Meta = mcp.Meta{}
+		}
+		res.Meta[OutputSchemaMetaKey] = uri
+		return res, out, nil
+	})
+}
+
+// OutputSchema returns the advertised output schema of a registered tool
+func OutputSchema(tool string) (*jsonschema.Schema, bool) {
+	schemasMu.RLock()
+	defer schemasMu.RUnlock()
+	schema, ok := outputSchemas[tool]
+	return schema, ok
+}
+
+// ToolNames returns the names of every tool registered through AddTool, sorted
+func ToolNames() []string {
+	schemasMu.RLock()
+	defer schemasMu.RUnlock()
+	names := make([]string, 0, len(outputSchemas))
+	for name := range outputSchemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
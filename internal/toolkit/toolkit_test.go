@@ -0,0 +1,132 @@
+package toolkit
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+type echoInput struct {
+	Message string `json:"message"`
+}
+
+type echoOutput struct {
+	Echo  string   `json:"echo"`
+	Words []string `json:"words"`
+}
+
+func echo(ctx context.Context, req *mcp.CallToolRequest, input echoInput) (*mcp.CallToolResult, echoOutput, error) {
+	return nil, echoOutput{Echo: input.Message, Words: []string{input.Message}}, nil
+}
+
+func connect(t *testing.T, server *mcp.Server) *mcp.ClientSession {
+	t.Helper()
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	if _, err := server.Connect(context.Background(), serverTransport, nil); err != nil {
+		t.Fatalf("server.Connect() error = %v", err)
+	}
+	client := mcp.NewClient(&mcp.Implementation{Name: "test", Version: "0.0.1"}, nil)
+	session, err := client.Connect(context.Background(), clientTransport, nil)
+	if err != nil {
+		t.Fatalf("client.Connect() error = %v", err)
+	}
+	t.Cleanup(func() { session.Close() })
+	return session
+}
+
+func TestAddTool_AdvertisesOutputSchema(t *testing.T) {
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "0.0.1"}, nil)
+	AddTool(server, &mcp.Tool{Name: "echo", Description: "Echo"}, echo)
+
+	schema, ok := OutputSchema("echo")
+	if !ok {
+		t.Fatal("Expected output schema to be registered")
+	}
+	if schema.ID != OutputSchemaURI("echo") {
+		t.Errorf("Expected $id %s, got %s", OutputSchemaURI("echo"), schema.ID)
+	}
+	if _, ok := schema.Properties["echo"]; !ok {
+		t.Errorf("Expected echo property in schema, got %v", schema.Properties)
+	}
+
+	session := connect(t, server)
+	tools, err := session.ListTools(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("ListTools() error = %v", err)
+	}
+	if len(tools.Tools) != 1 || tools.Tools[0].OutputSchema == nil {
+		t.Fatalf("Expected echo tool advertising an output schema, got %+v", tools.Tools)
+	}
+}
+
+func TestAddTool_ResponseReferencesSchema(t *testing.T) {
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "0.0.1"}, nil)
+	AddTool(server, &mcp.Tool{Name: "echo", Description: "Echo"}, echo)
+
+	session := connect(t, server)
+	res, err := session.CallTool(context.Background(), &mcp.CallToolParams{
+		Name:      "echo",
+		Arguments: map[string]any{"message": "hi"},
+	})
+	if err != nil {
+		t.Fatalf("CallTool() error = %v", err)
+	}
+	if res.IsError {
+		t.Fatalf("Unexpected tool error: %+v", res.Content)
+	}
+	if got := res.Meta[OutputSchemaMetaKey]; got != OutputSchemaURI("echo") {
+		t.Errorf("Expected _meta.%s = %s, got %v", OutputSchemaMetaKey, OutputSchemaURI("echo"), got)
+	}
+}
+
+func TestAddTool_KeepsDeclaredSchema(t *testing.T) {
+	declared := &jsonschema.Schema{Type: "object", Title: "custom"}
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "0.0.1"}, nil)
+	AddTool(server, &mcp.Tool{Name: "custom", Description: "Custom", OutputSchema: declared}, echo)
+
+	if schema, _ := OutputSchema("custom"); schema != declared {
+		t.Errorf("Expected declared schema to be kept, got %+v", schema)
+	}
+}
+
+func TestAddTool_RecoversFromPanics(t *testing.T) {
+	dir := t.TempDir()
+	SetCrashReportDir(dir)
+	defer SetCrashReportDir("")
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "0.0.1"}, nil)
+	AddTool(server, &mcp.Tool{Name: "boom", Description: "Panics"}, func(ctx context.Context, req *mcp.CallToolRequest, input echoInput) (*mcp.CallToolResult, echoOutput, error) {
+		var m map[string]int
+		m["x"] = 1 // nil map assignment
+		return nil, echoOutput{}, nil
+	})
+	AddTool(server, &mcp.Tool{Name: "echo", Description: "Echo"}, echo)
+
+	session := connect(t, server)
+	res, err := session.CallTool(context.Background(), &mcp.CallToolParams{Name: "boom", Arguments: map[string]any{"message": "x"}})
+	if err != nil {
+		t.Fatalf("CallTool() error = %v", err)
+	}
+	if !res.IsError {
+		t.Fatal("Expected panic to be returned as a tool error")
+	}
+	text := res.Content[0].(*mcp.TextContent).Text
+	if !strings.Contains(text, "internal error in tool boom") || !strings.Contains(text, "crash report") {
+		t.Errorf("Unexpected error message: %s", text)
+	}
+
+	reports, _ := filepath.Glob(filepath.Join(dir, "crash-*-boom-*.txt"))
+	if len(reports) != 1 {
+		t.Errorf("Expected one crash report, got %v", reports)
+	}
+
+	// The server keeps serving other calls
+	res, err = session.CallTool(context.Background(), &mcp.CallToolParams{Name: "echo", Arguments: map[string]any{"message": "still alive"}})
+	if err != nil || res.IsError {
+		t.Errorf("Expected server to keep working after a panic, got %v %+v", err, res)
+	}
+}
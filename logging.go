@@ -0,0 +1,35 @@
+package main
+
+import (
+	"io"
+	"log/slog"
+	"os"
+)
+
+// logFormats are the accepted --log-format values
+var logFormats = []string{"text", "json"}
+
+// newLogHandler returns the handler writing the server logs to w. JSON logs carry one object per
+// line with time, level, msg and the attributes of the message (e.g. tool, duration_ms and
+// outcome for tool calls), so operators can parse them.
+func newLogHandler(w io.Writer, level, format string) slog.Handler {
+	var min slog.Level
+	min.UnmarshalText([]byte(level))
+	opts := &slog.HandlerOptions{Level: min}
+	if format == "json" {
+		return slog.NewJSONHandler(w, opts)
+	}
+	return slog.NewTextHandler(w, opts)
+}
+
+// setupLogging writes the server logs to stderr (stdout carries the MCP protocol). Messages of
+// the standard logger go through the same handler at the info level.
+func setupLogging(level, format string) {
+	slog.SetDefault(slog.New(newLogHandler(os.Stderr, level, format)))
+}
+
+// fatal logs an error and exits
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
+}
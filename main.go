@@ -2,13 +2,18 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
+	"time"
 
+	"github.com/krakend/mcp-server/internal/notify"
 	"github.com/krakend/mcp-server/internal/usage"
 	"github.com/krakend/mcp-server/tools"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
@@ -19,6 +24,8 @@ const (
 	defaultHttpPort = "8090"
 	serverName      = "krakend-mcp-server"
 	description     = "MCP server for KrakenD API Gateway configuration assistance"
+	// shutdownTimeout bounds how long in-flight validations and HTTP requests may take to stop
+	shutdownTimeout = 10 * time.Second
 )
 
 func main() {
@@ -30,25 +37,58 @@ func main() {
 	go func() {
 		select {
 		case sig := <-sigs:
-			log.Println("Signal intercepted:", sig)
+			slog.Info("Signal intercepted", "signal", sig.String())
 			cancel()
 		case <-ctx.Done():
+			return
 		}
+		// A second signal skips the graceful shutdown
+		sig := <-sigs
+		slog.Warn("Signal intercepted again, exiting now", "signal", sig.String())
+		os.Exit(1)
 	}()
 
-	serveMode := false
 	if len(os.Args) > 1 {
 		if os.Args[1] == "--version" {
 			fmt.Printf("%s version %s\n", serverName, version)
 			os.Exit(0)
 		}
-
-		serveMode = os.Args[1] == "--http"
+		if os.Args[1] == "help" || os.Args[1] == "--help" || os.Args[1] == "-h" {
+			cliUsage(os.Stdout)
+			os.Exit(0)
+		}
+		if isCLICommand(os.Args[1]) {
+			code := runCLICommand(ctx, os.Args[1], os.Args[2:], os.Stdin, os.Stdout, os.Stderr)
+			tools.WaitConfigMemory()
+			os.Exit(code)
+		}
+		if os.Args[1] == "setup" {
+			log.SetOutput(os.Stderr)
+			os.Exit(runSetupCommand(ctx, os.Args[2:], os.Stdout, os.Stderr))
+		}
 	}
 
 	// Set up logging to stderr (MCP uses stdout for protocol)
 	log.SetOutput(os.Stderr)
-	log.Printf("%s v%s starting...", serverName, version)
+	slog.Info("Server starting", "server", serverName, "version", version)
+
+	settings, err := parseSettings(os.Args[1:])
+	if err != nil {
+		fatal("Invalid settings", err)
+	}
+	if err := settings.apply(); err != nil {
+		fatal("Invalid settings", err)
+	}
+	filter, err := parseToolsetFilter(os.Args[1:])
+	if err != nil {
+		fatal("Invalid toolset selection", err)
+	}
+	transport, err := parseTransport(os.Args[1:])
+	if err != nil {
+		fatal("Invalid transport", err)
+	}
+	settings.resolve("transport", transport.name)
+	settings.resolve("listen", transport.listen)
 
 	var reporter usage.Reporter
 	if os.Getenv("USAGE_DISABLE") == "1" {
@@ -57,82 +97,113 @@ func main() {
 		r, err := usage.NewReporter(serverName, version, os.Getenv("USAGE_URL"))
 		if err != nil {
 			r = usage.NewNoopReporter()
-			log.Printf("Failed to create usage reporter: %v", err)
+			slog.Warn("Failed to create usage reporter", "error", err)
 		}
 		reporter = r
 	}
 
+	if err := configureMiddlewares(settings.denyTools, settings.metrics); err != nil {
+		fatal("Invalid middleware configuration", err)
+	}
+
+	serverConfig, err := loadServerConfig(os.Getenv("KRAKEND_MCP_SERVER_CONFIG"))
+	if err != nil {
+		fatal("Invalid server configuration", err)
+	}
+	notifications, err := configureNotifications(serverConfig)
+	if err != nil {
+		fatal("Invalid notification configuration", err)
+	}
+	if err := configureSchedules(ctx, serverConfig); err != nil {
+		fatal("Invalid schedule configuration", err)
+	}
+
 	// Create MCP server
 	server := createMCPServer()
 
 	server.AddReceivingMiddleware(usage.NewUsageMethodHandlerFactory(ctx, reporter))
 
+	if tracePath := tools.TracePath(); tracePath != "" {
+		server.AddReceivingMiddleware(tools.NewTraceMiddleware(tracePath, version))
+		slog.Info("Recording tool calls", "path", tracePath)
+	}
+
 	// Register all tools, resources, and prompts
-	if err := registerTools(server); err != nil {
-		log.Fatalf("Failed to register tools: %v", err)
+	if err := registerTools(server, filter, settings); err != nil {
+		fatal("Failed to register tools", err)
+	}
+	if filter.allows("validation") {
+		// Keep validation tool descriptions in sync with krakend/Docker availability
+		go tools.WatchValidationEnvironment(ctx, server, 0)
+	}
+	if transport.serve {
+		// Daemon mode: the sessions share the index and feature data, loaded once for all of them
+		tools.Preload()
 	}
 	if err := registerResources(server); err != nil {
-		log.Fatalf("Failed to register resources: %v", err)
+		fatal("Failed to register resources", err)
 	}
 	if err := registerPrompts(server); err != nil {
-		log.Fatalf("Failed to register prompts: %v", err)
+		fatal("Failed to register prompts", err)
 	}
 
-	// Set up cleanup on shutdown
-	defer func() {
-		if err := tools.CloseDocSearch(); err != nil {
-			log.Printf("Error closing doc search: %v", err)
-		}
-	}()
-
-	if !serveMode {
-		log.Printf("✓ Running in stdio mode")
-		if err := server.Run(ctx, &mcp.StdioTransport{}); err != nil {
-			if err == context.Canceled {
-				log.Printf("Server gracefully stopped")
-				os.Exit(0)
-			}
-			log.Fatalf("Server run error: %v", err)
+	if transport.name == transportStdio {
+		slog.Info("Running in stdio mode")
+		err := server.Run(ctx, &mcp.StdioTransport{})
+		cancel()
+		shutdown(nil, notifications)
+		if err != nil && !errors.Is(err, context.Canceled) {
+			fatal("Server run error", err)
 		}
-		os.Exit(0)
+		slog.Info("Server gracefully stopped")
+		return
 	}
 
-	mcpHandler := mcp.NewStreamableHTTPHandler(
-		func(_ *http.Request) *mcp.Server {
-			return server
-		},
-		&mcp.StreamableHTTPOptions{
-			Stateless:    false,
-			JSONResponse: true,
-		},
-	)
-
-	httpHandler := http.HandlerFunc(mcpHandler.ServeHTTP)
-
 	mux := http.NewServeMux()
+	registerHealthEndpoints(ctx, mux, filter)
+	registerMetricsEndpoint(mux)
 
 	// Using old router matcher to pass all methods to MCP handler
-	mux.HandleFunc("/", httpHandler)
-
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = defaultHttpPort
-	}
+	mux.Handle("/", transport.handler(server))
 
 	s := &http.Server{
-		Addr:    ":" + port,
+		Addr:    transport.listen,
 		Handler: mux,
 	}
 
 	go func() {
-		log.Printf("✓ Starting server on %s", s.Addr)
-		s.ListenAndServe()
+		slog.Info("Starting server", "transport", transport.name, "listen", s.Addr, "serve", transport.serve, "session_timeout", transport.sessionTimeout.String())
+		if err := s.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			slog.Error("HTTP server error", "error", err)
+			cancel()
+		}
 	}()
 
 	<-ctx.Done()
-	log.Printf("Shutting down server...")
-	s.Shutdown(ctx)
-	log.Printf("Server gracefully stopped")
+	shutdown(s, notifications)
+	slog.Info("Server gracefully stopped")
+}
+
+// shutdown releases the server resources once the root context is cancelled: running validation
+// subprocesses are aborted so in-flight requests end quickly, the documentation index is closed and
+// its lock released, and the HTTP server (if any) drains the remaining requests. Deferred calls do
+// not run after os.Exit, so every exit path goes through here.
+func shutdown(s *http.Server, notifications *notify.Dispatcher) {
+	slog.Info("Shutting down server")
+	if err := tools.Shutdown(shutdownTimeout); err != nil {
+		slog.Error("Error during shutdown", "error", err)
+	}
+	if s != nil {
+		// The root context is already cancelled: draining gets its own deadline
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := s.Shutdown(ctx); err != nil {
+			slog.Warn("HTTP server did not stop cleanly", "error", err)
+		}
+	}
+	if notifications != nil {
+		notifications.Wait()
+	}
 }
 
 // createMCPServer initializes the MCP server
@@ -143,54 +214,153 @@ func createMCPServer() *mcp.Server {
 			Title:   description,
 			Version: version,
 		},
-		nil,
+		&mcp.ServerOptions{
+			// Any resource can be subscribed to, the documentation pages notify their updates
+			SubscribeHandler:   func(context.Context, *mcp.SubscribeRequest) error { return nil },
+			UnsubscribeHandler: func(context.Context, *mcp.UnsubscribeRequest) error { return nil },
+		},
 	)
 
-	log.Printf("Server initialized: %s v%s", serverName, version)
+	slog.Info("Server initialized", "server", serverName, "version", version)
 	return server
 }
 
-// registerTools registers all MCP tools
-func registerTools(server *mcp.Server) error {
+// registerTools registers the MCP tools of every toolset allowed by the filter
+func registerTools(server *mcp.Server, filter *toolsetFilter, settings *settings) error {
 	toolCount := 0
+	toolsets := []string{}
+
+	// Phase 1: Core validation tools (3 tools)
+	if filter.allows("validation") {
+		if err := tools.RegisterValidationTools(server); err != nil {
+			return fmt.Errorf("failed to register validation tools: %w", err)
+		}
+		toolCount += 3
+		toolsets = append(toolsets, "validation")
+	}
 
-	// Phase 1: Core validation tools (2 tools)
-	if err := tools.RegisterValidationTools(server); err != nil {
-		return fmt.Errorf("failed to register validation tools: %w", err)
+	// Phase 1: Runtime detection and setup tools (2 tools)
+	if filter.allows("runtime") {
+		tools.RegisterRuntimeTools(server)
+		tools.RegisterSetupTools(server)
+		toolCount += 2
+		toolsets = append(toolsets, "runtime")
 	}
-	toolCount += 2
 
-	// Phase 1: Runtime detection tool (1 tool)
-	tools.RegisterRuntimeTools(server)
-	toolCount++
+	// Phase 1: Documentation search tools (6 tools)
+	if filter.allows("search") {
+		if err := tools.RegisterDocSearchTools(server); err != nil {
+			slog.Warn("Failed to register doc search tools, documentation search will be unavailable", "error", err)
+		} else {
+			tools.RegisterDocsCoverageTools(server)
+			tools.RegisterKnowledgeTools(server)
+			tools.RegisterFeedbackTools(server)
+			toolCount += 6
+			toolsets = append(toolsets, "search")
+		}
+	}
 
-	// Phase 1: Documentation search tools (2 tools)
-	if err := tools.RegisterDocSearchTools(server); err != nil {
-		log.Printf("Warning: Failed to register doc search tools: %v", err)
-		log.Printf("Documentation search will be unavailable")
-	} else {
+	// Phase 1: Feature detection and plugin catalog tools (3 tools)
+	if filter.allows("features") {
+		if err := tools.RegisterFeatureTools(server); err != nil {
+			return fmt.Errorf("failed to register feature tools: %w", err)
+		}
+		tools.RegisterPluginCatalogTools(server)
+		toolCount += 3
+		toolsets = append(toolsets, "features")
+	}
+
+	// Configuration generation tools (25 tools)
+	if filter.allows("generation") {
+		tools.RegisterGenerationTools(server)
+		tools.RegisterEditorSchemaTools(server)
+		tools.RegisterSanitizeTools(server)
+		tools.RegisterGrafanaTools(server)
+		tools.RegisterAlertTools(server)
+		tools.RegisterChaosTools(server)
+		tools.RegisterReplayTools(server)
+		tools.RegisterEndpointDocsTools(server)
+		tools.RegisterSnippetTools(server)
+		tools.RegisterPlaygroundTools(server)
+		tools.RegisterVisualizeTools(server)
+		tools.RegisterPipelineTools(server)
+		tools.RegisterBackendAuthTools(server)
+		tools.RegisterSecretTools(server)
+		tools.RegisterRequestValidationTools(server)
+		tools.RegisterTieredRateLimitTools(server)
+		tools.RegisterAPIPlanTools(server)
+		tools.RegisterCapacityTools(server)
+		tools.RegisterSchemaPinTools(server)
+		tools.RegisterEndpointNameTools(server)
+		tools.RegisterHeaderPolicyTools(server)
+		tools.RegisterRequestIDTools(server)
+		tools.RegisterABRoutingTools(server)
+		tools.RegisterVersionAPITools(server)
+		tools.RegisterPruneTools(server)
+		toolCount += 25
+		toolsets = append(toolsets, "generation")
+	}
+
+	// Fleet analysis tools (7 tools)
+	if filter.allows("fleet") {
+		tools.RegisterFleetTools(server)
+		tools.RegisterDependencyTools(server)
+		tools.RegisterClusterTools(server)
+		tools.RegisterExportTools(server)
+		tools.RegisterSchemaVersionTools(server)
+		tools.RegisterMergeTools(server)
+		tools.RegisterSplitTools(server)
+		toolCount += 7
+		toolsets = append(toolsets, "fleet")
+	}
+
+	// Config memory and audit history tools (2 tools, opt-in storage)
+	if filter.allows("memory") {
+		tools.RegisterSimilarConfigTools(server)
+		tools.RegisterAuditHistoryTools(server)
 		toolCount += 2
+		toolsets = append(toolsets, "memory")
 	}
 
-	// Phase 1: Feature detection tools (2 tools)
-	if err := tools.RegisterFeatureTools(server); err != nil {
-		return fmt.Errorf("failed to register feature tools: %w", err)
+	// Backend, plugin, log and traffic diagnostics tools (8 tools)
+	if filter.allows("diagnostics") {
+		tools.RegisterProbeTools(server)
+		tools.RegisterCertificateTools(server)
+		tools.RegisterResolveTools(server)
+		tools.RegisterLogTools(server)
+		tools.RegisterCanaryTools(server)
+		tools.RegisterRateLimitSimulationTools(server)
+		tools.RegisterPluginTools(server)
+		toolCount += 8
+		toolsets = append(toolsets, "diagnostics")
 	}
-	toolCount += 2
 
-	log.Printf("✓ All tools registered: %d tools (validation + runtime + features + doc search)", toolCount)
+	// Live gateway introspection through the EE admin API (1 tool)
+	if filter.allows("live") {
+		tools.RegisterAdminTools(server)
+		toolCount++
+		toolsets = append(toolsets, "live")
+	}
+
+	// Server configuration, metrics and capability discovery (3 tools, always registered,
+	// capabilities last to report every other tool)
+	tools.RegisterServerConfigTools(server, settings.resolved)
+	tools.RegisterServerStatsTools(server)
+	tools.RegisterCapabilityTools(server, tools.ServerInfo{
+		Name:     serverName,
+		Version:  version,
+		Toolsets: toolsets,
+	})
+	toolCount += 3
+
+	slog.Info("All tools registered", "tools", toolCount, "toolsets", strings.Join(toolsets, ","))
 	return nil
 }
 
 // registerResources registers all MCP resources
 func registerResources(server *mcp.Server) error {
-	// TODO: Register schema resources
-	// TODO: Register feature catalog resources
-	// TODO: Register examples resources
-	// TODO: Register best practices resources
-	// TODO: Register migration guides resources
-
-	log.Printf("Resources registered: 0 (TODO - implementation pending)")
+	tools.RegisterResources(server)
+	tools.NotifyDocResourceUpdates(server)
 	return nil
 }
 
@@ -203,6 +373,6 @@ func registerPrompts(server *mcp.Server) error {
 	// TODO: Register optimization prompts
 	// TODO: Register security audit prompts
 
-	log.Printf("Prompts registered: 0 (TODO - implementation pending)")
+	slog.Info("Prompts registered", "prompts", 0)
 	return nil
 }
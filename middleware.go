@@ -0,0 +1,66 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"strconv"
+	"sync"
+
+	"github.com/krakend/mcp-server/internal/toolkit"
+	"github.com/krakend/mcp-server/tools"
+)
+
+// configureMiddlewares installs the tool call middlewares selected through the settings and the
+// environment:
+//   - denied: tools that are rejected (--deny-tools or KRAKEND_MCP_DENY_TOOLS)
+//   - withMetrics: tool calls are counted for get_server_stats and /metrics (--metrics or KRAKEND_MCP_METRICS)
+//   - KRAKEND_MCP_RATE_LIMIT: maximum calls per minute of each tool
+//   - KRAKEND_MCP_AUDIT_LOG: JSON Lines file receiving an audit event per call (arguments redacted)
+func configureMiddlewares(denied []string, withMetrics bool) error {
+	toolkit.Use(toolkit.Logging())
+
+	if withMetrics {
+		// Before the policies: rejected and rate limited calls are counted too
+		toolkit.Use(tools.EnableMetrics())
+		slog.Info("Collecting server metrics")
+	}
+
+	if len(denied) > 0 {
+		toolkit.Use(toolkit.Policy(func(call *toolkit.ToolCall) error {
+			if slices.Contains(denied, call.Tool) {
+				return fmt.Errorf("disabled by the server administrator")
+			}
+			return nil
+		}))
+	}
+
+	if limit := os.Getenv("KRAKEND_MCP_RATE_LIMIT"); limit != "" {
+		perMinute, err := strconv.Atoi(limit)
+		if err != nil || perMinute <= 0 {
+			return fmt.Errorf("KRAKEND_MCP_RATE_LIMIT must be a positive number of calls per minute, got %q", limit)
+		}
+		toolkit.Use(toolkit.RateLimit(perMinute))
+	}
+
+	if path := os.Getenv("KRAKEND_MCP_AUDIT_LOG"); path != "" {
+		f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+		if err != nil {
+			return fmt.Errorf("failed to open audit log: %w", err)
+		}
+		var mu sync.Mutex
+		enc := json.NewEncoder(f)
+		toolkit.Use(toolkit.Audit(func(event toolkit.AuditEvent) {
+			mu.Lock()
+			defer mu.Unlock()
+			if err := enc.Encode(event); err != nil {
+				slog.Warn("Could not write audit event", "error", err)
+			}
+		}, tools.RedactJSON))
+		slog.Info("Auditing tool calls", "path", path)
+	}
+
+	return nil
+}
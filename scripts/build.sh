@@ -45,6 +45,7 @@ PROJECT_ROOT="$(dirname "$SCRIPT_DIR")"
 DOCS_DIR="$PROJECT_ROOT/tools/data/docs"
 SEARCH_DIR="$PROJECT_ROOT/tools/data/search"
 FEATURES_DIR="$PROJECT_ROOT/tools/data/features"
+SCHEMAS_DIR="$PROJECT_ROOT/tools/data/schemas"
 BUILD_DIR="$PROJECT_ROOT/build"
 
 # URLs
@@ -80,12 +81,13 @@ for arg in "$@"; do
     esac
 done
 
-# Step 1: Prepare feature matrix and documentation
-log "Step 1: Preparing feature matrix and documentation for embedding..."
+# Step 1: Prepare feature matrix, JSON schemas and documentation
+log "Step 1: Preparing feature matrix, JSON schemas and documentation for embedding..."
 
 mkdir -p "$DOCS_DIR"
 mkdir -p "$SEARCH_DIR"
 mkdir -p "$FEATURES_DIR"
+mkdir -p "$SCHEMAS_DIR"
 
 # Download feature matrix
 log "Downloading feature matrix from $FEATURE_MATRIX_URL..."
@@ -103,6 +105,15 @@ else
     log_warning "Failed to download feature matrix — binary will fetch it at runtime"
 fi
 
+# Download JSON schemas (latest and every version, CE and EE) for offline schema validation
+log "Downloading JSON schemas..."
+
+if (cd "$PROJECT_ROOT" && go run ./cmd/schemabundle "$SCHEMAS_DIR"); then
+    log_success "JSON schemas downloaded ($(du -sh "$SCHEMAS_DIR" | cut -f1))"
+else
+    log_warning "Failed to download JSON schemas — binary will download them at runtime"
+fi
+
 # Download documentation
 log "Downloading documentation from $DOCS_URL..."
 
@@ -123,10 +134,11 @@ fi
 DOC_SIZE=$(wc -c < "$DOCS_DIR/llms-full.txt" | tr -d ' ')
 log_success "Documentation downloaded ($DOC_SIZE bytes)"
 
-# Create cache metadata
+# Create cache metadata: the snapshot date is reported by search results and get_capabilities
+DOCS_SNAPSHOT_DATE=$(date -u +"%Y-%m-%dT%H:%M:%SZ")
 cat > "$DOCS_DIR/cache.meta" <<EOF
 {
-  "downloaded_at": "$(date -u +"%Y-%m-%dT%H:%M:%SZ")",
+  "downloaded_at": "$DOCS_SNAPSHOT_DATE",
   "source_url": "$DOCS_URL",
   "size_bytes": $DOC_SIZE,
   "embedded": true
@@ -159,7 +171,7 @@ sleep 2
 # Clean up indexer binary
 rm -f "$PROJECT_ROOT/cmd/indexer/indexer"
 
-log_success "Documentation indexed with optimized chunking (v2 schema)"
+log_success "Documentation indexed with page-scoped chunks and page manifest"
 
 # Step 3: Build binary
 log "Step 3: Building binary..."
@@ -257,8 +269,9 @@ ls -lh "$BUILD_DIR"/ | grep krakend-mcp
 echo ""
 log "Data embedded:"
 [ -f "$FEATURES_DIR/mcp-feature-matrix.yaml" ] && echo "  - Feature matrix: $(wc -c < "$FEATURES_DIR/mcp-feature-matrix.yaml" | tr -d ' ') bytes" || echo "  - Feature matrix: not embedded (will fetch at runtime)"
-echo "  - KrakenD docs: $DOC_SIZE bytes"
+echo "  - KrakenD docs: $DOC_SIZE bytes (snapshot of $DOCS_SNAPSHOT_DATE)"
 echo "  - Search index: $(du -sh "$SEARCH_DIR/index" | cut -f1)"
+echo "  - JSON schemas: $(find "$SCHEMAS_DIR" -name krakend.json | wc -l | tr -d ' ') root schemas ($(du -sh "$SCHEMAS_DIR" | cut -f1))"
 echo ""
 if [ -f "$BUILD_DIR/checksums.txt" ]; then
     log "Checksums:"
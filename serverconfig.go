@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/krakend/mcp-server/internal/notify"
+	"github.com/krakend/mcp-server/tools"
+	"gopkg.in/yaml.v3"
+)
+
+// serverConfig is the server configuration file (YAML or JSON) selected with KRAKEND_MCP_SERVER_CONFIG
+type serverConfig struct {
+	Notifications struct {
+		Webhooks []notify.Webhook `yaml:"webhooks"`
+	} `yaml:"notifications"`
+	Schedules []tools.AuditSchedule `yaml:"schedules"`
+}
+
+// loadServerConfig reads the server configuration file, returning an empty configuration when
+// no file is given
+func loadServerConfig(path string) (*serverConfig, error) {
+	config := &serverConfig{}
+	if path == "" {
+		return config, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if err := yaml.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("invalid server configuration %s: %w", path, err)
+	}
+	return config, nil
+}
+
+// configureNotifications dispatches server events to the webhooks of the server configuration,
+// returning nil when none is configured
+func configureNotifications(config *serverConfig) (*notify.Dispatcher, error) {
+	webhooks := config.Notifications.Webhooks
+	if len(webhooks) == 0 {
+		return nil, nil
+	}
+	dispatcher, err := notify.New(webhooks)
+	if err != nil {
+		return nil, err
+	}
+	tools.EnableNotifications(dispatcher)
+	slog.Info("Sending notifications", "webhooks", len(webhooks))
+	return dispatcher, nil
+}
+
+// configureSchedules starts the background audits of the server configuration
+func configureSchedules(ctx context.Context, config *serverConfig) error {
+	if len(config.Schedules) == 0 {
+		return nil
+	}
+	if err := tools.StartAuditScheduler(ctx, config.Schedules); err != nil {
+		return err
+	}
+	slog.Info("Running scheduled audits", "schedules", len(config.Schedules))
+	return nil
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadServerConfig(t *testing.T) {
+	config, err := loadServerConfig("")
+	if err != nil || len(config.Notifications.Webhooks) != 0 {
+		t.Fatalf("Expected an empty configuration without file, got %+v (error %v)", config, err)
+	}
+
+	path := filepath.Join(t.TempDir(), "server.yaml")
+	content := "notifications:\n  webhooks:\n    - url: https://hooks.example.com/a\n      format: slack\n      events: [docs_refreshed]\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	config, err = loadServerConfig(path)
+	if err != nil {
+		t.Fatalf("loadServerConfig() error = %v", err)
+	}
+	hooks := config.Notifications.Webhooks
+	if len(hooks) != 1 || hooks[0].Format != "slack" || hooks[0].Events[0] != "docs_refreshed" {
+		t.Errorf("Unexpected webhooks: %+v", hooks)
+	}
+
+	schedulePath := filepath.Join(t.TempDir(), "schedules.yaml")
+	content = "schedules:\n  - name: nightly\n    config: krakend.json\n    cron: \"0 3 * * *\"\n"
+	if err := os.WriteFile(schedulePath, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	config, err = loadServerConfig(schedulePath)
+	if err != nil {
+		t.Fatalf("loadServerConfig() error = %v", err)
+	}
+	if len(config.Schedules) != 1 || config.Schedules[0].Cron != "0 3 * * *" || config.Schedules[0].Config != "krakend.json" {
+		t.Errorf("Unexpected schedules: %+v", config.Schedules)
+	}
+
+	if _, err := loadServerConfig(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Error("Expected an error for a missing file")
+	}
+}
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/krakend/mcp-server/tools"
+)
+
+// Sources of a setting value, by precedence
+const (
+	sourceFlag    = "flag"
+	sourceEnv     = "env"
+	sourceDefault = "default"
+)
+
+// logLevels are the accepted --log-level values, from the most to the least verbose
+var logLevels = []string{"debug", "info", "warn", "error"}
+
+// setting declares a server setting with its flag, environment variable and default value
+type setting struct {
+	name     string
+	flag     string
+	env      string
+	fallback string
+	boolean  bool // A bare flag is true
+}
+
+// serverSettings are the settings configurable with a flag, falling back to an environment variable
+var serverSettings = []setting{
+	{"data_dir", "--data-dir", "KRAKEND_MCP_DATA_DIR", "", false},
+	{"docs_url", "--docs-url", "KRAKEND_MCP_DOCS_URL", tools.DefaultDocsURL, false},
+	{"cache_ttl", "--cache-ttl", "KRAKEND_MCP_CACHE_TTL", tools.DefaultCacheTTL.String(), false},
+	{"validation_timeout", "--validation-timeout", "KRAKEND_MCP_VALIDATION_TIMEOUT", tools.DefaultValidationTimeout.String(), false},
+	{"docker_registry", "--docker-registry", "KRAKEND_MCP_DOCKER_REGISTRY", "", false},
+	{"strict", "--strict", "KRAKEND_MCP_STRICT", "false", true},
+	{"log_level", "--log-level", "KRAKEND_MCP_LOG_LEVEL", "info", false},
+	{"log_format", "--log-format", "KRAKEND_MCP_LOG_FORMAT", "text", false},
+	{"deny_tools", "--deny-tools", "KRAKEND_MCP_DENY_TOOLS", "", false},
+	{"metrics", "--metrics", "KRAKEND_MCP_METRICS", "false", true},
+	{"toolsets", "--toolsets", "KRAKEND_MCP_TOOLSETS", "", false},
+	{"disable_toolsets", "--disable-toolsets", "KRAKEND_MCP_DISABLE_TOOLSETS", "", false},
+	{"transport", "--transport", "KRAKEND_MCP_TRANSPORT", transportStdio, false},
+	{"listen", "--listen", "KRAKEND_MCP_LISTEN", ":" + defaultHttpPort, false},
+}
+
+// lookupSetting returns the value of a flag (--name=value or --name value), falling back to the
+// environment variable, and where it came from. It returns an empty value without a flag or variable.
+func lookupSetting(args []string, flag, env string) (value, source string, err error) {
+	return lookupFlag(args, flag, env, false)
+}
+
+// lookupBoolSetting is lookupSetting for boolean switches: a bare --name is true and never takes
+// the next argument as its value, --name=false turns it off
+func lookupBoolSetting(args []string, flag, env string) (value, source string, err error) {
+	return lookupFlag(args, flag, env, true)
+}
+
+// lookupFlag looks a flag up, reading the value of a bare value flag from the next argument
+func lookupFlag(args []string, flag, env string, boolean bool) (value, source string, err error) {
+	for i := 0; i < len(args); i++ {
+		name, v, hasValue := strings.Cut(args[i], "=")
+		if name != flag {
+			continue
+		}
+		switch {
+		case !hasValue && boolean:
+			v = "true"
+		case !hasValue:
+			if i+1 >= len(args) {
+				return "", "", fmt.Errorf("%s requires a value", flag)
+			}
+			i++
+			v = args[i]
+		}
+		value, source = v, sourceFlag
+	}
+	if source == sourceFlag {
+		return value, source, nil
+	}
+	if v := os.Getenv(env); v != "" {
+		return v, sourceEnv, nil
+	}
+	return "", sourceDefault, nil
+}
+
+// settings are the resolved server settings
+type settings struct {
+	dataDir           string
+	docsURL           string
+	cacheTTL          time.Duration
+	validationTimeout time.Duration // 0 disables it
+	dockerRegistry    string
+	strict            bool // Default of the strict parameter of the validation tools
+	logLevel          string
+	logFormat         string
+	denyTools         []string
+	metrics           bool
+	resolved          []tools.ServerSetting // Every setting with its source, reported by get_server_config
+}
+
+// parseSettings resolves the server settings from the command line flags and the environment
+func parseSettings(args []string) (*settings, error) {
+	s := &settings{}
+	values := map[string]string{}
+	for _, def := range serverSettings {
+		lookup := lookupSetting
+		if def.boolean {
+			lookup = lookupBoolSetting
+		}
+		value, source, err := lookup(args, def.flag, def.env)
+		if err != nil {
+			return nil, err
+		}
+		if source == sourceDefault {
+			value = def.fallback
+		}
+		values[def.name] = value
+		s.resolved = append(s.resolved, tools.ServerSetting{Name: def.name, Value: value, Source: source, Flag: def.flag, Env: def.env})
+	}
+
+	s.dataDir = values["data_dir"]
+	s.docsURL = values["docs_url"]
+	if !strings.HasPrefix(s.docsURL, "http://") && !strings.HasPrefix(s.docsURL, "https://") {
+		return nil, fmt.Errorf("docs URL must be an http(s) URL, got %q", s.docsURL)
+	}
+	ttl, err := time.ParseDuration(values["cache_ttl"])
+	if err != nil || ttl <= 0 {
+		return nil, fmt.Errorf("cache TTL must be a positive duration such as 24h, got %q", values["cache_ttl"])
+	}
+	s.cacheTTL = ttl
+	timeout, err := time.ParseDuration(values["validation_timeout"])
+	if err != nil || timeout < 0 {
+		return nil, fmt.Errorf("validation timeout must be a duration such as 2m, or 0 to disable it, got %q", values["validation_timeout"])
+	}
+	s.validationTimeout = timeout
+	s.dockerRegistry = values["docker_registry"]
+	if s.strict, err = strconv.ParseBool(values["strict"]); err != nil {
+		return nil, fmt.Errorf("strict must be true or false, got %q", values["strict"])
+	}
+	s.logLevel = strings.ToLower(values["log_level"])
+	if !slices.Contains(logLevels, s.logLevel) {
+		return nil, fmt.Errorf("unknown log level %q (expected %s)", values["log_level"], strings.Join(logLevels, ", "))
+	}
+	s.logFormat = strings.ToLower(values["log_format"])
+	if !slices.Contains(logFormats, s.logFormat) {
+		return nil, fmt.Errorf("unknown log format %q (expected %s)", values["log_format"], strings.Join(logFormats, ", "))
+	}
+	for _, name := range strings.Split(values["deny_tools"], ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			s.denyTools = append(s.denyTools, name)
+		}
+	}
+	if s.metrics, err = strconv.ParseBool(values["metrics"]); err != nil {
+		return nil, fmt.Errorf("metrics must be true or false, got %q", values["metrics"])
+	}
+	return s, nil
+}
+
+// resolve records the effective value of a setting resolved elsewhere, e.g. the listen address
+func (s *settings) resolve(name, value string) {
+	for i := range s.resolved {
+		if s.resolved[i].Name == name {
+			s.resolved[i].Value = value
+		}
+	}
+}
+
+// apply configures the server with the settings
+func (s *settings) apply() error {
+	setupLogging(s.logLevel, s.logFormat)
+	return s.configure()
+}
+
+// configure applies the settings of the tools, leaving logging as it is
+func (s *settings) configure() error {
+	// KRAKEND_MCP_DATA_DIR is already in use: only a different directory is switched to
+	if abs, _ := filepath.Abs(s.dataDir); s.dataDir != "" && abs != tools.DataDir() {
+		if err := tools.SetDataDir(s.dataDir); err != nil {
+			return fmt.Errorf("invalid data directory %s: %w", s.dataDir, err)
+		}
+	}
+	tools.ConfigureDocs(s.docsURL, s.cacheTTL)
+	tools.ConfigureValidationTimeout(s.validationTimeout)
+	tools.ConfigureStrictValidation(s.strict)
+	tools.ConfigureDockerRegistry(s.dockerRegistry)
+	return nil
+}
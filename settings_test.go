@@ -0,0 +1,137 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseSettings(t *testing.T) {
+	for _, def := range serverSettings {
+		t.Setenv(def.env, "")
+	}
+	t.Setenv("KRAKEND_MCP_CACHE_TTL", "24h")
+	t.Setenv("KRAKEND_MCP_LOG_LEVEL", "error")
+
+	s, err := parseSettings([]string{"--log-level", "WARN", "--docs-url=https://mirror.example.com/llms.txt", "--deny-tools=probe_backends, resolve_backends"})
+	if err != nil {
+		t.Fatalf("parseSettings() error = %v", err)
+	}
+	if s.cacheTTL != 24*time.Hour || s.logLevel != "warn" || s.docsURL != "https://mirror.example.com/llms.txt" {
+		t.Errorf("Unexpected settings: %+v", s)
+	}
+	if s.metrics {
+		t.Error("Metrics must be disabled by default")
+	}
+	if s.strict {
+		t.Error("Strict validation must be disabled by default")
+	}
+	if len(s.denyTools) != 2 || s.denyTools[1] != "resolve_backends" {
+		t.Errorf("Unexpected denied tools: %v", s.denyTools)
+	}
+
+	sources := map[string]string{}
+	for _, r := range s.resolved {
+		sources[r.Name] = r.Source + "=" + r.Value
+	}
+	for name, want := range map[string]string{
+		"log_level":          "flag=WARN",
+		"cache_ttl":          "env=24h",
+		"transport":          "default=stdio",
+		"validation_timeout": "default=2m0s",
+	} {
+		if sources[name] != want {
+			t.Errorf("Setting %s = %s, want %s", name, sources[name], want)
+		}
+	}
+
+	s.resolve("listen", ":9000")
+	for _, r := range s.resolved {
+		if r.Name == "listen" && r.Value != ":9000" {
+			t.Errorf("Expected the resolved listen address, got %+v", r)
+		}
+	}
+}
+
+func TestParseSettings_Invalid(t *testing.T) {
+	for _, def := range serverSettings {
+		t.Setenv(def.env, "")
+	}
+	for _, args := range [][]string{
+		{"--cache-ttl=7"},
+		{"--cache-ttl=-1h"},
+		{"--log-level=verbose"},
+		{"--log-format=xml"},
+		{"--docs-url=file:///docs.txt"},
+		{"--data-dir"},
+		{"--metrics=sometimes"},
+		{"--strict=maybe"},
+		{"--validation-timeout=-1s"},
+		{"--validation-timeout=soon"},
+	} {
+		if _, err := parseSettings(args); err == nil {
+			t.Errorf("parseSettings(%v) expected an error", args)
+		}
+	}
+}
+
+func TestParseSettings_BooleanFlags(t *testing.T) {
+	for _, def := range serverSettings {
+		t.Setenv(def.env, "")
+	}
+	tests := []struct {
+		args []string
+		want bool
+	}{
+		{[]string{"--strict"}, true},
+		{[]string{"--strict", "--version"}, true},
+		{[]string{"--strict", "--log-level", "debug"}, true},
+		{[]string{"--strict=true"}, true},
+		{[]string{"--strict=false"}, false},
+		{nil, false},
+	}
+	for _, tt := range tests {
+		s, err := parseSettings(tt.args)
+		if err != nil {
+			t.Errorf("parseSettings(%v) error = %v", tt.args, err)
+			continue
+		}
+		if s.strict != tt.want {
+			t.Errorf("parseSettings(%v) strict = %v, want %v", tt.args, s.strict, tt.want)
+		}
+	}
+
+	s, err := parseSettings([]string{"--metrics", "--strict"})
+	if err != nil || !s.metrics || !s.strict {
+		t.Errorf("Expected bare --metrics and --strict enabled, got %+v (%v)", s, err)
+	}
+
+	t.Setenv("KRAKEND_MCP_STRICT", "true")
+	if s, err := parseSettings([]string{"--strict=false"}); err != nil || s.strict {
+		t.Errorf("The flag must win over the environment, got %+v (%v)", s, err)
+	}
+}
+
+func TestNewLogHandler(t *testing.T) {
+	var out bytes.Buffer
+	logger := slog.New(newLogHandler(&out, "warn", "json"))
+	logger.Info("Data directory", "path", "/data")
+	logger.Warn("Could not download plugin catalog", "error", "timeout")
+
+	var entry map[string]any
+	if err := json.Unmarshal(out.Bytes(), &entry); err != nil {
+		t.Fatalf("Expected a single JSON line, got %q: %v", out.String(), err)
+	}
+	if entry["level"] != "WARN" || entry["msg"] != "Could not download plugin catalog" || entry["error"] != "timeout" {
+		t.Errorf("Unexpected log entry: %v", entry)
+	}
+
+	out.Reset()
+	slog.New(newLogHandler(&out, "debug", "text")).Debug("Index copied", "duration_ms", 12)
+	if !strings.Contains(out.String(), `level=DEBUG msg="Index copied" duration_ms=12`) {
+		t.Errorf("Unexpected text log: %s", out.String())
+	}
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/krakend/mcp-server/tools"
+)
+
+// runSetupCommand implements the "setup" subcommand and returns the process exit code
+func runSetupCommand(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("setup", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	dataDir := fs.String("data-dir", "", "data directory to initialize (default: current data directory)")
+	versions := fs.String("versions", "latest", "comma-separated KrakenD versions to prepare schemas (and images) for")
+	edition := fs.String("edition", "ce", "edition to prepare: ce, ee or both")
+	pullImages := fs.Bool("pull-images", false, "pre-pull the KrakenD Docker images")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	input := tools.SetupInput{
+		DataDir:    *dataDir,
+		Edition:    *edition,
+		PullImages: *pullImages,
+	}
+	for _, v := range strings.Split(*versions, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			input.Versions = append(input.Versions, v)
+		}
+	}
+
+	output, err := tools.RunSetup(ctx, input)
+	if err != nil {
+		fmt.Fprintf(stderr, "setup: %v\n", err)
+		return 2
+	}
+
+	fmt.Fprintf(stdout, "Data directory: %s\n\n", output.DataDir)
+	for _, s := range output.Steps {
+		fmt.Fprintf(stdout, "%-8s %-16s %s\n", strings.ToUpper(s.Status), s.Name, s.Detail)
+	}
+	fmt.Fprintf(stdout, "\n%s\n", output.Summary)
+
+	tools.CloseDocSearch()
+	if !output.Ready {
+		return 1
+	}
+	return 0
+}
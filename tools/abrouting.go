@@ -0,0 +1,419 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/krakend/mcp-server/internal/toolkit"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	conditionalNamespace  = "backend/conditional"
+	defaultVariantHeader  = "X-Variant"
+	abStrategyConditional = "conditional_backends"
+	abStrategyEndpoints   = "separate_endpoints"
+	// abRoutingLuaMarker identifies the Lua snippet generated by generate_ab_routing
+	abRoutingLuaMarker = "-- krakend-mcp: ab routing"
+)
+
+var (
+	variantNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+	luaPatternSpecials = regexp.MustCompile(`[%^$().\[\]*+\-?]`)
+)
+
+// ABVariant is a variant of an A/B test
+type ABVariant struct {
+	Name       string `json:"name" jsonschema:"Variant name, e.g. control or b (lowercase letters, digits and dashes). The first variant is the control"`
+	Value      string `json:"value,omitempty" jsonschema:"Header or cookie value selecting the variant (optional, defaults to the name)"`
+	Host       string `json:"host,omitempty" jsonschema:"Backend host of the variant, e.g. http://orders-v2:8080 (optional, defaults to the host of the endpoint's backend)"`
+	URLPattern string `json:"url_pattern,omitempty" jsonschema:"Backend url_pattern of the variant (optional, defaults to the url_pattern of the endpoint's backend)"`
+	Weight     int    `json:"weight,omitempty" jsonschema:"Share of the traffic without a variant, e.g. 90 and 10 (optional: without weights those requests go to the control)"`
+}
+
+// GenerateABRoutingInput defines input for generate_ab_routing tool
+type GenerateABRoutingInput struct {
+	Config         string      `json:"config" jsonschema:"KrakenD configuration as JSON string or file path"`
+	ConfigEncoding string      `json:"config_encoding,omitempty" jsonschema:"Encoding of config for large configurations: base64 or gzip+base64 (optional, defaults to a plain JSON string or file path)"`
+	Variants       []ABVariant `json:"variants" jsonschema:"Variants to route to, the control first (at least two)"`
+	Endpoints      []string    `json:"endpoints,omitempty" jsonschema:"Endpoint paths to split (optional, defaults to every endpoint)"`
+	Header         string      `json:"header,omitempty" jsonschema:"Header selecting the variant (optional, defaults to X-Variant)"`
+	Cookie         string      `json:"cookie,omitempty" jsonschema:"Cookie selecting the variant when the header is missing, e.g. ab_variant (optional)"`
+	BucketBy       string      `json:"bucket_by,omitempty" jsonschema:"Header identifying the user, e.g. X-User-Id, so weighted assignment is sticky (optional, random per request otherwise)"`
+	Edition        string      `json:"edition,omitempty" jsonschema:"Target edition: ee for conditional backends, ce for separate endpoints (optional, detected from the config)"`
+}
+
+// GenerateABRoutingOutput defines output for generate_ab_routing tool
+type GenerateABRoutingOutput struct {
+	Edition     string                 `json:"edition"`
+	Strategy    string                 `json:"strategy"` // conditional_backends or separate_endpoints
+	Header      string                 `json:"header"`
+	Config      map[string]interface{} `json:"config"`
+	Changes     []string               `json:"changes"` // JSON paths modified
+	Routes      map[string][]string    `json:"routes"`  // How to reach each variant
+	Warnings    []string               `json:"warnings,omitempty"`
+	Limitations []string               `json:"limitations"` // What the strategy cannot do
+	Notes       []string               `json:"notes"`
+	Summary     string                 `json:"summary"`
+}
+
+// validateABVariants checks the variants and fills their default values
+func validateABVariants(variants []ABVariant) ([]ABVariant, error) {
+	if len(variants) < 2 {
+		return nil, fmt.Errorf("at least two variants are required, the control first")
+	}
+	variants = slices.Clone(variants)
+	var names, values []string
+	weighted := 0
+	for i := range variants {
+		v := &variants[i]
+		if !variantNamePattern.MatchString(v.Name) {
+			return nil, fmt.Errorf("invalid variant name %q: use lowercase letters, digits and dashes", v.Name)
+		}
+		v.Value = envOrDefault(v.Value, v.Name)
+		if slices.Contains(names, v.Name) || slices.Contains(values, v.Value) {
+			return nil, fmt.Errorf("variant %q is defined twice", v.Name)
+		}
+		names = append(names, v.Name)
+		values = append(values, v.Value)
+		if v.Weight < 0 {
+			return nil, fmt.Errorf("variant %q has a negative weight", v.Name)
+		}
+		if v.Weight > 0 {
+			weighted++
+		}
+		if i > 0 && v.Host == "" && v.URLPattern == "" {
+			return nil, fmt.Errorf("variant %q needs a host or a url_pattern to differ from the control", v.Name)
+		}
+	}
+	if weighted > 0 && weighted < len(variants) {
+		return nil, fmt.Errorf("either every variant or none has a weight")
+	}
+	return variants, nil
+}
+
+// abRoutingLua assigns a variant to the requests without one: from the cookie first, then by
+// weight, hashing the bucket_by header so a user keeps the same variant
+func abRoutingLua(variants []ABVariant, header, cookie, bucketBy string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s\nlocal r = request.load()\nlocal variant = r:headers(%q)\n", abRoutingLuaMarker, header)
+	if cookie != "" {
+		pattern := luaPatternSpecials.ReplaceAllString(cookie, "%$0") + "=([^;]+)"
+		fmt.Fprintf(&b, "if variant == nil or variant == \"\" then\n  variant = string.match(r:headers(\"Cookie\") or \"\", %q)\nend\n", pattern)
+	}
+	total := 0
+	for _, v := range variants {
+		total += v.Weight
+	}
+	if total > 0 {
+		b.WriteString("if variant == nil or variant == \"\" then\n  local bucket\n")
+		if bucketBy != "" {
+			fmt.Fprintf(&b, "  local id = r:headers(%q)\n  if id ~= nil and id ~= \"\" then\n    bucket = 0\n    for i = 1, #id do bucket = (bucket * 31 + string.byte(id, i)) %% %d end\n  end\n", bucketBy, total)
+		}
+		fmt.Fprintf(&b, "  if bucket == nil then bucket = math.random(0, %d) end\n", total-1)
+		threshold := 0
+		for i, v := range variants {
+			threshold += v.Weight
+			switch {
+			case i == 0:
+				fmt.Fprintf(&b, "  if bucket < %d then variant = %q\n", threshold, v.Value)
+			case i == len(variants)-1:
+				fmt.Fprintf(&b, "  else variant = %q end\n", v.Value)
+			default:
+				fmt.Fprintf(&b, "  elseif bucket < %d then variant = %q\n", threshold, v.Value)
+			}
+		}
+		b.WriteString("end\n")
+	}
+	fmt.Fprintf(&b, "if variant ~= nil and variant ~= \"\" then r:headers(%q, variant) end", header)
+	return b.String()
+}
+
+// controlPolicy matches the requests that select no other variant
+func controlPolicy(header string, variants []ABVariant) string {
+	var others []string
+	for _, v := range variants[1:] {
+		others = append(others, fmt.Sprintf("'%s'", v.Value))
+	}
+	return fmt.Sprintf("!('%[1]s' in req_headers) || !(req_headers['%[1]s'][0] in [%[2]s])", header, strings.Join(others, ", "))
+}
+
+// variantBackend copies the endpoint backend for a variant
+func variantBackend(backend map[string]interface{}, v ABVariant) map[string]interface{} {
+	copied, _ := normalizeJSON(backend).(map[string]interface{})
+	if v.Host != "" {
+		copied["host"] = []interface{}{v.Host}
+	}
+	if v.URLPattern != "" {
+		copied["url_pattern"] = v.URLPattern
+	}
+	return copied
+}
+
+// weightedHosts repeats the hosts of the variants in proportion to their weights, so the
+// round-robin load balancer splits the traffic
+func weightedHosts(variants []ABVariant, fallback []interface{}) []interface{} {
+	divisor := 0
+	for _, v := range variants {
+		divisor = gcd(divisor, v.Weight)
+	}
+	var hosts []interface{}
+	for _, v := range variants {
+		host := interface{}(v.Host)
+		if v.Host == "" && len(fallback) > 0 {
+			host = fallback[0]
+		}
+		for range v.Weight / divisor {
+			hosts = append(hosts, host)
+		}
+	}
+	return hosts
+}
+
+func gcd(a, b int) int {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+// GenerateABRouting splits endpoints between variants selected by a header or a cookie: with
+// Enterprise conditional backends on the same endpoint, or with one endpoint per variant in the
+// Community Edition
+func GenerateABRouting(ctx context.Context, req *mcp.CallToolRequest, input GenerateABRoutingInput) (*mcp.CallToolResult, GenerateABRoutingOutput, error) {
+	variants, err := validateABVariants(input.Variants)
+	if err != nil {
+		return nil, GenerateABRoutingOutput{}, err
+	}
+	configContent, err := readEncodedConfig(input.Config, input.ConfigEncoding)
+	if err != nil {
+		return nil, GenerateABRoutingOutput{}, fmt.Errorf("failed to read config: %w", err)
+	}
+	var config map[string]interface{}
+	if err := json.Unmarshal([]byte(configContent), &config); err != nil {
+		return nil, GenerateABRoutingOutput{}, fmt.Errorf("invalid JSON: %w", err)
+	}
+	edition := input.Edition
+	if edition == "" {
+		edition = "ce"
+		if DetectEnterpriseFeatures(configContent) {
+			edition = "ee"
+		}
+	}
+	if edition != "ce" && edition != "ee" {
+		return nil, GenerateABRoutingOutput{}, fmt.Errorf("unknown edition %q (expected ce or ee)", edition)
+	}
+	header := http.CanonicalHeaderKey(envOrDefault(input.Header, defaultVariantHeader))
+	weighted := variants[0].Weight > 0
+
+	output := GenerateABRoutingOutput{Edition: edition, Header: header, Config: config, Changes: []string{}, Routes: map[string][]string{}}
+	if edition == "ee" {
+		output.Strategy = abStrategyConditional
+		output.Limitations = []string{
+			fmt.Sprintf("Clients can pick their variant by sending %s: convenient for QA, but do not use it to gate access.", header),
+			"Variants are chosen per request: the gateway does not set a cookie, so without bucket_by or a cookie set by your application a user may see different variants.",
+			"Each endpoint keeps a single response: a variant is a replacement backend, so aggregated endpoints (several backends) are not split.",
+		}
+	} else {
+		output.Strategy = abStrategyEndpoints
+		output.Limitations = []string{
+			fmt.Sprintf("The Community Edition cannot route on headers or cookies: each variant gets its own endpoint, and %s must be turned into a path by the load balancer or CDN in front of KrakenD.", header),
+			"Separate endpoints duplicate their settings: later changes must be applied to every copy.",
+		}
+		if weighted {
+			output.Limitations = append(output.Limitations,
+				"Weights are applied by repeating the variant hosts in the backend host list: the round-robin split is per request and not sticky, and it only works when the variants share the url_pattern.")
+		}
+	}
+	if edition == "ce" && input.Cookie != "" {
+		output.Warnings = append(output.Warnings, "The cookie is ignored in the Community Edition: the load balancer in front of KrakenD must read it")
+	}
+	if edition == "ce" && weighted && slices.ContainsFunc(variants, func(v ABVariant) bool { return v.URLPattern != "" }) {
+		output.Warnings = append(output.Warnings, "Weighted host lists keep the url_pattern of the control: variants with their own url_pattern only get it on their separate endpoints")
+	}
+
+	endpoints, _ := config["endpoints"].([]interface{})
+	declared := map[string]bool{}
+	for _, ep := range endpoints {
+		endpoint, _ := ep.(map[string]interface{})
+		path, _ := endpoint["endpoint"].(string)
+		declared[endpointKey(endpointMethod(endpoint), path)] = true
+	}
+	// variantCopy reports whether an endpoint is the copy of another for a variant, e.g. /b/orders
+	variantCopy := func(method, path string) bool {
+		for _, v := range variants[1:] {
+			if original, ok := strings.CutPrefix(path, "/"+v.Name); ok && declared[endpointKey(method, original)] {
+				return true
+			}
+		}
+		return false
+	}
+	var added []interface{}
+	var skipped []string
+	matched, split := 0, 0
+	for i, ep := range endpoints {
+		endpoint, ok := ep.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		path, _ := endpoint["endpoint"].(string)
+		if len(input.Endpoints) > 0 && !slices.Contains(input.Endpoints, path) {
+			continue
+		}
+		matched++
+		label := endpointKey(endpointMethod(endpoint), path)
+		backends, _ := endpoint["backend"].([]interface{})
+		if len(backends) == 0 {
+			continue
+		}
+		backend, ok := backends[0].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		backendExtra, _ := backend["extra_config"].(map[string]interface{})
+		if _, exists := backendExtra[conditionalNamespace]; exists {
+			output.Warnings = append(output.Warnings, fmt.Sprintf("%s already has conditional backends and was left unchanged", label))
+			continue
+		}
+		if len(backends) > 1 {
+			skipped = append(skipped, label)
+			continue
+		}
+		method := endpointMethod(endpoint)
+		if edition == "ce" && variantCopy(method, path) {
+			continue
+		}
+		if edition == "ce" && declared[endpointKey(method, "/"+variants[1].Name+path)] {
+			output.Warnings = append(output.Warnings, fmt.Sprintf("%s already has variant endpoints and was left unchanged", label))
+			continue
+		}
+		split++
+
+		if edition == "ce" {
+			hosts, _ := backend["host"].([]interface{})
+			for _, v := range variants[1:] {
+				variant, _ := normalizeJSON(endpoint).(map[string]interface{})
+				variant["endpoint"] = "/" + v.Name + path
+				variant["backend"] = []interface{}{variantBackend(backend, v)}
+				added = append(added, variant)
+				output.Routes[v.Name] = append(output.Routes[v.Name], method+" /"+v.Name+path)
+			}
+			output.Routes[variants[0].Name] = append(output.Routes[variants[0].Name], label)
+			if weighted {
+				backend["host"] = weightedHosts(variants, hosts)
+				output.Changes = append(output.Changes, fmt.Sprintf("$.endpoints[%d].backend[0].host", i))
+			}
+			continue
+		}
+
+		// Enterprise: one conditional backend per variant, the control catching the rest
+		conditional := make([]interface{}, 0, len(variants))
+		for j, v := range variants {
+			copied := variantBackend(backend, v)
+			extra, _ := copied["extra_config"].(map[string]interface{})
+			if extra == nil {
+				extra = map[string]interface{}{}
+				copied["extra_config"] = extra
+			}
+			condition := map[string]interface{}{"strategy": "header", "name": header, "value": v.Value}
+			if j == 0 {
+				condition = map[string]interface{}{"strategy": "policy", "value": controlPolicy(header, variants)}
+			}
+			extra[conditionalNamespace] = condition
+			conditional = append(conditional, copied)
+			output.Routes[v.Name] = append(output.Routes[v.Name], fmt.Sprintf("%s with %s: %s", label, header, v.Value))
+		}
+		endpoint["backend"] = conditional
+		output.Changes = append(output.Changes, fmt.Sprintf("$.endpoints[%d].backend", i))
+
+		// The variant header must reach the backends, and the cookie and bucket headers the Lua step
+		required := []string{header}
+		if input.Cookie != "" {
+			required = append(required, "Cookie")
+		}
+		if input.BucketBy != "" && weighted {
+			required = append(required, http.CanonicalHeaderKey(input.BucketBy))
+		}
+		headers, _ := endpoint["input_headers"].([]interface{})
+		forwarded := len(headers)
+		for _, h := range required {
+			if !slices.Contains(headers, interface{}("*")) && !slices.ContainsFunc(headers, func(e interface{}) bool {
+				s, _ := e.(string)
+				return strings.EqualFold(s, h)
+			}) {
+				headers = append(headers, h)
+			}
+		}
+		if len(headers) > forwarded {
+			endpoint["input_headers"] = headers
+			output.Changes = append(output.Changes, fmt.Sprintf("$.endpoints[%d].input_headers", i))
+		}
+		if input.Cookie == "" && !weighted {
+			continue
+		}
+		extra, _ := endpoint["extra_config"].(map[string]interface{})
+		if extra == nil {
+			extra = map[string]interface{}{}
+			endpoint["extra_config"] = extra
+		}
+		lua, _ := extra[luaProxyNamespace].(map[string]interface{})
+		if lua == nil {
+			lua = map[string]interface{}{}
+			extra[luaProxyNamespace] = lua
+		}
+		pre, _ := lua["pre"].(string)
+		script := abRoutingLua(variants, header, input.Cookie, input.BucketBy)
+		if pre != "" {
+			script += "\n" + pre
+		}
+		lua["pre"] = script
+		lua["allow_open_libs"] = true
+		output.Changes = append(output.Changes, fmt.Sprintf("$.endpoints[%d].extra_config['%s']", i, luaProxyNamespace))
+	}
+	if matched == 0 && len(input.Endpoints) > 0 {
+		return nil, GenerateABRoutingOutput{}, fmt.Errorf("none of the endpoints %s was found in the configuration", strings.Join(input.Endpoints, ", "))
+	}
+	if len(added) > 0 {
+		config["endpoints"] = append(endpoints, added...)
+		output.Changes = append(output.Changes, "$.endpoints")
+	}
+	if len(skipped) > 0 {
+		output.Warnings = append(output.Warnings, fmt.Sprintf("Endpoints aggregating several backends were not split: %s", strings.Join(skipped, ", ")))
+	}
+
+	if edition == "ee" {
+		output.Notes = append(output.Notes, fmt.Sprintf("Requests without %s, or with an unknown value, go to the %s variant.", header, variants[0].Name))
+		if input.Cookie != "" {
+			output.Notes = append(output.Notes, fmt.Sprintf("The %s cookie selects the variant when the header is missing: set it from your application to keep users on their variant.", input.Cookie))
+		}
+		if weighted {
+			how := "at random on every request"
+			if input.BucketBy != "" {
+				how = fmt.Sprintf("by hashing %s, so each user keeps the same variant (random when the header is missing)", input.BucketBy)
+			}
+			output.Notes = append(output.Notes, "Requests without a variant are assigned one by weight "+how+".",
+				"The Lua step needs allow_open_libs for the string and math libraries.")
+		}
+	} else {
+		output.Notes = append(output.Notes, fmt.Sprintf("Route requests with %s: <name> to /<name>/... in the load balancer, e.g. a rule rewriting the path before it reaches KrakenD.", header),
+			"Conditional backends in KrakenD Enterprise route on the header within the same endpoint, without extra endpoints.")
+	}
+	output.Summary = fmt.Sprintf("%d endpoint(s) split between %d variants with %s", split, len(variants), strings.ReplaceAll(output.Strategy, "_", " "))
+	return nil, output, nil
+}
+
+// RegisterABRoutingTools registers the A/B routing generation tool
+func RegisterABRoutingTools(server *mcp.Server) {
+	toolkit.AddTool(server,
+		&mcp.Tool{
+			Name:        "generate_ab_routing",
+			Description: "Generate A/B testing and header- or cookie-based routing from a list of variants and weights. Enterprise configurations get conditional backends (backend/conditional) with a Lua step assigning variants by weight, sticky per user with bucket_by; Community configurations get one endpoint per variant and weighted host lists. Documents the limitations of the chosen approach.",
+		},
+		GenerateABRouting,
+	)
+}
@@ -0,0 +1,132 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const abRoutingConfig = `{"version": 3, "endpoints": [
+	{"endpoint": "/orders", "input_headers": ["Authorization"], "backend": [{"host": ["http://orders:8080"], "url_pattern": "/orders"}]},
+	{"endpoint": "/dashboard", "backend": [{"host": ["http://orders:8080"], "url_pattern": "/summary"}, {"host": ["http://users:8080"], "url_pattern": "/me"}]}
+]}`
+
+func TestGenerateABRouting_Enterprise(t *testing.T) {
+	_, output, err := GenerateABRouting(context.Background(), nil, GenerateABRoutingInput{
+		Config: abRoutingConfig,
+		Variants: []ABVariant{
+			{Name: "control", Weight: 90},
+			{Name: "b", Host: "http://orders-v2:8080", Weight: 10},
+		},
+		Cookie:   "ab_variant",
+		BucketBy: "X-User-Id",
+		Edition:  "ee",
+	})
+	if err != nil {
+		t.Fatalf("GenerateABRouting() error = %v", err)
+	}
+	if output.Strategy != abStrategyConditional || output.Header != "X-Variant" {
+		t.Errorf("Unexpected strategy: %s on %s", output.Strategy, output.Header)
+	}
+
+	endpoint := output.Config["endpoints"].([]interface{})[0].(map[string]interface{})
+	backends := endpoint["backend"].([]interface{})
+	if len(backends) != 2 {
+		t.Fatalf("Expected a conditional backend per variant, got %v", backends)
+	}
+	control := backends[0].(map[string]interface{})["extra_config"].(map[string]interface{})[conditionalNamespace].(map[string]interface{})
+	if control["strategy"] != "policy" || !strings.Contains(control["value"].(string), "['b']") {
+		t.Errorf("Expected the control to catch the other values, got %v", control)
+	}
+	variant := backends[1].(map[string]interface{})
+	condition := variant["extra_config"].(map[string]interface{})[conditionalNamespace].(map[string]interface{})
+	if condition["name"] != "X-Variant" || condition["value"] != "b" || variant["host"].([]interface{})[0] != "http://orders-v2:8080" {
+		t.Errorf("Unexpected variant backend: %v", variant)
+	}
+	headers, _ := json.Marshal(endpoint["input_headers"])
+	if string(headers) != `["Authorization","X-Variant","Cookie","X-User-Id"]` {
+		t.Errorf("Unexpected input_headers: %s", headers)
+	}
+	lua := endpoint["extra_config"].(map[string]interface{})[luaProxyNamespace].(map[string]interface{})["pre"].(string)
+	for _, want := range []string{`"ab_variant=([^;]+)"`, `r:headers("X-User-Id")`, "% 100", `if bucket < 90 then variant = "control"`, `else variant = "b" end`} {
+		if !strings.Contains(lua, want) {
+			t.Errorf("Expected %q in the Lua step:\n%s", want, lua)
+		}
+	}
+	if !strings.Contains(strings.Join(output.Warnings, "\n"), "GET /dashboard") {
+		t.Errorf("Expected the aggregated endpoint to be skipped, got %v", output.Warnings)
+	}
+
+	// Running it again leaves the conditional backends alone
+	data, _ := json.Marshal(output.Config)
+	_, again, err := GenerateABRouting(context.Background(), nil, GenerateABRoutingInput{
+		Config:   string(data),
+		Variants: []ABVariant{{Name: "control"}, {Name: "b", Host: "http://orders-v2:8080"}},
+		Edition:  "ee",
+	})
+	if err != nil || len(again.Changes) != 0 {
+		t.Errorf("Expected no changes on a split configuration, got %v (%v)", again.Changes, err)
+	}
+}
+
+func TestGenerateABRouting_Community(t *testing.T) {
+	input := GenerateABRoutingInput{
+		Config: abRoutingConfig,
+		Variants: []ABVariant{
+			{Name: "a", Weight: 75},
+			{Name: "b", Host: "http://orders-v2:8080", Weight: 25},
+		},
+		Endpoints: []string{"/orders"},
+		Edition:   "ce",
+	}
+	_, output, err := GenerateABRouting(context.Background(), nil, input)
+	if err != nil {
+		t.Fatalf("GenerateABRouting() error = %v", err)
+	}
+	if output.Strategy != abStrategyEndpoints {
+		t.Errorf("Expected separate endpoints, got %s", output.Strategy)
+	}
+	endpoints := output.Config["endpoints"].([]interface{})
+	if len(endpoints) != 3 || endpoints[2].(map[string]interface{})["endpoint"] != "/b/orders" {
+		t.Fatalf("Expected a /b/orders endpoint, got %v", endpoints)
+	}
+	hosts, _ := json.Marshal(endpoints[0].(map[string]interface{})["backend"].([]interface{})[0].(map[string]interface{})["host"])
+	if string(hosts) != `["http://orders:8080","http://orders:8080","http://orders:8080","http://orders-v2:8080"]` {
+		t.Errorf("Expected hosts weighted 3 to 1, got %s", hosts)
+	}
+	if len(output.Routes["b"]) != 1 || output.Routes["b"][0] != "GET /b/orders" {
+		t.Errorf("Unexpected routes: %v", output.Routes)
+	}
+
+	// Variant endpoints are not split again
+	data, _ := json.Marshal(output.Config)
+	input.Config, input.Endpoints = string(data), nil
+	_, again, err := GenerateABRouting(context.Background(), nil, input)
+	if err != nil || len(again.Config["endpoints"].([]interface{})) != 3 {
+		t.Errorf("Expected no new endpoints, got %v (%v)", again.Changes, err)
+	}
+}
+
+func TestGenerateABRouting_Errors(t *testing.T) {
+	variants := []ABVariant{{Name: "a"}, {Name: "b", Host: "http://v2"}}
+	tests := []struct {
+		name  string
+		input GenerateABRoutingInput
+		want  string
+	}{
+		{"single variant", GenerateABRoutingInput{Config: abRoutingConfig, Variants: variants[:1]}, "at least two"},
+		{"same backend", GenerateABRoutingInput{Config: abRoutingConfig, Variants: []ABVariant{{Name: "a"}, {Name: "b"}}}, "host or a url_pattern"},
+		{"partial weights", GenerateABRoutingInput{Config: abRoutingConfig, Variants: []ABVariant{{Name: "a", Weight: 1}, variants[1]}}, "every variant"},
+		{"invalid name", GenerateABRoutingInput{Config: abRoutingConfig, Variants: []ABVariant{{Name: "A/B"}, variants[1]}}, "invalid variant name"},
+		{"unknown endpoint", GenerateABRoutingInput{Config: abRoutingConfig, Variants: variants, Endpoints: []string{"/x"}, Edition: "ee"}, "was found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := GenerateABRouting(context.Background(), nil, tt.input)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected an error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,298 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/krakend/mcp-server/internal/features"
+	"github.com/krakend/mcp-server/internal/toolkit"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	// adminURLEnv and adminTokenEnv configure the KrakenD EE admin API. The token is only read
+	// from the environment so it never travels through tool arguments.
+	adminURLEnv   = "KRAKEND_MCP_ADMIN_URL"
+	adminTokenEnv = "KRAKEND_MCP_ADMIN_TOKEN"
+
+	defaultAdminTimeout = 5 * time.Second
+	maxAdminResponse    = 16 << 20
+)
+
+// InspectLiveGatewayInput defines input for inspect_live_gateway tool
+type InspectLiveGatewayInput struct {
+	URL           string `json:"url,omitempty" jsonschema:"Base URL of the KrakenD EE admin API (optional, defaults to KRAKEND_MCP_ADMIN_URL)"`
+	Config        string `json:"config,omitempty" jsonschema:"Local KrakenD configuration (JSON string or file path) compared with the running one to detect drift (optional)"`
+	IncludeConfig bool   `json:"include_config,omitempty" jsonschema:"Include the running configuration (secrets redacted) in the output (optional)"`
+	TimeoutMs     int    `json:"timeout_ms,omitempty" jsonschema:"Timeout of each admin API request in milliseconds (optional, defaults to 5000)"`
+	HealthPath    string `json:"health_path,omitempty" jsonschema:"Path of the health endpoint (optional, defaults to /__health)"`
+	ConfigPath    string `json:"config_path,omitempty" jsonschema:"Path returning the running configuration (optional, defaults to /__config)"`
+	LicensePath   string `json:"license_path,omitempty" jsonschema:"Path returning the license information (optional, defaults to /__license)"`
+	StatsPath     string `json:"stats_path,omitempty" jsonschema:"Path returning runtime stats (optional, defaults to /__stats)"`
+}
+
+// AdminSource reports the outcome of one admin API request
+type AdminSource struct {
+	Name      string `json:"name"`
+	URL       string `json:"url"`
+	Available bool   `json:"available"`
+	Status    int    `json:"status,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// ConfigDrift lists the differences between a local configuration and the running one
+type ConfigDrift struct {
+	InSync                bool     `json:"in_sync"`
+	EndpointsOnlyLocal    []string `json:"endpoints_only_local"`   // Not deployed yet
+	EndpointsOnlyRunning  []string `json:"endpoints_only_running"` // Removed locally or changed outside source control
+	EndpointsChanged      []string `json:"endpoints_changed"`
+	NamespacesOnlyLocal   []string `json:"namespaces_only_local"`
+	NamespacesOnlyRunning []string `json:"namespaces_only_running"`
+	SettingsChanged       []string `json:"settings_changed"` // Top-level settings with different values
+}
+
+// InspectLiveGatewayOutput defines output for inspect_live_gateway tool
+type InspectLiveGatewayOutput struct {
+	URL               string                 `json:"url"`
+	Sources           []AdminSource          `json:"sources"`
+	Health            map[string]interface{} `json:"health,omitempty"`
+	License           map[string]interface{} `json:"license,omitempty"`
+	Stats             map[string]interface{} `json:"stats,omitempty"`
+	RunningEndpoints  int                    `json:"running_endpoints"`
+	RunningNamespaces []string               `json:"running_namespaces,omitempty"`
+	RunningConfig     map[string]interface{} `json:"running_config,omitempty"`
+	Drift             *ConfigDrift           `json:"drift,omitempty"`
+	Summary           string                 `json:"summary"`
+}
+
+// InspectLiveGateway fetches the running configuration, license and stats from a KrakenD EE admin API
+func InspectLiveGateway(ctx context.Context, req *mcp.CallToolRequest, input InspectLiveGatewayInput) (*mcp.CallToolResult, InspectLiveGatewayOutput, error) {
+	baseURL := input.URL
+	if baseURL == "" {
+		baseURL = os.Getenv(adminURLEnv)
+	}
+	if baseURL == "" {
+		return nil, InspectLiveGatewayOutput{}, fmt.Errorf("admin API URL is required (set url or %s)", adminURLEnv)
+	}
+	baseURL = strings.TrimSuffix(baseURL, "/")
+
+	var local map[string]interface{}
+	if input.Config != "" {
+		content, err := readConfigContent(input.Config)
+		if err != nil {
+			return nil, InspectLiveGatewayOutput{}, fmt.Errorf("failed to read config: %w", err)
+		}
+		if err := json.Unmarshal([]byte(content), &local); err != nil {
+			return nil, InspectLiveGatewayOutput{}, fmt.Errorf("invalid JSON: %w", err)
+		}
+	}
+
+	timeout := defaultAdminTimeout
+	if input.TimeoutMs > 0 {
+		timeout = time.Duration(input.TimeoutMs) * time.Millisecond
+	}
+	client := &http.Client{Timeout: timeout}
+	token := os.Getenv(adminTokenEnv)
+
+	output := InspectLiveGatewayOutput{URL: baseURL, Sources: []AdminSource{}}
+	fetch := func(name, path, fallback string) map[string]interface{} {
+		if path == "" {
+			path = fallback
+		}
+		source := AdminSource{Name: name, URL: baseURL + "/" + strings.TrimPrefix(path, "/")}
+		body, status, err := fetchAdminJSON(ctx, client, source.URL, token)
+		source.Status = status
+		if err != nil {
+			source.Error = err.Error()
+		} else {
+			source.Available = true
+		}
+		output.Sources = append(output.Sources, source)
+		return body
+	}
+
+	output.Health = fetch("health", input.HealthPath, "/__health")
+	output.License = fetch("license", input.LicensePath, "/__license")
+	output.Stats = fetch("stats", input.StatsPath, "/__stats")
+	running := fetch("config", input.ConfigPath, "/__config")
+
+	parts := []string{}
+	if running != nil {
+		output.RunningEndpoints, _ = countEndpointsWithoutAuth(running)
+		output.RunningNamespaces = features.FindNamespacesInConfig(running)
+		sort.Strings(output.RunningNamespaces)
+		if input.IncludeConfig {
+			output.RunningConfig, _ = redactTraceValue(running).(map[string]interface{})
+		}
+		parts = append(parts, fmt.Sprintf("running configuration has %d endpoint(s)", output.RunningEndpoints))
+
+		if local != nil {
+			output.Drift = compareConfigs(local, running)
+			if output.Drift.InSync {
+				parts = append(parts, "local configuration is in sync")
+			} else {
+				parts = append(parts, fmt.Sprintf("drift detected: %d endpoint(s) only local, %d only running, %d changed, %d setting(s) changed",
+					len(output.Drift.EndpointsOnlyLocal), len(output.Drift.EndpointsOnlyRunning), len(output.Drift.EndpointsChanged), len(output.Drift.SettingsChanged)))
+			}
+		}
+	} else if local != nil {
+		parts = append(parts, "drift detection unavailable (running configuration could not be fetched)")
+	}
+
+	available := 0
+	for _, s := range output.Sources {
+		if s.Available {
+			available++
+		}
+	}
+	output.Summary = fmt.Sprintf("%d of %d admin API source(s) available", available, len(output.Sources))
+	if len(parts) > 0 {
+		output.Summary += "; " + strings.Join(parts, "; ")
+	}
+
+	meta := map[string]interface{}{"sources_available": available}
+	if output.Drift != nil {
+		meta["in_sync"] = output.Drift.InSync
+	}
+	return &mcp.CallToolResult{Meta: meta}, output, nil
+}
+
+// fetchAdminJSON performs an authenticated GET and decodes a JSON object response
+func fetchAdminJSON(ctx context.Context, client *http.Client, url, token string) (map[string]interface{}, int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, resp.StatusCode, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	var body map[string]interface{}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxAdminResponse)).Decode(&body); err != nil {
+		return nil, resp.StatusCode, fmt.Errorf("invalid JSON response: %w", err)
+	}
+	return body, resp.StatusCode, nil
+}
+
+// compareConfigs reports the drift between a local and a running configuration
+func compareConfigs(local, running map[string]interface{}) *ConfigDrift {
+	drift := &ConfigDrift{
+		EndpointsOnlyLocal:    []string{},
+		EndpointsOnlyRunning:  []string{},
+		EndpointsChanged:      []string{},
+		NamespacesOnlyLocal:   []string{},
+		NamespacesOnlyRunning: []string{},
+		SettingsChanged:       []string{},
+	}
+
+	localEndpoints, runningEndpoints := endpointsByKey(local), endpointsByKey(running)
+	for key, endpoint := range localEndpoints {
+		other, ok := runningEndpoints[key]
+		switch {
+		case !ok:
+			drift.EndpointsOnlyLocal = append(drift.EndpointsOnlyLocal, key)
+		case !reflect.DeepEqual(endpoint, other):
+			drift.EndpointsChanged = append(drift.EndpointsChanged, key)
+		}
+	}
+	for key := range runningEndpoints {
+		if _, ok := localEndpoints[key]; !ok {
+			drift.EndpointsOnlyRunning = append(drift.EndpointsOnlyRunning, key)
+		}
+	}
+
+	localNamespaces, runningNamespaces := stringSet(features.FindNamespacesInConfig(local)), stringSet(features.FindNamespacesInConfig(running))
+	for ns := range localNamespaces {
+		if !runningNamespaces[ns] {
+			drift.NamespacesOnlyLocal = append(drift.NamespacesOnlyLocal, ns)
+		}
+	}
+	for ns := range runningNamespaces {
+		if !localNamespaces[ns] {
+			drift.NamespacesOnlyRunning = append(drift.NamespacesOnlyRunning, ns)
+		}
+	}
+
+	for key := range mergeKeys(local, running) {
+		if key == "endpoints" {
+			continue
+		}
+		if !reflect.DeepEqual(local[key], running[key]) {
+			drift.SettingsChanged = append(drift.SettingsChanged, key)
+		}
+	}
+
+	for _, list := range [][]string{drift.EndpointsOnlyLocal, drift.EndpointsOnlyRunning, drift.EndpointsChanged,
+		drift.NamespacesOnlyLocal, drift.NamespacesOnlyRunning, drift.SettingsChanged} {
+		sort.Strings(list)
+	}
+	drift.InSync = len(drift.EndpointsOnlyLocal)+len(drift.EndpointsOnlyRunning)+len(drift.EndpointsChanged)+len(drift.SettingsChanged) == 0
+	return drift
+}
+
+// endpointsByKey indexes the endpoints of a configuration by "METHOD /path"
+func endpointsByKey(config map[string]interface{}) map[string]interface{} {
+	result := map[string]interface{}{}
+	endpoints, _ := config["endpoints"].([]interface{})
+	for _, ep := range endpoints {
+		endpoint, ok := ep.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		method, _ := endpoint["method"].(string)
+		if method == "" {
+			method = "GET"
+		}
+		path, _ := endpoint["endpoint"].(string)
+		result[strings.ToUpper(method)+" "+path] = endpoint
+	}
+	return result
+}
+
+func stringSet(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, v := range values {
+		set[v] = true
+	}
+	return set
+}
+
+func mergeKeys(a, b map[string]interface{}) map[string]struct{} {
+	keys := make(map[string]struct{}, len(a)+len(b))
+	for k := range a {
+		keys[k] = struct{}{}
+	}
+	for k := range b {
+		keys[k] = struct{}{}
+	}
+	return keys
+}
+
+// RegisterAdminTools registers the KrakenD EE admin API integration tools
+func RegisterAdminTools(server *mcp.Server) {
+	toolkit.AddTool(server,
+		&mcp.Tool{
+			Name:        "inspect_live_gateway",
+			Description: "Connect to a running KrakenD EE admin API (URL from the input or KRAKEND_MCP_ADMIN_URL, bearer token from KRAKEND_MCP_ADMIN_TOKEN) to fetch health, license information, runtime stats and the running configuration. When a local config is given, reports drift between it and the running gateway (endpoints only local or only running, changed endpoints, namespaces and settings). Use it to explain live behavior instead of assuming the local file is deployed.",
+		},
+		InspectLiveGateway,
+	)
+}
@@ -0,0 +1,131 @@
+package tools
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const runningAdminConfig = `{
+	"version": 3,
+	"port": 8080,
+	"extra_config": {"security/cors": {}},
+	"endpoints": [
+		{"endpoint": "/users", "backend": [{"host": ["http://users"], "url_pattern": "/users"}]},
+		{"endpoint": "/legacy", "backend": [{"host": ["http://legacy"], "url_pattern": "/"}]}
+	],
+	"jwk_secret": "s3cr3t"
+}`
+
+func newAdminServer(t *testing.T, token string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+token {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/__health":
+			w.Write([]byte(`{"status": "ok"}`))
+		case "/__license":
+			w.Write([]byte(`{"valid": true, "expires": "2030-01-01"}`))
+		case "/__config":
+			w.Write([]byte(runningAdminConfig))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestInspectLiveGateway_Drift(t *testing.T) {
+	server := newAdminServer(t, "admin-token")
+	t.Setenv(adminURLEnv, server.URL)
+	t.Setenv(adminTokenEnv, "admin-token")
+
+	local := `{
+		"version": 3,
+		"port": 9090,
+		"endpoints": [
+			{"endpoint": "/users", "backend": [{"host": ["http://users"], "url_pattern": "/v2/users"}]},
+			{"endpoint": "/orders", "method": "POST"}
+		]
+	}`
+	_, output, err := InspectLiveGateway(context.Background(), nil, InspectLiveGatewayInput{Config: local, IncludeConfig: true})
+	if err != nil {
+		t.Fatalf("InspectLiveGateway() error = %v", err)
+	}
+
+	if output.Health["status"] != "ok" || output.License["valid"] != true {
+		t.Errorf("Expected health and license, got %v %v", output.Health, output.License)
+	}
+	for _, s := range output.Sources {
+		if s.Name == "stats" && (s.Available || s.Status != http.StatusNotFound) {
+			t.Errorf("Expected stats to be unavailable, got %+v", s)
+		}
+	}
+	if output.RunningEndpoints != 2 {
+		t.Errorf("Expected 2 running endpoints, got %d", output.RunningEndpoints)
+	}
+	if output.RunningConfig["jwk_secret"] == "s3cr3t" {
+		t.Error("Running configuration must be redacted")
+	}
+
+	drift := output.Drift
+	if drift == nil || drift.InSync {
+		t.Fatalf("Expected drift, got %+v", drift)
+	}
+	if len(drift.EndpointsOnlyLocal) != 1 || drift.EndpointsOnlyLocal[0] != "POST /orders" {
+		t.Errorf("Unexpected local-only endpoints: %v", drift.EndpointsOnlyLocal)
+	}
+	if len(drift.EndpointsOnlyRunning) != 1 || drift.EndpointsOnlyRunning[0] != "GET /legacy" {
+		t.Errorf("Unexpected running-only endpoints: %v", drift.EndpointsOnlyRunning)
+	}
+	if len(drift.EndpointsChanged) != 1 || drift.EndpointsChanged[0] != "GET /users" {
+		t.Errorf("Unexpected changed endpoints: %v", drift.EndpointsChanged)
+	}
+	if len(drift.NamespacesOnlyRunning) != 1 || drift.NamespacesOnlyRunning[0] != "security/cors" {
+		t.Errorf("Unexpected running-only namespaces: %v", drift.NamespacesOnlyRunning)
+	}
+	for _, key := range []string{"port", "extra_config", "jwk_secret"} {
+		if !containsString(drift.SettingsChanged, key) {
+			t.Errorf("Expected %s in changed settings, got %v", key, drift.SettingsChanged)
+		}
+	}
+}
+
+func TestInspectLiveGateway_Unauthorized(t *testing.T) {
+	server := newAdminServer(t, "admin-token")
+	t.Setenv(adminTokenEnv, "wrong")
+
+	_, output, err := InspectLiveGateway(context.Background(), nil, InspectLiveGatewayInput{URL: server.URL, Config: runningAdminConfig})
+	if err != nil {
+		t.Fatalf("InspectLiveGateway() error = %v", err)
+	}
+	for _, s := range output.Sources {
+		if s.Available || s.Status != http.StatusUnauthorized {
+			t.Errorf("Expected unauthorized source, got %+v", s)
+		}
+	}
+	if output.Drift != nil {
+		t.Error("Drift must not be computed without the running configuration")
+	}
+}
+
+func TestInspectLiveGateway_RequiresURL(t *testing.T) {
+	t.Setenv(adminURLEnv, "")
+	if _, _, err := InspectLiveGateway(context.Background(), nil, InspectLiveGatewayInput{}); err == nil {
+		t.Error("Expected error without admin API URL")
+	}
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
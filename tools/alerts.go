@@ -0,0 +1,328 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/krakend/mcp-server/internal/toolkit"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	defaultErrorRateThreshold  = 0.05
+	defaultLatencySLOFactor    = 0.8
+	defaultRateLimitSaturation = 0.9
+	defaultEndpointTimeout     = 2 * time.Second // KrakenD default when no timeout is set
+	alertRateWindow            = "5m"
+)
+
+// GenerateAlertRulesInput defines input for generate_alert_rules tool
+type GenerateAlertRulesInput struct {
+	Config              string  `json:"config" jsonschema:"KrakenD configuration (JSON string or file path)"`
+	ConfigEncoding      string  `json:"config_encoding,omitempty" jsonschema:"Encoding of config for large configurations: base64 or gzip+base64 (optional, defaults to a plain JSON string or file path)"`
+	Job                 string  `json:"job,omitempty" jsonschema:"Prometheus job scraping KrakenD, added to every selector (optional)"`
+	ErrorRateThreshold  float64 `json:"error_rate_threshold,omitempty" jsonschema:"5xx ratio per endpoint that fires an alert (optional, defaults to 0.05)"`
+	LatencySLOFactor    float64 `json:"latency_slo_factor,omitempty" jsonschema:"Fraction of the endpoint timeout that the backend p95 latency must stay under (optional, defaults to 0.8)"`
+	RateLimitSaturation float64 `json:"rate_limit_saturation,omitempty" jsonschema:"Fraction of an endpoint rate limit that fires a saturation alert (optional, defaults to 0.9)"`
+	OutputFile          string  `json:"output_file,omitempty" jsonschema:"Path where the Prometheus rule file (YAML) is written (optional)"`
+}
+
+// AlertRule is a Prometheus alerting rule
+type AlertRule struct {
+	Alert       string            `json:"alert" yaml:"alert"`
+	Expr        string            `json:"expr" yaml:"expr"`
+	For         string            `json:"for,omitempty" yaml:"for,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty" yaml:"annotations,omitempty"`
+}
+
+// AlertRuleGroup is a group of a Prometheus rule file
+type AlertRuleGroup struct {
+	Name  string      `json:"name" yaml:"name"`
+	Rules []AlertRule `json:"rules" yaml:"rules"`
+}
+
+// GenerateAlertRulesOutput defines output for generate_alert_rules tool
+type GenerateAlertRulesOutput struct {
+	Groups     []AlertRuleGroup `json:"groups"`
+	RulesYAML  string           `json:"rules_yaml"` // Ready for Prometheus rule_files
+	Warnings   []string         `json:"warnings,omitempty"`
+	OutputFile string           `json:"output_file,omitempty"`
+	Message    string           `json:"message"`
+}
+
+// alertSelector builds a metric selector with the optional job matcher
+func alertSelector(job, metric string, matchers ...string) string {
+	if job != "" {
+		matchers = append([]string{fmt.Sprintf("job=%q", job)}, matchers...)
+	}
+	if len(matchers) == 0 {
+		return metric
+	}
+	return metric + "{" + strings.Join(matchers, ",") + "}"
+}
+
+// formatFloat renders a threshold without trailing zeros or floating point noise
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(math.Round(v*1e6)/1e6, 'f', -1, 64)
+}
+
+// endpointTimeout returns the timeout of an endpoint, falling back to the global one
+func endpointTimeout(config, endpoint map[string]interface{}) time.Duration {
+	for _, source := range []map[string]interface{}{endpoint, config} {
+		if raw, ok := source["timeout"].(string); ok {
+			if d, err := time.ParseDuration(raw); err == nil && d > 0 {
+				return d
+			}
+		}
+	}
+	return defaultEndpointTimeout
+}
+
+// GenerateAlertRules builds Prometheus alerting rules tailored to the endpoints, backends and limits of a config
+func GenerateAlertRules(ctx context.Context, req *mcp.CallToolRequest, input GenerateAlertRulesInput) (*mcp.CallToolResult, GenerateAlertRulesOutput, error) {
+	configContent, err := readEncodedConfig(input.Config, input.ConfigEncoding)
+	if err != nil {
+		return nil, GenerateAlertRulesOutput{}, fmt.Errorf("failed to read config: %w", err)
+	}
+	var config map[string]interface{}
+	if err := json.Unmarshal([]byte(configContent), &config); err != nil {
+		return nil, GenerateAlertRulesOutput{}, fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	t := detectTelemetry(config)
+	if !t.OpenTelemetry {
+		return nil, GenerateAlertRulesOutput{}, fmt.Errorf("the configuration has no telemetry/opentelemetry namespace, so KrakenD exports no metrics to alert on")
+	}
+
+	errorThreshold := input.ErrorRateThreshold
+	if errorThreshold <= 0 {
+		errorThreshold = defaultErrorRateThreshold
+	}
+	sloFactor := input.LatencySLOFactor
+	if sloFactor <= 0 {
+		sloFactor = defaultLatencySLOFactor
+	}
+	saturation := input.RateLimitSaturation
+	if saturation <= 0 {
+		saturation = defaultRateLimitSaturation
+	}
+
+	output := GenerateAlertRulesOutput{}
+	labels := func(severity, layer string) map[string]string {
+		l := map[string]string{"severity": severity, "layer": layer}
+		if t.ServiceName != "" {
+			l["service"] = t.ServiceName
+		}
+		return l
+	}
+
+	var endpointRules, backendRules, rateLimitRules []AlertRule
+	endpoints, _ := config["endpoints"].([]interface{})
+
+	if t.Global {
+		requests := alertSelector(input.Job, metricServerDuration+"_count")
+		errors := alertSelector(input.Job, metricServerDuration+"_count", labelStatusCode+`=~"5.."`)
+		endpointRules = append(endpointRules, AlertRule{
+			Alert: "KrakenDEndpointHigh5xxRate",
+			Expr: fmt.Sprintf("sum by (%[1]s) (rate(%[2]s[%[4]s])) / sum by (%[1]s) (rate(%[3]s[%[4]s])) > %[5]s",
+				labelRoute, errors, requests, alertRateWindow, formatFloat(errorThreshold)),
+			For:    "5m",
+			Labels: labels("critical", "global"),
+			Annotations: map[string]string{
+				"summary":     "High 5xx rate on {{ $labels." + labelRoute + " }}",
+				"description": fmt.Sprintf("More than %s%% of the requests to {{ $labels.%s }} failed with 5xx in the last %s.", formatFloat(errorThreshold*100), labelRoute, alertRateWindow),
+			},
+		})
+	} else {
+		output.Warnings = append(output.Warnings, "Global layer metrics are disabled: per-endpoint 5xx and rate limit alerts cannot be generated")
+	}
+
+	// Backend latency SLOs and circuit breakers, keyed by the server address label
+	backendMetric := metricClientDuration
+	if !t.BackendRoundTrip {
+		backendMetric = metricBackendDuration
+	}
+	latencySLO := map[string]time.Duration{}
+	breakers := map[string]map[string]interface{}{}
+	for _, ep := range endpoints {
+		endpoint, ok := ep.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		path, _ := endpoint["endpoint"].(string)
+		timeout := endpointTimeout(config, endpoint)
+
+		backends, _ := endpoint["backend"].([]interface{})
+		for _, b := range backends {
+			backend, ok := b.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			extra, _ := backend["extra_config"].(map[string]interface{})
+			breaker, _ := extra["qos/circuit-breaker"].(map[string]interface{})
+			hosts, _ := backend["host"].([]interface{})
+			for _, h := range hosts {
+				host, _ := h.(string)
+				_, hostname, _, err := splitBackendHost(host)
+				if err != nil {
+					continue
+				}
+				if current, ok := latencySLO[hostname]; !ok || timeout < current {
+					latencySLO[hostname] = timeout
+				}
+				if breaker != nil {
+					breakers[hostname] = breaker
+				}
+			}
+		}
+
+		endpointExtra, _ := endpoint["extra_config"].(map[string]interface{})
+		if limit, ok := endpointExtra["qos/ratelimit/router"].(map[string]interface{}); ok && t.Global {
+			maxRate, _ := limit["max_rate"].(float64)
+			every := time.Second
+			if raw, ok := limit["every"].(string); ok {
+				if d, err := time.ParseDuration(raw); err == nil && d > 0 {
+					every = d
+				}
+			}
+			if maxRate > 0 {
+				perSecond := maxRate / every.Seconds()
+				rateLimitRules = append(rateLimitRules, AlertRule{
+					Alert: "KrakenDRateLimitSaturation",
+					Expr: fmt.Sprintf("sum(rate(%s[%s])) > %s",
+						alertSelector(input.Job, metricServerDuration+"_count", fmt.Sprintf("%s=%q", labelRoute, path)), alertRateWindow, formatFloat(perSecond*saturation)),
+					For:    "10m",
+					Labels: labels("warning", "global"),
+					Annotations: map[string]string{
+						"summary":     "Rate limit of " + path + " close to saturation",
+						"description": fmt.Sprintf("%s receives more than %s%% of its rate limit (%s requests per %s).", path, formatFloat(saturation*100), formatFloat(maxRate), every),
+					},
+				})
+			}
+		}
+	}
+
+	hostnames := make([]string, 0, len(latencySLO))
+	for hostname := range latencySLO {
+		hostnames = append(hostnames, hostname)
+	}
+	sort.Strings(hostnames)
+	if t.BackendStage || t.BackendRoundTrip {
+		for _, hostname := range hostnames {
+			threshold := latencySLO[hostname].Seconds() * sloFactor
+			backendRules = append(backendRules, AlertRule{
+				Alert: "KrakenDBackendLatencySLOBreach",
+				Expr: fmt.Sprintf("histogram_quantile(0.95, sum by (le) (rate(%s[%s]))) > %s",
+					alertSelector(input.Job, backendMetric+"_bucket", fmt.Sprintf("%s=%q", labelBackend, hostname)), alertRateWindow, formatFloat(threshold)),
+				For:    "10m",
+				Labels: labels("warning", "backend"),
+				Annotations: map[string]string{
+					"summary":     "Backend " + hostname + " is slow",
+					"description": fmt.Sprintf("p95 latency of %s is above %ss (%s%% of the %s endpoint timeout): requests are about to time out.", hostname, formatFloat(threshold), formatFloat(sloFactor*100), latencySLO[hostname]),
+				},
+			})
+		}
+	} else if len(hostnames) > 0 {
+		output.Warnings = append(output.Warnings, "Backend layer metrics are disabled: no backend latency alerts")
+	}
+
+	breakerHosts := make([]string, 0, len(breakers))
+	for hostname := range breakers {
+		breakerHosts = append(breakerHosts, hostname)
+	}
+	sort.Strings(breakerHosts)
+	if len(breakerHosts) > 0 && !t.BackendRoundTrip {
+		output.Warnings = append(output.Warnings, "Circuit breakers found but backend round_trip metrics are disabled: enable layers.backend.metrics.round_trip to alert on them")
+	} else {
+		for _, hostname := range breakerHosts {
+			breaker := breakers[hostname]
+			maxErrors, _ := breaker["max_errors"].(float64)
+			interval, _ := breaker["interval"].(float64)
+			if maxErrors <= 0 || interval <= 0 {
+				continue
+			}
+			// KrakenD does not export the breaker state: alert when errors reach the tripping threshold
+			backendRules = append(backendRules, AlertRule{
+				Alert: "KrakenDCircuitBreakerOpen",
+				Expr: fmt.Sprintf("sum(increase(%s[%ds])) >= %s",
+					alertSelector(input.Job, metricClientDuration+"_count", fmt.Sprintf("%s=%q", labelBackend, hostname), labelStatusCode+`=~"5.."`), int(interval), formatFloat(maxErrors)),
+				Labels: labels("critical", "backend"),
+				Annotations: map[string]string{
+					"summary":     "Circuit breaker for " + hostname + " is probably open",
+					"description": fmt.Sprintf("%s returned at least %s error(s) within the %ds breaker interval, which opens the circuit breaker.", hostname, formatFloat(maxErrors), int(interval)),
+				},
+			})
+		}
+	}
+
+	if len(rateLimitRules) > 0 {
+		rateLimitRules = append(rateLimitRules, AlertRule{
+			Alert: "KrakenDRateLimitRejections",
+			Expr: fmt.Sprintf("sum by (%s) (rate(%s[%s])) > 0", labelRoute,
+				alertSelector(input.Job, metricServerDuration+"_count", labelStatusCode+`="429"`), alertRateWindow),
+			For:    "5m",
+			Labels: labels("info", "global"),
+			Annotations: map[string]string{
+				"summary":     "Requests to {{ $labels." + labelRoute + " }} are being rate limited",
+				"description": "KrakenD is rejecting requests with 429 Too Many Requests.",
+			},
+		})
+	}
+
+	for _, g := range []AlertRuleGroup{
+		{Name: "krakend-endpoints", Rules: endpointRules},
+		{Name: "krakend-backends", Rules: backendRules},
+		{Name: "krakend-rate-limits", Rules: rateLimitRules},
+	} {
+		if len(g.Rules) > 0 {
+			output.Groups = append(output.Groups, g)
+		}
+	}
+	if len(output.Groups) == 0 {
+		return nil, GenerateAlertRulesOutput{}, fmt.Errorf("no alert rule applies: enable telemetry layers metrics or add endpoints with backends")
+	}
+
+	data, err := yaml.Marshal(map[string]interface{}{"groups": output.Groups})
+	if err != nil {
+		return nil, GenerateAlertRulesOutput{}, fmt.Errorf("failed to encode rules: %w", err)
+	}
+	output.RulesYAML = string(data)
+
+	if input.OutputFile != "" {
+		if err := os.MkdirAll(filepath.Dir(input.OutputFile), 0o755); err != nil {
+			return nil, GenerateAlertRulesOutput{}, fmt.Errorf("failed to create %s: %w", filepath.Dir(input.OutputFile), err)
+		}
+		if err := os.WriteFile(input.OutputFile, data, 0o644); err != nil {
+			return nil, GenerateAlertRulesOutput{}, fmt.Errorf("failed to write %s: %w", input.OutputFile, err)
+		}
+		output.OutputFile = input.OutputFile
+	}
+
+	total := 0
+	for _, g := range output.Groups {
+		total += len(g.Rules)
+	}
+	output.Message = fmt.Sprintf("Generated %d alert rule(s) in %d group(s)", total, len(output.Groups))
+	return nil, output, nil
+}
+
+// RegisterAlertTools registers alerting rule generation tools
+func RegisterAlertTools(server *mcp.Server) {
+	toolkit.AddTool(server,
+		&mcp.Tool{
+			Name:        "generate_alert_rules",
+			Description: "Generate Prometheus alerting rules (Alertmanager-ready YAML) tailored to a KrakenD configuration: per-endpoint high 5xx rate, backend p95 latency SLO breaches derived from endpoint timeouts, circuit breakers reaching their tripping threshold, and router rate limit saturation and rejections. Selectors use the metric names and labels of the configured telemetry/opentelemetry layers.",
+		},
+		GenerateAlertRules,
+	)
+}
@@ -0,0 +1,126 @@
+package tools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+const alertTestConfig = `{
+	"version": 3,
+	"timeout": "3s",
+	"extra_config": {
+		"telemetry/opentelemetry": {
+			"service_name": "orders-gateway",
+			"exporters": {"prometheus": [{"name": "local"}]},
+			"layers": {"backend": {"metrics": {"round_trip": true}}}
+		}
+	},
+	"endpoints": [
+		{
+			"endpoint": "/orders",
+			"timeout": "1s",
+			"extra_config": {"qos/ratelimit/router": {"max_rate": 100, "every": "10s"}},
+			"backend": [{
+				"host": ["http://orders:8080"],
+				"extra_config": {"qos/circuit-breaker": {"interval": 60, "timeout": 10, "max_errors": 5}}
+			}]
+		},
+		{"endpoint": "/users", "backend": [{"host": ["https://users.internal"]}, {"host": ["http://orders:8080"]}]}
+	]
+}`
+
+func TestGenerateAlertRules(t *testing.T) {
+	outFile := filepath.Join(t.TempDir(), "rules", "krakend.yml")
+	_, output, err := GenerateAlertRules(context.Background(), nil, GenerateAlertRulesInput{Config: alertTestConfig, Job: "krakend", OutputFile: outFile})
+	if err != nil {
+		t.Fatalf("GenerateAlertRules() error = %v", err)
+	}
+
+	rules := map[string][]AlertRule{}
+	for _, g := range output.Groups {
+		for _, r := range g.Rules {
+			rules[r.Alert] = append(rules[r.Alert], r)
+			if r.Labels["service"] != "orders-gateway" || r.Labels["layer"] == "" {
+				t.Errorf("Expected service and layer labels, got %v", r.Labels)
+			}
+			if !strings.Contains(r.Expr, `job="krakend"`) {
+				t.Errorf("Expected job matcher in %s", r.Expr)
+			}
+		}
+	}
+
+	if len(rules["KrakenDEndpointHigh5xxRate"]) != 1 {
+		t.Errorf("Expected a 5xx rule, got %v", rules)
+	}
+
+	// orders is used with 1s and 3s timeouts: the SLO follows the strictest one
+	latency := rules["KrakenDBackendLatencySLOBreach"]
+	if len(latency) != 2 {
+		t.Fatalf("Expected a latency rule per backend, got %d", len(latency))
+	}
+	for _, r := range latency {
+		if strings.Contains(r.Expr, `server_address="orders"`) && !strings.HasSuffix(r.Expr, "> 0.8") {
+			t.Errorf("Unexpected orders SLO %s", r.Expr)
+		}
+		if strings.Contains(r.Expr, `server_address="users.internal"`) && !strings.HasSuffix(r.Expr, "> 2.4") {
+			t.Errorf("Unexpected users SLO %s", r.Expr)
+		}
+	}
+
+	breaker := rules["KrakenDCircuitBreakerOpen"]
+	if len(breaker) != 1 || !strings.Contains(breaker[0].Expr, "[60s]") || !strings.HasSuffix(breaker[0].Expr, ">= 5") {
+		t.Errorf("Unexpected circuit breaker rule %+v", breaker)
+	}
+
+	// 100 requests every 10s = 10 rps, alert at 90%
+	saturation := rules["KrakenDRateLimitSaturation"]
+	if len(saturation) != 1 || !strings.HasSuffix(saturation[0].Expr, "> 9") {
+		t.Errorf("Unexpected saturation rule %+v", saturation)
+	}
+	if len(rules["KrakenDRateLimitRejections"]) != 1 {
+		t.Error("Expected a 429 rejection rule")
+	}
+
+	data, err := os.ReadFile(outFile)
+	if err != nil {
+		t.Fatalf("Rules not written: %v", err)
+	}
+	var parsed struct {
+		Groups []AlertRuleGroup `yaml:"groups"`
+	}
+	if err := yaml.Unmarshal(data, &parsed); err != nil || len(parsed.Groups) != len(output.Groups) {
+		t.Errorf("Written rules are not a valid rule file: %v", err)
+	}
+}
+
+func TestGenerateAlertRules_CircuitBreakerNeedsRoundTrip(t *testing.T) {
+	config := strings.Replace(alertTestConfig, `"round_trip": true`, `"round_trip": false`, 1)
+	_, output, err := GenerateAlertRules(context.Background(), nil, GenerateAlertRulesInput{Config: config})
+	if err != nil {
+		t.Fatalf("GenerateAlertRules() error = %v", err)
+	}
+	for _, g := range output.Groups {
+		for _, r := range g.Rules {
+			if r.Alert == "KrakenDCircuitBreakerOpen" {
+				t.Error("Circuit breaker rules need round trip metrics")
+			}
+			if r.Alert == "KrakenDBackendLatencySLOBreach" && !strings.Contains(r.Expr, metricBackendDuration) {
+				t.Errorf("Expected stage metric without round trip, got %s", r.Expr)
+			}
+		}
+	}
+	if len(output.Warnings) == 0 {
+		t.Error("Expected a warning about circuit breakers")
+	}
+}
+
+func TestGenerateAlertRules_NoTelemetry(t *testing.T) {
+	if _, _, err := GenerateAlertRules(context.Background(), nil, GenerateAlertRulesInput{Config: `{"version": 3}`}); err == nil {
+		t.Error("Expected error without telemetry/opentelemetry")
+	}
+}
@@ -0,0 +1,310 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/krakend/mcp-server/internal/toolkit"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	apiKeysNamespace    = "auth/api-keys"
+	quotaNamespace      = "governance/quota"
+	quotaProcessors     = "governance/processors"
+	planQuotaName       = "api_plans"
+	planRedisPool       = "quota_redis"
+	defaultPlanHeader   = "X-Plan"
+	defaultRedisAddress = "redis:6379"
+	apiKeyHeader        = "Authorization" // Header carrying the API key, identifying each client
+)
+
+// quotaUnits are the periods a quota can be counted over
+var quotaUnits = []string{"hour", "day", "week", "month", "year"}
+
+// planNamePattern restricts plan names to values usable as roles, tiers and env variable names
+var planNamePattern = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_-]*$`)
+
+// APIPlan is a commercial plan of the API
+type APIPlan struct {
+	Name      string   `json:"name" jsonschema:"Plan name, used as API key role and tier value, e.g. free, pro or enterprise"`
+	RateLimit float64  `json:"rate_limit" jsonschema:"Requests allowed per API key every rate_every"`
+	RateEvery string   `json:"rate_every,omitempty" jsonschema:"Period of rate_limit, e.g. 1s or 1m (optional, defaults to 1s)"`
+	Quota     int      `json:"quota,omitempty" jsonschema:"Requests allowed per API key every quota_unit, counted in Redis (optional, no quota by default)"`
+	QuotaUnit string   `json:"quota_unit,omitempty" jsonschema:"Quota period: hour, day, week, month or year (optional, defaults to month)"`
+	Endpoints []string `json:"endpoints,omitempty" jsonschema:"Endpoints included in the plan (optional, defaults to every endpoint)"`
+}
+
+// ModelAPIPlansInput defines input for model_api_plans tool
+type ModelAPIPlansInput struct {
+	Plans        []APIPlan `json:"plans" jsonschema:"API plans, from the cheapest to the most expensive"`
+	Config       string    `json:"config,omitempty" jsonschema:"KrakenD configuration to update (optional, JSON string or file path)"`
+	PlanHeader   string    `json:"plan_header,omitempty" jsonschema:"Header the API key role (plan) is propagated to (optional, defaults to X-Plan)"`
+	RedisAddress string    `json:"redis_address,omitempty" jsonschema:"Redis storing the quota counters (optional, defaults to redis:6379)"`
+	Edition      string    `json:"edition,omitempty" jsonschema:"Target edition: ce or ee (optional)"`
+}
+
+// PlanSummary maps a plan to the configuration implementing it
+type PlanSummary struct {
+	Plan      string   `json:"plan"`
+	Role      string   `json:"role"` // API key role
+	KeyEnv    string   `json:"key_env"`
+	RateLimit string   `json:"rate_limit"`
+	Quota     string   `json:"quota,omitempty"`
+	Endpoints []string `json:"endpoints"`
+}
+
+// ModelAPIPlansOutput defines output for model_api_plans tool
+type ModelAPIPlansOutput struct {
+	Plans              []PlanSummary          `json:"plans"`
+	Table              string                 `json:"table"` // Markdown table mapping plans to configuration
+	ServiceExtraConfig map[string]interface{} `json:"service_extra_config"`
+	EndpointTemplate   map[string]interface{} `json:"endpoint_extra_config"` // extra_config of an endpoint open to every plan
+	Env                []string               `json:"env"`                   // Environment variables holding the sample API keys
+	Config             map[string]interface{} `json:"config,omitempty"`
+	Changes            []string               `json:"changes,omitempty"` // JSON paths modified
+	Warnings           []string               `json:"warnings,omitempty"`
+	Notes              []string               `json:"notes"`
+}
+
+// planKeyEnv is the environment variable holding the sample API key of a plan
+func planKeyEnv(plan string) string {
+	return "API_KEY_" + strings.ToUpper(strings.ReplaceAll(plan, "-", "_"))
+}
+
+// planEndpointConfig builds the extra_config of an endpoint open to some plans: the API key
+// roles allowed, their rate limits and, when they have one, their quotas
+func planEndpointConfig(plans []APIPlan, planHeader string) (map[string]interface{}, error) {
+	roles := []interface{}{}
+	var tiers []RateLimitTier
+	var quotaTiers []interface{}
+	for _, plan := range plans {
+		roles = append(roles, plan.Name)
+		tiers = append(tiers, RateLimitTier{Value: plan.Name, MaxRate: plan.RateLimit, Every: plan.RateEvery})
+		if plan.Quota > 0 {
+			quotaTiers = append(quotaTiers, map[string]interface{}{
+				"rule_name":     plan.Name,
+				"tier_value":    plan.Name,
+				"tier_value_as": "literal",
+				"strategy":      "header",
+				"key":           apiKeyHeader,
+			})
+		}
+	}
+	rateLimit, err := tieredRateLimit(tiers, planHeader, apiKeyHeader)
+	if err != nil {
+		return nil, err
+	}
+	extra := map[string]interface{}{
+		apiKeysNamespace:         map[string]interface{}{"roles": roles},
+		tieredRateLimitNamespace: rateLimit,
+	}
+	if len(quotaTiers) > 0 {
+		extra[quotaNamespace] = map[string]interface{}{
+			"quota_name": planQuotaName,
+			"tier_key":   planHeader,
+			"tiers":      quotaTiers,
+		}
+	}
+	return extra, nil
+}
+
+// renderPlansTable renders the plans summary as a Markdown table
+func renderPlansTable(plans []PlanSummary) string {
+	var b strings.Builder
+	b.WriteString("| Plan | API key role | Rate limit | Quota | Endpoints |\n|---|---|---|---|---|\n")
+	for _, p := range plans {
+		quota := p.Quota
+		if quota == "" {
+			quota = "none"
+		}
+		fmt.Fprintf(&b, "| %s | %s | %s | %s | %s |\n", p.Plan, p.Role, p.RateLimit, quota, strings.Join(p.Endpoints, ", "))
+	}
+	return b.String()
+}
+
+// ModelAPIPlans turns API plans into Enterprise API keys with roles, tiered rate limits and quotas
+func ModelAPIPlans(ctx context.Context, req *mcp.CallToolRequest, input ModelAPIPlansInput) (*mcp.CallToolResult, ModelAPIPlansOutput, error) {
+	if input.Edition == "ce" {
+		return nil, ModelAPIPlansOutput{}, fmt.Errorf("API plans need KrakenD Enterprise (%s, %s and %s are not available in the Community Edition)", apiKeysNamespace, tieredRateLimitNamespace, quotaNamespace)
+	}
+	if len(input.Plans) == 0 {
+		return nil, ModelAPIPlansOutput{}, fmt.Errorf("at least one plan is required")
+	}
+	planHeader := envOrDefault(input.PlanHeader, defaultPlanHeader)
+	plans := make([]APIPlan, len(input.Plans))
+	copy(plans, input.Plans)
+	var names []string
+	for i, plan := range plans {
+		if !planNamePattern.MatchString(plan.Name) {
+			return nil, ModelAPIPlansOutput{}, fmt.Errorf("invalid plan name %q: use letters, digits, - and _", plan.Name)
+		}
+		if slices.Contains(names, plan.Name) {
+			return nil, ModelAPIPlansOutput{}, fmt.Errorf("plan %q is defined twice", plan.Name)
+		}
+		names = append(names, plan.Name)
+		if plan.RateLimit <= 0 {
+			return nil, ModelAPIPlansOutput{}, fmt.Errorf("plan %s needs a rate_limit greater than 0", plan.Name)
+		}
+		plans[i].RateEvery = envOrDefault(plan.RateEvery, "1s")
+		plans[i].QuotaUnit = envOrDefault(plan.QuotaUnit, "month")
+		if plan.Quota > 0 && !slices.Contains(quotaUnits, plans[i].QuotaUnit) {
+			return nil, ModelAPIPlansOutput{}, fmt.Errorf("invalid quota_unit %q for plan %s: use %s", plan.QuotaUnit, plan.Name, strings.Join(quotaUnits, ", "))
+		}
+	}
+
+	output := ModelAPIPlansOutput{Env: []string{}}
+	keys := []interface{}{}
+	var rules []interface{}
+	for _, plan := range plans {
+		env := planKeyEnv(plan.Name)
+		output.Env = append(output.Env, env)
+		keys = append(keys, map[string]interface{}{
+			"key":          envTemplate(env),
+			"roles":        []interface{}{plan.Name},
+			"@description": fmt.Sprintf("Sample %s plan key: add one entry per customer", plan.Name),
+		})
+		summary := PlanSummary{
+			Plan:      plan.Name,
+			Role:      plan.Name,
+			KeyEnv:    env,
+			RateLimit: fmt.Sprintf("%g req/%s", plan.RateLimit, plan.RateEvery),
+			Endpoints: plan.Endpoints,
+		}
+		if len(summary.Endpoints) == 0 {
+			summary.Endpoints = []string{"all"}
+		}
+		if plan.Quota > 0 {
+			summary.Quota = fmt.Sprintf("%d req/%s", plan.Quota, plan.QuotaUnit)
+			rules = append(rules, map[string]interface{}{
+				"name":   plan.Name,
+				"limits": []interface{}{map[string]interface{}{"amount": plan.Quota, "unit": plan.QuotaUnit}},
+			})
+		}
+		output.Plans = append(output.Plans, summary)
+	}
+
+	output.ServiceExtraConfig = map[string]interface{}{
+		apiKeysNamespace: map[string]interface{}{
+			"strategy":       "header",
+			"identifier":     apiKeyHeader,
+			"propagate_role": planHeader,
+			"keys":           keys,
+		},
+	}
+	if len(rules) > 0 {
+		output.ServiceExtraConfig["redis"] = map[string]interface{}{
+			"connection_pools": []interface{}{map[string]interface{}{
+				"name":    planRedisPool,
+				"address": envOrDefault(input.RedisAddress, defaultRedisAddress),
+			}},
+		}
+		output.ServiceExtraConfig[quotaProcessors] = map[string]interface{}{
+			"quotas": []interface{}{map[string]interface{}{
+				"name":             planQuotaName,
+				"connection_name":  planRedisPool,
+				"hash_keys":        true,
+				"on_failure_allow": false,
+				"rules":            rules,
+			}},
+		}
+		output.Notes = append(output.Notes, "Quotas are counted in Redis and shared by every KrakenD instance; requests are rejected when Redis is unreachable (on_failure_allow: false), set it to true to favour availability over billing accuracy.")
+	}
+	template, err := planEndpointConfig(plans, planHeader)
+	if err != nil {
+		return nil, ModelAPIPlansOutput{}, err
+	}
+	output.EndpointTemplate = template
+	output.Table = renderPlansTable(output.Plans)
+	output.Notes = append(output.Notes,
+		fmt.Sprintf("Each API key carries its plan as role; KrakenD propagates it to the %s header, which selects the rate limit and quota tier. Keys are identified by the %s header, so limits apply per key.", planHeader, apiKeyHeader),
+		"Sample keys are read from environment variables through Flexible Configuration (FC_ENABLE=1): replace them with one entry per customer, and never commit real keys.",
+		"Requests with a key whose plan does not include the endpoint get 403 Forbidden; requests over the rate limit or quota get 429 Too Many Requests.")
+
+	if input.Config == "" {
+		return nil, output, nil
+	}
+	configContent, err := readConfigContent(input.Config)
+	if err != nil {
+		return nil, ModelAPIPlansOutput{}, fmt.Errorf("failed to read config: %w", err)
+	}
+	var config map[string]interface{}
+	if err := json.Unmarshal([]byte(configContent), &config); err != nil {
+		return nil, ModelAPIPlansOutput{}, fmt.Errorf("invalid JSON: %w", err)
+	}
+	if input.Edition == "" && !DetectEnterpriseFeatures(configContent) {
+		output.Warnings = append(output.Warnings, "API plans use Enterprise features and the configuration uses no other Enterprise feature: run it with the krakend-ee image")
+	}
+
+	service, _ := config["extra_config"].(map[string]interface{})
+	if service == nil {
+		service = map[string]interface{}{}
+		config["extra_config"] = service
+	}
+	for _, namespace := range sortedKeys(output.ServiceExtraConfig) {
+		location := fmt.Sprintf("$.extra_config['%s']", namespace)
+		if _, exists := service[namespace]; exists {
+			output.Warnings = append(output.Warnings, fmt.Sprintf("%s already existed and was replaced", location))
+		}
+		service[namespace] = output.ServiceExtraConfig[namespace]
+		output.Changes = append(output.Changes, location)
+	}
+
+	declared := map[string]bool{}
+	endpoints, _ := config["endpoints"].([]interface{})
+	for i, ep := range endpoints {
+		endpoint, ok := ep.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		path, _ := endpoint["endpoint"].(string)
+		declared[path] = true
+		var included []APIPlan
+		for _, plan := range plans {
+			if len(plan.Endpoints) == 0 || slices.Contains(plan.Endpoints, path) {
+				included = append(included, plan)
+			}
+		}
+		if len(included) == 0 {
+			output.Warnings = append(output.Warnings, fmt.Sprintf("%s %s is in no plan and stays public", endpointMethod(endpoint), path))
+			continue
+		}
+		settings, err := planEndpointConfig(included, planHeader)
+		if err != nil {
+			return nil, ModelAPIPlansOutput{}, err
+		}
+		extra, _ := endpoint["extra_config"].(map[string]interface{})
+		if extra == nil {
+			extra = map[string]interface{}{}
+			endpoint["extra_config"] = extra
+		}
+		for _, namespace := range sortedKeys(settings) {
+			extra[namespace] = settings[namespace]
+			output.Changes = append(output.Changes, fmt.Sprintf("$.endpoints[%d].extra_config['%s']", i, namespace))
+		}
+	}
+	for _, plan := range plans {
+		for _, path := range plan.Endpoints {
+			if !declared[path] {
+				output.Warnings = append(output.Warnings, fmt.Sprintf("Plan %s includes %s, which is not declared in the configuration", plan.Name, path))
+			}
+		}
+	}
+	output.Config = config
+	return nil, output, nil
+}
+
+// RegisterAPIPlanTools registers the API plan modeling tool
+func RegisterAPIPlanTools(server *mcp.Server) {
+	toolkit.AddTool(server,
+		&mcp.Tool{
+			Name:        "model_api_plans",
+			Description: "Model monetized API plans (e.g. free, pro, enterprise) with per-key rate limits, Redis-backed quotas per hour/day/month and the endpoints each plan includes. Generates the Enterprise API keys with one role per plan, tiered rate limits, quotas and endpoint role restrictions, plus a table mapping every plan to its configuration. Optionally applies it to a configuration.",
+		},
+		ModelAPIPlans,
+	)
+}
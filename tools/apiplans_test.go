@@ -0,0 +1,77 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestModelAPIPlans(t *testing.T) {
+	setMockFeatureFetcher(t, minimalFeatureYAML)
+	config := `{"version": 3, "endpoints": [
+		{"endpoint": "/search"},
+		{"endpoint": "/reports"},
+		{"endpoint": "/__health"}
+	]}`
+	_, output, err := ModelAPIPlans(context.Background(), nil, ModelAPIPlansInput{
+		Plans: []APIPlan{
+			{Name: "free", RateLimit: 1, Quota: 1000, Endpoints: []string{"/search"}},
+			{Name: "pro", RateLimit: 20, Quota: 100000, QuotaUnit: "day", Endpoints: []string{"/search", "/reports"}},
+			{Name: "enterprise", RateLimit: 200, Endpoints: []string{"/search", "/reports", "/export"}},
+		},
+		Config: config,
+	})
+	if err != nil {
+		t.Fatalf("ModelAPIPlans() error = %v", err)
+	}
+
+	if !strings.Contains(output.Table, "| pro | pro | 20 req/1s | 100000 req/day | /search, /reports |") {
+		t.Errorf("Unexpected plans table:\n%s", output.Table)
+	}
+	keys := output.ServiceExtraConfig["auth/api-keys"].(map[string]interface{})
+	if keys["propagate_role"] != "X-Plan" || len(keys["keys"].([]interface{})) != 3 {
+		t.Errorf("Unexpected API keys: %v", keys)
+	}
+	rules := output.ServiceExtraConfig["governance/processors"].(map[string]interface{})["quotas"].([]interface{})[0].(map[string]interface{})["rules"].([]interface{})
+	if len(rules) != 2 {
+		t.Errorf("Expected quota rules for free and pro only, got %v", rules)
+	}
+
+	endpoints := output.Config["endpoints"].([]interface{})
+	reports := endpoints[1].(map[string]interface{})["extra_config"].(map[string]interface{})
+	if roles := reports["auth/api-keys"].(map[string]interface{})["roles"].([]interface{}); len(roles) != 2 || roles[0] != "pro" {
+		t.Errorf("Expected /reports to allow pro and enterprise, got %v", roles)
+	}
+	if tiers := reports["governance/quota"].(map[string]interface{})["tiers"].([]interface{}); len(tiers) != 1 {
+		t.Errorf("Expected only the pro quota tier on /reports, got %v", tiers)
+	}
+
+	warnings := strings.Join(output.Warnings, "\n")
+	for _, want := range []string{"GET /__health is in no plan", "/export, which is not declared"} {
+		if !strings.Contains(warnings, want) {
+			t.Errorf("Expected a warning containing %q, got %v", want, output.Warnings)
+		}
+	}
+}
+
+func TestModelAPIPlans_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input ModelAPIPlansInput
+		want  string
+	}{
+		{"community edition", ModelAPIPlansInput{Plans: []APIPlan{{Name: "free", RateLimit: 1}}, Edition: "ce"}, "Enterprise"},
+		{"no plans", ModelAPIPlansInput{}, "at least one plan"},
+		{"invalid name", ModelAPIPlansInput{Plans: []APIPlan{{Name: "free plan", RateLimit: 1}}}, "invalid plan name"},
+		{"invalid unit", ModelAPIPlansInput{Plans: []APIPlan{{Name: "free", RateLimit: 1, Quota: 10, QuotaUnit: "minute"}}}, "invalid quota_unit"},
+		{"no rate limit", ModelAPIPlansInput{Plans: []APIPlan{{Name: "free"}}}, "rate_limit"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := ModelAPIPlans(context.Background(), nil, tt.input)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected an error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,187 @@
+package tools
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/krakend/mcp-server/internal/toolkit"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	auditHistoryFile           = "history/audits.jsonl"
+	defaultAuditHistoryEntries = 20
+)
+
+// auditHistoryMu serializes access to the audit history file
+var auditHistoryMu sync.Mutex
+
+// AuditHistoryEntry is a scheduled validation and audit of a configuration
+type AuditHistoryEntry struct {
+	Time             time.Time      `json:"time"`
+	Schedule         string         `json:"schedule"`
+	Config           string         `json:"config"`
+	Valid            bool           `json:"valid"`
+	ValidationMethod string         `json:"validation_method,omitempty"`
+	Errors           int            `json:"errors"`
+	Warnings         int            `json:"warnings"`
+	AuditMethod      string         `json:"audit_method,omitempty"`
+	Score            int            `json:"score,omitempty"`
+	Issues           map[string]int `json:"issues,omitempty"` // Audit issues per severity
+	Error            string         `json:"error,omitempty"`  // The run could not complete
+}
+
+// AuditTrend summarizes the history of a configuration
+type AuditTrend struct {
+	Config     string    `json:"config"`
+	Runs       int       `json:"runs"`
+	FirstScore int       `json:"first_score,omitempty"`
+	LastScore  int       `json:"last_score,omitempty"`
+	ScoreDelta int       `json:"score_delta"`
+	Failures   int       `json:"failures"` // Runs where the validation failed
+	LastValid  bool      `json:"last_valid"`
+	LastRun    time.Time `json:"last_run"`
+}
+
+// GetAuditHistoryInput defines input for get_audit_history tool
+type GetAuditHistoryInput struct {
+	Config string `json:"config,omitempty" jsonschema:"Only the history of this configuration file (optional)"`
+	Limit  int    `json:"limit,omitempty" jsonschema:"Maximum number of runs returned, newest first (optional, defaults to 20)"`
+	Since  string `json:"since,omitempty" jsonschema:"Only runs in this period, e.g. 24h or 168h (optional)"`
+}
+
+// GetAuditHistoryOutput defines output for get_audit_history tool
+type GetAuditHistoryOutput struct {
+	Entries []AuditHistoryEntry `json:"entries"`
+	Trends  []AuditTrend        `json:"trends"`
+	Total   int                 `json:"total"` // Runs matching the filters, before the limit
+	Summary string              `json:"summary"`
+}
+
+// appendAuditHistory adds a run to the audit history file
+func appendAuditHistory(entry AuditHistoryEntry) error {
+	path := filepath.Join(dataDir, auditHistoryFile)
+	auditHistoryMu.Lock()
+	defer auditHistoryMu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to create audit history directory: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit history: %w", err)
+	}
+	if err := json.NewEncoder(f).Encode(entry); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write audit history: %w", err)
+	}
+	return f.Close()
+}
+
+// loadAuditHistory reads every run of the audit history file, oldest first
+func loadAuditHistory() ([]AuditHistoryEntry, error) {
+	auditHistoryMu.Lock()
+	defer auditHistoryMu.Unlock()
+	f, err := os.Open(filepath.Join(dataDir, auditHistoryFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to open audit history: %w", err)
+	}
+	defer f.Close()
+
+	var entries []AuditHistoryEntry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry AuditHistoryEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue // Skip corrupted lines
+		}
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
+
+// GetAuditHistory returns the scheduled validations and audits with the trend of every configuration
+func GetAuditHistory(ctx context.Context, req *mcp.CallToolRequest, input GetAuditHistoryInput) (*mcp.CallToolResult, GetAuditHistoryOutput, error) {
+	limit := input.Limit
+	if limit <= 0 {
+		limit = defaultAuditHistoryEntries
+	}
+	var since time.Time
+	if input.Since != "" {
+		period, err := time.ParseDuration(input.Since)
+		if err != nil || period <= 0 {
+			return nil, GetAuditHistoryOutput{}, fmt.Errorf("invalid since %q", input.Since)
+		}
+		since = time.Now().Add(-period)
+	}
+	history, err := loadAuditHistory()
+	if err != nil {
+		return nil, GetAuditHistoryOutput{}, err
+	}
+
+	output := GetAuditHistoryOutput{Entries: []AuditHistoryEntry{}, Trends: []AuditTrend{}}
+	trends := map[string]*AuditTrend{}
+	for _, entry := range history {
+		if (input.Config != "" && entry.Config != input.Config) || entry.Time.Before(since) {
+			continue
+		}
+		output.Total++
+		output.Entries = append(output.Entries, entry)
+
+		trend, ok := trends[entry.Config]
+		if !ok {
+			trend = &AuditTrend{Config: entry.Config}
+			trends[entry.Config] = trend
+		}
+		trend.Runs++
+		if entry.Error == "" && !entry.Valid {
+			trend.Failures++
+		}
+		if entry.Score > 0 {
+			if trend.FirstScore == 0 {
+				trend.FirstScore = entry.Score
+			}
+			trend.LastScore = entry.Score
+		}
+		trend.LastValid, trend.LastRun = entry.Valid, entry.Time
+	}
+
+	// Newest first
+	for i, j := 0, len(output.Entries)-1; i < j; i, j = i+1, j-1 {
+		output.Entries[i], output.Entries[j] = output.Entries[j], output.Entries[i]
+	}
+	if len(output.Entries) > limit {
+		output.Entries = output.Entries[:limit]
+	}
+	for _, trend := range trends {
+		trend.ScoreDelta = trend.LastScore - trend.FirstScore
+		output.Trends = append(output.Trends, *trend)
+	}
+	sort.Slice(output.Trends, func(i, j int) bool { return output.Trends[i].Config < output.Trends[j].Config })
+
+	output.Summary = fmt.Sprintf("%d scheduled run(s) of %d configuration(s)", output.Total, len(output.Trends))
+	if output.Total == 0 {
+		output.Summary = "No scheduled run recorded: declare audit schedules in the server configuration file (KRAKEND_MCP_SERVER_CONFIG)"
+	}
+	return nil, output, nil
+}
+
+// RegisterAuditHistoryTools registers the audit history tool
+func RegisterAuditHistoryTools(server *mcp.Server) {
+	toolkit.AddTool(server,
+		&mcp.Tool{
+			Name:        "get_audit_history",
+			Description: "Show the validations and security audits run in the background by the audit schedules of the server: newest runs first, with the security score trend, validation failures and last result of every configuration.",
+		},
+		GetAuditHistory,
+	)
+}
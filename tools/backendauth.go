@@ -0,0 +1,298 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/krakend/mcp-server/internal/toolkit"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const tokenTestTimeout = 5 * time.Second
+
+// backendAuthTypes maps each supported backend authentication to its namespace and edition
+var backendAuthTypes = map[string]struct {
+	namespace string
+	edition   string
+}{
+	"client-credentials": {"auth/client-credentials", "ce"},
+	"api-key":            {"modifier/martian", "ce"},
+	"aws-sigv4":          {"auth/aws-sigv4", "ee"},
+}
+
+// GenerateBackendAuthInput defines input for generate_backend_auth tool
+type GenerateBackendAuthInput struct {
+	Type            string   `json:"type" jsonschema:"Backend authentication: client-credentials, api-key or aws-sigv4 (Enterprise)"`
+	Config          string   `json:"config,omitempty" jsonschema:"KrakenD configuration to update (optional, JSON string or file path)"`
+	ConfigEncoding  string   `json:"config_encoding,omitempty" jsonschema:"Encoding of config for large configurations: base64 or gzip+base64 (optional, defaults to a plain JSON string or file path)"`
+	Endpoint        string   `json:"endpoint,omitempty" jsonschema:"Endpoint whose backends get the authentication (required with config)"`
+	Method          string   `json:"method,omitempty" jsonschema:"Endpoint method when the path is declared for several methods (optional)"`
+	Backends        []int    `json:"backends,omitempty" jsonschema:"Indexes of the backends to update (optional, defaults to every backend of the endpoint)"`
+	Edition         string   `json:"edition,omitempty" jsonschema:"Target edition: ce or ee (optional, detected from the config)"`
+	TokenURL        string   `json:"token_url,omitempty" jsonschema:"OAuth token endpoint (client-credentials)"`
+	Scopes          []string `json:"scopes,omitempty" jsonschema:"OAuth scopes requested (client-credentials, optional)"`
+	ClientIDEnv     string   `json:"client_id_env,omitempty" jsonschema:"Environment variable holding the client ID (client-credentials, optional, defaults to BACKEND_CLIENT_ID)"`
+	ClientSecretEnv string   `json:"client_secret_env,omitempty" jsonschema:"Environment variable holding the client secret (client-credentials, optional, defaults to BACKEND_CLIENT_SECRET)"`
+	Header          string   `json:"header,omitempty" jsonschema:"Header carrying the API key (api-key, optional, defaults to X-Api-Key)"`
+	APIKeyEnv       string   `json:"api_key_env,omitempty" jsonschema:"Environment variable holding the API key (api-key, optional, defaults to BACKEND_API_KEY)"`
+	Region          string   `json:"region,omitempty" jsonschema:"AWS region (aws-sigv4)"`
+	Service         string   `json:"service,omitempty" jsonschema:"AWS service name to sign for, e.g. lambda or execute-api (aws-sigv4)"`
+	AssumeRoleARN   string   `json:"assume_role_arn,omitempty" jsonschema:"IAM role assumed to sign requests (aws-sigv4, optional)"`
+	TestConnection  bool     `json:"test_connection,omitempty" jsonschema:"Request a token from the token endpoint to check it is reachable (client-credentials, optional)"`
+}
+
+// TokenEndpointTest is the result of requesting a token with the client credentials
+type TokenEndpointTest struct {
+	URL         string `json:"url"`
+	Reachable   bool   `json:"reachable"`
+	StatusCode  int    `json:"status_code,omitempty"`
+	TokenIssued bool   `json:"token_issued"`
+	Message     string `json:"message"`
+}
+
+// GenerateBackendAuthOutput defines output for generate_backend_auth tool
+type GenerateBackendAuthOutput struct {
+	Type        string                 `json:"type"`
+	Namespace   string                 `json:"namespace"`
+	Edition     string                 `json:"edition"`
+	ExtraConfig map[string]interface{} `json:"extra_config"` // Backend extra_config entry
+	Config      map[string]interface{} `json:"config,omitempty"`
+	Changes     []string               `json:"changes,omitempty"` // JSON paths modified
+	Env         []string               `json:"env"`               // Environment variables to provide at runtime
+	TokenTest   *TokenEndpointTest     `json:"token_test,omitempty"`
+	Warnings    []string               `json:"warnings,omitempty"`
+	Notes       []string               `json:"notes"`
+}
+
+// envTemplate references an environment variable from a Flexible Configuration template.
+// Backquotes keep the expression valid inside a JSON string.
+func envTemplate(name string) string {
+	return "{{ env `" + name + "` }}"
+}
+
+// GenerateBackendAuth generates the backend extra_config authenticating KrakenD against its backends
+func GenerateBackendAuth(ctx context.Context, req *mcp.CallToolRequest, input GenerateBackendAuthInput) (*mcp.CallToolResult, GenerateBackendAuthOutput, error) {
+	authType, ok := backendAuthTypes[input.Type]
+	if !ok {
+		return nil, GenerateBackendAuthOutput{}, fmt.Errorf("unsupported type %q: use client-credentials, api-key or aws-sigv4", input.Type)
+	}
+	output := GenerateBackendAuthOutput{
+		Type:        input.Type,
+		Namespace:   authType.namespace,
+		Edition:     authType.edition,
+		ExtraConfig: map[string]interface{}{},
+		Env:         []string{},
+	}
+
+	var config map[string]interface{}
+	var configContent string
+	if input.Config != "" {
+		var err error
+		if configContent, err = readEncodedConfig(input.Config, input.ConfigEncoding); err != nil {
+			return nil, GenerateBackendAuthOutput{}, fmt.Errorf("failed to read config: %w", err)
+		}
+		if err := json.Unmarshal([]byte(configContent), &config); err != nil {
+			return nil, GenerateBackendAuthOutput{}, fmt.Errorf("invalid JSON: %w", err)
+		}
+	}
+
+	// Edition gating: Enterprise-only signers cannot target a Community gateway
+	if authType.edition == "ee" {
+		switch {
+		case input.Edition == "ce":
+			return nil, GenerateBackendAuthOutput{}, fmt.Errorf("%s requires KrakenD Enterprise (%s is not available in the Community Edition)", input.Type, authType.namespace)
+		case input.Edition == "" && config != nil && !DetectEnterpriseFeatures(configContent):
+			output.Warnings = append(output.Warnings, fmt.Sprintf("%s is an Enterprise feature and the configuration uses no other Enterprise feature: run it with the krakend-ee image", authType.namespace))
+		}
+	}
+
+	switch input.Type {
+	case "client-credentials":
+		if input.TokenURL == "" {
+			return nil, GenerateBackendAuthOutput{}, fmt.Errorf("token_url is required for client-credentials")
+		}
+		idEnv := envOrDefault(input.ClientIDEnv, "BACKEND_CLIENT_ID")
+		secretEnv := envOrDefault(input.ClientSecretEnv, "BACKEND_CLIENT_SECRET")
+		settings := map[string]interface{}{
+			"client_id":     envTemplate(idEnv),
+			"client_secret": envTemplate(secretEnv),
+			"token_url":     input.TokenURL,
+		}
+		if len(input.Scopes) > 0 {
+			settings["scopes"] = strings.Join(input.Scopes, ",")
+		}
+		output.ExtraConfig[authType.namespace] = settings
+		output.Env = append(output.Env, idEnv, secretEnv)
+		output.Notes = append(output.Notes, "KrakenD requests a token with the client credentials grant, caches it until it expires and sends it as Authorization: Bearer to the backend.")
+		if input.TestConnection {
+			output.TokenTest = testTokenEndpoint(ctx, input.TokenURL, os.Getenv(idEnv), os.Getenv(secretEnv), input.Scopes)
+		}
+
+	case "api-key":
+		header := envOrDefault(input.Header, "X-Api-Key")
+		keyEnv := envOrDefault(input.APIKeyEnv, "BACKEND_API_KEY")
+		output.ExtraConfig[authType.namespace] = map[string]interface{}{
+			"header.Modifier": map[string]interface{}{
+				"scope": []interface{}{"request"},
+				"name":  header,
+				"value": envTemplate(keyEnv),
+			},
+		}
+		output.Env = append(output.Env, keyEnv)
+		output.Notes = append(output.Notes, fmt.Sprintf("The martian header.Modifier adds the %s header to every request sent to the backend; consumers never see the key.", header))
+
+	case "aws-sigv4":
+		if input.Region == "" || input.Service == "" {
+			return nil, GenerateBackendAuthOutput{}, fmt.Errorf("region and service are required for aws-sigv4")
+		}
+		settings := map[string]interface{}{"region": input.Region, "service": input.Service}
+		if input.AssumeRoleARN != "" {
+			settings["assume_role_arn"] = input.AssumeRoleARN
+		}
+		output.ExtraConfig[authType.namespace] = settings
+		output.Env = append(output.Env, "AWS_ACCESS_KEY_ID", "AWS_SECRET_ACCESS_KEY")
+		output.Notes = append(output.Notes, "Credentials follow the AWS default chain: environment variables, shared credentials file or the instance/task role. No secret is stored in the configuration.")
+	}
+	if input.Type != "aws-sigv4" {
+		output.Notes = append(output.Notes, "Secrets are read from environment variables through Flexible Configuration: start KrakenD with FC_ENABLE=1 so the {{ env }} expressions are rendered, and never commit the values.")
+	}
+
+	if config == nil {
+		return nil, output, nil
+	}
+	if input.Endpoint == "" {
+		return nil, GenerateBackendAuthOutput{}, fmt.Errorf("endpoint is required to update a configuration")
+	}
+	endpoint, err := findEndpoint(config, input.Endpoint, input.Method)
+	if err != nil {
+		return nil, GenerateBackendAuthOutput{}, err
+	}
+	index := endpointIndex(config, endpoint)
+	backends, _ := endpoint["backend"].([]interface{})
+	targets := input.Backends
+	if len(targets) == 0 {
+		for i := range backends {
+			targets = append(targets, i)
+		}
+	}
+	for _, i := range targets {
+		if i < 0 || i >= len(backends) {
+			return nil, GenerateBackendAuthOutput{}, fmt.Errorf("backend %d does not exist in %s (%d backend(s))", i, input.Endpoint, len(backends))
+		}
+		backend, ok := backends[i].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		extra, _ := backend["extra_config"].(map[string]interface{})
+		if extra == nil {
+			extra = map[string]interface{}{}
+			backend["extra_config"] = extra
+		}
+		location := fmt.Sprintf("$.endpoints[%d].backend[%d].extra_config['%s']", index, i, authType.namespace)
+		value := output.ExtraConfig[authType.namespace]
+		if existing, ok := extra[authType.namespace].(map[string]interface{}); ok {
+			if input.Type != "api-key" {
+				output.Warnings = append(output.Warnings, fmt.Sprintf("%s already existed and was replaced", location))
+			} else {
+				// Keep the existing martian modifiers and add the header after them
+				value = map[string]interface{}{
+					"fifo.Group": map[string]interface{}{
+						"scope":           []interface{}{"request", "response"},
+						"aggregateErrors": true,
+						"modifiers":       []interface{}{existing, value},
+					},
+				}
+				output.Warnings = append(output.Warnings, fmt.Sprintf("%s already had martian modifiers: both were grouped in a fifo.Group", location))
+			}
+		}
+		extra[authType.namespace] = value
+		output.Changes = append(output.Changes, location)
+	}
+	output.Config = config
+	return nil, output, nil
+}
+
+// envOrDefault returns value, or fallback when empty
+func envOrDefault(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
+
+// endpointIndex returns the position of an endpoint in the configuration
+func endpointIndex(config, endpoint map[string]interface{}) int {
+	endpoints, _ := config["endpoints"].([]interface{})
+	for i, ep := range endpoints {
+		if e, ok := ep.(map[string]interface{}); ok && e["endpoint"] == endpoint["endpoint"] && endpointMethod(e) == endpointMethod(endpoint) {
+			return i
+		}
+	}
+	return -1
+}
+
+// testTokenEndpoint requests a token with the client credentials grant. Missing credentials
+// still tell whether the endpoint is reachable.
+func testTokenEndpoint(ctx context.Context, tokenURL, clientID, clientSecret string, scopes []string) *TokenEndpointTest {
+	test := &TokenEndpointTest{URL: tokenURL}
+	form := url.Values{"grant_type": {"client_credentials"}}
+	if len(scopes) > 0 {
+		form.Set("scope", strings.Join(scopes, " "))
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, tokenTestTimeout)
+	defer cancel()
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		test.Message = fmt.Sprintf("invalid token_url: %v", err)
+		return test
+	}
+	httpReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if clientID != "" {
+		httpReq.SetBasicAuth(url.QueryEscape(clientID), url.QueryEscape(clientSecret))
+	}
+
+	resp, err := http.DefaultClient.Do(httpReq)
+	if err != nil {
+		test.Message = fmt.Sprintf("token endpoint unreachable: %v", err)
+		return test
+	}
+	defer resp.Body.Close()
+	test.Reachable = true
+	test.StatusCode = resp.StatusCode
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	switch {
+	case resp.StatusCode == http.StatusOK && json.Unmarshal(body, &token) == nil && token.AccessToken != "":
+		test.TokenIssued = true
+		test.Message = "Token issued with the client credentials"
+	case clientID == "":
+		test.Message = fmt.Sprintf("Token endpoint reachable (HTTP %d); set the client credentials environment variables to test them", resp.StatusCode)
+	case resp.StatusCode == http.StatusBadRequest || resp.StatusCode == http.StatusUnauthorized:
+		test.Message = fmt.Sprintf("Token endpoint reachable but the credentials were rejected (HTTP %d)", resp.StatusCode)
+	default:
+		test.Message = fmt.Sprintf("Token endpoint answered HTTP %d without a token", resp.StatusCode)
+	}
+	return test
+}
+
+// RegisterBackendAuthTools registers backend authentication generation tools
+func RegisterBackendAuthTools(server *mcp.Server) {
+	toolkit.AddTool(server,
+		&mcp.Tool{
+			Name:        "generate_backend_auth",
+			Description: "Generate the backend extra_config authenticating KrakenD against its backends: OAuth client credentials token injection (auth/client-credentials), a static API key header read from an environment variable (martian header.Modifier), or AWS SigV4 request signing (Enterprise). Optionally applies it to the backends of an endpoint and tests the token endpoint.",
+		},
+		GenerateBackendAuth,
+	)
+}
@@ -0,0 +1,94 @@
+package tools
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestGenerateBackendAuth_ClientCredentials(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("grant_type") != "client_credentials" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if id, secret, ok := r.BasicAuth(); !ok || id != "gateway" || secret != "s3cret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"access_token": "abc", "token_type": "bearer"}`))
+	}))
+	defer server.Close()
+	t.Setenv("BACKEND_CLIENT_ID", "gateway")
+	t.Setenv("BACKEND_CLIENT_SECRET", "s3cret")
+
+	config := `{"version": 3, "endpoints": [{"endpoint": "/orders", "backend": [
+		{"url_pattern": "/orders", "host": ["http://orders"]},
+		{"url_pattern": "/stock", "host": ["http://stock"]}
+	]}]}`
+	_, output, err := GenerateBackendAuth(context.Background(), nil, GenerateBackendAuthInput{
+		Type:           "client-credentials",
+		Config:         config,
+		Endpoint:       "/orders",
+		Backends:       []int{1},
+		TokenURL:       server.URL + "/token",
+		Scopes:         []string{"stock:read"},
+		TestConnection: true,
+	})
+	if err != nil {
+		t.Fatalf("GenerateBackendAuth() error = %v", err)
+	}
+
+	settings := output.ExtraConfig["auth/client-credentials"].(map[string]interface{})
+	if settings["client_secret"] != "{{ env `BACKEND_CLIENT_SECRET` }}" || settings["scopes"] != "stock:read" {
+		t.Errorf("Unexpected settings: %v", settings)
+	}
+	if len(output.Changes) != 1 || output.Changes[0] != "$.endpoints[0].backend[1].extra_config['auth/client-credentials']" {
+		t.Errorf("Expected only the second backend to change, got %v", output.Changes)
+	}
+	backends := output.Config["endpoints"].([]interface{})[0].(map[string]interface{})["backend"].([]interface{})
+	if _, ok := backends[0].(map[string]interface{})["extra_config"]; ok {
+		t.Error("The first backend must be left untouched")
+	}
+	if output.TokenTest == nil || !output.TokenTest.TokenIssued {
+		t.Errorf("Expected a token to be issued, got %+v", output.TokenTest)
+	}
+
+	t.Setenv("BACKEND_CLIENT_SECRET", "wrong")
+	_, output, _ = GenerateBackendAuth(context.Background(), nil, GenerateBackendAuthInput{Type: "client-credentials", TokenURL: server.URL, TestConnection: true})
+	if !output.TokenTest.Reachable || output.TokenTest.TokenIssued || !strings.Contains(output.TokenTest.Message, "rejected") {
+		t.Errorf("Expected rejected credentials, got %+v", output.TokenTest)
+	}
+}
+
+func TestGenerateBackendAuth_APIKeyKeepsMartian(t *testing.T) {
+	config := `{"version": 3, "endpoints": [{"endpoint": "/a", "backend": [{"url_pattern": "/a",
+		"extra_config": {"modifier/martian": {"header.Modifier": {"scope": ["request"], "name": "X-Source", "value": "gw"}}}}]}]}`
+	_, output, err := GenerateBackendAuth(context.Background(), nil, GenerateBackendAuthInput{Type: "api-key", Config: config, Endpoint: "/a", Header: "X-Token"})
+	if err != nil {
+		t.Fatalf("GenerateBackendAuth() error = %v", err)
+	}
+	backend := output.Config["endpoints"].([]interface{})[0].(map[string]interface{})["backend"].([]interface{})[0].(map[string]interface{})
+	group := backend["extra_config"].(map[string]interface{})["modifier/martian"].(map[string]interface{})["fifo.Group"].(map[string]interface{})
+	if len(group["modifiers"].([]interface{})) != 2 || len(output.Warnings) != 1 {
+		t.Errorf("Expected both modifiers grouped with a warning, got %v %v", group, output.Warnings)
+	}
+	if output.Env[0] != "BACKEND_API_KEY" {
+		t.Errorf("Unexpected env: %v", output.Env)
+	}
+}
+
+func TestGenerateBackendAuth_SigV4EditionGate(t *testing.T) {
+	if _, _, err := GenerateBackendAuth(context.Background(), nil, GenerateBackendAuthInput{Type: "aws-sigv4", Region: "eu-west-1", Service: "lambda", Edition: "ce"}); err == nil {
+		t.Error("Expected aws-sigv4 to be rejected for the Community Edition")
+	}
+	_, output, err := GenerateBackendAuth(context.Background(), nil, GenerateBackendAuthInput{Type: "aws-sigv4", Region: "eu-west-1", Service: "lambda", Edition: "ee"})
+	if err != nil || output.Edition != "ee" || output.ExtraConfig["auth/aws-sigv4"] == nil {
+		t.Errorf("Unexpected output: %+v (%v)", output, err)
+	}
+	if _, _, err := GenerateBackendAuth(context.Background(), nil, GenerateBackendAuthInput{Type: "aws-sigv4", Edition: "ee"}); err == nil {
+		t.Error("Expected region and service to be required")
+	}
+}
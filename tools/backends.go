@@ -0,0 +1,114 @@
+package tools
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// backendHost represents a backend host declared in a KrakenD configuration
+type backendHost struct {
+	Host      string   // Host as written in the configuration
+	SD        string   // Service discovery type ("static" when not set)
+	Locations []string // JSON paths of the backends using this host
+}
+
+// collectBackendHosts extracts the unique backend hosts declared in endpoints and async agents.
+// Hosts are returned sorted alphabetically; each host keeps every location where it is used.
+func collectBackendHosts(config map[string]interface{}) []backendHost {
+	byHost := map[string]*backendHost{}
+
+	collect := func(backends []interface{}, prefix string) {
+		for i, b := range backends {
+			backend, ok := b.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			sd := "static"
+			if s, ok := backend["sd"].(string); ok && s != "" {
+				sd = s
+			}
+			hosts, _ := backend["host"].([]interface{})
+			location := fmt.Sprintf("%s.backend[%d]", prefix, i)
+			for _, h := range hosts {
+				host, ok := h.(string)
+				if !ok || host == "" {
+					continue
+				}
+				key := sd + "|" + host
+				if existing, ok := byHost[key]; ok {
+					existing.Locations = append(existing.Locations, location)
+					continue
+				}
+				byHost[key] = &backendHost{Host: host, SD: sd, Locations: []string{location}}
+			}
+		}
+	}
+
+	if endpoints, ok := config["endpoints"].([]interface{}); ok {
+		for i, ep := range endpoints {
+			if endpoint, ok := ep.(map[string]interface{}); ok {
+				backends, _ := endpoint["backend"].([]interface{})
+				collect(backends, fmt.Sprintf("$.endpoints[%d]", i))
+			}
+		}
+	}
+
+	if agents, ok := config["async_agent"].([]interface{}); ok {
+		for i, a := range agents {
+			if agent, ok := a.(map[string]interface{}); ok {
+				backends, _ := agent["backend"].([]interface{})
+				collect(backends, fmt.Sprintf("$.async_agent[%d]", i))
+			}
+		}
+	}
+
+	hosts := make([]backendHost, 0, len(byHost))
+	for _, h := range byHost {
+		hosts = append(hosts, *h)
+	}
+	sort.Slice(hosts, func(i, j int) bool {
+		if hosts[i].Host == hosts[j].Host {
+			return hosts[i].SD < hosts[j].SD
+		}
+		return hosts[i].Host < hosts[j].Host
+	})
+	return hosts
+}
+
+// splitBackendHost parses a backend host into scheme, hostname and port.
+// Hosts without scheme default to http, as KrakenD does.
+func splitBackendHost(host string) (scheme, hostname, port string, err error) {
+	raw := host
+	if !strings.Contains(raw, "://") {
+		raw = "http://" + raw
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", "", "", fmt.Errorf("invalid host %q: %w", host, err)
+	}
+	if u.Hostname() == "" {
+		return "", "", "", fmt.Errorf("invalid host %q: missing hostname", host)
+	}
+
+	scheme = u.Scheme
+	hostname = u.Hostname()
+	port = u.Port()
+	if port == "" {
+		switch scheme {
+		case "https", "wss":
+			port = "443"
+		default:
+			port = "80"
+		}
+	}
+	return scheme, hostname, port, nil
+}
+
+// backendAddress returns the host:port address to dial for a backend host
+func backendAddress(hostname, port string) string {
+	return net.JoinHostPort(hostname, port)
+}
@@ -0,0 +1,505 @@
+package tools
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/krakend/mcp-server/internal/toolkit"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	defaultSampleDuration = time.Minute
+	maxCanaryRejections   = 100
+)
+
+// sampleLinePattern matches a plain "METHOD /path" request line, as written in access logs
+// ("GET /path HTTP/1.1") or by hand
+var sampleLinePattern = regexp.MustCompile(`\b(GET|POST|PUT|PATCH|DELETE|HEAD|OPTIONS)\s+"?(/[^\s"]*)`)
+
+// sampleTimePattern matches the time of a default-format access log line ("2024/01/02 - 15:04:05")
+var sampleTimePattern = regexp.MustCompile(`\d{4}/\d{2}/\d{2} - \d{2}:\d{2}:\d{2}`)
+
+// ReplaySampleTrafficInput defines input for replay_sample_traffic tool
+type ReplaySampleTrafficInput struct {
+	Config         string `json:"config" jsonschema:"KrakenD configuration about to ship (JSON string or file path)"`
+	Samples        string `json:"samples" jsonschema:"Recorded requests (text or file path): KrakenD access logs, one JSON object per line with method, path, headers, client_ip, timestamp and status, a JSON array of those objects, or METHOD /path lines followed by tab-separated Name: value headers"`
+	SampleDuration string `json:"sample_duration,omitempty" jsonschema:"Time span the samples were recorded over, used when they carry no timestamps (optional, defaults to 1m)"`
+}
+
+// TrafficSample is a recorded request
+type TrafficSample struct {
+	Method    string            `json:"method"`
+	Path      string            `json:"path"` // Path and query string
+	Headers   map[string]string `json:"headers,omitempty"`
+	ClientIP  string            `json:"client_ip,omitempty"`
+	Timestamp string            `json:"timestamp,omitempty"` // RFC 3339
+	Status    int               `json:"status,omitempty"`    // Status recorded in production
+}
+
+// RejectedSample is a recorded request the new configuration would reject
+type RejectedSample struct {
+	Line           int    `json:"line"`
+	Method         string `json:"method"`
+	Path           string `json:"path"`
+	Endpoint       string `json:"endpoint,omitempty"`
+	Status         int    `json:"status"` // 404, 401 or 429
+	Reason         string `json:"reason"`
+	RecordedStatus int    `json:"recorded_status,omitempty"`
+	Regression     bool   `json:"regression"` // Succeeded when recorded
+}
+
+// ReplaySampleTrafficOutput defines output for replay_sample_traffic tool
+type ReplaySampleTrafficOutput struct {
+	Requests    int              `json:"requests"`
+	Unparsed    int              `json:"unparsed"`
+	Statuses    map[string]int   `json:"statuses"` // Predicted status per request, 200 meaning forwarded to the backends
+	Rejected    []RejectedSample `json:"rejected"`
+	Truncated   bool             `json:"truncated,omitempty"` // More rejections than listed
+	Regressions int              `json:"regressions"`
+	Window      string           `json:"window"` // Time span replayed against the rate limits
+	Warnings    []string         `json:"warnings,omitempty"`
+	Notes       []string         `json:"notes"`
+	Summary     string           `json:"summary"`
+}
+
+// replaySample is a parsed sample with its replay time
+type replaySample struct {
+	TrafficSample
+	line    int
+	headers http.Header
+	at      time.Time
+}
+
+// tokenBucket mirrors the rate limiter of KrakenD: capacity tokens refilled at rate per every
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// rateLimitRule is a rate limit of the service or an endpoint, shared or per client
+type rateLimitRule struct {
+	scope    string // Description used in the rejection reason
+	rate     float64
+	capacity float64
+	every    time.Duration
+	client   func(s replaySample, pattern string) string // nil for the shared limit
+	buckets  map[string]*tokenBucket
+}
+
+// allow consumes a token of the bucket of a client, reporting whether one was left
+func (r *rateLimitRule) allow(key string, at time.Time) bool {
+	bucket, ok := r.buckets[key]
+	if !ok {
+		bucket = &tokenBucket{tokens: r.capacity, last: at}
+		r.buckets[key] = bucket
+	}
+	if elapsed := at.Sub(bucket.last); elapsed > 0 {
+		bucket.tokens = math.Min(r.capacity, bucket.tokens+elapsed.Seconds()*r.rate/r.every.Seconds())
+		bucket.last = at
+	}
+	if bucket.tokens < 1 {
+		return false
+	}
+	bucket.tokens--
+	return true
+}
+
+// rateLimitRules reads the shared and per-client limits of a qos/ratelimit/service or
+// qos/ratelimit/router configuration
+func rateLimitRules(settings map[string]interface{}, scope string) []*rateLimitRule {
+	if settings == nil {
+		return nil
+	}
+	every := time.Second
+	if raw, ok := settings["every"].(string); ok {
+		if d, err := time.ParseDuration(raw); err == nil && d > 0 {
+			every = d
+		}
+	}
+	var rules []*rateLimitRule
+	if rate, ok := settings["max_rate"].(float64); ok && rate > 0 {
+		capacity, _ := settings["capacity"].(float64)
+		if capacity <= 0 {
+			capacity = rate
+		}
+		rules = append(rules, &rateLimitRule{scope: scope, rate: rate, capacity: capacity, every: every, buckets: map[string]*tokenBucket{}})
+	}
+	if rate, ok := settings["client_max_rate"].(float64); ok && rate > 0 {
+		capacity, _ := settings["client_capacity"].(float64)
+		if capacity <= 0 {
+			capacity = rate
+		}
+		strategy, _ := settings["strategy"].(string)
+		key, _ := settings["key"].(string)
+		rules = append(rules, &rateLimitRule{
+			scope:    scope + " per client",
+			rate:     rate,
+			capacity: capacity,
+			every:    every,
+			client:   sampleClient(strategy, key),
+			buckets:  map[string]*tokenBucket{},
+		})
+	}
+	return rules
+}
+
+// sampleClient returns how a rate limit strategy identifies the client of a sample
+func sampleClient(strategy, key string) func(s replaySample, pattern string) string {
+	switch strategy {
+	case "header":
+		return func(s replaySample, _ string) string { return s.headers.Get(key) }
+	case "param":
+		return func(s replaySample, pattern string) string {
+			patternSegments := strings.Split(strings.Trim(pattern, "/"), "/")
+			pathSegments := strings.Split(strings.Trim(samplePath(s.Path), "/"), "/")
+			for i, segment := range patternSegments {
+				if strings.EqualFold(segment, "{"+key+"}") && i < len(pathSegments) {
+					return pathSegments[i]
+				}
+			}
+			return ""
+		}
+	}
+	return func(s replaySample, _ string) string {
+		if s.ClientIP != "" {
+			return s.ClientIP
+		}
+		forwarded, _, _ := strings.Cut(s.headers.Get("X-Forwarded-For"), ",")
+		return strings.TrimSpace(forwarded)
+	}
+}
+
+// samplePath strips the query string of a recorded path
+func samplePath(path string) string {
+	p, _, _ := strings.Cut(path, "?")
+	return p
+}
+
+// parseTrafficSamples reads the recorded requests in any of the supported formats, returning
+// the number of entries that contain no request
+func parseTrafficSamples(content string) ([]replaySample, int, error) {
+	var list []TrafficSample
+	if json.Unmarshal([]byte(content), &list) == nil {
+		var samples []replaySample
+		unparsed := 0
+		for i, s := range list {
+			if s.Method == "" || s.Path == "" {
+				unparsed++
+				continue
+			}
+			samples = append(samples, replaySample{TrafficSample: s, line: i + 1})
+		}
+		return samples, unparsed, nil
+	}
+
+	var samples []replaySample
+	unparsed := 0
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if sample, ok := parseSampleLine(line); ok {
+			sample.line = n
+			samples = append(samples, sample)
+		} else {
+			unparsed++
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, 0, fmt.Errorf("failed to read samples: %w", err)
+	}
+	return samples, unparsed, nil
+}
+
+// parseSampleLine parses a JSON sample, a structured or default-format access log record, or
+// a METHOD /path line with tab-separated headers
+func parseSampleLine(line string) (replaySample, bool) {
+	message := line
+	var sample replaySample
+	if strings.HasPrefix(line, "{") {
+		var record logRecord
+		if json.Unmarshal([]byte(line), &record) == nil && record.Message != "" {
+			message, sample.Timestamp = record.Message, record.Timestamp
+		} else if json.Unmarshal([]byte(line), &sample.TrafficSample) == nil && sample.Method != "" && sample.Path != "" {
+			return sample, true
+		} else {
+			return sample, false
+		}
+	}
+
+	if m := accessLogPattern.FindStringSubmatch(message); m != nil {
+		sample.Status, _ = strconv.Atoi(m[1])
+		sample.ClientIP, sample.Method, sample.Path = m[3], m[4], m[5]
+		if sample.Timestamp == "" {
+			if ts, err := time.Parse("2006/01/02 - 15:04:05", sampleTimePattern.FindString(message)); err == nil {
+				sample.Timestamp = ts.Format(time.RFC3339)
+			}
+		}
+		return sample, true
+	}
+
+	fields := strings.Split(message, "\t")
+	m := sampleLinePattern.FindStringSubmatch(fields[0])
+	if m == nil {
+		return sample, false
+	}
+	sample.Method, sample.Path = m[1], m[2]
+	for _, field := range fields[1:] {
+		if name, value, ok := strings.Cut(field, ":"); ok {
+			if sample.Headers == nil {
+				sample.Headers = map[string]string{}
+			}
+			sample.Headers[strings.TrimSpace(name)] = strings.TrimSpace(value)
+		}
+	}
+	return sample, true
+}
+
+// missingCredentials returns the authentication mechanisms of an endpoint for which a sample
+// carries no credentials. Credentials are not verified: a present token or key is assumed valid.
+func missingCredentials(s replaySample, auth []EndpointAuth) []string {
+	var missing []string
+	query, _ := url.ParseQuery(strings.SplitN(s.Path+"?", "?", 2)[1])
+	for _, a := range auth {
+		switch a.Type {
+		case "jwt":
+			if strings.HasPrefix(s.headers.Get("Authorization"), "Bearer ") {
+				continue
+			}
+			if a.Cookie != "" {
+				if _, err := (&http.Request{Header: s.headers}).Cookie(a.Cookie); err == nil {
+					continue
+				}
+			}
+			missing = append(missing, "no JWT in the Authorization header")
+		case "api-key":
+			if a.QueryString != "" {
+				if query.Get(a.QueryString) != "" {
+					continue
+				}
+				missing = append(missing, fmt.Sprintf("no API key in the %s query string", a.QueryString))
+				continue
+			}
+			if s.headers.Get(a.Header) != "" {
+				continue
+			}
+			missing = append(missing, fmt.Sprintf("no API key in the %s header", a.Header))
+		case "basic":
+			if strings.HasPrefix(s.headers.Get("Authorization"), "Basic ") {
+				continue
+			}
+			missing = append(missing, "no Basic credentials in the Authorization header")
+		}
+	}
+	return missing
+}
+
+// declaredMethods lists the methods the endpoints matching a path are declared for
+func declaredMethods(config map[string]interface{}, path string) []string {
+	var methods []string
+	endpoints, _ := config["endpoints"].([]interface{})
+	for _, ep := range endpoints {
+		endpoint, ok := ep.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		pattern, _ := endpoint["endpoint"].(string)
+		if endpointPatternMatches(pattern, path) && !containsFold(methods, endpointMethod(endpoint)) {
+			methods = append(methods, endpointMethod(endpoint))
+		}
+	}
+	sort.Strings(methods)
+	return methods
+}
+
+// ReplaySampleTraffic checks recorded production requests against the routing, authentication
+// and rate limits of a configuration, predicting which would be rejected once it ships
+func ReplaySampleTraffic(ctx context.Context, req *mcp.CallToolRequest, input ReplaySampleTrafficInput) (*mcp.CallToolResult, ReplaySampleTrafficOutput, error) {
+	configContent, err := readConfigContent(input.Config)
+	if err != nil {
+		return nil, ReplaySampleTrafficOutput{}, fmt.Errorf("failed to read config: %w", err)
+	}
+	var config map[string]interface{}
+	if err := json.Unmarshal([]byte(configContent), &config); err != nil {
+		return nil, ReplaySampleTrafficOutput{}, fmt.Errorf("invalid JSON: %w", err)
+	}
+	if strings.TrimSpace(input.Samples) == "" {
+		return nil, ReplaySampleTrafficOutput{}, fmt.Errorf("samples are required")
+	}
+	content := input.Samples
+	trimmed := strings.TrimSpace(content)
+	if !strings.Contains(trimmed, "\n") && !strings.HasPrefix(trimmed, "{") && !strings.HasPrefix(trimmed, "[") && sampleLinePattern.FindString(trimmed) == "" {
+		data, err := os.ReadFile(trimmed)
+		if err != nil {
+			return nil, ReplaySampleTrafficOutput{}, fmt.Errorf("failed to read samples: %w", err)
+		}
+		content = string(data)
+	}
+	duration := defaultSampleDuration
+	if input.SampleDuration != "" {
+		if duration, err = time.ParseDuration(input.SampleDuration); err != nil || duration <= 0 {
+			return nil, ReplaySampleTrafficOutput{}, fmt.Errorf("invalid sample_duration %q", input.SampleDuration)
+		}
+	}
+
+	samples, unparsed, err := parseTrafficSamples(content)
+	if err != nil {
+		return nil, ReplaySampleTrafficOutput{}, err
+	}
+	if len(samples) == 0 {
+		return nil, ReplaySampleTrafficOutput{}, fmt.Errorf("no request found in the samples")
+	}
+
+	output := ReplaySampleTrafficOutput{
+		Requests: len(samples),
+		Unparsed: unparsed,
+		Statuses: map[string]int{},
+		Rejected: []RejectedSample{},
+		Notes: []string{
+			"Credentials are checked for presence only: expired or invalid tokens and keys, and missing roles (403), cannot be predicted offline.",
+			"A 200 means the request reaches the backends; backend errors, timeouts and request body validation are not simulated.",
+		},
+	}
+
+	// Replay times: the recorded timestamps, or the samples spread evenly over the duration
+	timed := true
+	for i := range samples {
+		s := &samples[i]
+		s.Method = strings.ToUpper(s.Method)
+		s.headers = http.Header{}
+		for name, value := range s.Headers {
+			s.headers.Set(name, value)
+		}
+		ts, err := time.Parse(time.RFC3339Nano, s.Timestamp)
+		if err != nil {
+			timed = false
+		}
+		s.at = ts
+	}
+	if timed {
+		sort.SliceStable(samples, func(i, j int) bool { return samples[i].at.Before(samples[j].at) })
+		output.Window = samples[len(samples)-1].at.Sub(samples[0].at).String()
+	} else {
+		start := time.Time{}
+		for i := range samples {
+			samples[i].at = start.Add(duration * time.Duration(i) / time.Duration(len(samples)))
+		}
+		output.Window = duration.String()
+		output.Notes = append(output.Notes, fmt.Sprintf("The samples carry no timestamps: they are replayed evenly over %s, so bursts that happened in production are smoothed out.", duration))
+	}
+
+	service, _ := config["extra_config"].(map[string]interface{})
+	serviceSettings, _ := service["qos/ratelimit/service"].(map[string]interface{})
+	serviceRules := rateLimitRules(serviceSettings, "service rate limit")
+	endpointRules := map[string][]*rateLimitRule{}
+	unidentified := 0
+
+	for _, s := range samples {
+		status, reason, pattern := http.StatusOK, "", ""
+		limited := func(rules []*rateLimitRule) bool {
+			for _, rule := range rules {
+				key := ""
+				if rule.client != nil {
+					if key = rule.client(s, pattern); key == "" {
+						unidentified++
+					}
+				}
+				if !rule.allow(key, s.at) {
+					status, reason = http.StatusTooManyRequests, fmt.Sprintf("%s of %s requests every %s exceeded", rule.scope, formatFloat(rule.rate), rule.every)
+					return true
+				}
+			}
+			return false
+		}
+
+		endpoint, matched := matchConfigEndpoint(config, s.Method, samplePath(s.Path))
+		if matched {
+			pattern, _ = endpoint["endpoint"].(string)
+		}
+		switch {
+		case limited(serviceRules):
+		case !matched:
+			status, reason = http.StatusNotFound, "no endpoint matches the path"
+			if methods := declaredMethods(config, samplePath(s.Path)); len(methods) > 0 {
+				reason = fmt.Sprintf("the path is only declared for %s", strings.Join(methods, ", "))
+			}
+		default:
+			if missing := missingCredentials(s, endpointAuth(config, endpoint)); len(missing) > 0 {
+				status, reason = http.StatusUnauthorized, strings.Join(missing, "; ")
+				break
+			}
+			key := s.Method + " " + pattern
+			rules, ok := endpointRules[key]
+			if !ok {
+				extra, _ := endpoint["extra_config"].(map[string]interface{})
+				settings, _ := extra["qos/ratelimit/router"].(map[string]interface{})
+				rules = rateLimitRules(settings, "endpoint rate limit")
+				endpointRules[key] = rules
+			}
+			limited(rules)
+		}
+
+		output.Statuses[strconv.Itoa(status)]++
+		if status == http.StatusOK {
+			continue
+		}
+		rejected := RejectedSample{
+			Line:           s.line,
+			Method:         s.Method,
+			Path:           s.Path,
+			Endpoint:       pattern,
+			Status:         status,
+			Reason:         reason,
+			RecordedStatus: s.Status,
+			Regression:     s.Status > 0 && s.Status < 400,
+		}
+		if rejected.Regression {
+			output.Regressions++
+		}
+		if len(output.Rejected) < maxCanaryRejections {
+			output.Rejected = append(output.Rejected, rejected)
+		} else {
+			output.Truncated = true
+		}
+	}
+
+	if unidentified > 0 {
+		output.Warnings = append(output.Warnings, fmt.Sprintf("%d request(s) lack the client identity used by a per-client rate limit (client_ip, header or parameter) and share a single bucket", unidentified))
+	}
+	if unparsed > 0 {
+		output.Warnings = append(output.Warnings, fmt.Sprintf("%d line(s) of the samples contain no request and were skipped", unparsed))
+	}
+	rejectedCount := len(samples) - output.Statuses["200"]
+	output.Summary = fmt.Sprintf("%d of %d request(s) would be rejected (%d not found, %d unauthorized, %d rate limited)",
+		rejectedCount, len(samples), output.Statuses["404"], output.Statuses["401"], output.Statuses["429"])
+	if output.Regressions > 0 {
+		output.Summary += fmt.Sprintf("; %d of them succeeded in production", output.Regressions)
+	}
+	return nil, output, nil
+}
+
+// RegisterCanaryTools registers the traffic sample replay tool
+func RegisterCanaryTools(server *mcp.Server) {
+	toolkit.AddTool(server,
+		&mcp.Tool{
+			Name:        "replay_sample_traffic",
+			Description: "Canary-check a configuration before it ships: replay recorded production requests (access logs, JSON samples or METHOD /path lines with headers) statically against its routing, authentication and rate limits, and report which would get 404, 401 or 429, flagging those that succeeded in production.",
+		},
+		ReplaySampleTraffic,
+	)
+}
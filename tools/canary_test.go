@@ -0,0 +1,84 @@
+package tools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const canaryConfig = `{"version": 3,
+	"extra_config": {"auth/api-keys": {"identifier": "X-Key", "keys": []}},
+	"endpoints": [
+		{"endpoint": "/users/{id}", "backend": [{"url_pattern": "/users/{id}"}],
+		 "extra_config": {"qos/ratelimit/router": {"client_max_rate": 2, "every": "1m", "strategy": "header", "key": "X-Key"}}},
+		{"endpoint": "/orders", "method": "POST", "backend": [{"url_pattern": "/orders"}],
+		 "extra_config": {"auth/api-keys": {"roles": ["user"]}}}
+	]}`
+
+func TestReplaySampleTraffic(t *testing.T) {
+	samples := strings.Join([]string{
+		`{"method": "GET", "path": "/users/1", "headers": {"X-Key": "a"}, "timestamp": "2026-01-01T10:00:00Z", "status": 200}`,
+		`{"method": "GET", "path": "/users/2", "headers": {"X-Key": "a"}, "timestamp": "2026-01-01T10:00:01Z", "status": 200}`,
+		`{"method": "GET", "path": "/users/3", "headers": {"X-Key": "a"}, "timestamp": "2026-01-01T10:00:02Z", "status": 200}`,
+		`{"method": "GET", "path": "/users/3", "headers": {"X-Key": "b"}, "timestamp": "2026-01-01T10:00:03Z", "status": 200}`,
+		`{"method": "POST", "path": "/orders", "timestamp": "2026-01-01T10:00:04Z", "status": 201}`,
+		`{"method": "POST", "path": "/orders", "headers": {"x-key": "k"}, "timestamp": "2026-01-01T10:00:05Z"}`,
+		`{"method": "GET", "path": "/orders", "timestamp": "2026-01-01T10:00:06Z", "status": 200}`,
+		`{"method": "GET", "path": "/legacy", "timestamp": "2026-01-01T10:00:07Z", "status": 404}`,
+		`not a request`,
+	}, "\n")
+	_, output, err := ReplaySampleTraffic(context.Background(), nil, ReplaySampleTrafficInput{Config: canaryConfig, Samples: samples})
+	if err != nil {
+		t.Fatalf("ReplaySampleTraffic() error = %v", err)
+	}
+	want := map[string]int{"200": 4, "429": 1, "401": 1, "404": 2}
+	for status, count := range want {
+		if output.Statuses[status] != count {
+			t.Errorf("Expected %d request(s) with status %s, got %v", count, status, output.Statuses)
+		}
+	}
+	if output.Requests != 8 || output.Unparsed != 1 || output.Window != "7s" {
+		t.Errorf("Unexpected counts: %+v", output)
+	}
+	if output.Regressions != 3 {
+		t.Errorf("Expected 3 regressions (429, 401 and 404 on GET /orders), got %+v", output.Rejected)
+	}
+	for _, r := range output.Rejected {
+		if r.Path == "/orders" && r.Method == "GET" && !strings.Contains(r.Reason, "only declared for POST") {
+			t.Errorf("Expected the declared methods in the reason, got %q", r.Reason)
+		}
+		if r.Path == "/legacy" && r.Regression {
+			t.Error("A request that already failed in production is not a regression")
+		}
+	}
+}
+
+func TestReplaySampleTraffic_Formats(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "samples.log")
+	logs := strings.Join([]string{
+		`[GIN] 2026/01/01 - 10:00:00 | 200 |    1.2ms |  10.0.0.1 | GET      "/users/1"`,
+		"GET /users/2\tX-Key: a",
+		"POST /orders?page=1\tX-Key: k",
+	}, "\n")
+	if err := os.WriteFile(path, []byte(logs), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	_, output, err := ReplaySampleTraffic(context.Background(), nil, ReplaySampleTrafficInput{Config: canaryConfig, Samples: path, SampleDuration: "10s"})
+	if err != nil {
+		t.Fatalf("ReplaySampleTraffic() error = %v", err)
+	}
+	if output.Requests != 3 || output.Statuses["200"] != 3 || output.Window != "10s" {
+		t.Errorf("Expected the 3 requests to pass over 10s, got %+v", output)
+	}
+	if !strings.Contains(strings.Join(output.Warnings, "\n"), "share a single bucket") {
+		t.Errorf("Expected a warning about the access log without the X-Key header, got %v", output.Warnings)
+	}
+
+	_, output, err = ReplaySampleTraffic(context.Background(), nil, ReplaySampleTrafficInput{Config: canaryConfig, Samples: `[{"method": "GET"}, {"method": "get", "path": "/users/1"}]`})
+	if err != nil || output.Requests != 1 || output.Unparsed != 1 {
+		t.Errorf("Expected the sample without path to be skipped, got %+v (error %v)", output, err)
+	}
+}
@@ -0,0 +1,145 @@
+package tools
+
+import (
+	"context"
+	"time"
+
+	"github.com/krakend/mcp-server/internal/indexing"
+	"github.com/krakend/mcp-server/internal/runtime"
+	"github.com/krakend/mcp-server/internal/toolkit"
+	"github.com/krakend/mcp-server/tools/validation"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	// supportedKrakenDVersions is the KrakenD range whose configurations the tools understand
+	supportedKrakenDVersions = ">=2.0"
+	// configFormatVersion is the "version" value of KrakenD v2 configurations
+	configFormatVersion = 3
+)
+
+// ServerInfo identifies the running server and the toolsets it enabled
+type ServerInfo struct {
+	Name     string
+	Version  string
+	Toolsets []string
+}
+
+// GetCapabilitiesInput defines input for get_capabilities tool
+type GetCapabilitiesInput struct{}
+
+// ServerCapabilities describes the server build
+type ServerCapabilities struct {
+	Name                string `json:"name"`
+	Version             string `json:"version"`
+	OutputSchemaVersion string `json:"output_schema_version"`
+}
+
+// DatasetVersions describes the data the tools answer from
+type DatasetVersions struct {
+	DocsIndexSchema          int      `json:"docs_index_schema"`         // Schema version this build expects
+	DocsIndexInstalled       int      `json:"docs_index_installed"`      // Schema version of the local index (0 = none)
+	DocsUpdatedAt            string   `json:"docs_updated_at,omitempty"` // Documentation snapshot date
+	DocsAgeDays              int      `json:"docs_age_days"`
+	DocsEmbedded             bool     `json:"docs_embedded"` // Snapshot embedded at build time, never refreshed
+	DocsStale                bool     `json:"docs_stale"`    // Older than the 7 days cache TTL
+	DocsOutdated             bool     `json:"docs_outdated"` // Older than KRAKEND_MCP_DOCS_MAX_AGE days
+	DocsWarning              string   `json:"docs_warning,omitempty"`
+	FeatureCatalogVersion    string   `json:"feature_catalog_version"` // Empty when the catalog is not loaded
+	FeatureCatalogUpdatedAt  string   `json:"feature_catalog_updated_at,omitempty"`
+	SupportedKrakenDVersions string   `json:"supported_krakend_versions"`
+	ConfigFormatVersion      int      `json:"config_format_version"`
+	BundledSchemas           []string `json:"bundled_schemas,omitempty"` // Versions whose schema is embedded for offline validation
+}
+
+// EnvironmentSummary describes the tooling available to the server
+type EnvironmentSummary struct {
+	NativeKrakenD bool   `json:"native_krakend"`
+	NativeVersion string `json:"native_version,omitempty"`
+	Docker        bool   `json:"docker"`
+	DockerBinary  string `json:"docker_binary,omitempty"` // "docker" or "podman"
+	DockerVersion string `json:"docker_version,omitempty"`
+	License       bool   `json:"license"` // Enterprise license found
+	DataDir       string `json:"data_dir"`
+	ConfigMemory  bool   `json:"config_memory"`
+}
+
+// GetCapabilitiesOutput defines output for get_capabilities tool
+type GetCapabilitiesOutput struct {
+	Server      ServerCapabilities `json:"server"`
+	Toolsets    []string           `json:"toolsets"`
+	Tools       []string           `json:"tools"`
+	Datasets    DatasetVersions    `json:"datasets"`
+	Environment EnvironmentSummary `json:"environment"`
+}
+
+// getCapabilities returns the capabilities handler for the given server
+func getCapabilities(info ServerInfo) func(context.Context, *mcp.CallToolRequest, GetCapabilitiesInput) (*mcp.CallToolResult, GetCapabilitiesOutput, error) {
+	return func(ctx context.Context, req *mcp.CallToolRequest, input GetCapabilitiesInput) (*mcp.CallToolResult, GetCapabilitiesOutput, error) {
+		toolsets := info.Toolsets
+		if toolsets == nil {
+			toolsets = []string{}
+		}
+		output := GetCapabilitiesOutput{
+			Server: ServerCapabilities{
+				Name:                info.Name,
+				Version:             info.Version,
+				OutputSchemaVersion: toolkit.OutputSchemaVersion,
+			},
+			Toolsets: toolsets,
+			Tools:    toolkit.ToolNames(),
+			Datasets: DatasetVersions{
+				DocsIndexSchema:          indexing.IndexSchemaVersion,
+				DocsIndexInstalled:       getIndexVersion(),
+				DocsStale:                needsRefresh(),
+				SupportedKrakenDVersions: supportedKrakenDVersions,
+				ConfigFormatVersion:      configFormatVersion,
+				BundledSchemas:           validation.BundledSchemaVersions(false),
+			},
+		}
+
+		docs := currentDocsSnapshot(time.Now())
+		output.Datasets.DocsUpdatedAt = docs.Date
+		output.Datasets.DocsAgeDays = docs.AgeDays
+		output.Datasets.DocsEmbedded = docs.Embedded
+		output.Datasets.DocsOutdated = docs.Outdated
+		output.Datasets.DocsWarning = docs.Warning
+		if featureCatalog == nil {
+			_ = LoadFeatureData()
+		}
+		if featureCatalog != nil {
+			output.Datasets.FeatureCatalogVersion = featureCatalog.Version
+			output.Datasets.FeatureCatalogUpdatedAt = featureCatalog.LastUpdated
+		}
+
+		env := runtime.DetectEnvironment()
+		output.Environment = EnvironmentSummary{
+			NativeKrakenD: env.HasNativeKrakenD,
+			Docker:        env.HasDocker,
+			DockerBinary:  env.DockerBinary,
+			DockerVersion: env.DockerVersion,
+			License:       runtime.DetectLicense().Present,
+			DataDir:       dataDir,
+			ConfigMemory:  configMemoryEnabled(),
+		}
+		if env.HasNativeKrakenD {
+			if v, err := runtime.GetLocalKrakenDVersion(); err == nil {
+				output.Environment.NativeVersion = v
+			}
+		}
+
+		return nil, output, nil
+	}
+}
+
+// RegisterCapabilityTools registers the capability discovery tool.
+// It must be registered last so the reported tool list is complete.
+func RegisterCapabilityTools(server *mcp.Server, info ServerInfo) {
+	toolkit.AddTool(server,
+		&mcp.Tool{
+			Name:        "get_capabilities",
+			Description: "Report the server version, enabled toolsets and tools, dataset versions (documentation index schema, feature catalog version, supported KrakenD versions) and an environment summary (native KrakenD, Docker, license) in one call. Use it to branch behavior instead of probing each tool.",
+		},
+		getCapabilities(info),
+	)
+}
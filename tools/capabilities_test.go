@@ -0,0 +1,33 @@
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/krakend/mcp-server/internal/indexing"
+	"github.com/krakend/mcp-server/internal/toolkit"
+)
+
+func TestGetCapabilities(t *testing.T) {
+	handler := getCapabilities(ServerInfo{Name: "krakend-mcp-server", Version: "1.2.3", Toolsets: []string{"validation"}})
+
+	_, output, err := handler(context.Background(), nil, GetCapabilitiesInput{})
+	if err != nil {
+		t.Fatalf("getCapabilities() error = %v", err)
+	}
+	if output.Server.Version != "1.2.3" || output.Server.OutputSchemaVersion != toolkit.OutputSchemaVersion {
+		t.Errorf("Unexpected server info: %+v", output.Server)
+	}
+	if len(output.Toolsets) != 1 || output.Toolsets[0] != "validation" {
+		t.Errorf("Expected validation toolset, got %v", output.Toolsets)
+	}
+	if output.Datasets.DocsIndexSchema != indexing.IndexSchemaVersion {
+		t.Errorf("Expected docs index schema %d, got %d", indexing.IndexSchemaVersion, output.Datasets.DocsIndexSchema)
+	}
+	if output.Datasets.ConfigFormatVersion != 3 || output.Datasets.SupportedKrakenDVersions == "" {
+		t.Errorf("Unexpected supported versions: %+v", output.Datasets)
+	}
+	if output.Tools == nil {
+		t.Error("Expected tools list, got nil")
+	}
+}
@@ -0,0 +1,295 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/krakend/mcp-server/internal/toolkit"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	defaultCallsPerCore       = 2000 // Backend calls per second a core proxies with light middleware
+	defaultCapacityHeadroom   = 0.3
+	defaultCoresPerInstance   = 2
+	defaultMinInstances       = 2
+	defaultBackendLatency     = 100 * time.Millisecond
+	capacityBaseMemoryMB      = 256
+	capacityMemoryPerCallKB   = 64 // Buffers of an in-flight backend call and its response
+	capacityMiddlewareCost    = 0.5
+	defaultMaxIdleConnsByHost = 250 // KrakenD default of max_idle_connections_per_host
+)
+
+// capacityHeavyMiddlewares are the namespaces adding a noticeable CPU cost to every request
+var capacityHeavyMiddlewares = []string{
+	"modifier/lua-endpoint", "modifier/lua-proxy", "modifier/lua-backend", "validation/cel",
+	"validation/json-schema", "modifier/jmespath", "modifier/response-body-generator", "security/policies",
+}
+
+// EstimateCapacityInput defines input for estimate_capacity tool
+type EstimateCapacityInput struct {
+	Config           string             `json:"config" jsonschema:"KrakenD configuration (JSON string or file path)"`
+	TargetRPS        float64            `json:"target_rps" jsonschema:"Peak requests per second the gateway must serve"`
+	EndpointRPS      map[string]float64 `json:"endpoint_rps,omitempty" jsonschema:"Share of the traffic per endpoint, as relative weights keyed by path or METHOD /path (optional, defaults to an even split)"`
+	BackendLatency   string             `json:"backend_latency,omitempty" jsonschema:"Average backend response time, e.g. 80ms (optional, defaults to 100ms)"`
+	CallsPerCore     float64            `json:"calls_per_core,omitempty" jsonschema:"Backend calls per second one core proxies, measured in your own benchmarks (optional, defaults to 2000)"`
+	CoresPerInstance int                `json:"cores_per_instance,omitempty" jsonschema:"CPU cores of each instance (optional, defaults to 2)"`
+	Headroom         float64            `json:"headroom,omitempty" jsonschema:"Fraction of CPU kept free for spikes and failover (optional, defaults to 0.3)"`
+	MinInstances     int                `json:"min_instances,omitempty" jsonschema:"Minimum instances for high availability (optional, defaults to 2)"`
+}
+
+// CapacityEndpoint is the load an endpoint puts on the gateway
+type CapacityEndpoint struct {
+	Endpoint         string   `json:"endpoint"` // METHOD /path
+	RPS              float64  `json:"rps"`
+	FanOut           int      `json:"fan_out"`                      // Backend calls per request
+	Cost             float64  `json:"cost"`                         // CPU units per request: backend calls plus heavy middlewares
+	BackendRPS       float64  `json:"backend_rps"`                  // Backend calls per second
+	RateLimitCeiling float64  `json:"rate_limit_ceiling,omitempty"` // Requests per second max_rate lets through each instance
+	HeavyMiddlewares []string `json:"heavy_middlewares,omitempty"`
+}
+
+// EstimateCapacityOutput defines output for estimate_capacity tool
+type EstimateCapacityOutput struct {
+	Instances           int                    `json:"instances"`
+	CoresPerInstance    int                    `json:"cores_per_instance"`
+	MemoryPerInstanceMB int                    `json:"memory_per_instance_mb"`
+	TotalCores          float64                `json:"total_cores"` // Cores needed, headroom included
+	BackendCallsPerSec  float64                `json:"backend_calls_per_second"`
+	InFlightCalls       float64                `json:"in_flight_calls"` // Concurrent backend calls across the cluster
+	Endpoints           []CapacityEndpoint     `json:"endpoints"`
+	ServiceSettings     map[string]interface{} `json:"service_settings"` // Root-level settings to apply
+	Math                []string               `json:"math"`
+	Warnings            []string               `json:"warnings,omitempty"`
+	Notes               []string               `json:"notes"`
+	Summary             string                 `json:"summary"`
+}
+
+// endpointRateLimitCeiling returns the requests per second a qos/ratelimit/router max_rate
+// lets through one instance, 0 when the endpoint has no shared limit
+func endpointRateLimitCeiling(endpoint map[string]interface{}) float64 {
+	extra, _ := endpoint["extra_config"].(map[string]interface{})
+	settings, _ := extra["qos/ratelimit/router"].(map[string]interface{})
+	for _, rule := range rateLimitRules(settings, "") {
+		if rule.client == nil {
+			return rule.rate / rule.every.Seconds()
+		}
+	}
+	return 0
+}
+
+// EstimateCapacity sizes a KrakenD cluster for a target load from the fan-out, middlewares and
+// rate limits of its endpoints, showing the math behind every figure
+func EstimateCapacity(ctx context.Context, req *mcp.CallToolRequest, input EstimateCapacityInput) (*mcp.CallToolResult, EstimateCapacityOutput, error) {
+	if input.TargetRPS <= 0 {
+		return nil, EstimateCapacityOutput{}, fmt.Errorf("target_rps must be greater than 0")
+	}
+	configContent, err := readConfigContent(input.Config)
+	if err != nil {
+		return nil, EstimateCapacityOutput{}, fmt.Errorf("failed to read config: %w", err)
+	}
+	var config map[string]interface{}
+	if err := json.Unmarshal([]byte(configContent), &config); err != nil {
+		return nil, EstimateCapacityOutput{}, fmt.Errorf("invalid JSON: %w", err)
+	}
+	latency := defaultBackendLatency
+	if input.BackendLatency != "" {
+		if latency, err = time.ParseDuration(input.BackendLatency); err != nil || latency <= 0 {
+			return nil, EstimateCapacityOutput{}, fmt.Errorf("invalid backend_latency %q", input.BackendLatency)
+		}
+	}
+	if input.Headroom < 0 || input.Headroom >= 1 {
+		return nil, EstimateCapacityOutput{}, fmt.Errorf("headroom must be between 0 and 1")
+	}
+	callsPerCore := input.CallsPerCore
+	if callsPerCore <= 0 {
+		callsPerCore = defaultCallsPerCore
+	}
+	cores := input.CoresPerInstance
+	if cores <= 0 {
+		cores = defaultCoresPerInstance
+	}
+	headroom := input.Headroom
+	if headroom == 0 {
+		headroom = defaultCapacityHeadroom
+	}
+	minInstances := input.MinInstances
+	if minInstances <= 0 {
+		minInstances = defaultMinInstances
+	}
+
+	endpoints, _ := config["endpoints"].([]interface{})
+	if len(endpoints) == 0 {
+		return nil, EstimateCapacityOutput{}, fmt.Errorf("the configuration declares no endpoints")
+	}
+	output := EstimateCapacityOutput{
+		CoresPerInstance: cores,
+		Endpoints:        []CapacityEndpoint{},
+		Notes: []string{
+			fmt.Sprintf("Figures assume %s backend calls per core and second; benchmark your own endpoints (e.g. with k6 against a single instance) and pass calls_per_core to refine them.", formatFloat(callsPerCore)),
+			"KrakenD is stateless: scale horizontally behind the load balancer, and size for the peak with one instance down.",
+		},
+	}
+
+	// Traffic share of every endpoint: the given weights, or an even split
+	weights := make([]float64, len(endpoints))
+	totalWeight := 0.0
+	matchedWeights := map[string]bool{}
+	for i, ep := range endpoints {
+		endpoint, _ := ep.(map[string]interface{})
+		path, _ := endpoint["endpoint"].(string)
+		key := endpointMethod(endpoint) + " " + path
+		weights[i] = 1
+		if len(input.EndpointRPS) > 0 {
+			weights[i] = 0
+			for _, name := range []string{key, path} {
+				if w, ok := input.EndpointRPS[name]; ok {
+					weights[i] = w
+					matchedWeights[name] = true
+					break
+				}
+			}
+		}
+		totalWeight += weights[i]
+	}
+	for _, name := range slices.Sorted(maps.Keys(input.EndpointRPS)) {
+		if !matchedWeights[name] {
+			output.Warnings = append(output.Warnings, fmt.Sprintf("endpoint_rps entry %q matches no endpoint and was ignored", name))
+		}
+	}
+	if totalWeight <= 0 {
+		return nil, EstimateCapacityOutput{}, fmt.Errorf("endpoint_rps gives no traffic to any endpoint")
+	}
+
+	hostRPS := map[string]float64{}
+	totalCost := 0.0
+	for i, ep := range endpoints {
+		endpoint, _ := ep.(map[string]interface{})
+		path, _ := endpoint["endpoint"].(string)
+		rps := input.TargetRPS * weights[i] / totalWeight
+		backends, _ := endpoint["backend"].([]interface{})
+		load := CapacityEndpoint{
+			Endpoint:         endpointMethod(endpoint) + " " + path,
+			RPS:              math.Round(rps*100) / 100,
+			FanOut:           len(backends),
+			RateLimitCeiling: endpointRateLimitCeiling(endpoint),
+		}
+		extra, _ := endpoint["extra_config"].(map[string]interface{})
+		for _, namespace := range capacityHeavyMiddlewares {
+			if _, ok := extra[namespace]; ok {
+				load.HeavyMiddlewares = append(load.HeavyMiddlewares, namespace)
+			}
+		}
+		for _, b := range backends {
+			backend, _ := b.(map[string]interface{})
+			backendExtra, _ := backend["extra_config"].(map[string]interface{})
+			for _, namespace := range capacityHeavyMiddlewares {
+				if _, ok := backendExtra[namespace]; ok && !containsFold(load.HeavyMiddlewares, namespace) {
+					load.HeavyMiddlewares = append(load.HeavyMiddlewares, namespace)
+				}
+			}
+			hosts, _ := backend["host"].([]interface{})
+			if len(hosts) == 0 {
+				hosts = []interface{}{"(default host)"}
+			}
+			for _, host := range hosts {
+				hostRPS[fmt.Sprint(host)] += rps / float64(len(hosts))
+			}
+		}
+		load.Cost = float64(load.FanOut) + capacityMiddlewareCost*float64(len(load.HeavyMiddlewares))
+		if load.FanOut == 0 {
+			// Static or generated responses still cost the routing and encoding
+			load.Cost += capacityMiddlewareCost
+		}
+		load.BackendRPS = math.Round(rps*float64(load.FanOut)*100) / 100
+		output.BackendCallsPerSec += rps * float64(load.FanOut)
+		totalCost += rps * load.Cost
+		output.Endpoints = append(output.Endpoints, load)
+	}
+	sort.SliceStable(output.Endpoints, func(i, j int) bool {
+		return output.Endpoints[i].RPS*output.Endpoints[i].Cost > output.Endpoints[j].RPS*output.Endpoints[j].Cost
+	})
+
+	// CPU: cost units per second over the calls a core proxies, keeping the headroom free
+	output.TotalCores = math.Ceil(totalCost/callsPerCore/(1-headroom)*10) / 10
+	output.Instances = int(math.Ceil(output.TotalCores / float64(cores)))
+	if output.Instances < minInstances {
+		output.Instances = minInstances
+	}
+	output.Math = append(output.Math,
+		fmt.Sprintf("Backend calls: Σ endpoint rps × fan-out = %s calls/s", formatFloat(math.Round(output.BackendCallsPerSec))),
+		fmt.Sprintf("CPU load: Σ endpoint rps × (fan-out + %s × heavy middlewares) = %s units/s", formatFloat(capacityMiddlewareCost), formatFloat(math.Round(totalCost))),
+		fmt.Sprintf("Cores: %s / %s per core / (1 - %s headroom) = %s cores", formatFloat(math.Round(totalCost)), formatFloat(callsPerCore), formatFloat(headroom), formatFloat(output.TotalCores)),
+		fmt.Sprintf("Instances: max(%d minimum, ⌈%s / %d cores⌉) = %d", minInstances, formatFloat(output.TotalCores), cores, output.Instances),
+	)
+
+	// Memory and connections: in-flight backend calls by Little's law (rate × latency)
+	output.InFlightCalls = math.Ceil(output.BackendCallsPerSec * latency.Seconds())
+	perInstance := output.InFlightCalls / float64(output.Instances)
+	memory := capacityBaseMemoryMB + perInstance*capacityMemoryPerCallKB/1024
+	output.MemoryPerInstanceMB = int(math.Max(512, math.Ceil(memory/128)*128))
+	output.Math = append(output.Math,
+		fmt.Sprintf("In-flight backend calls: %s calls/s × %s latency = %s (%s per instance)", formatFloat(math.Round(output.BackendCallsPerSec)), latency, formatFloat(output.InFlightCalls), formatFloat(math.Ceil(perInstance))),
+		fmt.Sprintf("Memory: %d MB base + %s in-flight calls × %d KB, rounded up to 128 MB (512 MB minimum) = %d MB", capacityBaseMemoryMB, formatFloat(math.Ceil(perInstance)), capacityMemoryPerCallKB, output.MemoryPerInstanceMB),
+	)
+
+	busiestHost, busiest := "", 0.0
+	for host, rps := range hostRPS {
+		if rps > busiest || (rps == busiest && host < busiestHost) {
+			busiestHost, busiest = host, rps
+		}
+	}
+	// Idle connections cover the concurrent calls per host with a 50% margin for latency spikes
+	perHost := int(math.Ceil(busiest * latency.Seconds() / float64(output.Instances) * 1.5))
+	maxIdle := 0
+	for _, rps := range hostRPS {
+		maxIdle += int(math.Ceil(rps * latency.Seconds() / float64(output.Instances) * 1.5))
+	}
+	output.ServiceSettings = map[string]interface{}{
+		"max_idle_connections_per_host": max(perHost, defaultMaxIdleConnsByHost),
+		"max_idle_connections":          max(maxIdle, defaultMaxIdleConnsByHost),
+		"idle_connection_timeout":       "90s",
+	}
+	output.Math = append(output.Math, fmt.Sprintf("Idle connections per host: %s calls/s to %s × %s latency / %d instances × 1.5 = %d (never below the default of %d)",
+		formatFloat(math.Round(busiest)), busiestHost, latency, output.Instances, perHost, defaultMaxIdleConnsByHost))
+	if perHost <= defaultMaxIdleConnsByHost {
+		output.Notes = append(output.Notes, fmt.Sprintf("The default connection pool (%d idle connections per host) covers the expected concurrency.", defaultMaxIdleConnsByHost))
+	}
+
+	for _, load := range output.Endpoints {
+		if load.RateLimitCeiling > 0 {
+			clusterCeiling := load.RateLimitCeiling * float64(output.Instances)
+			if load.RPS > clusterCeiling {
+				output.Warnings = append(output.Warnings, fmt.Sprintf("%s expects %s rps but its max_rate lets through %s rps per instance (%s across %d instances): the rest gets 429",
+					load.Endpoint, formatFloat(load.RPS), formatFloat(load.RateLimitCeiling), formatFloat(clusterCeiling), output.Instances))
+			}
+		}
+		if load.FanOut > 3 {
+			output.Notes = append(output.Notes, fmt.Sprintf("%s aggregates %d backends: each request costs %d backend calls, the largest multiplier of the load.", load.Endpoint, load.FanOut, load.FanOut))
+		}
+	}
+	if endpointTimeout(config, map[string]interface{}{}) < 2*latency {
+		output.Warnings = append(output.Warnings, fmt.Sprintf("The global timeout (%s) is less than twice the backend latency (%s): latency spikes will turn into 5xx errors", endpointTimeout(config, map[string]interface{}{}), latency))
+	}
+
+	output.Summary = fmt.Sprintf("%d instance(s) of %d cores and %d MB for %s rps (%s backend calls/s across %d endpoint(s))",
+		output.Instances, cores, output.MemoryPerInstanceMB, formatFloat(input.TargetRPS), formatFloat(math.Round(output.BackendCallsPerSec)), len(output.Endpoints))
+	return nil, output, nil
+}
+
+// RegisterCapacityTools registers the capacity sizing tool
+func RegisterCapacityTools(server *mcp.Server) {
+	toolkit.AddTool(server,
+		&mcp.Tool{
+			Name:        "estimate_capacity",
+			Description: "Size a KrakenD cluster for a target RPS: combines the endpoints, their backend fan-out, CPU-heavy middlewares (Lua, CEL, JSON Schema) and rate limits to recommend instance count, CPU and memory per instance and connection pool settings (max_idle_connections, max_idle_connections_per_host), showing the math behind every figure. Warns when rate limits or timeouts conflict with the expected traffic.",
+		},
+		EstimateCapacity,
+	)
+}
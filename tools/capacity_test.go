@@ -0,0 +1,67 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestEstimateCapacity(t *testing.T) {
+	config := `{"version": 3, "timeout": "3s", "endpoints": [
+		{"endpoint": "/home", "backend": [
+			{"url_pattern": "/a", "host": ["http://a"]}, {"url_pattern": "/b", "host": ["http://b"]},
+			{"url_pattern": "/c", "host": ["http://c"]}, {"url_pattern": "/d", "host": ["http://a"]}
+		], "extra_config": {"modifier/jmespath": {"expr": "a"}}},
+		{"endpoint": "/orders", "method": "POST", "backend": [{"url_pattern": "/orders", "host": ["http://a"]}],
+		 "extra_config": {"qos/ratelimit/router": {"max_rate": 100}}}
+	]}`
+	_, output, err := EstimateCapacity(context.Background(), nil, EstimateCapacityInput{
+		Config:      config,
+		TargetRPS:   10000,
+		EndpointRPS: map[string]float64{"/home": 3, "POST /orders": 1, "/missing": 1},
+	})
+	if err != nil {
+		t.Fatalf("EstimateCapacity() error = %v", err)
+	}
+	// 7500 rps × 4 calls + 2500 rps × 1 call = 32500 calls/s
+	if output.BackendCallsPerSec != 32500 {
+		t.Errorf("Expected 32500 backend calls/s, got %v", output.BackendCallsPerSec)
+	}
+	// (30000 + 7500 × 0.5 + 2500) / 2000 / 0.7 = 25.9 cores, 13 instances of 2 cores
+	if output.TotalCores != 25.9 || output.Instances != 13 {
+		t.Errorf("Expected 25.9 cores over 13 instances, got %v cores and %d instances", output.TotalCores, output.Instances)
+	}
+	if output.Endpoints[0].Endpoint != "GET /home" || output.Endpoints[0].HeavyMiddlewares[0] != "modifier/jmespath" {
+		t.Errorf("Expected GET /home first with its JMESPath cost, got %+v", output.Endpoints[0])
+	}
+	warnings := strings.Join(output.Warnings, "\n")
+	for _, want := range []string{`"/missing" matches no endpoint`, "POST /orders expects 2500 rps"} {
+		if !strings.Contains(warnings, want) {
+			t.Errorf("Expected a warning containing %q, got %v", want, output.Warnings)
+		}
+	}
+	if strings.Contains(warnings, "less than twice") {
+		t.Errorf("Unexpected timeout warning with a 3s timeout: %v", output.Warnings)
+	}
+	if len(output.Math) == 0 || output.ServiceSettings["max_idle_connections_per_host"] == nil {
+		t.Errorf("Expected the math and the service settings, got %+v", output)
+	}
+}
+
+func TestEstimateCapacity_Small(t *testing.T) {
+	config := `{"version": 3, "timeout": "100ms", "endpoints": [{"endpoint": "/a", "backend": [{"url_pattern": "/a"}]}]}`
+	_, output, err := EstimateCapacity(context.Background(), nil, EstimateCapacityInput{Config: config, TargetRPS: 50})
+	if err != nil {
+		t.Fatalf("EstimateCapacity() error = %v", err)
+	}
+	if output.Instances != 2 || output.MemoryPerInstanceMB != 512 || output.ServiceSettings["max_idle_connections_per_host"] != 250 {
+		t.Errorf("Expected the minimum sizing and default pools, got %+v", output)
+	}
+	if !strings.Contains(strings.Join(output.Warnings, "\n"), "less than twice") {
+		t.Errorf("Expected a timeout warning, got %v", output.Warnings)
+	}
+
+	if _, _, err := EstimateCapacity(context.Background(), nil, EstimateCapacityInput{Config: config}); err == nil {
+		t.Error("Expected an error without target_rps")
+	}
+}
@@ -0,0 +1,273 @@
+package tools
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/krakend/mcp-server/internal/toolkit"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const defaultCertificateWarnDays = 30
+
+// InspectCertificatesInput defines input for inspect_certificates tool
+type InspectCertificatesInput struct {
+	Config    string   `json:"config" jsonschema:"KrakenD configuration (JSON string or file path)"`
+	WarnDays  int      `json:"warn_days,omitempty" jsonschema:"Warn about certificates expiring within this many days (optional, defaults to 30)"`
+	Hostnames []string `json:"hostnames,omitempty" jsonschema:"Public hostnames the gateway certificate must cover (optional, enables SAN matching)"`
+	TimeoutMs int      `json:"timeout_ms,omitempty" jsonschema:"Timeout in milliseconds for backend TLS handshakes (optional, defaults to 3000)"`
+}
+
+// CertificateReport describes a certificate found in the gateway TLS settings or served by a backend
+type CertificateReport struct {
+	Source        string    `json:"source"` // Certificate file path or backend host
+	Subject       string    `json:"subject,omitempty"`
+	Issuer        string    `json:"issuer,omitempty"`
+	DNSNames      []string  `json:"dns_names,omitempty"`
+	NotAfter      time.Time `json:"not_after,omitempty"`
+	DaysRemaining int       `json:"days_remaining"`
+	Expired       bool      `json:"expired"`
+	ExpiringSoon  bool      `json:"expiring_soon"`
+	ChainLength   int       `json:"chain_length"`
+	ChainVerified bool      `json:"chain_verified"`
+	ChainError    string    `json:"chain_error,omitempty"`
+	KeyMatches    *bool     `json:"key_matches,omitempty"` // Gateway only: private key matches certificate
+	SANMismatches []string  `json:"san_mismatches,omitempty"`
+	Error         string    `json:"error,omitempty"`
+}
+
+// InspectCertificatesOutput defines output for inspect_certificates tool
+type InspectCertificatesOutput struct {
+	Gateway  []CertificateReport `json:"gateway"`
+	Backends []CertificateReport `json:"backends"`
+	Warnings []string            `json:"warnings"`
+	Summary  string              `json:"summary"`
+}
+
+// InspectCertificates checks the gateway TLS certificates and the certificates served by HTTPS backends
+func InspectCertificates(ctx context.Context, req *mcp.CallToolRequest, input InspectCertificatesInput) (*mcp.CallToolResult, InspectCertificatesOutput, error) {
+	configContent, err := readConfigContent(input.Config)
+	if err != nil {
+		return nil, InspectCertificatesOutput{}, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	var config map[string]interface{}
+	if err := json.Unmarshal([]byte(configContent), &config); err != nil {
+		return nil, InspectCertificatesOutput{}, fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	warnDays := input.WarnDays
+	if warnDays <= 0 {
+		warnDays = defaultCertificateWarnDays
+	}
+	timeout := defaultProbeTimeout
+	if input.TimeoutMs > 0 {
+		timeout = time.Duration(input.TimeoutMs) * time.Millisecond
+	}
+
+	output := InspectCertificatesOutput{
+		Gateway:  []CertificateReport{},
+		Backends: []CertificateReport{},
+		Warnings: []string{},
+	}
+
+	// Gateway certificates
+	for _, pair := range gatewayKeyPairs(config) {
+		report := inspectKeyPair(pair[0], pair[1], input.Hostnames)
+		output.Gateway = append(output.Gateway, report)
+	}
+
+	// Backend certificates
+	var hosts []string
+	for _, h := range collectBackendHosts(config) {
+		if scheme, _, _, err := splitBackendHost(h.Host); err == nil && scheme == "https" && h.SD == "static" {
+			hosts = append(hosts, h.Host)
+		}
+	}
+
+	reports := make([]CertificateReport, len(hosts))
+	sem := make(chan struct{}, defaultProbeConcurrency)
+	var wg sync.WaitGroup
+	for i, host := range hosts {
+		wg.Add(1)
+		go func(i int, host string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			reports[i] = inspectBackendCertificate(ctx, host, timeout)
+		}(i, host)
+	}
+	wg.Wait()
+	output.Backends = append(output.Backends, reports...)
+
+	// Expiry window and issues
+	for _, list := range [][]CertificateReport{output.Gateway, output.Backends} {
+		for i := range list {
+			r := &list[i]
+			if r.Error != "" {
+				output.Warnings = append(output.Warnings, fmt.Sprintf("%s: %s", r.Source, r.Error))
+				continue
+			}
+			r.Expired = time.Now().After(r.NotAfter) // DaysRemaining is 0 during the first day after expiry
+			r.ExpiringSoon = !r.Expired && r.DaysRemaining <= warnDays
+			switch {
+			case r.Expired:
+				output.Warnings = append(output.Warnings, fmt.Sprintf("%s: certificate expired on %s", r.Source, r.NotAfter.Format("2006-01-02")))
+			case r.ExpiringSoon:
+				output.Warnings = append(output.Warnings, fmt.Sprintf("%s: certificate expires in %d day(s) (%s)", r.Source, r.DaysRemaining, r.NotAfter.Format("2006-01-02")))
+			}
+			if r.KeyMatches != nil && !*r.KeyMatches {
+				output.Warnings = append(output.Warnings, fmt.Sprintf("%s: private key does not match certificate", r.Source))
+			}
+			if !r.ChainVerified {
+				output.Warnings = append(output.Warnings, fmt.Sprintf("%s: certificate chain could not be verified: %s", r.Source, r.ChainError))
+			}
+			for _, name := range r.SANMismatches {
+				output.Warnings = append(output.Warnings, fmt.Sprintf("%s: certificate does not cover hostname %s", r.Source, name))
+			}
+		}
+	}
+
+	output.Summary = fmt.Sprintf("Inspected %d gateway and %d backend certificate(s): %d warning(s) (expiry window: %d days)",
+		len(output.Gateway), len(output.Backends), len(output.Warnings), warnDays)
+
+	return &mcp.CallToolResult{Meta: map[string]interface{}{"warnings": len(output.Warnings)}}, output, nil
+}
+
+// gatewayKeyPairs returns the (certificate, key) file pairs declared in the service tls section.
+// Both the legacy public_key/private_key fields and the keys list are supported.
+func gatewayKeyPairs(config map[string]interface{}) [][2]string {
+	tlsConfig, ok := config["tls"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	if disabled, _ := tlsConfig["disabled"].(bool); disabled {
+		return nil
+	}
+
+	var pairs [][2]string
+	if pub, ok := tlsConfig["public_key"].(string); ok && pub != "" {
+		priv, _ := tlsConfig["private_key"].(string)
+		pairs = append(pairs, [2]string{pub, priv})
+	}
+	if keys, ok := tlsConfig["keys"].([]interface{}); ok {
+		for _, k := range keys {
+			if key, ok := k.(map[string]interface{}); ok {
+				pub, _ := key["public_key"].(string)
+				priv, _ := key["private_key"].(string)
+				if pub != "" {
+					pairs = append(pairs, [2]string{pub, priv})
+				}
+			}
+		}
+	}
+	return pairs
+}
+
+// inspectKeyPair loads a gateway certificate file and checks its key, chain and SANs
+func inspectKeyPair(certFile, keyFile string, hostnames []string) CertificateReport {
+	report := CertificateReport{Source: certFile}
+
+	data, err := os.ReadFile(certFile)
+	if err != nil {
+		report.Error = fmt.Sprintf("failed to read certificate: %v", err)
+		return report
+	}
+
+	var certs []*x509.Certificate
+	for rest := data; ; {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			report.Error = fmt.Sprintf("failed to parse certificate: %v", err)
+			return report
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 {
+		report.Error = "no PEM certificates found"
+		return report
+	}
+
+	fillCertificateReport(&report, certs, "")
+
+	if keyFile != "" {
+		_, err := tls.LoadX509KeyPair(certFile, keyFile)
+		matches := err == nil
+		report.KeyMatches = &matches
+	}
+
+	for _, name := range hostnames {
+		if err := certs[0].VerifyHostname(name); err != nil {
+			report.SANMismatches = append(report.SANMismatches, name)
+		}
+	}
+
+	return report
+}
+
+// inspectBackendCertificate fetches the certificate served by an HTTPS backend host
+func inspectBackendCertificate(ctx context.Context, host string, timeout time.Duration) CertificateReport {
+	report := CertificateReport{Source: host}
+
+	_, hostname, port, err := splitBackendHost(host)
+	if err != nil {
+		report.Error = err.Error()
+		return report
+	}
+
+	hostCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	certs, err := fetchPeerCertificates(hostCtx, &net.Dialer{Timeout: timeout}, backendAddress(hostname, port), hostname)
+	if err != nil {
+		report.Error = err.Error()
+		return report
+	}
+
+	fillCertificateReport(&report, certs, hostname)
+	return report
+}
+
+// fillCertificateReport copies leaf details and chain verification into report
+func fillCertificateReport(report *CertificateReport, certs []*x509.Certificate, dnsName string) {
+	leaf := certs[0]
+	report.Subject = leaf.Subject.String()
+	report.Issuer = leaf.Issuer.String()
+	report.DNSNames = leaf.DNSNames
+	report.NotAfter = leaf.NotAfter
+	report.DaysRemaining = daysUntil(leaf.NotAfter)
+	report.ChainLength = len(certs)
+
+	if err := verifyCertificateChain(certs, dnsName); err != nil {
+		report.ChainError = err.Error()
+	} else {
+		report.ChainVerified = true
+	}
+}
+
+// RegisterCertificateTools registers TLS certificate inspection tools
+func RegisterCertificateTools(server *mcp.Server) {
+	toolkit.AddTool(server,
+		&mcp.Tool{
+			Name:        "inspect_certificates",
+			Description: "Inspect the gateway TLS certificate/key pairs (expiry, chain, key match, SAN coverage of the given hostnames) and the certificates served by HTTPS backends. Warns about certificates that are expired or expire within a configurable window (default 30 days).",
+		},
+		InspectCertificates,
+	)
+}
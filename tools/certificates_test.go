@@ -0,0 +1,124 @@
+package tools
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeTestKeyPair(t *testing.T, dnsName string, notAfter time.Time) (string, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: dnsName},
+		DNSNames:     []string{dnsName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+	keyDER, _ := x509.MarshalECPrivateKey(key)
+
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+	os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600)
+	os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600)
+	return certFile, keyFile
+}
+
+func TestInspectCertificates_Gateway(t *testing.T) {
+	certFile, keyFile := writeTestKeyPair(t, "api.example.com", time.Now().Add(10*24*time.Hour))
+	config := fmt.Sprintf(`{"version": 3, "tls": {"public_key": %q, "private_key": %q}, "endpoints": []}`, certFile, keyFile)
+
+	_, output, err := InspectCertificates(context.Background(), nil, InspectCertificatesInput{
+		Config:    config,
+		Hostnames: []string{"api.example.com", "www.example.com"},
+	})
+	if err != nil {
+		t.Fatalf("InspectCertificates() error = %v", err)
+	}
+
+	if len(output.Gateway) != 1 {
+		t.Fatalf("Expected 1 gateway certificate, got %d", len(output.Gateway))
+	}
+	report := output.Gateway[0]
+	if report.KeyMatches == nil || !*report.KeyMatches {
+		t.Error("Expected private key to match certificate")
+	}
+	if !report.ExpiringSoon {
+		t.Error("Expected certificate expiring in 10 days to be flagged")
+	}
+	if len(report.SANMismatches) != 1 || report.SANMismatches[0] != "www.example.com" {
+		t.Errorf("Expected SAN mismatch for www.example.com, got %v", report.SANMismatches)
+	}
+}
+
+func TestInspectCertificates_KeyMismatch(t *testing.T) {
+	certFile, _ := writeTestKeyPair(t, "api.example.com", time.Now().Add(365*24*time.Hour))
+	_, otherKey := writeTestKeyPair(t, "api.example.com", time.Now().Add(365*24*time.Hour))
+	config := fmt.Sprintf(`{"tls": {"keys": [{"public_key": %q, "private_key": %q}]}}`, certFile, otherKey)
+
+	_, output, err := InspectCertificates(context.Background(), nil, InspectCertificatesInput{Config: config})
+	if err != nil {
+		t.Fatalf("InspectCertificates() error = %v", err)
+	}
+	if len(output.Gateway) != 1 || output.Gateway[0].KeyMatches == nil || *output.Gateway[0].KeyMatches {
+		t.Errorf("Expected key mismatch to be reported, got %+v", output.Gateway)
+	}
+}
+
+func TestInspectCertificates_Backend(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	config := fmt.Sprintf(`{"endpoints": [{"backend": [{"host": [%q, "http://plain.example.com"]}]}]}`, server.URL)
+
+	_, output, err := InspectCertificates(context.Background(), nil, InspectCertificatesInput{Config: config})
+	if err != nil {
+		t.Fatalf("InspectCertificates() error = %v", err)
+	}
+	if len(output.Backends) != 1 {
+		t.Fatalf("Expected only the HTTPS backend to be inspected, got %d", len(output.Backends))
+	}
+	if output.Backends[0].NotAfter.IsZero() {
+		t.Errorf("Expected certificate details, got %+v", output.Backends[0])
+	}
+	if output.Backends[0].ChainVerified {
+		t.Error("Expected self-signed test certificate not to verify")
+	}
+}
+
+func TestInspectCertificates_ExpiredWithinADay(t *testing.T) {
+	certFile, keyFile := writeTestKeyPair(t, "api.example.com", time.Now().Add(-time.Hour))
+	config := fmt.Sprintf(`{"version": 3, "tls": {"public_key": %q, "private_key": %q}, "endpoints": []}`, certFile, keyFile)
+
+	_, output, err := InspectCertificates(context.Background(), nil, InspectCertificatesInput{Config: config})
+	if err != nil {
+		t.Fatalf("InspectCertificates() error = %v", err)
+	}
+	if len(output.Gateway) != 1 {
+		t.Fatalf("Expected 1 gateway certificate, got %d", len(output.Gateway))
+	}
+	if report := output.Gateway[0]; !report.Expired || report.ExpiringSoon {
+		t.Errorf("Expected a certificate expired an hour ago to be expired, got %+v", report)
+	}
+}
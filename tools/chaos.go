@@ -0,0 +1,322 @@
+package tools
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/krakend/mcp-server/internal/toolkit"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	defaultChaosDelay       = "1s"
+	defaultChaosErrorRate   = 0.2
+	defaultChaosErrorStatus = 503
+	defaultChaosTimeout     = "100ms"
+	defaultChaosProxyHost   = "toxiproxy"
+	chaosProxyFirstPort     = 21000
+)
+
+// chaosScenarios lists the failure scenarios generate_chaos_config knows
+var chaosScenarios = []string{"latency", "errors", "timeouts"}
+
+// GenerateChaosConfigInput defines input for generate_chaos_config tool
+type GenerateChaosConfigInput struct {
+	Config         string   `json:"config" jsonschema:"KrakenD configuration (JSON string or file path)"`
+	ConfigEncoding string   `json:"config_encoding,omitempty" jsonschema:"Encoding of config for large configurations: base64 or gzip+base64 (optional, defaults to a plain JSON string or file path)"`
+	Scenarios      []string `json:"scenarios,omitempty" jsonschema:"Variants to generate: latency, errors and/or timeouts (optional, defaults to all)"`
+	Endpoints      []string `json:"endpoints,omitempty" jsonschema:"Endpoint paths to degrade (optional, defaults to every endpoint)"`
+	Delay          string   `json:"delay,omitempty" jsonschema:"Delay added to backend calls in the latency variant (optional, defaults to 1s)"`
+	ErrorRate      float64  `json:"error_rate,omitempty" jsonschema:"Fraction of backend calls failing in the errors variant, 1 fails every call (optional, defaults to 0.2)"`
+	ErrorStatus    int      `json:"error_status,omitempty" jsonschema:"Status code of injected failures (optional, defaults to 503)"`
+	Timeout        string   `json:"timeout,omitempty" jsonschema:"Endpoint timeout of the timeouts variant (optional, defaults to 100ms)"`
+	ProxyHost      string   `json:"proxy_host,omitempty" jsonschema:"Host where the gateway reaches Toxiproxy in the latency variant, e.g. a Docker Compose service (optional, defaults to toxiproxy)"`
+	OutputDir      string   `json:"output_dir,omitempty" jsonschema:"Directory where krakend.chaos-<scenario>.json files are written (optional)"`
+}
+
+// ChaosVariant is a degraded copy of the configuration
+type ChaosVariant struct {
+	Scenario    string                 `json:"scenario"`
+	Description string                 `json:"description"`
+	Config      map[string]interface{} `json:"config"`
+	Changes     []string               `json:"changes"` // JSON paths modified
+	File        string                 `json:"file,omitempty"`
+}
+
+// ToxiproxyProxy is an entry of the Toxiproxy configuration file
+type ToxiproxyProxy struct {
+	Name     string `json:"name"`
+	Listen   string `json:"listen"`
+	Upstream string `json:"upstream"`
+	Enabled  bool   `json:"enabled"`
+}
+
+// ChaosToxiproxy is the Toxiproxy setup delaying the backends of the latency variant
+type ChaosToxiproxy struct {
+	Proxies  []ToxiproxyProxy `json:"proxies"`
+	Commands []string         `json:"commands"` // Start Toxiproxy and add the latency toxics
+	File     string           `json:"file,omitempty"`
+}
+
+// GenerateChaosConfigOutput defines output for generate_chaos_config tool
+type GenerateChaosConfigOutput struct {
+	Variants  []ChaosVariant  `json:"variants"`
+	Toxiproxy *ChaosToxiproxy `json:"toxiproxy,omitempty"`
+	Warnings  []string        `json:"warnings,omitempty"`
+	Notes     []string        `json:"notes"`
+}
+
+// chaosProxies routes backend hosts through Toxiproxy, one proxy per upstream host
+type chaosProxies struct {
+	host    string
+	byHost  map[string]string // Backend host → host of its proxy
+	proxies []ToxiproxyProxy
+}
+
+// route returns the host of the proxy in front of a backend host, e.g. http://users:8080 →
+// http://toxiproxy:21000
+func (p *chaosProxies) route(host string) (string, error) {
+	if proxied, ok := p.byHost[host]; ok {
+		return proxied, nil
+	}
+	// Hosts without scheme are valid in KrakenD (http)
+	scheme, hostname, port, err := splitBackendHost(host)
+	if err != nil {
+		return "", err
+	}
+	upstream := backendAddress(hostname, port)
+	listen := chaosProxyFirstPort + len(p.proxies)
+	p.proxies = append(p.proxies, ToxiproxyProxy{
+		Name:     strings.Trim(nameUnsafeChars.ReplaceAllString(strings.ToLower(upstream), "_"), "_"),
+		Listen:   fmt.Sprintf("0.0.0.0:%d", listen),
+		Upstream: upstream,
+		Enabled:  true,
+	})
+	p.byHost[host] = fmt.Sprintf("%s://%s:%d", scheme, p.host, listen)
+	return p.byHost[host], nil
+}
+
+// chaosBackendModifier returns the backend extra_config entry injecting failures
+func chaosBackendModifier(errorRate float64, status int) (namespace string, value map[string]interface{}) {
+	if errorRate >= 1 {
+		// Deterministic failure: skip the backend and answer with the error status
+		body := base64.StdEncoding.EncodeToString([]byte(`{"error":"chaos: injected failure"}`))
+		return "modifier/martian", map[string]interface{}{
+			"fifo.Group": map[string]interface{}{
+				"scope":           []string{"request", "response"},
+				"aggregateErrors": true,
+				"modifiers": []interface{}{
+					map[string]interface{}{"skip.RoundTrip": map[string]interface{}{"scope": []string{"request"}}},
+					map[string]interface{}{"status.Modifier": map[string]interface{}{"scope": []string{"response"}, "statusCode": status}},
+					map[string]interface{}{"body.Modifier": map[string]interface{}{"scope": []string{"response"}, "contentType": "application/json", "body": body}},
+				},
+			},
+		}
+	}
+	return "modifier/lua-backend", map[string]interface{}{
+		"pre":             fmt.Sprintf("if math.random() < %g then custom_error('chaos: injected failure', %d) end", errorRate, status),
+		"allow_open_libs": true,
+	}
+}
+
+// GenerateChaosConfig builds failure-testing variants of a configuration
+func GenerateChaosConfig(ctx context.Context, req *mcp.CallToolRequest, input GenerateChaosConfigInput) (*mcp.CallToolResult, GenerateChaosConfigOutput, error) {
+	configContent, err := readEncodedConfig(input.Config, input.ConfigEncoding)
+	if err != nil {
+		return nil, GenerateChaosConfigOutput{}, fmt.Errorf("failed to read config: %w", err)
+	}
+	var original map[string]interface{}
+	if err := json.Unmarshal([]byte(configContent), &original); err != nil {
+		return nil, GenerateChaosConfigOutput{}, fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	scenarios := input.Scenarios
+	if len(scenarios) == 0 {
+		scenarios = chaosScenarios
+	}
+	for _, s := range scenarios {
+		if !slices.Contains(chaosScenarios, s) {
+			return nil, GenerateChaosConfigOutput{}, fmt.Errorf("unknown scenario %q (available: %s)", s, strings.Join(chaosScenarios, ", "))
+		}
+	}
+
+	parseDuration := func(name, value, fallback string) (time.Duration, error) {
+		if value == "" {
+			value = fallback
+		}
+		d, err := time.ParseDuration(value)
+		if err != nil || d <= 0 {
+			return 0, fmt.Errorf("invalid %s %q", name, value)
+		}
+		return d, nil
+	}
+	delay, err := parseDuration("delay", input.Delay, defaultChaosDelay)
+	if err != nil {
+		return nil, GenerateChaosConfigOutput{}, err
+	}
+	timeout, err := parseDuration("timeout", input.Timeout, defaultChaosTimeout)
+	if err != nil {
+		return nil, GenerateChaosConfigOutput{}, err
+	}
+	errorRate := input.ErrorRate
+	if errorRate <= 0 {
+		errorRate = defaultChaosErrorRate
+	}
+	if errorRate > 1 {
+		return nil, GenerateChaosConfigOutput{}, fmt.Errorf("error_rate must be between 0 and 1")
+	}
+	status := input.ErrorStatus
+	if status == 0 {
+		status = defaultChaosErrorStatus
+	}
+	proxies := &chaosProxies{host: envOrDefault(input.ProxyHost, defaultChaosProxyHost), byHost: map[string]string{}}
+	serviceHosts, _ := original["host"].([]interface{})
+
+	output := GenerateChaosConfigOutput{Variants: []ChaosVariant{}}
+	matched := map[string]bool{}
+	overwritten := map[string]bool{}
+
+	for _, scenario := range scenarios {
+		// Every variant starts from a fresh copy of the original
+		var config map[string]interface{}
+		data, _ := json.Marshal(original)
+		json.Unmarshal(data, &config)
+
+		variant := ChaosVariant{Scenario: scenario, Config: config, Changes: []string{}}
+		switch scenario {
+		case "latency":
+			variant.Description = fmt.Sprintf("Backends answer %s later than usual", delay)
+		case "errors":
+			variant.Description = fmt.Sprintf("%g%% of backend calls fail with %d", errorRate*100, status)
+		case "timeouts":
+			variant.Description = fmt.Sprintf("Endpoints time out after %s", timeout)
+		}
+
+		endpoints, _ := config["endpoints"].([]interface{})
+		for i, ep := range endpoints {
+			endpoint, ok := ep.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			path, _ := endpoint["endpoint"].(string)
+			if len(input.Endpoints) > 0 && !slices.Contains(input.Endpoints, path) {
+				continue
+			}
+			matched[path] = true
+
+			if scenario == "timeouts" {
+				endpoint["timeout"] = timeout.String()
+				variant.Changes = append(variant.Changes, fmt.Sprintf("$.endpoints[%d].timeout", i))
+				continue
+			}
+
+			backends, _ := endpoint["backend"].([]interface{})
+			for j, b := range backends {
+				backend, ok := b.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				if scenario == "latency" {
+					// The backend answers late behind Toxiproxy: the gateway waits without spending CPU
+					hosts, _ := backend["host"].([]interface{})
+					if len(hosts) == 0 {
+						hosts = serviceHosts
+					}
+					if len(hosts) == 0 {
+						output.Warnings = append(output.Warnings, fmt.Sprintf("$.endpoints[%d].backend[%d] has no host to delay", i, j))
+						continue
+					}
+					proxied := make([]interface{}, 0, len(hosts))
+					for _, h := range hosts {
+						host, err := proxies.route(fmt.Sprint(h))
+						if err != nil {
+							return nil, GenerateChaosConfigOutput{}, err
+						}
+						proxied = append(proxied, host)
+					}
+					backend["host"] = proxied
+					variant.Changes = append(variant.Changes, fmt.Sprintf("$.endpoints[%d].backend[%d].host", i, j))
+					continue
+				}
+				extra, _ := backend["extra_config"].(map[string]interface{})
+				if extra == nil {
+					extra = map[string]interface{}{}
+					backend["extra_config"] = extra
+				}
+				namespace, value := chaosBackendModifier(errorRate, status)
+				location := fmt.Sprintf("$.endpoints[%d].backend[%d].extra_config.%s", i, j, namespace)
+				if _, exists := extra[namespace]; exists {
+					overwritten[location] = true
+				}
+				extra[namespace] = value
+				variant.Changes = append(variant.Changes, location)
+			}
+		}
+
+		if input.OutputDir != "" {
+			if err := os.MkdirAll(input.OutputDir, 0o755); err != nil {
+				return nil, GenerateChaosConfigOutput{}, fmt.Errorf("failed to create %s: %w", input.OutputDir, err)
+			}
+			variant.File = filepath.Join(input.OutputDir, "krakend.chaos-"+scenario+".json")
+			if err := writeJSONFile(variant.File, config, 0o644); err != nil {
+				return nil, GenerateChaosConfigOutput{}, err
+			}
+		}
+		output.Variants = append(output.Variants, variant)
+	}
+
+	if len(proxies.proxies) > 0 {
+		output.Toxiproxy = &ChaosToxiproxy{Proxies: proxies.proxies, Commands: []string{"toxiproxy-server -host 0.0.0.0 -config toxiproxy.json"}}
+		for _, proxy := range proxies.proxies {
+			output.Toxiproxy.Commands = append(output.Toxiproxy.Commands,
+				fmt.Sprintf("toxiproxy-cli toxic add -t latency -a latency=%d %s", delay.Milliseconds(), proxy.Name))
+		}
+		if input.OutputDir != "" {
+			output.Toxiproxy.File = filepath.Join(input.OutputDir, "toxiproxy.json")
+			if err := writeJSONFile(output.Toxiproxy.File, proxies.proxies, 0o644); err != nil {
+				return nil, GenerateChaosConfigOutput{}, err
+			}
+		}
+	}
+
+	for _, path := range input.Endpoints {
+		if !matched[path] {
+			output.Warnings = append(output.Warnings, fmt.Sprintf("Endpoint %s not found in the configuration", path))
+		}
+	}
+	locations := make([]string, 0, len(overwritten))
+	for location := range overwritten {
+		locations = append(locations, location)
+	}
+	sort.Strings(locations)
+	for _, location := range locations {
+		output.Warnings = append(output.Warnings, fmt.Sprintf("Existing modifier replaced at %s", location))
+	}
+
+	output.Notes = []string{
+		"Variants are for test environments only: never deploy them to production",
+		"Run a variant with krakend run -c krakend.chaos-<scenario>.json and point your clients to it",
+		"The latency variant routes the backends through Toxiproxy, reachable by the gateway at the proxy host: start it and add the latency toxics with the listed commands. HTTPS backends behind the proxy fail certificate checks unless the proxy host is in their certificate",
+		"Injected failures are visible in logs as \"chaos: injected failure\"",
+	}
+	return nil, output, nil
+}
+
+// RegisterChaosTools registers the fault-injection configuration generator
+func RegisterChaosTools(server *mcp.Server) {
+	toolkit.AddTool(server,
+		&mcp.Tool{
+			Name:        "generate_chaos_config",
+			Description: "Generate failure-testing variants of a KrakenD configuration so teams can rehearse how clients behave when backends degrade: backends delayed through Toxiproxy, a forced error rate (Lua, or martian modifiers for deterministic failures) and short-timeout endpoints. Optionally limited to some endpoints and written to files.",
+		},
+		GenerateChaosConfig,
+	)
+}
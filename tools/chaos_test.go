@@ -0,0 +1,142 @@
+package tools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const chaosTestConfig = `{
+	"version": 3,
+	"endpoints": [
+		{"endpoint": "/users", "backend": [{"host": ["http://users"], "url_pattern": "/users"}]},
+		{"endpoint": "/orders", "timeout": "5s", "backend": [{"host": ["http://orders"], "url_pattern": "/orders", "extra_config": {"modifier/lua-backend": {"pre": "print('x')"}}}]}
+	]
+}`
+
+func TestGenerateChaosConfig_AllScenarios(t *testing.T) {
+	dir := t.TempDir()
+	_, output, err := GenerateChaosConfig(context.Background(), nil, GenerateChaosConfigInput{Config: chaosTestConfig, OutputDir: dir})
+	if err != nil {
+		t.Fatalf("GenerateChaosConfig() error = %v", err)
+	}
+	if len(output.Variants) != 3 {
+		t.Fatalf("Expected 3 variants, got %d", len(output.Variants))
+	}
+
+	for _, v := range output.Variants {
+		if _, err := os.Stat(filepath.Join(dir, "krakend.chaos-"+v.Scenario+".json")); err != nil {
+			t.Errorf("Variant %s not written: %v", v.Scenario, err)
+		}
+		users := v.Config["endpoints"].([]interface{})[0].(map[string]interface{})
+		if v.Scenario == "timeouts" {
+			if users["timeout"] != "100ms" {
+				t.Errorf("Expected short timeout, got %v", users["timeout"])
+			}
+			continue
+		}
+		backend := users["backend"].([]interface{})[0].(map[string]interface{})
+		if v.Scenario == "latency" {
+			if hosts := backend["host"].([]interface{}); len(hosts) != 1 || hosts[0] != "http://toxiproxy:21000" {
+				t.Errorf("Expected the backend routed through Toxiproxy, got %v", hosts)
+			}
+			if _, ok := backend["extra_config"]; ok {
+				t.Errorf("The latency variant must not add modifiers, got %v", backend["extra_config"])
+			}
+			continue
+		}
+		lua := backend["extra_config"].(map[string]interface{})["modifier/lua-backend"].(map[string]interface{})
+		if !strings.Contains(lua["pre"].(string), "math.random() < 0.2") {
+			t.Errorf("Unexpected %s script %v", v.Scenario, lua["pre"])
+		}
+	}
+
+	toxiproxy := output.Toxiproxy
+	if toxiproxy == nil || len(toxiproxy.Proxies) != 2 || toxiproxy.Proxies[1].Upstream != "orders:80" || toxiproxy.Proxies[1].Listen != "0.0.0.0:21001" {
+		t.Fatalf("Expected one proxy per backend host, got %+v", toxiproxy)
+	}
+	if toxiproxy.Commands[1] != "toxiproxy-cli toxic add -t latency -a latency=1000 users_80" {
+		t.Errorf("Unexpected latency toxic command %q", toxiproxy.Commands[1])
+	}
+	if _, err := os.Stat(filepath.Join(dir, "toxiproxy.json")); err != nil {
+		t.Errorf("Toxiproxy configuration not written: %v", err)
+	}
+
+	if len(output.Warnings) != 1 || !strings.Contains(output.Warnings[0], "endpoints[1].backend[0]") {
+		t.Errorf("Expected a warning for the replaced Lua modifier, got %v", output.Warnings)
+	}
+}
+
+func TestGenerateChaosConfig_DeterministicErrors(t *testing.T) {
+	_, output, err := GenerateChaosConfig(context.Background(), nil, GenerateChaosConfigInput{
+		Config:    chaosTestConfig,
+		Scenarios: []string{"errors"},
+		Endpoints: []string{"/users", "/missing"},
+		ErrorRate: 1,
+	})
+	if err != nil {
+		t.Fatalf("GenerateChaosConfig() error = %v", err)
+	}
+
+	v := output.Variants[0]
+	if len(v.Changes) != 1 || !strings.HasSuffix(v.Changes[0], "modifier/martian") {
+		t.Errorf("Expected only /users to use martian, got %v", v.Changes)
+	}
+	orders := v.Config["endpoints"].([]interface{})[1].(map[string]interface{})
+	backend := orders["backend"].([]interface{})[0].(map[string]interface{})
+	if _, ok := backend["extra_config"].(map[string]interface{})["modifier/martian"]; ok {
+		t.Error("Endpoints outside the selection must not change")
+	}
+	if len(output.Warnings) != 1 || !strings.Contains(output.Warnings[0], "/missing") {
+		t.Errorf("Expected a warning for the unknown endpoint, got %v", output.Warnings)
+	}
+}
+
+func TestGenerateChaosConfig_LatencyServiceHosts(t *testing.T) {
+	config := `{"version": 3, "host": ["https://api.example.com"], "endpoints": [
+		{"endpoint": "/a", "backend": [{"url_pattern": "/a"}]},
+		{"endpoint": "/b", "backend": [{"url_pattern": "/b", "host": ["https://api.example.com"]}]}
+	]}`
+	_, output, err := GenerateChaosConfig(context.Background(), nil, GenerateChaosConfigInput{Config: config, Scenarios: []string{"latency"}, Delay: "250ms", ProxyHost: "chaos"})
+	if err != nil {
+		t.Fatalf("GenerateChaosConfig() error = %v", err)
+	}
+	for i, ep := range output.Variants[0].Config["endpoints"].([]interface{}) {
+		backend := ep.(map[string]interface{})["backend"].([]interface{})[0].(map[string]interface{})
+		if hosts := backend["host"].([]interface{}); hosts[0] != "https://chaos:21000" {
+			t.Errorf("Endpoint %d: expected the shared proxy, got %v", i, hosts)
+		}
+	}
+	if len(output.Toxiproxy.Proxies) != 1 || output.Toxiproxy.Proxies[0].Upstream != "api.example.com:443" || !strings.Contains(output.Toxiproxy.Commands[1], "latency=250") {
+		t.Errorf("Expected a single proxy delaying 250ms, got %+v", output.Toxiproxy)
+	}
+}
+
+func TestGenerateChaosConfig_LatencySchemelessHosts(t *testing.T) {
+	config := `{"version": 3, "endpoints": [{"endpoint": "/a", "backend": [{"url_pattern": "/a", "host": ["users:8080", "orders"]}]}]}`
+	_, output, err := GenerateChaosConfig(context.Background(), nil, GenerateChaosConfigInput{Config: config, Scenarios: []string{"latency"}})
+	if err != nil {
+		t.Fatalf("GenerateChaosConfig() error = %v", err)
+	}
+	backend := output.Variants[0].Config["endpoints"].([]interface{})[0].(map[string]interface{})["backend"].([]interface{})[0].(map[string]interface{})
+	if hosts := backend["host"].([]interface{}); hosts[0] != "http://toxiproxy:21000" || hosts[1] != "http://toxiproxy:21001" {
+		t.Errorf("Expected the hosts to go through their proxies over http, got %v", hosts)
+	}
+	if proxies := output.Toxiproxy.Proxies; len(proxies) != 2 || proxies[0].Upstream != "users:8080" || proxies[1].Upstream != "orders:80" {
+		t.Errorf("Unexpected proxies: %+v", proxies)
+	}
+}
+
+func TestGenerateChaosConfig_InvalidInput(t *testing.T) {
+	for name, input := range map[string]GenerateChaosConfigInput{
+		"scenario":   {Config: chaosTestConfig, Scenarios: []string{"meteor"}},
+		"delay":      {Config: chaosTestConfig, Delay: "soon"},
+		"error rate": {Config: chaosTestConfig, ErrorRate: 2},
+	} {
+		if _, _, err := GenerateChaosConfig(context.Background(), nil, input); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
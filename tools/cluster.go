@@ -0,0 +1,293 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/krakend/mcp-server/internal/toolkit"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// clusterNodeSettings are the root settings every node of a cluster must agree on
+var clusterNodeSettings = []string{"version", "port", "tls", "timeout", "cache_ttl", "output_encoding", "listen_ip", "use_h2c", "plugin"}
+
+// CheckClusterConsistencyInput defines input for check_cluster_consistency tool
+type CheckClusterConsistencyInput struct {
+	Configs []string `json:"configs" jsonschema:"Configuration of every node (JSON strings or file paths); a single configuration when all nodes share it"`
+	Nodes   int      `json:"nodes,omitempty" jsonschema:"Number of nodes running the configurations (optional, defaults to the number of configurations)"`
+}
+
+// SharedStateIssue is a feature keeping its state in the memory of each node
+type SharedStateIssue struct {
+	Config         string `json:"config"`
+	Path           string `json:"path"` // JSON path of the namespace
+	Namespace      string `json:"namespace"`
+	Problem        string `json:"problem"`
+	Recommendation string `json:"recommendation"`
+}
+
+// ClusterDifference is a setting whose value differs between node configurations
+type ClusterDifference struct {
+	Setting string            `json:"setting"` // JSON path, or METHOD /path for endpoints
+	Values  map[string]string `json:"values"`  // Value per configuration, "(missing)" when not set; endpoints show which variant each node declares
+}
+
+// CheckClusterConsistencyOutput defines output for check_cluster_consistency tool
+type CheckClusterConsistencyOutput struct {
+	Nodes       int                 `json:"nodes"`
+	Configs     []string            `json:"configs"`
+	SharedState []SharedStateIssue  `json:"shared_state"`
+	Differences []ClusterDifference `json:"differences"`
+	Consistent  bool                `json:"consistent"` // Node configurations agree on node-level settings and endpoints
+	Summary     string              `json:"summary"`
+}
+
+// clusterValue renders a setting for comparison: canonical JSON (map keys sorted)
+func clusterValue(v interface{}) string {
+	if v == nil {
+		return "(missing)"
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
+
+// perNodeLimit describes a limit enforced by each node and what the cluster lets through
+func perNodeLimit(settings map[string]interface{}, field string, nodes int) (string, bool) {
+	rate, ok := settings[field].(float64)
+	if !ok || rate <= 0 {
+		return "", false
+	}
+	every, _ := settings["every"].(string)
+	every = envOrDefault(every, "1s")
+	if nodes <= 1 {
+		return fmt.Sprintf("%s: %s every %s per node", field, formatFloat(rate), every), true
+	}
+	return fmt.Sprintf("%s: %s every %s per node, %s across %d nodes", field, formatFloat(rate), every, formatFloat(rate*float64(nodes)), nodes), true
+}
+
+// rateLimitIssue reports an in-memory rate limit, enforced by every node independently
+func rateLimitIssue(name, path, namespace string, settings map[string]interface{}, nodes int) (SharedStateIssue, bool) {
+	var limits []string
+	for _, field := range []string{"max_rate", "client_max_rate"} {
+		if limit, ok := perNodeLimit(settings, field, nodes); ok {
+			limits = append(limits, limit)
+		}
+	}
+	if len(limits) == 0 {
+		return SharedStateIssue{}, false
+	}
+	issue := SharedStateIssue{
+		Config:         name,
+		Path:           path,
+		Namespace:      namespace,
+		Problem:        fmt.Sprintf("The counters live in the memory of each node (%s): the load balancer multiplies the limit by the number of nodes", strings.Join(limits, "; ")),
+		Recommendation: "Use the Enterprise Redis-backed rate limit (qos/ratelimit/redis) to share the counters, or divide the limits by the number of nodes",
+	}
+	if nodes > 1 {
+		if rate, ok := settings["max_rate"].(float64); ok && rate > 0 {
+			issue.Recommendation += fmt.Sprintf(" (max_rate %s per node for %s in total)", formatFloat(rate/float64(nodes)), formatFloat(rate))
+		}
+	}
+	return issue, true
+}
+
+// clusterSharedState lists the features of a configuration keeping state in each node
+func clusterSharedState(name string, config map[string]interface{}, nodes int) []SharedStateIssue {
+	var issues []SharedStateIssue
+	service, _ := config["extra_config"].(map[string]interface{})
+	if settings, ok := service["qos/ratelimit/service"].(map[string]interface{}); ok {
+		if issue, ok := rateLimitIssue(name, "$.extra_config['qos/ratelimit/service']", "qos/ratelimit/service", settings, nodes); ok {
+			issues = append(issues, issue)
+		}
+	}
+	if _, ok := service["auth/revoker"]; ok {
+		issues = append(issues, SharedStateIssue{
+			Config:         name,
+			Path:           "$.extra_config['auth/revoker']",
+			Namespace:      "auth/revoker",
+			Problem:        "Each node keeps its own bloom filter of revoked tokens",
+			Recommendation: "Send every revocation to all the nodes through their RPC port (e.g. from a revoke server), or a token revoked on one node keeps working on the others",
+		})
+	}
+
+	endpoints, _ := config["endpoints"].([]interface{})
+	for i, ep := range endpoints {
+		endpoint, ok := ep.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		extra, _ := endpoint["extra_config"].(map[string]interface{})
+		if settings, ok := extra["qos/ratelimit/router"].(map[string]interface{}); ok {
+			path := fmt.Sprintf("$.endpoints[%d].extra_config['qos/ratelimit/router']", i)
+			if issue, ok := rateLimitIssue(name, path, "qos/ratelimit/router", settings, nodes); ok {
+				issues = append(issues, issue)
+			}
+		}
+		backends, _ := endpoint["backend"].([]interface{})
+		for j, b := range backends {
+			backend, ok := b.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			backendExtra, _ := backend["extra_config"].(map[string]interface{})
+			if settings, ok := backendExtra["qos/ratelimit/proxy"].(map[string]interface{}); ok {
+				if limit, ok := perNodeLimit(settings, "max_rate", nodes); ok {
+					issues = append(issues, SharedStateIssue{
+						Config:         name,
+						Path:           fmt.Sprintf("$.endpoints[%d].backend[%d].extra_config['qos/ratelimit/proxy']", i, j),
+						Namespace:      "qos/ratelimit/proxy",
+						Problem:        fmt.Sprintf("Each node limits the calls to the backend on its own (%s): the backend receives the sum of all nodes", limit),
+						Recommendation: "Divide max_rate by the number of nodes so the backend is protected with its real capacity",
+					})
+				}
+			}
+			if _, ok := backendExtra["qos/http-cache"]; ok {
+				issues = append(issues, SharedStateIssue{
+					Config:         name,
+					Path:           fmt.Sprintf("$.endpoints[%d].backend[%d].extra_config['qos/http-cache']", i, j),
+					Namespace:      "qos/http-cache",
+					Problem:        "The cache lives in the memory of each node: every node fills its own copy, lowering the hit ratio as the cluster grows",
+					Recommendation: "Accept per-node caches for short TTLs, or cache in a shared layer in front of the backends (e.g. a CDN or Varnish)",
+				})
+			}
+		}
+	}
+	return issues
+}
+
+// clusterEndpoints indexes the endpoints of a configuration by METHOD /path
+func clusterEndpoints(config map[string]interface{}) map[string]interface{} {
+	index := map[string]interface{}{}
+	endpoints, _ := config["endpoints"].([]interface{})
+	for _, ep := range endpoints {
+		endpoint, ok := ep.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		path, _ := endpoint["endpoint"].(string)
+		index[endpointMethod(endpoint)+" "+path] = endpoint
+	}
+	return index
+}
+
+// CheckClusterConsistency flags the features keeping state in each node of a cluster and the
+// node-level settings and endpoints that differ between the per-node configurations
+func CheckClusterConsistency(ctx context.Context, req *mcp.CallToolRequest, input CheckClusterConsistencyInput) (*mcp.CallToolResult, CheckClusterConsistencyOutput, error) {
+	if len(input.Configs) == 0 {
+		return nil, CheckClusterConsistencyOutput{}, fmt.Errorf("at least one configuration is required")
+	}
+	nodes := input.Nodes
+	if nodes <= 0 {
+		nodes = len(input.Configs)
+	}
+	if nodes < len(input.Configs) {
+		return nil, CheckClusterConsistencyOutput{}, fmt.Errorf("nodes (%d) cannot be less than the number of configurations (%d)", nodes, len(input.Configs))
+	}
+
+	output := CheckClusterConsistencyOutput{
+		Nodes:       nodes,
+		Configs:     []string{},
+		SharedState: []SharedStateIssue{},
+		Differences: []ClusterDifference{},
+	}
+	configs := make([]map[string]interface{}, 0, len(input.Configs))
+	for i, raw := range input.Configs {
+		name := dependencyConfigName(raw, i)
+		configContent, err := readConfigContent(raw)
+		if err != nil {
+			return nil, CheckClusterConsistencyOutput{}, fmt.Errorf("failed to read config %s: %w", name, err)
+		}
+		var config map[string]interface{}
+		if err := json.Unmarshal([]byte(configContent), &config); err != nil {
+			return nil, CheckClusterConsistencyOutput{}, fmt.Errorf("invalid JSON in %s: %w", name, err)
+		}
+		output.Configs = append(output.Configs, name)
+		configs = append(configs, config)
+		output.SharedState = append(output.SharedState, clusterSharedState(name, config, nodes)...)
+	}
+
+	// differ records a setting when the configurations do not all have the same value
+	differ := func(setting string, value func(config map[string]interface{}) interface{}) {
+		values := map[string]string{}
+		distinct := map[string]bool{}
+		for i, config := range configs {
+			v := clusterValue(value(config))
+			values[output.Configs[i]] = v
+			distinct[v] = true
+		}
+		if len(distinct) > 1 {
+			output.Differences = append(output.Differences, ClusterDifference{Setting: setting, Values: values})
+		}
+	}
+	for _, setting := range clusterNodeSettings {
+		differ("$."+setting, func(config map[string]interface{}) interface{} { return config[setting] })
+	}
+	service := map[string]bool{}
+	for _, config := range configs {
+		extra, _ := config["extra_config"].(map[string]interface{})
+		for namespace := range extra {
+			service[namespace] = true
+		}
+	}
+	for _, namespace := range slices.Sorted(maps.Keys(service)) {
+		differ(fmt.Sprintf("$.extra_config['%s']", namespace), func(config map[string]interface{}) interface{} {
+			extra, _ := config["extra_config"].(map[string]interface{})
+			return extra[namespace]
+		})
+	}
+
+	// Endpoints: declared by every node, then with the same definition
+	indexes := make([]map[string]interface{}, 0, len(configs))
+	routes := map[string]bool{}
+	for _, config := range configs {
+		index := clusterEndpoints(config)
+		indexes = append(indexes, index)
+		for route := range index {
+			routes[route] = true
+		}
+	}
+	for _, route := range slices.Sorted(maps.Keys(routes)) {
+		values := map[string]string{}
+		var variants []string
+		missing := false
+		for i, index := range indexes {
+			definition, ok := index[route]
+			if !ok {
+				values[output.Configs[i]] = "(missing)"
+				missing = true
+				continue
+			}
+			v := clusterValue(definition)
+			if !slices.Contains(variants, v) {
+				variants = append(variants, v)
+			}
+			values[output.Configs[i]] = fmt.Sprintf("variant %d", slices.Index(variants, v)+1)
+		}
+		if missing || len(variants) > 1 {
+			output.Differences = append(output.Differences, ClusterDifference{Setting: route, Values: values})
+		}
+	}
+
+	output.Consistent = len(output.Differences) == 0
+	output.Summary = fmt.Sprintf("%d node(s), %d configuration(s): %d feature(s) keeping per-node state, %d setting(s) or endpoint(s) differing between nodes",
+		nodes, len(configs), len(output.SharedState), len(output.Differences))
+	return nil, output, nil
+}
+
+// RegisterClusterTools registers the cluster consistency tool
+func RegisterClusterTools(server *mcp.Server) {
+	toolkit.AddTool(server,
+		&mcp.Tool{
+			Name:        "check_cluster_consistency",
+			Description: "Check a multi-node KrakenD deployment: flags features keeping their state in each node (in-memory rate limits, HTTP caches, token revocation) with the limits the cluster really enforces and the distributed alternatives, and compares per-node configuration variants for differing node-level settings (port, TLS, timeouts), service namespaces and endpoints.",
+		},
+		CheckClusterConsistency,
+	)
+}
@@ -0,0 +1,75 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestCheckClusterConsistency(t *testing.T) {
+	nodeA := `{"version": 3, "port": 8080, "tls": {"min_version": "TLS13"},
+		"extra_config": {"qos/ratelimit/service": {"max_rate": 1000}},
+		"endpoints": [
+			{"endpoint": "/a", "backend": [{"url_pattern": "/a", "extra_config": {"qos/http-cache": {}}}],
+			 "extra_config": {"qos/ratelimit/router": {"max_rate": 90, "client_max_rate": 5}}},
+			{"endpoint": "/b", "backend": [{"url_pattern": "/b"}]},
+			{"endpoint": "/c", "backend": [{"url_pattern": "/c"}]}
+		]}`
+	nodeB := `{"version": 3, "port": 8081, "tls": {"min_version": "TLS12"},
+		"extra_config": {"qos/ratelimit/service": {"max_rate": 1000}},
+		"endpoints": [
+			{"endpoint": "/a", "backend": [{"url_pattern": "/a", "extra_config": {"qos/http-cache": {}}}],
+			 "extra_config": {"qos/ratelimit/router": {"max_rate": 90, "client_max_rate": 5}}},
+			{"endpoint": "/b", "backend": [{"url_pattern": "/b-v2"}]}
+		]}`
+	_, output, err := CheckClusterConsistency(context.Background(), nil, CheckClusterConsistencyInput{Configs: []string{nodeA, nodeB}, Nodes: 3})
+	if err != nil {
+		t.Fatalf("CheckClusterConsistency() error = %v", err)
+	}
+	if output.Consistent {
+		t.Error("Expected the node configurations to be inconsistent")
+	}
+	settings := map[string]ClusterDifference{}
+	for _, d := range output.Differences {
+		settings[d.Setting] = d
+	}
+	for _, want := range []string{"$.port", "$.tls", "GET /b", "GET /c"} {
+		if _, ok := settings[want]; !ok {
+			t.Errorf("Expected %s to differ, got %+v", want, output.Differences)
+		}
+	}
+	if _, ok := settings["GET /a"]; ok {
+		t.Error("GET /a is identical on both nodes")
+	}
+	if settings["GET /c"].Values["config-2"] != "(missing)" || settings["GET /b"].Values["config-2"] != "variant 2" {
+		t.Errorf("Unexpected endpoint differences: %+v %+v", settings["GET /c"], settings["GET /b"])
+	}
+
+	// Service and router rate limits plus the HTTP cache, on both nodes
+	if len(output.SharedState) != 6 {
+		t.Fatalf("Expected 6 shared state issues, got %+v", output.SharedState)
+	}
+	var router SharedStateIssue
+	for _, issue := range output.SharedState {
+		if issue.Namespace == "qos/ratelimit/router" {
+			router = issue
+		}
+	}
+	if !strings.Contains(router.Problem, "270 across 3 nodes") || !strings.Contains(router.Recommendation, "max_rate 30 per node") {
+		t.Errorf("Expected the cluster-wide limit and the per-node split, got %+v", router)
+	}
+}
+
+func TestCheckClusterConsistency_SingleConfig(t *testing.T) {
+	config := `{"version": 3, "endpoints": [{"endpoint": "/a", "backend": [{"url_pattern": "/a"}]}]}`
+	_, output, err := CheckClusterConsistency(context.Background(), nil, CheckClusterConsistencyInput{Configs: []string{config}})
+	if err != nil {
+		t.Fatalf("CheckClusterConsistency() error = %v", err)
+	}
+	if !output.Consistent || len(output.SharedState) != 0 || output.Nodes != 1 {
+		t.Errorf("Expected a consistent single node, got %+v", output)
+	}
+	if _, _, err := CheckClusterConsistency(context.Background(), nil, CheckClusterConsistencyInput{Configs: []string{config, config}, Nodes: 1}); err == nil {
+		t.Error("Expected an error with fewer nodes than configurations")
+	}
+}
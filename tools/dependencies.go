@@ -0,0 +1,285 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/krakend/mcp-server/internal/toolkit"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// defaultSPOFEndpoints is the number of endpoints behind a single host making it a single point of failure
+const defaultSPOFEndpoints = 3
+
+// MapDependenciesInput defines input for map_dependencies tool
+type MapDependenciesInput struct {
+	Configs      []string `json:"configs" jsonschema:"KrakenD configurations (JSON strings or file paths) mapped together"`
+	MinEndpoints int      `json:"min_endpoints,omitempty" jsonschema:"Endpoints behind a single host to flag it as a single point of failure (optional, defaults to 3)"`
+}
+
+// DependencyBackend is a backend of an endpoint and the hosts it calls
+type DependencyBackend struct {
+	URLPattern string   `json:"url_pattern"`
+	Hosts      []string `json:"hosts"`
+	SD         string   `json:"sd"` // Service discovery ("static" when not set)
+}
+
+// DependencyEndpoint is an endpoint of a configuration with its backends
+type DependencyEndpoint struct {
+	Config   string              `json:"config"`
+	Method   string              `json:"method"`
+	Endpoint string              `json:"endpoint"`
+	Name     string              `json:"name,omitempty"` // From the name_endpoints sidecar file
+	Tags     []string            `json:"tags,omitempty"`
+	Backends []DependencyBackend `json:"backends"`
+}
+
+// DependencyHost is a backend host with the endpoints depending on it
+type DependencyHost struct {
+	Host      string   `json:"host"`
+	Endpoints []string `json:"endpoints"` // "config: METHOD /path"
+	Configs   []string `json:"configs"`
+	Redundant bool     `json:"redundant"` // Every backend using it lists other hosts too
+	SPOF      bool     `json:"single_point_of_failure"`
+}
+
+// MapDependenciesOutput defines output for map_dependencies tool
+type MapDependenciesOutput struct {
+	Endpoints             []DependencyEndpoint `json:"endpoints"`
+	Hosts                 []DependencyHost     `json:"hosts"`
+	SinglePointsOfFailure []string             `json:"single_points_of_failure"`
+	Mermaid               string               `json:"mermaid"`
+	DOT                   string               `json:"dot"`
+	Summary               string               `json:"summary"`
+}
+
+// dependencyConfigName names a configuration in the graph: its path, or its position when given inline
+func dependencyConfigName(input string, i int) string {
+	trimmed := strings.TrimSpace(input)
+	if strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[") {
+		return fmt.Sprintf("config-%d", i+1)
+	}
+	return input
+}
+
+// MapDependencies builds the graph of endpoints, backends and hosts of one or many configurations
+func MapDependencies(ctx context.Context, req *mcp.CallToolRequest, input MapDependenciesInput) (*mcp.CallToolResult, MapDependenciesOutput, error) {
+	if len(input.Configs) == 0 {
+		return nil, MapDependenciesOutput{}, fmt.Errorf("at least one configuration is required")
+	}
+	minEndpoints := input.MinEndpoints
+	if minEndpoints <= 0 {
+		minEndpoints = defaultSPOFEndpoints
+	}
+
+	output := MapDependenciesOutput{Endpoints: []DependencyEndpoint{}, Hosts: []DependencyHost{}, SinglePointsOfFailure: []string{}}
+	hosts := map[string]*DependencyHost{}
+	single := map[string]bool{} // Hosts that are the only host of at least one backend
+
+	for i, raw := range input.Configs {
+		name := dependencyConfigName(raw, i)
+		configContent, err := readConfigContent(raw)
+		if err != nil {
+			return nil, MapDependenciesOutput{}, fmt.Errorf("failed to read config %s: %w", name, err)
+		}
+		var config map[string]interface{}
+		if err := json.Unmarshal([]byte(configContent), &config); err != nil {
+			return nil, MapDependenciesOutput{}, fmt.Errorf("invalid JSON in %s: %w", name, err)
+		}
+
+		names := loadEndpointNames(raw)
+		endpoints, _ := config["endpoints"].([]interface{})
+		for _, ep := range endpoints {
+			endpoint, ok := ep.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			node := DependencyEndpoint{Config: name, Method: endpointMethod(endpoint), Backends: []DependencyBackend{}}
+			node.Endpoint, _ = endpoint["endpoint"].(string)
+			if named, ok := names[endpointKey(node.Method, node.Endpoint)]; ok {
+				node.Name, node.Tags = named.Name, named.Tags
+			}
+			label := fmt.Sprintf("%s: %s %s", name, node.Method, node.Endpoint)
+
+			backends, _ := endpoint["backend"].([]interface{})
+			for _, b := range backends {
+				backend, ok := b.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				dep := DependencyBackend{Hosts: []string{}, SD: "static"}
+				dep.URLPattern, _ = backend["url_pattern"].(string)
+				if sd, ok := backend["sd"].(string); ok && sd != "" {
+					dep.SD = sd
+				}
+				// Backends without host use the service-level default
+				declared, _ := backend["host"].([]interface{})
+				if len(declared) == 0 {
+					declared, _ = config["host"].([]interface{})
+				}
+				for _, h := range declared {
+					if host, ok := h.(string); ok && host != "" {
+						dep.Hosts = append(dep.Hosts, host)
+					}
+				}
+				for _, host := range dep.Hosts {
+					entry, ok := hosts[host]
+					if !ok {
+						entry = &DependencyHost{Host: host, Endpoints: []string{}, Configs: []string{}}
+						hosts[host] = entry
+					}
+					if !slices.Contains(entry.Endpoints, label) {
+						entry.Endpoints = append(entry.Endpoints, label)
+					}
+					if !slices.Contains(entry.Configs, name) {
+						entry.Configs = append(entry.Configs, name)
+					}
+					if len(dep.Hosts) == 1 {
+						single[host] = true
+					}
+				}
+				node.Backends = append(node.Backends, dep)
+			}
+			output.Endpoints = append(output.Endpoints, node)
+		}
+	}
+
+	for _, entry := range hosts {
+		entry.Redundant = !single[entry.Host]
+		entry.SPOF = !entry.Redundant && len(entry.Endpoints) >= minEndpoints
+		if entry.SPOF {
+			output.SinglePointsOfFailure = append(output.SinglePointsOfFailure,
+				fmt.Sprintf("%s serves %d endpoint(s) across %d config(s) without an alternative host", entry.Host, len(entry.Endpoints), len(entry.Configs)))
+		}
+		output.Hosts = append(output.Hosts, *entry)
+	}
+	sort.Slice(output.Hosts, func(i, j int) bool {
+		a, b := output.Hosts[i], output.Hosts[j]
+		if len(a.Endpoints) == len(b.Endpoints) {
+			return a.Host < b.Host
+		}
+		return len(a.Endpoints) > len(b.Endpoints)
+	})
+	sort.Strings(output.SinglePointsOfFailure)
+
+	output.Mermaid = renderDependenciesMermaid(output)
+	output.DOT = renderDependenciesDOT(output)
+	output.Summary = fmt.Sprintf("%d endpoint(s) in %d config(s) depend on %d host(s); %d single point(s) of failure with %d or more endpoints",
+		len(output.Endpoints), len(input.Configs), len(output.Hosts), len(output.SinglePointsOfFailure), minEndpoints)
+	return nil, output, nil
+}
+
+// dependencyHostIDs assigns stable node IDs to hosts
+func dependencyHostIDs(graph MapDependenciesOutput) map[string]string {
+	ids := map[string]string{}
+	for i, h := range graph.Hosts {
+		ids[h.Host] = fmt.Sprintf("h%d", i)
+	}
+	return ids
+}
+
+// dependencyEndpointLabel labels an endpoint node with its method and path, under its name when known
+func dependencyEndpointLabel(ep DependencyEndpoint, lineBreak string) string {
+	if ep.Name == "" {
+		return ep.Method + " " + ep.Endpoint
+	}
+	return ep.Name + lineBreak + ep.Method + " " + ep.Endpoint
+}
+
+// renderDependenciesMermaid renders the graph as a Mermaid flowchart, one subgraph per config
+func renderDependenciesMermaid(graph MapDependenciesOutput) string {
+	hostIDs := dependencyHostIDs(graph)
+	label := func(s string) string { return strings.ReplaceAll(s, `"`, "#quot;") }
+
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+	config := ""
+	for i, ep := range graph.Endpoints {
+		if ep.Config != config {
+			if config != "" {
+				b.WriteString("  end\n")
+			}
+			config = ep.Config
+			fmt.Fprintf(&b, "  subgraph c%d[\"%s\"]\n", i, label(config))
+		}
+		fmt.Fprintf(&b, "    e%d[\"%s\"]\n", i, label(dependencyEndpointLabel(ep, "<br/>")))
+	}
+	if config != "" {
+		b.WriteString("  end\n")
+	}
+	for _, h := range graph.Hosts {
+		fmt.Fprintf(&b, "  %s[(\"%s\")]\n", hostIDs[h.Host], label(h.Host))
+	}
+	for i, ep := range graph.Endpoints {
+		for j, backend := range ep.Backends {
+			id := fmt.Sprintf("e%db%d", i, j)
+			fmt.Fprintf(&b, "  e%d --> %s[\"%s\"]\n", i, id, label(backend.URLPattern))
+			for _, host := range backend.Hosts {
+				fmt.Fprintf(&b, "  %s --> %s\n", id, hostIDs[host])
+			}
+		}
+	}
+	b.WriteString("  classDef spof fill:#f96,stroke:#c00,stroke-width:2px\n")
+	for _, h := range graph.Hosts {
+		if h.SPOF {
+			fmt.Fprintf(&b, "  class %s spof\n", hostIDs[h.Host])
+		}
+	}
+	return b.String()
+}
+
+// renderDependenciesDOT renders the graph in Graphviz DOT, one cluster per config
+func renderDependenciesDOT(graph MapDependenciesOutput) string {
+	hostIDs := dependencyHostIDs(graph)
+	label := func(s string) string { return strings.ReplaceAll(s, `"`, `\"`) }
+
+	var b strings.Builder
+	b.WriteString("digraph dependencies {\n  rankdir=LR;\n  node [shape=box];\n")
+	config := ""
+	for i, ep := range graph.Endpoints {
+		if ep.Config != config {
+			if config != "" {
+				b.WriteString("  }\n")
+			}
+			config = ep.Config
+			fmt.Fprintf(&b, "  subgraph cluster_%d {\n    label=\"%s\";\n", i, label(config))
+		}
+		fmt.Fprintf(&b, "    e%d [label=\"%s\"];\n", i, label(dependencyEndpointLabel(ep, `\n`)))
+	}
+	if config != "" {
+		b.WriteString("  }\n")
+	}
+	for _, h := range graph.Hosts {
+		attrs := ""
+		if h.SPOF {
+			attrs = ", color=red, style=filled, fillcolor=\"#ffddcc\""
+		}
+		fmt.Fprintf(&b, "  %s [label=\"%s\", shape=cylinder%s];\n", hostIDs[h.Host], label(h.Host), attrs)
+	}
+	for i, ep := range graph.Endpoints {
+		for j, backend := range ep.Backends {
+			id := fmt.Sprintf("e%db%d", i, j)
+			fmt.Fprintf(&b, "  %s [label=\"%s\", shape=ellipse];\n  e%d -> %s;\n", id, label(backend.URLPattern), i, id)
+			for _, host := range backend.Hosts {
+				fmt.Fprintf(&b, "  %s -> %s;\n", id, hostIDs[host])
+			}
+		}
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// RegisterDependencyTools registers cross-config dependency mapping tools
+func RegisterDependencyTools(server *mcp.Server) {
+	toolkit.AddTool(server,
+		&mcp.Tool{
+			Name:        "map_dependencies",
+			Description: "Build the dependency graph endpoints → backends → hosts across one or many KrakenD configurations, exported as Mermaid and Graphviz DOT. Flags single points of failure: hosts without an alternative behind many endpoints. Use it for architecture reviews and impact analysis of backend outages.",
+		},
+		MapDependencies,
+	)
+}
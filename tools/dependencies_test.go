@@ -0,0 +1,63 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestMapDependencies(t *testing.T) {
+	users := `{"version": 3, "host": ["http://users:8080"], "endpoints": [
+		{"endpoint": "/users", "backend": [{"url_pattern": "/users"}]},
+		{"endpoint": "/users/{id}", "method": "DELETE", "backend": [{"url_pattern": "/users/{id}"}]},
+		{"endpoint": "/profile", "backend": [
+			{"url_pattern": "/me", "host": ["http://users:8080"]},
+			{"url_pattern": "/prefs", "host": ["http://prefs-a:8080", "http://prefs-b:8080"]}
+		]}
+	]}`
+	orders := `{"version": 3, "endpoints": [
+		{"endpoint": "/orders", "backend": [{"url_pattern": "/orders", "host": ["http://orders:8080"]}]},
+		{"endpoint": "/orders/{id}/user", "backend": [{"url_pattern": "/users/{id}", "host": ["http://users:8080"]}]}
+	]}`
+
+	_, output, err := MapDependencies(context.Background(), nil, MapDependenciesInput{Configs: []string{users, orders}})
+	if err != nil {
+		t.Fatalf("MapDependencies() error = %v", err)
+	}
+	if len(output.Endpoints) != 5 || len(output.Hosts) != 4 {
+		t.Fatalf("Expected 5 endpoints and 4 hosts, got %d/%d", len(output.Endpoints), len(output.Hosts))
+	}
+
+	top := output.Hosts[0]
+	if top.Host != "http://users:8080" || len(top.Endpoints) != 4 || len(top.Configs) != 2 || !top.SPOF {
+		t.Errorf("Expected users to be a single point of failure across both configs, got %+v", top)
+	}
+	for _, h := range output.Hosts[1:] {
+		if h.SPOF {
+			t.Errorf("Unexpected single point of failure: %+v", h)
+		}
+		if strings.HasPrefix(h.Host, "http://prefs") && !h.Redundant {
+			t.Errorf("Hosts sharing a backend are redundant, got %+v", h)
+		}
+	}
+	if len(output.SinglePointsOfFailure) != 1 {
+		t.Errorf("Expected 1 single point of failure, got %v", output.SinglePointsOfFailure)
+	}
+
+	for _, want := range []string{"flowchart LR", `subgraph c0["config-1"]`, `e1["DELETE /users/{id}"]`, "class h0 spof"} {
+		if !strings.Contains(output.Mermaid, want) {
+			t.Errorf("Mermaid missing %q:\n%s", want, output.Mermaid)
+		}
+	}
+	for _, want := range []string{"digraph dependencies", "subgraph cluster_3", "h0 [label=\"http://users:8080\", shape=cylinder, color=red"} {
+		if !strings.Contains(output.DOT, want) {
+			t.Errorf("DOT missing %q:\n%s", want, output.DOT)
+		}
+	}
+}
+
+func TestMapDependencies_RequiresConfigs(t *testing.T) {
+	if _, _, err := MapDependencies(context.Background(), nil, MapDependenciesInput{}); err == nil {
+		t.Error("Expected an error without configurations")
+	}
+}
@@ -0,0 +1,165 @@
+package tools
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math/rand"
+	"net"
+	"os"
+	"strings"
+)
+
+// DNS record types used when querying TTLs
+const (
+	dnsTypeA    uint16 = 1
+	dnsTypeAAAA uint16 = 28
+	dnsTypeSRV  uint16 = 33
+)
+
+// The Go resolver does not expose record TTLs, so a minimal DNS client is used
+// to query them directly from the configured nameserver.
+
+// systemNameserver returns the first nameserver listed in /etc/resolv.conf
+func systemNameserver() (string, error) {
+	f, err := os.Open("/etc/resolv.conf")
+	if err != nil {
+		return "", fmt.Errorf("failed to read resolv.conf: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 2 && fields[0] == "nameserver" {
+			return net.JoinHostPort(fields[1], "53"), nil
+		}
+	}
+	return "", fmt.Errorf("no nameserver found in resolv.conf")
+}
+
+// queryDNSTTL asks nameserver for records of qtype and returns the lowest TTL among the answers
+func queryDNSTTL(ctx context.Context, nameserver, name string, qtype uint16) (uint32, error) {
+	query, err := buildDNSQuery(uint16(rand.Intn(1<<16)), name, qtype)
+	if err != nil {
+		return 0, err
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "udp", nameserver)
+	if err != nil {
+		return 0, fmt.Errorf("failed to contact nameserver: %w", err)
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	if _, err := conn.Write(query); err != nil {
+		return 0, fmt.Errorf("failed to send DNS query: %w", err)
+	}
+
+	buf := make([]byte, 4096)
+	n, err := conn.Read(buf)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read DNS response: %w", err)
+	}
+
+	return parseDNSAnswerTTL(buf[:n], qtype)
+}
+
+// buildDNSQuery encodes a recursive DNS query for a single name and type
+func buildDNSQuery(id uint16, name string, qtype uint16) ([]byte, error) {
+	msg := make([]byte, 12, 512)
+	binary.BigEndian.PutUint16(msg[0:], id)
+	binary.BigEndian.PutUint16(msg[2:], 0x0100) // Recursion desired
+	binary.BigEndian.PutUint16(msg[4:], 1)      // One question
+
+	for _, label := range strings.Split(strings.TrimSuffix(name, "."), ".") {
+		if len(label) == 0 || len(label) > 63 {
+			return nil, fmt.Errorf("invalid DNS name %q", name)
+		}
+		msg = append(msg, byte(len(label)))
+		msg = append(msg, label...)
+	}
+	msg = append(msg, 0)
+	msg = binary.BigEndian.AppendUint16(msg, qtype)
+	msg = binary.BigEndian.AppendUint16(msg, 1) // Class IN
+
+	return msg, nil
+}
+
+// parseDNSAnswerTTL returns the lowest TTL of the answers matching qtype
+func parseDNSAnswerTTL(msg []byte, qtype uint16) (uint32, error) {
+	if len(msg) < 12 {
+		return 0, fmt.Errorf("DNS response too short")
+	}
+	if rcode := msg[3] & 0x0f; rcode != 0 {
+		return 0, fmt.Errorf("DNS query failed with rcode %d", rcode)
+	}
+	qdcount := binary.BigEndian.Uint16(msg[4:])
+	ancount := binary.BigEndian.Uint16(msg[6:])
+
+	offset := 12
+	for i := 0; i < int(qdcount); i++ {
+		next, err := skipDNSName(msg, offset)
+		if err != nil {
+			return 0, err
+		}
+		offset = next + 4 // Type and class
+	}
+
+	found := false
+	var minTTL uint32
+	for i := 0; i < int(ancount); i++ {
+		next, err := skipDNSName(msg, offset)
+		if err != nil {
+			return 0, err
+		}
+		if next+10 > len(msg) {
+			return 0, fmt.Errorf("truncated DNS answer")
+		}
+		rtype := binary.BigEndian.Uint16(msg[next:])
+		ttl := binary.BigEndian.Uint32(msg[next+4:])
+		rdlength := int(binary.BigEndian.Uint16(msg[next+8:]))
+		offset = next + 10 + rdlength
+		if offset > len(msg) {
+			return 0, fmt.Errorf("truncated DNS answer")
+		}
+
+		if rtype == qtype && (!found || ttl < minTTL) {
+			minTTL = ttl
+			found = true
+		}
+	}
+
+	if !found {
+		return 0, fmt.Errorf("no matching DNS answers")
+	}
+	return minTTL, nil
+}
+
+// skipDNSName returns the offset right after the (possibly compressed) name at offset.
+// Compression pointers end the name in place and are not followed, so they cannot loop.
+func skipDNSName(msg []byte, offset int) (int, error) {
+	for {
+		if offset >= len(msg) {
+			return 0, fmt.Errorf("truncated DNS name")
+		}
+		length := int(msg[offset])
+		switch {
+		case length == 0:
+			return offset + 1, nil
+		case length&0xc0 == 0xc0:
+			if offset+2 > len(msg) {
+				return 0, fmt.Errorf("truncated DNS name")
+			}
+			return offset + 2, nil
+		case length&0xc0 != 0:
+			return 0, fmt.Errorf("invalid DNS label type 0x%x", length&0xc0)
+		default:
+			offset += length + 1
+		}
+	}
+}
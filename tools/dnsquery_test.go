@@ -0,0 +1,128 @@
+package tools
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+// dnsResponse builds a response to a query for api.example.com with answers of the given names
+// (raw wire bytes), types and TTLs
+func dnsResponse(t *testing.T, answers ...dnsTestAnswer) []byte {
+	t.Helper()
+	msg, err := buildDNSQuery(42, "api.example.com", dnsTypeA)
+	if err != nil {
+		t.Fatalf("buildDNSQuery() error = %v", err)
+	}
+	binary.BigEndian.PutUint16(msg[2:], 0x8180) // Response, no error
+	binary.BigEndian.PutUint16(msg[6:], uint16(len(answers)))
+	for _, a := range answers {
+		msg = append(msg, a.name...)
+		msg = binary.BigEndian.AppendUint16(msg, a.rtype)
+		msg = binary.BigEndian.AppendUint16(msg, 1)
+		msg = binary.BigEndian.AppendUint32(msg, a.ttl)
+		msg = binary.BigEndian.AppendUint16(msg, 4)
+		msg = append(msg, 10, 0, 0, 1)
+	}
+	return msg
+}
+
+type dnsTestAnswer struct {
+	name  []byte
+	rtype uint16
+	ttl   uint32
+}
+
+// questionPointer points to the name of the question, right after the header
+var questionPointer = []byte{0xc0, 0x0c}
+
+func TestParseDNSAnswerTTL_Wire(t *testing.T) {
+	full := []byte("\x03api\x07example\x03com\x00")
+	// A label followed by a pointer to "example.com" in the question
+	partial := []byte("\x03www\xc0\x10")
+
+	tests := []struct {
+		name    string
+		msg     func() []byte
+		want    uint32
+		wantErr bool
+	}{
+		{"uncompressed name", func() []byte { return dnsResponse(t, dnsTestAnswer{full, dnsTypeA, 120}) }, 120, false},
+		{"compressed name", func() []byte { return dnsResponse(t, dnsTestAnswer{questionPointer, dnsTypeA, 90}) }, 90, false},
+		{"label then pointer", func() []byte { return dnsResponse(t, dnsTestAnswer{partial, dnsTypeA, 30}) }, 30, false},
+		{"lowest matching TTL", func() []byte {
+			return dnsResponse(t, dnsTestAnswer{questionPointer, dnsTypeAAAA, 5}, dnsTestAnswer{questionPointer, dnsTypeA, 300}, dnsTestAnswer{questionPointer, dnsTypeA, 60})
+		}, 60, false},
+		{"pointer to itself", func() []byte {
+			msg := dnsResponse(t, dnsTestAnswer{questionPointer, dnsTypeA, 45})
+			// The answer name points to its own offset: pointers are not followed
+			offset := len(msg) - 16
+			binary.BigEndian.PutUint16(msg[offset:], 0xc000|uint16(offset))
+			return msg
+		}, 45, false},
+		{"header only", func() []byte { return dnsResponse(t)[:8] }, 0, true},
+		{"error rcode", func() []byte {
+			msg := dnsResponse(t, dnsTestAnswer{questionPointer, dnsTypeA, 60})
+			msg[3] |= 3 // NXDOMAIN
+			return msg
+		}, 0, true},
+		{"truncated question", func() []byte { return dnsResponse(t, dnsTestAnswer{questionPointer, dnsTypeA, 60})[:20] }, 0, true},
+		{"truncated pointer", func() []byte {
+			msg := dnsResponse(t)
+			binary.BigEndian.PutUint16(msg[6:], 1)
+			return append(msg, 0xc0)
+		}, 0, true},
+		{"truncated record header", func() []byte {
+			msg := dnsResponse(t, dnsTestAnswer{questionPointer, dnsTypeA, 60})
+			return msg[:len(msg)-10] // Name, type and class only
+		}, 0, true},
+		{"truncated rdata", func() []byte {
+			msg := dnsResponse(t, dnsTestAnswer{questionPointer, dnsTypeA, 60})
+			return msg[:len(msg)-2]
+		}, 0, true},
+		{"reserved label type", func() []byte { return dnsResponse(t, dnsTestAnswer{[]byte{0x40, 0x00}, dnsTypeA, 60}) }, 0, true},
+		{"no answers", func() []byte { return dnsResponse(t) }, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseDNSAnswerTTL(tt.msg(), dnsTypeA)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseDNSAnswerTTL() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parseDNSAnswerTTL() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSkipDNSName(t *testing.T) {
+	tests := []struct {
+		name    string
+		msg     []byte
+		offset  int
+		want    int
+		wantErr bool
+	}{
+		{"root", []byte{0}, 0, 1, false},
+		{"labels", []byte("\x01a\x02bc\x00rest"), 0, 6, false},
+		{"pointer", []byte{0xc0, 0x00, 0xff}, 0, 2, false},
+		{"labels then pointer", []byte("\x01a\xc0\x00"), 0, 4, false},
+		{"from offset", []byte("xx\x01a\x00"), 2, 5, false},
+		{"missing terminator", []byte("\x01a"), 0, 0, true},
+		{"label past the end", []byte("\x05ab"), 0, 0, true},
+		{"half pointer", []byte{0xc0}, 0, 0, true},
+		{"extended label", []byte{0x80, 0x00}, 0, 0, true},
+		{"offset past the end", []byte{0}, 3, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := skipDNSName(tt.msg, tt.offset)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("skipDNSName() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("skipDNSName() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
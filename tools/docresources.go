@@ -0,0 +1,214 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/blevesearch/bleve/v2"
+	"github.com/krakend/mcp-server/internal/events"
+	"github.com/krakend/mcp-server/internal/indexing"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// URIs of the documentation resources. Chunks and pages are addressed by the IDs returned in
+// search results, pages also by their documentation URL.
+const (
+	docIndexURI          = "krakend://docs/index"
+	docChunkURITemplate  = "krakend://docs/chunks/{id}"
+	docChunkURIPrefix    = "krakend://docs/chunks/"
+	docPageURITemplate   = "krakend://docs/pages/{id}"
+	docPageURIPrefix     = "krakend://docs/pages/"
+	docPageByURLTemplate = "krakend://docs/pages{?url}"
+)
+
+var docIDPattern = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+// DocIndexPage is a page of the documentation index resource
+type DocIndexPage struct {
+	ID        string `json:"id"`
+	Title     string `json:"title"`
+	URL       string `json:"url,omitempty"`
+	UpdatedAt string `json:"updated_at,omitempty"`
+	Chunks    int    `json:"chunks"`
+	Resource  string `json:"resource"`
+}
+
+// DocPageResource is a documentation page with its chunks in reading order
+type DocPageResource struct {
+	ID        string              `json:"id"`
+	Title     string              `json:"title"`
+	URL       string              `json:"url,omitempty"`
+	UpdatedAt string              `json:"updated_at,omitempty"`
+	Chunks    []indexing.DocChunk `json:"chunks"`
+}
+
+// chunkResourceURI returns the resource with the whole page of a chunk, or with the chunk alone
+// when the index predates page tracking
+func chunkResourceURI(chunk indexing.DocChunk) string {
+	if chunk.PageID != "" {
+		return docPageURIPrefix + chunk.PageID
+	}
+	return docChunkURIPrefix + chunk.ID
+}
+
+// readDocManifest reads the page manifest written with the index
+func readDocManifest() (*indexing.PageManifest, error) {
+	manifest, err := indexing.ReadPageManifest(filepath.Join(dataDir, pageManifest))
+	if err != nil {
+		return nil, fmt.Errorf("page manifest unavailable, run refresh_documentation_index: %w", err)
+	}
+	return manifest, nil
+}
+
+// normalizeDocURL drops the fragment and trailing slash of a documentation URL
+func normalizeDocURL(raw string) string {
+	raw, _, _ = strings.Cut(raw, "#")
+	return strings.TrimSuffix(raw, "/")
+}
+
+// fetchDocChunks returns the indexed chunks with the given IDs, in the same order
+func fetchDocChunks(ids []string) ([]indexing.DocChunk, error) {
+	request := bleve.NewSearchRequest(bleve.NewDocIDQuery(ids))
+	request.Size = len(ids)
+	request.Fields = []string{"*"}
+	var hits *bleve.SearchResult
+	err := withDocIndex(func(index Index) (err error) {
+		hits, err = index.Search(request)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[string]indexing.DocChunk, len(hits.Hits))
+	for _, hit := range hits.Hits {
+		byID[hit.ID] = chunkFromHit(hit)
+	}
+	chunks := make([]indexing.DocChunk, 0, len(ids))
+	for _, id := range ids {
+		if chunk, ok := byID[id]; ok {
+			chunks = append(chunks, chunk)
+		}
+	}
+	return chunks, nil
+}
+
+// readDocIndexResource serves the list of documentation pages
+func readDocIndexResource(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+	manifest, err := readDocManifest()
+	if err != nil {
+		return nil, err
+	}
+	pages := make([]DocIndexPage, 0, len(manifest.Pages))
+	for id, page := range manifest.Pages {
+		pages = append(pages, DocIndexPage{
+			ID:        id,
+			Title:     page.Title,
+			URL:       page.URL,
+			UpdatedAt: page.UpdatedAt,
+			Chunks:    len(page.Chunks),
+			Resource:  docPageURIPrefix + id,
+		})
+	}
+	sort.Slice(pages, func(i, j int) bool { return pages[i].Title < pages[j].Title })
+	return jsonResource(req.Params.URI, map[string]interface{}{"pages": pages})
+}
+
+// readDocChunkResource serves a single documentation chunk
+func readDocChunkResource(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+	uri := req.Params.URI
+	id := strings.TrimPrefix(uri, docChunkURIPrefix)
+	if !docIDPattern.MatchString(id) {
+		return nil, mcp.ResourceNotFoundError(uri)
+	}
+	chunks, err := fetchDocChunks([]string{id})
+	if err != nil {
+		return nil, err
+	}
+	if len(chunks) == 0 {
+		return nil, mcp.ResourceNotFoundError(uri)
+	}
+	return jsonResource(uri, chunks[0])
+}
+
+// readDocPageResource serves every chunk of a documentation page, addressed by page ID or by URL
+func readDocPageResource(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+	uri := req.Params.URI
+	manifest, err := readDocManifest()
+	if err != nil {
+		return nil, err
+	}
+
+	var id string
+	if rest, ok := strings.CutPrefix(uri, docPageURIPrefix); ok {
+		id = rest
+	} else if u, err := url.Parse(uri); err == nil && u.Query().Get("url") != "" {
+		wanted := normalizeDocURL(u.Query().Get("url"))
+		for pageID, page := range manifest.Pages {
+			if page.URL != "" && normalizeDocURL(page.URL) == wanted {
+				id = pageID
+				break
+			}
+		}
+	}
+	page, ok := manifest.Pages[id]
+	if !ok || !docIDPattern.MatchString(id) {
+		return nil, mcp.ResourceNotFoundError(uri)
+	}
+
+	chunks, err := fetchDocChunks(page.Chunks)
+	if err != nil {
+		return nil, err
+	}
+	return jsonResource(uri, DocPageResource{ID: id, Title: page.Title, URL: page.URL, UpdatedAt: page.UpdatedAt, Chunks: chunks})
+}
+
+// registerDocResources registers the documentation resources: the list of pages, and templates
+// for pages and chunks. It returns the number of resources and templates registered.
+func registerDocResources(server *mcp.Server) int {
+	server.AddResource(&mcp.Resource{
+		URI:         docIndexURI,
+		Name:        "docs-index",
+		Title:       "KrakenD documentation pages",
+		Description: "Every indexed documentation page with its title, URL, last update and the resource with its content",
+		MIMEType:    jsonMIMEType,
+	}, readDocIndexResource)
+	server.AddResourceTemplate(&mcp.ResourceTemplate{
+		URITemplate: docPageURITemplate,
+		Name:        "docs-page",
+		Title:       "KrakenD documentation page",
+		Description: "Every chunk of a documentation page, in reading order: id is the page_id of a search result",
+		MIMEType:    jsonMIMEType,
+	}, readDocPageResource)
+	server.AddResourceTemplate(&mcp.ResourceTemplate{
+		URITemplate: docPageByURLTemplate,
+		Name:        "docs-page-by-url",
+		Title:       "KrakenD documentation page by URL",
+		Description: "Every chunk of the documentation page at a URL, e.g. https://www.krakend.io/docs/endpoints/rate-limit/",
+		MIMEType:    jsonMIMEType,
+	}, readDocPageResource)
+	server.AddResourceTemplate(&mcp.ResourceTemplate{
+		URITemplate: docChunkURITemplate,
+		Name:        "docs-chunk",
+		Title:       "KrakenD documentation chunk",
+		Description: "A single documentation chunk: id is the chunk id of a search result",
+		MIMEType:    jsonMIMEType,
+	}, readDocChunkResource)
+	return 4
+}
+
+// NotifyDocResourceUpdates notifies the clients subscribed to the documentation pages resource
+// when a refresh changes the index. It returns the function stopping the notifications.
+func NotifyDocResourceUpdates(server *mcp.Server) (stop func()) {
+	return events.Subscribe(events.DocsRefreshed, func(events.Event) {
+		if err := server.ResourceUpdated(context.Background(), &mcp.ResourceUpdatedNotificationParams{URI: docIndexURI}); err != nil {
+			slog.Warn("Could not notify the documentation update", "error", err)
+		}
+	})
+}
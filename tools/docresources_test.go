@@ -0,0 +1,130 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/krakend/mcp-server/internal/events"
+	"github.com/krakend/mcp-server/internal/indexing"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// useTestDocPages installs an index and a page manifest with a two-chunk rate limit page
+func useTestDocPages(t *testing.T) {
+	t.Helper()
+	useTestDocIndex(t, []indexing.DocChunk{
+		{ID: "page_rl_1", PageID: "page_rl", Page: "Rate limit", Content: "Second part on burst.", URL: "https://www.krakend.io/docs/endpoints/rate-limit/#burst"},
+		{ID: "page_rl_0", PageID: "page_rl", Page: "Rate limit", Content: "The qos/ratelimit/router namespace limits the endpoint.", URL: "https://www.krakend.io/docs/endpoints/rate-limit/"},
+		{ID: "page_cors_0", PageID: "page_cors", Page: "CORS", Content: "Enable CORS with security/cors.", URL: "https://www.krakend.io/docs/service-settings/cors/"},
+	})
+	origDataDir := dataDir
+	dataDir = t.TempDir()
+	t.Cleanup(func() { dataDir = origDataDir })
+	os.MkdirAll(filepath.Join(dataDir, "docs"), 0o755)
+	err := indexing.WritePageManifest(filepath.Join(dataDir, pageManifest), &indexing.PageManifest{Pages: map[string]indexing.PageEntry{
+		"page_rl":   {Title: "Rate limit", URL: "https://www.krakend.io/docs/endpoints/rate-limit/", Chunks: []string{"page_rl_0", "page_rl_1"}},
+		"page_cors": {Title: "CORS", URL: "https://www.krakend.io/docs/service-settings/cors/", Chunks: []string{"page_cors_0"}},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDocResources(t *testing.T) {
+	useTestDocPages(t)
+	session := connectResources(t)
+
+	tests := []struct {
+		uri  string
+		want []string
+	}{
+		{docIndexURI, []string{`"resource": "krakend://docs/pages/page_cors"`, `"chunks": 2`}},
+		{"krakend://docs/chunks/page_rl_1", []string{`"Second part on burst."`}},
+		{"krakend://docs/pages/page_rl", []string{`"title": "Rate limit"`, `"id": "page_rl_0"`}},
+		{"krakend://docs/pages?url=https%3A%2F%2Fwww.krakend.io%2Fdocs%2Fendpoints%2Frate-limit%2F%23burst", []string{`"id": "page_rl"`}},
+	}
+	for _, tt := range tests {
+		res, err := session.ReadResource(context.Background(), &mcp.ReadResourceParams{URI: tt.uri})
+		if err != nil {
+			t.Errorf("ReadResource(%s) error = %v", tt.uri, err)
+			continue
+		}
+		text := res.Contents[0].Text
+		for _, want := range tt.want {
+			if !strings.Contains(text, want) {
+				t.Errorf("ReadResource(%s) = %s, want %s", tt.uri, text, want)
+			}
+		}
+	}
+
+	// Chunks of a page are in reading order
+	res, err := session.ReadResource(context.Background(), &mcp.ReadResourceParams{URI: "krakend://docs/pages/page_rl"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var page DocPageResource
+	json.Unmarshal([]byte(res.Contents[0].Text), &page)
+	if len(page.Chunks) != 2 || page.Chunks[0].ID != "page_rl_0" || page.Chunks[1].ID != "page_rl_1" {
+		t.Errorf("Unexpected page chunks: %+v", page.Chunks)
+	}
+
+	for _, uri := range []string{"krakend://docs/chunks/missing", "krakend://docs/pages/page_unknown", "krakend://docs/pages?url=https%3A%2F%2Fexample.com%2F"} {
+		if _, err := session.ReadResource(context.Background(), &mcp.ReadResourceParams{URI: uri}); err == nil {
+			t.Errorf("ReadResource(%s) expected an error", uri)
+		}
+	}
+}
+
+func TestSearchDocumentation_ResourceLinks(t *testing.T) {
+	useTestDocPages(t)
+	_, output, err := SearchDocumentation(context.Background(), nil, SearchDocumentationInput{Query: "burst"})
+	if err != nil {
+		t.Fatalf("SearchDocumentation() error = %v", err)
+	}
+	if len(output.Results) == 0 || output.Results[0].Resource != "krakend://docs/pages/page_rl" {
+		t.Errorf("Expected the page resource in the results, got %+v", output.Results)
+	}
+}
+
+func TestNotifyDocResourceUpdates(t *testing.T) {
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "0.0.1"}, &mcp.ServerOptions{
+		SubscribeHandler:   func(context.Context, *mcp.SubscribeRequest) error { return nil },
+		UnsubscribeHandler: func(context.Context, *mcp.UnsubscribeRequest) error { return nil },
+	})
+	registerDocResources(server)
+	defer NotifyDocResourceUpdates(server)()
+
+	updated := make(chan string, 1)
+	client := mcp.NewClient(&mcp.Implementation{Name: "test", Version: "0.0.1"}, &mcp.ClientOptions{
+		ResourceUpdatedHandler: func(_ context.Context, req *mcp.ResourceUpdatedNotificationRequest) {
+			updated <- req.Params.URI
+		},
+	})
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	if _, err := server.Connect(context.Background(), serverTransport, nil); err != nil {
+		t.Fatal(err)
+	}
+	session, err := client.Connect(context.Background(), clientTransport, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer session.Close()
+	if err := session.Subscribe(context.Background(), &mcp.SubscribeParams{URI: docIndexURI}); err != nil {
+		t.Fatal(err)
+	}
+
+	events.Publish(events.DocsRefreshed, "", docsRefresh{Pages: 10, Changed: 1, Partial: true})
+	select {
+	case uri := <-updated:
+		if uri != docIndexURI {
+			t.Errorf("Updated %s, want %s", uri, docIndexURI)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected a resource update notification after the documentation refresh")
+	}
+}
@@ -0,0 +1,115 @@
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	docsMaxAgeEnv     = "KRAKEND_MCP_DOCS_MAX_AGE" // Days before the documentation snapshot is reported as outdated ("0" disables)
+	defaultDocsMaxAge = 30
+)
+
+// DocsSnapshot describes the documentation snapshot the search index was built from
+type DocsSnapshot struct {
+	Date       string `json:"date,omitempty"` // When the documentation was downloaded (RFC3339)
+	AgeDays    int    `json:"age_days"`
+	Embedded   bool   `json:"embedded"` // Snapshot taken when the binary was built
+	MaxAgeDays int    `json:"max_age_days"`
+	Outdated   bool   `json:"outdated"`
+	Warning    string `json:"warning,omitempty"`
+}
+
+// cacheMeta is the documentation metadata written by build.sh and by refreshes
+type cacheMeta struct {
+	DownloadedAt string `json:"downloaded_at"`
+	SourceURL    string `json:"source_url,omitempty"`
+	SizeBytes    int64  `json:"size_bytes,omitempty"`
+	Embedded     bool   `json:"embedded"`
+	ETag         string `json:"etag,omitempty"` // Sent back as If-None-Match to skip unchanged downloads
+}
+
+// readCacheMeta reads the metadata of the downloaded documentation; errors give empty metadata
+func readCacheMeta() cacheMeta {
+	var meta cacheMeta
+	if data, err := os.ReadFile(filepath.Join(dataDir, cacheMetaFile)); err == nil {
+		_ = json.Unmarshal(data, &meta)
+	}
+	return meta
+}
+
+// writeCacheMeta stores the metadata of the downloaded documentation
+func writeCacheMeta(meta cacheMeta) error {
+	data, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dataDir, cacheMetaFile), data, 0o644)
+}
+
+// parseCacheMeta reads the snapshot date of a cache.meta file, accepting the JSON format and
+// the former "last_update: <date>" line
+func parseCacheMeta(data []byte) (time.Time, bool, error) {
+	var meta cacheMeta
+	if err := json.Unmarshal(data, &meta); err == nil {
+		date, err := time.Parse(time.RFC3339, meta.DownloadedAt)
+		return date, meta.Embedded, err
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if value, ok := strings.CutPrefix(strings.TrimSpace(line), "last_update:"); ok {
+			date, err := time.Parse(time.RFC3339, strings.TrimSpace(value))
+			return date, false, err
+		}
+	}
+	return time.Time{}, false, fmt.Errorf("no download date in cache metadata")
+}
+
+// docsMaxAgeDays returns the configured maximum age of the documentation, in days
+func docsMaxAgeDays() int {
+	if v := os.Getenv(docsMaxAgeEnv); v != "" {
+		if days, err := strconv.Atoi(v); err == nil && days >= 0 {
+			return days
+		}
+		slog.Warn("Invalid number of days, using the default", "env", docsMaxAgeEnv, "value", v, "default", defaultDocsMaxAge)
+	}
+	return defaultDocsMaxAge
+}
+
+// currentDocsSnapshot describes the documentation in the data directory, falling back to the
+// snapshot embedded in the binary
+func currentDocsSnapshot(now time.Time) DocsSnapshot {
+	snapshot := DocsSnapshot{MaxAgeDays: docsMaxAgeDays()}
+	data, err := os.ReadFile(filepath.Join(dataDir, cacheMetaFile))
+	if err != nil {
+		data, err = defaultDataProvider.ReadFile("data/docs/cache.meta")
+	}
+	var date time.Time
+	var embedded bool
+	if err == nil {
+		date, embedded, err = parseCacheMeta(data)
+	}
+	if err != nil {
+		snapshot.Warning = "The documentation snapshot date is unknown: run refresh_documentation_index to download the current documentation."
+		return snapshot
+	}
+
+	snapshot.Date = date.UTC().Format(time.RFC3339)
+	snapshot.Embedded = embedded
+	snapshot.AgeDays = int(now.Sub(date).Hours() / 24)
+	if snapshot.MaxAgeDays > 0 && snapshot.AgeDays > snapshot.MaxAgeDays {
+		snapshot.Outdated = true
+		source := "Downloaded"
+		if embedded {
+			source = "Embedded"
+		}
+		snapshot.Warning = fmt.Sprintf("%s documentation is %d days old (snapshot of %s): results may miss recent features, run refresh_documentation_index or check https://www.krakend.io/docs/.",
+			source, snapshot.AgeDays, date.UTC().Format("2006-01-02"))
+	}
+	return snapshot
+}
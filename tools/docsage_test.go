@@ -0,0 +1,58 @@
+package tools
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseCacheMeta(t *testing.T) {
+	date, embedded, err := parseCacheMeta([]byte(`{"downloaded_at": "2025-01-10T08:00:00Z", "source_url": "https://www.krakend.io/llms-full.txt", "embedded": true}`))
+	if err != nil || !embedded || date.Format("2006-01-02") != "2025-01-10" {
+		t.Errorf("JSON metadata: got %v, %v, %v", date, embedded, err)
+	}
+
+	date, embedded, err = parseCacheMeta([]byte("last_update: 2025-02-01T10:00:00+01:00\n"))
+	if err != nil || embedded || date.UTC().Format(time.RFC3339) != "2025-02-01T09:00:00Z" {
+		t.Errorf("Legacy metadata: got %v, %v, %v", date, embedded, err)
+	}
+
+	if _, _, err := parseCacheMeta([]byte("size: 12")); err == nil {
+		t.Error("Expected an error for metadata without date")
+	}
+}
+
+func TestCurrentDocsSnapshot(t *testing.T) {
+	oldDataDir := dataDir
+	dataDir = t.TempDir()
+	defer func() { dataDir = oldDataDir }()
+	if err := os.MkdirAll(filepath.Join(dataDir, "docs"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	meta := `{"downloaded_at": "2025-01-01T00:00:00Z", "embedded": true}`
+	if err := os.WriteFile(filepath.Join(dataDir, cacheMetaFile), []byte(meta), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2025, 3, 2, 0, 0, 0, 0, time.UTC)
+
+	t.Setenv(docsMaxAgeEnv, "")
+	snapshot := currentDocsSnapshot(now)
+	if snapshot.Date != "2025-01-01T00:00:00Z" || snapshot.AgeDays != 60 || !snapshot.Embedded {
+		t.Errorf("Unexpected snapshot: %+v", snapshot)
+	}
+	if !snapshot.Outdated || snapshot.MaxAgeDays != defaultDocsMaxAge || !strings.Contains(snapshot.Warning, "60 days old") {
+		t.Errorf("Expected an outdated warning with the default max age, got %+v", snapshot)
+	}
+
+	t.Setenv(docsMaxAgeEnv, "90")
+	if snapshot := currentDocsSnapshot(now); snapshot.Outdated || snapshot.Warning != "" {
+		t.Errorf("Expected no warning within 90 days, got %+v", snapshot)
+	}
+
+	t.Setenv(docsMaxAgeEnv, "0")
+	if snapshot := currentDocsSnapshot(now); snapshot.Outdated {
+		t.Errorf("Expected the warning disabled, got %+v", snapshot)
+	}
+}
@@ -0,0 +1,191 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/blevesearch/bleve/v2"
+	"github.com/krakend/mcp-server/internal/features"
+	"github.com/krakend/mcp-server/internal/toolkit"
+	"github.com/krakend/mcp-server/tools/validation"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// docsCoverageHits is the number of chunks read per namespace to collect its documentation URLs
+const docsCoverageHits = 5
+
+// DocsCoverageInput defines input for docs_coverage tool
+type DocsCoverageInput struct {
+	Config string `json:"config" jsonschema:"KrakenD configuration (JSON string or file path)"`
+}
+
+// NamespaceDocs is the documentation found for a namespace of the configuration
+type NamespaceDocs struct {
+	Namespace    string   `json:"namespace"`
+	Covered      bool     `json:"covered"` // At least one local documentation chunk mentions it
+	CanonicalURL string   `json:"canonical_url,omitempty"`
+	URLs         []string `json:"urls"`   // Pages of the chunks mentioning it
+	Chunks       int      `json:"chunks"` // Chunks mentioning it in the local index
+}
+
+// DocsCoverageOutput defines output for docs_coverage tool
+type DocsCoverageOutput struct {
+	Namespaces []NamespaceDocs `json:"namespaces"`
+	Covered    int             `json:"covered"`
+	Missing    []string        `json:"missing"`        // Namespaces without local documentation
+	URLs       []string        `json:"canonical_urls"` // One documentation page per covered namespace, deduplicated
+	Docs       DocsSnapshot    `json:"docs"`
+	Summary    string          `json:"summary"`
+}
+
+// catalogDocsURL returns the documentation page of a namespace in the feature catalog
+func catalogDocsURL(namespace string) string {
+	if featureCatalog == nil {
+		_ = LoadFeatureData()
+	}
+	if featureCatalog == nil {
+		return ""
+	}
+	for _, f := range featureCatalog.Features {
+		if f.Namespace == namespace {
+			return f.DocsURL
+		}
+	}
+	return ""
+}
+
+// pageURL removes the anchor of a chunk URL
+func pageURL(url string) string {
+	page, _, _ := strings.Cut(url, "#")
+	return page
+}
+
+// namespaceDocs searches the index for the chunks mentioning a namespace
+func namespaceDocs(index Index, namespace string) (NamespaceDocs, error) {
+	docs := NamespaceDocs{Namespace: namespace, URLs: []string{}}
+	query := bleve.NewMatchPhraseQuery(namespace)
+	query.SetField("content")
+	search := bleve.NewSearchRequest(query)
+	search.Size = docsCoverageHits
+	search.Fields = []string{"url"}
+	result, err := index.Search(search)
+	if err != nil {
+		return docs, err
+	}
+
+	docs.Chunks = int(result.Total)
+	docs.Covered = docs.Chunks > 0
+	for _, hit := range result.Hits {
+		if url, ok := hit.Fields["url"].(string); ok && url != "" && !slices.Contains(docs.URLs, url) {
+			docs.URLs = append(docs.URLs, url)
+		}
+	}
+	// The feature catalog names the reference page; the best ranked chunk otherwise
+	docs.CanonicalURL = catalogDocsURL(namespace)
+	if docs.CanonicalURL == "" && len(docs.URLs) > 0 {
+		docs.CanonicalURL = pageURL(docs.URLs[0])
+	}
+	return docs, nil
+}
+
+func init() {
+	validation.DocLookup = namespaceErrorDoc
+}
+
+// namespaceErrorDoc links a validation error to the best ranked chunk mentioning its namespace.
+// Validation does not wait for the index: nothing is found while it initializes.
+func namespaceErrorDoc(namespace string) *validation.ErrorDoc {
+	indexPtr := indexMgr.current.Load()
+	if indexPtr == nil {
+		return nil
+	}
+	query := bleve.NewMatchPhraseQuery(namespace)
+	query.SetField("content")
+	search := bleve.NewSearchRequest(query)
+	search.Size = 1
+	search.Fields = []string{"*"}
+	result, err := (*indexPtr).Search(search)
+	if err != nil || len(result.Hits) == 0 {
+		return nil
+	}
+	chunk := chunkFromHit(result.Hits[0])
+	doc := &validation.ErrorDoc{Title: chunk.Breadcrumb, URL: chunk.URL, Resource: chunkResourceURI(chunk)}
+	if doc.Title == "" {
+		doc.Title = chunk.Page
+	}
+	// The feature catalog names the reference page, when already loaded
+	if featureCatalog != nil {
+		for _, f := range featureCatalog.Features {
+			if f.Namespace == namespace && f.DocsURL != "" {
+				doc.URL = f.DocsURL
+			}
+		}
+	}
+	if doc.URL == "" {
+		return nil
+	}
+	return doc
+}
+
+// DocsCoverage checks that every namespace of a configuration is documented in the local index
+func DocsCoverage(ctx context.Context, req *mcp.CallToolRequest, input DocsCoverageInput) (*mcp.CallToolResult, DocsCoverageOutput, error) {
+	configContent, err := readConfigContent(input.Config)
+	if err != nil {
+		return nil, DocsCoverageOutput{}, fmt.Errorf("failed to read config: %w", err)
+	}
+	var config map[string]interface{}
+	if err := json.Unmarshal([]byte(configContent), &config); err != nil {
+		return nil, DocsCoverageOutput{}, fmt.Errorf("invalid JSON: %w", err)
+	}
+	namespaces := features.FindNamespacesInConfig(config)
+	sort.Strings(namespaces)
+
+	output := DocsCoverageOutput{Namespaces: []NamespaceDocs{}, Missing: []string{}, URLs: []string{}, Docs: currentDocsSnapshot(time.Now())}
+	err = withDocIndex(func(index Index) error {
+		for _, namespace := range namespaces {
+			docs, err := namespaceDocs(index, namespace)
+			if err != nil {
+				return fmt.Errorf("search failed for %s: %w", namespace, err)
+			}
+			output.Namespaces = append(output.Namespaces, docs)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, DocsCoverageOutput{}, err
+	}
+
+	for _, docs := range output.Namespaces {
+		if !docs.Covered {
+			output.Missing = append(output.Missing, docs.Namespace)
+			continue
+		}
+		output.Covered++
+		if docs.CanonicalURL != "" && !slices.Contains(output.URLs, docs.CanonicalURL) {
+			output.URLs = append(output.URLs, docs.CanonicalURL)
+		}
+	}
+
+	output.Summary = fmt.Sprintf("%d of %d namespace(s) documented in the local index", output.Covered, len(namespaces))
+	if len(output.Missing) > 0 {
+		output.Summary += fmt.Sprintf("; no local documentation for %s: run refresh_documentation_index or look them up on https://www.krakend.io/docs/ (they may also be custom plugin namespaces)",
+			strings.Join(output.Missing, ", "))
+	}
+	return nil, output, nil
+}
+
+// RegisterDocsCoverageTools registers the documentation coverage tool
+func RegisterDocsCoverageTools(server *mcp.Server) {
+	toolkit.AddTool(server,
+		&mcp.Tool{
+			Name:        "docs_coverage",
+			Description: "For every namespace used in a KrakenD configuration, check that the local documentation index has chunks about it and return its canonical documentation URL. Flags namespaces without local docs, so you know a documentation refresh or a web lookup is needed before explaining them.",
+		},
+		DocsCoverage,
+	)
+}
@@ -0,0 +1,91 @@
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/blevesearch/bleve/v2"
+	"github.com/krakend/mcp-server/internal/indexing"
+)
+
+// useTestDocIndex installs an in-memory documentation index with the given chunks
+func useTestDocIndex(t *testing.T, chunks []indexing.DocChunk) {
+	t.Helper()
+	index, err := bleve.NewMemOnly(bleve.NewIndexMapping())
+	if err != nil {
+		t.Fatalf("Failed to create index: %v", err)
+	}
+	for _, chunk := range chunks {
+		if err := index.Index(chunk.ID, chunk); err != nil {
+			t.Fatalf("Failed to index chunk: %v", err)
+		}
+	}
+	oldMgr := indexMgr
+	indexMgr = &indexHolder{}
+	wrapped := NewBleveIndexWrapper(index)
+	indexMgr.current.Store(&wrapped)
+	t.Cleanup(func() {
+		index.Close()
+		indexMgr = oldMgr
+	})
+}
+
+func TestDocsCoverage(t *testing.T) {
+	setMockFeatureFetcher(t, minimalFeatureYAML)
+	useTestDocIndex(t, []indexing.DocChunk{
+		{ID: "1", Content: "Enable CORS with the security/cors namespace at the service level.", URL: "https://www.krakend.io/docs/service-settings/cors/#configuration"},
+		{ID: "2", Content: "The qos/ratelimit/router namespace limits the endpoint.", URL: "https://www.krakend.io/docs/endpoints/rate-limit/#configuration"},
+	})
+
+	config := `{
+		"version": 3,
+		"extra_config": {"security/cors": {"allow_origins": ["*"]}},
+		"endpoints": [{
+			"endpoint": "/users",
+			"extra_config": {
+				"qos/ratelimit/router": {"max_rate": 10},
+				"plugin/acme-custom": {}
+			},
+			"backend": [{"url_pattern": "/users"}]
+		}]
+	}`
+	_, output, err := DocsCoverage(context.Background(), nil, DocsCoverageInput{Config: config})
+	if err != nil {
+		t.Fatalf("DocsCoverage() error = %v", err)
+	}
+	if len(output.Namespaces) != 3 || output.Covered != 2 {
+		t.Fatalf("Expected 2 of 3 namespaces covered, got %+v", output.Namespaces)
+	}
+	if len(output.Missing) != 1 || output.Missing[0] != "plugin/acme-custom" {
+		t.Errorf("Expected plugin/acme-custom missing, got %v", output.Missing)
+	}
+
+	byNamespace := map[string]NamespaceDocs{}
+	for _, docs := range output.Namespaces {
+		byNamespace[docs.Namespace] = docs
+	}
+	// The feature catalog URL wins over the chunk URL
+	if got := byNamespace["security/cors"].CanonicalURL; got != "https://www.krakend.io/docs/cors" {
+		t.Errorf("Expected catalog URL for security/cors, got %q", got)
+	}
+	if got := byNamespace["qos/ratelimit/router"].CanonicalURL; got != "https://www.krakend.io/docs/endpoints/rate-limit/" {
+		t.Errorf("Expected chunk page URL for qos/ratelimit/router, got %q", got)
+	}
+	if len(output.URLs) != 2 {
+		t.Errorf("Expected 2 canonical URLs, got %v", output.URLs)
+	}
+}
+
+func TestNamespaceErrorDoc(t *testing.T) {
+	useTestDocIndex(t, []indexing.DocChunk{
+		{ID: "1", Page: "Rate limiting", Breadcrumb: "Rate limiting > Endpoint rate limit", Content: "The qos/ratelimit/router namespace limits the endpoint.", URL: "https://www.krakend.io/docs/endpoints/rate-limit/#configuration", PageID: "rate-limit"},
+	})
+
+	doc := namespaceErrorDoc("qos/ratelimit/router")
+	if doc == nil || doc.URL != "https://www.krakend.io/docs/endpoints/rate-limit/#configuration" || doc.Title != "Rate limiting > Endpoint rate limit" || doc.Resource == "" {
+		t.Errorf("Unexpected documentation %+v", doc)
+	}
+	if doc := namespaceErrorDoc("plugin/acme-custom"); doc != nil {
+		t.Errorf("Expected nothing for an undocumented namespace, got %+v", doc)
+	}
+}
@@ -2,12 +2,14 @@ package tools
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"net/http"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -15,27 +17,112 @@ import (
 	"time"
 
 	"github.com/blevesearch/bleve/v2"
+	"github.com/blevesearch/bleve/v2/search"
+	"github.com/krakend/mcp-server/internal/events"
 	"github.com/krakend/mcp-server/internal/indexing"
+	"github.com/krakend/mcp-server/internal/metrics"
+	"github.com/krakend/mcp-server/internal/pages"
+	"github.com/krakend/mcp-server/internal/toolkit"
+	"github.com/krakend/mcp-server/tools/validation"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
+// Documentation source, configurable at startup with ConfigureDocs
+var (
+	docsURL  = DefaultDocsURL
+	cacheTTL = DefaultCacheTTL
+)
+
 const (
-	docsURL       = "https://www.krakend.io/llms-full.txt"
-	cacheTTL      = 7 * 24 * time.Hour // 7 days
+	// DefaultDocsURL is the full KrakenD documentation indexed for search
+	DefaultDocsURL = "https://www.krakend.io/llms-full.txt"
+	// DefaultCacheTTL is how long downloaded documentation and catalogs are used before refreshing them
+	DefaultCacheTTL = 7 * 24 * time.Hour
+
 	maxResults    = 10
 	docsFile      = "docs/llms-full.txt"
 	cacheMetaFile = "docs/cache.meta"
+	pageManifest  = "docs/" + indexing.PageManifestFile
 	indexDir      = "search/index"
 	lockFile      = "search/index.lock"
 	lockTimeout   = 5 * time.Second // Max time to wait for lock
 	lockRetryWait = 500 * time.Millisecond
 
 	indexVersionFile = "search/.index_version"
+	schemasDir       = "schemas"
+	crashesDir       = "crashes"
+
+	dataDirEnv      = "KRAKEND_MCP_DATA_DIR"
+	crashReportsEnv = "KRAKEND_MCP_CRASH_REPORTS" // "0" disables crash report files
 )
 
 var dataDir string // Data directory for documentation and search index
 
 func init() {
+	// Strategy 0: Explicit data directory (e.g. chosen during setup)
+	if dir := os.Getenv(dataDirEnv); dir != "" {
+		err := setDataDir(dir)
+		if err == nil {
+			slog.Info("Data directory", "path", dataDir, "source", dataDirEnv)
+			return
+		}
+		slog.Warn("Could not use the data directory", "env", dataDirEnv, "path", dir, "error", err)
+	}
+
+	resolveDataDir()
+	configureDataDirConsumers()
+}
+
+// configureDataDirConsumers points the packages that store files in the data directory to it
+func configureDataDirConsumers() {
+	validation.SchemaCacheDir = filepath.Join(dataDir, schemasDir)
+	if os.Getenv(crashReportsEnv) == "0" {
+		toolkit.SetCrashReportDir("")
+	} else {
+		toolkit.SetCrashReportDir(filepath.Join(dataDir, crashesDir))
+	}
+}
+
+// SetDataDir switches the data directory chosen at startup, creating its layout
+func SetDataDir(dir string) error {
+	if err := setDataDir(dir); err != nil {
+		return err
+	}
+	slog.Info("Data directory", "path", dataDir)
+	return nil
+}
+
+// DataDir returns the data directory in use
+func DataDir() string {
+	return dataDir
+}
+
+// ConfigureDocs sets the documentation source and the cache TTL of the downloaded documentation,
+// feature matrix and plugin catalog
+func ConfigureDocs(url string, ttl time.Duration) {
+	docsURL = url
+	cacheTTL = ttl
+	featureCacheTTL = ttl
+}
+
+// setDataDir switches the data directory, creating its layout
+func setDataDir(dir string) error {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+	for _, sub := range []string{"docs", "search", schemasDir} {
+		if err := os.MkdirAll(filepath.Join(abs, sub), 0o755); err != nil {
+			return err
+		}
+	}
+	dataDir = abs
+	configureDataDirConsumers()
+	return nil
+}
+
+// resolveDataDir picks the default data directory
+func resolveDataDir() {
 	// Strategy 1: Try user home directory first (standalone installation)
 	// This works cross-platform: ~/.krakend-mcp/ on Unix, C:\Users\...\krakend-mcp\ on Windows
 	homeDir, err := os.UserHomeDir()
@@ -45,7 +132,7 @@ func init() {
 		// Check if user data directory exists
 		if info, err := os.Stat(userDataDir); err == nil && info.IsDir() {
 			dataDir = userDataDir
-			log.Printf("✓ Data directory: %s (user home)", dataDir)
+			slog.Info("Data directory", "path", dataDir, "source", "user home")
 			return
 		}
 
@@ -53,7 +140,7 @@ func init() {
 		if err := os.MkdirAll(userDataDir, 0o755); err == nil {
 			// Successfully created, use it
 			dataDir = userDataDir
-			log.Printf("✓ Data directory created: %s", dataDir)
+			slog.Info("Data directory created", "path", dataDir)
 
 			// Create subdirectories
 			os.MkdirAll(filepath.Join(dataDir, "docs"), 0o755)
@@ -62,9 +149,9 @@ func init() {
 		}
 
 		// If creation failed, log warning and try next strategy
-		log.Printf("Warning: Could not create user data directory at %s: %v", userDataDir, err)
+		slog.Warn("Could not create user data directory", "path", userDataDir, "error", err)
 	} else {
-		log.Printf("Warning: Could not determine user home directory: %v", err)
+		slog.Warn("Could not determine user home directory", "error", err)
 	}
 
 	// Strategy 2: Try relative to executable (development/plugin installation)
@@ -78,14 +165,14 @@ func init() {
 		// Check if data directory exists relative to binary
 		if info, err := os.Stat(relativeDataDir); err == nil && info.IsDir() {
 			dataDir, _ = filepath.Abs(relativeDataDir)
-			log.Printf("✓ Data directory: %s (relative to binary)", dataDir)
+			slog.Info("Data directory", "path", dataDir, "source", "relative to binary")
 			return
 		}
 	}
 
 	// Strategy 3: Last resort fallback to current working directory
 	dataDir = filepath.Join(".", "data")
-	log.Printf("⚠️  Data directory (fallback): %s", dataDir)
+	slog.Warn("Data directory", "path", dataDir, "source", "fallback")
 
 	// Try to create it
 	os.MkdirAll(filepath.Join(dataDir, "docs"), 0o755)
@@ -114,7 +201,7 @@ func cleanStaleLock() error {
 	pid, err := strconv.Atoi(pidStr)
 	if err != nil {
 		// Corrupted lock file, remove it
-		log.Printf("Warning: Corrupted lock file (invalid PID), removing...")
+		slog.Warn("Corrupted lock file (invalid PID), removing it", "path", lockPath)
 		return os.Remove(lockPath)
 	}
 
@@ -124,7 +211,7 @@ func cleanStaleLock() error {
 	}
 
 	// Process is dead, remove stale lock
-	log.Printf("Stale lock detected (PID %d not running), cleaning...", pid)
+	slog.Info("Stale lock detected, cleaning it", "pid", pid)
 	return os.Remove(lockPath)
 }
 
@@ -137,7 +224,7 @@ func acquireLock() error {
 	if data, err := os.ReadFile(lockPath); err == nil {
 		if pidStr := strings.TrimSpace(string(data)); pidStr != "" {
 			if pid, err := strconv.Atoi(pidStr); err == nil && pid == ourPID {
-				log.Printf("Lock already held by this process (PID %d)", ourPID)
+				slog.Debug("Lock already held by this process", "pid", ourPID)
 				return nil
 			}
 		}
@@ -154,7 +241,7 @@ func acquireLock() error {
 				return fmt.Errorf("timeout waiting for index lock after %v: %w", elapsed, err)
 			}
 
-			log.Printf("Index locked by another process, waiting... (%v elapsed)", elapsed.Round(100*time.Millisecond))
+			slog.Info("Index locked by another process, waiting", "waited_ms", elapsed.Milliseconds())
 			time.Sleep(lockRetryWait)
 			continue
 		}
@@ -165,7 +252,7 @@ func acquireLock() error {
 			return fmt.Errorf("failed to create lock file: %w", err)
 		}
 
-		log.Printf("✓ Index lock acquired (PID %d)", ourPID)
+		slog.Debug("Index lock acquired", "pid", ourPID)
 		return nil
 	}
 }
@@ -186,7 +273,7 @@ func releaseLock() error {
 	pidStr := strings.TrimSpace(string(data))
 	pid, err := strconv.Atoi(pidStr)
 	if err == nil && pid != os.Getpid() {
-		log.Printf("Warning: Lock file contains different PID (%d vs %d), not removing", pid, os.Getpid())
+		slog.Warn("Lock file held by another process, not removing it", "pid", pid, "own_pid", os.Getpid())
 		return nil
 	}
 
@@ -194,7 +281,7 @@ func releaseLock() error {
 		return fmt.Errorf("failed to remove lock file: %w", err)
 	}
 
-	log.Printf("✓ Index lock released")
+	slog.Debug("Index lock released")
 	return nil
 }
 
@@ -232,7 +319,7 @@ func acquireLockWithContext(ctx context.Context) error {
 				return fmt.Errorf("failed to create lock file: %w", err)
 			}
 
-			log.Printf("✓ Index lock acquired (PID %d)", ourPID)
+			slog.Debug("Index lock acquired", "pid", ourPID)
 			return nil // Success
 		}
 	}
@@ -310,22 +397,29 @@ func copyFile(src, dst string) error {
 
 // SearchResult represents a search result with score
 type SearchResult struct {
-	Chunk indexing.DocChunk `json:"chunk"`
-	Score float64           `json:"score"`
+	Chunk    indexing.DocChunk `json:"chunk"`
+	Score    float64           `json:"score"`
+	Resource string            `json:"resource,omitempty"` // Resource with the whole page of the chunk
 }
 
 // SearchDocumentationInput defines input for search_documentation tool
 type SearchDocumentationInput struct {
 	Query      string `json:"query" jsonschema:"Search query for documentation"`
 	MaxResults int    `json:"max_results,omitempty" jsonschema:"Maximum number of results (optional, defaults to 5)"`
+	PageSize   int    `json:"page_size,omitempty" jsonschema:"Results per page (optional, pages are otherwise only split to stay under client message limits)"`
+	PageToken  string `json:"page_token,omitempty" jsonschema:"next_page_token of a previous call, retrieving the following page (the other arguments are then ignored)"`
 }
 
 // SearchDocumentationOutput defines output for search_documentation tool
 type SearchDocumentationOutput struct {
-	Results    []SearchResult `json:"results"`
-	Query      string         `json:"query"`
-	TotalHits  int            `json:"total_hits"`
-	SourceURLs []string       `json:"source_urls"`
+	Results    []SearchResult  `json:"results"`
+	Query      string          `json:"query"`
+	TotalHits  int             `json:"total_hits"`
+	SourceURLs []string        `json:"source_urls"`
+	Docs       DocsSnapshot    `json:"docs"`             // Age of the documentation the results come from
+	Warmup     *DocIndexWarmup `json:"warmup,omitempty"` // Progress of the index initialization, when not ready yet
+	Message    string          `json:"message,omitempty"`
+	Page       *pages.Info     `json:"page,omitempty"` // Results are paginated: use page.next_page_token for the next ones
 }
 
 // RefreshDocumentationIndexInput defines input for refresh_documentation_index tool
@@ -338,10 +432,17 @@ type RefreshDocumentationIndexOutput struct {
 	Updated       bool      `json:"updated"`
 	LastUpdate    time.Time `json:"last_update"`
 	ChunksIndexed int       `json:"chunks_indexed"`
+	Pages         int       `json:"pages"`         // Documentation pages (H1 sections) in the snapshot
+	PagesChanged  int       `json:"pages_changed"` // New or modified pages since the last refresh
+	PagesRemoved  int       `json:"pages_removed"` // Pages no longer in the documentation
+	Partial       bool      `json:"partial"`       // Only the changed pages were reindexed
+	NotModified   bool      `json:"not_modified"`  // The server reported the documentation unchanged
 	Message       string    `json:"message"`
 }
 
-// indexHolder manages concurrent access to the Bleve documentation index
+// indexHolder manages concurrent access to the Bleve documentation index, shared by every
+// session. Each opened index tracks its own operations in flight (see bleveIndexWrapper), so
+// closing a replaced index never waits for the searches running on the current one.
 type indexHolder struct {
 	// current holds the active index pointer (atomic access for lock-free reads)
 	current atomic.Pointer[Index]
@@ -349,23 +450,22 @@ type indexHolder struct {
 	// refreshMu prevents concurrent refresh operations
 	// NOT used for searches - they are lock-free via atomic pointer
 	refreshMu sync.Mutex
-
-	// wg tracks in-flight search operations for graceful cleanup of old indexes
-	wg sync.WaitGroup
 }
 
-var indexMgr *indexHolder
+// maxIndexAttempts bounds how many times an operation is retried on indexes swapped meanwhile
+const maxIndexAttempts = 3
+
+var indexMgr = &indexHolder{}
 
 // InitializeDocSearch initializes the documentation search system
 // Priority: Local docs (if exist and recent) > Embedded docs (always available)
 func InitializeDocSearch() error {
-	startTime := time.Now()
-	log.Printf("Initializing documentation search...")
+	docSearchInitMu.Lock()
+	defer docSearchInitMu.Unlock()
 
-	// Initialize indexHolder if needed
-	if indexMgr == nil {
-		indexMgr = &indexHolder{}
-	}
+	startTime := time.Now()
+	slog.Info("Initializing documentation search")
+	setWarmupStep(warmupChecking)
 
 	// Master index path (shared, source of truth)
 	masterIndexPath := filepath.Join(dataDir, indexDir)
@@ -386,11 +486,9 @@ func InitializeDocSearch() error {
 		// If index needs refresh (stale or wrong version)
 		if isStale || wrongVersion {
 			if isStale {
-				log.Printf("Master index is %v old (>7 days), attempting refresh...",
-					indexAge.Round(24*time.Hour))
+				slog.Info("Master index is stale, attempting refresh", "age_days", int(indexAge.Hours()/24))
 			} else {
-				log.Printf("Master index schema mismatch (v%d vs v%d), attempting refresh...",
-					currentVersion, indexing.IndexSchemaVersion)
+				slog.Info("Master index schema mismatch, attempting refresh", "version", currentVersion, "expected", indexing.IndexSchemaVersion)
 			}
 
 			// Try to acquire lock with SHORT timeout (non-blocking)
@@ -400,31 +498,34 @@ func InitializeDocSearch() error {
 			if err := acquireLockWithContext(lockCtx); err == nil {
 				// We got the lock - refresh master index from remote
 				defer releaseLock()
+				setWarmupStep(warmupRefreshing)
 
-				log.Printf("Refreshing master index from remote...")
-				if err := downloadAndReindexDocs(); err != nil {
-					log.Printf("Warning: Refresh failed: %v, using existing master index", err)
+				slog.Info("Refreshing master index from remote")
+				if _, err := downloadAndReindexDocs(); err != nil {
+					slog.Warn("Refresh failed, using existing master index", "error", err)
 				} else {
-					log.Printf("✓ Master index refreshed successfully")
+					slog.Info("Master index refreshed")
 				}
 			} else {
 				// Another process is refreshing - use existing master
-				log.Printf("Another process is refreshing master index, will use current version")
+				slog.Info("Another process is refreshing master index, using current version")
 			}
 		}
 	} else {
 		// No master index - extract embedded
-		log.Printf("No master index found, extracting embedded documentation...")
+		slog.Info("No master index found, extracting embedded documentation")
+		setWarmupStep(warmupExtracting)
 		extractStart := time.Now()
 
 		if err := extractEmbeddedIndex(); err != nil {
 			return fmt.Errorf("failed to extract embedded index: %w", err)
 		}
-		log.Printf("Embedded index extracted in %v", time.Since(extractStart).Round(time.Millisecond))
+		slog.Debug("Embedded index extracted", "duration_ms", time.Since(extractStart).Milliseconds())
 	}
 
 	// Step 2: Copy master index to process-specific temp directory
-	log.Printf("Copying master index to process-specific temp directory...")
+	slog.Debug("Copying master index to process-specific temp directory")
+	setWarmupStep(warmupCopying)
 	copyStart := time.Now()
 
 	// Clean up any existing temp index for this PID
@@ -433,21 +534,25 @@ func InitializeDocSearch() error {
 	if err := copyDir(masterIndexPath, tempIndexPath); err != nil {
 		return fmt.Errorf("failed to copy master index to temp: %w", err)
 	}
-	log.Printf("Index copied in %v", time.Since(copyStart).Round(time.Millisecond))
+	slog.Debug("Index copied", "duration_ms", time.Since(copyStart).Milliseconds())
 
 	// Step 3: Open the process-specific copy (no conflicts with other processes)
+	setWarmupStep(warmupOpening)
 	openStart := time.Now()
 	index, err := bleve.Open(tempIndexPath)
 	if err != nil {
 		return fmt.Errorf("failed to open temp index: %w", err)
 	}
-	log.Printf("Temp index opened in %v", time.Since(openStart).Round(time.Millisecond))
+	slog.Debug("Temp index opened", "duration_ms", time.Since(openStart).Milliseconds())
 
 	wrapped := NewBleveIndexWrapper(index)
 	indexMgr.current.Store(&wrapped)
 	count, _ := wrapped.DocCount()
 	elapsed := time.Since(startTime).Round(time.Millisecond)
-	log.Printf("✓ Documentation search initialized (%d docs, process temp index) in %v", count, elapsed)
+	slog.Info("Documentation search initialized", "docs", count, "duration_ms", elapsed.Milliseconds())
+	if snapshot := currentDocsSnapshot(time.Now()); snapshot.Warning != "" {
+		slog.Warn(snapshot.Warning)
+	}
 
 	return nil
 }
@@ -499,18 +604,20 @@ func extractEmbeddedIndex() error {
 		}
 	}
 
-	// Extract cache.meta
-	if metaData, err := defaultDataProvider.ReadFile("data/docs/cache.meta"); err == nil {
-		if err := os.WriteFile(filepath.Join(docsPath, "cache.meta"), metaData, 0o644); err != nil {
-			return fmt.Errorf("failed to extract cache.meta: %w", err)
+	// Extract cache.meta and the page manifest
+	for _, name := range []string{"cache.meta", indexing.PageManifestFile} {
+		if data, err := defaultDataProvider.ReadFile("data/docs/" + name); err == nil {
+			if err := os.WriteFile(filepath.Join(docsPath, name), data, 0o644); err != nil {
+				return fmt.Errorf("failed to extract %s: %w", name, err)
+			}
 		}
 	}
 
-	log.Printf("✓ Embedded index and docs extracted to %s", dataDir)
+	slog.Info("Embedded index and docs extracted", "path", dataDir)
 
-	// Write version file to mark this as v2 index
+	// Write version file to mark the index schema as current
 	if err := writeIndexVersion(); err != nil {
-		log.Printf("Warning: Failed to write index version: %v", err)
+		slog.Warn("Failed to write index version", "error", err)
 	}
 
 	return nil
@@ -562,51 +669,69 @@ func needsRefresh() bool {
 	return age > cacheTTL
 }
 
-// downloadDocumentation downloads the full documentation
-func downloadDocumentation() error {
-	log.Printf("Downloading documentation from %s", docsURL)
+// downloadDocumentation downloads the full documentation. It returns true without downloading
+// when the server reports that the local copy (same ETag) is current.
+func downloadDocumentation() (bool, error) {
+	slog.Info("Downloading documentation", "url", docsURL)
 
-	resp, err := http.Get(docsURL)
+	req, err := http.NewRequest(http.MethodGet, docsURL, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to download: %w", err)
+	}
+	previous := readCacheMeta()
+	if _, err := os.Stat(filepath.Join(dataDir, docsFile)); err == nil && previous.ETag != "" {
+		req.Header.Set("If-None-Match", previous.ETag)
+	}
+	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
-		return fmt.Errorf("failed to download: %w", err)
+		return false, fmt.Errorf("failed to download: %w", err)
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotModified {
+		// The local copy is current: only record the check
+		previous.DownloadedAt = time.Now().UTC().Format(time.RFC3339)
+		previous.Embedded = false
+		slog.Info("Documentation not modified since last download")
+		return true, writeCacheMeta(previous)
+	}
 	if resp.StatusCode != http.StatusOK {
 		resp.Body.Close() // Close explicitly before early return
-		return fmt.Errorf("download failed with status: %d", resp.StatusCode)
+		return false, fmt.Errorf("download failed with status: %d", resp.StatusCode)
 	}
 
 	// Ensure docs directory exists
 	docsPath := filepath.Join(dataDir, "docs")
 	if err := os.MkdirAll(docsPath, 0o755); err != nil {
-		return fmt.Errorf("failed to create docs directory: %w", err)
+		return false, fmt.Errorf("failed to create docs directory: %w", err)
 	}
 
 	// Write to file
 	fullPath := filepath.Join(dataDir, docsFile)
 	file, err := os.Create(fullPath)
 	if err != nil {
-		return fmt.Errorf("failed to create file: %w", err)
+		return false, fmt.Errorf("failed to create file: %w", err)
 	}
 	defer file.Close()
 
-	if _, err := io.Copy(file, resp.Body); err != nil {
-		return fmt.Errorf("failed to write file: %w", err)
+	size, err := io.Copy(file, resp.Body)
+	if err != nil {
+		return false, fmt.Errorf("failed to write file: %w", err)
 	}
 
-	// Write cache metadata
-	metaPath := filepath.Join(dataDir, cacheMetaFile)
-	metaFile, err := os.Create(metaPath)
+	// Write cache metadata (same format as the snapshot embedded by build.sh)
+	err = writeCacheMeta(cacheMeta{
+		DownloadedAt: time.Now().UTC().Format(time.RFC3339),
+		SourceURL:    docsURL,
+		SizeBytes:    size,
+		ETag:         resp.Header.Get("ETag"),
+	})
 	if err != nil {
-		return fmt.Errorf("failed to create meta file: %w", err)
+		return false, fmt.Errorf("failed to create meta file: %w", err)
 	}
-	defer metaFile.Close()
 
-	fmt.Fprintf(metaFile, "last_update: %s\n", time.Now().Format(time.RFC3339))
-
-	log.Printf("Documentation downloaded successfully")
-	return nil
+	slog.Info("Documentation downloaded")
+	return false, nil
 }
 
 // indexChunks creates/updates the Bleve search index
@@ -635,7 +760,6 @@ func countOversized(chunks []indexing.DocChunk) int {
 
 func indexChunks(chunks []indexing.DocChunk) error {
 	startTime := time.Now()
-	indexPath := filepath.Join(dataDir, indexDir)
 	tempIndexPath := filepath.Join(dataDir, indexDir+".tmp")
 
 	// Clean up any leftover temp index from previous crash
@@ -647,14 +771,14 @@ func indexChunks(chunks []indexing.DocChunk) error {
 	}
 
 	// Create new index in temp location
-	log.Printf("Creating new index with %d chunks in temp location...", len(chunks))
+	slog.Info("Creating new index in temp location", "chunks", len(chunks))
 	createStart := time.Now()
 	mapping := bleve.NewIndexMapping()
 	newIndex, err := bleve.New(tempIndexPath, mapping)
 	if err != nil {
 		return fmt.Errorf("failed to create temp index: %w", err)
 	}
-	log.Printf("Temp index created in %v", time.Since(createStart).Round(time.Millisecond))
+	slog.Debug("Temp index created", "duration_ms", time.Since(createStart).Milliseconds())
 
 	// Index all chunks
 	indexStart := time.Now()
@@ -674,7 +798,7 @@ func indexChunks(chunks []indexing.DocChunk) error {
 				return fmt.Errorf("failed to index batch: %w", err)
 			}
 			batch = newIndex.NewBatch()
-			log.Printf("Indexed %d/%d chunks...", i, len(chunks))
+			slog.Debug("Indexing chunks", "indexed", i, "chunks", len(chunks))
 		}
 	}
 
@@ -687,17 +811,69 @@ func indexChunks(chunks []indexing.DocChunk) error {
 		}
 	}
 
-	log.Printf("Indexed %d chunks in %v", len(chunks), time.Since(indexStart).Round(time.Millisecond))
+	slog.Info("Chunks indexed", "chunks", len(chunks), "duration_ms", time.Since(indexStart).Milliseconds())
 
 	// Close temp index before moving
 	if err := newIndex.Close(); err != nil {
-		log.Printf("Warning: Error closing temp index: %v", err)
+		slog.Warn("Error closing temp index", "error", err)
 		os.RemoveAll(tempIndexPath)
 		return fmt.Errorf("failed to close temp index: %w", err)
 	}
 
+	return installIndex(tempIndexPath, startTime)
+}
+
+// updateIndexPages refreshes only the changed pages: the chunks of changed and removed pages
+// are replaced in a copy of the master index, which is then swapped in like a full reindex
+func updateIndexPages(chunks []indexing.DocChunk, deleteIDs []string) error {
+	startTime := time.Now()
+	indexPath := filepath.Join(dataDir, indexDir)
+	tempIndexPath := filepath.Join(dataDir, indexDir+".tmp")
+
+	// Clean up any leftover temp index from previous crash
+	os.RemoveAll(tempIndexPath)
+
+	if err := copyDir(indexPath, tempIndexPath); err != nil {
+		os.RemoveAll(tempIndexPath)
+		return fmt.Errorf("failed to copy index: %w", err)
+	}
+	index, err := bleve.Open(tempIndexPath)
+	if err != nil {
+		os.RemoveAll(tempIndexPath)
+		return fmt.Errorf("failed to open index copy: %w", err)
+	}
+
+	batch := index.NewBatch()
+	for _, id := range deleteIDs {
+		batch.Delete(id)
+	}
+	for _, chunk := range chunks {
+		if err := batch.Index(chunk.ID, chunk); err != nil {
+			index.Close()
+			os.RemoveAll(tempIndexPath)
+			return fmt.Errorf("failed to add chunk %s to batch: %w", chunk.ID, err)
+		}
+	}
+	if err := index.Batch(batch); err != nil {
+		index.Close()
+		os.RemoveAll(tempIndexPath)
+		return fmt.Errorf("failed to update index: %w", err)
+	}
+	if err := index.Close(); err != nil {
+		os.RemoveAll(tempIndexPath)
+		return fmt.Errorf("failed to close index copy: %w", err)
+	}
+	slog.Info("Chunks replaced", "removed", len(deleteIDs), "added", len(chunks), "duration_ms", time.Since(startTime).Milliseconds())
+
+	return installIndex(tempIndexPath, startTime)
+}
+
+// installIndex moves a closed temp index to the master location and swaps searches to it
+func installIndex(tempIndexPath string, startTime time.Time) error {
+	indexPath := filepath.Join(dataDir, indexDir)
+
 	// Atomic filesystem swap: rename temp to final location
-	log.Printf("Swapping temp index into place...")
+	slog.Debug("Swapping temp index into place")
 	swapStart := time.Now()
 
 	// Remove old index directory (atomic operation will replace it)
@@ -711,22 +887,22 @@ func indexChunks(chunks []indexing.DocChunk) error {
 		os.RemoveAll(tempIndexPath)
 		return fmt.Errorf("failed to rename temp index: %w", err)
 	}
-	log.Printf("Index swapped in %v", time.Since(swapStart).Round(time.Millisecond))
+	slog.Debug("Index swapped", "duration_ms", time.Since(swapStart).Milliseconds())
 
 	// Open the index from final location
-	log.Printf("Opening new index for use...")
+	slog.Debug("Opening new index")
 	reopenStart := time.Now()
 	finalIndex, err := bleve.Open(indexPath)
 	if err != nil {
 		return fmt.Errorf("failed to open new index: %w", err)
 	}
-	log.Printf("New index opened in %v", time.Since(reopenStart).Round(time.Millisecond))
+	slog.Debug("New index opened", "duration_ms", time.Since(reopenStart).Milliseconds())
 
 	// Wrap the index with our interface
 	wrapped := NewBleveIndexWrapper(finalIndex)
 
 	// ATOMIC SWAP: Replace the global index pointer
-	log.Printf("Swapping index pointer atomically...")
+	slog.Debug("Swapping index pointer")
 	oldIndexPtr := indexMgr.current.Swap(&wrapped)
 
 	// Graceful cleanup of old index in background
@@ -735,70 +911,118 @@ func indexChunks(chunks []indexing.DocChunk) error {
 			return
 		}
 
-		log.Printf("Waiting for in-flight searches to complete before closing old index...")
+		slog.Debug("Closing old index once its in-flight searches complete")
 		waitStart := time.Now()
 
-		// Wait for all in-flight searches on old index to complete
-		indexMgr.wg.Wait()
-
-		log.Printf("All searches completed, closing old index (waited %v)...",
-			time.Since(waitStart).Round(time.Millisecond))
-
 		old := *oldPtr
 		if err := old.Close(); err != nil {
-			log.Printf("Warning: Error closing old index: %v", err)
+			slog.Warn("Error closing old index", "error", err)
 		} else {
-			log.Printf("✓ Old index closed successfully")
+			slog.Debug("Old index closed", "waited_ms", time.Since(waitStart).Milliseconds())
 		}
 	}(oldIndexPtr)
 
 	elapsed := time.Since(startTime).Round(time.Millisecond)
-	log.Printf("✓ Index swap completed in %v, searches now using new index", elapsed)
+	slog.Info("Index swap completed, searches now use the new index", "duration_ms", elapsed.Milliseconds())
 
 	// Write version file to mark this as current index version
 	if err := writeIndexVersion(); err != nil {
-		log.Printf("Warning: Failed to write index version: %v", err)
+		slog.Warn("Failed to write index version", "error", err)
 	}
 
 	return nil
 }
 
-// downloadAndReindexDocs downloads documentation from remote and rebuilds the index
+// docsRefresh describes what a documentation refresh changed
+type docsRefresh struct {
+	Pages       int
+	Changed     int
+	Removed     int
+	Partial     bool // Only the changed pages were reindexed
+	NotModified bool // The remote documentation did not change since the last download
+}
+
+// downloadAndReindexDocs downloads documentation from remote and updates the index: only the
+// pages whose hash changed when the page manifest and index are current, everything otherwise.
 // Assumes lock is already held by caller for inter-process coordination
-func downloadAndReindexDocs() error {
+func downloadAndReindexDocs() (docsRefresh, error) {
+	var refresh docsRefresh
+
 	// Download documentation
 	downloadStart := time.Now()
-	if err := downloadDocumentation(); err != nil {
-		return fmt.Errorf("download failed: %w", err)
+	notModified, err := downloadDocumentation()
+	if err != nil {
+		return refresh, fmt.Errorf("download failed: %w", err)
 	}
-	log.Printf("Download completed in %v", time.Since(downloadStart).Round(time.Millisecond))
+	slog.Debug("Download completed", "duration_ms", time.Since(downloadStart).Milliseconds())
+	refresh.NotModified = notModified
 
-	// Parse into chunks
+	// Split into pages and compare them with the last indexed ones
 	parseStart := time.Now()
-	fullPath := filepath.Join(dataDir, docsFile)
-	chunks, err := indexing.ParseDocumentation(fullPath)
+	content, err := os.ReadFile(filepath.Join(dataDir, docsFile))
 	if err != nil {
-		return fmt.Errorf("parse failed: %w", err)
+		return refresh, fmt.Errorf("parse failed: %w", err)
 	}
-	log.Printf("Parsed %d documentation chunks (avg: %d tokens, %d over limit)",
-		len(chunks), averageTokens(chunks), countOversized(chunks))
-	log.Printf("Parse completed in %v", time.Since(parseStart).Round(time.Millisecond))
-
-	// Create search index (this closes and reopens the global index)
-	if err := indexChunks(chunks); err != nil {
-		return fmt.Errorf("indexing failed: %w", err)
+	pages := indexing.SplitPages(string(content))
+	manifestPath := filepath.Join(dataDir, pageManifest)
+	previous, err := indexing.ReadPageManifest(manifestPath)
+	if err != nil {
+		slog.Warn("Reindexing every page", "error", err)
+		previous = &indexing.PageManifest{Pages: map[string]indexing.PageEntry{}}
+	}
+	changes := previous.DiffPages(pages)
+
+	chunks := indexing.ParsePages(pages)
+	manifest := indexing.BuildPageManifest(pages, chunks, previous, time.Now().UTC().Format(time.RFC3339))
+	indexing.StampChunks(chunks, manifest)
+	refresh.Pages, refresh.Changed, refresh.Removed = len(pages), len(changes.Changed), len(changes.Removed)
+	slog.Info("Documentation parsed", "chunks", len(chunks), "pages", len(pages), "changed", refresh.Changed, "removed", refresh.Removed,
+		"avg_tokens", averageTokens(chunks), "oversized", countOversized(chunks))
+	slog.Debug("Parse completed", "duration_ms", time.Since(parseStart).Milliseconds())
+
+	_, statErr := os.Stat(filepath.Join(dataDir, indexDir))
+	refresh.Partial = len(previous.Pages) > 0 && statErr == nil && getIndexVersion() == indexing.IndexSchemaVersion
+	switch {
+	case refresh.Partial && refresh.Changed == 0 && refresh.Removed == 0:
+		slog.Info("No documentation page changed, index kept as is")
+	case refresh.Partial:
+		var deleteIDs []string
+		for _, id := range append(changes.Changed, changes.Removed...) {
+			deleteIDs = append(deleteIDs, previous.Pages[id].Chunks...)
+		}
+		changed := map[string]bool{}
+		for _, id := range changes.Changed {
+			changed[id] = true
+		}
+		var changedChunks []indexing.DocChunk
+		for _, chunk := range chunks {
+			if changed[chunk.PageID] {
+				changedChunks = append(changedChunks, chunk)
+			}
+		}
+		if err := updateIndexPages(changedChunks, deleteIDs); err != nil {
+			return refresh, fmt.Errorf("indexing failed: %w", err)
+		}
+	default:
+		// Create search index (this closes and reopens the global index)
+		if err := indexChunks(chunks); err != nil {
+			return refresh, fmt.Errorf("indexing failed: %w", err)
+		}
 	}
 
-	return nil
+	if err := indexing.WritePageManifest(manifestPath, manifest); err != nil {
+		slog.Warn("Failed to write page manifest", "error", err)
+	}
+	return refresh, nil
 }
 
 // refreshDocumentationIndex downloads and re-indexes documentation
-func refreshDocumentationIndex(force bool) error {
+func refreshDocumentationIndex(force bool) (docsRefresh, error) {
 	startTime := time.Now()
 
 	if !force && !needsRefresh() {
-		log.Printf("Documentation cache is fresh, skipping refresh")
-		return nil // Cache is fresh
+		slog.Info("Documentation cache is fresh, skipping refresh")
+		return docsRefresh{}, nil // Cache is fresh
 	}
 
 	// Serialize refresh operations (prevent concurrent refreshes)
@@ -808,54 +1032,126 @@ func refreshDocumentationIndex(force bool) error {
 	// Re-check after acquiring lock (double-checked locking pattern)
 	// Another goroutine may have already refreshed while we were waiting
 	if !force && !needsRefresh() {
-		log.Printf("Documentation was refreshed by another goroutine, skipping")
-		return nil
+		slog.Info("Documentation was refreshed by another goroutine, skipping")
+		return docsRefresh{}, nil
 	}
 
-	log.Printf("Starting documentation refresh (force=%v)...", force)
+	slog.Info("Starting documentation refresh", "force", force)
 
 	// Acquire inter-process lock for re-indexing (will wait if another process has it)
 	if err := acquireLock(); err != nil {
-		return fmt.Errorf("failed to acquire lock for refresh: %w", err)
+		return docsRefresh{}, fmt.Errorf("failed to acquire lock for refresh: %w", err)
 	}
 	defer releaseLock() // Release lock immediately after refresh completes
 
 	// Download, parse, and re-index documentation
-	if err := downloadAndReindexDocs(); err != nil {
-		return fmt.Errorf("refresh failed: %w", err)
+	refresh, err := downloadAndReindexDocs()
+	if err != nil {
+		return refresh, fmt.Errorf("refresh failed: %w", err)
 	}
 
 	elapsed := time.Since(startTime).Round(time.Millisecond)
-	log.Printf("✓ Documentation refresh completed in %v", elapsed)
+	slog.Info("Documentation refresh completed", "duration_ms", elapsed.Milliseconds())
 
-	return nil
+	unchanged := refresh.NotModified || (refresh.Partial && refresh.Changed == 0 && refresh.Removed == 0)
+	if !unchanged {
+		events.Publish(events.DocsRefreshed, "", refresh)
+	}
+	return refresh, nil
 }
 
-// SearchDocumentation searches through KrakenD documentation
-func SearchDocumentation(ctx context.Context, req *mcp.CallToolRequest, input SearchDocumentationInput) (*mcp.CallToolResult, SearchDocumentationOutput, error) {
-	// Track in-flight searches for graceful cleanup (MUST be before Load)
-	indexMgr.wg.Add(1)
-	defer indexMgr.wg.Done()
-
+// withDocIndex runs fn with the current documentation index. While the index initializes it
+// returns an indexWarmingUpError instead of blocking, starting the initialization if needed.
+// When a refresh swaps and closes the index while fn runs, fn runs again on the new index.
+func withDocIndex(fn func(index Index) error) error {
 	// Get current index atomically (lock-free read)
 	indexPtr := indexMgr.current.Load()
 
-	// If index not initialized, try to initialize it now
+	// If index not initialized, initialize it in the background and tell when to retry
 	if indexPtr == nil {
-		log.Printf("Doc index not initialized, initializing now...")
-		if err := InitializeDocSearch(); err != nil {
-			return nil, SearchDocumentationOutput{}, fmt.Errorf("failed to initialize documentation index: %w", err)
+		startDocSearchWarmup()
+		if warmup, running := docSearchWarmupProgress(time.Now()); running {
+			return &indexWarmingUpError{warmup: warmup}
 		}
-		// Reload after initialization
+		// The initialization finished meanwhile
 		indexPtr = indexMgr.current.Load()
 		if indexPtr == nil {
-			return nil, SearchDocumentationOutput{}, fmt.Errorf("index still nil after initialization")
+			err := lastWarmupError()
+			if err == nil {
+				err = errors.New("index not available")
+			}
+			return fmt.Errorf("failed to initialize documentation index: %w", err)
 		}
 	}
 
-	// Dereference pointer to get actual index
-	index := *indexPtr
+	for attempt := 1; ; attempt++ {
+		err := fn(*indexPtr)
+		if !errors.Is(err, errIndexClosed) || attempt == maxIndexAttempts {
+			return err
+		}
+		// Replaced by a refresh: retry on the new index, unless the server is shutting down
+		next := indexMgr.current.Load()
+		if next == nil || next == indexPtr {
+			return err
+		}
+		indexPtr = next
+	}
+}
+
+// chunkFromHit rebuilds a documentation chunk from the stored fields of a search hit
+func chunkFromHit(hit *search.DocumentMatch) indexing.DocChunk {
+	chunk := indexing.DocChunk{
+		ID: hit.ID,
+	}
+
+	if subcategory, ok := hit.Fields["subcategory"].(string); ok {
+		chunk.Subcategory = subcategory
+	}
+	if content, ok := hit.Fields["content"].(string); ok {
+		chunk.Content = content
+	}
+	if page, ok := hit.Fields["page"].(string); ok {
+		chunk.Page = page
+	}
+	if category, ok := hit.Fields["category"].(string); ok {
+		chunk.Category = category
+	}
+	if url, ok := hit.Fields["url"].(string); ok {
+		chunk.URL = url
+	}
+	if breadcrumb, ok := hit.Fields["breadcrumb"].(string); ok {
+		chunk.Breadcrumb = breadcrumb
+	}
+	if keywords, ok := hit.Fields["keywords"].([]interface{}); ok {
+		chunk.Keywords = make([]string, 0, len(keywords))
+		for _, kw := range keywords {
+			if kwStr, ok := kw.(string); ok {
+				chunk.Keywords = append(chunk.Keywords, kwStr)
+			}
+		}
+	}
+	if pageID, ok := hit.Fields["page_id"].(string); ok {
+		chunk.PageID = pageID
+	}
+	if updatedAt, ok := hit.Fields["updated_at"].(string); ok {
+		chunk.UpdatedAt = updatedAt
+	}
+	if tokenCount, ok := hit.Fields["token_count"].(float64); ok {
+		chunk.TokenCount = int(tokenCount)
+	}
+	return chunk
+}
 
+// SearchDocumentation searches through KrakenD documentation
+func SearchDocumentation(ctx context.Context, req *mcp.CallToolRequest, input SearchDocumentationInput) (*mcp.CallToolResult, SearchDocumentationOutput, error) {
+	if input.PageToken != "" {
+		output, results, page, err := pages.Continue[SearchDocumentationOutput, SearchResult](pages.Session(req), "search_documentation", input.PageToken)
+		if err != nil {
+			return nil, SearchDocumentationOutput{}, err
+		}
+		output.Results, output.Page = results, page
+		return nil, output, nil
+	}
 	maxResults := input.MaxResults
 	if maxResults == 0 || maxResults > 20 {
 		maxResults = 10
@@ -868,7 +1164,27 @@ func SearchDocumentation(ctx context.Context, req *mcp.CallToolRequest, input Se
 	search.Fields = []string{"*"}
 
 	// Execute search on current index
-	searchResults, err := index.Search(search)
+	var searchResults *bleve.SearchResult
+	err := withDocIndex(func(index Index) (err error) {
+		searchResults, err = index.Search(search)
+		return err
+	})
+	var warming *indexWarmingUpError
+	if errors.As(err, &warming) {
+		// Not a failure: the client can retry once the index is ready
+		metrics.Count(metrics.DocSearch, "warming_up")
+		output := SearchDocumentationOutput{
+			Results:    []SearchResult{},
+			Query:      input.Query,
+			SourceURLs: []string{"https://www.krakend.io/docs/"},
+			Warmup:     &warming.warmup,
+			Message:    "The " + warming.Error(),
+		}
+		return &mcp.CallToolResult{Meta: map[string]interface{}{
+			"index_status":     "warming_up",
+			"retry_in_seconds": warming.warmup.RetryInSeconds,
+		}}, output, nil
+	}
 	if err != nil {
 		return nil, SearchDocumentationOutput{}, fmt.Errorf("search failed: %w", err)
 	}
@@ -876,44 +1192,28 @@ func SearchDocumentation(ctx context.Context, req *mcp.CallToolRequest, input Se
 	// Convert to output format
 	results := make([]SearchResult, 0, len(searchResults.Hits))
 	for _, hit := range searchResults.Hits {
-		chunk := indexing.DocChunk{
-			ID: hit.ID,
-		}
+		chunk := chunkFromHit(hit)
+		results = append(results, SearchResult{
+			Chunk:    chunk,
+			Score:    hit.Score,
+			Resource: chunkResourceURI(chunk),
+		})
+	}
 
-		if subcategory, ok := hit.Fields["subcategory"].(string); ok {
-			chunk.Subcategory = subcategory
-		}
-		if content, ok := hit.Fields["content"].(string); ok {
-			chunk.Content = content
-		}
-		if page, ok := hit.Fields["page"].(string); ok {
-			chunk.Page = page
-		}
-		if category, ok := hit.Fields["category"].(string); ok {
-			chunk.Category = category
-		}
-		if url, ok := hit.Fields["url"].(string); ok {
-			chunk.URL = url
-		}
-		if breadcrumb, ok := hit.Fields["breadcrumb"].(string); ok {
-			chunk.Breadcrumb = breadcrumb
-		}
-		if keywords, ok := hit.Fields["keywords"].([]interface{}); ok {
-			chunk.Keywords = make([]string, 0, len(keywords))
-			for _, kw := range keywords {
-				if kwStr, ok := kw.(string); ok {
-					chunk.Keywords = append(chunk.Keywords, kwStr)
-				}
+	// Reorder by the local search feedback
+	if boosts := searchFeedbackBoosts(input.Query); len(boosts) > 0 {
+		for i := range results {
+			if boost, ok := boosts[results[i].Chunk.ID]; ok {
+				results[i].Score *= boost
 			}
 		}
-		if tokenCount, ok := hit.Fields["token_count"].(float64); ok {
-			chunk.TokenCount = int(tokenCount)
-		}
+		sort.SliceStable(results, func(i, j int) bool { return results[i].Score > results[j].Score })
+	}
 
-		results = append(results, SearchResult{
-			Chunk: chunk,
-			Score: hit.Score,
-		})
+	if len(results) > 0 {
+		metrics.Count(metrics.DocSearch, "results")
+	} else {
+		metrics.Count(metrics.DocSearch, "no_results")
 	}
 
 	output := SearchDocumentationOutput{
@@ -921,9 +1221,19 @@ func SearchDocumentation(ctx context.Context, req *mcp.CallToolRequest, input Se
 		Query:      input.Query,
 		TotalHits:  int(searchResults.Total),
 		SourceURLs: []string{"https://www.krakend.io/docs/"},
+		Docs:       currentDocsSnapshot(time.Now()),
 	}
+	output.Results, output.Page = pages.Paginate(pages.Session(req), "search_documentation", output, output.Results, input.PageSize)
 
-	return &mcp.CallToolResult{Meta: map[string]interface{}{"total_hits": output.TotalHits}}, output, nil
+	meta := map[string]interface{}{
+		"total_hits":         output.TotalHits,
+		"docs_snapshot_date": output.Docs.Date,
+		"docs_age_days":      output.Docs.AgeDays,
+	}
+	if output.Docs.Warning != "" {
+		meta["docs_warning"] = output.Docs.Warning
+	}
+	return &mcp.CallToolResult{Meta: meta}, output, nil
 }
 
 // RefreshDocumentationIndex forces refresh of documentation index
@@ -943,9 +1253,12 @@ func RefreshDocumentationIndex(ctx context.Context, req *mcp.CallToolRequest, in
 	}
 
 	// Perform refresh
-	if err := refreshDocumentationIndex(input.Force); err != nil {
+	refresh, err := refreshDocumentationIndex(input.Force)
+	if err != nil {
 		return nil, output, fmt.Errorf("refresh failed: %w", err)
 	}
+	output.Pages, output.PagesChanged, output.PagesRemoved = refresh.Pages, refresh.Changed, refresh.Removed
+	output.Partial, output.NotModified = refresh.Partial, refresh.NotModified
 
 	// Count chunks from current index
 	indexPtr := indexMgr.current.Load()
@@ -958,20 +1271,21 @@ func RefreshDocumentationIndex(ctx context.Context, req *mcp.CallToolRequest, in
 	output.Updated = true
 	output.LastUpdate = time.Now()
 	output.Message = fmt.Sprintf("Documentation refreshed successfully, %d chunks indexed", output.ChunksIndexed)
+	if output.Partial {
+		output.Message += fmt.Sprintf(" (%d of %d pages changed, %d removed: only those were reindexed)",
+			output.PagesChanged, output.Pages, output.PagesRemoved)
+	}
 
 	return nil, output, nil
 }
 
-// RegisterDocSearchTools registers documentation search tools
+// RegisterDocSearchTools registers documentation search tools. The index initializes in the
+// background: searches report its progress until it is ready.
 func RegisterDocSearchTools(server *mcp.Server) error {
-	// Initialize doc search synchronously
-	if err := InitializeDocSearch(); err != nil {
-		log.Printf("Warning: Documentation search initialization failed: %v", err)
-		log.Printf("Documentation search will attempt to initialize on first use")
-	}
+	startDocSearchWarmup()
 
 	// Tool 18: search_documentation
-	mcp.AddTool(server,
+	toolkit.AddTool(server,
 		&mcp.Tool{
 			Name:        "search_documentation",
 			Description: "Search through KrakenD documentation using full-text search. Returns top relevant chunks with context.",
@@ -980,7 +1294,7 @@ func RegisterDocSearchTools(server *mcp.Server) error {
 	)
 
 	// Tool 20: refresh_documentation_index
-	mcp.AddTool(server,
+	toolkit.AddTool(server,
 		&mcp.Tool{
 			Name:        "refresh_documentation_index",
 			Description: "Force re-download and re-index of KrakenD documentation (auto-runs if cache > 7 days old)",
@@ -1001,23 +1315,22 @@ func CloseDocSearch() error {
 		indexPtr := indexMgr.current.Swap(nil)
 
 		if indexPtr != nil {
-			log.Printf("Waiting for in-flight searches to complete before closing...")
-
-			// Wait for all in-flight searches to complete
-			indexMgr.wg.Wait()
-
-			// Now safe to close index
+			// Close waits for the searches in flight
+			slog.Debug("Waiting for in-flight searches before closing the index")
 			index := *indexPtr
 			closeErr = index.Close()
 			if closeErr != nil {
-				log.Printf("Error closing doc index: %v", closeErr)
+				slog.Error("Error closing doc index", "error", closeErr)
 			}
 		}
 	}
 
-	// No lock to release - lock is only held during refresh operations
+	// The lock is only held during refresh operations: release it when shutdown interrupts one
+	if err := releaseLock(); err != nil {
+		slog.Error("Error releasing index lock", "error", err)
+	}
 	if closeErr == nil {
-		log.Printf("✓ Doc index closed successfully")
+		slog.Info("Doc index closed")
 	}
 
 	return closeErr
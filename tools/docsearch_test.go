@@ -1,6 +1,7 @@
 package tools
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -9,6 +10,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/blevesearch/bleve/v2"
 	"github.com/krakend/mcp-server/internal/indexing"
 )
 
@@ -310,9 +312,6 @@ func TestIndexHolderConcurrentReads(t *testing.T) {
 		go func(id int) {
 			defer func() { doneChan <- true }()
 
-			holder.wg.Add(1)
-			defer holder.wg.Done()
-
 			// Load index atomically
 			indexPtr := holder.current.Load()
 			if indexPtr == nil {
@@ -345,9 +344,6 @@ func TestIndexHolderConcurrentReads(t *testing.T) {
 	for err := range errChan {
 		t.Error(err)
 	}
-
-	// Verify WaitGroup drained
-	holder.wg.Wait() // Should return immediately
 }
 
 func TestIndexHolderAtomicSwap(t *testing.T) {
@@ -438,43 +434,67 @@ func TestIndexHolderRefreshMutexSerialization(t *testing.T) {
 	}
 }
 
-func TestIndexHolderWaitGroupTracking(t *testing.T) {
-	// Test that WaitGroup properly tracks in-flight operations
-	holder := &indexHolder{}
-
-	const numOperations = 100
-	doneChan := make(chan bool, numOperations)
-
-	// Launch operations
-	for i := 0; i < numOperations; i++ {
-		holder.wg.Add(1)
-		go func() {
-			defer holder.wg.Done()
-			defer func() { doneChan <- true }()
+func TestBleveIndexWrapper_CloseWaitsForSearches(t *testing.T) {
+	index, err := bleve.NewMemOnly(bleve.NewIndexMapping())
+	if err != nil {
+		t.Fatal(err)
+	}
+	wrapped := NewBleveIndexWrapper(index).(*bleveIndexWrapper)
 
-			// Simulate some work
-			for j := 0; j < 100; j++ {
-				_ = j * j
-			}
-		}()
+	// A search in flight holds the index open
+	wrapped.mu.RLock()
+	closed := make(chan error)
+	go func() { closed <- wrapped.Close() }()
+	select {
+	case <-closed:
+		t.Fatal("Close must wait for the operations in flight")
+	case <-time.After(50 * time.Millisecond):
+	}
+	wrapped.mu.RUnlock()
+	if err := <-closed; err != nil {
+		t.Fatalf("Close() error = %v", err)
 	}
 
-	// Wait for all operations to complete
-	holder.wg.Wait()
+	// Later operations fail without touching the closed bleve index, closing again is a no-op
+	if _, err := wrapped.DocCount(); !errors.Is(err, errIndexClosed) {
+		t.Errorf("DocCount() after Close = %v, want errIndexClosed", err)
+	}
+	if err := wrapped.Close(); err != nil {
+		t.Errorf("Second Close() error = %v", err)
+	}
+}
 
-	// Verify all goroutines finished
-	completedCount := 0
-	for i := 0; i < numOperations; i++ {
-		select {
-		case <-doneChan:
-			completedCount++
-		default:
-			// Should not happen - all should be done
+func TestWithDocIndex_RetriesOnSwappedIndex(t *testing.T) {
+	oldMgr := indexMgr
+	indexMgr = &indexHolder{}
+	t.Cleanup(func() { indexMgr = oldMgr })
+
+	first, second := Index(newMockIndex(1)), Index(newMockIndex(2))
+	indexMgr.current.Store(&first)
+	var used []Index
+	err := withDocIndex(func(index Index) error {
+		used = append(used, index)
+		if len(used) == 1 {
+			// A refresh swaps the index while the search runs
+			indexMgr.current.Store(&second)
+			return errIndexClosed
 		}
+		return nil
+	})
+	if err != nil || len(used) != 2 || used[1] != second {
+		t.Errorf("withDocIndex() = %v after %d attempt(s)", err, len(used))
 	}
 
-	if completedCount != numOperations {
-		t.Errorf("Expected %d completed operations, got %d", numOperations, completedCount)
+	// Closed by the shutdown: no retry
+	indexMgr.current.Store(&first)
+	calls := 0
+	err = withDocIndex(func(index Index) error {
+		calls++
+		indexMgr.current.Store(nil)
+		return errIndexClosed
+	})
+	if !errors.Is(err, errIndexClosed) || calls != 1 {
+		t.Errorf("withDocIndex() during shutdown = %v after %d call(s)", err, calls)
 	}
 }
 
@@ -502,11 +522,9 @@ func TestIndexHolderConcurrentSwapAndRead(t *testing.T) {
 			defer func() { doneChan <- true }()
 
 			for j := 0; j < iterations; j++ {
-				holder.wg.Add(1)
 				indexPtr := holder.current.Load()
 
 				if indexPtr == nil {
-					holder.wg.Done()
 					errChan <- fmt.Errorf("reader %d iteration %d: got nil", id, j)
 					return
 				}
@@ -514,7 +532,6 @@ func TestIndexHolderConcurrentSwapAndRead(t *testing.T) {
 				// Try to access the index
 				index := *indexPtr
 				_, err := index.DocCount()
-				holder.wg.Done()
 
 				if err != nil && err.Error() != "index closed" {
 					// Allow "index closed" errors during swap (expected race)
@@ -534,11 +551,9 @@ func TestIndexHolderConcurrentSwapAndRead(t *testing.T) {
 			newMock := newMockIndex(i + 1)
 			newIdx := Index(newMock)
 
-			// Swap atomically
-			_ = holder.current.Swap(&newIdx)
-
-			// Note: In production, cleanup happens in background
-			// Here we skip cleanup to avoid WaitGroup misuse in test
+			// Swap atomically and close the old index, as a refresh does
+			old := holder.current.Swap(&newIdx)
+			(*old).Close()
 		}
 	}()
 
@@ -554,9 +569,6 @@ func TestIndexHolderConcurrentSwapAndRead(t *testing.T) {
 	for err := range errChan {
 		t.Error(err)
 	}
-
-	// Final wait to ensure all ops completed
-	holder.wg.Wait()
 }
 
 // --- Lock Mechanism Tests ---
@@ -0,0 +1,156 @@
+package tools
+
+import (
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Steps of the documentation index initialization. Extracting and refreshing are alternatives:
+// the embedded index is extracted on first run, a stale one is refreshed from the remote docs.
+const (
+	warmupChecking = iota
+	warmupExtracting
+	warmupRefreshing
+	warmupCopying
+	warmupOpening
+)
+
+// warmupSteps describe each step with the time it usually takes, to estimate when to retry
+var warmupSteps = []struct {
+	stage    string
+	position int // Step reported to clients, out of warmupPositions
+	estimate time.Duration
+}{
+	warmupChecking:   {"checking the master index", 1, time.Second},
+	warmupExtracting: {"extracting the embedded index", 1, 5 * time.Second},
+	warmupRefreshing: {"refreshing the stale index from the remote documentation", 1, 30 * time.Second},
+	warmupCopying:    {"copying the index", 2, 3 * time.Second},
+	warmupOpening:    {"opening the index", 3, 2 * time.Second},
+}
+
+const warmupPositions = 3
+
+// docSearchWarmup tracks the initialization of the documentation index in the background, so the
+// server answers tool calls while the index is extracted, refreshed or copied
+var docSearchWarmup struct {
+	mu        sync.Mutex
+	running   bool
+	started   time.Time
+	step      int
+	stepStart time.Time
+	err       error // Error of the last initialization
+	done      chan struct{}
+}
+
+// docSearchInitMu serializes initializations of the documentation index (the background warmup
+// and the setup tool), which copy the index to the same process directory
+var docSearchInitMu sync.Mutex
+
+// DocIndexWarmup reports the progress of the documentation index initialization
+type DocIndexWarmup struct {
+	Stage          string `json:"stage"`
+	Step           int    `json:"step"`
+	Steps          int    `json:"steps"`
+	ElapsedSeconds int    `json:"elapsed_seconds"`
+	RetryInSeconds int    `json:"retry_in_seconds"`
+	LastError      string `json:"last_error,omitempty"` // Why the previous initialization failed
+}
+
+// indexWarmingUpError is returned by searches while the index initializes
+type indexWarmingUpError struct {
+	warmup DocIndexWarmup
+}
+
+func (e *indexWarmingUpError) Error() string {
+	msg := fmt.Sprintf("documentation index is warming up (%s, step %d of %d, %ds elapsed): retry in %ds",
+		e.warmup.Stage, e.warmup.Step, e.warmup.Steps, e.warmup.ElapsedSeconds, e.warmup.RetryInSeconds)
+	if e.warmup.LastError != "" {
+		msg += "; the previous attempt failed: " + e.warmup.LastError
+	}
+	return msg
+}
+
+// startDocSearchWarmup initializes the documentation index in the background, unless it is
+// already initializing
+func startDocSearchWarmup() {
+	w := &docSearchWarmup
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.running {
+		return
+	}
+	w.running = true
+	w.started, w.stepStart, w.step = time.Now(), time.Now(), warmupChecking
+	w.done = make(chan struct{})
+
+	go func(done chan struct{}) {
+		defer close(done)
+		err := InitializeDocSearch()
+		if err != nil {
+			slog.Warn("Documentation search initialization failed, retrying on next search", "error", err)
+		}
+		w.mu.Lock()
+		w.running, w.err = false, err
+		w.mu.Unlock()
+	}(w.done)
+}
+
+// waitDocSearchWarmup waits up to timeout for a running initialization to finish, reporting
+// whether none is running anymore
+func waitDocSearchWarmup(timeout time.Duration) bool {
+	docSearchWarmup.mu.Lock()
+	running, done := docSearchWarmup.running, docSearchWarmup.done
+	docSearchWarmup.mu.Unlock()
+	if !running {
+		return true
+	}
+	select {
+	case <-done:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
+
+// setWarmupStep records the step the initialization is running
+func setWarmupStep(step int) {
+	docSearchWarmup.mu.Lock()
+	docSearchWarmup.step, docSearchWarmup.stepStart = step, time.Now()
+	docSearchWarmup.mu.Unlock()
+}
+
+// docSearchWarmupProgress returns the progress of the initialization, and false when none runs
+func docSearchWarmupProgress(now time.Time) (DocIndexWarmup, bool) {
+	w := &docSearchWarmup
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if !w.running {
+		return DocIndexWarmup{}, false
+	}
+
+	// What is left of the current step, at least a second, and the steps after it
+	remaining := max(warmupSteps[w.step].estimate-now.Sub(w.stepStart), time.Second)
+	for step := max(w.step+1, warmupCopying); step < len(warmupSteps); step++ {
+		remaining += warmupSteps[step].estimate
+	}
+	progress := DocIndexWarmup{
+		Stage:          warmupSteps[w.step].stage,
+		Step:           warmupSteps[w.step].position,
+		Steps:          warmupPositions,
+		ElapsedSeconds: int(now.Sub(w.started).Seconds()),
+		RetryInSeconds: int((remaining + time.Second - 1) / time.Second),
+	}
+	if w.err != nil {
+		progress.LastError = w.err.Error()
+	}
+	return progress, true
+}
+
+// lastWarmupError returns why the last initialization failed, nil when it succeeded
+func lastWarmupError() error {
+	docSearchWarmup.mu.Lock()
+	defer docSearchWarmup.mu.Unlock()
+	return docSearchWarmup.err
+}
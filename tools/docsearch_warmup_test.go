@@ -0,0 +1,111 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// simulateWarmup pretends an initialization is running the given step since stepAge
+func simulateWarmup(t *testing.T, step int, stepAge time.Duration, lastErr error) {
+	t.Helper()
+	oldMgr := indexMgr
+	indexMgr = &indexHolder{}
+	docSearchWarmup.mu.Lock()
+	docSearchWarmup.running, docSearchWarmup.err = true, lastErr
+	docSearchWarmup.started = time.Now().Add(-stepAge - 2*time.Second)
+	docSearchWarmup.step, docSearchWarmup.stepStart = step, time.Now().Add(-stepAge)
+	docSearchWarmup.mu.Unlock()
+	t.Cleanup(func() {
+		indexMgr = oldMgr
+		docSearchWarmup.mu.Lock()
+		docSearchWarmup.running, docSearchWarmup.err = false, nil
+		docSearchWarmup.mu.Unlock()
+	})
+}
+
+func TestSearchDocumentation_WarmingUp(t *testing.T) {
+	simulateWarmup(t, warmupRefreshing, 10*time.Second, errors.New("connection refused"))
+
+	result, output, err := SearchDocumentation(context.Background(), nil, SearchDocumentationInput{Query: "rate limit"})
+	if err != nil {
+		t.Fatalf("SearchDocumentation() must not fail while warming up, got %v", err)
+	}
+	if output.Warmup == nil || output.Warmup.Stage != warmupSteps[warmupRefreshing].stage || output.Warmup.Step != 1 || output.Warmup.Steps != 3 {
+		t.Fatalf("Unexpected warmup progress: %+v", output.Warmup)
+	}
+	// 20s left of the refresh, then copying and opening the index
+	if output.Warmup.RetryInSeconds != 25 || output.Warmup.ElapsedSeconds != 12 {
+		t.Errorf("Unexpected estimate: %+v", output.Warmup)
+	}
+	for _, want := range []string{"warming up", "retry in 25s", "connection refused"} {
+		if !strings.Contains(output.Message, want) {
+			t.Errorf("Expected %q in the message: %s", want, output.Message)
+		}
+	}
+	if result.Meta["index_status"] != "warming_up" || len(output.Results) != 0 {
+		t.Errorf("Unexpected result: %+v %+v", result.Meta, output)
+	}
+
+	// Other tools searching the docs fail with the same message
+	if _, err := searchKnowledgeDocs("rate limit", 5); err == nil || !strings.Contains(err.Error(), "retry in") {
+		t.Errorf("Expected a warming up error, got %v", err)
+	}
+}
+
+func TestDocSearchWarmupProgress(t *testing.T) {
+	if _, running := docSearchWarmupProgress(time.Now()); running {
+		t.Fatal("Expected no warmup running")
+	}
+
+	tests := []struct {
+		step      int
+		stepAge   time.Duration
+		wantStep  int
+		wantRetry int
+	}{
+		{warmupChecking, 0, 1, 6},
+		{warmupExtracting, 2 * time.Second, 1, 8},
+		{warmupCopying, time.Second, 2, 4},
+		{warmupOpening, time.Minute, 3, 1}, // Slower than usual: retry soon
+	}
+	for _, tt := range tests {
+		simulateWarmup(t, tt.step, tt.stepAge, nil)
+		progress, running := docSearchWarmupProgress(docSearchWarmup.stepStart.Add(tt.stepAge))
+		if !running || progress.Step != tt.wantStep || progress.RetryInSeconds != tt.wantRetry || progress.LastError != "" {
+			t.Errorf("Step %d: unexpected progress %+v", tt.step, progress)
+		}
+	}
+}
+
+func TestStartDocSearchWarmup(t *testing.T) {
+	origDataDir := dataDir
+	dataDir = t.TempDir()
+	oldMgr := indexMgr
+	indexMgr = &indexHolder{}
+	t.Cleanup(func() {
+		if indexPtr := indexMgr.current.Load(); indexPtr != nil {
+			(*indexPtr).Close()
+		}
+		indexMgr = oldMgr
+		dataDir = origDataDir
+	})
+
+	start := time.Now()
+	startDocSearchWarmup()
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Starting the warmup must not block, took %s", elapsed)
+	}
+	if !waitDocSearchWarmup(time.Minute) {
+		t.Fatal("Warmup did not finish")
+	}
+	if _, running := docSearchWarmupProgress(time.Now()); running {
+		t.Error("Expected the warmup to be finished")
+	}
+	// The embedded index may be a placeholder in tests: the failure is then kept for the next search
+	if indexMgr.current.Load() == nil && lastWarmupError() == nil {
+		t.Error("Expected either an index or the initialization error")
+	}
+}
@@ -0,0 +1,249 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/krakend/mcp-server/internal/features"
+	"github.com/krakend/mcp-server/internal/toolkit"
+	"github.com/krakend/mcp-server/tools/validation"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	defaultEditorSchemaFile = ".vscode/krakend.schema.json"
+	vscodeSettingsFile      = ".vscode/settings.json"
+)
+
+// defaultSchemaFileMatch lists the file patterns associated with the KrakenD schema in the editor
+var defaultSchemaFileMatch = []string{"krakend.json", "krakend*.json", "**/config/templates/*.tmpl"}
+
+// ExportEditorSchemaInput defines input for export_editor_schema tool
+type ExportEditorSchemaInput struct {
+	Config    string   `json:"config,omitempty" jsonschema:"KrakenD configuration (JSON string or file path) used to detect version and edition (optional)"`
+	Version   string   `json:"version,omitempty" jsonschema:"KrakenD version to pin the schema to, e.g. 2.12 (optional, defaults to the config $schema or latest)"`
+	Edition   string   `json:"edition,omitempty" jsonschema:"Schema edition: ce or ee (optional, detected from the config features)"`
+	Workspace string   `json:"workspace,omitempty" jsonschema:"Workspace directory to write into (optional, defaults to the current directory)"`
+	Mode      string   `json:"mode,omitempty" jsonschema:"What to write: file (local schema copy), vscode (settings.json association to the remote schema) or both (default: local copy associated in settings.json)"`
+	FileMatch []string `json:"file_match,omitempty" jsonschema:"File patterns associated with the schema in .vscode/settings.json (optional)"`
+}
+
+// ExportEditorSchemaOutput defines output for export_editor_schema tool
+type ExportEditorSchemaOutput struct {
+	SchemaURL    string   `json:"schema_url"`
+	Version      string   `json:"version"`
+	Edition      string   `json:"edition"`
+	SchemaFile   string   `json:"schema_file,omitempty"`
+	SettingsFile string   `json:"settings_file,omitempty"`
+	FileMatch    []string `json:"file_match,omitempty"`
+	Message      string   `json:"message"`
+}
+
+// ExportEditorSchema writes the version-pinned KrakenD schema into the workspace for editor validation
+func ExportEditorSchema(ctx context.Context, req *mcp.CallToolRequest, input ExportEditorSchemaInput) (*mcp.CallToolResult, ExportEditorSchemaOutput, error) {
+	version := input.Version
+	edition := input.Edition
+
+	if input.Config != "" {
+		configContent, err := readConfigContent(input.Config)
+		if err != nil {
+			return nil, ExportEditorSchemaOutput{}, fmt.Errorf("failed to read config: %w", err)
+		}
+		if version == "" {
+			version = validation.ExtractVersionFromConfig(configContent)
+		}
+		if edition == "" && features.DetectEnterpriseFeatures(configContent, nil) {
+			edition = "ee"
+		}
+	}
+	if version == "" {
+		version = "latest"
+	}
+	if edition == "" {
+		edition = "ce"
+	}
+	if edition != "ce" && edition != "ee" {
+		return nil, ExportEditorSchemaOutput{}, fmt.Errorf("invalid edition %q (expected ce or ee)", edition)
+	}
+
+	mode := input.Mode
+	if mode == "" {
+		mode = "both"
+	}
+	if mode != "file" && mode != "vscode" && mode != "both" {
+		return nil, ExportEditorSchemaOutput{}, fmt.Errorf("invalid mode %q (expected file, vscode or both)", mode)
+	}
+
+	workspace := input.Workspace
+	if workspace == "" {
+		workspace = "."
+	}
+	fileMatch := input.FileMatch
+	if len(fileMatch) == 0 {
+		fileMatch = defaultSchemaFileMatch
+	}
+
+	// The latest schema is only a relative $ref to the versioned one, which does not exist next to
+	// a local copy: copy the versioned schema instead
+	resolved := false
+	if version == "latest" && mode != "vscode" {
+		if latest, err := latestSchemaVersion(ctx, edition == "ee"); err == nil {
+			version, resolved = latest, true
+		}
+	}
+
+	output := ExportEditorSchemaOutput{
+		SchemaURL: validation.SchemaURL(version, edition == "ee"),
+		Version:   version,
+		Edition:   edition,
+	}
+
+	// The association points to the local copy when one is written, otherwise to the remote schema
+	schemaRef := output.SchemaURL
+
+	if mode == "file" || mode == "both" {
+		content, err := validation.SchemaFetcher(ctx, output.SchemaURL)
+		if err != nil {
+			return nil, ExportEditorSchemaOutput{}, fmt.Errorf("failed to download schema from %s: %w", output.SchemaURL, err)
+		}
+		content, err = absoluteSchemaRefs(content, output.SchemaURL)
+		if err != nil {
+			return nil, ExportEditorSchemaOutput{}, fmt.Errorf("downloaded schema from %s is not valid JSON", output.SchemaURL)
+		}
+
+		schemaPath := filepath.Join(workspace, defaultEditorSchemaFile)
+		if err := os.MkdirAll(filepath.Dir(schemaPath), 0o755); err != nil {
+			return nil, ExportEditorSchemaOutput{}, fmt.Errorf("failed to create schema directory: %w", err)
+		}
+		if err := os.WriteFile(schemaPath, content, 0o644); err != nil {
+			return nil, ExportEditorSchemaOutput{}, fmt.Errorf("failed to write schema file: %w", err)
+		}
+		output.SchemaFile = schemaPath
+		schemaRef = "./" + filepath.ToSlash(defaultEditorSchemaFile)
+	}
+
+	if mode == "vscode" || mode == "both" {
+		settingsPath := filepath.Join(workspace, vscodeSettingsFile)
+		if err := associateVSCodeSchema(settingsPath, schemaRef, fileMatch); err != nil {
+			return nil, ExportEditorSchemaOutput{}, err
+		}
+		output.SettingsFile = settingsPath
+		output.FileMatch = fileMatch
+	}
+
+	output.Message = fmt.Sprintf("KrakenD %s schema (v%s) exported for editor validation", edition, version)
+	if version == "latest" {
+		output.Message = fmt.Sprintf("KrakenD %s schema (latest) exported for editor validation; set $schema to a versioned URL to pin it", edition)
+	} else if resolved {
+		output.Message = fmt.Sprintf("KrakenD %s schema (latest, currently v%s) exported for editor validation; export it again after upgrading KrakenD", edition, version)
+	}
+
+	return nil, output, nil
+}
+
+// absoluteSchemaRefs rewrites the relative $refs of a schema downloaded from schemaURL to absolute
+// URLs, so they still resolve from a local copy. References within the schema ("#/...") are kept.
+func absoluteSchemaRefs(content []byte, schemaURL string) ([]byte, error) {
+	var schema interface{}
+	if err := json.Unmarshal(content, &schema); err != nil {
+		return nil, err
+	}
+	base, err := url.Parse(schemaURL)
+	if err != nil {
+		return nil, err
+	}
+
+	rewritten := false
+	var walk func(value interface{})
+	walk = func(value interface{}) {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			for k, child := range v {
+				if ref, ok := child.(string); ok && k == "$ref" && !strings.HasPrefix(ref, "#") {
+					if u, err := url.Parse(ref); err == nil && !u.IsAbs() {
+						v[k] = base.ResolveReference(u).String()
+						rewritten = true
+					}
+					continue
+				}
+				walk(child)
+			}
+		case []interface{}:
+			for _, child := range v {
+				walk(child)
+			}
+		}
+	}
+	walk(schema)
+	if !rewritten {
+		return content, nil
+	}
+	return json.MarshalIndent(schema, "", "  ")
+}
+
+// associateVSCodeSchema adds or updates the json.schemas entry for the KrakenD schema in settings.json.
+// Existing settings are preserved; files with comments are not rewritten to avoid losing them.
+func associateVSCodeSchema(settingsPath, schemaRef string, fileMatch []string) error {
+	settings := map[string]interface{}{}
+
+	if data, err := os.ReadFile(settingsPath); err == nil {
+		if err := json.Unmarshal(data, &settings); err != nil {
+			return fmt.Errorf("cannot update %s (it may contain comments): add {\"fileMatch\": %q, \"url\": %q} to json.schemas manually", settingsPath, fileMatch, schemaRef)
+		}
+	} else if !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read %s: %w", settingsPath, err)
+	}
+
+	entry := map[string]interface{}{
+		"fileMatch": fileMatch,
+		"url":       schemaRef,
+	}
+
+	schemas, _ := settings["json.schemas"].([]interface{})
+	replaced := false
+	for i, s := range schemas {
+		existing, ok := s.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if url, _ := existing["url"].(string); isKrakenDSchemaRef(url) {
+			schemas[i] = entry
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		schemas = append(schemas, entry)
+	}
+	settings["json.schemas"] = schemas
+
+	data, err := json.MarshalIndent(settings, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode settings: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(settingsPath), 0o755); err != nil {
+		return fmt.Errorf("failed to create settings directory: %w", err)
+	}
+	return os.WriteFile(settingsPath, append(data, '\n'), 0o644)
+}
+
+// isKrakenDSchemaRef reports whether a json.schemas url was written by this tool
+func isKrakenDSchemaRef(url string) bool {
+	return url == "./"+filepath.ToSlash(defaultEditorSchemaFile) || strings.HasPrefix(url, "https://www.krakend.io/schema/")
+}
+
+// RegisterEditorSchemaTools registers editor integration tools
+func RegisterEditorSchemaTools(server *mcp.Server) {
+	toolkit.AddTool(server,
+		&mcp.Tool{
+			Name:        "export_editor_schema",
+			Description: "Export the version-pinned KrakenD JSON Schema (EE schema when Enterprise features are used) into the workspace so editors validate configurations natively. Writes a local schema copy and/or a json.schemas association in .vscode/settings.json.",
+		},
+		ExportEditorSchema,
+	)
+}
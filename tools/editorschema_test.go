@@ -0,0 +1,138 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/krakend/mcp-server/tools/validation"
+)
+
+func setMockSchemaFetcher(t *testing.T) *[]string {
+	t.Helper()
+	var requested []string
+	orig := validation.SchemaFetcher
+	validation.SchemaFetcher = func(ctx context.Context, url string) ([]byte, error) {
+		requested = append(requested, url)
+		return []byte(`{"$schema": "http://json-schema.org/draft-07/schema#", "type": "object"}`), nil
+	}
+	t.Cleanup(func() { validation.SchemaFetcher = orig })
+	return &requested
+}
+
+func TestExportEditorSchema_BothModes(t *testing.T) {
+	requested := setMockSchemaFetcher(t)
+	workspace := t.TempDir()
+
+	config := `{"$schema": "https://www.krakend.io/schema/v2.12/krakend.json", "extra_config": {"auth/api-keys": {}}}`
+	_, output, err := ExportEditorSchema(context.Background(), nil, ExportEditorSchemaInput{Config: config, Workspace: workspace})
+	if err != nil {
+		t.Fatalf("ExportEditorSchema() error = %v", err)
+	}
+
+	if output.SchemaURL != "https://www.krakend.io/schema/ee/v2.12/krakend.json" {
+		t.Errorf("Unexpected schema URL: %s", output.SchemaURL)
+	}
+	if len(*requested) != 1 || (*requested)[0] != output.SchemaURL {
+		t.Errorf("Expected schema to be downloaded from %s, got %v", output.SchemaURL, *requested)
+	}
+	if _, err := os.Stat(filepath.Join(workspace, defaultEditorSchemaFile)); err != nil {
+		t.Errorf("Expected local schema file: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(workspace, vscodeSettingsFile))
+	if err != nil {
+		t.Fatalf("Expected settings.json to be written: %v", err)
+	}
+	var settings map[string]interface{}
+	json.Unmarshal(data, &settings)
+	schemas := settings["json.schemas"].([]interface{})
+	if url := schemas[0].(map[string]interface{})["url"]; url != "./.vscode/krakend.schema.json" {
+		t.Errorf("Expected association to local schema, got %v", url)
+	}
+}
+
+func TestExportEditorSchema_PreservesSettings(t *testing.T) {
+	setMockSchemaFetcher(t)
+	workspace := t.TempDir()
+	os.MkdirAll(filepath.Join(workspace, ".vscode"), 0o755)
+	os.WriteFile(filepath.Join(workspace, vscodeSettingsFile), []byte(`{
+		"editor.tabSize": 2,
+		"json.schemas": [
+			{"fileMatch": ["other.json"], "url": "https://example.com/other.json"},
+			{"fileMatch": ["krakend.json"], "url": "https://www.krakend.io/schema/v2.5/krakend.json"}
+		]
+	}`), 0o644)
+
+	for i := 0; i < 2; i++ {
+		if _, _, err := ExportEditorSchema(context.Background(), nil, ExportEditorSchemaInput{Version: "2.12", Workspace: workspace, Mode: "vscode"}); err != nil {
+			t.Fatalf("ExportEditorSchema() error = %v", err)
+		}
+	}
+
+	data, _ := os.ReadFile(filepath.Join(workspace, vscodeSettingsFile))
+	var settings map[string]interface{}
+	json.Unmarshal(data, &settings)
+
+	if settings["editor.tabSize"] != float64(2) {
+		t.Error("Expected unrelated settings to be preserved")
+	}
+	schemas := settings["json.schemas"].([]interface{})
+	if len(schemas) != 2 {
+		t.Fatalf("Expected KrakenD association to be replaced in place, got %d entries", len(schemas))
+	}
+	if url := schemas[1].(map[string]interface{})["url"]; url != "https://www.krakend.io/schema/v2.12/krakend.json" {
+		t.Errorf("Expected remote v2.12 schema association, got %v", url)
+	}
+}
+
+func TestExportEditorSchema_InvalidMode(t *testing.T) {
+	if _, _, err := ExportEditorSchema(context.Background(), nil, ExportEditorSchemaInput{Mode: "emacs"}); err == nil {
+		t.Error("Expected error for invalid mode")
+	}
+}
+
+func TestExportEditorSchema_LatestRef(t *testing.T) {
+	orig := validation.SchemaFetcher
+	validation.SchemaFetcher = func(ctx context.Context, url string) ([]byte, error) {
+		switch url {
+		case "https://www.krakend.io/schema/krakend.json":
+			return []byte(`{"$schema": "http://json-schema.org/draft-07/schema#", "$ref": "v2.12/krakend.json"}`), nil
+		case "https://www.krakend.io/schema/v2.12/krakend.json":
+			return []byte(`{"type": "object", "properties": {"endpoints": {"$ref": "endpoint.json"}, "port": {"$ref": "#/definitions/port"}}}`), nil
+		}
+		return nil, fmt.Errorf("unexpected schema %s", url)
+	}
+	t.Cleanup(func() { validation.SchemaFetcher = orig })
+	workspace := t.TempDir()
+
+	_, output, err := ExportEditorSchema(context.Background(), nil, ExportEditorSchemaInput{Workspace: workspace, Mode: "file"})
+	if err != nil {
+		t.Fatalf("ExportEditorSchema() error = %v", err)
+	}
+	if output.Version != "2.12" || output.SchemaURL != "https://www.krakend.io/schema/v2.12/krakend.json" {
+		t.Errorf("Expected latest to be resolved to v2.12, got %+v", output)
+	}
+
+	data, err := os.ReadFile(filepath.Join(workspace, defaultEditorSchemaFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var schema struct {
+		Properties map[string]struct {
+			Ref string `json:"$ref"`
+		} `json:"properties"`
+	}
+	if err := json.Unmarshal(data, &schema); err != nil {
+		t.Fatalf("Invalid schema copy: %v", err)
+	}
+	if ref := schema.Properties["endpoints"].Ref; ref != "https://www.krakend.io/schema/v2.12/endpoint.json" {
+		t.Errorf("Expected an absolute $ref, got %q", ref)
+	}
+	if ref := schema.Properties["port"].Ref; ref != "#/definitions/port" {
+		t.Errorf("Expected the local $ref to be kept, got %q", ref)
+	}
+}
@@ -3,6 +3,8 @@ package tools
 import (
 	"embed"
 	"io/fs"
+
+	"github.com/krakend/mcp-server/tools/validation"
 )
 
 // Embed static data files into the binary.
@@ -12,12 +14,20 @@ import (
 // - KrakenD documentation (offline documentation search)
 // - Bleve search index (pre-built for instant search)
 // - Feature matrix YAML (offline feature discovery; downloaded by build.sh)
+// - Plugin catalog YAML (plugin discovery)
+// - KrakenD JSON schemas (offline schema validation; downloaded by build.sh)
 
 //go:embed data/docs/*
 //go:embed data/search/index/*
 //go:embed all:data/features
+//go:embed data/plugins/*
+//go:embed all:data/schemas
 var embeddedFS embed.FS
 
+func init() {
+	validation.SchemaBundle, _ = fs.Sub(embeddedFS, "data/schemas")
+}
+
 // embeddedDataProvider implements DataProvider using embed.FS.
 // This is the production implementation that uses actual embedded files.
 type embeddedDataProvider struct {
@@ -0,0 +1,444 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/krakend/mcp-server/internal/toolkit"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// GenerateEndpointDocsInput defines input for generate_endpoint_docs tool
+type GenerateEndpointDocsInput struct {
+	Config         string   `json:"config" jsonschema:"KrakenD configuration (JSON string or file path)"`
+	ConfigEncoding string   `json:"config_encoding,omitempty" jsonschema:"Encoding of config for large configurations: base64 or gzip+base64 (optional, defaults to a plain JSON string or file path)"`
+	Endpoint       string   `json:"endpoint" jsonschema:"Endpoint path to document, as declared in the configuration (e.g. /users/{id})"`
+	Method         string   `json:"method,omitempty" jsonschema:"Endpoint method when the path is declared for several methods (optional)"`
+	GatewayURL     string   `json:"gateway_url,omitempty" jsonschema:"Public base URL of the gateway used in examples (optional, defaults to http://localhost:8080)"`
+	Samples        []string `json:"samples,omitempty" jsonschema:"Sample responses of each backend in declaration order, JSON string or file path (optional)"`
+	OutputFile     string   `json:"output_file,omitempty" jsonschema:"Path where the Markdown document is written (optional)"`
+}
+
+// EndpointStatusCode is a status code consumers can receive
+type EndpointStatusCode struct {
+	Code        int    `json:"code"`
+	Description string `json:"description"`
+}
+
+// EndpointAuth describes how consumers authenticate
+type EndpointAuth struct {
+	Type         string `json:"type"` // jwt, api-key or basic
+	Header       string `json:"header,omitempty"`
+	QueryString  string `json:"query_string,omitempty"`
+	Cookie       string `json:"cookie,omitempty"`
+	Instructions string `json:"instructions"`
+}
+
+// GenerateEndpointDocsOutput defines output for generate_endpoint_docs tool
+type GenerateEndpointDocsOutput struct {
+	Method         string               `json:"method"`
+	Endpoint       string               `json:"endpoint"`
+	Name           string               `json:"name,omitempty"` // From the name_endpoints sidecar file
+	Tags           []string             `json:"tags,omitempty"`
+	Description    string               `json:"description,omitempty"`
+	Curl           string               `json:"curl"`
+	Auth           []EndpointAuth       `json:"auth"`
+	StatusCodes    []EndpointStatusCode `json:"status_codes"`
+	RateLimits     []string             `json:"rate_limits"`
+	SampleResponse interface{}          `json:"sample_response,omitempty"`
+	Markdown       string               `json:"markdown"`
+	Warnings       []string             `json:"warnings,omitempty"`
+	OutputFile     string               `json:"output_file,omitempty"`
+}
+
+// findEndpoint returns the endpoint declared with a path and, optionally, a method
+func findEndpoint(config map[string]interface{}, path, method string) (map[string]interface{}, error) {
+	endpoints, _ := config["endpoints"].([]interface{})
+	var candidates []map[string]interface{}
+	for _, ep := range endpoints {
+		endpoint, ok := ep.(map[string]interface{})
+		if !ok || endpoint["endpoint"] != path {
+			continue
+		}
+		if method != "" && !strings.EqualFold(endpointMethod(endpoint), method) {
+			continue
+		}
+		candidates = append(candidates, endpoint)
+	}
+	switch len(candidates) {
+	case 0:
+		return nil, fmt.Errorf("endpoint %s not found in the configuration", strings.TrimSpace(method+" "+path))
+	case 1:
+		return candidates[0], nil
+	}
+	methods := make([]string, 0, len(candidates))
+	for _, c := range candidates {
+		methods = append(methods, endpointMethod(c))
+	}
+	return nil, fmt.Errorf("endpoint %s is declared for %s: set the method", path, strings.Join(methods, ", "))
+}
+
+// endpointMethod returns the method of an endpoint, GET when omitted
+func endpointMethod(endpoint map[string]interface{}) string {
+	if method, ok := endpoint["method"].(string); ok && method != "" {
+		return strings.ToUpper(method)
+	}
+	return "GET"
+}
+
+// endpointAuth lists the authentication mechanisms protecting an endpoint
+func endpointAuth(config, endpoint map[string]interface{}) []EndpointAuth {
+	auth := []EndpointAuth{}
+	extra, _ := endpoint["extra_config"].(map[string]interface{})
+
+	if validator, ok := extra["auth/validator"].(map[string]interface{}); ok {
+		a := EndpointAuth{Type: "jwt", Header: "Authorization", Instructions: "Send a JWT issued by the identity provider as Authorization: Bearer <token>"}
+		if cookie, ok := validator["cookie_key"].(string); ok && cookie != "" {
+			a.Cookie = cookie
+			a.Instructions += fmt.Sprintf(", or in the %s cookie", cookie)
+		}
+		if roles, ok := validator["roles"].([]interface{}); ok && len(roles) > 0 {
+			a.Instructions += fmt.Sprintf(". The token needs one of these roles: %s", joinValues(roles))
+		}
+		auth = append(auth, a)
+	}
+
+	if keys, ok := extra["auth/api-keys"].(map[string]interface{}); ok {
+		service, _ := config["extra_config"].(map[string]interface{})
+		settings, _ := service["auth/api-keys"].(map[string]interface{})
+		identifier, _ := settings["identifier"].(string)
+		if identifier == "" {
+			identifier = "Authorization"
+		}
+		a := EndpointAuth{Type: "api-key"}
+		if settings["strategy"] == "query_string" {
+			a.QueryString = identifier
+			a.Instructions = fmt.Sprintf("Send your API key in the %s query string parameter", identifier)
+		} else {
+			a.Header = identifier
+			a.Instructions = fmt.Sprintf("Send your API key in the %s header", identifier)
+			if identifier == "Authorization" {
+				a.Instructions += " as Bearer <key>"
+			}
+		}
+		if roles, ok := keys["roles"].([]interface{}); ok && len(roles) > 0 {
+			a.Instructions += fmt.Sprintf(". The key needs one of these roles: %s", joinValues(roles))
+		}
+		auth = append(auth, a)
+	}
+
+	if _, ok := extra["auth/basic"]; ok {
+		auth = append(auth, EndpointAuth{Type: "basic", Header: "Authorization", Instructions: "Send your credentials with HTTP Basic authentication"})
+	}
+	return auth
+}
+
+// joinValues renders a list of configuration values
+func joinValues(values []interface{}) string {
+	parts := make([]string, 0, len(values))
+	for _, v := range values {
+		parts = append(parts, fmt.Sprint(v))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// endpointRateLimits describes the router rate limits of an endpoint
+func endpointRateLimits(endpoint map[string]interface{}) []string {
+	limits := []string{}
+	extra, _ := endpoint["extra_config"].(map[string]interface{})
+	limit, ok := extra["qos/ratelimit/router"].(map[string]interface{})
+	if !ok {
+		return limits
+	}
+	every, _ := limit["every"].(string)
+	if every == "" {
+		every = "1s"
+	}
+	if maxRate, ok := limit["max_rate"].(float64); ok && maxRate > 0 {
+		limits = append(limits, fmt.Sprintf("%s requests every %s shared by all consumers", formatFloat(maxRate), every))
+	}
+	if clientRate, ok := limit["client_max_rate"].(float64); ok && clientRate > 0 {
+		identity := "IP address"
+		switch limit["strategy"] {
+		case "header":
+			key, _ := limit["key"].(string)
+			identity = key + " header"
+		case "param":
+			key, _ := limit["key"].(string)
+			identity = key + " parameter"
+		}
+		limits = append(limits, fmt.Sprintf("%s requests every %s per %s", formatFloat(clientRate), every, identity))
+	}
+	return limits
+}
+
+// endpointStatusCodes lists the status codes the gateway can answer for an endpoint
+func endpointStatusCodes(endpoint map[string]interface{}, auth []EndpointAuth, limited bool) []EndpointStatusCode {
+	backends, _ := endpoint["backend"].([]interface{})
+	extra, _ := endpoint["extra_config"].(map[string]interface{})
+
+	if endpoint["output_encoding"] == "no-op" {
+		return []EndpointStatusCode{{Code: 0, Description: "Status codes are those of the backend, which KrakenD returns unchanged"}}
+	}
+	codes := []EndpointStatusCode{{Code: 200, Description: "Success"}}
+	if len(backends) > 1 {
+		codes[0].Description += "; when some backends fail the response is partial and the X-KrakenD-Completed header is false"
+	}
+	if _, ok := extra["validation/json-schema"]; ok {
+		codes = append(codes, EndpointStatusCode{Code: 400, Description: "The request body does not match the expected schema"})
+	}
+	if len(auth) > 0 {
+		codes = append(codes, EndpointStatusCode{Code: 401, Description: "Missing or invalid credentials"})
+		for _, a := range auth {
+			if strings.Contains(a.Instructions, "roles") {
+				codes = append(codes, EndpointStatusCode{Code: 403, Description: "The credentials lack the required role"})
+				break
+			}
+		}
+	}
+	if limited {
+		codes = append(codes, EndpointStatusCode{Code: 429, Description: "Rate limit exceeded, retry later"})
+	}
+	codes = append(codes, EndpointStatusCode{Code: 500, Description: "The backends failed or did not answer within the endpoint timeout"})
+	return codes
+}
+
+// shapeBackendSample applies the backend manipulation options to a sample response
+func shapeBackendSample(backend map[string]interface{}, sample interface{}) interface{} {
+	if backend["is_collection"] == true {
+		sample = map[string]interface{}{"collection": sample}
+	}
+	data, ok := sample.(map[string]interface{})
+	if !ok {
+		return sample
+	}
+	if target, ok := backend["target"].(string); ok && target != "" {
+		nested, _ := data[target].(map[string]interface{})
+		data = nested
+		if data == nil {
+			data = map[string]interface{}{}
+		}
+	}
+	if allow, ok := backend["allow"].([]interface{}); ok && len(allow) > 0 {
+		allowed := map[string]interface{}{}
+		for _, a := range allow {
+			if key, ok := a.(string); ok {
+				if value, exists := data[key]; exists {
+					allowed[key] = value
+				}
+			}
+		}
+		data = allowed
+	}
+	if deny, ok := backend["deny"].([]interface{}); ok {
+		for _, d := range deny {
+			if key, ok := d.(string); ok {
+				delete(data, key)
+			}
+		}
+	}
+	if mapping, ok := backend["mapping"].(map[string]interface{}); ok {
+		for from, to := range mapping {
+			if value, exists := data[from]; exists {
+				if name, ok := to.(string); ok {
+					delete(data, from)
+					data[name] = value
+				}
+			}
+		}
+	}
+	if group, ok := backend["group"].(string); ok && group != "" {
+		return map[string]interface{}{group: data}
+	}
+	return data
+}
+
+// GenerateEndpointDocs builds consumer-facing documentation for an endpoint
+func GenerateEndpointDocs(ctx context.Context, req *mcp.CallToolRequest, input GenerateEndpointDocsInput) (*mcp.CallToolResult, GenerateEndpointDocsOutput, error) {
+	configContent, err := readEncodedConfig(input.Config, input.ConfigEncoding)
+	if err != nil {
+		return nil, GenerateEndpointDocsOutput{}, fmt.Errorf("failed to read config: %w", err)
+	}
+	var config map[string]interface{}
+	if err := json.Unmarshal([]byte(configContent), &config); err != nil {
+		return nil, GenerateEndpointDocsOutput{}, fmt.Errorf("invalid JSON: %w", err)
+	}
+	endpoint, err := findEndpoint(config, input.Endpoint, input.Method)
+	if err != nil {
+		return nil, GenerateEndpointDocsOutput{}, err
+	}
+	gateway := strings.TrimRight(input.GatewayURL, "/")
+	if gateway == "" {
+		gateway = defaultReplayGateway
+	}
+
+	method := endpointMethod(endpoint)
+	output := GenerateEndpointDocsOutput{
+		Method:     method,
+		Endpoint:   input.Endpoint,
+		Auth:       endpointAuth(config, endpoint),
+		RateLimits: endpointRateLimits(endpoint),
+	}
+	output.StatusCodes = endpointStatusCodes(endpoint, output.Auth, len(output.RateLimits) > 0)
+	// Names are kept next to configuration files, never sent encoded
+	if input.ConfigEncoding == "" {
+		if named, ok := loadEndpointNames(input.Config)[endpointKey(method, input.Endpoint)]; ok {
+			output.Name, output.Tags, output.Description = named.Name, named.Tags, named.Description
+		}
+	}
+
+	// Assemble the sample response as the gateway merges backend responses
+	backends, _ := endpoint["backend"].([]interface{})
+	if len(input.Samples) > len(backends) {
+		output.Warnings = append(output.Warnings, fmt.Sprintf("%d sample(s) given for %d backend(s): extra samples are ignored", len(input.Samples), len(backends)))
+	}
+	merged := map[string]interface{}{}
+	for i, b := range backends {
+		backend, ok := b.(map[string]interface{})
+		if !ok || i >= len(input.Samples) {
+			continue
+		}
+		content, err := readConfigContent(input.Samples[i])
+		if err != nil {
+			return nil, GenerateEndpointDocsOutput{}, fmt.Errorf("failed to read sample %d: %w", i, err)
+		}
+		var sample interface{}
+		if err := json.Unmarshal([]byte(content), &sample); err != nil {
+			return nil, GenerateEndpointDocsOutput{}, fmt.Errorf("invalid JSON in sample %d: %w", i, err)
+		}
+		if endpoint["output_encoding"] == "no-op" {
+			output.SampleResponse = sample
+			break
+		}
+		shaped, ok := shapeBackendSample(backend, sample).(map[string]interface{})
+		if !ok {
+			output.Warnings = append(output.Warnings, fmt.Sprintf("Sample %d is not a JSON object: set is_collection on its backend", i))
+			continue
+		}
+		for k, v := range shaped {
+			merged[k] = v
+		}
+	}
+	if output.SampleResponse == nil && len(merged) > 0 {
+		output.SampleResponse = merged
+	}
+
+	// curl example with placeholders for path parameters and credentials
+	path := input.Endpoint
+	for _, segment := range strings.Split(path, "/") {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			path = strings.Replace(path, segment, "<"+strings.Trim(segment, "{}")+">", 1)
+		}
+	}
+	var curl strings.Builder
+	target := gateway + path
+	for _, a := range output.Auth {
+		if a.QueryString != "" {
+			target += "?" + a.QueryString + "=$API_KEY"
+		}
+	}
+	fmt.Fprintf(&curl, "curl -i -X %s \"%s\"", method, target)
+	for _, a := range output.Auth {
+		switch {
+		case a.Type == "jwt":
+			curl.WriteString(" \\\n  -H \"Authorization: Bearer $TOKEN\"")
+		case a.Type == "api-key" && a.Header == "Authorization":
+			curl.WriteString(" \\\n  -H \"Authorization: Bearer $API_KEY\"")
+		case a.Type == "api-key" && a.Header != "":
+			fmt.Fprintf(&curl, " \\\n  -H \"%s: $API_KEY\"", a.Header)
+		case a.Type == "basic":
+			curl.WriteString(" \\\n  -u \"$USER:$PASSWORD\"")
+		}
+	}
+	if method == "POST" || method == "PUT" || method == "PATCH" {
+		curl.WriteString(" \\\n  -H 'Content-Type: application/json' \\\n  -d '{}'")
+	}
+	output.Curl = curl.String()
+	output.Markdown = renderEndpointDocs(output, endpoint)
+
+	if input.OutputFile != "" {
+		if err := os.MkdirAll(filepath.Dir(input.OutputFile), 0o755); err != nil {
+			return nil, GenerateEndpointDocsOutput{}, fmt.Errorf("failed to create %s: %w", filepath.Dir(input.OutputFile), err)
+		}
+		if err := os.WriteFile(input.OutputFile, []byte(output.Markdown), 0o644); err != nil {
+			return nil, GenerateEndpointDocsOutput{}, fmt.Errorf("failed to write %s: %w", input.OutputFile, err)
+		}
+		output.OutputFile = input.OutputFile
+	}
+	return nil, output, nil
+}
+
+// renderEndpointDocs renders the consumer documentation as Markdown
+func renderEndpointDocs(doc GenerateEndpointDocsOutput, endpoint map[string]interface{}) string {
+	var b strings.Builder
+	if doc.Name != "" {
+		fmt.Fprintf(&b, "# %s\n\n`%s %s`\n\n", doc.Name, doc.Method, doc.Endpoint)
+	} else {
+		fmt.Fprintf(&b, "# %s %s\n\n", doc.Method, doc.Endpoint)
+	}
+	if doc.Description != "" {
+		b.WriteString(doc.Description + "\n\n")
+	}
+	if len(doc.Tags) > 0 {
+		fmt.Fprintf(&b, "Tags: %s\n\n", strings.Join(doc.Tags, ", "))
+	}
+
+	b.WriteString("## Authentication\n\n")
+	if len(doc.Auth) == 0 {
+		b.WriteString("No authentication required.\n\n")
+	}
+	for _, a := range doc.Auth {
+		fmt.Fprintf(&b, "- %s.\n", a.Instructions)
+	}
+	if len(doc.Auth) > 0 {
+		b.WriteString("\n")
+	}
+
+	if params, ok := endpoint["input_query_strings"].([]interface{}); ok && len(params) > 0 {
+		fmt.Fprintf(&b, "## Query string\n\nAccepted parameters: %s. Other parameters are ignored.\n\n", joinValues(params))
+	}
+	if headers, ok := endpoint["input_headers"].([]interface{}); ok && len(headers) > 0 {
+		fmt.Fprintf(&b, "## Headers\n\nForwarded headers: %s. Other headers are ignored.\n\n", joinValues(headers))
+	}
+
+	b.WriteString("## Example\n\n```sh\n" + doc.Curl + "\n```\n\n")
+
+	b.WriteString("## Responses\n\n| Status | Description |\n|---|---|\n")
+	for _, c := range doc.StatusCodes {
+		code := fmt.Sprint(c.Code)
+		if c.Code == 0 {
+			code = "any"
+		}
+		fmt.Fprintf(&b, "| %s | %s |\n", code, c.Description)
+	}
+	b.WriteString("\n")
+
+	if len(doc.RateLimits) > 0 {
+		b.WriteString("## Rate limits\n\n")
+		for _, l := range doc.RateLimits {
+			fmt.Fprintf(&b, "- %s\n", l)
+		}
+		b.WriteString("\nRequests over the limit receive 429 Too Many Requests.\n\n")
+	}
+
+	if doc.SampleResponse != nil {
+		data, _ := json.MarshalIndent(doc.SampleResponse, "", "  ")
+		b.WriteString("## Sample response\n\n```json\n" + string(data) + "\n```\n")
+	}
+	return b.String()
+}
+
+// RegisterEndpointDocsTools registers consumer documentation generation tools
+func RegisterEndpointDocsTools(server *mcp.Server) {
+	toolkit.AddTool(server,
+		&mcp.Tool{
+			Name:        "generate_endpoint_docs",
+			Description: "Generate consumer-facing documentation for one endpoint: curl example, expected status codes, authentication instructions (JWT, API keys, basic auth), rate limit notes and a sample response assembled from backend samples with the endpoint's allow, deny, mapping, target and group options applied. Returns Markdown ready to share with API consumers.",
+		},
+		GenerateEndpointDocs,
+	)
+}
@@ -0,0 +1,103 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+const endpointDocsTestConfig = `{
+	"version": 3,
+	"extra_config": {"auth/api-keys": {"strategy": "header", "identifier": "X-Key", "keys": []}},
+	"endpoints": [
+		{
+			"endpoint": "/users/{id}",
+			"input_query_strings": ["fields"],
+			"extra_config": {
+				"auth/validator": {"alg": "RS256", "roles": ["admin"], "cookie_key": "session"},
+				"qos/ratelimit/router": {"max_rate": 100, "client_max_rate": 5, "strategy": "header", "key": "X-Tenant", "every": "1m"}
+			},
+			"backend": [
+				{"host": ["http://users"], "url_pattern": "/users/{id}", "deny": ["password"], "mapping": {"mail": "email"}},
+				{"host": ["http://orders"], "url_pattern": "/orders?user={id}", "is_collection": true, "group": "orders"}
+			]
+		},
+		{"endpoint": "/users/{id}", "method": "DELETE", "extra_config": {"auth/api-keys": {}}, "backend": [{"host": ["http://users"], "url_pattern": "/users/{id}"}]},
+		{"endpoint": "/files", "output_encoding": "no-op", "backend": [{"host": ["http://files"], "url_pattern": "/files"}]}
+	]
+}`
+
+func TestGenerateEndpointDocs(t *testing.T) {
+	_, output, err := GenerateEndpointDocs(context.Background(), nil, GenerateEndpointDocsInput{
+		Config:     endpointDocsTestConfig,
+		Endpoint:   "/users/{id}",
+		Method:     "get",
+		GatewayURL: "https://api.example.com",
+		Samples:    []string{`{"id": 1, "mail": "a@example.com", "password": "x"}`, `[{"id": 7}]`},
+	})
+	if err != nil {
+		t.Fatalf("GenerateEndpointDocs() error = %v", err)
+	}
+
+	if len(output.Auth) != 1 || output.Auth[0].Type != "jwt" || output.Auth[0].Cookie != "session" {
+		t.Errorf("Expected JWT auth with cookie, got %+v", output.Auth)
+	}
+	codes := map[int]bool{}
+	for _, c := range output.StatusCodes {
+		codes[c.Code] = true
+	}
+	for _, code := range []int{200, 401, 403, 429, 500} {
+		if !codes[code] {
+			t.Errorf("Missing status code %d in %+v", code, output.StatusCodes)
+		}
+	}
+	if len(output.RateLimits) != 2 || !strings.Contains(output.RateLimits[1], "per X-Tenant header") {
+		t.Errorf("Unexpected rate limits %v", output.RateLimits)
+	}
+
+	sample := output.SampleResponse.(map[string]interface{})
+	if _, ok := sample["password"]; ok {
+		t.Error("Denied fields must not appear in the sample")
+	}
+	if sample["email"] != "a@example.com" {
+		t.Errorf("Expected mail mapped to email, got %v", sample)
+	}
+	orders, _ := sample["orders"].(map[string]interface{})
+	if _, ok := orders["collection"]; !ok {
+		t.Errorf("Expected the collection grouped under orders, got %v", sample["orders"])
+	}
+
+	for _, want := range []string{`curl -i -X GET "https://api.example.com/users/<id>"`, "Bearer $TOKEN", "Accepted parameters: fields", "| 429 |", "## Sample response"} {
+		if !strings.Contains(output.Markdown, want) {
+			t.Errorf("Markdown missing %q:\n%s", want, output.Markdown)
+		}
+	}
+}
+
+func TestGenerateEndpointDocs_APIKeysAndNoOp(t *testing.T) {
+	_, output, err := GenerateEndpointDocs(context.Background(), nil, GenerateEndpointDocsInput{Config: endpointDocsTestConfig, Endpoint: "/users/{id}", Method: "DELETE"})
+	if err != nil {
+		t.Fatalf("GenerateEndpointDocs() error = %v", err)
+	}
+	if len(output.Auth) != 1 || output.Auth[0].Header != "X-Key" || !strings.Contains(output.Curl, `-H "X-Key: $API_KEY"`) {
+		t.Errorf("Expected the X-Key API key header, got %+v\n%s", output.Auth, output.Curl)
+	}
+
+	_, output, err = GenerateEndpointDocs(context.Background(), nil, GenerateEndpointDocsInput{Config: endpointDocsTestConfig, Endpoint: "/files"})
+	if err != nil {
+		t.Fatalf("GenerateEndpointDocs() error = %v", err)
+	}
+	if len(output.StatusCodes) != 1 || output.StatusCodes[0].Code != 0 {
+		t.Errorf("no-op endpoints return backend status codes, got %+v", output.StatusCodes)
+	}
+}
+
+func TestGenerateEndpointDocs_Ambiguous(t *testing.T) {
+	_, _, err := GenerateEndpointDocs(context.Background(), nil, GenerateEndpointDocsInput{Config: endpointDocsTestConfig, Endpoint: "/users/{id}"})
+	if err == nil || !strings.Contains(err.Error(), "GET, DELETE") {
+		t.Errorf("Expected an ambiguity error, got %v", err)
+	}
+	if _, _, err := GenerateEndpointDocs(context.Background(), nil, GenerateEndpointDocsInput{Config: endpointDocsTestConfig, Endpoint: "/nope"}); err == nil {
+		t.Error("Expected an error for an unknown endpoint")
+	}
+}
@@ -0,0 +1,305 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/krakend/mcp-server/internal/toolkit"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// endpointNamesSuffix is appended to the configuration name to get its sidecar file:
+// krakend.json → krakend.endpoints.json
+const endpointNamesSuffix = ".endpoints.json"
+
+var (
+	// apiVersionSegment matches version path segments, e.g. v1 or v2.1
+	apiVersionSegment = regexp.MustCompile(`^v\d+(\.\d+)*$`)
+	nameUnsafeChars   = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// EndpointName is the human-readable name, tags and description of an endpoint
+type EndpointName struct {
+	Name        string   `json:"name"`
+	Tags        []string `json:"tags,omitempty"`
+	Description string   `json:"description,omitempty"`
+}
+
+// EndpointNames is the sidecar file of a configuration, keyed by "METHOD /path"
+type EndpointNames struct {
+	Endpoints map[string]EndpointName `json:"endpoints"`
+}
+
+// NameEndpointsInput defines input for name_endpoints tool
+type NameEndpointsInput struct {
+	Config    string `json:"config" jsonschema:"KrakenD configuration as JSON string or file path"`
+	Sidecar   string `json:"sidecar,omitempty" jsonschema:"Path of the sidecar file (optional, defaults to <config>.endpoints.json next to the config file)"`
+	Overwrite bool   `json:"overwrite,omitempty" jsonschema:"Regenerate the names and tags already in the sidecar file (optional, defaults to keeping them)"`
+	Write     bool   `json:"write,omitempty" jsonschema:"Write the sidecar file (optional, defaults to returning its content only)"`
+}
+
+// NamedEndpoint is an endpoint with its name and tags
+type NamedEndpoint struct {
+	Method   string `json:"method"`
+	Endpoint string `json:"endpoint"`
+	EndpointName
+	Source string `json:"source"` // generated or sidecar
+}
+
+// NameEndpointsOutput defines output for name_endpoints tool
+type NameEndpointsOutput struct {
+	Endpoints []NamedEndpoint `json:"endpoints"`
+	Sidecar   string          `json:"sidecar,omitempty"` // Path of the sidecar file
+	Content   string          `json:"content"`           // Sidecar file content
+	Written   bool            `json:"written"`
+	Removed   []string        `json:"removed"` // Sidecar entries of endpoints no longer declared
+	Summary   string          `json:"summary"`
+}
+
+// endpointKey is the key of an endpoint in the sidecar file
+func endpointKey(method, path string) string {
+	return method + " " + path
+}
+
+// endpointNamesPath returns the sidecar file of a configuration file, or "" for inline configurations
+func endpointNamesPath(config string) string {
+	trimmed := strings.TrimSpace(config)
+	if trimmed == "" || strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[") {
+		return ""
+	}
+	return strings.TrimSuffix(config, filepath.Ext(config)) + endpointNamesSuffix
+}
+
+// readEndpointNames reads a sidecar file; a missing file has no names
+func readEndpointNames(path string) (EndpointNames, error) {
+	names := EndpointNames{Endpoints: map[string]EndpointName{}}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return names, nil
+	}
+	if err != nil {
+		return names, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if err := json.Unmarshal(data, &names); err != nil {
+		return names, fmt.Errorf("invalid sidecar file %s: %w", path, err)
+	}
+	if names.Endpoints == nil {
+		names.Endpoints = map[string]EndpointName{}
+	}
+	return names, nil
+}
+
+// loadEndpointNames returns the endpoint names of the sidecar file of a configuration, used by the
+// docs and diagram tools to label endpoints. It is empty without a sidecar file.
+func loadEndpointNames(config string) map[string]EndpointName {
+	path := endpointNamesPath(config)
+	if path == "" {
+		return nil
+	}
+	names, err := readEndpointNames(path)
+	if err != nil {
+		slog.Warn("Could not read endpoint names", "error", err)
+		return nil
+	}
+	return names.Endpoints
+}
+
+// isCollectionName guesses whether a path segment names a collection: users, orders, but not status
+func isCollectionName(segment string) bool {
+	return strings.HasSuffix(segment, "s") && !strings.HasSuffix(segment, "ss") &&
+		!strings.HasSuffix(segment, "us") && !strings.HasSuffix(segment, "is")
+}
+
+// suggestEndpointName derives a name from the path and method of an endpoint: the literal path
+// segments (without api and version prefixes) followed by the action, e.g. GET /v1/users/{id} →
+// users-detail, POST /orders → orders-create, POST /orders/{id}/cancel → orders-cancel
+func suggestEndpointName(method, path string) (name, version string) {
+	var literals []string
+	lastParam := false
+	for i, segment := range strings.Split(strings.Trim(path, "/"), "/") {
+		switch {
+		case segment == "":
+		case strings.HasPrefix(segment, "{") || segment == "*":
+			lastParam = true
+			continue
+		case len(literals) == 0 && version == "" && apiVersionSegment.MatchString(strings.ToLower(segment)):
+			version = strings.ToLower(segment)
+		case len(literals) == 0 && i == 0 && strings.EqualFold(segment, "api"):
+		default:
+			if literal := strings.Trim(nameUnsafeChars.ReplaceAllString(strings.ToLower(segment), "-"), "-"); literal != "" {
+				literals = append(literals, literal)
+			}
+		}
+		lastParam = false
+	}
+	if len(literals) == 0 {
+		literals = []string{"root"}
+	}
+
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	afterParam := len(segments) > 1 && !lastParam && strings.HasPrefix(segments[len(segments)-2], "{")
+	base := strings.Join(literals, "-")
+	var action string
+	switch method {
+	case "GET":
+		switch {
+		case lastParam:
+			action = "detail"
+		case isCollectionName(literals[len(literals)-1]):
+			action = "list"
+		default:
+			action = "get"
+		}
+	case "POST", "PUT", "PATCH":
+		if afterParam && len(literals) > 1 {
+			// An action on a resource: POST /orders/{id}/cancel
+			return strings.Join(literals[:len(literals)-1], "-") + "-" + literals[len(literals)-1], version
+		}
+		action = "update"
+		if method == "POST" && !lastParam {
+			action = "create"
+		}
+	case "DELETE":
+		action = "delete"
+	default:
+		action = strings.ToLower(method)
+	}
+	return base + "-" + action, version
+}
+
+// suggestEndpointTags tags an endpoint with its resource group, API version, whether it reads or
+// writes, requires authentication and aggregates several backends
+func suggestEndpointTags(config, endpoint map[string]interface{}, name, version string) []string {
+	tags := []string{strings.SplitN(name, "-", 2)[0]}
+	if version != "" {
+		tags = append(tags, version)
+	}
+	switch endpointMethod(endpoint) {
+	case "GET", "HEAD", "OPTIONS":
+		tags = append(tags, "read")
+	default:
+		tags = append(tags, "write")
+	}
+	if len(endpointAuth(config, endpoint)) > 0 {
+		tags = append(tags, "authenticated")
+	} else {
+		tags = append(tags, "public")
+	}
+	if backends, _ := endpoint["backend"].([]interface{}); len(backends) > 1 {
+		tags = append(tags, "aggregation")
+	}
+	return tags
+}
+
+// NameEndpoints generates human-readable names and tags for the endpoints of a configuration and
+// stores them in a sidecar file, keeping the names already there
+func NameEndpoints(ctx context.Context, req *mcp.CallToolRequest, input NameEndpointsInput) (*mcp.CallToolResult, NameEndpointsOutput, error) {
+	configContent, err := readConfigContent(input.Config)
+	if err != nil {
+		return nil, NameEndpointsOutput{}, fmt.Errorf("failed to read config: %w", err)
+	}
+	var config map[string]interface{}
+	if err := json.Unmarshal([]byte(configContent), &config); err != nil {
+		return nil, NameEndpointsOutput{}, fmt.Errorf("invalid JSON: %w", err)
+	}
+	sidecarPath := envOrDefault(input.Sidecar, endpointNamesPath(input.Config))
+	if input.Write && sidecarPath == "" {
+		return nil, NameEndpointsOutput{}, fmt.Errorf("write needs a config file path or a sidecar path")
+	}
+	existing := EndpointNames{Endpoints: map[string]EndpointName{}}
+	if sidecarPath != "" {
+		if existing, err = readEndpointNames(sidecarPath); err != nil {
+			return nil, NameEndpointsOutput{}, err
+		}
+	}
+
+	output := NameEndpointsOutput{Endpoints: []NamedEndpoint{}, Sidecar: sidecarPath, Removed: []string{}}
+	names := EndpointNames{Endpoints: map[string]EndpointName{}}
+	// Names kept from the sidecar file are taken before generating the others
+	endpoints, _ := config["endpoints"].([]interface{})
+	used := map[string]bool{}
+	for _, ep := range endpoints {
+		if endpoint, ok := ep.(map[string]interface{}); ok && !input.Overwrite {
+			path, _ := endpoint["endpoint"].(string)
+			used[existing.Endpoints[endpointKey(endpointMethod(endpoint), path)].Name] = true
+		}
+	}
+	for _, ep := range endpoints {
+		endpoint, ok := ep.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		named := NamedEndpoint{Method: endpointMethod(endpoint), Source: "sidecar"}
+		named.Endpoint, _ = endpoint["endpoint"].(string)
+		key := endpointKey(named.Method, named.Endpoint)
+		entry, found := existing.Endpoints[key]
+		if !found || input.Overwrite || entry.Name == "" {
+			name, version := suggestEndpointName(named.Method, named.Endpoint)
+			// Disambiguate names taken by another endpoint
+			candidate := name
+			for i := 2; used[candidate]; i++ {
+				candidate = fmt.Sprintf("%s-%d", name, i)
+			}
+			entry = EndpointName{Name: candidate, Tags: suggestEndpointTags(config, endpoint, name, version), Description: entry.Description}
+			named.Source = "generated"
+		}
+		used[entry.Name] = true
+		named.EndpointName = entry
+		names.Endpoints[key] = entry
+		output.Endpoints = append(output.Endpoints, named)
+	}
+	for key := range existing.Endpoints {
+		if _, ok := names.Endpoints[key]; !ok {
+			output.Removed = append(output.Removed, key)
+		}
+	}
+	sort.Strings(output.Removed)
+
+	data, err := json.MarshalIndent(names, "", "  ")
+	if err != nil {
+		return nil, NameEndpointsOutput{}, fmt.Errorf("failed to encode sidecar file: %w", err)
+	}
+	output.Content = string(data) + "\n"
+	if input.Write {
+		if err := os.WriteFile(sidecarPath, []byte(output.Content), 0o644); err != nil {
+			return nil, NameEndpointsOutput{}, fmt.Errorf("failed to write %s: %w", sidecarPath, err)
+		}
+		output.Written = true
+	}
+
+	generated := 0
+	for _, named := range output.Endpoints {
+		if named.Source == "generated" {
+			generated++
+		}
+	}
+	output.Summary = fmt.Sprintf("%d endpoint(s) named, %d generated and %d kept from the sidecar file", len(output.Endpoints), generated, len(output.Endpoints)-generated)
+	if len(output.Removed) > 0 {
+		output.Summary += fmt.Sprintf(", %d stale endpoint(s) dropped from the sidecar file", len(output.Removed))
+	}
+	switch {
+	case output.Written:
+		output.Summary += ": written to " + sidecarPath
+	case generated > 0 || len(output.Removed) > 0:
+		output.Summary += ": call again with write to store them"
+	}
+	return nil, output, nil
+}
+
+// RegisterEndpointNameTools registers the endpoint naming tool
+func RegisterEndpointNameTools(server *mcp.Server) {
+	toolkit.AddTool(server,
+		&mcp.Tool{
+			Name:        "name_endpoints",
+			Description: "Generate human-readable names (users-list, users-detail, orders-create, orders-cancel) and tags (resource, version, read/write, public/authenticated, aggregation) for the endpoints of a configuration from their paths and methods. Stores them in a <config>.endpoints.json sidecar file, keeping names and descriptions edited by hand, that generate_endpoint_docs and map_dependencies use to label endpoints.",
+		},
+		NameEndpoints,
+	)
+}
@@ -0,0 +1,116 @@
+package tools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSuggestEndpointName(t *testing.T) {
+	tests := []struct {
+		method, path string
+		want         string
+		version      string
+	}{
+		{"GET", "/users", "users-list", ""},
+		{"GET", "/v1/users/{id}", "users-detail", "v1"},
+		{"GET", "/api/v2/users/{id}/orders", "users-orders-list", "v2"},
+		{"GET", "/status", "status-get", ""},
+		{"POST", "/orders", "orders-create", ""},
+		{"PUT", "/orders/{id}", "orders-update", ""},
+		{"POST", "/orders/{id}/cancel", "orders-cancel", ""},
+		{"DELETE", "/users/{id}", "users-delete", ""},
+		{"GET", "/", "root-get", ""},
+		{"GET", "/user_profiles/{id}", "user-profiles-detail", ""},
+	}
+	for _, tt := range tests {
+		name, version := suggestEndpointName(tt.method, tt.path)
+		if name != tt.want || version != tt.version {
+			t.Errorf("suggestEndpointName(%s, %s) = %s, %s; want %s, %s", tt.method, tt.path, name, version, tt.want, tt.version)
+		}
+	}
+}
+
+func TestNameEndpoints(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "krakend.json")
+	config := `{"version": 3, "endpoints": [
+		{"endpoint": "/users", "backend": [{"url_pattern": "/users"}]},
+		{"endpoint": "/users/{id}", "extra_config": {"auth/validator": {"alg": "RS256"}}, "backend": [{"url_pattern": "/u"}, {"url_pattern": "/p"}]},
+		{"endpoint": "/users", "method": "POST", "backend": [{"url_pattern": "/users"}]}
+	]}`
+	if err := os.WriteFile(configPath, []byte(config), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Keep a name edited by hand and drop removed endpoints
+	sidecar := filepath.Join(dir, "krakend.endpoints.json")
+	existing := `{"endpoints": {"GET /users": {"name": "users-all", "description": "All users"}, "GET /gone": {"name": "gone-get"}}}`
+	if err := os.WriteFile(sidecar, []byte(existing), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, output, err := NameEndpoints(context.Background(), nil, NameEndpointsInput{Config: configPath, Write: true})
+	if err != nil {
+		t.Fatalf("NameEndpoints() error = %v", err)
+	}
+	if output.Sidecar != sidecar || !output.Written {
+		t.Errorf("Expected the sidecar file to be written, got %+v", output)
+	}
+	if len(output.Endpoints) != 3 {
+		t.Fatalf("Expected 3 endpoints, got %+v", output.Endpoints)
+	}
+	if e := output.Endpoints[0]; e.Name != "users-all" || e.Source != "sidecar" || e.Description != "All users" {
+		t.Errorf("Expected the name from the sidecar file, got %+v", e)
+	}
+	if e := output.Endpoints[1]; e.Name != "users-detail" || strings.Join(e.Tags, ",") != "users,read,authenticated,aggregation" {
+		t.Errorf("Unexpected generated name, got %+v", e)
+	}
+	if e := output.Endpoints[2]; e.Name != "users-create" || strings.Join(e.Tags, ",") != "users,write,public" {
+		t.Errorf("Unexpected generated name, got %+v", e)
+	}
+	if len(output.Removed) != 1 || output.Removed[0] != "GET /gone" {
+		t.Errorf("Expected the stale entry to be removed, got %v", output.Removed)
+	}
+
+	names := loadEndpointNames(configPath)
+	if names["GET /users/{id}"].Name != "users-detail" || names["GET /users"].Name != "users-all" || len(names) != 3 {
+		t.Errorf("Unexpected sidecar file content: %+v", names)
+	}
+
+	// The docs and dependency tools label endpoints with their names
+	_, docs, err := GenerateEndpointDocs(context.Background(), nil, GenerateEndpointDocsInput{Config: configPath, Endpoint: "/users/{id}"})
+	if err != nil {
+		t.Fatalf("GenerateEndpointDocs() error = %v", err)
+	}
+	if docs.Name != "users-detail" || !strings.HasPrefix(docs.Markdown, "# users-detail\n\n`GET /users/{id}`") {
+		t.Errorf("Expected the endpoint name in the docs, got:\n%s", docs.Markdown)
+	}
+	_, graph, err := MapDependencies(context.Background(), nil, MapDependenciesInput{Configs: []string{configPath}})
+	if err != nil {
+		t.Fatalf("MapDependencies() error = %v", err)
+	}
+	if !strings.Contains(graph.Mermaid, `"users-all<br/>GET /users"`) || !strings.Contains(graph.DOT, `"users-create\nPOST /users"`) {
+		t.Errorf("Expected endpoint names in the diagrams:\n%s\n%s", graph.Mermaid, graph.DOT)
+	}
+}
+
+func TestNameEndpoints_InlineConfig(t *testing.T) {
+	config := `{"version": 3, "endpoints": [{"endpoint": "/users/{id}"}, {"endpoint": "/users/{name}"}]}`
+	_, output, err := NameEndpoints(context.Background(), nil, NameEndpointsInput{Config: config})
+	if err != nil {
+		t.Fatalf("NameEndpoints() error = %v", err)
+	}
+	if output.Endpoints[0].Name != "users-detail" || output.Endpoints[1].Name != "users-detail-2" {
+		t.Errorf("Expected duplicated names to be disambiguated, got %+v", output.Endpoints)
+	}
+	if output.Sidecar != "" || output.Written || !strings.Contains(output.Content, `"GET /users/{name}"`) {
+		t.Errorf("Expected the sidecar content only for inline configs, got %+v", output)
+	}
+
+	if _, _, err := NameEndpoints(context.Background(), nil, NameEndpointsInput{Config: config, Write: true}); err == nil {
+		t.Error("Expected an error writing the sidecar file of an inline config")
+	}
+}
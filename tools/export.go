@@ -0,0 +1,294 @@
+package tools
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/krakend/mcp-server/internal/features"
+	"github.com/krakend/mcp-server/internal/parquet"
+	"github.com/krakend/mcp-server/internal/toolkit"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// exportDatasets are the datasets export_results can write
+var exportDatasets = []string{"metrics", "validation", "audit"}
+
+// exportValidator and exportAuditor run the validation and the audit of every exported
+// configuration. They can be replaced in tests.
+var (
+	exportValidator = ValidateConfig
+	exportAuditor   = AuditSecurity
+)
+
+// ExportResultsInput defines input for export_results tool
+type ExportResultsInput struct {
+	Configs   []string `json:"configs,omitempty" jsonschema:"KrakenD configurations (JSON strings or file paths)"`
+	Directory string   `json:"directory,omitempty" jsonschema:"Directory containing KrakenD configurations, searched recursively (optional, in addition to configs)"`
+	Pattern   string   `json:"pattern,omitempty" jsonschema:"File name glob used to select configurations in the directory (optional, defaults to *.json)"`
+	OutputDir string   `json:"output_dir" jsonschema:"Directory where the dataset files are written (created when missing)"`
+	Datasets  []string `json:"datasets,omitempty" jsonschema:"Datasets to export: metrics, validation and audit (optional, defaults to all)"`
+	Format    string   `json:"format,omitempty" jsonschema:"File format: csv, jsonl or parquet (optional, defaults to csv)"`
+}
+
+// ExportedFile is a dataset file written by export_results
+type ExportedFile struct {
+	Dataset string `json:"dataset"`
+	Path    string `json:"path"`
+	Rows    int    `json:"rows"`
+}
+
+// ExportResultsOutput defines output for export_results tool
+type ExportResultsOutput struct {
+	Configs int             `json:"configs"`
+	Files   []ExportedFile  `json:"files"`
+	Skipped []SkippedConfig `json:"skipped,omitempty"`
+	Summary string          `json:"summary"`
+}
+
+// exportTable is a dataset file: its columns and rows
+type exportTable struct {
+	dataset string
+	name    string
+	columns []string
+	rows    [][]string
+}
+
+// add appends a row, prefixed with the export time and the configuration name
+func (t *exportTable) add(exportedAt, config string, values ...interface{}) {
+	row := []string{exportedAt, config}
+	for _, v := range values {
+		row = append(row, fmt.Sprint(v))
+	}
+	t.rows = append(t.rows, row)
+}
+
+// write writes the table as CSV, JSON lines or Parquet
+func (t *exportTable) write(path, format string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	switch format {
+	case "parquet":
+		if err := parquet.Write(f, t.columns, t.rows); err != nil {
+			return err
+		}
+		return f.Close()
+	case "jsonl":
+		encoder := json.NewEncoder(f)
+		for _, row := range t.rows {
+			record := make(map[string]string, len(row))
+			for i, column := range t.columns {
+				record[column] = row[i]
+			}
+			if err := encoder.Encode(record); err != nil {
+				return err
+			}
+		}
+		return f.Close()
+	}
+	w := csv.NewWriter(f)
+	if err := w.Write(t.columns); err != nil {
+		return err
+	}
+	if err := w.WriteAll(t.rows); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// newExportTable creates a dataset file with the common columns
+func newExportTable(dataset, name string, columns ...string) *exportTable {
+	return &exportTable{dataset: dataset, name: name, columns: append([]string{"exported_at", "config"}, columns...)}
+}
+
+// exportConfigSources lists the configurations to export: the given ones, then those found in the directory
+func exportConfigSources(input ExportResultsInput) ([]string, []SkippedConfig, error) {
+	sources := slices.Clone(input.Configs)
+	if input.Directory == "" {
+		return sources, nil, nil
+	}
+	pattern := envOrDefault(input.Pattern, "*.json")
+	if _, err := filepath.Match(pattern, ""); err != nil {
+		return nil, nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+	}
+	var skipped []SkippedConfig
+	err := filepath.WalkDir(input.Directory, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			skipped = append(skipped, SkippedConfig{Path: path, Reason: err.Error()})
+			return nil
+		}
+		if d.IsDir() {
+			if path != input.Directory && (strings.HasPrefix(d.Name(), ".") || d.Name() == "node_modules") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if ok, _ := filepath.Match(pattern, d.Name()); ok {
+			sources = append(sources, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to walk %s: %w", input.Directory, err)
+	}
+	return sources, skipped, nil
+}
+
+// configMetrics counts the endpoints, backends, hosts and namespaces of a configuration
+func configMetrics(config map[string]interface{}, eeSet map[string]struct{}) (backends, hosts, namespaces, eeFeatures int) {
+	seen := map[string]bool{}
+	endpoints, _ := config["endpoints"].([]interface{})
+	for _, ep := range endpoints {
+		endpoint, _ := ep.(map[string]interface{})
+		list, _ := endpoint["backend"].([]interface{})
+		backends += len(list)
+		for _, b := range list {
+			backend, _ := b.(map[string]interface{})
+			hostList, _ := backend["host"].([]interface{})
+			for _, h := range hostList {
+				seen[fmt.Sprint(h)] = true
+			}
+		}
+	}
+	found := features.FindNamespacesInConfig(config)
+	for _, ns := range found {
+		if _, ok := eeSet[ns]; ok {
+			eeFeatures++
+		}
+	}
+	return backends, len(seen), len(found), eeFeatures
+}
+
+// ExportResults validates, audits and measures one or many configurations and writes the
+// results as CSV, JSON lines or Parquet files for spreadsheet or notebook analysis
+func ExportResults(ctx context.Context, req *mcp.CallToolRequest, input ExportResultsInput) (*mcp.CallToolResult, ExportResultsOutput, error) {
+	if input.OutputDir == "" {
+		return nil, ExportResultsOutput{}, fmt.Errorf("output_dir is required")
+	}
+	format := strings.ToLower(envOrDefault(input.Format, "csv"))
+	switch format {
+	case "csv", "jsonl", "parquet":
+	default:
+		return nil, ExportResultsOutput{}, fmt.Errorf("invalid format %q (expected csv, jsonl or parquet)", input.Format)
+	}
+	datasets := input.Datasets
+	if len(datasets) == 0 {
+		datasets = exportDatasets
+	}
+	for _, dataset := range datasets {
+		if !slices.Contains(exportDatasets, dataset) {
+			return nil, ExportResultsOutput{}, fmt.Errorf("unknown dataset %q (expected %s)", dataset, strings.Join(exportDatasets, ", "))
+		}
+	}
+	sources, skipped, err := exportConfigSources(input)
+	if err != nil {
+		return nil, ExportResultsOutput{}, err
+	}
+	if len(sources) == 0 {
+		return nil, ExportResultsOutput{}, fmt.Errorf("no configuration to export: pass configs or a directory containing them")
+	}
+
+	exportedAt := time.Now().UTC().Format(time.RFC3339)
+	metrics := newExportTable("metrics", "metrics", "version", "endpoints", "endpoints_without_auth", "backends", "hosts", "namespaces", "ee_features")
+	validations := newExportTable("validation", "validation", "valid", "method", "errors", "warnings", "summary")
+	findings := newExportTable("validation", "validation_findings", "kind", "code", "level", "path", "message")
+	audits := newExportTable("audit", "audit", "method", "score", "critical", "high", "medium", "low", "info")
+	issues := newExportTable("audit", "audit_issues", "rule", "severity", "category", "title", "location")
+	var eeSet map[string]struct{}
+	if slices.Contains(datasets, "metrics") {
+		eeSet = eeOnlyNamespaces()
+	}
+
+	output := ExportResultsOutput{Files: []ExportedFile{}, Skipped: skipped}
+	for i, source := range sources {
+		if err := ctx.Err(); err != nil {
+			return nil, ExportResultsOutput{}, err
+		}
+		name := dependencyConfigName(source, i)
+		configContent, err := readConfigContent(source)
+		if err != nil {
+			output.Skipped = append(output.Skipped, SkippedConfig{Path: name, Reason: err.Error()})
+			continue
+		}
+		var config map[string]interface{}
+		if err := json.Unmarshal([]byte(configContent), &config); err != nil {
+			output.Skipped = append(output.Skipped, SkippedConfig{Path: name, Reason: "invalid JSON: " + err.Error()})
+			continue
+		}
+		output.Configs++
+
+		if slices.Contains(datasets, "metrics") {
+			endpoints, withoutAuth := countEndpointsWithoutAuth(config)
+			backends, hosts, namespaces, eeFeatures := configMetrics(config, eeSet)
+			metrics.add(exportedAt, name, ExtractVersionFromConfig(configContent), endpoints, withoutAuth, backends, hosts, namespaces, eeFeatures)
+		}
+		if slices.Contains(datasets, "validation") {
+			_, result, err := exportValidator(ctx, req, ValidateConfigInput{Config: source})
+			if err != nil {
+				validations.add(exportedAt, name, false, "error", 0, 0, err.Error())
+			} else {
+				validations.add(exportedAt, name, result.Valid, result.Method, len(result.Errors), len(result.Warnings), result.Summary)
+				for _, e := range result.Errors {
+					findings.add(exportedAt, name, "error", e.Code, "error", e.Path, e.Message)
+				}
+				for _, w := range result.Warnings {
+					findings.add(exportedAt, name, "warning", w.Code, w.Level, w.Path, w.Message)
+				}
+			}
+		}
+		if slices.Contains(datasets, "audit") {
+			_, result, err := exportAuditor(ctx, req, AuditSecurityInput{Config: source})
+			if err != nil {
+				audits.add(exportedAt, name, "error", "", 0, 0, 0, 0, 0)
+			} else {
+				severities := map[string]int{}
+				for _, issue := range result.Issues {
+					severities[issue.Severity]++
+					issues.add(exportedAt, name, issue.Rule, issue.Severity, issue.Category, issue.Title, issue.Location)
+				}
+				audits.add(exportedAt, name, result.Method, result.Score, severities["critical"], severities["high"], severities["medium"], severities["low"], severities["info"])
+			}
+		}
+	}
+
+	if err := os.MkdirAll(input.OutputDir, 0o755); err != nil {
+		return nil, ExportResultsOutput{}, fmt.Errorf("failed to create %s: %w", input.OutputDir, err)
+	}
+	for _, table := range []*exportTable{metrics, validations, findings, audits, issues} {
+		if !slices.Contains(datasets, table.dataset) {
+			continue
+		}
+		path := filepath.Join(input.OutputDir, table.name+"."+format)
+		if err := table.write(path, format); err != nil {
+			return nil, ExportResultsOutput{}, fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		output.Files = append(output.Files, ExportedFile{Dataset: table.dataset, Path: path, Rows: len(table.rows)})
+	}
+
+	output.Summary = fmt.Sprintf("Exported %d configuration(s) to %d %s file(s) in %s", output.Configs, len(output.Files), format, input.OutputDir)
+	if len(output.Skipped) > 0 {
+		output.Summary += fmt.Sprintf(" (%d skipped)", len(output.Skipped))
+	}
+	return nil, output, nil
+}
+
+// RegisterExportTools registers the results export tool
+func RegisterExportTools(server *mcp.Server) {
+	toolkit.AddTool(server,
+		&mcp.Tool{
+			Name:        "export_results",
+			Description: "Validate, audit and measure one or many KrakenD configurations (or a directory of them) and write the results as CSV, JSON lines or Parquet files: per-config metrics, validation results and findings, audit scores and issues. Every row carries the export time and the config, so exports can be appended and analyzed over time in spreadsheets or notebooks.",
+		},
+		ExportResults,
+	)
+}
@@ -0,0 +1,106 @@
+package tools
+
+import (
+	"context"
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// useExportRunners replaces the validation and audit run by export_results
+func useExportRunners(t *testing.T) {
+	validator, auditor := exportValidator, exportAuditor
+	t.Cleanup(func() { exportValidator, exportAuditor = validator, auditor })
+	exportValidator = func(ctx context.Context, req *mcp.CallToolRequest, input ValidateConfigInput) (*mcp.CallToolResult, ValidateConfigOutput, error) {
+		var output ValidateConfigOutput
+		output.Valid, output.Method, output.Summary = true, "schema", "Configuration is valid"
+		output.Warnings = []ValidationWarning{{Code: "SCHEMA_ONLY", Level: "info", Path: "$", Message: "Validated with the schema, only"}}
+		return nil, output, nil
+	}
+	exportAuditor = func(ctx context.Context, req *mcp.CallToolRequest, input AuditSecurityInput) (*mcp.CallToolResult, AuditSecurityOutput, error) {
+		return nil, AuditSecurityOutput{Method: "basic", Score: 80, Issues: []SecurityIssue{
+			{Rule: "endpoint-no-auth", Severity: "high", Category: "authentication", Title: "Endpoint without authentication", Location: "$.endpoints[0]"},
+		}}, nil
+	}
+}
+
+func TestExportResults(t *testing.T) {
+	setMockFeatureFetcher(t, minimalFeatureYAML)
+	useExportRunners(t)
+	configs := t.TempDir()
+	config := `{"version": 3, "endpoints": [{"endpoint": "/a", "backend": [{"url_pattern": "/a", "host": ["http://a", "http://b"]}]}]}`
+	if err := os.WriteFile(filepath.Join(configs, "a.json"), []byte(config), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(configs, "broken.json"), []byte("{"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	out := filepath.Join(t.TempDir(), "export")
+
+	_, output, err := ExportResults(context.Background(), nil, ExportResultsInput{Configs: []string{config}, Directory: configs, OutputDir: out})
+	if err != nil {
+		t.Fatalf("ExportResults() error = %v", err)
+	}
+	if output.Configs != 2 || len(output.Skipped) != 1 || len(output.Files) != 5 {
+		t.Fatalf("Expected 2 configs, 1 skipped and 5 files, got %+v", output)
+	}
+
+	data, err := os.ReadFile(filepath.Join(out, "metrics.csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(strings.NewReader(string(data))).ReadAll()
+	if err != nil {
+		t.Fatalf("Invalid CSV: %v", err)
+	}
+	if len(records) != 3 || strings.Join(records[0], ",") != "exported_at,config,version,endpoints,endpoints_without_auth,backends,hosts,namespaces,ee_features" {
+		t.Fatalf("Unexpected metrics: %v", records)
+	}
+	if records[1][1] != "config-1" || records[1][3] != "1" || records[1][6] != "2" {
+		t.Errorf("Unexpected metrics row: %v", records[1])
+	}
+	for _, file := range output.Files {
+		if file.Path == filepath.Join(out, "audit_issues.csv") && file.Rows != 2 {
+			t.Errorf("Expected one audit issue per config, got %+v", file)
+		}
+	}
+}
+
+func TestExportResults_Formats(t *testing.T) {
+	useExportRunners(t)
+	out := t.TempDir()
+	config := `{"version": 3}`
+	_, output, err := ExportResults(context.Background(), nil, ExportResultsInput{Configs: []string{config}, OutputDir: out, Datasets: []string{"validation"}, Format: "jsonl"})
+	if err != nil {
+		t.Fatalf("ExportResults() error = %v", err)
+	}
+	if len(output.Files) != 2 {
+		t.Fatalf("Expected the validation files only, got %+v", output.Files)
+	}
+	data, err := os.ReadFile(filepath.Join(out, "validation_findings.jsonl"))
+	if err != nil || !strings.Contains(string(data), `"code":"SCHEMA_ONLY"`) {
+		t.Errorf("Expected the finding as a JSON line, got %s (error %v)", data, err)
+	}
+
+	_, output, err = ExportResults(context.Background(), nil, ExportResultsInput{Configs: []string{config}, OutputDir: out, Datasets: []string{"metrics"}, Format: "parquet"})
+	if err != nil {
+		t.Fatalf("ExportResults() error = %v", err)
+	}
+	if len(output.Files) != 1 || output.Files[0].Path != filepath.Join(out, "metrics.parquet") || output.Files[0].Rows != 1 {
+		t.Fatalf("Expected the metrics as a Parquet file, got %+v", output.Files)
+	}
+	data, err = os.ReadFile(output.Files[0].Path)
+	if err != nil || !strings.HasPrefix(string(data), "PAR1") || !strings.HasSuffix(string(data), "PAR1") || !strings.Contains(string(data), "endpoints_without_auth") {
+		t.Errorf("Expected a Parquet file with the metrics columns, got %q (error %v)", data, err)
+	}
+	if _, _, err := ExportResults(context.Background(), nil, ExportResultsInput{Configs: []string{config}, OutputDir: out, Format: "xlsx"}); err == nil {
+		t.Error("Expected an unknown format to be rejected")
+	}
+	if _, _, err := ExportResults(context.Background(), nil, ExportResultsInput{Configs: []string{config}, OutputDir: out, Datasets: []string{"latency"}}); err == nil {
+		t.Error("Expected an unknown dataset to be rejected")
+	}
+}
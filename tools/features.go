@@ -4,22 +4,27 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"slices"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/krakend/mcp-server/internal/features"
+	"github.com/krakend/mcp-server/internal/toolkit"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
 const (
 	remoteFeatureMatrixURL = "https://www.krakend.io/mcp-feature-matrix.yaml"
 	featureMatrixFile      = "features/mcp-feature-matrix.yaml"
-	featureCacheTTL        = 7 * 24 * time.Hour
 )
 
+var featureCacheTTL = DefaultCacheTTL
+
 // Re-export types from internal/features for backward compatibility
 type (
 	Feature        = features.Feature
@@ -30,6 +35,9 @@ type (
 var (
 	featureCatalog *features.FeatureCatalog
 	editionMatrix  *features.EditionMatrix
+
+	// featureDataMu serializes the loads of the feature data, started lazily by concurrent sessions
+	featureDataMu sync.Mutex
 )
 
 // DetectEnterpriseFeatures checks if config uses EE-only features
@@ -46,11 +54,26 @@ func DetectEnterpriseFeatures(configJSON string) bool {
 	return features.DetectEnterpriseFeatures(configJSON, editionMatrix.EEOnlyFeatures)
 }
 
+// eeOnlyNamespace reports whether the edition matrix lists a namespace as Enterprise only,
+// falling back to the common EE namespaces when the matrix is unavailable
+func eeOnlyNamespace(namespace string) bool {
+	if editionMatrix == nil {
+		_ = LoadFeatureData()
+	}
+	eeOnly := features.CommonEEFeatures
+	if editionMatrix != nil && len(editionMatrix.EEOnlyFeatures) > 0 {
+		eeOnly = editionMatrix.EEOnlyFeatures
+	}
+	return slices.Contains(eeOnly, namespace)
+}
+
 // LoadFeatureData loads the feature catalog and edition matrix using an offline-first strategy:
 //  1. Use local cached file if fresh (<7 days old)
 //  2. Re-download if stale; on failure fall back to existing local file
 //  3. If no local file, download; on failure use embedded fallback
 func LoadFeatureData() error {
+	featureDataMu.Lock()
+	defer featureDataMu.Unlock()
 	localPath := filepath.Join(dataDir, featureMatrixFile)
 
 	if info, err := os.Stat(localPath); err == nil {
@@ -58,16 +81,16 @@ func LoadFeatureData() error {
 		if age <= featureCacheTTL {
 			return loadFeatureMatrixFromPath(localPath)
 		}
-		log.Printf("Feature matrix is %v old (>7 days), refreshing...", age.Round(24*time.Hour))
+		slog.Info("Feature matrix is stale, refreshing", "age_days", int(age.Hours()/24))
 		if err := downloadFeatureMatrix(localPath); err != nil {
-			log.Printf("Warning: could not refresh feature matrix: %v — using existing local file", err)
+			slog.Warn("Could not refresh feature matrix, using existing local file", "error", err)
 		}
 		return loadFeatureMatrixFromPath(localPath)
 	}
 
 	// No local file: try download first, fall back to embedded.
 	if err := downloadFeatureMatrix(localPath); err != nil {
-		log.Printf("Warning: could not download feature matrix: %v — using embedded fallback", err)
+		slog.Warn("Could not download feature matrix, using embedded fallback", "error", err)
 		return loadEmbeddedFeatureMatrix()
 	}
 	return loadFeatureMatrixFromPath(localPath)
@@ -175,25 +198,35 @@ type CheckEditionCompatibilityInput struct {
 	Config string `json:"config" jsonschema:"KrakenD configuration as JSON string"`
 }
 
+// Confidence levels of check_edition_compatibility findings
+const (
+	ConfidenceHigh = "high" // The edition matrix lists the namespace
+	ConfidenceLow  = "low"  // Unknown namespace: its edition is not known
+)
+
 // CheckEditionCompatibilityOutput defines output for check_edition_compatibility tool
 type CheckEditionCompatibilityOutput struct {
-	Edition        string                 `json:"edition"`       // "ce", "ee", or "mixed"
-	EEFeatures     []string               `json:"ee_features"`   // List of EE-only features found
-	CECompatible   bool                   `json:"ce_compatible"` // True if config works with CE
-	RequiresEE     bool                   `json:"requires_ee"`   // True if config requires EE
-	FeatureDetails []FeatureCompatibility `json:"feature_details"`
-	Message        string                 `json:"message"`
+	Edition           string                 `json:"edition"`            // "ce", "ee", or "uncertain" when unknown namespaces may require EE
+	EEFeatures        []string               `json:"ee_features"`        // List of EE-only features found
+	CECompatible      bool                   `json:"ce_compatible"`      // True if config is known to work with CE
+	RequiresEE        bool                   `json:"requires_ee"`        // True if config requires EE
+	Confidence        string                 `json:"confidence"`         // "high", or "low" when the verdict depends on unknown namespaces
+	UnknownNamespaces []string               `json:"unknown_namespaces"` // Namespaces missing from the edition matrix
+	FeatureDetails    []FeatureCompatibility `json:"feature_details"`
+	Message           string                 `json:"message"`
 }
 
 // FeatureCompatibility represents compatibility info for a feature
 type FeatureCompatibility struct {
-	Namespace string `json:"namespace"`
-	Name      string `json:"name"`
-	Edition   string `json:"edition"`
-	Available bool   `json:"available"`
+	Namespace  string `json:"namespace"`
+	Name       string `json:"name,omitempty"`
+	Edition    string `json:"edition"`    // "ce", "ee", or "unknown"
+	Available  bool   `json:"available"`  // Known to be available in CE
+	Confidence string `json:"confidence"` // "high" or "low"
 }
 
-// CheckEditionCompatibility detects which edition is required for a config
+// CheckEditionCompatibility detects which edition is required for a config. Namespaces missing
+// from the edition matrix are never assumed to be CE: they make a CE verdict uncertain.
 func CheckEditionCompatibility(ctx context.Context, req *mcp.CallToolRequest, input CheckEditionCompatibilityInput) (*mcp.CallToolResult, CheckEditionCompatibilityOutput, error) {
 	if editionMatrix == nil || featureCatalog == nil {
 		if err := LoadFeatureData(); err != nil {
@@ -209,53 +242,62 @@ func CheckEditionCompatibility(ctx context.Context, req *mcp.CallToolRequest, in
 
 	// Find all namespaces used in config
 	namespaces := features.FindNamespacesInConfig(config)
+	sort.Strings(namespaces)
 
 	// Initialize as empty slices (not nil) to ensure JSON marshals as [] instead of null
 	eeFeatures := []string{}
+	unknown := []string{}
 	featureDetails := []FeatureCompatibility{}
-	requiresEE := false
 
 	// Check each namespace against edition matrix
 	for _, ns := range namespaces {
-		isEEOnly := false
-		for _, eeNs := range editionMatrix.EEOnlyFeatures {
-			if ns == eeNs {
-				isEEOnly = true
-				requiresEE = true
-				eeFeatures = append(eeFeatures, ns)
-				break
-			}
+		detail := FeatureCompatibility{Namespace: ns, Confidence: ConfidenceHigh}
+		switch {
+		case slices.Contains(editionMatrix.EEOnlyFeatures, ns):
+			detail.Edition = "ee"
+			eeFeatures = append(eeFeatures, ns)
+		case slices.Contains(editionMatrix.CEFeatures, ns):
+			detail.Edition = "ce"
+			detail.Available = true
+		default:
+			detail.Edition = "unknown"
+			detail.Confidence = ConfidenceLow
+			unknown = append(unknown, ns)
 		}
 
 		// Find feature details
 		for _, feature := range featureCatalog.Features {
 			if feature.Namespace == ns {
-				featureDetails = append(featureDetails, FeatureCompatibility{
-					Namespace: ns,
-					Name:      feature.Name,
-					Edition:   feature.Edition,
-					Available: !isEEOnly,
-				})
+				detail.Name = feature.Name
 				break
 			}
 		}
+		featureDetails = append(featureDetails, detail)
 	}
 
-	edition := "ce"
-	message := "Configuration is compatible with Community Edition"
-	if requiresEE {
-		edition = "ee"
-		message = fmt.Sprintf("Configuration requires Enterprise Edition (uses %d EE-only feature(s))", len(eeFeatures))
+	output := CheckEditionCompatibilityOutput{
+		Edition:           "ce",
+		EEFeatures:        eeFeatures,
+		CECompatible:      true,
+		Confidence:        ConfidenceHigh,
+		UnknownNamespaces: unknown,
+		FeatureDetails:    featureDetails,
+		Message:           "Configuration is compatible with Community Edition",
 	}
-
-	return nil, CheckEditionCompatibilityOutput{
-		Edition:        edition,
-		EEFeatures:     eeFeatures,
-		CECompatible:   !requiresEE,
-		RequiresEE:     requiresEE,
-		FeatureDetails: featureDetails,
-		Message:        message,
-	}, nil
+	switch {
+	case len(eeFeatures) > 0:
+		// Known EE-only features settle it, whatever the unknown namespaces are
+		output.Edition = "ee"
+		output.CECompatible = false
+		output.RequiresEE = true
+		output.Message = fmt.Sprintf("Configuration requires Enterprise Edition (uses %d EE-only feature(s))", len(eeFeatures))
+	case len(unknown) > 0:
+		output.Edition = "uncertain"
+		output.CECompatible = false
+		output.Confidence = ConfidenceLow
+		output.Message = fmt.Sprintf("Compatibility with Community Edition is uncertain: %d namespace(s) are not in the edition matrix (%s). Check their documentation before asserting the configuration works with CE.", len(unknown), strings.Join(unknown, ", "))
+	}
+	return nil, output, nil
 }
 
 // RegisterFeatureTools registers all feature detection tools
@@ -266,7 +308,7 @@ func RegisterFeatureTools(server *mcp.Server) error {
 	}
 
 	// Tool 1: list_features
-	mcp.AddTool(server,
+	toolkit.AddTool(server,
 		&mcp.Tool{
 			Name:        "list_features",
 			Description: "List KrakenD features with name, namespace, edition (ce/ee), category, and description. Optionally filter by edition (ee=true for Enterprise-only) or search by keyword across name and description.",
@@ -275,10 +317,10 @@ func RegisterFeatureTools(server *mcp.Server) error {
 	)
 
 	// Tool 2: check_edition_compatibility
-	mcp.AddTool(server,
+	toolkit.AddTool(server,
 		&mcp.Tool{
 			Name:        "check_edition_compatibility",
-			Description: "Detect which KrakenD edition (CE or EE) is required for a configuration by analyzing which features are used",
+			Description: "Detect which KrakenD edition (CE or EE) is required for a configuration by analyzing which features are used. Namespaces missing from the edition matrix are listed in unknown_namespaces and make a CE verdict uncertain (low confidence) rather than compatible",
 		},
 		CheckEditionCompatibility,
 	)
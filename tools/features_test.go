@@ -353,3 +353,58 @@ func TestListFeatures_QueryFilter_NoMatch(t *testing.T) {
 		t.Error("Features slice should not be nil")
 	}
 }
+
+func callCheckEditionCompatibility(t *testing.T, config string) CheckEditionCompatibilityOutput {
+	t.Helper()
+	_, output, err := CheckEditionCompatibility(context.Background(), &mcp.CallToolRequest{}, CheckEditionCompatibilityInput{Config: config})
+	if err != nil {
+		t.Fatalf("CheckEditionCompatibility returned unexpected error: %v", err)
+	}
+	return output
+}
+
+func TestCheckEditionCompatibility_KnownCE(t *testing.T) {
+	setMockFeatureFetcher(t, minimalFeatureYAML)
+
+	output := callCheckEditionCompatibility(t, `{"extra_config": {"security/cors": {}}}`)
+
+	if output.Edition != "ce" || !output.CECompatible || output.Confidence != ConfidenceHigh {
+		t.Errorf("Expected a high confidence CE verdict, got %+v", output)
+	}
+	if len(output.FeatureDetails) != 1 || output.FeatureDetails[0].Name != "CORS" || !output.FeatureDetails[0].Available {
+		t.Errorf("Unexpected feature details %+v", output.FeatureDetails)
+	}
+}
+
+func TestCheckEditionCompatibility_UnknownNamespace(t *testing.T) {
+	setMockFeatureFetcher(t, minimalFeatureYAML)
+
+	output := callCheckEditionCompatibility(t, `{"extra_config": {"security/cors": {}, "ai/llm-gateway": {}}}`)
+
+	if output.Edition != "uncertain" || output.CECompatible || output.RequiresEE || output.Confidence != ConfidenceLow {
+		t.Errorf("Expected an uncertain verdict, got %+v", output)
+	}
+	if len(output.UnknownNamespaces) != 1 || output.UnknownNamespaces[0] != "ai/llm-gateway" {
+		t.Errorf("Expected ai/llm-gateway to be unknown, got %v", output.UnknownNamespaces)
+	}
+	if len(output.FeatureDetails) != 2 {
+		t.Fatalf("Expected the unknown namespace in the details, got %+v", output.FeatureDetails)
+	}
+	unknown := output.FeatureDetails[0]
+	if unknown.Edition != "unknown" || unknown.Available || unknown.Confidence != ConfidenceLow {
+		t.Errorf("Unexpected unknown namespace details %+v", unknown)
+	}
+}
+
+func TestCheckEditionCompatibility_EEWithUnknownNamespace(t *testing.T) {
+	setMockFeatureFetcher(t, minimalFeatureYAML)
+
+	output := callCheckEditionCompatibility(t, `{"extra_config": {"auth/api-keys": {}, "ai/llm-gateway": {}}}`)
+
+	if output.Edition != "ee" || !output.RequiresEE || output.Confidence != ConfidenceHigh {
+		t.Errorf("Expected EE-only features to settle the verdict, got %+v", output)
+	}
+	if len(output.UnknownNamespaces) != 1 {
+		t.Errorf("Expected the unknown namespace to be listed, got %v", output.UnknownNamespaces)
+	}
+}
@@ -0,0 +1,262 @@
+package tools
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/krakend/mcp-server/internal/toolkit"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	searchFeedbackFile = "feedback/search.jsonl"
+
+	// Ranking multiplier per net vote: votes for the same query weigh more than votes
+	// given to the result for other queries
+	feedbackQueryWeight  = 0.3
+	feedbackGlobalWeight = 0.1
+	feedbackMinBoost     = 0.25
+	feedbackMaxBoost     = 2.0
+)
+
+// searchFeedbackMu serializes access to the local feedback file
+var searchFeedbackMu sync.Mutex
+
+// searchFeedback is a vote on a search result, stored one per line
+type searchFeedback struct {
+	Query     string    `json:"query"` // Normalized query terms
+	ResultID  string    `json:"result_id"`
+	URL       string    `json:"url,omitempty"`
+	Helpful   bool      `json:"helpful"`
+	Comment   string    `json:"comment,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ReportSearchFeedbackInput defines input for report_search_feedback tool
+type ReportSearchFeedbackInput struct {
+	Query    string `json:"query" jsonschema:"Query that returned the result"`
+	ResultID string `json:"result_id" jsonschema:"Chunk id of a search_documentation result, or id of a search_knowledge result"`
+	Rating   string `json:"rating" jsonschema:"helpful or irrelevant"`
+	URL      string `json:"url,omitempty" jsonschema:"URL of the result (optional, helps reading the exported feedback)"`
+	Comment  string `json:"comment,omitempty" jsonschema:"Why the result was (not) useful (optional)"`
+}
+
+// ReportSearchFeedbackOutput defines output for report_search_feedback tool
+type ReportSearchFeedbackOutput struct {
+	Recorded bool    `json:"recorded"`
+	Boost    float64 `json:"boost"` // Ranking multiplier the result now gets for this query
+	Message  string  `json:"message"`
+}
+
+// ExportSearchFeedbackInput defines input for export_search_feedback tool
+type ExportSearchFeedbackInput struct {
+	MinVotes int `json:"min_votes,omitempty" jsonschema:"Only export results with at least this many votes (optional, defaults to 1)"`
+}
+
+// ResultFeedback aggregates the votes of a search result
+type ResultFeedback struct {
+	ResultID   string   `json:"result_id"`
+	URL        string   `json:"url,omitempty"`
+	Helpful    int      `json:"helpful"`
+	Irrelevant int      `json:"irrelevant"`
+	Queries    []string `json:"queries"`
+	Comments   []string `json:"comments,omitempty"`
+}
+
+// ExportSearchFeedbackOutput defines output for export_search_feedback tool
+type ExportSearchFeedbackOutput struct {
+	File    string           `json:"file"`
+	Votes   int              `json:"votes"`
+	Results []ResultFeedback `json:"results"` // Most irrelevant first: candidates for index tuning
+}
+
+// normalizeQuery reduces a query to its terms so rephrasings with the same words match
+func normalizeQuery(query string) string {
+	terms := queryTerms(query)
+	sort.Strings(terms)
+	return strings.Join(terms, " ")
+}
+
+// loadSearchFeedback reads every vote of the local feedback file
+func loadSearchFeedback() ([]searchFeedback, error) {
+	f, err := os.Open(filepath.Join(dataDir, searchFeedbackFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to open search feedback: %w", err)
+	}
+	defer f.Close()
+
+	var votes []searchFeedback
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var vote searchFeedback
+		if err := json.Unmarshal(scanner.Bytes(), &vote); err != nil {
+			continue // Skip corrupted lines
+		}
+		votes = append(votes, vote)
+	}
+	return votes, scanner.Err()
+}
+
+// appendSearchFeedback adds a vote to the local feedback file
+func appendSearchFeedback(vote searchFeedback) error {
+	path := filepath.Join(dataDir, searchFeedbackFile)
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to create feedback directory: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open search feedback: %w", err)
+	}
+	defer f.Close()
+	return json.NewEncoder(f).Encode(vote)
+}
+
+// feedbackBoosts returns the ranking multiplier of every result with votes, for a query
+func feedbackBoosts(votes []searchFeedback, query string) map[string]float64 {
+	query = normalizeQuery(query)
+	net := map[string]float64{}
+	for _, vote := range votes {
+		weight := feedbackGlobalWeight
+		if vote.Query == query {
+			weight = feedbackQueryWeight
+		}
+		if !vote.Helpful {
+			weight = -weight
+		}
+		net[vote.ResultID] += weight
+	}
+	boosts := make(map[string]float64, len(net))
+	for id, n := range net {
+		boosts[id] = math.Max(feedbackMinBoost, math.Min(feedbackMaxBoost, 1+n))
+	}
+	return boosts
+}
+
+// searchFeedbackBoosts loads the local feedback and returns the multipliers for a query.
+// Ranking never fails because of feedback: errors give no boosts.
+func searchFeedbackBoosts(query string) map[string]float64 {
+	searchFeedbackMu.Lock()
+	votes, err := loadSearchFeedback()
+	searchFeedbackMu.Unlock()
+	if err != nil || len(votes) == 0 {
+		return nil
+	}
+	return feedbackBoosts(votes, query)
+}
+
+// ReportSearchFeedback records a vote on a search result
+func ReportSearchFeedback(ctx context.Context, req *mcp.CallToolRequest, input ReportSearchFeedbackInput) (*mcp.CallToolResult, ReportSearchFeedbackOutput, error) {
+	if strings.TrimSpace(input.Query) == "" || input.ResultID == "" {
+		return nil, ReportSearchFeedbackOutput{}, fmt.Errorf("query and result_id are required")
+	}
+	if input.Rating != "helpful" && input.Rating != "irrelevant" {
+		return nil, ReportSearchFeedbackOutput{}, fmt.Errorf("rating must be helpful or irrelevant, got %q", input.Rating)
+	}
+
+	searchFeedbackMu.Lock()
+	defer searchFeedbackMu.Unlock()
+	vote := searchFeedback{
+		Query:     normalizeQuery(input.Query),
+		ResultID:  input.ResultID,
+		URL:       input.URL,
+		Helpful:   input.Rating == "helpful",
+		Comment:   input.Comment,
+		CreatedAt: time.Now().UTC(),
+	}
+	if err := appendSearchFeedback(vote); err != nil {
+		return nil, ReportSearchFeedbackOutput{}, err
+	}
+	votes, err := loadSearchFeedback()
+	if err != nil {
+		return nil, ReportSearchFeedbackOutput{}, err
+	}
+
+	boost := feedbackBoosts(votes, input.Query)[input.ResultID]
+	return nil, ReportSearchFeedbackOutput{
+		Recorded: true,
+		Boost:    boost,
+		Message:  fmt.Sprintf("Feedback stored locally: %s now ranks with a x%.2f multiplier for this query", input.ResultID, boost),
+	}, nil
+}
+
+// ExportSearchFeedback aggregates the local feedback per result
+func ExportSearchFeedback(ctx context.Context, req *mcp.CallToolRequest, input ExportSearchFeedbackInput) (*mcp.CallToolResult, ExportSearchFeedbackOutput, error) {
+	minVotes := input.MinVotes
+	if minVotes <= 0 {
+		minVotes = 1
+	}
+	searchFeedbackMu.Lock()
+	votes, err := loadSearchFeedback()
+	searchFeedbackMu.Unlock()
+	if err != nil {
+		return nil, ExportSearchFeedbackOutput{}, err
+	}
+
+	output := ExportSearchFeedbackOutput{File: filepath.Join(dataDir, searchFeedbackFile), Votes: len(votes), Results: []ResultFeedback{}}
+	byResult := map[string]*ResultFeedback{}
+	var order []string
+	for _, vote := range votes {
+		r, ok := byResult[vote.ResultID]
+		if !ok {
+			r = &ResultFeedback{ResultID: vote.ResultID, Queries: []string{}}
+			byResult[vote.ResultID] = r
+			order = append(order, vote.ResultID)
+		}
+		if vote.Helpful {
+			r.Helpful++
+		} else {
+			r.Irrelevant++
+		}
+		if vote.URL != "" {
+			r.URL = vote.URL
+		}
+		if !slices.Contains(r.Queries, vote.Query) {
+			r.Queries = append(r.Queries, vote.Query)
+		}
+		if vote.Comment != "" {
+			r.Comments = append(r.Comments, vote.Comment)
+		}
+	}
+	for _, id := range order {
+		if r := byResult[id]; r.Helpful+r.Irrelevant >= minVotes {
+			output.Results = append(output.Results, *r)
+		}
+	}
+	sort.SliceStable(output.Results, func(i, j int) bool {
+		a, b := output.Results[i], output.Results[j]
+		return a.Irrelevant-a.Helpful > b.Irrelevant-b.Helpful
+	})
+	return nil, output, nil
+}
+
+// RegisterFeedbackTools registers the search feedback tools
+func RegisterFeedbackTools(server *mcp.Server) {
+	toolkit.AddTool(server,
+		&mcp.Tool{
+			Name:        "report_search_feedback",
+			Description: "Mark a search_documentation or search_knowledge result as helpful or irrelevant for a query. Feedback is stored locally and boosts or demotes the result in later searches with similar queries.",
+		},
+		ReportSearchFeedback,
+	)
+
+	toolkit.AddTool(server,
+		&mcp.Tool{
+			Name:        "export_search_feedback",
+			Description: "Export the locally stored search feedback aggregated per result (helpful and irrelevant votes, queries, comments), most irrelevant first, to guide documentation index tuning.",
+		},
+		ExportSearchFeedback,
+	)
+}
@@ -0,0 +1,67 @@
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/krakend/mcp-server/internal/indexing"
+)
+
+func TestSearchFeedback(t *testing.T) {
+	oldDataDir := dataDir
+	dataDir = t.TempDir()
+	defer func() { dataDir = oldDataDir }()
+	useTestDocIndex(t, []indexing.DocChunk{
+		{ID: "cors-1", Content: "CORS origins.", URL: "https://www.krakend.io/docs/service-settings/cors/"},
+		{ID: "cors-2", Content: "Set the allowed CORS origins in the FAQ example.", URL: "https://www.krakend.io/docs/faq/"},
+	})
+
+	search := func() []SearchResult {
+		_, output, err := SearchDocumentation(context.Background(), nil, SearchDocumentationInput{Query: "CORS origins"})
+		if err != nil {
+			t.Fatalf("SearchDocumentation() error = %v", err)
+		}
+		return output.Results
+	}
+	if results := search(); len(results) != 2 || results[0].Chunk.ID != "cors-1" {
+		t.Fatalf("Expected cors-1 ranked first before feedback, got %+v", results)
+	}
+
+	for i := 0; i < 3; i++ {
+		_, out, err := ReportSearchFeedback(context.Background(), nil, ReportSearchFeedbackInput{Query: "origins CORS", ResultID: "cors-1", Rating: "irrelevant", Comment: "FAQ was better"})
+		if err != nil || !out.Recorded {
+			t.Fatalf("ReportSearchFeedback() = %+v, %v", out, err)
+		}
+	}
+	if _, _, err := ReportSearchFeedback(context.Background(), nil, ReportSearchFeedbackInput{Query: "cors", ResultID: "cors-2", Rating: "meh"}); err == nil {
+		t.Error("Expected an error for an unknown rating")
+	}
+
+	// Same terms in another order count as the same query
+	if results := search(); results[0].Chunk.ID != "cors-2" {
+		t.Errorf("Expected cors-1 demoted below cors-2, got %+v", results)
+	}
+
+	_, export, err := ExportSearchFeedback(context.Background(), nil, ExportSearchFeedbackInput{})
+	if err != nil {
+		t.Fatalf("ExportSearchFeedback() error = %v", err)
+	}
+	if export.Votes != 3 || len(export.Results) != 1 || export.Results[0].Irrelevant != 3 || export.Results[0].Queries[0] != "cors origins" {
+		t.Errorf("Unexpected export: %+v", export)
+	}
+}
+
+func TestFeedbackBoosts(t *testing.T) {
+	votes := []searchFeedback{
+		{Query: "jwt validation", ResultID: "a", Helpful: true},
+		{Query: "rate limit", ResultID: "a", Helpful: true},
+		{Query: "jwt validation", ResultID: "b", Helpful: false},
+	}
+	boosts := feedbackBoosts(votes, "validation JWT")
+	if boosts["a"] != 1+feedbackQueryWeight+feedbackGlobalWeight {
+		t.Errorf("Unexpected boost for a: %v", boosts["a"])
+	}
+	if boosts["b"] != 1-feedbackQueryWeight {
+		t.Errorf("Unexpected boost for b: %v", boosts["b"])
+	}
+}
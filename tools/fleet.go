@@ -0,0 +1,229 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/krakend/mcp-server/internal/features"
+	"github.com/krakend/mcp-server/internal/pages"
+	"github.com/krakend/mcp-server/internal/toolkit"
+	"github.com/krakend/mcp-server/tools/validation"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// AnalyzeConfigFleetInput defines input for analyze_config_fleet tool
+type AnalyzeConfigFleetInput struct {
+	Directory string `json:"directory" jsonschema:"Directory containing KrakenD configurations (searched recursively)"`
+	Pattern   string `json:"pattern,omitempty" jsonschema:"File name glob used to select configurations (optional, defaults to *.json)"`
+	PageSize  int    `json:"page_size,omitempty" jsonschema:"Configurations per page (optional, pages are otherwise only split to stay under client message limits)"`
+	PageToken string `json:"page_token,omitempty" jsonschema:"next_page_token of a previous call, retrieving the following page (the other arguments are then ignored)"`
+}
+
+// NamespaceAdoption reports how many configurations use a namespace
+type NamespaceAdoption struct {
+	Namespace string  `json:"namespace"`
+	Configs   int     `json:"configs"`
+	Percent   float64 `json:"percent"`
+	Edition   string  `json:"edition,omitempty"`
+}
+
+// FleetConfigSummary contains the statistics of a single configuration in the fleet
+type FleetConfigSummary struct {
+	Path                 string   `json:"path"`
+	Version              string   `json:"version"`
+	Endpoints            int      `json:"endpoints"`
+	EndpointsWithoutAuth int      `json:"endpoints_without_auth"`
+	EEFeatures           []string `json:"ee_features"`
+}
+
+// SkippedConfig is a file that could not be analyzed
+type SkippedConfig struct {
+	Path   string `json:"path"`
+	Reason string `json:"reason"`
+}
+
+// AnalyzeConfigFleetOutput defines output for analyze_config_fleet tool
+type AnalyzeConfigFleetOutput struct {
+	ConfigsAnalyzed      int                  `json:"configs_analyzed"`
+	Versions             map[string]int       `json:"versions"`
+	NamespaceAdoption    []NamespaceAdoption  `json:"namespace_adoption"`
+	TotalEndpoints       int                  `json:"total_endpoints"`
+	EndpointsWithoutAuth int                  `json:"endpoints_without_auth"`
+	ConfigsUsingEE       int                  `json:"configs_using_ee"`
+	EEFeatureSpread      map[string]int       `json:"ee_feature_spread"`
+	Configs              []FleetConfigSummary `json:"configs"`
+	Skipped              []SkippedConfig      `json:"skipped,omitempty"`
+	Summary              string               `json:"summary"`
+	Page                 *pages.Info          `json:"page,omitempty"` // Configs are paginated, the statistics cover the whole fleet
+}
+
+// AnalyzeConfigFleet aggregates statistics across every KrakenD configuration found in a directory
+func AnalyzeConfigFleet(ctx context.Context, req *mcp.CallToolRequest, input AnalyzeConfigFleetInput) (*mcp.CallToolResult, AnalyzeConfigFleetOutput, error) {
+	if input.PageToken != "" {
+		output, configs, page, err := pages.Continue[AnalyzeConfigFleetOutput, FleetConfigSummary](pages.Session(req), "analyze_config_fleet", input.PageToken)
+		if err != nil {
+			return nil, AnalyzeConfigFleetOutput{}, err
+		}
+		output.Configs, output.Page = configs, page
+		return nil, output, nil
+	}
+	if input.Directory == "" {
+		return nil, AnalyzeConfigFleetOutput{}, fmt.Errorf("directory is required")
+	}
+	pattern := input.Pattern
+	if pattern == "" {
+		pattern = "*.json"
+	}
+	if _, err := filepath.Match(pattern, ""); err != nil {
+		return nil, AnalyzeConfigFleetOutput{}, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+	}
+
+	eeSet := eeOnlyNamespaces()
+
+	output := AnalyzeConfigFleetOutput{
+		Versions:          map[string]int{},
+		NamespaceAdoption: []NamespaceAdoption{},
+		EEFeatureSpread:   map[string]int{},
+		Configs:           []FleetConfigSummary{},
+	}
+	adoption := map[string]int{}
+
+	err := filepath.WalkDir(input.Directory, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			output.Skipped = append(output.Skipped, SkippedConfig{Path: path, Reason: err.Error()})
+			return nil
+		}
+		if d.IsDir() {
+			if path != input.Directory && (strings.HasPrefix(d.Name(), ".") || d.Name() == "node_modules") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if ok, _ := filepath.Match(pattern, d.Name()); !ok {
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			output.Skipped = append(output.Skipped, SkippedConfig{Path: path, Reason: err.Error()})
+			return nil
+		}
+		var config map[string]interface{}
+		if err := json.Unmarshal(data, &config); err != nil {
+			output.Skipped = append(output.Skipped, SkippedConfig{Path: path, Reason: "invalid JSON: " + err.Error()})
+			return nil
+		}
+		if _, ok := config["endpoints"]; !ok {
+			if _, ok := config["version"]; !ok {
+				output.Skipped = append(output.Skipped, SkippedConfig{Path: path, Reason: "not a KrakenD configuration"})
+				return nil
+			}
+		}
+
+		summary := FleetConfigSummary{
+			Path:       path,
+			Version:    validation.ExtractVersionFromConfig(string(data)),
+			EEFeatures: []string{},
+		}
+		summary.Endpoints, summary.EndpointsWithoutAuth = countEndpointsWithoutAuth(config)
+
+		namespaces := features.FindNamespacesInConfig(config)
+		sort.Strings(namespaces)
+		for _, ns := range namespaces {
+			adoption[ns]++
+			if _, ok := eeSet[ns]; ok {
+				summary.EEFeatures = append(summary.EEFeatures, ns)
+				output.EEFeatureSpread[ns]++
+			}
+		}
+
+		output.ConfigsAnalyzed++
+		output.Versions[summary.Version]++
+		output.TotalEndpoints += summary.Endpoints
+		output.EndpointsWithoutAuth += summary.EndpointsWithoutAuth
+		if len(summary.EEFeatures) > 0 {
+			output.ConfigsUsingEE++
+		}
+		output.Configs = append(output.Configs, summary)
+		return nil
+	})
+	if err != nil {
+		return nil, AnalyzeConfigFleetOutput{}, fmt.Errorf("failed to walk %s: %w", input.Directory, err)
+	}
+
+	for ns, count := range adoption {
+		entry := NamespaceAdoption{
+			Namespace: ns,
+			Configs:   count,
+			Percent:   float64(count) * 100 / float64(output.ConfigsAnalyzed),
+		}
+		if _, ok := eeSet[ns]; ok {
+			entry.Edition = "ee"
+		}
+		output.NamespaceAdoption = append(output.NamespaceAdoption, entry)
+	}
+	sort.Slice(output.NamespaceAdoption, func(i, j int) bool {
+		a, b := output.NamespaceAdoption[i], output.NamespaceAdoption[j]
+		if a.Configs == b.Configs {
+			return a.Namespace < b.Namespace
+		}
+		return a.Configs > b.Configs
+	})
+
+	output.Summary = fmt.Sprintf("Analyzed %d configuration(s): %d endpoint(s), %d without authentication, %d config(s) using EE features, %d distinct namespace(s)",
+		output.ConfigsAnalyzed, output.TotalEndpoints, output.EndpointsWithoutAuth, output.ConfigsUsingEE, len(output.NamespaceAdoption))
+	output.Configs, output.Page = pages.Paginate(pages.Session(req), "analyze_config_fleet", output, output.Configs, input.PageSize)
+
+	return &mcp.CallToolResult{Meta: map[string]interface{}{"configs_analyzed": output.ConfigsAnalyzed}}, output, nil
+}
+
+// eeOnlyNamespaces returns the Enterprise-only namespaces of the edition matrix, or the
+// curated list when the feature catalog is not available
+func eeOnlyNamespaces() map[string]struct{} {
+	eeOnly := features.CommonEEFeatures
+	if editionMatrix == nil {
+		_ = LoadFeatureData()
+	}
+	if editionMatrix != nil && len(editionMatrix.EEOnlyFeatures) > 0 {
+		eeOnly = editionMatrix.EEOnlyFeatures
+	}
+	eeSet := make(map[string]struct{}, len(eeOnly))
+	for _, ns := range eeOnly {
+		eeSet[ns] = struct{}{}
+	}
+	return eeSet
+}
+
+// countEndpointsWithoutAuth returns the number of endpoints and how many lack JWT or API key authentication
+func countEndpointsWithoutAuth(config map[string]interface{}) (total, withoutAuth int) {
+	endpoints, _ := config["endpoints"].([]interface{})
+	for _, ep := range endpoints {
+		endpoint, ok := ep.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		total++
+		extra, _ := endpoint["extra_config"].(map[string]interface{})
+		if extra["auth/validator"] == nil && extra["auth/api-keys"] == nil {
+			withoutAuth++
+		}
+	}
+	return total, withoutAuth
+}
+
+// RegisterFleetTools registers multi-config analysis tools
+func RegisterFleetTools(server *mcp.Server) {
+	toolkit.AddTool(server,
+		&mcp.Tool{
+			Name:        "analyze_config_fleet",
+			Description: "Aggregate statistics across a directory of KrakenD configurations (e.g. one per team): namespace adoption, KrakenD versions in use, endpoints without authentication and Enterprise feature spread. Useful for platform governance across many gateways.",
+		},
+		AnalyzeConfigFleet,
+	)
+}
@@ -0,0 +1,89 @@
+package tools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestAnalyzeConfigFleet(t *testing.T) {
+	setMockFeatureFetcher(t, minimalFeatureYAML)
+
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "team-b"), 0o755)
+	os.WriteFile(filepath.Join(dir, "team-a.json"), []byte(`{
+		"$schema": "https://www.krakend.io/schema/v2.12/krakend.json",
+		"version": 3,
+		"extra_config": {"security/cors": {}},
+		"endpoints": [
+			{"endpoint": "/a", "extra_config": {"auth/validator": {}}},
+			{"endpoint": "/b"}
+		]
+	}`), 0o644)
+	os.WriteFile(filepath.Join(dir, "team-b", "krakend.json"), []byte(`{
+		"version": 3,
+		"extra_config": {"security/cors": {}},
+		"endpoints": [{"endpoint": "/c", "extra_config": {"auth/api-keys": {}}}]
+	}`), 0o644)
+	os.WriteFile(filepath.Join(dir, "broken.json"), []byte(`{broken`), 0o644)
+	os.WriteFile(filepath.Join(dir, "package.json"), []byte(`{"name": "not-krakend"}`), 0o644)
+
+	_, output, err := AnalyzeConfigFleet(context.Background(), nil, AnalyzeConfigFleetInput{Directory: dir})
+	if err != nil {
+		t.Fatalf("AnalyzeConfigFleet() error = %v", err)
+	}
+
+	if output.ConfigsAnalyzed != 2 {
+		t.Errorf("Expected 2 configs analyzed, got %d", output.ConfigsAnalyzed)
+	}
+	if len(output.Skipped) != 2 {
+		t.Errorf("Expected 2 skipped files, got %v", output.Skipped)
+	}
+	if output.Versions["2.12"] != 1 || output.Versions["latest"] != 1 {
+		t.Errorf("Unexpected versions: %v", output.Versions)
+	}
+	if output.TotalEndpoints != 3 || output.EndpointsWithoutAuth != 1 {
+		t.Errorf("Expected 3 endpoints with 1 without auth, got %d/%d", output.TotalEndpoints, output.EndpointsWithoutAuth)
+	}
+	if output.ConfigsUsingEE != 1 || output.EEFeatureSpread["auth/api-keys"] != 1 {
+		t.Errorf("Unexpected EE spread: %d %v", output.ConfigsUsingEE, output.EEFeatureSpread)
+	}
+	if output.NamespaceAdoption[0].Namespace != "security/cors" || output.NamespaceAdoption[0].Percent != 100 {
+		t.Errorf("Expected security/cors to be the most adopted namespace, got %+v", output.NamespaceAdoption[0])
+	}
+}
+
+func TestAnalyzeConfigFleet_RequiresDirectory(t *testing.T) {
+	if _, _, err := AnalyzeConfigFleet(context.Background(), nil, AnalyzeConfigFleetInput{}); err == nil {
+		t.Error("Expected error when directory is missing")
+	}
+}
+
+func TestAnalyzeConfigFleet_Pages(t *testing.T) {
+	setMockFeatureFetcher(t, minimalFeatureYAML)
+
+	dir := t.TempDir()
+	for _, name := range []string{"a.json", "b.json", "c.json"} {
+		os.WriteFile(filepath.Join(dir, name), []byte(`{"version": 3, "endpoints": [{"endpoint": "/a"}]}`), 0o644)
+	}
+
+	_, output, err := AnalyzeConfigFleet(context.Background(), nil, AnalyzeConfigFleetInput{Directory: dir, PageSize: 2})
+	if err != nil {
+		t.Fatalf("AnalyzeConfigFleet() error = %v", err)
+	}
+	if len(output.Configs) != 2 || output.Page == nil || output.Page.Total != 3 || output.ConfigsAnalyzed != 3 {
+		t.Fatalf("Expected 2 of 3 configs with the fleet statistics, got %+v", output)
+	}
+
+	_, next, err := AnalyzeConfigFleet(context.Background(), nil, AnalyzeConfigFleetInput{PageToken: output.Page.NextToken})
+	if err != nil {
+		t.Fatalf("AnalyzeConfigFleet() error = %v", err)
+	}
+	if len(next.Configs) != 1 || next.Configs[0].Path != filepath.Join(dir, "c.json") || next.TotalEndpoints != 3 {
+		t.Errorf("Expected the last config with the fleet statistics, got %+v", next)
+	}
+	if _, _, err := AnalyzeConfigFleet(context.Background(), nil, AnalyzeConfigFleetInput{PageToken: output.Page.NextToken}); err == nil {
+		t.Error("Expected the token to be used up after the last page")
+	}
+}
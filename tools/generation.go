@@ -0,0 +1,248 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/krakend/mcp-server/internal/toolkit"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	defaultConfigName    = "KrakenD API Gateway"
+	defaultConfigPort    = 8080
+	defaultConfigBackend = "http://localhost:8000"
+	defaultConfigPreset  = "public-rest-api"
+)
+
+// configPreset describes the defaults applied for an API gateway archetype
+type configPreset struct {
+	Description   string
+	Timeout       string
+	CacheTTL      string
+	JWTAuth       bool
+	CORSOrigins   []string
+	MaxRate       int
+	ClientMaxRate int
+	LogLevel      string
+	Metrics       bool
+	BestPractices []string
+}
+
+// configPresets holds the named archetypes supported by generate_basic_config
+var configPresets = map[string]configPreset{
+	"public-rest-api": {
+		Description:   "Internet-facing REST API consumed by third-party browsers and clients",
+		Timeout:       "3s",
+		CacheTTL:      "300s",
+		JWTAuth:       true,
+		CORSOrigins:   []string{"https://example.com"},
+		MaxRate:       1000,
+		ClientMaxRate: 10,
+		LogLevel:      "WARNING",
+		BestPractices: []string{
+			"JWT validation is enabled on every endpoint: replace jwk_url with your identity provider",
+			"CORS only allows the listed origins: never use \"*\" together with credentials",
+			"Per-client rate limiting (by IP) protects the API from abusive consumers",
+			"Short timeouts (3s) keep slow backends from exhausting gateway resources",
+		},
+	},
+	"internal-mesh-facade": {
+		Description:   "Gateway in front of internal services, reachable only from the private network",
+		Timeout:       "10s",
+		CacheTTL:      "0s",
+		JWTAuth:       false,
+		MaxRate:       0,
+		ClientMaxRate: 0,
+		LogLevel:      "INFO",
+		Metrics:       true,
+		BestPractices: []string{
+			"No authentication is configured: make sure the gateway is not reachable from the internet",
+			"CORS is omitted because traffic does not originate from browsers",
+			"Rate limiting is omitted to avoid throttling service-to-service traffic; add it if callers are untrusted",
+			"OpenTelemetry with a Prometheus exporter is enabled to observe internal traffic",
+			"Longer timeouts (10s) accommodate chained internal calls",
+		},
+	},
+	"mobile-bff": {
+		Description:   "Backend-for-frontend aggregating several services for mobile applications",
+		Timeout:       "5s",
+		CacheTTL:      "60s",
+		JWTAuth:       true,
+		MaxRate:       5000,
+		ClientMaxRate: 20,
+		LogLevel:      "WARNING",
+		BestPractices: []string{
+			"JWT validation is enabled on every endpoint: replace jwk_url with your identity provider",
+			"CORS is omitted because native mobile clients do not enforce it",
+			"Aggregate several backends per endpoint to reduce round-trips over mobile networks",
+			"A short cache TTL (60s) lets clients reuse responses while keeping data fresh",
+			"Per-client rate limiting (by IP) absorbs retry storms from flaky connections",
+		},
+	},
+	"partner-api": {
+		Description:   "API exposed to a limited set of business partners under contract",
+		Timeout:       "5s",
+		CacheTTL:      "0s",
+		JWTAuth:       true,
+		CORSOrigins:   []string{"https://partner.example.com"},
+		MaxRate:       500,
+		ClientMaxRate: 5,
+		LogLevel:      "INFO",
+		Metrics:       true,
+		BestPractices: []string{
+			"JWT validation is enabled on every endpoint: issue partner credentials from your identity provider",
+			"CORS only allows the partner origins: keep the list in sync with your contracts",
+			"Strict per-client rate limits enforce the agreed usage quotas",
+			"OpenTelemetry metrics are enabled to report per-partner consumption",
+			"Responses are not cached so partners always receive up-to-date data",
+		},
+	},
+}
+
+// GenerateBasicConfigInput defines input for generate_basic_config tool
+type GenerateBasicConfigInput struct {
+	Name    string `json:"name,omitempty" jsonschema:"Name of the gateway (optional)"`
+	Port    int    `json:"port,omitempty" jsonschema:"Port the gateway listens on (optional, defaults to 8080)"`
+	Backend string `json:"backend,omitempty" jsonschema:"Backend host used in the example endpoint (optional, defaults to http://localhost:8000)"`
+	Preset  string `json:"preset,omitempty" jsonschema:"Gateway archetype: public-rest-api, internal-mesh-facade, mobile-bff or partner-api (optional, defaults to public-rest-api)"`
+}
+
+// GenerateBasicConfigOutput defines output for generate_basic_config tool
+type GenerateBasicConfigOutput struct {
+	Config        map[string]interface{} `json:"config"`
+	Preset        string                 `json:"preset"`
+	Description   string                 `json:"description"`
+	BestPractices []string               `json:"best_practices"`
+}
+
+// PresetNames returns the sorted list of available config presets
+func PresetNames() []string {
+	names := make([]string, 0, len(configPresets))
+	for name := range configPresets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// GenerateBasicConfig generates a starter KrakenD configuration for the selected preset
+func GenerateBasicConfig(ctx context.Context, req *mcp.CallToolRequest, input GenerateBasicConfigInput) (*mcp.CallToolResult, GenerateBasicConfigOutput, error) {
+	presetName := input.Preset
+	if presetName == "" {
+		presetName = defaultConfigPreset
+	}
+
+	preset, ok := configPresets[presetName]
+	if !ok {
+		return nil, GenerateBasicConfigOutput{}, fmt.Errorf("unknown preset %q (available: %s)", presetName, strings.Join(PresetNames(), ", "))
+	}
+
+	name := input.Name
+	if name == "" {
+		name = defaultConfigName
+	}
+	port := input.Port
+	if port == 0 {
+		port = defaultConfigPort
+	}
+	backend := input.Backend
+	if backend == "" {
+		backend = defaultConfigBackend
+	}
+
+	return nil, GenerateBasicConfigOutput{
+		Config:        buildPresetConfig(preset, name, port, backend),
+		Preset:        presetName,
+		Description:   preset.Description,
+		BestPractices: preset.BestPractices,
+	}, nil
+}
+
+// buildPresetConfig assembles the configuration document for a preset
+func buildPresetConfig(preset configPreset, name string, port int, backend string) map[string]interface{} {
+	extraConfig := map[string]interface{}{
+		"telemetry/logging": map[string]interface{}{
+			"level":  preset.LogLevel,
+			"prefix": "[KRAKEND]",
+			"stdout": true,
+		},
+	}
+
+	if len(preset.CORSOrigins) > 0 {
+		extraConfig["security/cors"] = map[string]interface{}{
+			"allow_origins":  preset.CORSOrigins,
+			"allow_methods":  []string{"GET", "HEAD", "POST", "PUT", "PATCH", "DELETE"},
+			"allow_headers":  []string{"Authorization", "Content-Type"},
+			"expose_headers": []string{"Content-Length", "Content-Type"},
+			"max_age":        "12h",
+		}
+	}
+
+	if preset.MaxRate > 0 {
+		extraConfig["qos/ratelimit/service"] = map[string]interface{}{
+			"max_rate":        preset.MaxRate,
+			"client_max_rate": preset.ClientMaxRate,
+			"strategy":        "ip",
+		}
+	}
+
+	if preset.Metrics {
+		extraConfig["telemetry/opentelemetry"] = map[string]interface{}{
+			"service_name": strings.ToLower(strings.ReplaceAll(name, " ", "-")),
+			"exporters": map[string]interface{}{
+				"prometheus": []interface{}{
+					map[string]interface{}{"name": "local_prometheus", "port": 9090},
+				},
+			},
+		}
+	}
+
+	endpoint := map[string]interface{}{
+		"endpoint":        "/v1/example",
+		"method":          "GET",
+		"output_encoding": "json",
+		"backend": []interface{}{
+			map[string]interface{}{
+				"url_pattern": "/example",
+				"host":        []string{backend},
+				"encoding":    "json",
+			},
+		},
+	}
+
+	if preset.JWTAuth {
+		endpoint["input_headers"] = []string{"Authorization"}
+		endpoint["extra_config"] = map[string]interface{}{
+			"auth/validator": map[string]interface{}{
+				"alg":     "RS256",
+				"jwk_url": "https://auth.example.com/.well-known/jwks.json",
+				"cache":   true,
+			},
+		}
+	}
+
+	return map[string]interface{}{
+		"$schema":      "https://www.krakend.io/schema/krakend.json",
+		"version":      3,
+		"name":         name,
+		"port":         port,
+		"timeout":      preset.Timeout,
+		"cache_ttl":    preset.CacheTTL,
+		"extra_config": extraConfig,
+		"endpoints":    []interface{}{endpoint},
+	}
+}
+
+// RegisterGenerationTools registers configuration generation tools
+func RegisterGenerationTools(server *mcp.Server) {
+	toolkit.AddTool(server,
+		&mcp.Tool{
+			Name:        "generate_basic_config",
+			Description: "Generate a starter KrakenD configuration from a named preset (public-rest-api, internal-mesh-facade, mobile-bff, partner-api). Each preset selects defaults for authentication, CORS, rate limiting, telemetry and timeouts; the best_practices field explains the choices made.",
+		},
+		GenerateBasicConfig,
+	)
+}
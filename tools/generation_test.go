@@ -0,0 +1,72 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+func TestGenerateBasicConfig_DefaultPreset(t *testing.T) {
+	_, output, err := GenerateBasicConfig(context.Background(), nil, GenerateBasicConfigInput{})
+	if err != nil {
+		t.Fatalf("GenerateBasicConfig() error = %v", err)
+	}
+
+	if output.Preset != defaultConfigPreset {
+		t.Errorf("Expected preset %s, got %s", defaultConfigPreset, output.Preset)
+	}
+	if output.Config["port"] != defaultConfigPort {
+		t.Errorf("Expected port %d, got %v", defaultConfigPort, output.Config["port"])
+	}
+	if len(output.BestPractices) == 0 {
+		t.Error("Expected best practices to be documented")
+	}
+}
+
+func TestGenerateBasicConfig_Presets(t *testing.T) {
+	tests := []struct {
+		preset    string
+		wantAuth  bool
+		wantCORS  bool
+		wantLimit bool
+	}{
+		{preset: "public-rest-api", wantAuth: true, wantCORS: true, wantLimit: true},
+		{preset: "internal-mesh-facade", wantAuth: false, wantCORS: false, wantLimit: false},
+		{preset: "mobile-bff", wantAuth: true, wantCORS: false, wantLimit: true},
+		{preset: "partner-api", wantAuth: true, wantCORS: true, wantLimit: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.preset, func(t *testing.T) {
+			_, output, err := GenerateBasicConfig(context.Background(), nil, GenerateBasicConfigInput{Preset: tt.preset})
+			if err != nil {
+				t.Fatalf("GenerateBasicConfig() error = %v", err)
+			}
+
+			extraConfig := output.Config["extra_config"].(map[string]interface{})
+			if _, ok := extraConfig["security/cors"]; ok != tt.wantCORS {
+				t.Errorf("CORS present = %v, want %v", ok, tt.wantCORS)
+			}
+			if _, ok := extraConfig["qos/ratelimit/service"]; ok != tt.wantLimit {
+				t.Errorf("rate limit present = %v, want %v", ok, tt.wantLimit)
+			}
+
+			endpoint := output.Config["endpoints"].([]interface{})[0].(map[string]interface{})
+			_, hasAuth := endpoint["extra_config"]
+			if hasAuth != tt.wantAuth {
+				t.Errorf("auth present = %v, want %v", hasAuth, tt.wantAuth)
+			}
+
+			if _, err := json.Marshal(output.Config); err != nil {
+				t.Errorf("generated config is not serializable: %v", err)
+			}
+		})
+	}
+}
+
+func TestGenerateBasicConfig_UnknownPreset(t *testing.T) {
+	_, _, err := GenerateBasicConfig(context.Background(), nil, GenerateBasicConfigInput{Preset: "does-not-exist"})
+	if err == nil {
+		t.Error("Expected error for unknown preset")
+	}
+}
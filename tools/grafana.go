@@ -0,0 +1,300 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/krakend/mcp-server/internal/toolkit"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const grafanaDatasourceInput = "${DS_PROMETHEUS}"
+
+// GenerateGrafanaDashboardInput defines input for generate_grafana_dashboard tool
+type GenerateGrafanaDashboardInput struct {
+	Config         string `json:"config" jsonschema:"KrakenD configuration (JSON string or file path)"`
+	ConfigEncoding string `json:"config_encoding,omitempty" jsonschema:"Encoding of config for large configurations: base64 or gzip+base64 (optional, defaults to a plain JSON string or file path)"`
+	Title          string `json:"title,omitempty" jsonschema:"Dashboard title (optional, defaults to the service name)"`
+	DatasourceUID  string `json:"datasource_uid,omitempty" jsonschema:"UID of an existing Prometheus datasource (optional, by default the datasource is chosen on import)"`
+	OutputFile     string `json:"output_file,omitempty" jsonschema:"Path where the dashboard JSON is written (optional)"`
+}
+
+// GenerateGrafanaDashboardOutput defines output for generate_grafana_dashboard tool
+type GenerateGrafanaDashboardOutput struct {
+	Dashboard  map[string]interface{} `json:"dashboard"`
+	Panels     []string               `json:"panels"`
+	Metrics    []string               `json:"metrics"` // Prometheus metrics the panels query
+	Warnings   []string               `json:"warnings,omitempty"`
+	OutputFile string                 `json:"output_file,omitempty"`
+	Message    string                 `json:"message"`
+}
+
+// grafanaPanel is a time series panel definition
+type grafanaPanel struct {
+	title  string
+	expr   string
+	legend string
+	unit   string
+	metric string
+}
+
+// dashboardBuilder lays out rows and panels on the Grafana grid (two panels per line)
+type dashboardBuilder struct {
+	datasource map[string]interface{}
+	panels     []interface{}
+	titles     []string
+	metrics    map[string]struct{}
+	nextID     int
+	y          int
+	x          int
+}
+
+func (b *dashboardBuilder) row(title string, panels ...grafanaPanel) {
+	if len(panels) == 0 {
+		return
+	}
+	if b.x != 0 {
+		b.x, b.y = 0, b.y+8
+	}
+	b.nextID++
+	b.panels = append(b.panels, map[string]interface{}{
+		"id":        b.nextID,
+		"type":      "row",
+		"title":     title,
+		"collapsed": false,
+		"panels":    []interface{}{},
+		"gridPos":   map[string]int{"h": 1, "w": 24, "x": 0, "y": b.y},
+	})
+	b.y++
+
+	for _, p := range panels {
+		b.nextID++
+		b.panels = append(b.panels, map[string]interface{}{
+			"id":         b.nextID,
+			"type":       "timeseries",
+			"title":      p.title,
+			"datasource": b.datasource,
+			"gridPos":    map[string]int{"h": 8, "w": 12, "x": b.x, "y": b.y},
+			"targets": []map[string]interface{}{
+				{"refId": "A", "expr": p.expr, "legendFormat": p.legend, "datasource": b.datasource},
+			},
+			"fieldConfig": map[string]interface{}{
+				"defaults":  map[string]interface{}{"unit": p.unit},
+				"overrides": []interface{}{},
+			},
+			"options": map[string]interface{}{
+				"legend":  map[string]interface{}{"displayMode": "table", "placement": "bottom", "calcs": []string{"mean", "max"}},
+				"tooltip": map[string]interface{}{"mode": "multi"},
+			},
+		})
+		b.titles = append(b.titles, title+" / "+p.title)
+		b.metrics[p.metric] = struct{}{}
+		if b.x == 0 {
+			b.x = 12
+		} else {
+			b.x, b.y = 0, b.y+8
+		}
+	}
+}
+
+// promSelector adds the dashboard job filter and extra matchers to a metric
+func promSelector(metric string, matchers ...string) string {
+	return metric + "{" + strings.Join(append([]string{`job=~"$job"`}, matchers...), ",") + "}"
+}
+
+// quantilePanel is the p95 of a histogram grouped by label
+func quantilePanel(title, metric, by, unit string) grafanaPanel {
+	return grafanaPanel{
+		title:  title,
+		expr:   fmt.Sprintf("histogram_quantile(0.95, sum by (le, %s) (rate(%s[$__rate_interval])))", by, promSelector(metric+"_bucket")),
+		legend: "{{" + by + "}}",
+		unit:   unit,
+		metric: metric,
+	}
+}
+
+// GenerateGrafanaDashboard builds a ready-to-import Grafana dashboard for the metrics a config exports
+func GenerateGrafanaDashboard(ctx context.Context, req *mcp.CallToolRequest, input GenerateGrafanaDashboardInput) (*mcp.CallToolResult, GenerateGrafanaDashboardOutput, error) {
+	configContent, err := readEncodedConfig(input.Config, input.ConfigEncoding)
+	if err != nil {
+		return nil, GenerateGrafanaDashboardOutput{}, fmt.Errorf("failed to read config: %w", err)
+	}
+	var config map[string]interface{}
+	if err := json.Unmarshal([]byte(configContent), &config); err != nil {
+		return nil, GenerateGrafanaDashboardOutput{}, fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	t := detectTelemetry(config)
+	if !t.OpenTelemetry {
+		if t.LegacyMetrics {
+			return nil, GenerateGrafanaDashboardOutput{}, fmt.Errorf("telemetry/metrics only exposes a stats endpoint without Prometheus series; add telemetry/opentelemetry with a prometheus exporter to generate a dashboard")
+		}
+		return nil, GenerateGrafanaDashboardOutput{}, fmt.Errorf("the configuration has no telemetry/opentelemetry namespace, so KrakenD exports no metrics to chart")
+	}
+
+	output := GenerateGrafanaDashboardOutput{}
+	if !t.PrometheusExporter {
+		output.Warnings = append(output.Warnings, "No prometheus exporter configured: the dashboard assumes the OTLP metrics reach Prometheus through a collector with the same metric names")
+	}
+	if t.LegacyMetrics {
+		output.Warnings = append(output.Warnings, "telemetry/metrics is also enabled; its stats endpoint is not charted")
+	}
+
+	datasource := map[string]interface{}{"type": "prometheus", "uid": grafanaDatasourceInput}
+	if input.DatasourceUID != "" {
+		datasource["uid"] = input.DatasourceUID
+	}
+	b := &dashboardBuilder{datasource: datasource, metrics: map[string]struct{}{}}
+
+	if t.Global {
+		requests := promSelector(metricServerDuration + "_count")
+		errors := promSelector(metricServerDuration+"_count", labelStatusCode+`=~"5.."`)
+		b.row("Global",
+			grafanaPanel{
+				title:  "Requests per second by endpoint",
+				expr:   fmt.Sprintf("sum by (%s) (rate(%s[$__rate_interval]))", labelRoute, requests),
+				legend: "{{" + labelRoute + "}}", unit: "reqps", metric: metricServerDuration,
+			},
+			grafanaPanel{
+				title:  "5xx error ratio by endpoint",
+				expr:   fmt.Sprintf("sum by (%[1]s) (rate(%[2]s[$__rate_interval])) / sum by (%[1]s) (rate(%[3]s[$__rate_interval]))", labelRoute, errors, requests),
+				legend: "{{" + labelRoute + "}}", unit: "percentunit", metric: metricServerDuration,
+			},
+			quantilePanel("p95 latency by endpoint", metricServerDuration, labelRoute, "s"),
+			grafanaPanel{
+				title:  "Responses by status code",
+				expr:   fmt.Sprintf("sum by (%s) (rate(%s[$__rate_interval]))", labelStatusCode, requests),
+				legend: "{{" + labelStatusCode + "}}", unit: "reqps", metric: metricServerDuration,
+			},
+			quantilePanel("p95 response size by endpoint", metricServerResponseSize, labelRoute, "bytes"),
+		)
+	} else {
+		output.Warnings = append(output.Warnings, "Global layer metrics are disabled: no request rate, error or latency panels per endpoint")
+	}
+
+	if t.Proxy {
+		b.row("Proxy", quantilePanel("p95 proxy duration by endpoint", metricProxyDuration, labelRoute, "s"))
+	}
+
+	var backendPanels []grafanaPanel
+	if t.BackendStage {
+		backendPanels = append(backendPanels, quantilePanel("p95 backend stage duration", metricBackendDuration, labelBackend, "s"))
+	}
+	if t.BackendRoundTrip {
+		backendPanels = append(backendPanels, quantilePanel("p95 backend round trip", metricClientDuration, labelBackend, "s"))
+	}
+	if t.BackendReadPayload {
+		backendPanels = append(backendPanels, quantilePanel("p95 backend response size", metricClientReadSize, labelBackend, "bytes"))
+	}
+	if t.BackendConnection {
+		backendPanels = append(backendPanels,
+			quantilePanel("p95 connection acquisition", metricClientGetConn, labelBackend, "s"),
+			quantilePanel("p95 DNS resolution", metricClientDNS, labelBackend, "s"),
+			quantilePanel("p95 TLS handshake", metricClientTLS, labelBackend, "s"),
+		)
+	}
+	b.row("Backends", backendPanels...)
+
+	var runtimePanels []grafanaPanel
+	if t.ProcessMetrics {
+		runtimePanels = append(runtimePanels,
+			grafanaPanel{title: "CPU usage", expr: fmt.Sprintf("rate(%s[$__rate_interval])", promSelector("process_cpu_seconds_total")), legend: "{{instance}}", unit: "percentunit", metric: "process_cpu_seconds_total"},
+			grafanaPanel{title: "Resident memory", expr: promSelector("process_resident_memory_bytes"), legend: "{{instance}}", unit: "bytes", metric: "process_resident_memory_bytes"},
+		)
+	}
+	if t.GoMetrics {
+		runtimePanels = append(runtimePanels,
+			grafanaPanel{title: "Goroutines", expr: promSelector("go_goroutines"), legend: "{{instance}}", unit: "short", metric: "go_goroutines"},
+			grafanaPanel{title: "Heap in use", expr: promSelector("go_memstats_heap_inuse_bytes"), legend: "{{instance}}", unit: "bytes", metric: "go_memstats_heap_inuse_bytes"},
+		)
+	}
+	b.row("Runtime", runtimePanels...)
+
+	if len(b.titles) == 0 {
+		return nil, GenerateGrafanaDashboardOutput{}, fmt.Errorf("every telemetry layer has its metrics disabled, there is nothing to chart")
+	}
+
+	title := input.Title
+	if title == "" {
+		title = "KrakenD"
+		if t.ServiceName != "" {
+			title = "KrakenD - " + t.ServiceName
+		}
+	}
+
+	jobMetric := metricServerDuration + "_count"
+	if !t.Global {
+		jobMetric = "up"
+	}
+	dashboard := map[string]interface{}{
+		"title":         title,
+		"tags":          []string{"krakend", "generated"},
+		"timezone":      "browser",
+		"schemaVersion": 39,
+		"refresh":       "30s",
+		"time":          map[string]string{"from": "now-1h", "to": "now"},
+		"editable":      true,
+		"panels":        b.panels,
+		"templating": map[string]interface{}{
+			"list": []map[string]interface{}{
+				{
+					"name":       "job",
+					"label":      "Job",
+					"type":       "query",
+					"datasource": datasource,
+					"query":      fmt.Sprintf("label_values(%s, job)", jobMetric),
+					"refresh":    2,
+					"multi":      true,
+					"includeAll": true,
+					"allValue":   ".*",
+					"current":    map[string]interface{}{"text": "All", "value": "$__all"},
+				},
+			},
+		},
+	}
+	if input.DatasourceUID == "" {
+		dashboard["__inputs"] = []map[string]string{{
+			"name": "DS_PROMETHEUS", "label": "Prometheus", "type": "datasource",
+			"pluginId": "prometheus", "pluginName": "Prometheus",
+		}}
+	}
+
+	output.Dashboard = dashboard
+	output.Panels = b.titles
+	for metric := range b.metrics {
+		output.Metrics = append(output.Metrics, metric)
+	}
+	sort.Strings(output.Metrics)
+
+	if input.OutputFile != "" {
+		if err := os.MkdirAll(filepath.Dir(input.OutputFile), 0o755); err != nil {
+			return nil, GenerateGrafanaDashboardOutput{}, fmt.Errorf("failed to create %s: %w", filepath.Dir(input.OutputFile), err)
+		}
+		if err := writeJSONFile(input.OutputFile, dashboard, 0o644); err != nil {
+			return nil, GenerateGrafanaDashboardOutput{}, err
+		}
+		output.OutputFile = input.OutputFile
+	}
+
+	output.Message = fmt.Sprintf("Generated dashboard %q with %d panel(s) querying %d metric(s)", title, len(output.Panels), len(output.Metrics))
+	if t.PrometheusExporter {
+		output.Message += fmt.Sprintf("; scrape KrakenD on port %d", t.PrometheusPort)
+	}
+	return nil, output, nil
+}
+
+// RegisterGrafanaTools registers observability dashboard generation tools
+func RegisterGrafanaTools(server *mcp.Server) {
+	toolkit.AddTool(server,
+		&mcp.Tool{
+			Name:        "generate_grafana_dashboard",
+			Description: "Generate a ready-to-import Grafana dashboard JSON for the telemetry a KrakenD configuration exports (telemetry/opentelemetry layers, Prometheus exporter, process and Go metrics). Panels only query metric names and labels KrakenD actually exports for those settings: request rate, 5xx ratio, p95 latency per endpoint, proxy and backend durations, connection timings and runtime usage.",
+		},
+		GenerateGrafanaDashboard,
+	)
+}
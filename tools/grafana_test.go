@@ -0,0 +1,107 @@
+package tools
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const otelTestConfig = `{
+	"version": 3,
+	"extra_config": {
+		"telemetry/opentelemetry": {
+			"service_name": "orders-gateway",
+			"exporters": {"prometheus": [{"name": "local", "port": 9091, "go_metrics": true}]},
+			"layers": {
+				"proxy": {"disable_metrics": true},
+				"backend": {"metrics": {"round_trip": true, "detailed_connection": true}}
+			}
+		}
+	},
+	"endpoints": [{"endpoint": "/orders"}]
+}`
+
+func TestGenerateGrafanaDashboard(t *testing.T) {
+	outFile := filepath.Join(t.TempDir(), "grafana", "krakend.json")
+	_, output, err := GenerateGrafanaDashboard(context.Background(), nil, GenerateGrafanaDashboardInput{Config: otelTestConfig, OutputFile: outFile})
+	if err != nil {
+		t.Fatalf("GenerateGrafanaDashboard() error = %v", err)
+	}
+
+	if output.Dashboard["title"] != "KrakenD - orders-gateway" {
+		t.Errorf("Unexpected title %v", output.Dashboard["title"])
+	}
+	if _, ok := output.Dashboard["__inputs"]; !ok {
+		t.Error("Expected a datasource input for import")
+	}
+	for _, want := range []string{metricServerDuration, metricClientDuration, metricClientTLS, "go_goroutines"} {
+		if !containsString(output.Metrics, want) {
+			t.Errorf("Expected metric %s, got %v", want, output.Metrics)
+		}
+	}
+	for _, unwanted := range []string{metricProxyDuration, metricClientReadSize, "process_resident_memory_bytes"} {
+		if containsString(output.Metrics, unwanted) {
+			t.Errorf("Metric %s must not be charted for this config", unwanted)
+		}
+	}
+	if !strings.Contains(output.Message, "port 9091") {
+		t.Errorf("Expected scrape port in message, got %q", output.Message)
+	}
+
+	data, err := os.ReadFile(outFile)
+	if err != nil {
+		t.Fatalf("Dashboard not written: %v", err)
+	}
+	var written map[string]interface{}
+	if err := json.Unmarshal(data, &written); err != nil {
+		t.Fatalf("Written dashboard is not JSON: %v", err)
+	}
+	panels, _ := written["panels"].([]interface{})
+	seen := map[string]bool{}
+	for _, p := range panels {
+		panel := p.(map[string]interface{})
+		pos := panel["gridPos"].(map[string]interface{})
+		key := fmt.Sprintf("%v/%v", pos["x"], pos["y"])
+		if seen[key] {
+			t.Errorf("Overlapping panels at %v", pos)
+		}
+		seen[key] = true
+	}
+}
+
+func TestGenerateGrafanaDashboard_NoTelemetry(t *testing.T) {
+	_, _, err := GenerateGrafanaDashboard(context.Background(), nil, GenerateGrafanaDashboardInput{Config: `{"version": 3, "extra_config": {"telemetry/metrics": {}}}`})
+	if err == nil || !strings.Contains(err.Error(), "telemetry/opentelemetry") {
+		t.Errorf("Expected an error pointing to telemetry/opentelemetry, got %v", err)
+	}
+}
+
+func TestGenerateGrafanaDashboard_DatasourceUID(t *testing.T) {
+	_, output, err := GenerateGrafanaDashboard(context.Background(), nil, GenerateGrafanaDashboardInput{Config: otelTestConfig, DatasourceUID: "prom-main"})
+	if err != nil {
+		t.Fatalf("GenerateGrafanaDashboard() error = %v", err)
+	}
+	if _, ok := output.Dashboard["__inputs"]; ok {
+		t.Error("No import input expected with an explicit datasource")
+	}
+	data, _ := json.Marshal(output.Dashboard)
+	if strings.Contains(string(data), "DS_PROMETHEUS") {
+		t.Error("Panels must use the explicit datasource")
+	}
+}
+
+func TestGenerateGrafanaDashboard_ConfigEncoding(t *testing.T) {
+	encoded := base64.StdEncoding.EncodeToString([]byte(otelTestConfig))
+	_, output, err := GenerateGrafanaDashboard(context.Background(), nil, GenerateGrafanaDashboardInput{Config: encoded, ConfigEncoding: "base64"})
+	if err != nil {
+		t.Fatalf("GenerateGrafanaDashboard() error = %v", err)
+	}
+	if output.Dashboard["title"] != "KrakenD - orders-gateway" {
+		t.Errorf("Unexpected title %v", output.Dashboard["title"])
+	}
+}
@@ -0,0 +1,343 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"reflect"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/krakend/mcp-server/internal/toolkit"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	httpSecureNamespace = "security/http"
+	martianNamespace    = "modifier/martian"
+)
+
+// defaultHeaderPolicy is applied when no policy is given
+var defaultHeaderPolicy = HeaderPolicy{
+	Strip: []string{"X-Powered-By", "Server"},
+	Add: map[string]string{
+		"X-Content-Type-Options":    "nosniff",
+		"X-Frame-Options":           "DENY",
+		"Strict-Transport-Security": "max-age=31536000; includeSubDomains",
+		"Referrer-Policy":           "no-referrer",
+	},
+	Forward: []string{"X-Request-Id", "X-Correlation-Id"},
+}
+
+var stsMaxAge = regexp.MustCompile(`(?i)max-age=(\d+)`)
+
+// HeaderPolicy is an organization's standard header rules
+type HeaderPolicy struct {
+	Strip   []string          `yaml:"strip" json:"strip"`     // Response headers removed, e.g. X-Powered-By
+	Add     map[string]string `yaml:"add" json:"add"`         // Response headers added, e.g. security headers
+	Forward []string          `yaml:"forward" json:"forward"` // Request headers forwarded to the backends, e.g. correlation IDs
+}
+
+// ApplyHeaderPolicyInput defines input for apply_header_policy tool
+type ApplyHeaderPolicyInput struct {
+	Config    string   `json:"config" jsonschema:"KrakenD configuration as JSON string or file path"`
+	Policy    string   `json:"policy,omitempty" jsonschema:"Header policy with strip, add and forward lists, as JSON string or JSON/YAML file path (optional, defaults to stripping X-Powered-By and Server, adding the usual security headers and forwarding X-Request-Id and X-Correlation-Id)"`
+	Endpoints []string `json:"endpoints,omitempty" jsonschema:"Endpoint paths the policy applies to (optional, defaults to every endpoint)"`
+}
+
+// ApplyHeaderPolicyOutput defines output for apply_header_policy tool
+type ApplyHeaderPolicyOutput struct {
+	Policy             HeaderPolicy           `json:"policy"`
+	ServiceExtraConfig map[string]interface{} `json:"service_extra_config,omitempty"` // security/http entry
+	BackendExtraConfig map[string]interface{} `json:"backend_extra_config,omitempty"` // martian entry of no-op endpoint backends
+	Config             map[string]interface{} `json:"config"`
+	Changes            []string               `json:"changes"` // JSON paths modified
+	Warnings           []string               `json:"warnings"`
+	Notes              []string               `json:"notes"`
+	Summary            string                 `json:"summary"`
+}
+
+// readHeaderPolicy parses a policy given as JSON or as a JSON/YAML file path
+func readHeaderPolicy(input string) (HeaderPolicy, error) {
+	if strings.TrimSpace(input) == "" {
+		return defaultHeaderPolicy, nil
+	}
+	content, err := readConfigContent(input)
+	if err != nil {
+		return HeaderPolicy{}, fmt.Errorf("failed to read policy: %w", err)
+	}
+	var policy HeaderPolicy
+	if err := yaml.Unmarshal([]byte(content), &policy); err != nil {
+		return HeaderPolicy{}, fmt.Errorf("invalid policy: %w", err)
+	}
+	if len(policy.Strip) == 0 && len(policy.Add) == 0 && len(policy.Forward) == 0 {
+		return HeaderPolicy{}, fmt.Errorf("the policy has no strip, add or forward headers")
+	}
+	for i, name := range policy.Strip {
+		policy.Strip[i] = http.CanonicalHeaderKey(name)
+	}
+	for i, name := range policy.Forward {
+		policy.Forward[i] = http.CanonicalHeaderKey(name)
+	}
+	return policy, nil
+}
+
+// httpSecureSettings translates the security headers KrakenD sets natively into security/http
+// settings. The other headers can only be added with martian.
+func httpSecureSettings(add map[string]string) (settings map[string]interface{}, unmapped []string) {
+	settings = map[string]interface{}{}
+	for name, value := range add {
+		switch http.CanonicalHeaderKey(name) {
+		case "X-Frame-Options":
+			if strings.EqualFold(value, "DENY") {
+				settings["frame_deny"] = true
+			} else {
+				settings["custom_frame_options_value"] = value
+			}
+		case "X-Content-Type-Options":
+			settings["content_type_nosniff"] = strings.EqualFold(value, "nosniff")
+		case "X-Xss-Protection":
+			settings["browser_xss_filter"] = !strings.HasPrefix(value, "0")
+		case "Content-Security-Policy":
+			settings["content_security_policy"] = value
+		case "Referrer-Policy":
+			settings["referrer_policy"] = value
+		case "Strict-Transport-Security":
+			match := stsMaxAge.FindStringSubmatch(value)
+			if match == nil {
+				unmapped = append(unmapped, name)
+				continue
+			}
+			seconds, _ := strconv.Atoi(match[1])
+			settings["sts_seconds"] = seconds
+			if strings.Contains(strings.ToLower(value), "includesubdomains") {
+				settings["sts_include_subdomains"] = true
+			}
+		default:
+			unmapped = append(unmapped, name)
+		}
+	}
+	sort.Strings(unmapped)
+	return settings, unmapped
+}
+
+// headerPolicyModifier builds the martian modifier stripping and adding response headers
+func headerPolicyModifier(policy HeaderPolicy, names []string) map[string]interface{} {
+	var modifiers []interface{}
+	if len(policy.Strip) > 0 {
+		modifiers = append(modifiers, map[string]interface{}{"header.Blacklist": map[string]interface{}{
+			"scope": []interface{}{"response"},
+			"names": toInterfaceSlice(policy.Strip),
+		}})
+	}
+	for _, name := range names {
+		modifiers = append(modifiers, map[string]interface{}{"header.Modifier": map[string]interface{}{
+			"scope": []interface{}{"response"},
+			"name":  name,
+			"value": policy.Add[name],
+		}})
+	}
+	switch len(modifiers) {
+	case 0:
+		return nil
+	case 1:
+		return modifiers[0].(map[string]interface{})
+	}
+	return map[string]interface{}{"fifo.Group": map[string]interface{}{
+		"scope":           []interface{}{"response"},
+		"aggregateErrors": true,
+		"modifiers":       modifiers,
+	}}
+}
+
+// toInterfaceSlice converts strings to the JSON representation of a configuration
+func toInterfaceSlice(values []string) []interface{} {
+	result := make([]interface{}, len(values))
+	for i, v := range values {
+		result[i] = v
+	}
+	return result
+}
+
+// hasMartianModifier tells whether a martian entry already is, or groups, the modifier
+func hasMartianModifier(existing, modifier map[string]interface{}) bool {
+	if reflect.DeepEqual(normalizeJSON(existing), normalizeJSON(modifier)) {
+		return true
+	}
+	group, _ := existing["fifo.Group"].(map[string]interface{})
+	modifiers, _ := group["modifiers"].([]interface{})
+	for _, m := range modifiers {
+		if m, ok := m.(map[string]interface{}); ok && hasMartianModifier(m, modifier) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeJSON round-trips a value through JSON so generated and parsed values compare equal
+func normalizeJSON(value interface{}) interface{} {
+	data, _ := json.Marshal(value)
+	var normalized interface{}
+	_ = json.Unmarshal(data, &normalized)
+	return normalized
+}
+
+// ApplyHeaderPolicy applies an organization's header rules to every endpoint of a configuration:
+// security headers at the service level, response header stripping on the backends whose headers
+// reach the client and correlation IDs in the forwarded headers
+func ApplyHeaderPolicy(ctx context.Context, req *mcp.CallToolRequest, input ApplyHeaderPolicyInput) (*mcp.CallToolResult, ApplyHeaderPolicyOutput, error) {
+	configContent, err := readConfigContent(input.Config)
+	if err != nil {
+		return nil, ApplyHeaderPolicyOutput{}, fmt.Errorf("failed to read config: %w", err)
+	}
+	var config map[string]interface{}
+	if err := json.Unmarshal([]byte(configContent), &config); err != nil {
+		return nil, ApplyHeaderPolicyOutput{}, fmt.Errorf("invalid JSON: %w", err)
+	}
+	policy, err := readHeaderPolicy(input.Policy)
+	if err != nil {
+		return nil, ApplyHeaderPolicyOutput{}, err
+	}
+
+	output := ApplyHeaderPolicyOutput{Policy: policy, Config: config, Changes: []string{}, Warnings: []string{}, Notes: []string{
+		"Endpoints with the json, fast-json or other encodings answer with the headers KrakenD sets, never the backend ones: stripping is only needed on no-op endpoints, which proxy the backend response as is.",
+		"Headers not in input_headers are not forwarded to the backends: correlation IDs are added to every endpoint that does not forward all headers already.",
+	}}
+	extraConfig := func(parent map[string]interface{}) map[string]interface{} {
+		extra, _ := parent["extra_config"].(map[string]interface{})
+		if extra == nil {
+			extra = map[string]interface{}{}
+			parent["extra_config"] = extra
+		}
+		return extra
+	}
+
+	// Security headers KrakenD sets natively for every endpoint
+	settings, unmapped := httpSecureSettings(policy.Add)
+	if len(settings) > 0 {
+		output.ServiceExtraConfig = map[string]interface{}{httpSecureNamespace: settings}
+		service := extraConfig(config)
+		current, _ := service[httpSecureNamespace].(map[string]interface{})
+		if current == nil {
+			current = map[string]interface{}{}
+			service[httpSecureNamespace] = current
+		}
+		changed := false
+		for _, key := range slices.Sorted(maps.Keys(settings)) {
+			old, exists := current[key]
+			if exists && reflect.DeepEqual(normalizeJSON(old), normalizeJSON(settings[key])) {
+				continue
+			}
+			if exists {
+				output.Warnings = append(output.Warnings, fmt.Sprintf("$.extra_config['%s'].%s was %v and is now %v", httpSecureNamespace, key, old, settings[key]))
+			}
+			current[key] = settings[key]
+			changed = true
+		}
+		if changed {
+			output.Changes = append(output.Changes, fmt.Sprintf("$.extra_config['%s']", httpSecureNamespace))
+		}
+	}
+	modifier := headerPolicyModifier(policy, unmapped)
+	if modifier != nil {
+		output.BackendExtraConfig = map[string]interface{}{martianNamespace: modifier}
+	}
+
+	endpoints, _ := config["endpoints"].([]interface{})
+	applied, noop := 0, 0
+	var encoded []string // Endpoints that cannot get the headers security/http does not set
+	for i, ep := range endpoints {
+		endpoint, ok := ep.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		path, _ := endpoint["endpoint"].(string)
+		if len(input.Endpoints) > 0 && !slices.Contains(input.Endpoints, path) {
+			continue
+		}
+		applied++
+
+		if len(policy.Forward) > 0 {
+			headers, _ := endpoint["input_headers"].([]interface{})
+			if !slices.Contains(headers, interface{}("*")) {
+				added := false
+				for _, name := range policy.Forward {
+					if !slices.ContainsFunc(headers, func(h interface{}) bool { s, _ := h.(string); return strings.EqualFold(s, name) }) {
+						headers = append(headers, name)
+						added = true
+					}
+				}
+				if added {
+					endpoint["input_headers"] = headers
+					output.Changes = append(output.Changes, fmt.Sprintf("$.endpoints[%d].input_headers", i))
+				}
+			}
+		}
+
+		if endpoint["output_encoding"] != "no-op" {
+			if len(unmapped) > 0 {
+				encoded = append(encoded, endpointMethod(endpoint)+" "+path)
+			}
+			continue
+		}
+		if modifier == nil {
+			continue
+		}
+		noop++
+		backends, _ := endpoint["backend"].([]interface{})
+		for j, b := range backends {
+			backend, ok := b.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			extra := extraConfig(backend)
+			location := fmt.Sprintf("$.endpoints[%d].backend[%d].extra_config['%s']", i, j, martianNamespace)
+			existing, ok := extra[martianNamespace].(map[string]interface{})
+			switch {
+			case ok && hasMartianModifier(existing, modifier):
+				continue
+			case ok:
+				// Keep the existing martian modifiers and apply the policy after them
+				extra[martianNamespace] = map[string]interface{}{"fifo.Group": map[string]interface{}{
+					"scope":           []interface{}{"request", "response"},
+					"aggregateErrors": true,
+					"modifiers":       []interface{}{existing, modifier},
+				}}
+				output.Warnings = append(output.Warnings, fmt.Sprintf("%s already had martian modifiers: both were grouped in a fifo.Group", location))
+			default:
+				extra[martianNamespace] = modifier
+			}
+			output.Changes = append(output.Changes, location)
+		}
+	}
+	if len(encoded) > 0 {
+		output.Warnings = append(output.Warnings, fmt.Sprintf("%s cannot be added to the responses of %d endpoint(s) without the no-op encoding (security/http has no equivalent): %s",
+			strings.Join(unmapped, ", "), len(encoded), strings.Join(encoded, ", ")))
+	}
+	if len(input.Endpoints) > 0 && applied < len(input.Endpoints) {
+		output.Warnings = append(output.Warnings, fmt.Sprintf("%d of %d endpoint path(s) given were not found in the configuration", len(input.Endpoints)-applied, len(input.Endpoints)))
+	}
+
+	output.Summary = fmt.Sprintf("Header policy applied to %d endpoint(s) (%d with the no-op encoding): %d change(s)", applied, noop, len(output.Changes))
+	if len(output.Changes) == 0 {
+		output.Summary = fmt.Sprintf("The %d endpoint(s) already follow the header policy", applied)
+	}
+	return nil, output, nil
+}
+
+// RegisterHeaderPolicyTools registers the header policy tool
+func RegisterHeaderPolicyTools(server *mcp.Server) {
+	toolkit.AddTool(server,
+		&mcp.Tool{
+			Name:        "apply_header_policy",
+			Description: "Apply an organization's standard header rules to all endpoints at once instead of hand-editing each one: security headers through security/http at the service level, stripping headers such as X-Powered-By and adding custom ones with martian on no-op endpoints, and forwarding correlation IDs in input_headers. Idempotent: re-applying the policy changes nothing.",
+		},
+		ApplyHeaderPolicy,
+	)
+}
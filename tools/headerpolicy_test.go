@@ -0,0 +1,103 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestApplyHeaderPolicy(t *testing.T) {
+	config := `{"version": 3, "extra_config": {"security/http": {"frame_deny": false}}, "endpoints": [
+		{"endpoint": "/users", "input_headers": ["Authorization"], "backend": [{"url_pattern": "/users"}]},
+		{"endpoint": "/files", "output_encoding": "no-op", "backend": [{"url_pattern": "/files"}]},
+		{"endpoint": "/proxy", "input_headers": ["*"], "output_encoding": "no-op", "backend": [{"url_pattern": "/p", "extra_config": {"modifier/martian": {"header.Modifier": {"scope": ["request"], "name": "X-Key", "value": "k"}}}}]}
+	]}`
+	_, output, err := ApplyHeaderPolicy(context.Background(), nil, ApplyHeaderPolicyInput{Config: config})
+	if err != nil {
+		t.Fatalf("ApplyHeaderPolicy() error = %v", err)
+	}
+
+	secure := output.Config["extra_config"].(map[string]interface{})["security/http"].(map[string]interface{})
+	if secure["frame_deny"] != true || secure["content_type_nosniff"] != true || secure["sts_seconds"] != 31536000 || secure["referrer_policy"] != "no-referrer" {
+		t.Errorf("Unexpected security/http settings: %v", secure)
+	}
+	if len(output.Warnings) != 2 || !strings.Contains(output.Warnings[0], "frame_deny was false") {
+		t.Errorf("Expected warnings for the replaced setting and the grouped modifiers, got %v", output.Warnings)
+	}
+
+	endpoints := output.Config["endpoints"].([]interface{})
+	users := endpoints[0].(map[string]interface{})
+	if headers := users["input_headers"].([]interface{}); len(headers) != 3 || headers[1] != "X-Request-Id" {
+		t.Errorf("Expected the correlation IDs to be forwarded, got %v", headers)
+	}
+	if _, ok := users["backend"].([]interface{})[0].(map[string]interface{})["extra_config"]; ok {
+		t.Error("Endpoints without the no-op encoding should not get martian modifiers")
+	}
+	files := endpoints[1].(map[string]interface{})
+	martian := files["backend"].([]interface{})[0].(map[string]interface{})["extra_config"].(map[string]interface{})["modifier/martian"].(map[string]interface{})
+	if blacklist, ok := martian["header.Blacklist"].(map[string]interface{}); !ok || len(blacklist["names"].([]interface{})) != 2 {
+		t.Errorf("Expected a header.Blacklist stripping X-Powered-By and Server, got %v", martian)
+	}
+	proxy := endpoints[2].(map[string]interface{})
+	if headers := proxy["input_headers"].([]interface{}); len(headers) != 1 {
+		t.Errorf("Endpoints forwarding every header should be left as is, got %v", headers)
+	}
+	grouped := proxy["backend"].([]interface{})[0].(map[string]interface{})["extra_config"].(map[string]interface{})["modifier/martian"].(map[string]interface{})
+	if _, ok := grouped["fifo.Group"]; !ok {
+		t.Errorf("Expected the existing modifier to be grouped, got %v", grouped)
+	}
+
+	// Re-applying the policy changes nothing
+	path := filepath.Join(t.TempDir(), "krakend.json")
+	data, err := json.Marshal(output.Config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	_, again, err := ApplyHeaderPolicy(context.Background(), nil, ApplyHeaderPolicyInput{Config: path})
+	if err != nil {
+		t.Fatalf("ApplyHeaderPolicy() error = %v", err)
+	}
+	if len(again.Changes) != 0 || !strings.Contains(again.Summary, "already follow") {
+		t.Errorf("Expected no changes re-applying the policy, got %v", again.Changes)
+	}
+}
+
+func TestApplyHeaderPolicy_CustomPolicy(t *testing.T) {
+	policy := filepath.Join(t.TempDir(), "policy.yaml")
+	content := "add:\n  X-Api-Version: \"2\"\nforward:\n  - x-trace-id\n"
+	if err := os.WriteFile(policy, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	config := `{"version": 3, "endpoints": [
+		{"endpoint": "/a", "backend": [{"url_pattern": "/a"}]},
+		{"endpoint": "/b", "output_encoding": "no-op", "backend": [{"url_pattern": "/b"}]}
+	]}`
+	_, output, err := ApplyHeaderPolicy(context.Background(), nil, ApplyHeaderPolicyInput{Config: config, Policy: policy, Endpoints: []string{"/a", "/b", "/c"}})
+	if err != nil {
+		t.Fatalf("ApplyHeaderPolicy() error = %v", err)
+	}
+	if output.ServiceExtraConfig != nil {
+		t.Errorf("X-Api-Version has no security/http equivalent, got %v", output.ServiceExtraConfig)
+	}
+	modifier := output.BackendExtraConfig["modifier/martian"].(map[string]interface{})["header.Modifier"].(map[string]interface{})
+	if modifier["name"] != "X-Api-Version" || modifier["value"] != "2" {
+		t.Errorf("Unexpected martian modifier: %v", modifier)
+	}
+	a := output.Config["endpoints"].([]interface{})[0].(map[string]interface{})
+	if headers := a["input_headers"].([]interface{}); len(headers) != 1 || headers[0] != "X-Trace-Id" {
+		t.Errorf("Expected the canonical header to be forwarded, got %v", headers)
+	}
+	if len(output.Warnings) != 2 || !strings.Contains(output.Warnings[0], "GET /a") || !strings.Contains(output.Warnings[1], "1 of 3") {
+		t.Errorf("Unexpected warnings: %v", output.Warnings)
+	}
+
+	if _, _, err := ApplyHeaderPolicy(context.Background(), nil, ApplyHeaderPolicyInput{Config: config, Policy: `{"strip": []}`}); err == nil {
+		t.Error("Expected an error for an empty policy")
+	}
+}
@@ -0,0 +1,82 @@
+package tools
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/krakend/mcp-server/tools/validation"
+)
+
+// Status of a health check. Ok and degraded components serve requests, starting and failed
+// ones do not.
+const (
+	HealthOK       = "ok"
+	HealthDegraded = "degraded"
+	HealthStarting = "starting"
+	HealthFailed   = "failed"
+)
+
+// HealthCheck is the state of a server component, reported by the readiness endpoint
+type HealthCheck struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// Ready reports whether the component can serve requests
+func (c HealthCheck) Ready() bool {
+	return c.Status == HealthOK || c.Status == HealthDegraded
+}
+
+// DocIndexHealth reports whether the documentation index is initialized. A failed initialization
+// is degraded: searches retry it.
+func DocIndexHealth() HealthCheck {
+	check := HealthCheck{Name: "docs_index"}
+	if indexPtr := indexMgr.current.Load(); indexPtr != nil {
+		count, _ := (*indexPtr).DocCount()
+		check.Status, check.Detail = HealthOK, fmt.Sprintf("%d chunks indexed", count)
+		return check
+	}
+	if warmup, running := docSearchWarmupProgress(time.Now()); running {
+		check.Status, check.Detail = HealthStarting, (&indexWarmingUpError{warmup: warmup}).Error()
+		return check
+	}
+	if err := lastWarmupError(); err != nil {
+		check.Status, check.Detail = HealthDegraded, "initialization failed, retried on the next search: "+err.Error()
+		return check
+	}
+	check.Status, check.Detail = HealthStarting, "not initialized yet"
+	return check
+}
+
+// FeatureDataHealth reports whether the feature catalog and edition matrix are loaded
+func FeatureDataHealth() HealthCheck {
+	check := HealthCheck{Name: "feature_data"}
+	catalog, matrix := featureCatalog, editionMatrix
+	if catalog == nil || matrix == nil {
+		check.Status, check.Detail = HealthStarting, "feature catalog not loaded yet"
+		return check
+	}
+	check.Status, check.Detail = HealthOK, fmt.Sprintf("feature catalog v%s with %d features", catalog.Version, len(catalog.Features))
+	return check
+}
+
+// ValidationHealth reports the validation executors found by the environment detection. Without
+// krakend nor Docker validation is degraded to JSON Schema checks.
+func ValidationHealth() HealthCheck {
+	check := HealthCheck{Name: "validation_environment"}
+	native, docker, detected := validation.DetectedBackends()
+	switch {
+	case !detected:
+		check.Status, check.Detail = HealthStarting, "environment not detected yet"
+	case native && docker:
+		check.Status, check.Detail = HealthOK, "krakend binary and Docker available"
+	case native:
+		check.Status, check.Detail = HealthOK, "krakend binary available"
+	case docker:
+		check.Status, check.Detail = HealthOK, "Docker available"
+	default:
+		check.Status, check.Detail = HealthDegraded, "neither krakend nor Docker available: JSON Schema checks only"
+	}
+	return check
+}
@@ -0,0 +1,48 @@
+package tools
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/krakend/mcp-server/internal/indexing"
+)
+
+func TestDocIndexHealth(t *testing.T) {
+	t.Run("warming up", func(t *testing.T) {
+		simulateWarmup(t, warmupCopying, 0, nil)
+		check := DocIndexHealth()
+		if check.Status != HealthStarting || check.Ready() || !strings.Contains(check.Detail, "copying the index") {
+			t.Errorf("Unexpected check: %+v", check)
+		}
+	})
+	t.Run("failed", func(t *testing.T) {
+		simulateWarmup(t, warmupCopying, 0, errors.New("disk full"))
+		docSearchWarmup.mu.Lock()
+		docSearchWarmup.running = false
+		docSearchWarmup.mu.Unlock()
+		check := DocIndexHealth()
+		if check.Status != HealthDegraded || !check.Ready() || !strings.Contains(check.Detail, "disk full") {
+			t.Errorf("Unexpected check: %+v", check)
+		}
+	})
+	t.Run("ready", func(t *testing.T) {
+		useTestDocIndex(t, []indexing.DocChunk{{ID: "1", Content: "Rate limit"}})
+		if check := DocIndexHealth(); check.Status != HealthOK || check.Detail != "1 chunks indexed" {
+			t.Errorf("Unexpected check: %+v", check)
+		}
+	})
+}
+
+func TestFeatureDataHealth(t *testing.T) {
+	setMockFeatureFetcher(t, minimalFeatureYAML)
+	if check := FeatureDataHealth(); check.Status != HealthStarting || check.Ready() {
+		t.Errorf("Unexpected check before loading: %+v", check)
+	}
+	if err := LoadFeatureData(); err != nil {
+		t.Fatal(err)
+	}
+	if check := FeatureDataHealth(); check.Status != HealthOK || !strings.Contains(check.Detail, "feature catalog v") {
+		t.Errorf("Unexpected check: %+v", check)
+	}
+}
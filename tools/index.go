@@ -1,6 +1,11 @@
 package tools
 
-import "github.com/blevesearch/bleve/v2"
+import (
+	"errors"
+	"sync"
+
+	"github.com/blevesearch/bleve/v2"
+)
 
 // Index is an interface that abstracts bleve.Index operations
 // This allows for easier testing with mocks
@@ -15,9 +20,16 @@ type Index interface {
 	Close() error
 }
 
-// bleveIndexWrapper wraps a bleve.Index to implement our Index interface
+// errIndexClosed is returned by the operations on an index closed by a refresh or the shutdown
+var errIndexClosed = errors.New("documentation index closed")
+
+// bleveIndexWrapper wraps a bleve.Index to implement our Index interface. Close waits for the
+// operations in flight on this index, so a refresh closes the replaced index without waiting
+// for the searches running on the new one.
 type bleveIndexWrapper struct {
-	index bleve.Index
+	index  bleve.Index
+	mu     sync.RWMutex // Held for reading by operations, for writing by Close
+	closed bool
 }
 
 // NewBleveIndexWrapper wraps a bleve.Index
@@ -26,13 +38,29 @@ func NewBleveIndexWrapper(index bleve.Index) Index {
 }
 
 func (w *bleveIndexWrapper) Search(req *bleve.SearchRequest) (*bleve.SearchResult, error) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if w.closed {
+		return nil, errIndexClosed
+	}
 	return w.index.Search(req)
 }
 
 func (w *bleveIndexWrapper) DocCount() (uint64, error) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if w.closed {
+		return 0, errIndexClosed
+	}
 	return w.index.DocCount()
 }
 
 func (w *bleveIndexWrapper) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return nil
+	}
+	w.closed = true
 	return w.index.Close()
 }
@@ -0,0 +1,290 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/blevesearch/bleve/v2"
+	"github.com/krakend/mcp-server/internal/pages"
+	"github.com/krakend/mcp-server/internal/toolkit"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Knowledge result types, one per federated source
+const (
+	knowledgeDoc             = "doc"             // Documentation index
+	knowledgeFeature         = "feature"         // Feature catalog
+	knowledgeExample         = "example"         // Configuration presets and catalog examples
+	knowledgeTroubleshooting = "troubleshooting" // Known errors dataset
+)
+
+var knowledgeTypes = []string{knowledgeDoc, knowledgeFeature, knowledgeExample, knowledgeTroubleshooting}
+
+// SearchKnowledgeInput defines input for search_knowledge tool
+type SearchKnowledgeInput struct {
+	Query      string   `json:"query" jsonschema:"What to look for: a question, a feature, a namespace or an error message"`
+	Types      []string `json:"types,omitempty" jsonschema:"Restrict results to doc, feature, example and/or troubleshooting (optional, defaults to all)"`
+	MaxResults int      `json:"max_results,omitempty" jsonschema:"Maximum number of results (optional, defaults to 10, max 20)"`
+	PageSize   int      `json:"page_size,omitempty" jsonschema:"Results per page (optional, pages are otherwise only split to stay under client message limits)"`
+	PageToken  string   `json:"page_token,omitempty" jsonschema:"next_page_token of a previous call, retrieving the following page (the other arguments are then ignored)"`
+}
+
+// KnowledgeResult is a typed result of the federated search
+type KnowledgeResult struct {
+	ID        string      `json:"id"`   // Result id for report_search_feedback
+	Type      string      `json:"type"` // doc, feature, example or troubleshooting
+	Title     string      `json:"title"`
+	Content   string      `json:"content"`
+	URL       string      `json:"url,omitempty"`
+	UpdatedAt string      `json:"updated_at,omitempty"` // When the documentation page last changed (doc results)
+	Namespace string      `json:"namespace,omitempty"`
+	Edition   string      `json:"edition,omitempty"`
+	Example   interface{} `json:"example,omitempty"` // Configuration of example results
+	Score     float64     `json:"score"`             // Relevance normalized to 0-1 within each source, adjusted by search feedback
+}
+
+// SearchKnowledgeOutput defines output for search_knowledge tool
+type SearchKnowledgeOutput struct {
+	Query    string            `json:"query"`
+	Results  []KnowledgeResult `json:"results"`
+	Counts   map[string]int    `json:"counts"` // Matches per type before truncation
+	Docs     DocsSnapshot      `json:"docs"`
+	Warnings []string          `json:"warnings,omitempty"` // Sources that could not be queried
+	Page     *pages.Info       `json:"page,omitempty"`     // Results are paginated: use page.next_page_token for the next ones
+}
+
+// queryTerms splits a query into lowercase terms, ignoring punctuation around them
+func queryTerms(query string) []string {
+	var terms []string
+	for _, field := range strings.Fields(strings.ToLower(query)) {
+		term := strings.Trim(field, `.,;:!?()[]{}"'`)
+		if len(term) > 1 && !slices.Contains(terms, term) {
+			terms = append(terms, term)
+		}
+	}
+	return terms
+}
+
+// termScore scores a text by the share of query terms it contains, titles counting double
+func termScore(terms []string, title, text string) float64 {
+	if len(terms) == 0 {
+		return 0
+	}
+	title, text = strings.ToLower(title), strings.ToLower(text)
+	score := 0.0
+	for _, term := range terms {
+		switch {
+		case strings.Contains(title, term):
+			score += 2
+		case strings.Contains(text, term):
+			score++
+		}
+	}
+	return score / float64(2*len(terms))
+}
+
+// searchKnowledgeDocs queries the documentation index, normalizing scores to the best hit
+func searchKnowledgeDocs(query string, size int) ([]KnowledgeResult, error) {
+	search := bleve.NewSearchRequest(bleve.NewMatchQuery(query))
+	search.Size = size
+	search.Fields = []string{"*"}
+	var hits *bleve.SearchResult
+	err := withDocIndex(func(index Index) (err error) {
+		hits, err = index.Search(search)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	results := []KnowledgeResult{}
+	for _, hit := range hits.Hits {
+		result := KnowledgeResult{ID: hit.ID, Type: knowledgeDoc, Score: 1}
+		if hits.MaxScore > 0 {
+			result.Score = hit.Score / hits.MaxScore
+		}
+		result.Title, _ = hit.Fields["breadcrumb"].(string)
+		if result.Title == "" {
+			result.Title, _ = hit.Fields["subcategory"].(string)
+		}
+		result.Content, _ = hit.Fields["content"].(string)
+		result.URL, _ = hit.Fields["url"].(string)
+		result.UpdatedAt, _ = hit.Fields["updated_at"].(string)
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// searchKnowledgeFeatures matches the feature catalog by name, namespace and description
+func searchKnowledgeFeatures(terms []string) []KnowledgeResult {
+	if featureCatalog == nil {
+		_ = LoadFeatureData()
+	}
+	if featureCatalog == nil {
+		return nil
+	}
+	var results []KnowledgeResult
+	for _, f := range featureCatalog.Features {
+		score := termScore(terms, f.Name+" "+f.Namespace, f.Category+" "+f.Description)
+		if score == 0 {
+			continue
+		}
+		results = append(results, KnowledgeResult{
+			ID:        "feature:" + f.Namespace,
+			Type:      knowledgeFeature,
+			Title:     f.Name,
+			Content:   f.Description,
+			URL:       f.DocsURL,
+			Namespace: f.Namespace,
+			Edition:   f.Edition,
+			Score:     score,
+		})
+		if f.ExampleConfig != nil {
+			results = append(results, KnowledgeResult{
+				ID:        "example:" + f.Namespace,
+				Type:      knowledgeExample,
+				Title:     f.Name + " example",
+				Content:   f.Description,
+				URL:       f.DocsURL,
+				Namespace: f.Namespace,
+				Edition:   f.Edition,
+				Example:   map[string]interface{}{f.Namespace: f.ExampleConfig},
+				Score:     score,
+			})
+		}
+	}
+	return results
+}
+
+// searchKnowledgeExamples matches the generate_basic_config presets
+func searchKnowledgeExamples(terms []string) []KnowledgeResult {
+	var results []KnowledgeResult
+	for _, name := range PresetNames() {
+		preset := configPresets[name]
+		content := preset.Description + ". " + strings.Join(preset.BestPractices, ". ")
+		score := termScore(terms, strings.ReplaceAll(name, "-", " "), content)
+		if score == 0 {
+			continue
+		}
+		results = append(results, KnowledgeResult{
+			ID:      "example:" + name,
+			Type:    knowledgeExample,
+			Title:   fmt.Sprintf("%s preset (generate_basic_config)", name),
+			Content: content,
+			Example: buildPresetConfig(preset, defaultConfigName, defaultConfigPort, defaultConfigBackend),
+			Score:   score,
+		})
+	}
+	return results
+}
+
+// searchKnowledgeErrors matches the known errors: a pasted error message is a full match
+func searchKnowledgeErrors(query string, terms []string) []KnowledgeResult {
+	var results []KnowledgeResult
+	for _, e := range knownErrors {
+		score := termScore(terms, e.Title, e.Cause+" "+e.Fix)
+		if e.Pattern.MatchString(query) {
+			score = 1
+		}
+		if score == 0 {
+			continue
+		}
+		results = append(results, KnowledgeResult{
+			ID:      "troubleshooting:" + e.ID,
+			Type:    knowledgeTroubleshooting,
+			Title:   e.Title,
+			Content: fmt.Sprintf("Cause: %s\nFix: %s", e.Cause, e.Fix),
+			URL:     e.DocsURL,
+			Score:   score,
+		})
+	}
+	return results
+}
+
+// SearchKnowledge federates a query across documentation, feature catalog, examples and known errors
+func SearchKnowledge(ctx context.Context, req *mcp.CallToolRequest, input SearchKnowledgeInput) (*mcp.CallToolResult, SearchKnowledgeOutput, error) {
+	if input.PageToken != "" {
+		output, results, page, err := pages.Continue[SearchKnowledgeOutput, KnowledgeResult](pages.Session(req), "search_knowledge", input.PageToken)
+		if err != nil {
+			return nil, SearchKnowledgeOutput{}, err
+		}
+		output.Results, output.Page = results, page
+		return nil, output, nil
+	}
+	if strings.TrimSpace(input.Query) == "" {
+		return nil, SearchKnowledgeOutput{}, fmt.Errorf("query is required")
+	}
+	types := input.Types
+	if len(types) == 0 {
+		types = knowledgeTypes
+	}
+	for _, t := range types {
+		if !slices.Contains(knowledgeTypes, t) {
+			return nil, SearchKnowledgeOutput{}, fmt.Errorf("unknown type %q (available: %s)", t, strings.Join(knowledgeTypes, ", "))
+		}
+	}
+	maxResults := input.MaxResults
+	if maxResults <= 0 || maxResults > 20 {
+		maxResults = 10
+	}
+
+	terms := queryTerms(input.Query)
+	output := SearchKnowledgeOutput{Query: input.Query, Results: []KnowledgeResult{}, Counts: map[string]int{}}
+	var results []KnowledgeResult
+	if slices.Contains(types, knowledgeDoc) {
+		docs, err := searchKnowledgeDocs(input.Query, maxResults)
+		if err != nil {
+			output.Warnings = append(output.Warnings, fmt.Sprintf("Documentation index unavailable: %v", err))
+		}
+		results = append(results, docs...)
+		output.Docs = currentDocsSnapshot(time.Now())
+	}
+	if slices.Contains(types, knowledgeFeature) || slices.Contains(types, knowledgeExample) {
+		if featureCatalog == nil {
+			_ = LoadFeatureData()
+		}
+		if featureCatalog == nil {
+			output.Warnings = append(output.Warnings, "Feature catalog unavailable: no feature or catalog example results")
+		}
+		results = append(results, searchKnowledgeFeatures(terms)...)
+	}
+	if slices.Contains(types, knowledgeExample) {
+		results = append(results, searchKnowledgeExamples(terms)...)
+	}
+	if slices.Contains(types, knowledgeTroubleshooting) {
+		results = append(results, searchKnowledgeErrors(input.Query, terms)...)
+	}
+
+	boosts := searchFeedbackBoosts(input.Query)
+	for _, r := range results {
+		if boost, ok := boosts[r.ID]; ok {
+			r.Score *= boost
+		}
+		if slices.Contains(types, r.Type) {
+			output.Results = append(output.Results, r)
+			output.Counts[r.Type]++
+		}
+	}
+	// Stable sort keeps the source order (docs first) between equal scores
+	sort.SliceStable(output.Results, func(i, j int) bool { return output.Results[i].Score > output.Results[j].Score })
+	if len(output.Results) > maxResults {
+		output.Results = output.Results[:maxResults]
+	}
+	output.Results, output.Page = pages.Paginate(pages.Session(req), "search_knowledge", output, output.Results, input.PageSize)
+	return nil, output, nil
+}
+
+// RegisterKnowledgeTools registers the federated knowledge search tool
+func RegisterKnowledgeTools(server *mcp.Server) {
+	toolkit.AddTool(server,
+		&mcp.Tool{
+			Name:        "search_knowledge",
+			Description: "Search the documentation index, the feature catalog, configuration examples and the known errors dataset in one call. Results are typed (doc, feature, example, troubleshooting) and ranked together; paste an error message to get its cause and fix. Prefer it over guessing which source to query.",
+		},
+		SearchKnowledge,
+	)
+}
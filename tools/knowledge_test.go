@@ -0,0 +1,55 @@
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/krakend/mcp-server/internal/indexing"
+)
+
+func TestSearchKnowledge(t *testing.T) {
+	setMockFeatureFetcher(t, minimalFeatureYAML)
+	useTestDocIndex(t, []indexing.DocChunk{
+		{ID: "1", Breadcrumb: "CORS > Configuration", Content: "Enable CORS with the security/cors namespace.", URL: "https://www.krakend.io/docs/service-settings/cors/"},
+		{ID: "2", Breadcrumb: "Rate limit", Content: "Limit the endpoint with qos/ratelimit/router.", URL: "https://www.krakend.io/docs/endpoints/rate-limit/"},
+	})
+
+	_, output, err := SearchKnowledge(context.Background(), nil, SearchKnowledgeInput{Query: "CORS support"})
+	if err != nil {
+		t.Fatalf("SearchKnowledge() error = %v", err)
+	}
+	if output.Counts[knowledgeDoc] != 1 || output.Counts[knowledgeFeature] != 1 || output.Counts[knowledgeTroubleshooting] == 0 {
+		t.Errorf("Expected doc, feature and troubleshooting results, got %v", output.Counts)
+	}
+	for i := 1; i < len(output.Results); i++ {
+		if output.Results[i].Score > output.Results[i-1].Score {
+			t.Errorf("Results not sorted by score: %+v", output.Results)
+		}
+	}
+
+	// A pasted error message matches its known error
+	_, output, err = SearchKnowledge(context.Background(), nil, SearchKnowledgeInput{
+		Query: `Get "users:8080/users": unsupported protocol scheme ""`,
+		Types: []string{knowledgeTroubleshooting},
+	})
+	if err != nil {
+		t.Fatalf("SearchKnowledge() error = %v", err)
+	}
+	if len(output.Results) == 0 || output.Results[0].Title != "Backend host without scheme" || output.Results[0].Score != 1 {
+		t.Errorf("Expected the scheme known error first, got %+v", output.Results)
+	}
+	for _, r := range output.Results {
+		if r.Type != knowledgeTroubleshooting {
+			t.Errorf("Expected only troubleshooting results, got %s", r.Type)
+		}
+	}
+
+	_, output, _ = SearchKnowledge(context.Background(), nil, SearchKnowledgeInput{Query: "mobile", Types: []string{knowledgeExample}})
+	if len(output.Results) != 1 || output.Results[0].Example == nil {
+		t.Errorf("Expected the mobile-bff preset example, got %+v", output.Results)
+	}
+
+	if _, _, err := SearchKnowledge(context.Background(), nil, SearchKnowledgeInput{Query: "cors", Types: []string{"blog"}}); err == nil {
+		t.Error("Expected an error for an unknown type")
+	}
+}
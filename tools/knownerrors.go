@@ -0,0 +1,97 @@
+package tools
+
+import "regexp"
+
+// knownError is a recurring KrakenD error with its cause and fix
+type knownError struct {
+	ID      string
+	Title   string
+	Pattern *regexp.Regexp // Matches the message as logged by KrakenD or returned by krakend check
+	Cause   string
+	Fix     string
+	DocsURL string
+}
+
+// knownErrors is the troubleshooting dataset: errors users hit most often when running KrakenD
+var knownErrors = []knownError{
+	{
+		ID:      "unsupported-version",
+		Title:   "Unsupported configuration version",
+		Pattern: regexp.MustCompile(`(?i)unsupported version`),
+		Cause:   "The configuration declares a \"version\" other than 3, the only format KrakenD v2 reads.",
+		Fix:     "Set \"version\": 3 at the root of the configuration and migrate v1 namespaces (github.com/devopsfaith/...) to their v2 names.",
+		DocsURL: "https://www.krakend.io/docs/configuration/structure/",
+	},
+	{
+		ID:      "json-syntax",
+		Title:   "Configuration is not valid JSON",
+		Pattern: regexp.MustCompile(`(?i)(invalid character|unexpected end of JSON input|cannot unmarshal)`),
+		Cause:   "The configuration file has a syntax error (trailing comma, missing quote or bracket) or a value of the wrong type.",
+		Fix:     "Run validate_config to locate the error; with Flexible Configuration, check the rendered output of the templates.",
+		DocsURL: "https://www.krakend.io/docs/configuration/check/",
+	},
+	{
+		ID:      "wildcard-conflict",
+		Title:   "Endpoint paths conflict in the router",
+		Pattern: regexp.MustCompile(`(?i)conflicts with existing wildcard`),
+		Cause:   "Two endpoints declare different parameter names (or a parameter and a literal) at the same position of the path, e.g. /users/{id} and /users/{name}.",
+		Fix:     "Use the same parameter name at the same position in every endpoint, or move the literal segment to a different prefix.",
+		DocsURL: "https://www.krakend.io/docs/endpoints/",
+	},
+	{
+		ID:      "unsupported-protocol-scheme",
+		Title:   "Backend host without scheme",
+		Pattern: regexp.MustCompile(`(?i)unsupported protocol scheme`),
+		Cause:   "A backend host is declared without http:// or https://.",
+		Fix:     "Declare every host with its scheme, e.g. \"host\": [\"http://users-service:8080\"].",
+		DocsURL: "https://www.krakend.io/docs/backends/",
+	},
+	{
+		ID:      "deadline-exceeded",
+		Title:   "Backend timeout",
+		Pattern: regexp.MustCompile(`(?i)context deadline exceeded`),
+		Cause:   "The backend did not answer within the endpoint timeout (2s by default).",
+		Fix:     "Raise \"timeout\" at the endpoint or service level for slow backends, or add a circuit breaker and a cache to protect them.",
+		DocsURL: "https://www.krakend.io/docs/throttling/timeouts/",
+	},
+	{
+		ID:      "invalid-status-code",
+		Title:   "Backend returned a non-2xx status",
+		Pattern: regexp.MustCompile(`(?i)invalid status code`),
+		Cause:   "By default KrakenD treats backend statuses other than 200 and 201 as errors and hides their body.",
+		Fix:     "Add backend/http with return_error_code or return_error_details to forward backend errors, or use the no-op encoding to proxy the response as is.",
+		DocsURL: "https://www.krakend.io/docs/backends/detailed-errors/",
+	},
+	{
+		ID:      "connection-refused",
+		Title:   "Backend unreachable",
+		Pattern: regexp.MustCompile(`(?i)connection refused|no such host`),
+		Cause:   "The backend host does not resolve or does not listen on the port, often localhost used from inside a container.",
+		Fix:     "Use the service name or host.docker.internal instead of localhost inside containers, and check the host with probe_backends or resolve_backends.",
+		DocsURL: "https://www.krakend.io/docs/backends/",
+	},
+	{
+		ID:      "unknown-authority",
+		Title:   "Backend TLS certificate not trusted",
+		Pattern: regexp.MustCompile(`(?i)x509: certificate signed by unknown authority`),
+		Cause:   "The backend certificate is self-signed or issued by a private CA that KrakenD does not trust.",
+		Fix:     "Add the CA to client_tls.ca_certs at the service level; allow_insecure_connections is only acceptable in development.",
+		DocsURL: "https://www.krakend.io/docs/service-settings/tls/",
+	},
+	{
+		ID:      "jwt-rejected",
+		Title:   "JWT rejected by auth/validator",
+		Pattern: regexp.MustCompile(`(?i)(token is expired|no Keys has been found|error in cryptographic primitive|invalid audience|invalid issuer)`),
+		Cause:   "The token is expired, signed with a key or algorithm that the jwk_url does not publish, or its aud/iss claims differ from the validator settings.",
+		Fix:     "Check alg, jwk_url, audience and issuer of auth/validator against the identity provider, and the clock of the gateway host.",
+		DocsURL: "https://www.krakend.io/docs/authorization/jwt-validation/",
+	},
+	{
+		ID:      "cors-preflight",
+		Title:   "CORS preflight fails",
+		Pattern: regexp.MustCompile(`(?i)(CORS|preflight|Access-Control-Allow-Origin)`),
+		Cause:   "security/cors is missing at the service level, or the origin, method or header is not in its allow lists.",
+		Fix:     "Declare security/cors in the root extra_config with the browser origins, methods and headers; do not add OPTIONS endpoints yourself.",
+		DocsURL: "https://www.krakend.io/docs/service-settings/cors/",
+	},
+}
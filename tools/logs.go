@@ -0,0 +1,336 @@
+package tools
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/krakend/mcp-server/internal/toolkit"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	defaultLogLevel        = "INFO"
+	maxLogErrorSamples     = 20
+	defaultLogTopEndpoints = 10
+)
+
+// accessLogFields documents the structured access log schema produced by configure_access_logs
+// and understood by analyze_gateway_logs
+var accessLogFields = []AccessLogField{
+	{Name: "@timestamp", Type: "string", Description: "RFC 3339 time of the log record"},
+	{Name: "@version", Type: "number", Description: "Logstash format version"},
+	{Name: "level", Type: "string", Description: "Log level: DEBUG, INFO, WARNING, ERROR or CRITICAL"},
+	{Name: "module", Type: "string", Description: "Log prefix identifying the gateway"},
+	{Name: "host", Type: "string", Description: "Hostname of the KrakenD instance"},
+	{Name: "message", Type: "string", Description: "Log message; access log records contain the fields below"},
+	{Name: "status", Type: "number", Description: "Access logs: HTTP status code returned to the client (parsed from message)"},
+	{Name: "latency", Type: "duration", Description: "Access logs: time spent serving the request (parsed from message)"},
+	{Name: "client_ip", Type: "string", Description: "Access logs: client address (parsed from message)"},
+	{Name: "method", Type: "string", Description: "Access logs: HTTP method (parsed from message)"},
+	{Name: "path", Type: "string", Description: "Access logs: requested path (parsed from message)"},
+}
+
+// accessLogPattern matches the access log line KrakenD writes for every request:
+// "... | 200 |   1.234ms |  127.0.0.1 | GET  "/path""
+var accessLogPattern = regexp.MustCompile(`\|\s*(\d{3})\s*\|\s*([0-9.]+[a-zµ]*s)\s*\|\s*([^|]*?)\s*\|\s*([A-Z]+)\s+"([^"]*)"`)
+
+// AccessLogField describes a field of the structured access log schema
+type AccessLogField struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Description string `json:"description"`
+}
+
+// ConfigureAccessLogsInput defines input for configure_access_logs tool
+type ConfigureAccessLogsInput struct {
+	Config         string   `json:"config,omitempty" jsonschema:"KrakenD configuration (JSON string or file path) to add the logging settings to (optional)"`
+	ConfigEncoding string   `json:"config_encoding,omitempty" jsonschema:"Encoding of config for large configurations: base64 or gzip+base64 (optional, defaults to a plain JSON string or file path)"`
+	Level          string   `json:"level,omitempty" jsonschema:"Log level: DEBUG, INFO, WARNING, ERROR or CRITICAL (optional, defaults to INFO; access logs need INFO or DEBUG)"`
+	SkipPaths      []string `json:"skip_paths,omitempty" jsonschema:"Paths excluded from access logs (optional, defaults to /__health)"`
+}
+
+// ConfigureAccessLogsOutput defines output for configure_access_logs tool
+type ConfigureAccessLogsOutput struct {
+	Config  map[string]interface{} `json:"config"` // Complete config, or only the logging settings when no config was given
+	Fields  []AccessLogField       `json:"fields"`
+	Example string                 `json:"example"`
+	Notes   []string               `json:"notes"`
+}
+
+// ConfigureAccessLogs configures structured JSON (logstash) logs with access logs enabled
+func ConfigureAccessLogs(ctx context.Context, req *mcp.CallToolRequest, input ConfigureAccessLogsInput) (*mcp.CallToolResult, ConfigureAccessLogsOutput, error) {
+	level := strings.ToUpper(input.Level)
+	if level == "" {
+		level = defaultLogLevel
+	}
+	switch level {
+	case "DEBUG", "INFO", "WARNING", "ERROR", "CRITICAL":
+	default:
+		return nil, ConfigureAccessLogsOutput{}, fmt.Errorf("invalid level %q (expected DEBUG, INFO, WARNING, ERROR or CRITICAL)", input.Level)
+	}
+	skipPaths := input.SkipPaths
+	if skipPaths == nil {
+		skipPaths = []string{"/__health"}
+	}
+
+	config := map[string]interface{}{}
+	if input.Config != "" {
+		content, err := readEncodedConfig(input.Config, input.ConfigEncoding)
+		if err != nil {
+			return nil, ConfigureAccessLogsOutput{}, fmt.Errorf("failed to read config: %w", err)
+		}
+		if err := json.Unmarshal([]byte(content), &config); err != nil {
+			return nil, ConfigureAccessLogsOutput{}, fmt.Errorf("invalid JSON: %w", err)
+		}
+	}
+
+	extra, _ := config["extra_config"].(map[string]interface{})
+	if extra == nil {
+		extra = map[string]interface{}{}
+		config["extra_config"] = extra
+	}
+	logging, _ := extra["telemetry/logging"].(map[string]interface{})
+	if logging == nil {
+		logging = map[string]interface{}{"prefix": "[KRAKEND]", "stdout": true}
+	}
+	logging["level"] = level
+	logging["format"] = "logstash"
+	extra["telemetry/logging"] = logging
+	extra["telemetry/logstash"] = map[string]interface{}{"enabled": true}
+
+	router, _ := extra["router"].(map[string]interface{})
+	if router == nil {
+		router = map[string]interface{}{}
+	}
+	router["disable_access_log"] = false
+	if len(skipPaths) > 0 {
+		router["logger_skip_paths"] = skipPaths
+	}
+	extra["router"] = router
+
+	output := ConfigureAccessLogsOutput{
+		Config:  config,
+		Fields:  accessLogFields,
+		Example: `{"@timestamp":"2025-01-01T12:00:00.000Z","@version":1,"level":"INFO","host":"gateway-1","module":"[KRAKEND]","message":"[AccessLog] | 200 |   1.234ms |  10.0.0.1 | GET  \"/v1/users\""}`,
+		Notes: []string{
+			"Every record is one JSON object per line, ready for Logstash, Loki, Elasticsearch or jq",
+			"Feed the logs to analyze_gateway_logs to get status, latency and error breakdowns per endpoint",
+		},
+	}
+	if level != "DEBUG" && level != "INFO" {
+		output.Notes = append(output.Notes, fmt.Sprintf("Access logs are written at INFO level and are hidden with level %s", level))
+	}
+	return nil, output, nil
+}
+
+// AnalyzeGatewayLogsInput defines input for analyze_gateway_logs tool
+type AnalyzeGatewayLogsInput struct {
+	Logs         string `json:"logs" jsonschema:"KrakenD logs: a file path or the log lines themselves (structured JSON as produced by configure_access_logs, or the default text format)"`
+	TopEndpoints int    `json:"top_endpoints,omitempty" jsonschema:"Number of endpoints reported (optional, defaults to 10)"`
+}
+
+// EndpointLogStats aggregates the access logs of one endpoint
+type EndpointLogStats struct {
+	Method       string  `json:"method"`
+	Path         string  `json:"path"`
+	Requests     int     `json:"requests"`
+	Errors       int     `json:"errors"` // 5xx responses
+	ErrorRate    float64 `json:"error_rate"`
+	P50LatencyMs float64 `json:"p50_latency_ms"`
+	P95LatencyMs float64 `json:"p95_latency_ms"`
+
+	latencies []float64
+}
+
+// AnalyzeGatewayLogsOutput defines output for analyze_gateway_logs tool
+type AnalyzeGatewayLogsOutput struct {
+	Lines          int                `json:"lines"`
+	Structured     int                `json:"structured"` // JSON records following the access log schema
+	AccessRecords  int                `json:"access_records"`
+	Unparsed       int                `json:"unparsed"`
+	From           string             `json:"from,omitempty"`
+	To             string             `json:"to,omitempty"`
+	StatusCodes    map[string]int     `json:"status_codes"`
+	Levels         map[string]int     `json:"levels"`
+	Endpoints      []EndpointLogStats `json:"endpoints"`
+	ErrorSamples   []string           `json:"error_samples"`
+	Recommendation string             `json:"recommendation,omitempty"`
+	Summary        string             `json:"summary"`
+}
+
+// logRecord is a record of the structured access log schema
+type logRecord struct {
+	Timestamp string `json:"@timestamp"`
+	Level     string `json:"level"`
+	Message   string `json:"message"`
+}
+
+// AnalyzeGatewayLogs parses KrakenD logs and reports traffic, status and latency per endpoint
+func AnalyzeGatewayLogs(ctx context.Context, req *mcp.CallToolRequest, input AnalyzeGatewayLogsInput) (*mcp.CallToolResult, AnalyzeGatewayLogsOutput, error) {
+	if strings.TrimSpace(input.Logs) == "" {
+		return nil, AnalyzeGatewayLogsOutput{}, fmt.Errorf("logs are required")
+	}
+	logs := input.Logs
+	if !strings.Contains(logs, "\n") && !strings.HasPrefix(strings.TrimSpace(logs), "{") {
+		data, err := os.ReadFile(logs)
+		if err != nil {
+			return nil, AnalyzeGatewayLogsOutput{}, fmt.Errorf("failed to read logs: %w", err)
+		}
+		logs = string(data)
+	}
+	top := input.TopEndpoints
+	if top <= 0 {
+		top = defaultLogTopEndpoints
+	}
+
+	output := AnalyzeGatewayLogsOutput{
+		StatusCodes:  map[string]int{},
+		Levels:       map[string]int{},
+		Endpoints:    []EndpointLogStats{},
+		ErrorSamples: []string{},
+	}
+	endpoints := map[string]*EndpointLogStats{}
+	var first, last time.Time
+
+	scanner := bufio.NewScanner(strings.NewReader(logs))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		output.Lines++
+
+		message, level := line, ""
+		var record logRecord
+		if strings.HasPrefix(line, "{") && json.Unmarshal([]byte(line), &record) == nil && record.Message != "" {
+			output.Structured++
+			message, level = record.Message, strings.ToUpper(record.Level)
+			if ts, err := time.Parse(time.RFC3339Nano, record.Timestamp); err == nil {
+				if first.IsZero() || ts.Before(first) {
+					first = ts
+				}
+				if ts.After(last) {
+					last = ts
+				}
+			}
+		} else {
+			level = textLogLevel(line)
+		}
+		if level != "" {
+			output.Levels[level]++
+		}
+
+		m := accessLogPattern.FindStringSubmatch(message)
+		if m == nil {
+			if level == "ERROR" || level == "CRITICAL" {
+				if len(output.ErrorSamples) < maxLogErrorSamples {
+					output.ErrorSamples = append(output.ErrorSamples, message)
+				}
+			} else if level == "" {
+				output.Unparsed++
+			}
+			continue
+		}
+
+		output.AccessRecords++
+		status, _ := strconv.Atoi(m[1])
+		output.StatusCodes[m[1]]++
+		key := m[4] + " " + m[5]
+		stats, ok := endpoints[key]
+		if !ok {
+			stats = &EndpointLogStats{Method: m[4], Path: m[5]}
+			endpoints[key] = stats
+		}
+		stats.Requests++
+		if status >= 500 {
+			stats.Errors++
+		}
+		if latency, err := time.ParseDuration(strings.Replace(m[2], "µ", "u", 1)); err == nil {
+			stats.latencies = append(stats.latencies, float64(latency.Microseconds())/1000)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, AnalyzeGatewayLogsOutput{}, fmt.Errorf("failed to read logs: %w", err)
+	}
+
+	for _, stats := range endpoints {
+		stats.ErrorRate = float64(stats.Errors) / float64(stats.Requests)
+		sort.Float64s(stats.latencies)
+		stats.P50LatencyMs = percentile(stats.latencies, 0.50)
+		stats.P95LatencyMs = percentile(stats.latencies, 0.95)
+		output.Endpoints = append(output.Endpoints, *stats)
+	}
+	sort.Slice(output.Endpoints, func(i, j int) bool {
+		a, b := output.Endpoints[i], output.Endpoints[j]
+		if a.Requests == b.Requests {
+			return a.Method+" "+a.Path < b.Method+" "+b.Path
+		}
+		return a.Requests > b.Requests
+	})
+	if len(output.Endpoints) > top {
+		output.Endpoints = output.Endpoints[:top]
+	}
+	if !first.IsZero() {
+		output.From, output.To = first.Format(time.RFC3339), last.Format(time.RFC3339)
+	}
+	if output.Structured == 0 {
+		output.Recommendation = "Logs are not structured: use configure_access_logs to emit JSON records with a stable field set"
+	}
+
+	output.Summary = fmt.Sprintf("Analyzed %d line(s): %d access log record(s) across %d endpoint(s), %d error message(s)",
+		output.Lines, output.AccessRecords, len(endpoints), output.Levels["ERROR"]+output.Levels["CRITICAL"])
+	return nil, output, nil
+}
+
+// textLogLevel extracts the level of a default-format KrakenD log line ("▶ ERROR ...")
+func textLogLevel(line string) string {
+	for _, level := range []string{"DEBUG", "INFO", "WARNING", "ERROR", "CRITICAL"} {
+		if strings.Contains(line, "▶ "+level) || strings.Contains(line, " "+level+" ") {
+			return level
+		}
+	}
+	return ""
+}
+
+// percentile returns the p-th percentile of sorted values (nearest rank)
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	i := int(float64(len(sorted))*p+0.5) - 1
+	if i < 0 {
+		i = 0
+	}
+	if i >= len(sorted) {
+		i = len(sorted) - 1
+	}
+	return sorted[i]
+}
+
+// RegisterLogTools registers the structured logging configuration and log analysis tools
+func RegisterLogTools(server *mcp.Server) {
+	toolkit.AddTool(server,
+		&mcp.Tool{
+			Name:        "configure_access_logs",
+			Description: "Configure KrakenD for structured JSON logs (logstash format) with access logs enabled, optionally merged into an existing config. Returns the documented field set of every record, which analyze_gateway_logs understands exactly.",
+		},
+		ConfigureAccessLogs,
+	)
+
+	toolkit.AddTool(server,
+		&mcp.Tool{
+			Name:        "analyze_gateway_logs",
+			Description: "Analyze KrakenD logs (a file path or the lines themselves): status code and level breakdowns, requests, 5xx rate and p50/p95 latency per endpoint, time range and error samples. Understands the structured schema produced by configure_access_logs and the default text format.",
+		},
+		AnalyzeGatewayLogs,
+	)
+}
@@ -0,0 +1,88 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestConfigureAccessLogs_MergesConfig(t *testing.T) {
+	config := `{"version": 3, "extra_config": {"telemetry/logging": {"level": "ERROR", "prefix": "[GW]"}, "router": {"return_error_msg": true}}}`
+	_, output, err := ConfigureAccessLogs(context.Background(), nil, ConfigureAccessLogsInput{Config: config})
+	if err != nil {
+		t.Fatalf("ConfigureAccessLogs() error = %v", err)
+	}
+
+	extra := output.Config["extra_config"].(map[string]interface{})
+	logging := extra["telemetry/logging"].(map[string]interface{})
+	if logging["level"] != "INFO" || logging["format"] != "logstash" || logging["prefix"] != "[GW]" {
+		t.Errorf("Unexpected logging settings %v", logging)
+	}
+	if extra["telemetry/logstash"] == nil {
+		t.Error("Expected telemetry/logstash to be enabled")
+	}
+	router := extra["router"].(map[string]interface{})
+	if router["return_error_msg"] != true || router["disable_access_log"] != false {
+		t.Errorf("Router settings must be merged, got %v", router)
+	}
+	if len(output.Fields) == 0 {
+		t.Error("Expected the documented field set")
+	}
+}
+
+func TestConfigureAccessLogs_InvalidLevel(t *testing.T) {
+	if _, _, err := ConfigureAccessLogs(context.Background(), nil, ConfigureAccessLogsInput{Level: "verbose"}); err == nil {
+		t.Error("Expected error for an invalid level")
+	}
+}
+
+func TestAnalyzeGatewayLogs_RoundTrip(t *testing.T) {
+	// The example of configure_access_logs must be understood by analyze_gateway_logs
+	_, configured, _ := ConfigureAccessLogs(context.Background(), nil, ConfigureAccessLogsInput{})
+
+	var lines []string
+	lines = append(lines, configured.Example)
+	record := func(ts, level, message string) string {
+		data, _ := json.Marshal(map[string]interface{}{"@timestamp": ts, "@version": 1, "level": level, "module": "[KRAKEND]", "message": message})
+		return string(data)
+	}
+	for i := 0; i < 9; i++ {
+		lines = append(lines, record("2025-01-01T12:00:0"+fmt.Sprint(i)+"Z", "INFO", fmt.Sprintf(`[AccessLog] | 200 | %dms | 10.0.0.1 | GET  "/v1/users"`, i+1)))
+	}
+	lines = append(lines,
+		record("2025-01-01T12:01:00Z", "INFO", `[AccessLog] | 502 | 2.5s | 10.0.0.2 | POST "/v1/orders"`),
+		record("2025-01-01T12:01:00Z", "ERROR", "[ENDPOINT: /v1/orders] backend timeout"),
+		"not a log line",
+	)
+
+	path := filepath.Join(t.TempDir(), "krakend.log")
+	os.WriteFile(path, []byte(strings.Join(lines, "\n")), 0o644)
+
+	_, output, err := AnalyzeGatewayLogs(context.Background(), nil, AnalyzeGatewayLogsInput{Logs: path})
+	if err != nil {
+		t.Fatalf("AnalyzeGatewayLogs() error = %v", err)
+	}
+
+	if output.Lines != 13 || output.Structured != 12 || output.AccessRecords != 11 || output.Unparsed != 1 {
+		t.Errorf("Unexpected counts: %+v", output)
+	}
+	if output.StatusCodes["200"] != 10 || output.StatusCodes["502"] != 1 {
+		t.Errorf("Unexpected status codes %v", output.StatusCodes)
+	}
+	if output.Endpoints[0].Path != "/v1/users" || output.Endpoints[0].Requests != 10 {
+		t.Errorf("Expected /v1/users first, got %+v", output.Endpoints[0])
+	}
+	if orders := output.Endpoints[1]; orders.ErrorRate != 1 || orders.P95LatencyMs != 2500 {
+		t.Errorf("Unexpected /v1/orders stats %+v", orders)
+	}
+	if len(output.ErrorSamples) != 1 || output.Levels["ERROR"] != 1 {
+		t.Errorf("Expected one error sample, got %v", output.ErrorSamples)
+	}
+	if output.From != "2025-01-01T12:00:00Z" || output.To != "2025-01-01T12:01:00Z" {
+		t.Errorf("Unexpected time range %s - %s", output.From, output.To)
+	}
+}
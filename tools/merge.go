@@ -0,0 +1,407 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/krakend/mcp-server/internal/toolkit"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Strategies of merge_configs for the conflicts between configurations
+const (
+	MergeStrategyFirst = "first" // The earliest configuration declaring the setting wins
+	MergeStrategyLast  = "last"  // The latest configuration declaring the setting wins
+	MergeStrategyFail  = "fail"  // Conflicts are reported and nothing is merged
+)
+
+// mergeValidator validates the merged configuration. It can be replaced in tests.
+var mergeValidator = ValidateConfig
+
+var (
+	// mergeEndpointDefaults are the endpoint settings defaulting to the service-level value
+	mergeEndpointDefaults = []string{"timeout", "cache_ttl", "output_encoding"}
+	// mergeBackendDefaults are the backend settings defaulting to the service-level value
+	mergeBackendDefaults = []string{"host"}
+)
+
+// MergeConfigsInput defines input for merge_configs tool
+type MergeConfigsInput struct {
+	Configs    []string `json:"configs" jsonschema:"Team configurations to merge (JSON strings or file paths), in order of precedence"`
+	Strategy   string   `json:"strategy,omitempty" jsonschema:"How conflicting service-level settings, service namespaces and colliding endpoints are reconciled: first (default, the earliest configuration wins), last (the latest configuration wins) or fail (report the conflicts without merging)"`
+	OutputFile string   `json:"output_file,omitempty" jsonschema:"Path where the merged configuration is written when it is valid (optional)"`
+}
+
+// MergeConflict is a service-level setting or namespace declared with different values
+type MergeConflict struct {
+	Setting string            `json:"setting"` // JSON path
+	Values  map[string]string `json:"values"`  // Value per configuration declaring it
+	Kept    string            `json:"kept,omitempty"`
+}
+
+// MergedEndpoint locates an endpoint in the configuration declaring it
+type MergedEndpoint struct {
+	Config   string `json:"config"`
+	Endpoint string `json:"endpoint"`
+}
+
+// EndpointCollision is a pair of endpoints of different configurations the router cannot serve together
+type EndpointCollision struct {
+	Method    string           `json:"method"`
+	Endpoints []MergedEndpoint `json:"endpoints"`
+	Problem   string           `json:"problem"`
+	Kept      string           `json:"kept,omitempty"` // Configuration whose endpoint was kept
+}
+
+// MergeConfigsOutput defines output for merge_configs tool
+type MergeConfigsOutput struct {
+	Configs      []string            `json:"configs"`
+	Strategy     string              `json:"strategy"`
+	Merged       bool                `json:"merged"`
+	MergedConfig string              `json:"merged_config,omitempty"`
+	OutputFile   string              `json:"output_file,omitempty"`
+	Endpoints    int                 `json:"endpoints"`
+	Duplicates   int                 `json:"duplicates"` // Identical endpoints declared by several configurations, kept once
+	Conflicts    []MergeConflict     `json:"conflicts"`
+	Collisions   []EndpointCollision `json:"collisions"`
+	Notes        []string            `json:"notes,omitempty"`
+	Validation   *ValidationResult   `json:"validation,omitempty"`
+	Summary      string              `json:"summary"`
+}
+
+// mergeSource is an endpoint of the merge with the configuration declaring it
+type mergeSource struct {
+	config   int
+	endpoint map[string]interface{}
+}
+
+// isRouteParameter reports whether a path segment is a parameter, e.g. {id}
+func isRouteParameter(segment string) bool {
+	return strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}")
+}
+
+// routesCollide reports whether the router refuses to serve two endpoints of the same method
+// together: the same route, or parameters named differently at the same position of a shared prefix.
+// A fixed segment and a parameter at the same position, as in /users/me and /users/{id}, do not
+// collide: the router matches fixed segments first, see routeShadows.
+func routesCollide(a, b string) (string, bool) {
+	as := strings.Split(strings.Trim(a, "/"), "/")
+	bs := strings.Split(strings.Trim(b, "/"), "/")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		aParam, bParam := isRouteParameter(as[i]), isRouteParameter(bs[i])
+		switch {
+		case aParam && bParam && as[i] != bs[i]:
+			if len(as) == len(bs) && a != b {
+				return "The routes differ only in the name of their parameters", true
+			}
+			return fmt.Sprintf("Parameters %s and %s at the same position: the router rejects the second route", as[i], bs[i]), true
+		case aParam != bParam || as[i] != bs[i]:
+			return "", false
+		}
+	}
+	if len(as) != len(bs) {
+		return "", false
+	}
+	return "Both configurations declare the same route", true
+}
+
+// routeShadows reports whether two routes match the same paths apart from fixed segments of one
+// where the other has parameters, e.g. /users/me and /users/{id}. Both are served, but the route
+// with the fixed segment takes the requests to that path away from the other one.
+func routeShadows(a, b string) bool {
+	as := strings.Split(strings.Trim(a, "/"), "/")
+	bs := strings.Split(strings.Trim(b, "/"), "/")
+	if len(as) != len(bs) {
+		return false
+	}
+	shadowed := false
+	for i := range as {
+		aParam, bParam := isRouteParameter(as[i]), isRouteParameter(bs[i])
+		switch {
+		case aParam != bParam:
+			shadowed = true
+		case !aParam && as[i] != bs[i]:
+			return false
+		}
+	}
+	return shadowed
+}
+
+// mergeSettings reconciles a setting declared by several configurations. Kept is the index of the
+// configuration whose value wins, -1 when none declares it.
+func mergeSettings(names []string, values []interface{}, strategy string) (*MergeConflict, int) {
+	kept := -1
+	declared := map[string]string{}
+	distinct := map[string]bool{}
+	for i, v := range values {
+		if v == nil {
+			continue
+		}
+		if kept < 0 || strategy == MergeStrategyLast {
+			kept = i
+		}
+		declared[names[i]] = clusterValue(v)
+		distinct[declared[names[i]]] = true
+	}
+	if len(distinct) < 2 {
+		return nil, kept
+	}
+	conflict := &MergeConflict{Values: declared}
+	if strategy != MergeStrategyFail {
+		conflict.Kept = names[kept]
+	}
+	return conflict, kept
+}
+
+// inlineDefaults copies the service-level defaults of a configuration into its endpoints and backends
+// not setting them, when the merged configuration keeps another value. It returns the settings inlined.
+func inlineDefaults(config, merged map[string]interface{}, endpoint map[string]interface{}) []string {
+	var inlined []string
+	differs := func(setting string) bool {
+		value, ok := config[setting]
+		return ok && clusterValue(value) != clusterValue(merged[setting])
+	}
+	for _, setting := range mergeEndpointDefaults {
+		if _, ok := endpoint[setting]; !ok && differs(setting) {
+			endpoint[setting] = config[setting]
+			inlined = append(inlined, setting)
+		}
+	}
+	backends, _ := endpoint["backend"].([]interface{})
+	for _, b := range backends {
+		backend, ok := b.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for _, setting := range mergeBackendDefaults {
+			if hosts, ok := backend[setting].([]interface{}); (!ok || len(hosts) == 0) && differs(setting) {
+				backend[setting] = config[setting]
+				if !slices.Contains(inlined, setting) {
+					inlined = append(inlined, setting)
+				}
+			}
+		}
+	}
+	return inlined
+}
+
+// MergeConfigs composes the configurations of several teams into a single gateway
+func MergeConfigs(ctx context.Context, req *mcp.CallToolRequest, input MergeConfigsInput) (*mcp.CallToolResult, MergeConfigsOutput, error) {
+	if len(input.Configs) < 2 {
+		return nil, MergeConfigsOutput{}, fmt.Errorf("at least two configurations are required")
+	}
+	strategy := envOrDefault(input.Strategy, MergeStrategyFirst)
+	if strategy != MergeStrategyFirst && strategy != MergeStrategyLast && strategy != MergeStrategyFail {
+		return nil, MergeConfigsOutput{}, fmt.Errorf("invalid strategy %q: use first, last or fail", input.Strategy)
+	}
+
+	output := MergeConfigsOutput{
+		Configs:    []string{},
+		Strategy:   strategy,
+		Conflicts:  []MergeConflict{},
+		Collisions: []EndpointCollision{},
+	}
+	configs := make([]map[string]interface{}, 0, len(input.Configs))
+	for i, raw := range input.Configs {
+		name := dependencyConfigName(raw, i)
+		configContent, err := readConfigContent(raw)
+		if err != nil {
+			return nil, MergeConfigsOutput{}, fmt.Errorf("failed to read config %s: %w", name, err)
+		}
+		var config map[string]interface{}
+		if err := json.Unmarshal([]byte(configContent), &config); err != nil {
+			return nil, MergeConfigsOutput{}, fmt.Errorf("invalid JSON in %s: %w", name, err)
+		}
+		output.Configs = append(output.Configs, name)
+		configs = append(configs, config)
+	}
+
+	// Service-level settings, then the service namespaces one by one
+	merged := map[string]interface{}{}
+	settings := map[string]bool{}
+	namespaces := map[string]bool{}
+	for _, config := range configs {
+		for setting := range config {
+			settings[setting] = true
+		}
+		extra, _ := config["extra_config"].(map[string]interface{})
+		for namespace := range extra {
+			namespaces[namespace] = true
+		}
+	}
+	values := make([]interface{}, len(configs))
+	for _, setting := range slices.Sorted(maps.Keys(settings)) {
+		if setting == "endpoints" || setting == "extra_config" {
+			continue
+		}
+		for i, config := range configs {
+			values[i] = config[setting]
+		}
+		conflict, kept := mergeSettings(output.Configs, values, strategy)
+		if conflict != nil {
+			conflict.Setting = "$." + setting
+			output.Conflicts = append(output.Conflicts, *conflict)
+		}
+		if kept >= 0 {
+			merged[setting] = configs[kept][setting]
+		}
+	}
+	if len(namespaces) > 0 {
+		extra := map[string]interface{}{}
+		for _, namespace := range slices.Sorted(maps.Keys(namespaces)) {
+			for i, config := range configs {
+				service, _ := config["extra_config"].(map[string]interface{})
+				values[i] = service[namespace]
+			}
+			conflict, kept := mergeSettings(output.Configs, values, strategy)
+			if conflict != nil {
+				conflict.Setting = fmt.Sprintf("$.extra_config['%s']", namespace)
+				output.Conflicts = append(output.Conflicts, *conflict)
+			}
+			if kept >= 0 {
+				service, _ := configs[kept]["extra_config"].(map[string]interface{})
+				extra[namespace] = service[namespace]
+			}
+		}
+		merged["extra_config"] = extra
+	}
+
+	// Endpoints, in the order of the configurations: identical ones are kept once, colliding ones
+	// reconciled with the strategy
+	var kept []*mergeSource
+	for i, config := range configs {
+		endpoints, _ := config["endpoints"].([]interface{})
+		for _, ep := range endpoints {
+			endpoint, ok := ep.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			source := &mergeSource{config: i, endpoint: endpoint}
+			method := endpointMethod(endpoint)
+			route, _ := endpoint["endpoint"].(string)
+			add := true
+			for j, other := range kept {
+				if other == nil || other.config == i || endpointMethod(other.endpoint) != method {
+					continue
+				}
+				otherRoute, _ := other.endpoint["endpoint"].(string)
+				problem, collide := routesCollide(otherRoute, route)
+				if !collide {
+					if routeShadows(otherRoute, route) {
+						output.Notes = append(output.Notes, fmt.Sprintf("%s %s of %s and %s of %s overlap: the router serves both, but a path matching the fixed segments goes to that endpoint and never to the one with parameters",
+							method, otherRoute, output.Configs[other.config], route, output.Configs[i]))
+					}
+					continue
+				}
+				if otherRoute == route && clusterValue(other.endpoint) == clusterValue(endpoint) {
+					output.Duplicates++
+					add = false
+					break
+				}
+				collision := EndpointCollision{
+					Method: method,
+					Endpoints: []MergedEndpoint{
+						{Config: output.Configs[other.config], Endpoint: otherRoute},
+						{Config: output.Configs[i], Endpoint: route},
+					},
+					Problem: problem,
+				}
+				switch strategy {
+				case MergeStrategyFirst:
+					collision.Kept = output.Configs[other.config]
+					add = false
+				case MergeStrategyLast:
+					collision.Kept = output.Configs[i]
+					kept[j] = nil
+				}
+				output.Collisions = append(output.Collisions, collision)
+				if !add {
+					break
+				}
+			}
+			if add {
+				kept = append(kept, source)
+			}
+		}
+	}
+
+	conflicts := len(output.Conflicts) + len(output.Collisions)
+	if strategy == MergeStrategyFail && conflicts > 0 {
+		output.Summary = fmt.Sprintf("%d setting conflict(s) and %d endpoint collision(s) between %d configurations: nothing merged, resolve them or choose the first or last strategy",
+			len(output.Conflicts), len(output.Collisions), len(configs))
+		return nil, output, nil
+	}
+
+	endpoints := []interface{}{}
+	inlined := make([][]string, len(configs))
+	for _, source := range kept {
+		if source == nil {
+			continue
+		}
+		for _, setting := range inlineDefaults(configs[source.config], merged, source.endpoint) {
+			if !slices.Contains(inlined[source.config], setting) {
+				inlined[source.config] = append(inlined[source.config], setting)
+			}
+		}
+		endpoints = append(endpoints, source.endpoint)
+	}
+	merged["endpoints"] = endpoints
+	for i, settings := range inlined {
+		if len(settings) > 0 {
+			output.Notes = append(output.Notes, fmt.Sprintf("The service-level %s of %s was not kept: its value was copied into the endpoints and backends relying on it",
+				strings.Join(settings, ", "), output.Configs[i]))
+		}
+	}
+
+	data, err := json.MarshalIndent(merged, "", "  ")
+	if err != nil {
+		return nil, MergeConfigsOutput{}, fmt.Errorf("failed to encode the merged configuration: %w", err)
+	}
+	output.Merged = true
+	output.MergedConfig = string(data)
+	output.Endpoints = len(endpoints)
+
+	verdict := "not validated"
+	if _, result, err := mergeValidator(ctx, req, ValidateConfigInput{Config: output.MergedConfig}); err != nil {
+		output.Notes = append(output.Notes, "The merged configuration could not be validated: "+err.Error())
+	} else {
+		output.Validation = &result.ValidationResult
+		verdict = "invalid"
+		if result.Valid {
+			verdict = "valid"
+		}
+	}
+
+	// Only a valid merge replaces the output file
+	if input.OutputFile != "" && verdict == "valid" {
+		if err := os.MkdirAll(filepath.Dir(input.OutputFile), 0o755); err != nil {
+			return nil, MergeConfigsOutput{}, fmt.Errorf("failed to create %s: %w", filepath.Dir(input.OutputFile), err)
+		}
+		if err := os.WriteFile(input.OutputFile, append(data, '\n'), 0o644); err != nil {
+			return nil, MergeConfigsOutput{}, fmt.Errorf("failed to write %s: %w", input.OutputFile, err)
+		}
+		output.OutputFile = input.OutputFile
+	} else if input.OutputFile != "" {
+		output.Notes = append(output.Notes, fmt.Sprintf("%s was not written: the merged configuration is %s", input.OutputFile, verdict))
+	}
+	output.Summary = fmt.Sprintf("Merged %d configurations into %d endpoint(s) (%s): %d setting conflict(s) and %d endpoint collision(s) resolved with the %s strategy, %d duplicate endpoint(s) kept once",
+		len(configs), output.Endpoints, verdict, len(output.Conflicts), len(output.Collisions), strategy, output.Duplicates)
+	return nil, output, nil
+}
+
+// RegisterMergeTools registers the configuration composition tool
+func RegisterMergeTools(server *mcp.Server) {
+	toolkit.AddTool(server,
+		&mcp.Tool{
+			Name:        "merge_configs",
+			Description: "Compose the KrakenD configurations of several teams into a single gateway: combines their endpoints, detects route collisions (same route, or parameters named differently at the same position), reconciles conflicting service-level settings and service namespaces with an explicit strategy (first, last or fail) and validates the merged configuration.",
+		},
+		MergeConfigs,
+	)
+}
@@ -0,0 +1,225 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// useMergeValidator replaces the validation of the merged configuration, recording what it checks
+func useMergeValidator(t *testing.T, validated *string) {
+	validator := mergeValidator
+	t.Cleanup(func() { mergeValidator = validator })
+	mergeValidator = func(ctx context.Context, req *mcp.CallToolRequest, input ValidateConfigInput) (*mcp.CallToolResult, ValidateConfigOutput, error) {
+		*validated = input.Config
+		var output ValidateConfigOutput
+		output.Valid, output.Method, output.Summary = true, "schema", "Configuration is valid"
+		return nil, output, nil
+	}
+}
+
+const (
+	mergeTeamA = `{"version": 3, "port": 8080, "timeout": "3s", "host": ["http://users"],
+		"extra_config": {"security/cors": {"allow_origins": ["*"]}, "telemetry/logging": {"level": "INFO"}},
+		"endpoints": [
+			{"endpoint": "/users/{id}", "backend": [{"url_pattern": "/users/{id}"}]},
+			{"endpoint": "/health", "backend": [{"url_pattern": "/health", "host": ["http://health"]}]}
+		]}`
+	mergeTeamB = `{"version": 3, "port": 8090, "timeout": "10s", "host": ["http://orders"],
+		"extra_config": {"security/cors": {"allow_origins": ["https://shop.example.com"]}},
+		"endpoints": [
+			{"endpoint": "/users/{name}", "backend": [{"url_pattern": "/profiles/{name}"}]},
+			{"endpoint": "/orders", "timeout": "1s", "backend": [{"url_pattern": "/orders"}]},
+			{"endpoint": "/health", "backend": [{"url_pattern": "/health", "host": ["http://health"]}]}
+		]}`
+)
+
+func TestMergeConfigs(t *testing.T) {
+	var validated string
+	useMergeValidator(t, &validated)
+	out := filepath.Join(t.TempDir(), "gateway", "krakend.json")
+
+	_, output, err := MergeConfigs(context.Background(), nil, MergeConfigsInput{Configs: []string{mergeTeamA, mergeTeamB}, OutputFile: out})
+	if err != nil {
+		t.Fatalf("MergeConfigs() error = %v", err)
+	}
+	if !output.Merged || output.Strategy != MergeStrategyFirst {
+		t.Fatalf("Expected a merge with the first strategy, got %+v", output)
+	}
+	if output.Endpoints != 3 || output.Duplicates != 1 {
+		t.Errorf("Expected 3 endpoints and the shared /health kept once, got %d endpoints and %d duplicates", output.Endpoints, output.Duplicates)
+	}
+	if len(output.Collisions) != 1 || output.Collisions[0].Kept != "config-1" || !strings.Contains(output.Collisions[0].Problem, "name of their parameters") {
+		t.Errorf("Expected /users/{id} and /users/{name} to collide, got %+v", output.Collisions)
+	}
+	conflicts := map[string]MergeConflict{}
+	for _, c := range output.Conflicts {
+		conflicts[c.Setting] = c
+	}
+	for _, want := range []string{"$.port", "$.timeout", "$.host", "$.extra_config['security/cors']"} {
+		if conflicts[want].Kept != "config-1" {
+			t.Errorf("Expected %s to conflict and keep config-1, got %+v", want, output.Conflicts)
+		}
+	}
+	if _, ok := conflicts["$.version"]; ok {
+		t.Error("Both configurations declare version 3")
+	}
+	if _, ok := conflicts["$.extra_config['telemetry/logging']"]; ok {
+		t.Error("Only config-1 declares telemetry/logging")
+	}
+	if output.Validation == nil || !output.Validation.Valid || validated != output.MergedConfig {
+		t.Errorf("Expected the merged configuration to be validated, got %+v", output.Validation)
+	}
+
+	var merged map[string]interface{}
+	if err := json.Unmarshal([]byte(output.MergedConfig), &merged); err != nil {
+		t.Fatalf("Invalid merged configuration: %v", err)
+	}
+	if merged["port"] != float64(8080) || merged["timeout"] != "3s" {
+		t.Errorf("Expected the settings of config-1, got port %v timeout %v", merged["port"], merged["timeout"])
+	}
+	// /orders relied on the service timeout and host of config-2, which were not kept
+	var orders map[string]interface{}
+	for _, ep := range merged["endpoints"].([]interface{}) {
+		if endpoint := ep.(map[string]interface{}); endpoint["endpoint"] == "/orders" {
+			orders = endpoint
+		}
+	}
+	if orders == nil {
+		t.Fatal("Expected /orders in the merged configuration")
+	}
+	if orders["timeout"] != "1s" {
+		t.Errorf("Expected /orders to keep its own timeout, got %v", orders["timeout"])
+	}
+	backend := orders["backend"].([]interface{})[0].(map[string]interface{})
+	if hosts, _ := backend["host"].([]interface{}); len(hosts) != 1 || hosts[0] != "http://orders" {
+		t.Errorf("Expected the host of config-2 inlined into /orders, got %v", backend["host"])
+	}
+	if len(output.Notes) != 1 || !strings.Contains(output.Notes[0], "config-2") {
+		t.Errorf("Expected a note about the inlined defaults of config-2, got %v", output.Notes)
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil || strings.TrimSpace(string(data)) != output.MergedConfig {
+		t.Errorf("Expected the merged configuration written to %s, got %v", out, err)
+	}
+}
+
+func TestMergeConfigsStrategies(t *testing.T) {
+	var validated string
+	useMergeValidator(t, &validated)
+
+	_, last, err := MergeConfigs(context.Background(), nil, MergeConfigsInput{Configs: []string{mergeTeamA, mergeTeamB}, Strategy: "last"})
+	if err != nil {
+		t.Fatalf("MergeConfigs() error = %v", err)
+	}
+	var merged map[string]interface{}
+	if err := json.Unmarshal([]byte(last.MergedConfig), &merged); err != nil {
+		t.Fatalf("Invalid merged configuration: %v", err)
+	}
+	if merged["port"] != float64(8090) {
+		t.Errorf("Expected the port of config-2 with the last strategy, got %v", merged["port"])
+	}
+	var routes []string
+	for _, ep := range merged["endpoints"].([]interface{}) {
+		routes = append(routes, ep.(map[string]interface{})["endpoint"].(string))
+	}
+	if strings.Join(routes, " ") != "/health /users/{name} /orders" {
+		t.Errorf("Expected /users/{name} to replace /users/{id}, got %v", routes)
+	}
+
+	validated = ""
+	_, fail, err := MergeConfigs(context.Background(), nil, MergeConfigsInput{Configs: []string{mergeTeamA, mergeTeamB}, Strategy: "fail"})
+	if err != nil {
+		t.Fatalf("MergeConfigs() error = %v", err)
+	}
+	if fail.Merged || fail.MergedConfig != "" || validated != "" {
+		t.Error("Expected nothing merged nor validated with the fail strategy")
+	}
+	if len(fail.Conflicts) != 4 || len(fail.Collisions) != 1 || fail.Collisions[0].Kept != "" {
+		t.Errorf("Expected the conflicts reported without resolution, got %+v %+v", fail.Conflicts, fail.Collisions)
+	}
+
+	if _, _, err := MergeConfigs(context.Background(), nil, MergeConfigsInput{Configs: []string{mergeTeamA, mergeTeamB}, Strategy: "newest"}); err == nil {
+		t.Error("Expected an error for an unknown strategy")
+	}
+	if _, _, err := MergeConfigs(context.Background(), nil, MergeConfigsInput{Configs: []string{mergeTeamA}}); err == nil {
+		t.Error("Expected an error for a single configuration")
+	}
+}
+
+func TestRoutesCollide(t *testing.T) {
+	tests := []struct {
+		a, b    string
+		collide bool
+	}{
+		{"/users/{id}", "/users/{id}", true},
+		{"/users/{id}", "/users/{name}", true},
+		{"/users/{id}", "/users/{name}/orders", true},
+		{"/users/{id}", "/users/{id}/orders", false},
+		{"/users/{id}", "/users/me", false},
+		{"/users", "/orders", false},
+		{"/users/", "/users", true},
+	}
+	for _, tt := range tests {
+		if _, collide := routesCollide(tt.a, tt.b); collide != tt.collide {
+			t.Errorf("routesCollide(%q, %q) = %v, want %v", tt.a, tt.b, collide, tt.collide)
+		}
+	}
+}
+
+func TestRouteShadows(t *testing.T) {
+	tests := []struct {
+		a, b    string
+		shadows bool
+	}{
+		{"/users/{id}", "/users/me", true},
+		{"/users/me/orders", "/users/{id}/{resource}", true},
+		{"/users/{id}", "/users/{name}", false},
+		{"/users/{id}", "/users/me/orders", false},
+		{"/users/me", "/orders/{id}", false},
+	}
+	for _, tt := range tests {
+		if got := routeShadows(tt.a, tt.b); got != tt.shadows {
+			t.Errorf("routeShadows(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.shadows)
+		}
+	}
+}
+
+func TestMergeConfigs_InvalidNotWritten(t *testing.T) {
+	validator := mergeValidator
+	t.Cleanup(func() { mergeValidator = validator })
+	mergeValidator = func(ctx context.Context, req *mcp.CallToolRequest, input ValidateConfigInput) (*mcp.CallToolResult, ValidateConfigOutput, error) {
+		var output ValidateConfigOutput
+		output.Valid, output.Method, output.Summary = false, "schema", "Configuration is invalid"
+		return nil, output, nil
+	}
+	out := filepath.Join(t.TempDir(), "krakend.json")
+	if err := os.WriteFile(out, []byte(`{"version": 3}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	teamC := `{"version": 3, "endpoints": [{"endpoint": "/users/me", "backend": [{"url_pattern": "/me", "host": ["http://me"]}]}]}`
+
+	_, output, err := MergeConfigs(context.Background(), nil, MergeConfigsInput{Configs: []string{mergeTeamA, teamC}, OutputFile: out})
+	if err != nil {
+		t.Fatalf("MergeConfigs() error = %v", err)
+	}
+	if !output.Merged || output.OutputFile != "" {
+		t.Errorf("Expected an invalid merge not written, got merged %v output file %q", output.Merged, output.OutputFile)
+	}
+	if data, _ := os.ReadFile(out); string(data) != `{"version": 3}` {
+		t.Errorf("The output file must be kept when the merge is invalid, got %s", data)
+	}
+	notes := strings.Join(output.Notes, "\n")
+	if !strings.Contains(notes, "was not written") || !strings.Contains(notes, "GET /users/{id} of config-1 and /users/me of config-2 overlap") {
+		t.Errorf("Expected notes about the output file and the overlapping routes, got %v", output.Notes)
+	}
+	if len(output.Collisions) != 0 || output.Endpoints != 3 {
+		t.Errorf("/users/me and /users/{id} are both served, got %+v", output.Collisions)
+	}
+}
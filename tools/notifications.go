@@ -0,0 +1,109 @@
+package tools
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/krakend/mcp-server/internal/events"
+	"github.com/krakend/mcp-server/internal/notify"
+	"github.com/krakend/mcp-server/tools/validation"
+)
+
+// notificationConfigName names a configuration in notifications: its path, or "inline configuration"
+func notificationConfigName(config string) string {
+	trimmed := strings.TrimSpace(config)
+	if strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[") {
+		return "inline configuration"
+	}
+	return config
+}
+
+// EnableNotifications dispatches failed validations, audit score drops of configuration files
+// and documentation refreshes to the webhooks of the server configuration
+func EnableNotifications(d *notify.Dispatcher) {
+	validation.AddValidationObserver(validationNotifier(d))
+	validation.AddAuditObserver(auditNotifier(d))
+	notifyDocsRefresh := docsRefreshNotifier(d)
+	events.Subscribe(events.DocsRefreshed, func(e events.Event) {
+		if refresh, ok := e.Data.(docsRefresh); ok {
+			notifyDocsRefresh(refresh)
+		}
+	})
+}
+
+// validationNotifier reports the validations that found errors
+func validationNotifier(d *notify.Dispatcher) validation.ValidationObserver {
+	return func(config string, result ValidationResult) {
+		if result.Valid {
+			return
+		}
+		event := notify.Event{
+			Type:    notify.EventValidationFailed,
+			Title:   "KrakenD configuration validation failed",
+			Message: result.Summary,
+			Fields: map[string]string{
+				"config": notificationConfigName(config),
+				"errors": strconv.Itoa(len(result.Errors)),
+				"method": result.Method,
+			},
+		}
+		if len(result.Errors) > 0 {
+			event.Fields["first_error"] = fmt.Sprintf("%s: %s", result.Errors[0].Path, result.Errors[0].Message)
+		}
+		d.Dispatch(event)
+	}
+}
+
+// auditNotifier reports the audits scoring a configuration file lower than its previous audit.
+// Inline configurations have no identity across calls and are not compared.
+func auditNotifier(d *notify.Dispatcher) validation.AuditObserver {
+	var mu sync.Mutex
+	scores := map[string]int{}
+	return func(config string, result AuditSecurityOutput) {
+		name := notificationConfigName(config)
+		if name != config || result.Score == 0 {
+			return
+		}
+		mu.Lock()
+		previous, seen := scores[name]
+		scores[name] = result.Score
+		mu.Unlock()
+		if !seen || result.Score >= previous {
+			return
+		}
+		d.Dispatch(notify.Event{
+			Type:    notify.EventAuditScoreDropped,
+			Title:   "KrakenD security audit score dropped",
+			Message: fmt.Sprintf("The security score of %s dropped from %d to %d", name, previous, result.Score),
+			Fields: map[string]string{
+				"config":   name,
+				"previous": strconv.Itoa(previous),
+				"score":    strconv.Itoa(result.Score),
+				"issues":   strconv.Itoa(len(result.Issues)),
+			},
+		})
+	}
+}
+
+// docsRefreshNotifier reports the documentation refreshes
+func docsRefreshNotifier(d *notify.Dispatcher) func(refresh docsRefresh) {
+	return func(refresh docsRefresh) {
+		message := fmt.Sprintf("The documentation index was rebuilt from %d pages", refresh.Pages)
+		if refresh.Partial {
+			message = fmt.Sprintf("%d documentation page(s) changed and %d were removed", refresh.Changed, refresh.Removed)
+		}
+		d.Dispatch(notify.Event{
+			Type:    notify.EventDocsRefreshed,
+			Title:   "KrakenD documentation index refreshed",
+			Message: message,
+			Fields: map[string]string{
+				"pages":   strconv.Itoa(refresh.Pages),
+				"changed": strconv.Itoa(refresh.Changed),
+				"removed": strconv.Itoa(refresh.Removed),
+				"partial": strconv.FormatBool(refresh.Partial),
+			},
+		})
+	}
+}
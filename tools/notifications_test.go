@@ -0,0 +1,57 @@
+package tools
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/krakend/mcp-server/internal/notify"
+)
+
+func TestNotifiers(t *testing.T) {
+	var mu sync.Mutex
+	var events []notify.Event
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event notify.Event
+		if err := json.NewDecoder(r.Body).Decode(&event); err == nil {
+			mu.Lock()
+			events = append(events, event)
+			mu.Unlock()
+		}
+	}))
+	defer server.Close()
+	d, err := notify.New([]notify.Webhook{{URL: server.URL}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	validated := validationNotifier(d)
+	validated("krakend.json", ValidationResult{Valid: true})
+	validated("krakend.json", ValidationResult{Summary: "1 error", Errors: []ValidationError{{Path: "$.port", Message: "must be a number"}}})
+
+	audited := auditNotifier(d)
+	for _, score := range []int{90, 95, 70} {
+		audited("krakend.json", AuditSecurityOutput{Score: score})
+	}
+	audited(`{"version": 3}`, AuditSecurityOutput{Score: 90})
+	audited(`{"version": 3}`, AuditSecurityOutput{Score: 10})
+
+	docsRefreshNotifier(d)(docsRefresh{Pages: 10, Changed: 2, Partial: true})
+	d.Wait()
+
+	types := map[string][]notify.Event{}
+	for _, e := range events {
+		types[e.Type] = append(types[e.Type], e)
+	}
+	if len(types[notify.EventValidationFailed]) != 1 || types[notify.EventValidationFailed][0].Fields["first_error"] != "$.port: must be a number" {
+		t.Errorf("Expected one validation failure, got %+v", types[notify.EventValidationFailed])
+	}
+	if len(types[notify.EventAuditScoreDropped]) != 1 || types[notify.EventAuditScoreDropped][0].Fields["previous"] != "95" {
+		t.Errorf("Expected one score drop from 95 (inline configurations are not compared), got %+v", types[notify.EventAuditScoreDropped])
+	}
+	if len(types[notify.EventDocsRefreshed]) != 1 || types[notify.EventDocsRefreshed][0].Message != "2 documentation page(s) changed and 0 were removed" {
+		t.Errorf("Expected one docs refresh, got %+v", types[notify.EventDocsRefreshed])
+	}
+}
@@ -0,0 +1,232 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/krakend/mcp-server/internal/toolkit"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Pipeline stages, in the order a request goes through them
+const (
+	stageService = "service" // HTTP server, before routing
+	stageRouter  = "router"  // Endpoint handler, before the proxy pipe
+	stageProxy   = "proxy"   // Endpoint proxy pipe, around the merge of backend responses
+	stageBackend = "backend" // Backend proxy pipe, once per backend
+)
+
+// middlewareStep is a namespace of the ordering dataset: where it is declared and when it runs
+type middlewareStep struct {
+	Namespace   string
+	Level       string // Where it is declared: "service", "endpoint" or "backend"
+	Stage       string
+	Phase       string // "request", "response" or "both"
+	Description string
+}
+
+// middlewareOrder is the ordering dataset: middlewares in the order KrakenD applies them to a
+// request. Response-phase middlewares run in reverse order on the way back.
+var middlewareOrder = []middlewareStep{
+	{"telemetry/opentelemetry", "service", stageService, "both", "Starts the server span and request metrics"},
+	{"security/cors", "service", stageService, "request", "Answers preflight requests and adds CORS headers before routing"},
+	{"security/http", "service", stageService, "both", "Enforces allowed hosts, SSL redirects and security headers"},
+	{"qos/ratelimit/service", "service", stageService, "request", "Service-wide rate limit, applied before any endpoint"},
+	{"security/bot-detector", "endpoint", stageRouter, "request", "Rejects requests from detected bots"},
+	{"auth/validator", "endpoint", stageRouter, "request", "Validates the JWT and its roles"},
+	{"auth/api-keys", "endpoint", stageRouter, "request", "Validates the API key and its roles"},
+	{"auth/basic", "endpoint", stageRouter, "request", "Validates basic authentication credentials"},
+	{"modifier/lua-endpoint", "endpoint", stageRouter, "request", "Runs the Lua pre script on the incoming HTTP request"},
+	{"qos/ratelimit/router", "endpoint", stageRouter, "request", "Endpoint rate limit, shared and per client"},
+	{"validation/json-schema", "endpoint", stageProxy, "request", "Validates the request body against the JSON schema"},
+	{"validation/cel", "endpoint", stageProxy, "both", "Evaluates CEL expressions on the request and the merged response"},
+	{"modifier/lua-proxy", "endpoint", stageProxy, "both", "Runs the Lua pre script before the backends and post script after the merge"},
+	{"proxy", "endpoint", stageProxy, "both", "Sequential calls, static responses, shadow backends and flatmap on the merged response"},
+	{"modifier/jmespath", "endpoint", stageProxy, "response", "Transforms the merged response with JMESPath"},
+	{"qos/ratelimit/proxy", "backend", stageBackend, "request", "Limits the requests sent to the backend"},
+	{"qos/circuit-breaker", "backend", stageBackend, "request", "Stops calling the backend after consecutive failures"},
+	{"validation/cel", "backend", stageBackend, "both", "Evaluates CEL expressions on the backend request and response"},
+	{"modifier/lua-proxy", "backend", stageBackend, "both", "Runs the Lua pre and post scripts around the backend call"},
+	{"modifier/lua-backend", "backend", stageBackend, "both", "Runs the Lua pre and post scripts on the raw HTTP request and response"},
+	{"modifier/martian", "backend", stageBackend, "both", "Modifies the HTTP request and response with martian"},
+	{"auth/client-credentials", "backend", stageBackend, "request", "Obtains an OAuth token and adds it to the backend request"},
+	{"qos/http-cache", "backend", stageBackend, "both", "Serves cached backend responses"},
+	{"backend/http", "backend", stageBackend, "response", "Controls how backend errors and status codes are returned"},
+	{"proxy", "backend", stageBackend, "response", "Applies flatmap to the backend response"},
+}
+
+// pipelineStep is a middleware configured for a request path
+type pipelineStep struct {
+	middlewareStep
+	Backend int // Index of the backend for backend stage steps, -1 otherwise
+}
+
+// endpointPipeline returns the middlewares configured for an endpoint in execution order.
+// Namespaces missing from the ordering dataset are returned apart, sorted by name.
+func endpointPipeline(config, endpoint map[string]interface{}) (steps []pipelineStep, unordered []string) {
+	collect := func(extra map[string]interface{}, level string, backend int) {
+		known := map[string]bool{}
+		for _, m := range middlewareOrder {
+			if m.Level != level {
+				continue
+			}
+			known[m.Namespace] = true
+			if _, ok := extra[m.Namespace]; ok {
+				steps = append(steps, pipelineStep{middlewareStep: m, Backend: backend})
+			}
+		}
+		// Service extra_config mostly holds settings, not middlewares: only endpoint and
+		// backend namespaces missing from the dataset are worth reporting
+		if level == "service" {
+			return
+		}
+		var extraNames []string
+		for namespace := range extra {
+			if !known[namespace] {
+				extraNames = append(extraNames, namespace)
+			}
+		}
+		sort.Strings(extraNames)
+		for _, namespace := range extraNames {
+			if backend >= 0 {
+				namespace = fmt.Sprintf("%s (backend %d)", namespace, backend)
+			}
+			unordered = append(unordered, namespace)
+		}
+	}
+
+	serviceExtra, _ := config["extra_config"].(map[string]interface{})
+	collect(serviceExtra, "service", -1)
+	endpointExtra, _ := endpoint["extra_config"].(map[string]interface{})
+	collect(endpointExtra, "endpoint", -1)
+	backends, _ := endpoint["backend"].([]interface{})
+	for i, b := range backends {
+		backend, _ := b.(map[string]interface{})
+		backendExtra, _ := backend["extra_config"].(map[string]interface{})
+		collect(backendExtra, "backend", i)
+	}
+	return steps, unordered
+}
+
+// ExplainPipelineInput defines input for explain_pipeline tool
+type ExplainPipelineInput struct {
+	Config   string `json:"config" jsonschema:"KrakenD configuration (JSON string or file path)"`
+	Endpoint string `json:"endpoint" jsonschema:"Endpoint path to explain, as declared in the configuration (e.g. /users/{id})"`
+	Method   string `json:"method,omitempty" jsonschema:"Endpoint method when the path is declared for several methods (optional)"`
+}
+
+// PipelineMiddleware is a configured middleware in execution order
+type PipelineMiddleware struct {
+	Order       int    `json:"order"`
+	Namespace   string `json:"namespace"`
+	Location    string `json:"location"` // Where the namespace is declared
+	Stage       string `json:"stage"`    // "service", "router", "proxy" or "backend"
+	Phase       string `json:"phase"`    // "request", "response" or "both"
+	Backend     *int   `json:"backend,omitempty"`
+	Description string `json:"description"`
+}
+
+// ExplainPipelineOutput defines output for explain_pipeline tool
+type ExplainPipelineOutput struct {
+	Method      string               `json:"method"`
+	Endpoint    string               `json:"endpoint"`
+	Middlewares []PipelineMiddleware `json:"middlewares"`
+	Unordered   []string             `json:"unordered,omitempty"` // Namespaces missing from the ordering dataset
+	Notes       []string             `json:"notes"`
+	Explanation string               `json:"explanation"`
+}
+
+// pipelineNotes explains the interactions between the configured middlewares
+func pipelineNotes(steps []pipelineStep) []string {
+	present := map[string]bool{}
+	for _, step := range steps {
+		present[step.Level+"|"+step.Namespace] = true
+	}
+	auth := present["endpoint|auth/validator"] || present["endpoint|auth/api-keys"] || present["endpoint|auth/basic"]
+
+	notes := []string{"Response-phase middlewares run in reverse order on the way back to the client."}
+	if present["service|security/cors"] && auth {
+		notes = append(notes, "CORS preflight (OPTIONS) requests are answered before authentication, so they never need credentials.")
+	}
+	if auth && present["endpoint|qos/ratelimit/router"] {
+		notes = append(notes, "Requests rejected by authentication never reach the endpoint rate limit and do not consume it.")
+	}
+	if present["service|qos/ratelimit/service"] && present["endpoint|qos/ratelimit/router"] {
+		notes = append(notes, "The service rate limit applies first: a request can be rejected by it even when the endpoint limit has room.")
+	}
+	if present["endpoint|modifier/lua-endpoint"] && present["endpoint|qos/ratelimit/router"] {
+		notes = append(notes, "The modifier/lua-endpoint script runs before the endpoint rate limit, so headers it sets can feed a header-based limit.")
+	}
+	if present["endpoint|validation/json-schema"] {
+		notes = append(notes, "Invalid bodies are rejected by validation/json-schema after authentication and rate limits, without calling any backend.")
+	}
+	return notes
+}
+
+// ExplainPipeline lists the middlewares of an endpoint in the order KrakenD applies them
+func ExplainPipeline(ctx context.Context, req *mcp.CallToolRequest, input ExplainPipelineInput) (*mcp.CallToolResult, ExplainPipelineOutput, error) {
+	configContent, err := readConfigContent(input.Config)
+	if err != nil {
+		return nil, ExplainPipelineOutput{}, fmt.Errorf("failed to read config: %w", err)
+	}
+	var config map[string]interface{}
+	if err := json.Unmarshal([]byte(configContent), &config); err != nil {
+		return nil, ExplainPipelineOutput{}, fmt.Errorf("invalid JSON: %w", err)
+	}
+	endpoint, err := findEndpoint(config, input.Endpoint, input.Method)
+	if err != nil {
+		return nil, ExplainPipelineOutput{}, err
+	}
+
+	steps, unordered := endpointPipeline(config, endpoint)
+	output := ExplainPipelineOutput{
+		Method:      endpointMethod(endpoint),
+		Endpoint:    input.Endpoint,
+		Middlewares: []PipelineMiddleware{},
+		Unordered:   unordered,
+		Notes:       pipelineNotes(steps),
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s runs %d middleware(s):\n", output.Method, output.Endpoint, len(steps))
+	for i, step := range steps {
+		m := PipelineMiddleware{
+			Order:       i + 1,
+			Namespace:   step.Namespace,
+			Stage:       step.Stage,
+			Phase:       step.Phase,
+			Description: step.Description,
+		}
+		switch step.Level {
+		case "service":
+			m.Location = fmt.Sprintf("$.extra_config['%s']", step.Namespace)
+		case "endpoint":
+			m.Location = fmt.Sprintf("endpoint.extra_config['%s']", step.Namespace)
+		default:
+			backend := step.Backend
+			m.Backend = &backend
+			m.Location = fmt.Sprintf("endpoint.backend[%d].extra_config['%s']", backend, step.Namespace)
+		}
+		output.Middlewares = append(output.Middlewares, m)
+		fmt.Fprintf(&b, "%d. [%s] %s: %s\n", m.Order, m.Stage, m.Location, m.Description)
+	}
+	if len(unordered) > 0 {
+		fmt.Fprintf(&b, "Not in the ordering dataset (check their documentation): %s\n", strings.Join(unordered, ", "))
+	}
+	output.Explanation = b.String()
+	return nil, output, nil
+}
+
+// RegisterPipelineTools registers middleware ordering tools
+func RegisterPipelineTools(server *mcp.Server) {
+	toolkit.AddTool(server,
+		&mcp.Tool{
+			Name:        "explain_pipeline",
+			Description: "Explain in which exact order KrakenD applies the middlewares configured for an endpoint (CORS, authentication, rate limits, Lua, validation, circuit breakers, caches, martian...) at service, router, proxy and backend level, with notes on how they interact. Use it to answer questions such as whether rate limits count unauthenticated requests.",
+		},
+		ExplainPipeline,
+	)
+}
@@ -0,0 +1,51 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestExplainPipeline(t *testing.T) {
+	_, output, err := ExplainPipeline(context.Background(), nil, ExplainPipelineInput{Config: visualizeConfig, Endpoint: "/users/{id}"})
+	if err != nil {
+		t.Fatalf("ExplainPipeline() error = %v", err)
+	}
+	if output.Method != "GET" || len(output.Middlewares) != 6 {
+		t.Fatalf("Expected 6 middlewares for GET, got %+v", output)
+	}
+
+	first, last := output.Middlewares[0], output.Middlewares[5]
+	if first.Namespace != "security/cors" || first.Stage != stageService || first.Location != "$.extra_config['security/cors']" {
+		t.Errorf("Unexpected first middleware: %+v", first)
+	}
+	if last.Namespace != "qos/circuit-breaker" || last.Backend == nil || *last.Backend != 0 || last.Location != "endpoint.backend[0].extra_config['qos/circuit-breaker']" {
+		t.Errorf("Unexpected last middleware: %+v", last)
+	}
+	for i, m := range output.Middlewares {
+		if m.Order != i+1 {
+			t.Errorf("Middleware %s has order %d, want %d", m.Namespace, m.Order, i+1)
+		}
+	}
+
+	notes := strings.Join(output.Notes, "\n")
+	for _, want := range []string{"preflight", "never reach the endpoint rate limit"} {
+		if !strings.Contains(notes, want) {
+			t.Errorf("Notes missing %q: %v", want, output.Notes)
+		}
+	}
+	if !strings.Contains(output.Explanation, "custom/plugin") {
+		t.Errorf("Explanation should mention unordered namespaces:\n%s", output.Explanation)
+	}
+}
+
+func TestMiddlewareOrder_UniquePerLevel(t *testing.T) {
+	seen := map[string]bool{}
+	for _, m := range middlewareOrder {
+		key := m.Level + "|" + m.Namespace
+		if seen[key] {
+			t.Errorf("%s declared twice at %s level", m.Namespace, m.Level)
+		}
+		seen[key] = true
+	}
+}
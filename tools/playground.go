@@ -0,0 +1,238 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/krakend/mcp-server/internal/toolkit"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Layout of the KrakenD Playground: the gateway reads config/krakend and a static
+// server (fake_api) publishes the data directory as mock backends
+const (
+	playgroundConfigDir = "config/krakend"
+	playgroundDataDir   = "data"
+	playgroundFakeAPI   = "http://fake_api:8080"
+	playgroundLwanImage = "ghcr.io/lpereira/lwan:latest"
+)
+
+// ExportPlaygroundInput defines input for export_playground tool
+type ExportPlaygroundInput struct {
+	Config     string            `json:"config" jsonschema:"KrakenD configuration (JSON string or file path)"`
+	Samples    map[string]string `json:"samples,omitempty" jsonschema:"Mock responses keyed by backend url_pattern, JSON string or file path (optional, placeholders are generated otherwise)"`
+	Version    string            `json:"version,omitempty" jsonschema:"KrakenD image tag (optional, defaults to latest)"`
+	Enterprise bool              `json:"enterprise,omitempty" jsonschema:"Use the Enterprise image (optional)"`
+	OutputDir  string            `json:"output_dir,omitempty" jsonschema:"Directory where the playground is written (optional)"`
+}
+
+// PlaygroundFile is a file of the generated playground
+type PlaygroundFile struct {
+	Path    string `json:"path"` // Relative to the playground root
+	Content string `json:"content"`
+}
+
+// PlaygroundMock is a fake backend response replacing a real backend
+type PlaygroundMock struct {
+	Endpoint string `json:"endpoint"`
+	Backend  string `json:"backend"` // Original url_pattern
+	File     string `json:"file"`
+	Sampled  bool   `json:"sampled"` // false when the response is a generated placeholder
+}
+
+// ExportPlaygroundOutput defines output for export_playground tool
+type ExportPlaygroundOutput struct {
+	Files        []PlaygroundFile `json:"files"`
+	Mocks        []PlaygroundMock `json:"mocks"`
+	Redacted     []string         `json:"redacted"` // JSON paths of redacted secrets
+	Warnings     []string         `json:"warnings,omitempty"`
+	OutputDir    string           `json:"output_dir,omitempty"`
+	Instructions string           `json:"instructions"`
+}
+
+// playgroundMockFile returns the data file serving a backend, ignoring url_pattern
+// parameters so any value gets the same mock
+func playgroundMockFile(host, urlPattern string) string {
+	_, hostname, _, err := splitBackendHost(host)
+	if err != nil || hostname == "" {
+		hostname = "backend"
+	}
+	hostname = strings.TrimSuffix(hostname, ".example.invalid")
+	pattern := strings.SplitN(urlPattern, "?", 2)[0]
+	segments := []string{hostname}
+	for _, segment := range strings.Split(strings.Trim(pattern, "/"), "/") {
+		if segment == "" {
+			continue
+		}
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			segment = "_" + strings.Trim(segment, "{}") + "_"
+		}
+		segments = append(segments, segment)
+	}
+	if len(segments) == 1 {
+		segments = append(segments, "index")
+	}
+	return path.Join(segments...) + ".json"
+}
+
+// ExportPlayground packages a config and mock backends as a runnable KrakenD Playground
+func ExportPlayground(ctx context.Context, req *mcp.CallToolRequest, input ExportPlaygroundInput) (*mcp.CallToolResult, ExportPlaygroundOutput, error) {
+	configContent, err := readConfigContent(input.Config)
+	if err != nil {
+		return nil, ExportPlaygroundOutput{}, fmt.Errorf("failed to read config: %w", err)
+	}
+	var config map[string]interface{}
+	if err := json.Unmarshal([]byte(configContent), &config); err != nil {
+		return nil, ExportPlaygroundOutput{}, fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	// Playgrounds are meant to be shared: never ship secrets or real hosts
+	s := &configSanitizer{hosts: map[string]string{}, mapping: map[string]string{}, redacted: []string{}}
+	playground := s.sanitize(config, "$", "").(map[string]interface{})
+	sort.Strings(s.redacted)
+
+	output := ExportPlaygroundOutput{Files: []PlaygroundFile{}, Mocks: []PlaygroundMock{}, Redacted: s.redacted}
+	mocks := map[string]string{} // File → content
+	usedSamples := map[string]bool{}
+
+	endpoints, _ := config["endpoints"].([]interface{})
+	sanitizedEndpoints, _ := playground["endpoints"].([]interface{})
+	for i, ep := range endpoints {
+		endpoint, ok := ep.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		endpointPath, _ := endpoint["endpoint"].(string)
+		backends, _ := endpoint["backend"].([]interface{})
+		sanitizedBackends, _ := sanitizedEndpoints[i].(map[string]interface{})["backend"].([]interface{})
+		for j, b := range backends {
+			backend, ok := b.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			// Mock files are named after the host placeholders to keep real hosts private
+			sanitized := sanitizedBackends[j].(map[string]interface{})
+			urlPattern, _ := backend["url_pattern"].(string)
+			hosts, _ := sanitized["host"].([]interface{})
+			host := ""
+			if len(hosts) > 0 {
+				host, _ = hosts[0].(string)
+			}
+			file := playgroundMockFile(host, urlPattern)
+
+			mock := PlaygroundMock{Endpoint: endpointPath, Backend: urlPattern, File: playgroundDataDir + "/" + file}
+			if raw, ok := input.Samples[urlPattern]; ok {
+				content, err := readConfigContent(raw)
+				if err != nil {
+					return nil, ExportPlaygroundOutput{}, fmt.Errorf("failed to read sample for %s: %w", urlPattern, err)
+				}
+				if !json.Valid([]byte(content)) {
+					return nil, ExportPlaygroundOutput{}, fmt.Errorf("invalid JSON in sample for %s", urlPattern)
+				}
+				mocks[file] = content
+				mock.Sampled = true
+				usedSamples[urlPattern] = true
+			} else if _, exists := mocks[file]; !exists {
+				placeholder := `{"mock": true, "backend": ` + quoted(urlPattern) + `}`
+				if backend["is_collection"] == true {
+					placeholder = "[" + placeholder + "]"
+				}
+				mocks[file] = placeholder
+			}
+			output.Mocks = append(output.Mocks, mock)
+
+			sanitized["host"] = []interface{}{playgroundFakeAPI}
+			sanitized["url_pattern"] = "/" + file
+			if _, ok := sanitized["sd"]; ok {
+				delete(sanitized, "sd")
+				output.Warnings = append(output.Warnings, fmt.Sprintf("Service discovery removed from a backend of %s: the playground uses static mocks", endpointPath))
+			}
+			if encoding, ok := sanitized["encoding"].(string); ok && encoding != "json" && encoding != "safejson" && encoding != "no-op" {
+				sanitized["encoding"] = "json"
+				output.Warnings = append(output.Warnings, fmt.Sprintf("Backend encoding %s of %s replaced by json to read the mocks", encoding, endpointPath))
+			}
+		}
+	}
+	for pattern := range input.Samples {
+		if !usedSamples[pattern] {
+			output.Warnings = append(output.Warnings, fmt.Sprintf("Sample for %s matches no backend url_pattern", pattern))
+		}
+	}
+	sort.Strings(output.Warnings)
+
+	version := input.Version
+	if version == "" {
+		version = "latest"
+	}
+	// Shared playgrounds must run anywhere: use the public images, not local mirrors
+	image := "krakend:" + version
+	if input.Enterprise {
+		image = "krakend/krakend-ee:" + version
+	}
+
+	configData, _ := json.MarshalIndent(playground, "", "  ")
+	output.Files = append(output.Files,
+		PlaygroundFile{Path: "docker-compose.yml", Content: fmt.Sprintf(`services:
+  krakend:
+    image: %s
+    volumes:
+      - ./%s:/etc/krakend
+    ports:
+      - "8080:8080"
+    depends_on:
+      - fake_api
+  fake_api:
+    image: %s
+    volumes:
+      - ./%s:/wwwroot
+    ports:
+      - "8000:8080"
+`, image, playgroundConfigDir, playgroundLwanImage, playgroundDataDir)},
+		PlaygroundFile{Path: playgroundConfigDir + "/krakend.json", Content: string(configData) + "\n"},
+	)
+	files := make([]string, 0, len(mocks))
+	for file := range mocks {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+	for _, file := range files {
+		output.Files = append(output.Files, PlaygroundFile{Path: playgroundDataDir + "/" + file, Content: mocks[file] + "\n"})
+	}
+
+	output.Instructions = "Run docker compose up and call the gateway on http://localhost:8080. Mock backends are served from data/ on http://localhost:8000; edit the JSON files to reproduce the responses of your issue."
+	if input.Enterprise {
+		output.Instructions += " The Enterprise image needs a LICENSE file in config/krakend."
+	}
+	output.Files = append(output.Files, PlaygroundFile{Path: "README.md", Content: "# KrakenD Playground\n\n" + output.Instructions + "\n"})
+
+	if input.OutputDir != "" {
+		for _, f := range output.Files {
+			target := filepath.Join(input.OutputDir, filepath.FromSlash(f.Path))
+			if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+				return nil, ExportPlaygroundOutput{}, fmt.Errorf("failed to create %s: %w", filepath.Dir(target), err)
+			}
+			if err := os.WriteFile(target, []byte(f.Content), 0o644); err != nil {
+				return nil, ExportPlaygroundOutput{}, fmt.Errorf("failed to write %s: %w", target, err)
+			}
+		}
+		output.OutputDir = input.OutputDir
+	}
+	return nil, output, nil
+}
+
+// RegisterPlaygroundTools registers KrakenD Playground export tools
+func RegisterPlaygroundTools(server *mcp.Server) {
+	toolkit.AddTool(server,
+		&mcp.Tool{
+			Name:        "export_playground",
+			Description: "Package a KrakenD configuration as a runnable KrakenD Playground: docker-compose with the gateway and a static fake_api server, the config under config/krakend with secrets redacted, and one mock JSON file per backend (from provided samples or placeholders) under data. Use it to reproduce and share runnable examples of an issue.",
+		},
+		ExportPlayground,
+	)
+}
@@ -0,0 +1,77 @@
+package tools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPlaygroundMockFile(t *testing.T) {
+	tests := []struct {
+		host, pattern, want string
+	}{
+		{"http://users.internal:8080", "/users/{id}?expand=true", "users.internal/users/_id_.json"},
+		{"https://api.example.com", "/", "api.example.com/index.json"},
+		{"http://host-2.example.invalid:8000", "/orders", "host-2/orders.json"},
+	}
+	for _, tt := range tests {
+		if got := playgroundMockFile(tt.host, tt.pattern); got != tt.want {
+			t.Errorf("playgroundMockFile(%q, %q) = %q, want %q", tt.host, tt.pattern, got, tt.want)
+		}
+	}
+}
+
+func TestExportPlayground(t *testing.T) {
+	config := `{
+		"version": 3,
+		"extra_config": {"auth/api-keys": {"keys": [{"key": "s3cr3t", "roles": ["user"]}]}},
+		"endpoints": [
+			{"endpoint": "/users/{id}", "backend": [
+				{"host": ["http://users.internal"], "url_pattern": "/users/{id}", "encoding": "xml"},
+				{"host": ["http://orders.internal"], "url_pattern": "/orders", "is_collection": true, "sd": "dns"}
+			]}
+		]
+	}`
+	dir := t.TempDir()
+	_, output, err := ExportPlayground(context.Background(), nil, ExportPlaygroundInput{
+		Config:    config,
+		Samples:   map[string]string{"/users/{id}": `{"id": 1}`, "/unknown": `{}`},
+		Version:   "2.12",
+		OutputDir: dir,
+	})
+	if err != nil {
+		t.Fatalf("ExportPlayground() error = %v", err)
+	}
+
+	files := map[string]string{}
+	for _, f := range output.Files {
+		files[f.Path] = f.Content
+		if _, err := os.Stat(filepath.Join(dir, f.Path)); err != nil {
+			t.Errorf("%s not written: %v", f.Path, err)
+		}
+	}
+	if !strings.Contains(files["docker-compose.yml"], "image: krakend:2.12") {
+		t.Errorf("Unexpected docker-compose.yml:\n%s", files["docker-compose.yml"])
+	}
+	if files["data/host-1/users/_id_.json"] != "{\"id\": 1}\n" {
+		t.Errorf("Expected the sample as users mock, got %q", files["data/host-1/users/_id_.json"])
+	}
+	if !strings.HasPrefix(files["data/host-2/orders.json"], "[") {
+		t.Errorf("Collections need an array placeholder, got %q", files["data/host-2/orders.json"])
+	}
+
+	krakend := files["config/krakend/krakend.json"]
+	for _, unwanted := range []string{"s3cr3t", "users.internal", `"sd"`, `"xml"`} {
+		if strings.Contains(krakend, unwanted) {
+			t.Errorf("Playground config must not contain %s:\n%s", unwanted, krakend)
+		}
+	}
+	if !strings.Contains(krakend, `"url_pattern": "/host-1/users/_id_.json"`) || !strings.Contains(krakend, playgroundFakeAPI) {
+		t.Errorf("Backends must point to fake_api:\n%s", krakend)
+	}
+	if len(output.Redacted) == 0 || len(output.Warnings) != 3 {
+		t.Errorf("Expected redactions and 3 warnings, got %v and %v", output.Redacted, output.Warnings)
+	}
+}
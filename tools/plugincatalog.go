@@ -0,0 +1,232 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/krakend/mcp-server/internal/features"
+	"github.com/krakend/mcp-server/internal/toolkit"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"gopkg.in/yaml.v3"
+)
+
+const pluginCatalogFile = "plugins/catalog.yaml"
+
+// pluginTypes are the plugin types, loaded by the plugin/<type> namespaces
+var pluginTypes = []string{"http-server", "http-client", "req-resp-modifier"}
+
+// pluginCatalog is the loaded plugin catalog and where it came from
+var (
+	pluginCatalog       *PluginCatalog
+	pluginCatalogOrigin string
+)
+
+// PluginCatalogEntry is a known KrakenD plugin
+type PluginCatalogEntry struct {
+	Name        string   `yaml:"name" json:"name"`
+	Type        string   `yaml:"type" json:"type"`     // http-server, http-client or req-resp-modifier
+	Source      string   `yaml:"source" json:"source"` // enterprise, example or community
+	Edition     string   `yaml:"edition" json:"edition"`
+	Description string   `yaml:"description" json:"description"`
+	Tags        []string `yaml:"tags" json:"tags,omitempty"`
+	DocsURL     string   `yaml:"docs_url" json:"docs_url,omitempty"`
+	Repository  string   `yaml:"repository" json:"repository,omitempty"`
+	Notes       string   `yaml:"notes" json:"-"`
+	Usage       string   `yaml:"usage" json:"usage"` // extra_config snippet
+}
+
+// PluginCatalog is the catalog of known KrakenD plugins
+type PluginCatalog struct {
+	Plugins []PluginCatalogEntry `yaml:"plugins"`
+}
+
+// FindPluginInput defines input for find_plugin tool
+type FindPluginInput struct {
+	Query   string `json:"query,omitempty" jsonschema:"What the plugin should do, e.g. ip allowlist or rewrite urls (optional, lists every plugin when empty)"`
+	Type    string `json:"type,omitempty" jsonschema:"Plugin type: http-server, http-client or req-resp-modifier (optional)"`
+	Edition string `json:"edition,omitempty" jsonschema:"Only plugins available in this edition: ce or ee (optional)"`
+	Refresh bool   `json:"refresh,omitempty" jsonschema:"Download the catalog again from KRAKEND_MCP_PLUGIN_CATALOG_URL before searching (optional)"`
+}
+
+// PluginMatch is a catalog plugin matching the search
+type PluginMatch struct {
+	PluginCatalogEntry
+	Namespace     string   `json:"namespace"`
+	Compatibility []string `json:"compatibility"`
+}
+
+// FindPluginOutput defines output for find_plugin tool
+type FindPluginOutput struct {
+	Plugins []PluginMatch `json:"plugins"`
+	Count   int           `json:"count"`
+	Catalog string        `json:"catalog"` // embedded, cache or remote
+	Summary string        `json:"summary"`
+}
+
+// loadPluginCatalog loads the plugin catalog: the copy downloaded from KRAKEND_MCP_PLUGIN_CATALOG_URL
+// (refreshed every 7 days) or placed in the data directory, falling back to the embedded catalog
+func loadPluginCatalog(refresh bool) error {
+	localPath := filepath.Join(dataDir, pluginCatalogFile)
+	if url := os.Getenv("KRAKEND_MCP_PLUGIN_CATALOG_URL"); url != "" {
+		info, err := os.Stat(localPath)
+		if refresh || err != nil || time.Since(info.ModTime()) > featureCacheTTL {
+			err := downloadPluginCatalog(url, localPath)
+			if err == nil {
+				return loadPluginCatalogFromPath(localPath, "remote")
+			}
+			slog.Warn("Could not download plugin catalog", "error", err)
+		}
+	}
+	if _, err := os.Stat(localPath); err == nil {
+		err := loadPluginCatalogFromPath(localPath, "cache")
+		if err == nil {
+			return nil
+		}
+		slog.Warn("Using embedded plugin catalog", "error", err)
+	}
+	data, err := defaultDataProvider.ReadFile("data/" + pluginCatalogFile)
+	if err != nil {
+		return fmt.Errorf("no embedded plugin catalog available: %w", err)
+	}
+	return parseAndStorePluginCatalog(data, "embedded")
+}
+
+func downloadPluginCatalog(url, localPath string) error {
+	data, err := features.HTTPFetcher(url)
+	if err != nil {
+		return err
+	}
+	if _, err := parsePluginCatalog(data); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(localPath), 0o755); err != nil {
+		return fmt.Errorf("failed to create plugins directory: %w", err)
+	}
+	return os.WriteFile(localPath, data, 0o644)
+}
+
+func loadPluginCatalogFromPath(path, origin string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read plugin catalog: %w", err)
+	}
+	return parseAndStorePluginCatalog(data, origin)
+}
+
+func parsePluginCatalog(data []byte) (*PluginCatalog, error) {
+	var catalog PluginCatalog
+	if err := yaml.Unmarshal(data, &catalog); err != nil {
+		return nil, fmt.Errorf("failed to parse plugin catalog: %w", err)
+	}
+	if len(catalog.Plugins) == 0 {
+		return nil, fmt.Errorf("plugin catalog has no plugins")
+	}
+	return &catalog, nil
+}
+
+func parseAndStorePluginCatalog(data []byte, origin string) error {
+	catalog, err := parsePluginCatalog(data)
+	if err != nil {
+		return err
+	}
+	pluginCatalog, pluginCatalogOrigin = catalog, origin
+	return nil
+}
+
+// pluginScore ranks a plugin for the query terms: name matches first, then tags and description
+func pluginScore(plugin PluginCatalogEntry, terms []string) int {
+	score := 0
+	for _, term := range terms {
+		switch {
+		case strings.Contains(strings.ToLower(plugin.Name), term):
+			score += 3
+		case slices.ContainsFunc(plugin.Tags, func(tag string) bool { return strings.Contains(strings.ToLower(tag), term) }):
+			score += 2
+		case strings.Contains(strings.ToLower(plugin.Description), term):
+			score++
+		}
+	}
+	return score
+}
+
+// pluginCompatibility returns the compatibility notes of a plugin
+func pluginCompatibility(plugin PluginCatalogEntry) []string {
+	var notes []string
+	if plugin.Source == "enterprise" {
+		notes = append(notes, "Bundled in the KrakenD Enterprise binary: no build or plugin folder needed, not available in the Community Edition")
+	} else {
+		notes = append(notes, "Custom plugin: compile it with the exact Go and lura versions of the target KrakenD (krakend/builder image), declare the plugin folder at the root of the config, and verify the .so with check_plugin_compatibility")
+	}
+	if plugin.Type == "http-server" {
+		notes = append(notes, "Server plugins are declared in the service extra_config and run for every request before the router")
+	}
+	if plugin.Notes != "" {
+		notes = append(notes, plugin.Notes)
+	}
+	return notes
+}
+
+// FindPlugin searches the catalog of known KrakenD plugins
+func FindPlugin(ctx context.Context, req *mcp.CallToolRequest, input FindPluginInput) (*mcp.CallToolResult, FindPluginOutput, error) {
+	if input.Type != "" && !slices.Contains(pluginTypes, input.Type) {
+		return nil, FindPluginOutput{}, fmt.Errorf("invalid type %q (expected http-server, http-client or req-resp-modifier)", input.Type)
+	}
+	if pluginCatalog == nil || input.Refresh {
+		if err := loadPluginCatalog(input.Refresh); err != nil {
+			return nil, FindPluginOutput{}, fmt.Errorf("failed to load plugin catalog: %w", err)
+		}
+	}
+
+	terms := strings.Fields(strings.ToLower(input.Query))
+	type scored struct {
+		match PluginMatch
+		score int
+	}
+	var results []scored
+	for _, plugin := range pluginCatalog.Plugins {
+		if input.Type != "" && plugin.Type != input.Type {
+			continue
+		}
+		if input.Edition == "ce" && plugin.Edition == "ee" {
+			continue
+		}
+		score := pluginScore(plugin, terms)
+		if len(terms) > 0 && score == 0 {
+			continue
+		}
+		results = append(results, scored{
+			match: PluginMatch{PluginCatalogEntry: plugin, Namespace: "plugin/" + plugin.Type, Compatibility: pluginCompatibility(plugin)},
+			score: score,
+		})
+	}
+	sort.SliceStable(results, func(i, j int) bool { return results[i].score > results[j].score })
+
+	output := FindPluginOutput{Plugins: []PluginMatch{}, Catalog: pluginCatalogOrigin}
+	for _, result := range results {
+		output.Plugins = append(output.Plugins, result.match)
+	}
+	output.Count = len(output.Plugins)
+	output.Summary = fmt.Sprintf("%d of %d known plugin(s) match", output.Count, len(pluginCatalog.Plugins))
+	if output.Count == 0 {
+		output.Summary += ": try list_features for built-in namespaces, or search_documentation for writing a custom plugin"
+	}
+	return nil, output, nil
+}
+
+// RegisterPluginCatalogTools registers the plugin catalog tool
+func RegisterPluginCatalogTools(server *mcp.Server) {
+	toolkit.AddTool(server,
+		&mcp.Tool{
+			Name:        "find_plugin",
+			Description: "Search the catalog of known KrakenD plugins (Enterprise bundled plugins and reference plugins to build custom ones) by what they do, type or edition. Returns the extra_config snippet loading each plugin, its documentation and compatibility notes.",
+		},
+		FindPlugin,
+	)
+}
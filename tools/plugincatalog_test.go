@@ -0,0 +1,86 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/krakend/mcp-server/internal/features"
+)
+
+// resetPluginCatalog loads the catalog again from a temporary data directory
+func resetPluginCatalog(t *testing.T) {
+	oldDataDir, oldCatalog, oldOrigin := dataDir, pluginCatalog, pluginCatalogOrigin
+	dataDir = t.TempDir()
+	pluginCatalog, pluginCatalogOrigin = nil, ""
+	t.Cleanup(func() { dataDir, pluginCatalog, pluginCatalogOrigin = oldDataDir, oldCatalog, oldOrigin })
+}
+
+func TestFindPlugin(t *testing.T) {
+	resetPluginCatalog(t)
+	t.Setenv("KRAKEND_MCP_PLUGIN_CATALOG_URL", "")
+
+	_, output, err := FindPlugin(context.Background(), nil, FindPluginInput{Query: "ip allowlist"})
+	if err != nil {
+		t.Fatalf("FindPlugin() error = %v", err)
+	}
+	if output.Catalog != "embedded" || output.Count == 0 || output.Plugins[0].Name != "ip-filter" {
+		t.Fatalf("Expected ip-filter first from the embedded catalog, got %+v", output)
+	}
+	first := output.Plugins[0]
+	if first.Namespace != "plugin/http-server" || !strings.Contains(first.Usage, `"ip-filter"`) || len(first.Compatibility) < 2 {
+		t.Errorf("Expected the namespace, snippet and compatibility notes, got %+v", first)
+	}
+
+	_, output, err = FindPlugin(context.Background(), nil, FindPluginInput{Edition: "ce"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, plugin := range output.Plugins {
+		if plugin.Edition == "ee" {
+			t.Errorf("Expected CE plugins only, got %s", plugin.Name)
+		}
+	}
+
+	_, output, err = FindPlugin(context.Background(), nil, FindPluginInput{Type: "http-client"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, plugin := range output.Plugins {
+		if plugin.Type != "http-client" {
+			t.Errorf("Expected http-client plugins only, got %s", plugin.Name)
+		}
+	}
+
+	if _, _, err := FindPlugin(context.Background(), nil, FindPluginInput{Type: "router"}); err == nil {
+		t.Error("Expected an error for an invalid type")
+	}
+}
+
+func TestFindPluginRemoteCatalog(t *testing.T) {
+	resetPluginCatalog(t)
+	t.Setenv("KRAKEND_MCP_PLUGIN_CATALOG_URL", "https://plugins.example.com/catalog.yaml")
+	orig := features.HTTPFetcher
+	t.Cleanup(func() { features.HTTPFetcher = orig })
+	features.HTTPFetcher = func(_ string) ([]byte, error) {
+		return []byte("plugins:\n  - name: acme-geo\n    type: http-server\n    source: community\n    edition: ce\n    description: Geolocate clients\n"), nil
+	}
+
+	_, output, err := FindPlugin(context.Background(), nil, FindPluginInput{Query: "geolocate", Refresh: true})
+	if err != nil {
+		t.Fatalf("FindPlugin() error = %v", err)
+	}
+	if output.Catalog != "remote" || output.Count != 1 || output.Plugins[0].Name != "acme-geo" {
+		t.Fatalf("Expected the remote catalog, got %+v", output)
+	}
+
+	// An invalid download keeps the cached copy
+	features.HTTPFetcher = func(_ string) ([]byte, error) { return []byte("plugins: []"), nil }
+	_, output, err = FindPlugin(context.Background(), nil, FindPluginInput{Refresh: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if output.Catalog != "cache" || output.Count != 1 {
+		t.Errorf("Expected the cached catalog, got %+v", output)
+	}
+}
@@ -0,0 +1,404 @@
+package tools
+
+import (
+	"context"
+	"debug/buildinfo"
+	"debug/elf"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime/debug"
+	"slices"
+	"strings"
+
+	"github.com/krakend/mcp-server/internal/features"
+	"github.com/krakend/mcp-server/internal/toolkit"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// luraModule is the framework module every plugin shares with KrakenD
+const luraModule = "github.com/luraproject/lura/v2"
+
+// krakendGoSeries is the Go release series of the official KrakenD builds per minor version.
+// Plugins must be compiled with the exact same Go release: the patch level is only known from
+// `krakend version` or the go_version input.
+var krakendGoSeries = map[string]string{
+	"2.0":  "go1.17",
+	"2.1":  "go1.19",
+	"2.2":  "go1.19",
+	"2.3":  "go1.20",
+	"2.4":  "go1.20",
+	"2.5":  "go1.21",
+	"2.6":  "go1.22",
+	"2.7":  "go1.22",
+	"2.8":  "go1.23",
+	"2.9":  "go1.23",
+	"2.10": "go1.24",
+	"2.11": "go1.24",
+	"2.12": "go1.25",
+}
+
+// pluginRegisterers maps the symbol exported by a plugin to the namespace loading it
+var pluginRegisterers = map[string]string{
+	"HandlerRegisterer":  "plugin/http-server",
+	"ClientRegisterer":   "plugin/http-client",
+	"ModifierRegisterer": "plugin/req-resp-modifier",
+}
+
+// krakendVersionOutput returns the output of `krakend version`. It can be replaced in tests.
+var krakendVersionOutput = func(ctx context.Context) (string, error) {
+	output, err := exec.CommandContext(ctx, "krakend", "version").CombinedOutput()
+	return string(output), err
+}
+
+var (
+	krakendVersionPattern = regexp.MustCompile(`(?i)krakend version:\s*v?(\d+\.\d+)(\.\d+)?`)
+	goVersionPattern      = regexp.MustCompile(`(?i)go version:\s*(go\d+\.\d+(\.\d+)?)`)
+)
+
+// CheckPluginCompatibilityInput defines input for check_plugin_compatibility tool
+type CheckPluginCompatibilityInput struct {
+	Config         string   `json:"config" jsonschema:"KrakenD configuration as JSON string or file path"`
+	Plugins        []string `json:"plugins,omitempty" jsonschema:"Plugin .so files to inspect (optional, defaults to the files matching the plugin folder and pattern of the config)"`
+	KrakenDVersion string   `json:"krakend_version,omitempty" jsonschema:"Target KrakenD version, e.g. 2.7 (optional, defaults to the $schema version of the config, then the local krakend binary)"`
+	GoVersion      string   `json:"go_version,omitempty" jsonschema:"Exact Go version of the target KrakenD binary as printed by krakend version, e.g. go1.22.3 (optional)"`
+	Platform       string   `json:"platform,omitempty" jsonschema:"Target OS and architecture, e.g. linux/arm64 (optional, defaults to linux/amd64)"`
+}
+
+// PluginProblem is an incompatibility between a plugin and the target KrakenD
+type PluginProblem struct {
+	Severity string `json:"severity"` // "error" fails to load, "warning" may fail
+	Check    string `json:"check"`    // go_version, lura_version, platform, build_mode, cgo, registerer, load
+	Message  string `json:"message"`
+	Fix      string `json:"fix,omitempty"`
+}
+
+// PluginReport is the build metadata of a plugin and its problems
+type PluginReport struct {
+	Path        string          `json:"path"`
+	Module      string          `json:"module,omitempty"`
+	GoVersion   string          `json:"go_version,omitempty"`
+	Platform    string          `json:"platform,omitempty"`
+	BuildMode   string          `json:"build_mode,omitempty"`
+	LuraVersion string          `json:"lura_version,omitempty"`
+	Registerers []string        `json:"registerers,omitempty"` // Exported registerer symbols, e.g. HandlerRegisterer
+	Compatible  bool            `json:"compatible"`
+	Problems    []PluginProblem `json:"problems"`
+}
+
+// CheckPluginCompatibilityOutput defines output for check_plugin_compatibility tool
+type CheckPluginCompatibilityOutput struct {
+	KrakenDVersion  string          `json:"krakend_version"`
+	GoVersion       string          `json:"go_version"`        // Exact version, or release series when only the minor is known
+	GoVersionSource string          `json:"go_version_source"` // input, krakend version, release table or unknown
+	Platform        string          `json:"platform"`
+	Plugins         []PluginReport  `json:"plugins"`
+	ConfigProblems  []PluginProblem `json:"config_problems"`
+	Compatible      bool            `json:"compatible"`
+	Summary         string          `json:"summary"`
+}
+
+// pluginBuild is the build metadata read from a plugin
+type pluginBuild struct {
+	info        *debug.BuildInfo
+	registerers []string
+	symbols     bool // The symbol table was readable (not stripped)
+}
+
+// readPluginBuild reads the Go build information and the registerer symbols of a plugin
+func readPluginBuild(path string) (pluginBuild, error) {
+	info, err := buildinfo.ReadFile(path)
+	if err != nil {
+		return pluginBuild{}, err
+	}
+	build := pluginBuild{info: info}
+	f, err := elf.Open(path)
+	if err != nil {
+		return build, nil
+	}
+	defer f.Close()
+	symbols, err := f.Symbols()
+	if err != nil {
+		return build, nil
+	}
+	build.symbols = true
+	for _, symbol := range symbols {
+		for registerer := range pluginRegisterers {
+			if strings.HasSuffix(symbol.Name, "."+registerer) && !slices.Contains(build.registerers, registerer) {
+				build.registerers = append(build.registerers, registerer)
+			}
+		}
+	}
+	slices.Sort(build.registerers)
+	return build, nil
+}
+
+// buildSetting returns a setting of the build information
+func buildSetting(info *debug.BuildInfo, key string) string {
+	for _, setting := range info.Settings {
+		if setting.Key == key {
+			return setting.Value
+		}
+	}
+	return ""
+}
+
+// moduleVersion returns the version of a dependency, following replacements
+func moduleVersion(info *debug.BuildInfo, path string) string {
+	for _, dep := range info.Deps {
+		if dep.Path != path {
+			continue
+		}
+		if dep.Replace != nil {
+			return dep.Replace.Version
+		}
+		return dep.Version
+	}
+	return ""
+}
+
+// goMinor returns the release series of a Go version: go1.22.3 → go1.22
+func goMinor(version string) string {
+	parts := strings.SplitN(version, ".", 3)
+	if len(parts) < 2 {
+		return version
+	}
+	return parts[0] + "." + parts[1]
+}
+
+// checkPluginBuild compares the build metadata of a plugin with the target KrakenD
+func checkPluginBuild(path string, build pluginBuild, target CheckPluginCompatibilityOutput) PluginReport {
+	info := build.info
+	report := PluginReport{
+		Path:        path,
+		Module:      info.Main.Path,
+		GoVersion:   info.GoVersion,
+		Platform:    buildSetting(info, "GOOS") + "/" + buildSetting(info, "GOARCH"),
+		BuildMode:   buildSetting(info, "-buildmode"),
+		LuraVersion: moduleVersion(info, luraModule),
+		Registerers: build.registerers,
+		Problems:    []PluginProblem{},
+	}
+	add := func(severity, check, message, fix string) {
+		report.Problems = append(report.Problems, PluginProblem{Severity: severity, Check: check, Message: message, Fix: fix})
+	}
+
+	if report.BuildMode != "plugin" {
+		add("error", "build_mode", fmt.Sprintf("Built with -buildmode=%s, not as a plugin", envOrDefault(report.BuildMode, "exe")),
+			"Build it with go build -buildmode=plugin")
+	}
+	if report.Platform != target.Platform {
+		add("error", "platform", fmt.Sprintf("Built for %s, the target runs %s", report.Platform, target.Platform),
+			fmt.Sprintf("Build it for %s, e.g. with the krakend/builder image of the target version", target.Platform))
+	}
+	if buildSetting(info, "CGO_ENABLED") != "1" {
+		add("error", "cgo", "Built with CGO_ENABLED=0: Go plugins need cgo", "Build it with CGO_ENABLED=1")
+	}
+
+	builder := fmt.Sprintf("Build it with the krakend/builder:%s image, which ships the Go release of the official binary", target.KrakenDVersion)
+	switch {
+	case target.GoVersion == "":
+	case target.GoVersion == goMinor(target.GoVersion):
+		// Only the release series is known: the patch level must match too
+		if goMinor(info.GoVersion) != target.GoVersion {
+			add("error", "go_version", fmt.Sprintf("Compiled with %s, KrakenD %s is built with %s.x: loading fails with \"plugin was built with a different version of package\"", info.GoVersion, target.KrakenDVersion, target.GoVersion), builder)
+		} else {
+			add("warning", "go_version", fmt.Sprintf("Compiled with %s: the patch level must also match the KrakenD binary, unknown without krakend version", info.GoVersion),
+				"Compare with the Go Version printed by krakend version, or pass go_version")
+		}
+	case info.GoVersion != target.GoVersion:
+		add("error", "go_version", fmt.Sprintf("Compiled with %s, the KrakenD binary with %s: loading fails with \"plugin was built with a different version of package\"", info.GoVersion, target.GoVersion), builder)
+	}
+
+	switch wanted := "v" + target.KrakenDVersion; {
+	case report.LuraVersion == "":
+		add("warning", "lura_version", "The plugin does not depend on lura: it cannot implement the KrakenD plugin interfaces", "")
+	case target.KrakenDVersion != "" && goMinor(report.LuraVersion) != wanted:
+		add("error", "lura_version", fmt.Sprintf("Depends on lura %s, KrakenD %s ships lura %s.x: shared packages must have the same version", report.LuraVersion, target.KrakenDVersion, wanted),
+			fmt.Sprintf("Require %s %s.x in go.mod and run krakend check-plugin against its go.sum", luraModule, wanted))
+	}
+
+	if build.symbols && len(build.registerers) == 0 {
+		add("error", "registerer", "Exports none of HandlerRegisterer, ClientRegisterer or ModifierRegisterer: KrakenD cannot register it",
+			"Declare the registerer as an exported package-level variable of the main package")
+	}
+
+	report.Compatible = !slices.ContainsFunc(report.Problems, func(p PluginProblem) bool { return p.Severity == "error" })
+	return report
+}
+
+// pluginFiles lists the plugins KrakenD loads: the files of the plugin folder matching the pattern
+func pluginFiles(configPath string, config map[string]interface{}) ([]string, error) {
+	settings, _ := config["plugin"].(map[string]interface{})
+	folder, _ := settings["folder"].(string)
+	pattern, _ := settings["pattern"].(string)
+	if folder == "" {
+		return nil, nil
+	}
+	if !filepath.IsAbs(folder) && configPath != "" {
+		folder = filepath.Join(filepath.Dir(configPath), folder)
+	}
+	entries, err := os.ReadDir(folder)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read plugin folder: %w", err)
+	}
+	var files []string
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.Contains(entry.Name(), pattern) {
+			files = append(files, filepath.Join(folder, entry.Name()))
+		}
+	}
+	return files, nil
+}
+
+// usedPluginNamespaces lists the plugin namespaces used by a configuration
+func usedPluginNamespaces(config map[string]interface{}) []string {
+	var used []string
+	for _, ns := range features.FindNamespacesInConfig(config) {
+		if strings.HasPrefix(ns, "plugin/") && !slices.Contains(used, ns) {
+			used = append(used, ns)
+		}
+	}
+	slices.Sort(used)
+	return used
+}
+
+// pluginTarget resolves the KrakenD version, the Go version and the platform plugins must match
+func pluginTarget(ctx context.Context, input CheckPluginCompatibilityInput, configContent string) CheckPluginCompatibilityOutput {
+	target := CheckPluginCompatibilityOutput{
+		KrakenDVersion:  strings.TrimPrefix(input.KrakenDVersion, "v"),
+		Platform:        envOrDefault(input.Platform, "linux/amd64"),
+		GoVersionSource: "unknown",
+	}
+	if target.KrakenDVersion == "" {
+		if version := ExtractVersionFromConfig(configContent); version != "latest" {
+			target.KrakenDVersion = version
+		}
+	}
+	if input.GoVersion != "" {
+		target.GoVersion, target.GoVersionSource = input.GoVersion, "input"
+		if !strings.HasPrefix(target.GoVersion, "go") {
+			target.GoVersion = "go" + target.GoVersion
+		}
+		return target
+	}
+	// The local binary gives the exact Go release when it is the target version
+	if output, err := krakendVersionOutput(ctx); err == nil {
+		local := krakendVersionPattern.FindStringSubmatch(output)
+		goVersion := goVersionPattern.FindStringSubmatch(output)
+		if local != nil && target.KrakenDVersion == "" {
+			target.KrakenDVersion = local[1]
+		}
+		if local != nil && goVersion != nil && local[1] == target.KrakenDVersion {
+			target.GoVersion, target.GoVersionSource = goVersion[1], "krakend version"
+			return target
+		}
+	}
+	if series, ok := krakendGoSeries[target.KrakenDVersion]; ok {
+		target.GoVersion, target.GoVersionSource = series, "release table"
+	}
+	return target
+}
+
+// CheckPluginCompatibility compares the build metadata of the plugins of a configuration with
+// the Go release, lura version and platform of the target KrakenD
+func CheckPluginCompatibility(ctx context.Context, req *mcp.CallToolRequest, input CheckPluginCompatibilityInput) (*mcp.CallToolResult, CheckPluginCompatibilityOutput, error) {
+	configContent, err := readConfigContent(input.Config)
+	if err != nil {
+		return nil, CheckPluginCompatibilityOutput{}, fmt.Errorf("failed to read config: %w", err)
+	}
+	var config map[string]interface{}
+	if err := json.Unmarshal([]byte(configContent), &config); err != nil {
+		return nil, CheckPluginCompatibilityOutput{}, fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	output := pluginTarget(ctx, input, configContent)
+	output.Plugins = []PluginReport{}
+	output.ConfigProblems = []PluginProblem{}
+	if output.KrakenDVersion == "" {
+		output.ConfigProblems = append(output.ConfigProblems, PluginProblem{
+			Severity: "warning", Check: "go_version",
+			Message: "Unknown target KrakenD version: Go and lura versions were not checked",
+			Fix:     "Pass krakend_version or pin the version in the $schema of the config",
+		})
+	}
+
+	files := input.Plugins
+	if len(files) == 0 {
+		configPath := ""
+		if !strings.HasPrefix(strings.TrimSpace(input.Config), "{") {
+			configPath = input.Config
+		}
+		if files, err = pluginFiles(configPath, config); err != nil {
+			output.ConfigProblems = append(output.ConfigProblems, PluginProblem{Severity: "error", Check: "load", Message: err.Error(),
+				Fix: "Pass the plugin files, or check the folder of the plugin settings (relative paths resolve from the working directory of KrakenD)"})
+		}
+	}
+	used := usedPluginNamespaces(config)
+	if len(used) > 0 && config["plugin"] == nil {
+		output.ConfigProblems = append(output.ConfigProblems, PluginProblem{
+			Severity: "error", Check: "load",
+			Message: fmt.Sprintf("The config uses %s but declares no plugin settings: no plugin is loaded", strings.Join(used, ", ")),
+			Fix:     `Add "plugin": {"pattern": ".so", "folder": "./plugins/"} at the root of the config`,
+		})
+	}
+
+	exported := map[string]bool{}
+	stripped := false
+	for _, file := range files {
+		build, err := readPluginBuild(file)
+		if err != nil {
+			output.Plugins = append(output.Plugins, PluginReport{Path: file, Problems: []PluginProblem{{
+				Severity: "error", Check: "load", Message: "Not a Go binary: " + err.Error(),
+			}}})
+			continue
+		}
+		report := checkPluginBuild(file, build, output)
+		for _, registerer := range report.Registerers {
+			exported[pluginRegisterers[registerer]] = true
+		}
+		stripped = stripped || !build.symbols
+		output.Plugins = append(output.Plugins, report)
+	}
+	if len(files) > 0 && !stripped {
+		for _, ns := range used {
+			if !exported[ns] {
+				output.ConfigProblems = append(output.ConfigProblems, PluginProblem{
+					Severity: "error", Check: "registerer",
+					Message: fmt.Sprintf("The config uses %s but no plugin exports its registerer", ns),
+					Fix:     "Add the plugin implementing it to the plugin folder",
+				})
+			}
+		}
+	}
+
+	output.Compatible = !slices.ContainsFunc(output.ConfigProblems, func(p PluginProblem) bool { return p.Severity == "error" })
+	compatible := 0
+	for _, report := range output.Plugins {
+		if report.Compatible {
+			compatible++
+		} else {
+			output.Compatible = false
+		}
+	}
+	output.Summary = fmt.Sprintf("%d of %d plugin(s) compatible with KrakenD %s (%s, %s from %s)", compatible, len(output.Plugins),
+		envOrDefault(output.KrakenDVersion, "(unknown)"), output.Platform, envOrDefault(output.GoVersion, "unknown Go"), output.GoVersionSource)
+	if len(files) == 0 {
+		output.Summary = "No plugin to inspect: pass the plugin files or declare the plugin folder in the config"
+	}
+	return nil, output, nil
+}
+
+// RegisterPluginTools registers the plugin compatibility tool
+func RegisterPluginTools(server *mcp.Server) {
+	toolkit.AddTool(server,
+		&mcp.Tool{
+			Name:        "check_plugin_compatibility",
+			Description: "Catch \"plugin was built with a different version of package\" before deploying: reads the build metadata of the plugin .so files of a config (Go version, lura version, platform, build mode, exported registerers) and compares it with the target KrakenD version, also checking the config loads the plugins it uses.",
+		},
+		CheckPluginCompatibility,
+	)
+}
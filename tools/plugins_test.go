@@ -0,0 +1,133 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"testing"
+)
+
+// testPluginBuild is the build metadata of a plugin built for KrakenD 2.7
+func testPluginBuild(goVersion, lura string) pluginBuild {
+	return pluginBuild{
+		info: &debug.BuildInfo{
+			GoVersion: goVersion,
+			Main:      debug.Module{Path: "example.com/my-plugin"},
+			Deps:      []*debug.Module{{Path: luraModule, Version: lura}},
+			Settings: []debug.BuildSetting{
+				{Key: "-buildmode", Value: "plugin"},
+				{Key: "CGO_ENABLED", Value: "1"},
+				{Key: "GOOS", Value: "linux"},
+				{Key: "GOARCH", Value: "amd64"},
+			},
+		},
+		registerers: []string{"HandlerRegisterer"},
+		symbols:     true,
+	}
+}
+
+func TestCheckPluginBuild(t *testing.T) {
+	target := CheckPluginCompatibilityOutput{KrakenDVersion: "2.7", GoVersion: "go1.22.3", Platform: "linux/amd64"}
+	tests := []struct {
+		name   string
+		build  pluginBuild
+		target CheckPluginCompatibilityOutput
+		checks []string
+	}{
+		{"compatible", testPluginBuild("go1.22.3", "v2.7.0"), target, nil},
+		{"go patch", testPluginBuild("go1.22.5", "v2.7.0"), target, []string{"go_version"}},
+		{"lura", testPluginBuild("go1.22.3", "v2.6.1"), target, []string{"lura_version"}},
+		{"platform", testPluginBuild("go1.22.3", "v2.7.0"), CheckPluginCompatibilityOutput{KrakenDVersion: "2.7", GoVersion: "go1.22.3", Platform: "linux/arm64"}, []string{"platform"}},
+		{"series only", testPluginBuild("go1.22.3", "v2.7.0"), CheckPluginCompatibilityOutput{KrakenDVersion: "2.7", GoVersion: "go1.22", Platform: "linux/amd64"}, []string{"go_version"}},
+		{"series mismatch", testPluginBuild("go1.21.9", "v2.7.0"), CheckPluginCompatibilityOutput{KrakenDVersion: "2.7", GoVersion: "go1.22", Platform: "linux/amd64"}, []string{"go_version"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := checkPluginBuild("my-plugin.so", tt.build, tt.target)
+			if len(report.Problems) != len(tt.checks) {
+				t.Fatalf("Expected problems %v, got %+v", tt.checks, report.Problems)
+			}
+			for i, check := range tt.checks {
+				if report.Problems[i].Check != check {
+					t.Errorf("Expected %s problem, got %+v", check, report.Problems[i])
+				}
+			}
+		})
+	}
+
+	// Only the release series known: the patch level is a warning
+	report := checkPluginBuild("my-plugin.so", testPluginBuild("go1.22.3", "v2.7.0"), tests[4].target)
+	if !report.Compatible || report.Problems[0].Severity != "warning" {
+		t.Errorf("Expected a compatible plugin with a warning, got %+v", report)
+	}
+
+	build := testPluginBuild("go1.22.3", "v2.7.0")
+	build.registerers = nil
+	if report := checkPluginBuild("my-plugin.so", build, target); report.Compatible || report.Problems[0].Check != "registerer" {
+		t.Errorf("Expected a missing registerer, got %+v", report)
+	}
+}
+
+func TestCheckPluginCompatibility(t *testing.T) {
+	setMockFeatureFetcher(t, minimalFeatureYAML)
+	old := krakendVersionOutput
+	t.Cleanup(func() { krakendVersionOutput = old })
+	krakendVersionOutput = func(ctx context.Context) (string, error) {
+		return "KrakenD Version: 2.7.2\nGo Version: go1.22.4\nGlibc Version: GLIBC-2.31_buildroot\n", nil
+	}
+
+	// The test binary is a Go executable: readable metadata, but not a plugin
+	dir := t.TempDir()
+	executable, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(executable)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "test.so"), data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "broken.so"), []byte("not a plugin"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	config := fmt.Sprintf(`{"version": 3, "plugin": {"pattern": ".so", "folder": %q},
+		"endpoints": [{"endpoint": "/a", "backend": [{"url_pattern": "/a", "host": ["http://a"]}]}]}`, dir)
+
+	_, output, err := CheckPluginCompatibility(context.Background(), nil, CheckPluginCompatibilityInput{Config: config})
+	if err != nil {
+		t.Fatalf("CheckPluginCompatibility() error = %v", err)
+	}
+	if output.KrakenDVersion != "2.7" || output.GoVersion != "go1.22.4" || output.GoVersionSource != "krakend version" {
+		t.Errorf("Expected the target from krakend version, got %+v", output)
+	}
+	if output.Compatible || len(output.Plugins) != 2 {
+		t.Fatalf("Expected 2 incompatible plugins, got %+v", output)
+	}
+	var buildMode bool
+	for _, report := range output.Plugins {
+		for _, problem := range report.Problems {
+			buildMode = buildMode || (filepath.Base(report.Path) == "test.so" && problem.Check == "build_mode")
+		}
+	}
+	if !buildMode {
+		t.Errorf("Expected a build mode problem for the test binary, got %+v", output.Plugins)
+	}
+
+	// Plugins used without plugin settings, target from the release table
+	config = `{"version": 3, "$schema": "https://www.krakend.io/schema/v2.5/krakend.json", "endpoints": [{"endpoint": "/a",
+		"extra_config": {"plugin/http-server": {"name": ["my-plugin"]}}, "backend": [{"url_pattern": "/a", "host": ["http://a"]}]}]}`
+	_, output, err = CheckPluginCompatibility(context.Background(), nil, CheckPluginCompatibilityInput{Config: config})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if output.GoVersion != "go1.21" || output.GoVersionSource != "release table" {
+		t.Errorf("Expected the release series of 2.5, got %+v", output)
+	}
+	if output.Compatible || len(output.ConfigProblems) != 1 || output.ConfigProblems[0].Check != "load" {
+		t.Errorf("Expected the missing plugin settings, got %+v", output.ConfigProblems)
+	}
+}
@@ -0,0 +1,17 @@
+package tools
+
+import "log/slog"
+
+// Preload loads the data shared by every session in the background: the documentation index and
+// the feature catalog. The daemon mode calls it at startup, so no session waits for them on its
+// first calls, whatever the enabled toolsets.
+func Preload() {
+	startDocSearchWarmup()
+	go func() {
+		if err := LoadFeatureData(); err != nil {
+			slog.Warn("Could not preload the feature data", "error", err)
+			return
+		}
+		slog.Info("Feature data preloaded")
+	}()
+}
@@ -0,0 +1,280 @@
+package tools
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/krakend/mcp-server/internal/toolkit"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	defaultProbeTimeout     = 3 * time.Second
+	defaultProbeConcurrency = 8
+	defaultProbeAttempts    = 3
+	maxProbeConcurrency     = 32
+	maxProbeAttempts        = 10
+)
+
+// ProbeBackendsInput defines input for probe_backends tool
+type ProbeBackendsInput struct {
+	Config      string `json:"config" jsonschema:"KrakenD configuration (JSON string or file path)"`
+	TimeoutMs   int    `json:"timeout_ms,omitempty" jsonschema:"Per-host timeout in milliseconds (optional, defaults to 3000)"`
+	Concurrency int    `json:"concurrency,omitempty" jsonschema:"Maximum number of hosts probed at the same time (optional, defaults to 8)"`
+	Attempts    int    `json:"attempts,omitempty" jsonschema:"Number of TCP connections used to average latency (optional, defaults to 3)"`
+}
+
+// TLSProbe contains the TLS details observed for a backend host
+type TLSProbe struct {
+	Verified      bool      `json:"verified"`
+	ExpiresAt     time.Time `json:"expires_at"`
+	DaysRemaining int       `json:"days_remaining"`
+	Subject       string    `json:"subject"`
+	Error         string    `json:"error,omitempty"`
+}
+
+// BackendProbe contains the probe results for a single backend host
+type BackendProbe struct {
+	Host         string    `json:"host"`
+	Address      string    `json:"address"`
+	Reachable    bool      `json:"reachable"`
+	AvgLatencyMs float64   `json:"avg_latency_ms"`
+	Attempts     int       `json:"attempts"`
+	Failures     int       `json:"failures"`
+	HTTPStatus   int       `json:"http_status,omitempty"`
+	TLS          *TLSProbe `json:"tls,omitempty"`
+	Error        string    `json:"error,omitempty"`
+}
+
+// ProbeBackendsOutput defines output for probe_backends tool
+type ProbeBackendsOutput struct {
+	Hosts       []BackendProbe `json:"hosts"`
+	Reachable   int            `json:"reachable"`
+	Unreachable int            `json:"unreachable"`
+	Skipped     []string       `json:"skipped,omitempty"` // Hosts resolved through service discovery other than static
+	Summary     string         `json:"summary"`
+}
+
+// ProbeBackends checks reachability of every unique backend host in a configuration
+func ProbeBackends(ctx context.Context, req *mcp.CallToolRequest, input ProbeBackendsInput) (*mcp.CallToolResult, ProbeBackendsOutput, error) {
+	configContent, err := readConfigContent(input.Config)
+	if err != nil {
+		return nil, ProbeBackendsOutput{}, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	var config map[string]interface{}
+	if err := json.Unmarshal([]byte(configContent), &config); err != nil {
+		return nil, ProbeBackendsOutput{}, fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	timeout := defaultProbeTimeout
+	if input.TimeoutMs > 0 {
+		timeout = time.Duration(input.TimeoutMs) * time.Millisecond
+	}
+	concurrency := input.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultProbeConcurrency
+	}
+	if concurrency > maxProbeConcurrency {
+		concurrency = maxProbeConcurrency
+	}
+	attempts := input.Attempts
+	if attempts <= 0 {
+		attempts = defaultProbeAttempts
+	}
+	if attempts > maxProbeAttempts {
+		attempts = maxProbeAttempts
+	}
+
+	output := ProbeBackendsOutput{Hosts: []BackendProbe{}}
+
+	var hosts []string
+	for _, h := range collectBackendHosts(config) {
+		if h.SD != "static" {
+			output.Skipped = append(output.Skipped, h.Host)
+			continue
+		}
+		hosts = append(hosts, h.Host)
+	}
+
+	// One client for every host of the call: its idle connections are closed once probed
+	client := newProbeClient(timeout)
+	defer client.CloseIdleConnections()
+
+	results := make([]BackendProbe, len(hosts))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	for i, host := range hosts {
+		wg.Add(1)
+		go func(i int, host string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			hostCtx, cancel := context.WithTimeout(ctx, timeout*time.Duration(attempts+2))
+			defer cancel()
+			results[i] = probeBackendHost(hostCtx, client, host, timeout, attempts)
+		}(i, host)
+	}
+	wg.Wait()
+
+	for _, r := range results {
+		if r.Reachable {
+			output.Reachable++
+		} else {
+			output.Unreachable++
+		}
+	}
+	output.Hosts = append(output.Hosts, results...)
+	output.Summary = fmt.Sprintf("Probed %d backend host(s): %d reachable, %d unreachable", len(results), output.Reachable, output.Unreachable)
+	if len(output.Skipped) > 0 {
+		output.Summary += fmt.Sprintf(" (%d skipped: not using static service discovery)", len(output.Skipped))
+	}
+
+	return &mcp.CallToolResult{Meta: map[string]interface{}{"unreachable": output.Unreachable}}, output, nil
+}
+
+// newProbeClient returns the client of the HTTP HEAD checks, which never follows redirects
+func newProbeClient(timeout time.Duration) *http.Client {
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true}, // Verification is reported separately
+		},
+		CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+	}
+}
+
+// probeBackendHost performs TCP, TLS and HTTP HEAD checks against a single host
+func probeBackendHost(ctx context.Context, client *http.Client, host string, timeout time.Duration, attempts int) BackendProbe {
+	probe := BackendProbe{Host: host}
+
+	scheme, hostname, port, err := splitBackendHost(host)
+	if err != nil {
+		probe.Error = err.Error()
+		return probe
+	}
+	probe.Address = backendAddress(hostname, port)
+
+	// TCP: average connection latency over several attempts
+	dialer := &net.Dialer{Timeout: timeout}
+	var total time.Duration
+	var lastErr error
+	for i := 0; i < attempts; i++ {
+		probe.Attempts++
+		start := time.Now()
+		conn, err := dialer.DialContext(ctx, "tcp", probe.Address)
+		if err != nil {
+			probe.Failures++
+			lastErr = err
+			continue
+		}
+		total += time.Since(start)
+		conn.Close()
+	}
+
+	succeeded := probe.Attempts - probe.Failures
+	if succeeded == 0 {
+		probe.Error = fmt.Sprintf("TCP connection failed: %v", lastErr)
+		return probe
+	}
+	probe.Reachable = true
+	probe.AvgLatencyMs = float64(total.Microseconds()) / float64(succeeded) / 1000
+
+	// TLS: certificate expiry and chain verification
+	if scheme == "https" || scheme == "wss" {
+		probe.TLS = probeTLS(ctx, dialer, probe.Address, hostname)
+	}
+
+	// HTTP: HEAD request against the host root
+	if scheme == "http" || scheme == "https" {
+		headReq, err := http.NewRequestWithContext(ctx, http.MethodHead, fmt.Sprintf("%s://%s/", scheme, probe.Address), nil)
+		if err == nil {
+			if resp, err := client.Do(headReq); err == nil {
+				probe.HTTPStatus = resp.StatusCode
+				resp.Body.Close()
+			} else {
+				probe.Error = fmt.Sprintf("HTTP HEAD failed: %v", err)
+			}
+		}
+	}
+
+	return probe
+}
+
+// probeTLS connects to address and inspects the leaf certificate presented by the server
+func probeTLS(ctx context.Context, dialer *net.Dialer, address, serverName string) *TLSProbe {
+	certs, err := fetchPeerCertificates(ctx, dialer, address, serverName)
+	if err != nil {
+		return &TLSProbe{Error: err.Error()}
+	}
+
+	leaf := certs[0]
+	result := &TLSProbe{
+		ExpiresAt:     leaf.NotAfter,
+		DaysRemaining: daysUntil(leaf.NotAfter),
+		Subject:       leaf.Subject.String(),
+	}
+
+	if err := verifyCertificateChain(certs, serverName); err != nil {
+		result.Error = err.Error()
+	} else {
+		result.Verified = true
+	}
+
+	return result
+}
+
+// fetchPeerCertificates performs a TLS handshake without verification and returns the presented chain
+func fetchPeerCertificates(ctx context.Context, dialer *net.Dialer, address, serverName string) ([]*x509.Certificate, error) {
+	tlsDialer := &tls.Dialer{
+		NetDialer: dialer,
+		Config:    &tls.Config{ServerName: serverName, InsecureSkipVerify: true},
+	}
+	conn, err := tlsDialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return nil, fmt.Errorf("TLS handshake failed: %w", err)
+	}
+	defer conn.Close()
+
+	state := conn.(*tls.Conn).ConnectionState()
+	if len(state.PeerCertificates) == 0 {
+		return nil, fmt.Errorf("server presented no certificates")
+	}
+	return state.PeerCertificates, nil
+}
+
+// verifyCertificateChain verifies the leaf certificate against the system roots.
+// The remaining certificates in the chain are used as intermediates.
+func verifyCertificateChain(certs []*x509.Certificate, dnsName string) error {
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+	_, err := certs[0].Verify(x509.VerifyOptions{DNSName: dnsName, Intermediates: intermediates})
+	return err
+}
+
+// daysUntil returns the number of whole days remaining until t
+func daysUntil(t time.Time) int {
+	return int(time.Until(t).Hours() / 24)
+}
+
+// RegisterProbeTools registers backend probing tools
+func RegisterProbeTools(server *mcp.Server) {
+	toolkit.AddTool(server,
+		&mcp.Tool{
+			Name:        "probe_backends",
+			Description: "Concurrently probe every unique backend host in a KrakenD configuration with TCP, TLS and HTTP HEAD checks. Reports reachability, average TCP latency, HTTP status and TLS certificate expiry per host. Supports per-host timeouts and a concurrency limit.",
+		},
+		ProbeBackends,
+	)
+}
@@ -0,0 +1,131 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCollectBackendHosts(t *testing.T) {
+	config := map[string]interface{}{
+		"endpoints": []interface{}{
+			map[string]interface{}{
+				"backend": []interface{}{
+					map[string]interface{}{"host": []interface{}{"http://a.example.com", "http://b.example.com"}},
+				},
+			},
+			map[string]interface{}{
+				"backend": []interface{}{
+					map[string]interface{}{"host": []interface{}{"http://a.example.com"}},
+					map[string]interface{}{"host": []interface{}{"service.consul"}, "sd": "dns"},
+				},
+			},
+		},
+	}
+
+	hosts := collectBackendHosts(config)
+	if len(hosts) != 3 {
+		t.Fatalf("Expected 3 unique hosts, got %d: %v", len(hosts), hosts)
+	}
+	if hosts[0].Host != "http://a.example.com" || len(hosts[0].Locations) != 2 {
+		t.Errorf("Expected a.example.com used in 2 locations, got %+v", hosts[0])
+	}
+	if hosts[2].SD != "dns" {
+		t.Errorf("Expected dns service discovery, got %s", hosts[2].SD)
+	}
+}
+
+func TestSplitBackendHost(t *testing.T) {
+	tests := []struct {
+		host, scheme, hostname, port string
+	}{
+		{"http://example.com", "http", "example.com", "80"},
+		{"https://example.com", "https", "example.com", "443"},
+		{"example.com:8080", "http", "example.com", "8080"},
+	}
+	for _, tt := range tests {
+		scheme, hostname, port, err := splitBackendHost(tt.host)
+		if err != nil {
+			t.Fatalf("splitBackendHost(%q) error = %v", tt.host, err)
+		}
+		if scheme != tt.scheme || hostname != tt.hostname || port != tt.port {
+			t.Errorf("splitBackendHost(%q) = %s %s %s", tt.host, scheme, hostname, port)
+		}
+	}
+}
+
+func TestProbeBackends(t *testing.T) {
+	httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer httpServer.Close()
+	tlsServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer tlsServer.Close()
+
+	// Reserve a port and close it so the connection is refused
+	listener, _ := net.Listen("tcp", "127.0.0.1:0")
+	closedAddr := listener.Addr().String()
+	listener.Close()
+
+	config := fmt.Sprintf(`{"endpoints": [{"backend": [
+		{"host": [%q, %q, "http://%s"]},
+		{"host": ["my-service"], "sd": "dns"}
+	]}]}`, httpServer.URL, tlsServer.URL, closedAddr)
+
+	_, output, err := ProbeBackends(context.Background(), nil, ProbeBackendsInput{Config: config, TimeoutMs: 500, Attempts: 2})
+	if err != nil {
+		t.Fatalf("ProbeBackends() error = %v", err)
+	}
+
+	if output.Reachable != 2 || output.Unreachable != 1 {
+		t.Errorf("Expected 2 reachable and 1 unreachable, got %d/%d", output.Reachable, output.Unreachable)
+	}
+	if len(output.Skipped) != 1 {
+		t.Errorf("Expected 1 skipped host, got %v", output.Skipped)
+	}
+
+	for _, h := range output.Hosts {
+		switch h.Host {
+		case httpServer.URL:
+			if h.HTTPStatus != http.StatusOK {
+				t.Errorf("Expected HTTP 200 for %s, got %d", h.Host, h.HTTPStatus)
+			}
+		case tlsServer.URL:
+			if h.TLS == nil || h.TLS.ExpiresAt.IsZero() {
+				t.Errorf("Expected TLS details for %s, got %+v", h.Host, h.TLS)
+			}
+		}
+	}
+}
+
+func TestProbeBackends_ClosesIdleConnections(t *testing.T) {
+	var open atomic.Int32
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+		switch state {
+		case http.StateNew:
+			open.Add(1)
+		case http.StateClosed, http.StateHijacked:
+			open.Add(-1)
+		}
+	}
+	server.Start()
+	defer server.Close()
+
+	config := fmt.Sprintf(`{"endpoints": [{"backend": [{"host": [%q]}]}]}`, server.URL)
+	_, output, err := ProbeBackends(context.Background(), nil, ProbeBackendsInput{Config: config, TimeoutMs: 500, Attempts: 1})
+	if err != nil || output.Hosts[0].HTTPStatus != http.StatusOK {
+		t.Fatalf("ProbeBackends() = %+v, %v", output, err)
+	}
+	// The TCP probes close their connections right away; the HEAD request must not stay idle
+	deadline := time.Now().Add(5 * time.Second)
+	for open.Load() != 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if n := open.Load(); n != 0 {
+		t.Errorf("Expected every connection to be closed after probing, %d still open", n)
+	}
+}
@@ -0,0 +1,378 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/krakend/mcp-server/internal/toolkit"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Kinds of definitions removed by prune_config
+const (
+	pruneKindEndpoint    = "endpoint"
+	pruneKindAsyncAgent  = "async_agent"
+	pruneKindBackend     = "backend"
+	pruneKindHost        = "host"
+	pruneKindExtraConfig = "extra_config"
+	pruneKindSetting     = "setting"
+)
+
+// endpointDefaults are the root settings every endpoint inherits unless it declares its own
+var endpointDefaults = []string{"timeout", "cache_ttl", "output_encoding"}
+
+// PruneConfigInput defines input for prune_config tool
+type PruneConfigInput struct {
+	Config              string   `json:"config" jsonschema:"KrakenD configuration as JSON string or file path"`
+	DecommissionedHosts []string `json:"decommissioned_hosts,omitempty" jsonschema:"Backend hosts no longer in service, e.g. http://legacy:8080 or legacy (any scheme and port) (optional)"`
+}
+
+// PruneRemoval is a definition removed from the configuration
+type PruneRemoval struct {
+	Path   string `json:"path"` // JSON path in the input configuration
+	Kind   string `json:"kind"` // endpoint, async_agent, backend, host, extra_config or setting
+	Reason string `json:"reason"`
+}
+
+// PruneConfigOutput defines output for prune_config tool
+type PruneConfigOutput struct {
+	Config   map[string]interface{} `json:"config"`
+	Removed  []PruneRemoval         `json:"removed"`
+	Warnings []string               `json:"warnings"`
+	Summary  string                 `json:"summary"`
+}
+
+// hostMatcher matches backend hosts against a list of decommissioned hosts. Entries without
+// scheme nor port match the hostname on any port.
+type hostMatcher struct {
+	entries []decommissionedHost
+}
+
+type decommissionedHost struct {
+	raw      string
+	hostname string
+	port     string // Empty to match any port
+	used     bool
+}
+
+func newHostMatcher(hosts []string) *hostMatcher {
+	m := &hostMatcher{}
+	for _, raw := range hosts {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+		entry := decommissionedHost{raw: raw}
+		if _, hostname, port, err := splitBackendHost(raw); err == nil {
+			entry.hostname = strings.ToLower(hostname)
+			withScheme := raw
+			if !strings.Contains(raw, "://") {
+				withScheme = "http://" + raw
+			}
+			if u, _ := url.Parse(withScheme); strings.Contains(raw, "://") || u.Port() != "" {
+				entry.port = port
+			}
+		}
+		m.entries = append(m.entries, entry)
+	}
+	return m
+}
+
+// matches reports whether a backend host is decommissioned
+func (m *hostMatcher) matches(host string) bool {
+	_, hostname, port, err := splitBackendHost(host)
+	found := false
+	for i := range m.entries {
+		e := &m.entries[i]
+		switch {
+		case e.raw == host:
+		case err == nil && e.hostname == strings.ToLower(hostname) && (e.port == "" || e.port == port):
+		default:
+			continue
+		}
+		e.used = true
+		found = true
+	}
+	return found
+}
+
+// unused returns the decommissioned hosts no backend points to
+func (m *hostMatcher) unused() []string {
+	unused := []string{}
+	for _, e := range m.entries {
+		if !e.used {
+			unused = append(unused, e.raw)
+		}
+	}
+	return unused
+}
+
+// pruneHosts removes the decommissioned hosts of a host list, returning the hosts kept and
+// those removed
+func (m *hostMatcher) pruneHosts(hosts []interface{}) (kept []interface{}, removed []string) {
+	kept = []interface{}{}
+	for _, h := range hosts {
+		if host, ok := h.(string); ok && m.matches(host) {
+			removed = append(removed, host)
+			continue
+		}
+		kept = append(kept, h)
+	}
+	return kept, removed
+}
+
+// configPruner removes dead definitions from a configuration, recording every removal with its
+// path in the input configuration
+type configPruner struct {
+	hosts    *hostMatcher
+	rootDead bool // Every default host of the root is decommissioned
+	removed  []PruneRemoval
+	warnings []string
+}
+
+func (p *configPruner) remove(path, kind, reason string) {
+	p.removed = append(p.removed, PruneRemoval{Path: path, Kind: kind, Reason: reason})
+}
+
+// pruneBackends removes the decommissioned hosts of a list of backends, and the backends left
+// without hosts. Removed backends are replaced by nil to keep the paths of the others.
+func (p *configPruner) pruneBackends(backends []interface{}, prefix string) (alive int) {
+	for i, b := range backends {
+		backend, ok := b.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		path := fmt.Sprintf("%s.backend[%d]", prefix, i)
+		hosts, declared := backend["host"].([]interface{})
+		if !declared || len(hosts) == 0 {
+			// Backends without hosts use the default hosts of the root
+			if p.rootDead {
+				backends[i] = nil
+				p.remove(path, pruneKindBackend, "it uses the default hosts, all decommissioned")
+				continue
+			}
+			alive++
+			continue
+		}
+		kept, removed := p.hosts.pruneHosts(hosts)
+		if len(kept) == 0 {
+			backends[i] = nil
+			p.remove(path, pruneKindBackend, "every host is decommissioned: "+strings.Join(removed, ", "))
+			continue
+		}
+		if len(removed) > 0 {
+			backend["host"] = kept
+			p.remove(path+".host", pruneKindHost, "decommissioned: "+strings.Join(removed, ", "))
+		}
+		alive++
+	}
+	return alive
+}
+
+// pruneEmptyExtraConfig removes the empty extra_config objects under value, skipping the
+// definitions already removed
+func (p *configPruner) pruneEmptyExtraConfig(value interface{}, path string) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if extra, ok := v["extra_config"].(map[string]interface{}); ok && len(extra) == 0 {
+			delete(v, "extra_config")
+			p.remove(path+".extra_config", pruneKindExtraConfig, "empty object")
+		}
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			childPath := path + "." + k
+			if strings.Contains(k, "/") || strings.Contains(k, ".") {
+				childPath = fmt.Sprintf("%s['%s']", path, k)
+			}
+			p.pruneEmptyExtraConfig(v[k], childPath)
+		}
+	case []interface{}:
+		for i, child := range v {
+			if child != nil {
+				p.pruneEmptyExtraConfig(child, fmt.Sprintf("%s[%d]", path, i))
+			}
+		}
+	}
+}
+
+// compact drops the nil placeholders of removed definitions
+func compact(values []interface{}) []interface{} {
+	kept := make([]interface{}, 0, len(values))
+	for _, v := range values {
+		if v != nil {
+			kept = append(kept, v)
+		}
+	}
+	return kept
+}
+
+// PruneConfig removes dead definitions: endpoints and backends pointing only to decommissioned
+// hosts, empty extra_config objects and root settings every endpoint overrides
+func PruneConfig(ctx context.Context, req *mcp.CallToolRequest, input PruneConfigInput) (*mcp.CallToolResult, PruneConfigOutput, error) {
+	configContent, err := readConfigContent(input.Config)
+	if err != nil {
+		return nil, PruneConfigOutput{}, fmt.Errorf("failed to read config: %w", err)
+	}
+	var config map[string]interface{}
+	if err := json.Unmarshal([]byte(configContent), &config); err != nil {
+		return nil, PruneConfigOutput{}, fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	p := &configPruner{hosts: newHostMatcher(input.DecommissionedHosts), removed: []PruneRemoval{}, warnings: []string{}}
+
+	// Decommissioned hosts, starting with the default hosts of the root
+	if rootHosts, ok := config["host"].([]interface{}); ok && len(rootHosts) > 0 {
+		kept, removed := p.hosts.pruneHosts(rootHosts)
+		switch {
+		case len(kept) == 0:
+			p.rootDead = true
+			delete(config, "host")
+			p.remove("$.host", pruneKindSetting, "every default host is decommissioned: "+strings.Join(removed, ", "))
+		case len(removed) > 0:
+			config["host"] = kept
+			p.remove("$.host", pruneKindHost, "decommissioned: "+strings.Join(removed, ", "))
+		}
+	}
+	endpoints, _ := config["endpoints"].([]interface{})
+	for i, ep := range endpoints {
+		endpoint, ok := ep.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		backends, _ := endpoint["backend"].([]interface{})
+		prefix := fmt.Sprintf("$.endpoints[%d]", i)
+		path, _ := endpoint["endpoint"].(string)
+		label := endpointKey(endpointMethod(endpoint), path)
+		alive := p.pruneBackends(backends, prefix)
+		switch {
+		case len(backends) > 0 && alive == 0:
+			endpoints[i] = nil
+			p.remove(prefix, pruneKindEndpoint, label+": every backend points to decommissioned hosts")
+		case alive < len(backends):
+			p.warnings = append(p.warnings, fmt.Sprintf("%s keeps %d of its %d backends: its response no longer includes the data of the removed ones, review its clients and any sequential proxy references (resp0_...)", label, alive, len(backends)))
+		}
+	}
+	agents, _ := config["async_agent"].([]interface{})
+	for i, a := range agents {
+		agent, ok := a.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		backends, _ := agent["backend"].([]interface{})
+		prefix := fmt.Sprintf("$.async_agent[%d]", i)
+		if alive := p.pruneBackends(backends, prefix); len(backends) > 0 && alive == 0 {
+			agents[i] = nil
+			name, _ := agent["name"].(string)
+			p.remove(prefix, pruneKindAsyncAgent, fmt.Sprintf("%s: every backend points to decommissioned hosts", name))
+		}
+	}
+
+	// Empty extra_config objects, before the removed definitions are dropped to report the
+	// paths of the input configuration
+	p.pruneEmptyExtraConfig(config, "$")
+
+	for _, ep := range endpoints {
+		if endpoint, ok := ep.(map[string]interface{}); ok {
+			if backends, ok := endpoint["backend"].([]interface{}); ok {
+				endpoint["backend"] = compact(backends)
+			}
+		}
+	}
+	for _, a := range agents {
+		if agent, ok := a.(map[string]interface{}); ok {
+			if backends, ok := agent["backend"].([]interface{}); ok {
+				agent["backend"] = compact(backends)
+			}
+		}
+	}
+	if endpoints != nil {
+		endpoints = compact(endpoints)
+		config["endpoints"] = endpoints
+	}
+	if agents != nil {
+		agents = compact(agents)
+		config["async_agent"] = agents
+	}
+
+	// Root settings every endpoint overrides
+	if len(endpoints) > 0 {
+		for _, setting := range endpointDefaults {
+			if _, ok := config[setting]; !ok {
+				continue
+			}
+			overridden := true
+			for _, ep := range endpoints {
+				if endpoint, ok := ep.(map[string]interface{}); !ok || endpoint[setting] == nil {
+					overridden = false
+					break
+				}
+			}
+			if overridden {
+				delete(config, setting)
+				p.remove("$."+setting, pruneKindSetting, "every endpoint declares its own value")
+			}
+		}
+	}
+	if _, ok := config["host"]; ok {
+		overridden := len(endpoints)+len(agents) > 0
+		for _, owner := range append(append([]interface{}{}, endpoints...), agents...) {
+			backends, _ := owner.(map[string]interface{})["backend"].([]interface{})
+			for _, b := range backends {
+				if backend, ok := b.(map[string]interface{}); ok {
+					if hosts, _ := backend["host"].([]interface{}); len(hosts) == 0 {
+						overridden = false
+					}
+				}
+			}
+		}
+		if overridden {
+			delete(config, "host")
+			p.remove("$.host", pruneKindSetting, "every backend declares its own hosts")
+		}
+	}
+
+	if unused := p.hosts.unused(); len(unused) > 0 {
+		p.warnings = append(p.warnings, fmt.Sprintf("No backend points to %s: check the spelling, or they were already removed", strings.Join(unused, ", ")))
+	}
+	if len(endpoints) == 0 && p.countKind(pruneKindEndpoint) > 0 {
+		p.warnings = append(p.warnings, "No endpoint is left: the gateway would not serve any route")
+	}
+
+	return nil, PruneConfigOutput{
+		Config:   config,
+		Removed:  p.removed,
+		Warnings: p.warnings,
+		Summary: fmt.Sprintf("Removed %d endpoint(s), %d async agent(s), %d backend(s), %d empty extra_config object(s) and %d overridden setting(s)",
+			p.countKind(pruneKindEndpoint), p.countKind(pruneKindAsyncAgent), p.countKind(pruneKindBackend), p.countKind(pruneKindExtraConfig), p.countKind(pruneKindSetting)),
+	}, nil
+}
+
+// countKind returns the number of removals of a kind
+func (p *configPruner) countKind(kind string) int {
+	count := 0
+	for _, r := range p.removed {
+		if r.Kind == kind {
+			count++
+		}
+	}
+	return count
+}
+
+// RegisterPruneTools registers the dead configuration elimination tool
+func RegisterPruneTools(server *mcp.Server) {
+	toolkit.AddTool(server,
+		&mcp.Tool{
+			Name:        "prune_config",
+			Description: "Remove dead definitions from a KrakenD configuration: endpoints, async agents and backends whose hosts are all decommissioned (per a provided list), empty extra_config objects and root settings (timeout, cache_ttl, output_encoding, host) every endpoint or backend overrides. Returns the cleaned config and a report of every removal with its JSON path.",
+		},
+		PruneConfig,
+	)
+}
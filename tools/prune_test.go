@@ -0,0 +1,96 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+const pruneConfig = `{"version": 3, "timeout": "3s", "cache_ttl": "300s", "host": ["http://legacy:8080"], "extra_config": {}, "endpoints": [
+	{"endpoint": "/orders", "timeout": "2s", "backend": [
+		{"host": ["http://orders:8080", "http://legacy:8080"], "url_pattern": "/orders"},
+		{"host": ["https://legacy"], "url_pattern": "/orders-legacy", "extra_config": {}}
+	]},
+	{"endpoint": "/reports", "method": "POST", "timeout": "5s", "backend": [{"url_pattern": "/reports"}]},
+	{"endpoint": "/users", "timeout": "1s", "extra_config": {}, "backend": [{"host": ["http://users:8080"], "url_pattern": "/users", "extra_config": {"qos/http-cache": {}}}]}
+], "async_agent": [{"name": "legacy-consumer", "backend": [{"host": ["legacy:9000"], "url_pattern": "/events"}]}]}`
+
+func TestPruneConfig(t *testing.T) {
+	_, output, err := PruneConfig(context.Background(), nil, PruneConfigInput{
+		Config:              pruneConfig,
+		DecommissionedHosts: []string{"legacy", "http://billing:8080"},
+	})
+	if err != nil {
+		t.Fatalf("PruneConfig() error = %v", err)
+	}
+
+	removed := map[string]string{}
+	for _, r := range output.Removed {
+		removed[r.Path] = r.Kind
+	}
+	want := map[string]string{
+		"$.host":                         pruneKindSetting,
+		"$.timeout":                      pruneKindSetting,
+		"$.extra_config":                 pruneKindExtraConfig,
+		"$.endpoints[0].backend[0].host": pruneKindHost,
+		"$.endpoints[0].backend[1]":      pruneKindBackend,
+		"$.endpoints[1].backend[0]":      pruneKindBackend,
+		"$.endpoints[1]":                 pruneKindEndpoint,
+		"$.endpoints[2].extra_config":    pruneKindExtraConfig,
+		"$.async_agent[0].backend[0]":    pruneKindBackend,
+		"$.async_agent[0]":               pruneKindAsyncAgent,
+	}
+	for path, kind := range want {
+		if removed[path] != kind {
+			t.Errorf("Expected %s to be removed as %s, got %q", path, kind, removed[path])
+		}
+	}
+	if len(output.Removed) != len(want) {
+		t.Errorf("Unexpected removals: %+v", output.Removed)
+	}
+
+	// cache_ttl is not overridden by every endpoint, and the empty namespace enables the cache
+	if output.Config["cache_ttl"] != "300s" {
+		t.Errorf("cache_ttl must be kept, got %v", output.Config)
+	}
+	endpoints := output.Config["endpoints"].([]interface{})
+	if len(endpoints) != 2 || len(output.Config["async_agent"].([]interface{})) != 0 {
+		t.Fatalf("Unexpected endpoints left: %v", endpoints)
+	}
+	orders := endpoints[0].(map[string]interface{})["backend"].([]interface{})
+	if len(orders) != 1 || len(orders[0].(map[string]interface{})["host"].([]interface{})) != 1 {
+		t.Errorf("Unexpected backends left in /orders: %v", orders)
+	}
+	users := endpoints[1].(map[string]interface{})["backend"].([]interface{})[0].(map[string]interface{})
+	if _, ok := users["extra_config"].(map[string]interface{})["qos/http-cache"]; !ok {
+		t.Errorf("Empty namespaces must be kept, got %v", users)
+	}
+
+	warnings := strings.Join(output.Warnings, "\n")
+	for _, want := range []string{"GET /orders keeps 1 of its 2 backends", "http://billing:8080"} {
+		if !strings.Contains(warnings, want) {
+			t.Errorf("Expected a warning about %s, got %v", want, output.Warnings)
+		}
+	}
+}
+
+func TestHostMatcher(t *testing.T) {
+	m := newHostMatcher([]string{"legacy", "http://billing:8080", "{{ env \"LEGACY_HOST\" }}"})
+	tests := []struct {
+		host string
+		want bool
+	}{
+		{"http://legacy:8080", true},
+		{"https://LEGACY", true},
+		{"legacy.internal:8080", false},
+		{"http://billing:8080", true},
+		{"billing:8080", true},
+		{"http://billing:9090", false},
+		{"{{ env \"LEGACY_HOST\" }}", true},
+	}
+	for _, tt := range tests {
+		if got := m.matches(tt.host); got != tt.want {
+			t.Errorf("matches(%q) = %v, want %v", tt.host, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,392 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/krakend/mcp-server/internal/toolkit"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	defaultProfileDuration = time.Minute
+	maxSimulatedRequests   = 1_000_000
+	maxTimelineBuckets     = 120
+	maxSimulatedInstances  = 1000
+)
+
+// Levels of a rate limit rejection
+const (
+	RateLimitLevelService  = "service"  // Shared qos/ratelimit/service limit
+	RateLimitLevelEndpoint = "endpoint" // Shared qos/ratelimit/router limit
+	RateLimitLevelClient   = "client"   // Per-client limit of the service or the endpoint
+)
+
+// RateLimitProfile is synthetic traffic sent to an endpoint: clients × rps per client × duration
+type RateLimitProfile struct {
+	Endpoint string  `json:"endpoint" jsonschema:"Endpoint receiving the traffic, as path or METHOD /path of the configuration"`
+	Clients  int     `json:"clients,omitempty" jsonschema:"Distinct clients sending requests (optional, defaults to 1)"`
+	RPS      float64 `json:"rps" jsonschema:"Requests per second sent by each client"`
+	Duration string  `json:"duration,omitempty" jsonschema:"How long the clients send requests, e.g. 30s (optional, defaults to 1m)"`
+}
+
+// SimulateRateLimitInput defines input for simulate_rate_limit tool
+type SimulateRateLimitInput struct {
+	Config    string             `json:"config" jsonschema:"KrakenD configuration (JSON string or file path)"`
+	Profiles  []RateLimitProfile `json:"profiles" jsonschema:"Traffic sent at the same time, one profile per endpoint and client population"`
+	Instances int                `json:"instances,omitempty" jsonschema:"KrakenD instances sharing the traffic behind a round-robin load balancer, each with its own counters (optional, defaults to 1)"`
+}
+
+// SimulatedLimit is a rate limit of the configuration and the requests it rejected
+type SimulatedLimit struct {
+	Level    string `json:"level"`              // "service", "endpoint" or "client"
+	Endpoint string `json:"endpoint,omitempty"` // METHOD /path, for endpoint limits
+	Limit    string `json:"limit"`
+	Rejected int    `json:"rejected"`
+}
+
+// SimulatedEndpoint is the outcome of the traffic of an endpoint
+type SimulatedEndpoint struct {
+	Endpoint       string         `json:"endpoint"` // METHOD /path
+	Requests       int            `json:"requests"`
+	Allowed        int            `json:"allowed"`
+	Rejected       int            `json:"rejected"`
+	RejectedBy     map[string]int `json:"rejected_by"`               // Rejections per level
+	OfferedRPS     float64        `json:"offered_rps"`               // Requests per second sent
+	AllowedRPS     float64        `json:"allowed_rps"`               // Requests per second forwarded to the backends
+	FirstRejection string         `json:"first_rejection,omitempty"` // Time of the first 429, from the start
+}
+
+// SimulatedInterval is the traffic of a slice of the simulated time
+type SimulatedInterval struct {
+	Start    string `json:"start"` // Offset from the start
+	Requests int    `json:"requests"`
+	Rejected int    `json:"rejected"`
+}
+
+// SimulateRateLimitOutput defines output for simulate_rate_limit tool
+type SimulateRateLimitOutput struct {
+	Requests   int                 `json:"requests"`
+	Allowed    int                 `json:"allowed"`
+	Rejected   int                 `json:"rejected"`
+	RejectedBy map[string]int      `json:"rejected_by"` // Rejections per level: service, endpoint, client
+	Limits     []SimulatedLimit    `json:"limits"`
+	Endpoints  []SimulatedEndpoint `json:"endpoints"`
+	Timeline   []SimulatedInterval `json:"timeline"`
+	Warnings   []string            `json:"warnings,omitempty"`
+	Notes      []string            `json:"notes"`
+	Summary    string              `json:"summary"`
+}
+
+// simulatedRequest is a request of the synthetic timeline
+type simulatedRequest struct {
+	sample   *replaySample // Shared by the requests of a client
+	endpoint int           // Index in the output endpoints
+	offset   time.Duration
+}
+
+// simulatedLimits are the rate limits of one instance
+type simulatedLimits struct {
+	service  []*rateLimitRule
+	endpoint map[int][]*rateLimitRule
+}
+
+// SimulateRateLimit replays synthetic traffic against the rate limits of a configuration, with the
+// token buckets of KrakenD, to tell how many requests each level would reject
+func SimulateRateLimit(ctx context.Context, req *mcp.CallToolRequest, input SimulateRateLimitInput) (*mcp.CallToolResult, SimulateRateLimitOutput, error) {
+	if len(input.Profiles) == 0 {
+		return nil, SimulateRateLimitOutput{}, fmt.Errorf("at least one traffic profile is required")
+	}
+	configContent, err := readConfigContent(input.Config)
+	if err != nil {
+		return nil, SimulateRateLimitOutput{}, fmt.Errorf("failed to read config: %w", err)
+	}
+	var config map[string]interface{}
+	if err := json.Unmarshal([]byte(configContent), &config); err != nil {
+		return nil, SimulateRateLimitOutput{}, fmt.Errorf("invalid JSON: %w", err)
+	}
+	instances := input.Instances
+	if instances <= 0 {
+		instances = 1
+	}
+	if instances > maxSimulatedInstances {
+		return nil, SimulateRateLimitOutput{}, fmt.Errorf("instances must be at most %d", maxSimulatedInstances)
+	}
+
+	output := SimulateRateLimitOutput{
+		RejectedBy: map[string]int{RateLimitLevelService: 0, RateLimitLevelEndpoint: 0, RateLimitLevelClient: 0},
+		Limits:     []SimulatedLimit{},
+		Endpoints:  []SimulatedEndpoint{},
+		Timeline:   []SimulatedInterval{},
+		Notes: []string{
+			"Clients send their requests evenly spaced, each one shifted a fraction of the interval, so the simulation shows sustained rates rather than bursts.",
+			"Rate limits count per KrakenD instance: with several instances behind a load balancer, the cluster lets through the limit times the instances.",
+		},
+	}
+	if instances > 1 {
+		output.Notes = append(output.Notes, fmt.Sprintf("Requests are spread round-robin over %d instances, each with its own counters.", instances))
+	}
+
+	// Build the timeline of every profile
+	headerNames := rateLimitHeaders(config)
+	var requests []simulatedRequest
+	endpoints := map[string]int{}
+	var endpointConfigs []map[string]interface{}
+	var endpointWindows []time.Duration
+	var window time.Duration
+	total := 0.0
+	for i, profile := range input.Profiles {
+		if profile.RPS <= 0 {
+			return nil, SimulateRateLimitOutput{}, fmt.Errorf("profiles[%d]: rps must be greater than 0", i)
+		}
+		clients := profile.Clients
+		if clients <= 0 {
+			clients = 1
+		}
+		duration := defaultProfileDuration
+		if profile.Duration != "" {
+			if duration, err = time.ParseDuration(profile.Duration); err != nil || duration <= 0 {
+				return nil, SimulateRateLimitOutput{}, fmt.Errorf("profiles[%d]: invalid duration %q", i, profile.Duration)
+			}
+		}
+		total += float64(clients) * profile.RPS * duration.Seconds()
+		if total > maxSimulatedRequests {
+			return nil, SimulateRateLimitOutput{}, fmt.Errorf("the profiles send more than %d requests: lower the clients, rps or duration", maxSimulatedRequests)
+		}
+
+		endpoint, ok := profileEndpoint(config, profile.Endpoint)
+		if !ok {
+			output.Warnings = append(output.Warnings, fmt.Sprintf("profiles[%d]: %q matches no endpoint of the configuration and was skipped", i, profile.Endpoint))
+			continue
+		}
+		pattern, _ := endpoint["endpoint"].(string)
+		key := endpointMethod(endpoint) + " " + pattern
+		index, seen := endpoints[key]
+		if !seen {
+			index = len(output.Endpoints)
+			endpoints[key] = index
+			endpointConfigs = append(endpointConfigs, endpoint)
+			endpointWindows = append(endpointWindows, 0)
+			output.Endpoints = append(output.Endpoints, SimulatedEndpoint{Endpoint: key, RejectedBy: map[string]int{}})
+		}
+		output.Endpoints[index].OfferedRPS += float64(clients) * profile.RPS
+		endpointWindows[index] = max(endpointWindows[index], duration)
+		window = max(window, duration)
+
+		interval := time.Duration(float64(time.Second) / profile.RPS)
+		if interval <= 0 {
+			return nil, SimulateRateLimitOutput{}, fmt.Errorf("profiles[%d]: rps is too high to simulate", i)
+		}
+		for c := 0; c < clients; c++ {
+			client := fmt.Sprintf("client-%d-%d", i+1, c+1)
+			sample := &replaySample{
+				TrafficSample: TrafficSample{Method: endpointMethod(endpoint), Path: clientPath(pattern, client), ClientIP: client},
+				headers:       clientHeaders(client, headerNames),
+			}
+			for at := interval * time.Duration(c) / time.Duration(clients); at < duration; at += interval {
+				requests = append(requests, simulatedRequest{sample: sample, endpoint: index, offset: at})
+			}
+		}
+	}
+	if len(requests) == 0 {
+		return nil, SimulateRateLimitOutput{}, fmt.Errorf("no profile matches an endpoint of the configuration")
+	}
+	sort.SliceStable(requests, func(i, j int) bool { return requests[i].offset < requests[j].offset })
+
+	// Every instance counts on its own buckets
+	service, _ := config["extra_config"].(map[string]interface{})
+	serviceSettings, _ := service["qos/ratelimit/service"].(map[string]interface{})
+	limits := make([]simulatedLimits, instances)
+	for i := range limits {
+		limits[i] = simulatedLimits{service: rateLimitRules(serviceSettings, "service rate limit"), endpoint: map[int][]*rateLimitRule{}}
+		for index, endpoint := range endpointConfigs {
+			extra, _ := endpoint["extra_config"].(map[string]interface{})
+			settings, _ := extra["qos/ratelimit/router"].(map[string]interface{})
+			limits[i].endpoint[index] = rateLimitRules(settings, "endpoint rate limit")
+		}
+	}
+	limitIndex := map[*rateLimitRule]int{}
+	describe := func(rules []*rateLimitRule, level, endpoint string) {
+		for _, rule := range rules {
+			ruleLevel := level
+			if rule.client != nil {
+				ruleLevel = RateLimitLevelClient
+			}
+			limitIndex[rule] = len(output.Limits)
+			output.Limits = append(output.Limits, SimulatedLimit{
+				Level:    ruleLevel,
+				Endpoint: endpoint,
+				Limit:    fmt.Sprintf("%s: %s requests every %s (capacity %s)", rule.scope, formatFloat(rule.rate), rule.every, formatFloat(rule.capacity)),
+			})
+		}
+	}
+	describe(limits[0].service, RateLimitLevelService, "")
+	for index := range endpointConfigs {
+		describe(limits[0].endpoint[index], RateLimitLevelEndpoint, output.Endpoints[index].Endpoint)
+	}
+	// The other instances share the descriptions of the first one
+	for i := 1; i < instances; i++ {
+		for r, rule := range limits[i].service {
+			limitIndex[rule] = limitIndex[limits[0].service[r]]
+		}
+		for index, rules := range limits[i].endpoint {
+			for r, rule := range rules {
+				limitIndex[rule] = limitIndex[limits[0].endpoint[index][r]]
+			}
+		}
+	}
+	if len(output.Limits) == 0 {
+		output.Warnings = append(output.Warnings, "Neither the service nor the simulated endpoints declare qos/ratelimit/service or qos/ratelimit/router: every request goes through")
+	}
+
+	bucket := timelineBucket(window)
+	start := time.Time{}
+	for i, r := range requests {
+		if i%10000 == 0 && ctx.Err() != nil {
+			return nil, SimulateRateLimitOutput{}, ctx.Err()
+		}
+		instance := limits[i%instances]
+		pattern, _ := endpointConfigs[r.endpoint]["endpoint"].(string)
+		r.sample.at = start.Add(r.offset)
+
+		var rejectedBy *rateLimitRule
+		for _, rules := range [][]*rateLimitRule{instance.service, instance.endpoint[r.endpoint]} {
+			for _, rule := range rules {
+				key := ""
+				if rule.client != nil {
+					key = rule.client(*r.sample, pattern)
+				}
+				if !rule.allow(key, r.sample.at) {
+					rejectedBy = rule
+					break
+				}
+			}
+			if rejectedBy != nil {
+				break
+			}
+		}
+
+		slot := int(r.offset / bucket)
+		for len(output.Timeline) <= slot {
+			output.Timeline = append(output.Timeline, SimulatedInterval{Start: (bucket * time.Duration(len(output.Timeline))).String()})
+		}
+		output.Timeline[slot].Requests++
+		endpoint := &output.Endpoints[r.endpoint]
+		endpoint.Requests++
+		output.Requests++
+		if rejectedBy == nil {
+			endpoint.Allowed++
+			output.Allowed++
+			continue
+		}
+		limit := &output.Limits[limitIndex[rejectedBy]]
+		limit.Rejected++
+		output.Timeline[slot].Rejected++
+		endpoint.Rejected++
+		endpoint.RejectedBy[limit.Level]++
+		output.Rejected++
+		output.RejectedBy[limit.Level]++
+		if endpoint.FirstRejection == "" {
+			endpoint.FirstRejection = r.offset.String()
+		}
+	}
+
+	for i := range output.Endpoints {
+		endpoint := &output.Endpoints[i]
+		endpoint.OfferedRPS = math.Round(endpoint.OfferedRPS*100) / 100
+		endpoint.AllowedRPS = math.Round(float64(endpoint.Allowed)/endpointWindows[i].Seconds()*100) / 100
+	}
+
+	output.Summary = fmt.Sprintf("%d of %d request(s) would get %d Too Many Requests over %s (%d by the service limit, %d by endpoint limits, %d by per-client limits)",
+		output.Rejected, output.Requests, http.StatusTooManyRequests, window,
+		output.RejectedBy[RateLimitLevelService], output.RejectedBy[RateLimitLevelEndpoint], output.RejectedBy[RateLimitLevelClient])
+	if instances > 1 {
+		output.Summary += fmt.Sprintf(" across %d instances", instances)
+	}
+	return nil, output, nil
+}
+
+// profileEndpoint finds the endpoint of a profile, by METHOD /path or by path
+func profileEndpoint(config map[string]interface{}, name string) (map[string]interface{}, bool) {
+	name = strings.TrimSpace(name)
+	endpoints, _ := config["endpoints"].([]interface{})
+	for _, ep := range endpoints {
+		endpoint, _ := ep.(map[string]interface{})
+		path, _ := endpoint["endpoint"].(string)
+		if path != "" && (name == path || strings.EqualFold(name, endpointMethod(endpoint)+" "+path)) {
+			return endpoint, true
+		}
+	}
+	return nil, false
+}
+
+// clientPath fills the parameters of an endpoint with the client, so that a per-client limit
+// keyed by a parameter tells the clients apart
+func clientPath(pattern, client string) string {
+	segments := strings.Split(pattern, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			segments[i] = client
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// clientHeaders identifies the client in the headers the per-client limits are keyed by
+func clientHeaders(client string, names []string) http.Header {
+	headers := http.Header{}
+	for _, name := range names {
+		headers.Set(name, client)
+	}
+	return headers
+}
+
+// rateLimitHeaders lists the headers identifying clients in the per-client limits of the service
+// and the endpoints
+func rateLimitHeaders(config map[string]interface{}) []string {
+	var names []string
+	add := func(extra map[string]interface{}, namespace string) {
+		settings, _ := extra[namespace].(map[string]interface{})
+		if strategy, _ := settings["strategy"].(string); strategy == "header" {
+			if key, _ := settings["key"].(string); key != "" {
+				names = append(names, key)
+			}
+		}
+	}
+	service, _ := config["extra_config"].(map[string]interface{})
+	add(service, "qos/ratelimit/service")
+	endpoints, _ := config["endpoints"].([]interface{})
+	for _, ep := range endpoints {
+		endpoint, _ := ep.(map[string]interface{})
+		extra, _ := endpoint["extra_config"].(map[string]interface{})
+		add(extra, "qos/ratelimit/router")
+	}
+	return names
+}
+
+// timelineBucket is the width of the timeline slices, whole seconds keeping at most
+// maxTimelineBuckets of them
+func timelineBucket(window time.Duration) time.Duration {
+	bucket := time.Second
+	if n := int64(math.Ceil(window.Seconds() / maxTimelineBuckets)); n > 1 {
+		bucket = time.Duration(n) * time.Second
+	}
+	return bucket
+}
+
+// RegisterRateLimitSimulationTools registers the rate limit simulation tool
+func RegisterRateLimitSimulationTools(server *mcp.Server) {
+	toolkit.AddTool(server,
+		&mcp.Tool{
+			Name:        "simulate_rate_limit",
+			Description: "Check that rate limits match intent before deploying: send synthetic traffic (clients × requests per second × duration, per endpoint) through the qos/ratelimit/service and qos/ratelimit/router limits of a configuration, with the token buckets of KrakenD, and report how many requests the service, endpoint and per-client limits would reject, per endpoint and over time.",
+		},
+		SimulateRateLimit,
+	)
+}
@@ -0,0 +1,109 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestSimulateRateLimit_EndpointLimit(t *testing.T) {
+	config := `{"version": 3, "endpoints": [
+		{"endpoint": "/orders", "backend": [{"url_pattern": "/orders"}],
+		 "extra_config": {"qos/ratelimit/router": {"max_rate": 10}}},
+		{"endpoint": "/users", "backend": [{"url_pattern": "/users"}]}
+	]}`
+	_, output, err := SimulateRateLimit(context.Background(), nil, SimulateRateLimitInput{
+		Config: config,
+		Profiles: []RateLimitProfile{
+			{Endpoint: "GET /orders", RPS: 20, Duration: "10s"},
+			{Endpoint: "/users", Clients: 5, RPS: 100, Duration: "10s"},
+			{Endpoint: "/missing", RPS: 1},
+		},
+	})
+	if err != nil {
+		t.Fatalf("SimulateRateLimit() error = %v", err)
+	}
+	if output.Requests != 5200 || len(output.Endpoints) != 2 {
+		t.Fatalf("Expected 5200 requests to 2 endpoints, got %d to %+v", output.Requests, output.Endpoints)
+	}
+	orders := output.Endpoints[0]
+	// 10 tokens at the start, then 10 per second over the 9.95s of traffic
+	if orders.Allowed < 105 || orders.Allowed > 110 || orders.RejectedBy[RateLimitLevelEndpoint] != orders.Rejected {
+		t.Errorf("Expected about 109 requests let through by the endpoint limit, got %+v", orders)
+	}
+	if orders.FirstRejection == "" || orders.OfferedRPS != 20 || orders.AllowedRPS > 11 {
+		t.Errorf("Unexpected rates of %+v", orders)
+	}
+	if users := output.Endpoints[1]; users.Rejected != 0 {
+		t.Errorf("Expected no limit on /users, got %+v", users)
+	}
+	if output.RejectedBy[RateLimitLevelEndpoint] != output.Rejected || len(output.Limits) != 1 || output.Limits[0].Rejected != output.Rejected {
+		t.Errorf("Expected every rejection from the endpoint limit, got %+v and %+v", output.RejectedBy, output.Limits)
+	}
+	// The full bucket absorbs the first second but one request, then 10 of the 20 requests of every second are rejected
+	if len(output.Timeline) != 10 || output.Timeline[0].Rejected != 1 || output.Timeline[9].Rejected != 10 {
+		t.Errorf("Unexpected timeline %+v", output.Timeline)
+	}
+	if len(output.Warnings) != 1 || !strings.Contains(output.Warnings[0], `"/missing"`) {
+		t.Errorf("Expected a warning about /missing, got %v", output.Warnings)
+	}
+}
+
+func TestSimulateRateLimit_PerClientHeader(t *testing.T) {
+	config := `{"version": 3, "endpoints": [
+		{"endpoint": "/orders/{id}", "backend": [{"url_pattern": "/orders"}],
+		 "extra_config": {"qos/ratelimit/router": {"client_max_rate": 1, "strategy": "header", "key": "X-Api-Key"}}}
+	]}`
+	for _, tt := range []struct {
+		rps      float64
+		rejected bool
+	}{{1, false}, {2, true}} {
+		_, output, err := SimulateRateLimit(context.Background(), nil, SimulateRateLimitInput{
+			Config:   config,
+			Profiles: []RateLimitProfile{{Endpoint: "/orders/{id}", Clients: 3, RPS: tt.rps, Duration: "10s"}},
+		})
+		if err != nil {
+			t.Fatalf("SimulateRateLimit() error = %v", err)
+		}
+		if got := output.RejectedBy[RateLimitLevelClient] > 0; got != tt.rejected || output.Rejected != output.RejectedBy[RateLimitLevelClient] {
+			t.Errorf("%v rps per client: expected rejections %v, got %+v", tt.rps, tt.rejected, output.RejectedBy)
+		}
+	}
+}
+
+func TestSimulateRateLimit_Instances(t *testing.T) {
+	config := `{"version": 3, "extra_config": {"qos/ratelimit/service": {"max_rate": 10}},
+		"endpoints": [{"endpoint": "/a", "backend": [{"url_pattern": "/a"}]}]}`
+	input := SimulateRateLimitInput{Config: config, Profiles: []RateLimitProfile{{Endpoint: "/a", RPS: 20, Duration: "10s"}}}
+
+	_, single, err := SimulateRateLimit(context.Background(), nil, input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	input.Instances = 2
+	_, cluster, err := SimulateRateLimit(context.Background(), nil, input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if single.RejectedBy[RateLimitLevelService] < 80 || cluster.Rejected != 0 {
+		t.Errorf("Expected the service limit to reject with one instance only, got %+v and %+v", single.RejectedBy, cluster.RejectedBy)
+	}
+	if !strings.Contains(cluster.Summary, "across 2 instances") {
+		t.Errorf("Unexpected summary %q", cluster.Summary)
+	}
+}
+
+func TestSimulateRateLimit_Errors(t *testing.T) {
+	config := `{"version": 3, "endpoints": [{"endpoint": "/a", "backend": [{"url_pattern": "/a"}]}]}`
+	for name, input := range map[string]SimulateRateLimitInput{
+		"no profiles":      {Config: config},
+		"no rps":           {Config: config, Profiles: []RateLimitProfile{{Endpoint: "/a"}}},
+		"invalid duration": {Config: config, Profiles: []RateLimitProfile{{Endpoint: "/a", RPS: 1, Duration: "soon"}}},
+		"no endpoint":      {Config: config, Profiles: []RateLimitProfile{{Endpoint: "/b", RPS: 1}}},
+		"too many":         {Config: config, Profiles: []RateLimitProfile{{Endpoint: "/a", Clients: 1000, RPS: 1000, Duration: "1h"}}},
+	} {
+		if _, _, err := SimulateRateLimit(context.Background(), nil, input); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
@@ -0,0 +1,370 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/krakend/mcp-server/internal/toolkit"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const defaultReplayGateway = "http://localhost:8080"
+
+// replaySkippedHeaders are recorded by browsers but must not be replayed
+var replaySkippedHeaders = map[string]bool{
+	"host": true, "content-length": true, "connection": true, "accept-encoding": true,
+	"keep-alive": true, "transfer-encoding": true, "upgrade": true, "te": true,
+}
+
+// replayEnvPattern turns a header name into an environment variable name
+var replayEnvPattern = regexp.MustCompile(`[^A-Z0-9]+`)
+
+// GenerateReplayFromHARInput defines input for generate_replay_from_har tool
+type GenerateReplayFromHARInput struct {
+	HAR        string   `json:"har" jsonschema:"Browser HAR capture (JSON string or file path)"`
+	Config     string   `json:"config" jsonschema:"KrakenD configuration (JSON string or file path)"`
+	GatewayURL string   `json:"gateway_url,omitempty" jsonschema:"Base URL of the gateway under test (optional, defaults to http://localhost:8080)"`
+	Hosts      []string `json:"hosts,omitempty" jsonschema:"Only replay requests sent to these hosts, e.g. api.example.com (optional, defaults to every host)"`
+	Format     string   `json:"format,omitempty" jsonschema:"Script format: curl (shell script) or k6 (optional, defaults to curl)"`
+	OutputFile string   `json:"output_file,omitempty" jsonschema:"Path where the script is written (optional)"`
+}
+
+// ReplayRequest is a captured request and the endpoint it maps to
+type ReplayRequest struct {
+	Method         string `json:"method"`
+	Path           string `json:"path"` // Path and query string sent to the gateway
+	Endpoint       string `json:"endpoint,omitempty"`
+	Matched        bool   `json:"matched"`
+	RecordedStatus int    `json:"recorded_status,omitempty"`
+}
+
+// GenerateReplayFromHAROutput defines output for generate_replay_from_har tool
+type GenerateReplayFromHAROutput struct {
+	Format     string          `json:"format"`
+	Script     string          `json:"script"`
+	Requests   []ReplayRequest `json:"requests"`
+	Unmatched  []string        `json:"unmatched"`  // "METHOD /path" with no configured endpoint
+	SecretEnv  []string        `json:"secret_env"` // Environment variables the script expects
+	Skipped    int             `json:"skipped"`    // Entries filtered out by host or scheme
+	OutputFile string          `json:"output_file,omitempty"`
+	Summary    string          `json:"summary"`
+}
+
+// harFile is the subset of the HAR 1.2 format used for replays
+type harFile struct {
+	Log struct {
+		Entries []struct {
+			Request struct {
+				Method  string `json:"method"`
+				URL     string `json:"url"`
+				Headers []struct {
+					Name  string `json:"name"`
+					Value string `json:"value"`
+				} `json:"headers"`
+				PostData *struct {
+					MimeType string `json:"mimeType"`
+					Text     string `json:"text"`
+				} `json:"postData"`
+			} `json:"request"`
+			Response struct {
+				Status int `json:"status"`
+			} `json:"response"`
+		} `json:"entries"`
+	} `json:"log"`
+}
+
+// replayCall is a request ready to be rendered in a script
+type replayCall struct {
+	ReplayRequest
+	Headers [][2]string // name, literal value or $ENV reference
+	Body    string
+}
+
+// endpointPatternMatches reports whether a request path matches a KrakenD endpoint
+// definition, where {param} matches one segment and a trailing * the rest of the path
+func endpointPatternMatches(pattern, path string) bool {
+	patternSegments := strings.Split(strings.Trim(pattern, "/"), "/")
+	pathSegments := strings.Split(strings.Trim(path, "/"), "/")
+	for i, segment := range patternSegments {
+		if segment == "*" && i == len(patternSegments)-1 {
+			return true
+		}
+		if i >= len(pathSegments) {
+			return false
+		}
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			if pathSegments[i] == "" {
+				return false
+			}
+			continue
+		}
+		if segment != pathSegments[i] {
+			return false
+		}
+	}
+	return len(patternSegments) == len(pathSegments)
+}
+
+// matchConfigEndpoint returns the endpoint serving a method and path, preferring
+// the definition with most literal segments as the router does
+func matchConfigEndpoint(config map[string]interface{}, method, path string) (map[string]interface{}, bool) {
+	var best map[string]interface{}
+	bestLiterals := -1
+	endpoints, _ := config["endpoints"].([]interface{})
+	for _, ep := range endpoints {
+		endpoint, ok := ep.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		pattern, _ := endpoint["endpoint"].(string)
+		if !strings.EqualFold(endpointMethod(endpoint), method) || !endpointPatternMatches(pattern, path) {
+			continue
+		}
+		literals := 0
+		for _, segment := range strings.Split(pattern, "/") {
+			if segment != "*" && !strings.HasPrefix(segment, "{") {
+				literals++
+			}
+		}
+		if literals > bestLiterals {
+			best, bestLiterals = endpoint, literals
+		}
+	}
+	return best, best != nil
+}
+
+// isSecretHeader reports whether a header carries credentials that must not end in a script
+func isSecretHeader(name string) bool {
+	switch strings.ToLower(name) {
+	case "authorization", "proxy-authorization", "cookie":
+		return true
+	}
+	return isSecretKey(strings.ReplaceAll(name, "-", "_"))
+}
+
+// GenerateReplayFromHAR converts a browser capture into a script replaying it against the gateway
+func GenerateReplayFromHAR(ctx context.Context, req *mcp.CallToolRequest, input GenerateReplayFromHARInput) (*mcp.CallToolResult, GenerateReplayFromHAROutput, error) {
+	harContent, err := readConfigContent(input.HAR)
+	if err != nil {
+		return nil, GenerateReplayFromHAROutput{}, fmt.Errorf("failed to read HAR: %w", err)
+	}
+	var har harFile
+	if err := json.Unmarshal([]byte(harContent), &har); err != nil {
+		return nil, GenerateReplayFromHAROutput{}, fmt.Errorf("invalid HAR: %w", err)
+	}
+	configContent, err := readConfigContent(input.Config)
+	if err != nil {
+		return nil, GenerateReplayFromHAROutput{}, fmt.Errorf("failed to read config: %w", err)
+	}
+	var config map[string]interface{}
+	if err := json.Unmarshal([]byte(configContent), &config); err != nil {
+		return nil, GenerateReplayFromHAROutput{}, fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	format := input.Format
+	if format == "" {
+		format = "curl"
+	}
+	if format != "curl" && format != "k6" {
+		return nil, GenerateReplayFromHAROutput{}, fmt.Errorf("unknown format %q (available: curl, k6)", format)
+	}
+	gateway := strings.TrimRight(input.GatewayURL, "/")
+	if gateway == "" {
+		gateway = defaultReplayGateway
+	}
+
+	output := GenerateReplayFromHAROutput{Format: format, Requests: []ReplayRequest{}, Unmatched: []string{}, SecretEnv: []string{}}
+	var calls []replayCall
+	secretEnv := map[string]bool{}
+	unmatched := map[string]bool{}
+
+	for _, entry := range har.Log.Entries {
+		u, err := url.Parse(entry.Request.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			output.Skipped++
+			continue
+		}
+		if len(input.Hosts) > 0 && !containsFold(input.Hosts, u.Hostname()) && !containsFold(input.Hosts, u.Host) {
+			output.Skipped++
+			continue
+		}
+
+		method := strings.ToUpper(entry.Request.Method)
+		call := replayCall{ReplayRequest: ReplayRequest{
+			Method:         method,
+			Path:           u.RequestURI(),
+			RecordedStatus: entry.Response.Status,
+		}}
+		if endpoint, ok := matchConfigEndpoint(config, method, u.Path); ok {
+			call.Endpoint, _ = endpoint["endpoint"].(string)
+			call.Matched = true
+		} else {
+			key := method + " " + u.Path
+			if !unmatched[key] {
+				unmatched[key] = true
+				output.Unmatched = append(output.Unmatched, key)
+			}
+		}
+
+		for _, h := range entry.Request.Headers {
+			name := h.Name
+			if strings.HasPrefix(name, ":") || replaySkippedHeaders[strings.ToLower(name)] {
+				continue
+			}
+			value := h.Value
+			if isSecretHeader(name) {
+				env := "HAR_" + strings.Trim(replayEnvPattern.ReplaceAllString(strings.ToUpper(name), "_"), "_")
+				secretEnv[env] = true
+				value = "$" + env
+			}
+			call.Headers = append(call.Headers, [2]string{name, value})
+		}
+		if entry.Request.PostData != nil {
+			call.Body = entry.Request.PostData.Text
+		}
+
+		calls = append(calls, call)
+		output.Requests = append(output.Requests, call.ReplayRequest)
+	}
+	if len(calls) == 0 {
+		return nil, GenerateReplayFromHAROutput{}, fmt.Errorf("the HAR has no HTTP requests to replay (%d skipped)", output.Skipped)
+	}
+
+	for env := range secretEnv {
+		output.SecretEnv = append(output.SecretEnv, env)
+	}
+	sort.Strings(output.SecretEnv)
+
+	if format == "k6" {
+		output.Script = renderK6Replay(gateway, calls)
+	} else {
+		output.Script = renderCurlReplay(gateway, calls)
+	}
+
+	if input.OutputFile != "" {
+		if err := os.MkdirAll(filepath.Dir(input.OutputFile), 0o755); err != nil {
+			return nil, GenerateReplayFromHAROutput{}, fmt.Errorf("failed to create %s: %w", filepath.Dir(input.OutputFile), err)
+		}
+		perm := os.FileMode(0o644)
+		if format == "curl" {
+			perm = 0o755
+		}
+		if err := os.WriteFile(input.OutputFile, []byte(output.Script), perm); err != nil {
+			return nil, GenerateReplayFromHAROutput{}, fmt.Errorf("failed to write %s: %w", input.OutputFile, err)
+		}
+		output.OutputFile = input.OutputFile
+	}
+
+	output.Summary = fmt.Sprintf("%d request(s) to replay, %d without a matching endpoint", len(calls), len(calls)-countMatched(output.Requests))
+	if len(output.Unmatched) > 0 {
+		output.Summary += ": unmatched requests are kept in the script commented out"
+	}
+	return nil, output, nil
+}
+
+// containsFold reports whether list contains s, ignoring case
+func containsFold(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func countMatched(requests []ReplayRequest) int {
+	n := 0
+	for _, r := range requests {
+		if r.Matched {
+			n++
+		}
+	}
+	return n
+}
+
+// shellQuote quotes a value for POSIX shells
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// renderCurlReplay renders the replay as a POSIX shell script using curl
+func renderCurlReplay(gateway string, calls []replayCall) string {
+	var b strings.Builder
+	b.WriteString("#!/bin/sh\n# Replay of a browser HAR capture against KrakenD\n")
+	b.WriteString("GATEWAY=\"${GATEWAY:-" + gateway + "}\"\n")
+	for _, call := range calls {
+		prefix := ""
+		b.WriteString("\n")
+		if call.Matched {
+			fmt.Fprintf(&b, "# %s (recorded status %d)\n", call.Endpoint, call.RecordedStatus)
+		} else {
+			b.WriteString("# NO MATCHING ENDPOINT\n")
+			prefix = "# "
+		}
+		fmt.Fprintf(&b, "%scurl -sS -o /dev/null -w '%%{http_code} %s %%{time_total}s\\n' -X %s \"$GATEWAY\"%s", prefix, call.Method, call.Method, shellQuote(call.Path))
+		for _, h := range call.Headers {
+			if strings.HasPrefix(h[1], "$HAR_") {
+				fmt.Fprintf(&b, " \\\n%s  -H \"%s: %s\"", prefix, h[0], h[1])
+			} else {
+				fmt.Fprintf(&b, " \\\n%s  -H %s", prefix, shellQuote(h[0]+": "+h[1]))
+			}
+		}
+		if call.Body != "" {
+			fmt.Fprintf(&b, " \\\n%s  --data-raw %s", prefix, strings.ReplaceAll(shellQuote(call.Body), "\n", "\n"+prefix))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// renderK6Replay renders the replay as a k6 script
+func renderK6Replay(gateway string, calls []replayCall) string {
+	quote := func(s string) string {
+		data, _ := json.Marshal(s)
+		return string(data)
+	}
+	var b strings.Builder
+	b.WriteString("// Replay of a browser HAR capture against KrakenD\nimport http from 'k6/http';\nimport { check } from 'k6';\n\n")
+	fmt.Fprintf(&b, "const GATEWAY = __ENV.GATEWAY || %s;\n\nexport default function () {\n", quote(gateway))
+	for _, call := range calls {
+		headers := make([]string, 0, len(call.Headers))
+		for _, h := range call.Headers {
+			value := quote(h[1])
+			if strings.HasPrefix(h[1], "$HAR_") {
+				value = "__ENV." + strings.TrimPrefix(h[1], "$")
+			}
+			headers = append(headers, quote(h[0])+": "+value)
+		}
+		body := "null"
+		if call.Body != "" {
+			body = quote(call.Body)
+		}
+		statement := fmt.Sprintf("check(http.request(%s, GATEWAY + %s, %s, { headers: { %s } }), { %s: (r) => r.status === %d });",
+			quote(call.Method), quote(call.Path), body, strings.Join(headers, ", "), quote(call.Method+" "+call.Path+" status"), call.RecordedStatus)
+		if call.Matched {
+			fmt.Fprintf(&b, "  // %s\n  %s\n", call.Endpoint, statement)
+		} else {
+			fmt.Fprintf(&b, "  // NO MATCHING ENDPOINT\n  // %s\n", statement)
+		}
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// RegisterReplayTools registers traffic replay generation tools
+func RegisterReplayTools(server *mcp.Server) {
+	toolkit.AddTool(server,
+		&mcp.Tool{
+			Name:        "generate_replay_from_har",
+			Description: "Convert a browser HAR capture into a curl or k6 script that replays the requests against the gateway. Each request is mapped to the configured endpoint serving it; requests matching no endpoint are flagged and commented out. Credentials (Authorization, cookies, API keys) are replaced by environment variables.",
+		},
+		GenerateReplayFromHAR,
+	)
+}
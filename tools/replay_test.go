@@ -0,0 +1,103 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+const replayTestConfig = `{
+	"version": 3,
+	"endpoints": [
+		{"endpoint": "/users/{id}", "backend": [{"host": ["http://users"], "url_pattern": "/users/{id}"}]},
+		{"endpoint": "/users/me", "backend": [{"host": ["http://users"], "url_pattern": "/me"}]},
+		{"endpoint": "/orders", "method": "POST", "backend": [{"host": ["http://orders"], "url_pattern": "/orders"}]},
+		{"endpoint": "/static/*", "backend": [{"host": ["http://cdn"], "url_pattern": "/"}]}
+	]
+}`
+
+const replayTestHAR = `{"log": {"entries": [
+	{"request": {"method": "GET", "url": "https://api.example.com/users/42?expand=roles", "headers": [{"name": "Authorization", "value": "Bearer abc"}, {"name": "Accept", "value": "application/json"}, {"name": ":authority", "value": "api.example.com"}]}, "response": {"status": 200}},
+	{"request": {"method": "GET", "url": "https://api.example.com/users/me", "headers": []}, "response": {"status": 200}},
+	{"request": {"method": "POST", "url": "https://api.example.com/orders", "headers": [{"name": "Content-Type", "value": "application/json"}], "postData": {"mimeType": "application/json", "text": "{\"item\":\"it's\"}"}}, "response": {"status": 201}},
+	{"request": {"method": "DELETE", "url": "https://api.example.com/orders", "headers": []}, "response": {"status": 405}},
+	{"request": {"method": "GET", "url": "https://fonts.example.net/font.woff", "headers": []}, "response": {"status": 200}},
+	{"request": {"method": "GET", "url": "data:image/png;base64,AAAA", "headers": []}, "response": {"status": 200}}
+]}}`
+
+func TestEndpointPatternMatches(t *testing.T) {
+	tests := []struct {
+		pattern, path string
+		want          bool
+	}{
+		{"/users/{id}", "/users/42", true},
+		{"/users/{id}", "/users", false},
+		{"/users/{id}", "/users/42/roles", false},
+		{"/static/*", "/static/css/app.css", true},
+		{"/", "/", true},
+	}
+	for _, tt := range tests {
+		if got := endpointPatternMatches(tt.pattern, tt.path); got != tt.want {
+			t.Errorf("endpointPatternMatches(%q, %q) = %v, want %v", tt.pattern, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestGenerateReplayFromHAR_Curl(t *testing.T) {
+	_, output, err := GenerateReplayFromHAR(context.Background(), nil, GenerateReplayFromHARInput{
+		HAR:    replayTestHAR,
+		Config: replayTestConfig,
+		Hosts:  []string{"api.example.com"},
+	})
+	if err != nil {
+		t.Fatalf("GenerateReplayFromHAR() error = %v", err)
+	}
+
+	if len(output.Requests) != 4 || output.Skipped != 2 {
+		t.Fatalf("Expected 4 requests and 2 skipped, got %d and %d", len(output.Requests), output.Skipped)
+	}
+	if output.Requests[0].Endpoint != "/users/{id}" || output.Requests[0].Path != "/users/42?expand=roles" {
+		t.Errorf("Unexpected mapping %+v", output.Requests[0])
+	}
+	if output.Requests[1].Endpoint != "/users/me" {
+		t.Errorf("Literal endpoint should win over the parameter, got %s", output.Requests[1].Endpoint)
+	}
+	if len(output.Unmatched) != 1 || output.Unmatched[0] != "DELETE /orders" {
+		t.Errorf("Expected DELETE /orders unmatched, got %v", output.Unmatched)
+	}
+	if len(output.SecretEnv) != 1 || output.SecretEnv[0] != "HAR_AUTHORIZATION" {
+		t.Errorf("Expected the Authorization header in an env var, got %v", output.SecretEnv)
+	}
+
+	if strings.Contains(output.Script, "Bearer abc") {
+		t.Error("Credentials must not be written to the script")
+	}
+	for _, want := range []string{`-H "Authorization: $HAR_AUTHORIZATION"`, `--data-raw '{"item":"it'\''s"}'`, "# curl -sS -o /dev/null -w '%{http_code} DELETE"} {
+		if !strings.Contains(output.Script, want) {
+			t.Errorf("Script missing %q:\n%s", want, output.Script)
+		}
+	}
+	if strings.Contains(output.Script, ":authority") {
+		t.Error("HTTP/2 pseudo headers must be skipped")
+	}
+}
+
+func TestGenerateReplayFromHAR_K6(t *testing.T) {
+	_, output, err := GenerateReplayFromHAR(context.Background(), nil, GenerateReplayFromHARInput{
+		HAR:        replayTestHAR,
+		Config:     replayTestConfig,
+		Format:     "k6",
+		GatewayURL: "http://gw:8080/",
+	})
+	if err != nil {
+		t.Fatalf("GenerateReplayFromHAR() error = %v", err)
+	}
+	for _, want := range []string{`__ENV.GATEWAY || "http://gw:8080"`, `"Authorization": __ENV.HAR_AUTHORIZATION`, "r.status === 201"} {
+		if !strings.Contains(output.Script, want) {
+			t.Errorf("Script missing %q:\n%s", want, output.Script)
+		}
+	}
+	if len(output.Unmatched) != 2 {
+		t.Errorf("Expected DELETE /orders and the font unmatched, got %v", output.Unmatched)
+	}
+}
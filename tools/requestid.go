@@ -0,0 +1,259 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/krakend/mcp-server/internal/toolkit"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	defaultRequestIDHeader = "X-Request-Id"
+	luaProxyNamespace      = "modifier/lua-proxy"
+	// requestIDLuaMarker identifies the Lua snippet generated by configure_request_id
+	requestIDLuaMarker = "-- krakend-mcp: request id"
+)
+
+var (
+	// requestIDHeaderPattern matches header names used for request correlation
+	requestIDHeaderPattern = regexp.MustCompile(`(?i)^(x-)?(request|correlation|trace|amzn-trace)[-_]?id$`)
+	luaHeaderPattern       = regexp.MustCompile(`headers\(\s*["']([^"']+)["']`)
+)
+
+// ConfigureRequestIDInput defines input for configure_request_id tool
+type ConfigureRequestIDInput struct {
+	Config         string   `json:"config" jsonschema:"KrakenD configuration as JSON string or file path"`
+	ConfigEncoding string   `json:"config_encoding,omitempty" jsonschema:"Encoding of config for large configurations: base64 or gzip+base64 (optional, defaults to a plain JSON string or file path)"`
+	Header         string   `json:"header,omitempty" jsonschema:"Request ID header clients send and backends receive (optional, defaults to X-Request-Id)"`
+	BackendHeader  string   `json:"backend_header,omitempty" jsonschema:"Header backends expect the ID in, when it differs from header: it is copied with martian (optional)"`
+	Endpoints      []string `json:"endpoints,omitempty" jsonschema:"Endpoint paths to configure (optional, defaults to every endpoint)"`
+}
+
+// RequestIDIssue is a layer that does not agree on the request ID header
+type RequestIDIssue struct {
+	Endpoint string `json:"endpoint,omitempty"` // "METHOD /path", empty for service-level issues
+	Layer    string `json:"layer"`              // input_headers, backend or logging
+	Message  string `json:"message"`
+}
+
+// ConfigureRequestIDOutput defines output for configure_request_id tool
+type ConfigureRequestIDOutput struct {
+	Header     string                 `json:"header"`
+	Config     map[string]interface{} `json:"config"`
+	Changes    []string               `json:"changes"` // JSON paths modified
+	Issues     []RequestIDIssue       `json:"issues"`  // Conflicting header names found before configuring
+	Consistent bool                   `json:"consistent"`
+	Notes      []string               `json:"notes"`
+	Summary    string                 `json:"summary"`
+}
+
+// requestIDLua generates the ID when the client sends none, so every backend receives one, and
+// logs it with the request line
+func requestIDLua(header string) string {
+	return fmt.Sprintf(`%s
+local r = request.load()
+local id = r:headers(%q)
+if id == nil or id == "" then
+  id = string.format("%%08x%%08x%%08x%%08x", math.random(0, 0x7fffffff), math.random(0, 0x7fffffff), math.random(0, 0x7fffffff), math.random(0, 0x7fffffff))
+  r:headers(%q, id)
+end
+print(string.format("[REQUEST-ID] %%s %%s %%s", id, r:method(), r:path()))`, requestIDLuaMarker, header, header)
+}
+
+// requestIDHeadersIn lists the correlation headers a configuration value refers to: header names
+// of martian modifiers and Lua headers() calls
+func requestIDHeadersIn(value interface{}) []string {
+	var found []string
+	add := func(name string) {
+		if requestIDHeaderPattern.MatchString(name) && !slices.Contains(found, http.CanonicalHeaderKey(name)) {
+			found = append(found, http.CanonicalHeaderKey(name))
+		}
+	}
+	var walk func(v interface{})
+	walk = func(v interface{}) {
+		switch v := v.(type) {
+		case map[string]interface{}:
+			for key, child := range v {
+				if s, ok := child.(string); ok {
+					switch key {
+					case "name", "from", "to":
+						add(s)
+					case "pre", "post":
+						for _, m := range luaHeaderPattern.FindAllStringSubmatch(s, -1) {
+							add(m[1])
+						}
+					}
+				}
+				walk(child)
+			}
+		case []interface{}:
+			for _, child := range v {
+				walk(child)
+			}
+		}
+	}
+	walk(value)
+	return found
+}
+
+// ConfigureRequestID configures end-to-end request ID propagation: endpoints forward the header,
+// a Lua step generates it when missing and logs it, and backends receive it under the name they
+// expect. Conflicting header names found in the configuration are reported.
+func ConfigureRequestID(ctx context.Context, req *mcp.CallToolRequest, input ConfigureRequestIDInput) (*mcp.CallToolResult, ConfigureRequestIDOutput, error) {
+	configContent, err := readEncodedConfig(input.Config, input.ConfigEncoding)
+	if err != nil {
+		return nil, ConfigureRequestIDOutput{}, fmt.Errorf("failed to read config: %w", err)
+	}
+	var config map[string]interface{}
+	if err := json.Unmarshal([]byte(configContent), &config); err != nil {
+		return nil, ConfigureRequestIDOutput{}, fmt.Errorf("invalid JSON: %w", err)
+	}
+	header := http.CanonicalHeaderKey(envOrDefault(input.Header, defaultRequestIDHeader))
+	backendHeader := http.CanonicalHeaderKey(input.BackendHeader)
+	if backendHeader == header {
+		backendHeader = ""
+	}
+	expected := []string{header}
+	if backendHeader != "" {
+		expected = append(expected, backendHeader)
+	}
+
+	output := ConfigureRequestIDOutput{Header: header, Config: config, Changes: []string{}, Issues: []RequestIDIssue{}, Notes: []string{
+		fmt.Sprintf("Clients sending %s keep their ID end to end; requests without it get a random one generated by Lua before calling the backends.", header),
+		"Every request writes a [REQUEST-ID] <id> <method> <path> line to the KrakenD logs: search for the ID to follow a request across the gateway and the backend logs.",
+		"The Lua step needs allow_open_libs for the string and math libraries.",
+	}}
+	extraConfig := func(parent map[string]interface{}) map[string]interface{} {
+		extra, _ := parent["extra_config"].(map[string]interface{})
+		if extra == nil {
+			extra = map[string]interface{}{}
+			parent["extra_config"] = extra
+		}
+		return extra
+	}
+	conflict := func(endpoint, layer string, names []string, where string) {
+		for _, name := range names {
+			if !slices.Contains(expected, name) {
+				output.Issues = append(output.Issues, RequestIDIssue{Endpoint: endpoint, Layer: layer,
+					Message: fmt.Sprintf("%s uses %s, the request ID header is %s", where, name, header)})
+			}
+		}
+	}
+	if service, ok := config["extra_config"].(map[string]interface{}); ok {
+		conflict("", "logging", requestIDHeadersIn(service[luaProxyNamespace]), "The service Lua")
+	}
+
+	var copyModifier map[string]interface{}
+	if backendHeader != "" {
+		copyModifier = map[string]interface{}{"header.Copy": map[string]interface{}{
+			"scope": []interface{}{"request"},
+			"from":  header,
+			"to":    backendHeader,
+		}}
+	}
+
+	endpoints, _ := config["endpoints"].([]interface{})
+	configured := 0
+	for i, ep := range endpoints {
+		endpoint, ok := ep.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		path, _ := endpoint["endpoint"].(string)
+		if len(input.Endpoints) > 0 && !slices.Contains(input.Endpoints, path) {
+			continue
+		}
+		configured++
+		label := endpointMethod(endpoint) + " " + path
+
+		// Layer 1: the endpoint forwards the header to the backends
+		headers, _ := endpoint["input_headers"].([]interface{})
+		var forwarded []string
+		for _, h := range headers {
+			if s, ok := h.(string); ok && requestIDHeaderPattern.MatchString(s) {
+				forwarded = append(forwarded, http.CanonicalHeaderKey(s))
+			}
+		}
+		conflict(label, "input_headers", forwarded, "input_headers")
+		if !slices.Contains(headers, interface{}("*")) && !slices.Contains(forwarded, header) {
+			endpoint["input_headers"] = append(headers, header)
+			output.Changes = append(output.Changes, fmt.Sprintf("$.endpoints[%d].input_headers", i))
+		}
+
+		// Layer 2: the ID is generated when missing and logged
+		extra := extraConfig(endpoint)
+		lua, _ := extra[luaProxyNamespace].(map[string]interface{})
+		pre, _ := lua["pre"].(string)
+		conflict(label, "logging", requestIDHeadersIn(lua), "The endpoint Lua")
+		if !strings.Contains(pre, requestIDLuaMarker) {
+			if lua == nil {
+				lua = map[string]interface{}{}
+				extra[luaProxyNamespace] = lua
+			}
+			script := requestIDLua(header)
+			if pre != "" {
+				script += "\n" + pre
+			}
+			lua["pre"] = script
+			lua["allow_open_libs"] = true
+			output.Changes = append(output.Changes, fmt.Sprintf("$.endpoints[%d].extra_config['%s']", i, luaProxyNamespace))
+		}
+
+		// Layer 3: backends receive the ID under the name they expect
+		backends, _ := endpoint["backend"].([]interface{})
+		for j, b := range backends {
+			backend, ok := b.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			backendExtra, _ := backend["extra_config"].(map[string]interface{})
+			conflict(label, "backend", requestIDHeadersIn(backendExtra[martianNamespace]), fmt.Sprintf("The martian modifiers of backend %d", j))
+			if copyModifier == nil {
+				continue
+			}
+			backendExtra = extraConfig(backend)
+			location := fmt.Sprintf("$.endpoints[%d].backend[%d].extra_config['%s']", i, j, martianNamespace)
+			existing, ok := backendExtra[martianNamespace].(map[string]interface{})
+			switch {
+			case ok && hasMartianModifier(existing, copyModifier):
+				continue
+			case ok:
+				backendExtra[martianNamespace] = map[string]interface{}{"fifo.Group": map[string]interface{}{
+					"scope":           []interface{}{"request", "response"},
+					"aggregateErrors": true,
+					"modifiers":       []interface{}{existing, copyModifier},
+				}}
+			default:
+				backendExtra[martianNamespace] = copyModifier
+			}
+			output.Changes = append(output.Changes, location)
+		}
+	}
+	if backendHeader != "" {
+		output.Notes = append(output.Notes, fmt.Sprintf("Backends receive the ID both as %s and %s (martian header.Copy).", header, backendHeader))
+	}
+
+	output.Consistent = len(output.Issues) == 0
+	output.Summary = fmt.Sprintf("Request ID %s propagated through %d endpoint(s): %d change(s)", header, configured, len(output.Changes))
+	if !output.Consistent {
+		output.Summary += fmt.Sprintf(", %d conflicting header name(s) to review", len(output.Issues))
+	}
+	return nil, output, nil
+}
+
+// RegisterRequestIDTools registers the request ID propagation tool
+func RegisterRequestIDTools(server *mcp.Server) {
+	toolkit.AddTool(server,
+		&mcp.Tool{
+			Name:        "configure_request_id",
+			Description: "Configure end-to-end request ID propagation across every endpoint: forward the header in input_headers, generate it with Lua when the client sends none and log it with each request, and copy it to the header name backends expect with martian. Validates that input_headers, backend modifiers and Lua scripts agree on the header name. Idempotent.",
+		},
+		ConfigureRequestID,
+	)
+}
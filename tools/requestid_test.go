@@ -0,0 +1,80 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestConfigureRequestID(t *testing.T) {
+	config := `{"version": 3, "endpoints": [
+		{"endpoint": "/users", "input_headers": ["Authorization", "X-Correlation-Id"], "backend": [{"url_pattern": "/users"}]},
+		{"endpoint": "/orders", "input_headers": ["*"], "extra_config": {"modifier/lua-proxy": {"pre": "print('hi')"}}, "backend": [
+			{"url_pattern": "/orders", "extra_config": {"modifier/martian": {"header.Modifier": {"scope": ["request"], "name": "X-Trace-Id", "value": "1"}}}}
+		]}
+	]}`
+	_, output, err := ConfigureRequestID(context.Background(), nil, ConfigureRequestIDInput{Config: config, BackendHeader: "x-correlation-id"})
+	if err != nil {
+		t.Fatalf("ConfigureRequestID() error = %v", err)
+	}
+	if output.Header != "X-Request-Id" || output.Consistent {
+		t.Errorf("Expected conflicts with the default header, got %+v", output)
+	}
+	if len(output.Issues) != 1 || output.Issues[0].Layer != "backend" || !strings.Contains(output.Issues[0].Message, "X-Trace-Id") {
+		t.Errorf("Expected the martian X-Trace-Id conflict only (X-Correlation-Id is the backend header), got %+v", output.Issues)
+	}
+
+	endpoints := output.Config["endpoints"].([]interface{})
+	users := endpoints[0].(map[string]interface{})
+	if headers := users["input_headers"].([]interface{}); len(headers) != 3 || headers[2] != "X-Request-Id" {
+		t.Errorf("Expected the header to be forwarded, got %v", headers)
+	}
+	lua := users["extra_config"].(map[string]interface{})["modifier/lua-proxy"].(map[string]interface{})
+	if pre := lua["pre"].(string); !strings.Contains(pre, `r:headers("X-Request-Id", id)`) || !strings.Contains(pre, "[REQUEST-ID]") || lua["allow_open_libs"] != true {
+		t.Errorf("Unexpected Lua step: %v", lua)
+	}
+	copied := users["backend"].([]interface{})[0].(map[string]interface{})["extra_config"].(map[string]interface{})["modifier/martian"].(map[string]interface{})
+	if c, ok := copied["header.Copy"].(map[string]interface{}); !ok || c["to"] != "X-Correlation-Id" {
+		t.Errorf("Expected a header.Copy to the backend header, got %v", copied)
+	}
+
+	orders := endpoints[1].(map[string]interface{})
+	if headers := orders["input_headers"].([]interface{}); len(headers) != 1 {
+		t.Errorf("Endpoints forwarding every header should be left as is, got %v", headers)
+	}
+	pre := orders["extra_config"].(map[string]interface{})["modifier/lua-proxy"].(map[string]interface{})["pre"].(string)
+	if !strings.HasPrefix(pre, requestIDLuaMarker) || !strings.HasSuffix(pre, "print('hi')") {
+		t.Errorf("Expected the existing Lua to run after the request ID step, got %s", pre)
+	}
+
+	// Configuring again changes nothing
+	data, err := json.Marshal(output.Config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, again, err := ConfigureRequestID(context.Background(), nil, ConfigureRequestIDInput{Config: string(data), BackendHeader: "X-Correlation-Id"})
+	if err != nil {
+		t.Fatalf("ConfigureRequestID() error = %v", err)
+	}
+	if len(again.Changes) != 0 {
+		t.Errorf("Expected no changes configuring again, got %v", again.Changes)
+	}
+}
+
+func TestConfigureRequestID_Conflicts(t *testing.T) {
+	config := `{"version": 3, "endpoints": [
+		{"endpoint": "/a", "input_headers": ["X-Correlation-Id"], "extra_config": {"modifier/lua-proxy": {"pre": "local id = request.load():headers('Request-Id')"}}}
+	]}`
+	_, output, err := ConfigureRequestID(context.Background(), nil, ConfigureRequestIDInput{Config: config, Header: "x-request-id"})
+	if err != nil {
+		t.Fatalf("ConfigureRequestID() error = %v", err)
+	}
+	layers := []string{}
+	for _, issue := range output.Issues {
+		layers = append(layers, issue.Layer)
+	}
+	if strings.Join(layers, ",") != "input_headers,logging" || output.Issues[0].Endpoint != "GET /a" {
+		t.Errorf("Expected input_headers and Lua conflicts, got %+v", output.Issues)
+	}
+}
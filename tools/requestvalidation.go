@@ -0,0 +1,239 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/krakend/mcp-server/internal/toolkit"
+	"github.com/krakend/mcp-server/tools/validation"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+const (
+	requestValidationNamespace  = "validation/json-schema"
+	responseValidationNamespace = "validation/response-json-schema"
+)
+
+// bodylessMethods are the methods whose requests usually have no body
+var bodylessMethods = map[string]bool{"GET": true, "HEAD": true, "DELETE": true, "OPTIONS": true}
+
+// ConfigureRequestValidationInput defines input for configure_request_validation tool
+type ConfigureRequestValidationInput struct {
+	Schema         string `json:"schema" jsonschema:"JSON Schema of the request body (JSON string or file path)"`
+	ResponseSchema string `json:"response_schema,omitempty" jsonschema:"JSON Schema of the backend responses, validated by the Enterprise response schema validator (optional, JSON string or file path)"`
+	Config         string `json:"config,omitempty" jsonschema:"KrakenD configuration to update (optional, JSON string or file path)"`
+	ConfigEncoding string `json:"config_encoding,omitempty" jsonschema:"Encoding of config for large configurations: base64 or gzip+base64 (optional, defaults to a plain JSON string or file path)"`
+	Endpoint       string `json:"endpoint,omitempty" jsonschema:"Endpoint that validates its requests (required with config)"`
+	Method         string `json:"method,omitempty" jsonschema:"Endpoint method when the path is declared for several methods (optional)"`
+	Edition        string `json:"edition,omitempty" jsonschema:"Target edition: ce or ee (optional, detected from the config)"`
+}
+
+// ConfigureRequestValidationOutput defines output for configure_request_validation tool
+type ConfigureRequestValidationOutput struct {
+	Namespace          string                 `json:"namespace"`
+	ExtraConfig        map[string]interface{} `json:"extra_config"`                   // Endpoint extra_config entry
+	BackendExtraConfig map[string]interface{} `json:"backend_extra_config,omitempty"` // Backend extra_config entry validating responses (Enterprise)
+	Compiled           bool                   `json:"compiled"`                       // The schemas compile (remote references are not resolved)
+	Config             map[string]interface{} `json:"config,omitempty"`
+	Changes            []string               `json:"changes,omitempty"` // JSON paths modified
+	Warnings           []string               `json:"warnings,omitempty"`
+	Notes              []string               `json:"notes"`
+}
+
+// readSchema parses a JSON Schema given as JSON or as a file path
+func readSchema(name, input string) (interface{}, error) {
+	content, err := readConfigContent(input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	var schema interface{}
+	if err := json.Unmarshal([]byte(content), &schema); err != nil {
+		return nil, fmt.Errorf("invalid JSON in %s: %w", name, err)
+	}
+	return schema, nil
+}
+
+// compileSchema compiles a schema as KrakenD does. Remote references cannot be resolved
+// offline: they are reported as a warning instead of an error.
+func compileSchema(name string, schema interface{}) (bool, []string, error) {
+	err := validation.CompileEmbeddedSchema(schema)
+	var loadErr *jsonschema.LoadURLError
+	var schemeErr *jsonschema.UnsupportedURLSchemeError
+	switch {
+	case err == nil:
+		return true, nil, nil
+	case errors.As(err, &loadErr), errors.As(err, &schemeErr):
+		return false, []string{fmt.Sprintf("%s references remote documents that could not be checked offline (%v): KrakenD downloads them when it starts, so they must be reachable from the gateway", name, err)}, nil
+	}
+	return false, nil, fmt.Errorf("%s does not compile: %w", name, err)
+}
+
+// requestSchemaWarnings lists the constraints the request body must meet to pass the validation
+func requestSchemaWarnings(schema interface{}) []string {
+	obj, ok := schema.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	var warnings []string
+	switch t := obj["type"].(type) {
+	case nil:
+		warnings = append(warnings, "The schema declares no root \"type\": any JSON document (including a string or a number) passes unless other keywords reject it")
+	case string:
+		if t != "object" && t != "array" {
+			warnings = append(warnings, fmt.Sprintf("The root type is %q: clients must send a bare JSON %s as the body", t, t))
+		}
+	}
+	if obj["properties"] != nil && obj["required"] == nil {
+		warnings = append(warnings, "No property is \"required\": an empty object {} passes the validation")
+	}
+
+	var keywords []string
+	var walk func(v interface{})
+	walk = func(v interface{}) {
+		switch v := v.(type) {
+		case map[string]interface{}:
+			for _, keyword := range []string{"format", "contentEncoding", "contentMediaType"} {
+				if _, ok := v[keyword]; ok && !slices.Contains(keywords, keyword) {
+					keywords = append(keywords, keyword)
+				}
+			}
+			for _, child := range v {
+				walk(child)
+			}
+		case []interface{}:
+			for _, child := range v {
+				walk(child)
+			}
+		}
+	}
+	walk(obj)
+	if slices.Contains(keywords, "format") {
+		warnings = append(warnings, "\"format\" is only asserted for the formats the validator knows: add a \"pattern\" when the value must be enforced")
+	}
+	if slices.Contains(keywords, "contentEncoding") || slices.Contains(keywords, "contentMediaType") {
+		warnings = append(warnings, "contentEncoding and contentMediaType are not decoded: base64 or embedded JSON strings are only validated as plain strings")
+	}
+	return warnings
+}
+
+// ConfigureRequestValidation generates the endpoint configuration validating request bodies
+// against a JSON Schema
+func ConfigureRequestValidation(ctx context.Context, req *mcp.CallToolRequest, input ConfigureRequestValidationInput) (*mcp.CallToolResult, ConfigureRequestValidationOutput, error) {
+	if strings.TrimSpace(input.Schema) == "" {
+		return nil, ConfigureRequestValidationOutput{}, fmt.Errorf("schema is required")
+	}
+	schema, err := readSchema("schema", input.Schema)
+	if err != nil {
+		return nil, ConfigureRequestValidationOutput{}, err
+	}
+	compiled, warnings, err := compileSchema("schema", schema)
+	if err != nil {
+		return nil, ConfigureRequestValidationOutput{}, err
+	}
+	output := ConfigureRequestValidationOutput{
+		Namespace:   requestValidationNamespace,
+		ExtraConfig: map[string]interface{}{requestValidationNamespace: schema},
+		Compiled:    compiled,
+		Warnings:    append(warnings, requestSchemaWarnings(schema)...),
+		Notes: []string{
+			"KrakenD validates the request body before calling the backends and answers 400 Bad Request when it does not match the schema.",
+			"Only JSON bodies can be validated: form-encoded, XML or empty bodies are rejected, so clients must send Content-Type: application/json.",
+		},
+	}
+
+	var config map[string]interface{}
+	var configContent string
+	if input.Config != "" {
+		if configContent, err = readEncodedConfig(input.Config, input.ConfigEncoding); err != nil {
+			return nil, ConfigureRequestValidationOutput{}, fmt.Errorf("failed to read config: %w", err)
+		}
+		if err := json.Unmarshal([]byte(configContent), &config); err != nil {
+			return nil, ConfigureRequestValidationOutput{}, fmt.Errorf("invalid JSON: %w", err)
+		}
+	}
+
+	if input.ResponseSchema != "" {
+		switch {
+		case input.Edition == "ce":
+			return nil, ConfigureRequestValidationOutput{}, fmt.Errorf("response_schema requires KrakenD Enterprise (%s is not available in the Community Edition)", responseValidationNamespace)
+		case input.Edition == "" && config != nil && !DetectEnterpriseFeatures(configContent):
+			output.Warnings = append(output.Warnings, fmt.Sprintf("%s is an Enterprise feature and the configuration uses no other Enterprise feature: run it with the krakend-ee image", responseValidationNamespace))
+		}
+		responseSchema, err := readSchema("response_schema", input.ResponseSchema)
+		if err != nil {
+			return nil, ConfigureRequestValidationOutput{}, err
+		}
+		responseCompiled, responseWarnings, err := compileSchema("response_schema", responseSchema)
+		if err != nil {
+			return nil, ConfigureRequestValidationOutput{}, err
+		}
+		output.Compiled = output.Compiled && responseCompiled
+		output.Warnings = append(output.Warnings, responseWarnings...)
+		output.BackendExtraConfig = map[string]interface{}{
+			responseValidationNamespace: map[string]interface{}{"schema": responseSchema},
+		}
+		output.Notes = append(output.Notes, "The response schema is checked on every backend response; responses that do not match it are treated as backend errors.")
+	}
+
+	if config == nil {
+		return nil, output, nil
+	}
+	if input.Endpoint == "" {
+		return nil, ConfigureRequestValidationOutput{}, fmt.Errorf("endpoint is required to update a configuration")
+	}
+	endpoint, err := findEndpoint(config, input.Endpoint, input.Method)
+	if err != nil {
+		return nil, ConfigureRequestValidationOutput{}, err
+	}
+	index := endpointIndex(config, endpoint)
+	if method := endpointMethod(endpoint); bodylessMethods[method] {
+		output.Warnings = append(output.Warnings, fmt.Sprintf("%s %s usually receives no body: every request without a JSON body matching the schema will be rejected", method, input.Endpoint))
+	}
+
+	extra, _ := endpoint["extra_config"].(map[string]interface{})
+	if extra == nil {
+		extra = map[string]interface{}{}
+		endpoint["extra_config"] = extra
+	}
+	location := fmt.Sprintf("$.endpoints[%d].extra_config['%s']", index, requestValidationNamespace)
+	if _, exists := extra[requestValidationNamespace]; exists {
+		output.Warnings = append(output.Warnings, fmt.Sprintf("%s already existed and was replaced", location))
+	}
+	extra[requestValidationNamespace] = schema
+	output.Changes = append(output.Changes, location)
+
+	if output.BackendExtraConfig != nil {
+		backends, _ := endpoint["backend"].([]interface{})
+		for i, b := range backends {
+			backend, ok := b.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			backendExtra, _ := backend["extra_config"].(map[string]interface{})
+			if backendExtra == nil {
+				backendExtra = map[string]interface{}{}
+				backend["extra_config"] = backendExtra
+			}
+			backendExtra[responseValidationNamespace] = output.BackendExtraConfig[responseValidationNamespace]
+			output.Changes = append(output.Changes, fmt.Sprintf("$.endpoints[%d].backend[%d].extra_config['%s']", index, i, responseValidationNamespace))
+		}
+	}
+	output.Config = config
+	return nil, output, nil
+}
+
+// RegisterRequestValidationTools registers the request validation generation tool
+func RegisterRequestValidationTools(server *mcp.Server) {
+	toolkit.AddTool(server,
+		&mcp.Tool{
+			Name:        "configure_request_validation",
+			Description: "Generate the validation/json-schema endpoint configuration from a JSON Schema of the request body, and optionally the Enterprise response schema validator for the backends. Verifies the schemas compile, warns about body and encoding constraints (bodyless methods, missing required properties, unasserted formats), and optionally applies it to an endpoint of a configuration.",
+		},
+		ConfigureRequestValidation,
+	)
+}
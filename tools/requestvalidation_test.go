@@ -0,0 +1,89 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestConfigureRequestValidation(t *testing.T) {
+	schema := `{"type": "object", "required": ["email"], "properties": {"email": {"type": "string", "format": "email"}}}`
+	config := `{"version": 3, "endpoints": [
+		{"endpoint": "/users", "method": "POST", "backend": [{"url_pattern": "/users", "host": ["http://users"]}]},
+		{"endpoint": "/users", "backend": [{"url_pattern": "/users", "host": ["http://users"]}]}
+	]}`
+	_, output, err := ConfigureRequestValidation(context.Background(), nil, ConfigureRequestValidationInput{
+		Schema:   schema,
+		Config:   config,
+		Endpoint: "/users",
+		Method:   "post",
+	})
+	if err != nil {
+		t.Fatalf("ConfigureRequestValidation() error = %v", err)
+	}
+	if !output.Compiled || output.ExtraConfig["validation/json-schema"] == nil {
+		t.Errorf("Expected a compiled validation/json-schema entry, got %+v", output)
+	}
+	if len(output.Changes) != 1 || output.Changes[0] != "$.endpoints[0].extra_config['validation/json-schema']" {
+		t.Errorf("Expected only the POST endpoint to change, got %v", output.Changes)
+	}
+	if !strings.Contains(strings.Join(output.Warnings, "\n"), "format") {
+		t.Errorf("Expected a warning about format, got %v", output.Warnings)
+	}
+	get := output.Config["endpoints"].([]interface{})[1].(map[string]interface{})
+	if _, ok := get["extra_config"]; ok {
+		t.Error("The GET endpoint must be left untouched")
+	}
+}
+
+func TestConfigureRequestValidation_Warnings(t *testing.T) {
+	config := `{"version": 3, "endpoints": [{"endpoint": "/search", "backend": [{"url_pattern": "/search"}]}]}`
+	_, output, err := ConfigureRequestValidation(context.Background(), nil, ConfigureRequestValidationInput{
+		Schema:   `{"properties": {"q": {"type": "string"}}}`,
+		Config:   config,
+		Endpoint: "/search",
+	})
+	if err != nil {
+		t.Fatalf("ConfigureRequestValidation() error = %v", err)
+	}
+	warnings := strings.Join(output.Warnings, "\n")
+	for _, want := range []string{"no root \"type\"", "empty object", "GET /search usually receives no body"} {
+		if !strings.Contains(warnings, want) {
+			t.Errorf("Expected a warning containing %q, got %v", want, output.Warnings)
+		}
+	}
+}
+
+func TestConfigureRequestValidation_InvalidSchema(t *testing.T) {
+	_, _, err := ConfigureRequestValidation(context.Background(), nil, ConfigureRequestValidationInput{Schema: `{"type": "strng"}`})
+	if err == nil || !strings.Contains(err.Error(), "does not compile") {
+		t.Errorf("Expected a compilation error, got %v", err)
+	}
+
+	_, output, err := ConfigureRequestValidation(context.Background(), nil, ConfigureRequestValidationInput{Schema: `{"$ref": "https://schemas.example.com/user.json"}`})
+	if err != nil || output.Compiled || !strings.Contains(strings.Join(output.Warnings, "\n"), "remote documents") {
+		t.Errorf("Expected a remote reference warning, got %+v (error %v)", output, err)
+	}
+}
+
+func TestConfigureRequestValidation_ResponseSchema(t *testing.T) {
+	input := ConfigureRequestValidationInput{
+		Schema:         `{"type": "object"}`,
+		ResponseSchema: `{"type": "array"}`,
+		Edition:        "ce",
+	}
+	if _, _, err := ConfigureRequestValidation(context.Background(), nil, input); err == nil {
+		t.Error("Expected the response schema to be rejected for the Community Edition")
+	}
+
+	input.Edition = "ee"
+	input.Config = `{"version": 3, "endpoints": [{"endpoint": "/items", "method": "PUT", "backend": [{"url_pattern": "/a"}, {"url_pattern": "/b"}]}]}`
+	input.Endpoint = "/items"
+	_, output, err := ConfigureRequestValidation(context.Background(), nil, input)
+	if err != nil {
+		t.Fatalf("ConfigureRequestValidation() error = %v", err)
+	}
+	if output.BackendExtraConfig["validation/response-json-schema"] == nil || len(output.Changes) != 3 {
+		t.Errorf("Expected the response schema on both backends, got %+v", output)
+	}
+}
@@ -0,0 +1,234 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/krakend/mcp-server/internal/toolkit"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ResolveBackendsInput defines input for resolve_backends tool
+type ResolveBackendsInput struct {
+	Config     string `json:"config" jsonschema:"KrakenD configuration (JSON string or file path)"`
+	Nameserver string `json:"nameserver,omitempty" jsonschema:"Nameserver to query as host:port (optional, defaults to the system resolver)"`
+	TimeoutMs  int    `json:"timeout_ms,omitempty" jsonschema:"Per-host resolution timeout in milliseconds (optional, defaults to 3000)"`
+}
+
+// ResolvedTarget is a single address or SRV target a backend host resolves to
+type ResolvedTarget struct {
+	Address  string `json:"address"`
+	Port     uint16 `json:"port,omitempty"`
+	Priority uint16 `json:"priority,omitempty"`
+	Weight   uint16 `json:"weight,omitempty"`
+}
+
+// HostResolution contains the resolution results for a backend host
+type HostResolution struct {
+	Host      string           `json:"host"`
+	SD        string           `json:"sd"`
+	Lookup    string           `json:"lookup"` // "A/AAAA", "SRV" or "literal"
+	Targets   []ResolvedTarget `json:"targets"`
+	TTL       *uint32          `json:"ttl_seconds,omitempty"`
+	Issues    []string         `json:"issues,omitempty"`
+	Locations []string         `json:"locations"`
+	Error     string           `json:"error,omitempty"`
+}
+
+// ResolveBackendsOutput defines output for resolve_backends tool
+type ResolveBackendsOutput struct {
+	Hosts      []HostResolution `json:"hosts"`
+	Failures   int              `json:"failures"`
+	IssueCount int              `json:"issue_count"`
+	Summary    string           `json:"summary"`
+}
+
+// ResolveBackends resolves every backend host as the gateway would, including DNS SRV service discovery
+func ResolveBackends(ctx context.Context, req *mcp.CallToolRequest, input ResolveBackendsInput) (*mcp.CallToolResult, ResolveBackendsOutput, error) {
+	configContent, err := readConfigContent(input.Config)
+	if err != nil {
+		return nil, ResolveBackendsOutput{}, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	var config map[string]interface{}
+	if err := json.Unmarshal([]byte(configContent), &config); err != nil {
+		return nil, ResolveBackendsOutput{}, fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	timeout := defaultProbeTimeout
+	if input.TimeoutMs > 0 {
+		timeout = time.Duration(input.TimeoutMs) * time.Millisecond
+	}
+
+	resolver := net.DefaultResolver
+	nameserver := input.Nameserver
+	if nameserver != "" {
+		resolver = &net.Resolver{
+			PreferGo: true,
+			Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, network, nameserver)
+			},
+		}
+	} else if ns, err := systemNameserver(); err == nil {
+		nameserver = ns
+	}
+
+	output := ResolveBackendsOutput{Hosts: []HostResolution{}}
+	for _, h := range collectBackendHosts(config) {
+		hostCtx, cancel := context.WithTimeout(ctx, timeout)
+		res := resolveBackendHost(hostCtx, resolver, nameserver, h)
+		cancel()
+
+		if res.Error != "" {
+			output.Failures++
+		}
+		output.IssueCount += len(res.Issues)
+		output.Hosts = append(output.Hosts, res)
+	}
+
+	output.Summary = fmt.Sprintf("Resolved %d backend host(s): %d failed, %d issue(s) found", len(output.Hosts), output.Failures, output.IssueCount)
+	return &mcp.CallToolResult{Meta: map[string]interface{}{"failures": output.Failures}}, output, nil
+}
+
+// resolveBackendHost resolves a single backend host according to its service discovery type
+func resolveBackendHost(ctx context.Context, resolver *net.Resolver, nameserver string, h backendHost) HostResolution {
+	res := HostResolution{
+		Host:      h.Host,
+		SD:        h.SD,
+		Targets:   []ResolvedTarget{},
+		Locations: h.Locations,
+	}
+
+	if h.SD == "dns" {
+		res.Lookup = "SRV"
+		name := strings.TrimPrefix(strings.TrimPrefix(h.Host, "http://"), "https://")
+		_, records, err := resolver.LookupSRV(ctx, "", "", name)
+		if err != nil {
+			res.Error = fmt.Sprintf("SRV lookup failed: %v", err)
+			return res
+		}
+		for _, r := range records {
+			res.Targets = append(res.Targets, ResolvedTarget{
+				Address:  strings.TrimSuffix(r.Target, "."),
+				Port:     r.Port,
+				Priority: r.Priority,
+				Weight:   r.Weight,
+			})
+		}
+		res.TTL = lookupTTL(ctx, nameserver, name, dnsTypeSRV)
+		res.Issues = srvImbalanceIssues(res.Targets)
+		return res
+	}
+
+	_, hostname, _, err := splitBackendHost(h.Host)
+	if err != nil {
+		res.Error = err.Error()
+		return res
+	}
+
+	if ip := net.ParseIP(hostname); ip != nil {
+		res.Lookup = "literal"
+		res.Targets = append(res.Targets, ResolvedTarget{Address: ip.String()})
+		if ip.IsLoopback() {
+			res.Issues = append(res.Issues, "Host is a loopback address: it only works when the backend runs on the same machine as the gateway")
+		}
+		return res
+	}
+
+	res.Lookup = "A/AAAA"
+	addrs, err := resolver.LookupIPAddr(ctx, hostname)
+	if err != nil {
+		res.Error = fmt.Sprintf("lookup failed: %v", err)
+		return res
+	}
+	loopback := true
+	for _, a := range addrs {
+		res.Targets = append(res.Targets, ResolvedTarget{Address: a.IP.String()})
+		loopback = loopback && a.IP.IsLoopback()
+	}
+	if len(addrs) > 0 && loopback {
+		res.Issues = append(res.Issues, "Host resolves only to loopback addresses: it will not be reachable from a containerized or remote gateway")
+	}
+
+	res.TTL = lookupTTL(ctx, nameserver, hostname, dnsTypeA)
+	if res.TTL == nil {
+		res.TTL = lookupTTL(ctx, nameserver, hostname, dnsTypeAAAA)
+	}
+	return res
+}
+
+// lookupTTL queries the record TTL, returning nil when it cannot be determined
+func lookupTTL(ctx context.Context, nameserver, name string, qtype uint16) *uint32 {
+	if nameserver == "" {
+		return nil
+	}
+	ttl, err := queryDNSTTL(ctx, nameserver, name, qtype)
+	if err != nil {
+		return nil
+	}
+	return &ttl
+}
+
+// srvImbalanceIssues detects SRV record sets that will not spread traffic as expected
+func srvImbalanceIssues(targets []ResolvedTarget) []string {
+	var issues []string
+	if len(targets) == 0 {
+		return []string{"SRV lookup returned no targets: the backend will have no hosts to send traffic to"}
+	}
+	if len(targets) == 1 {
+		issues = append(issues, "Only one SRV target found: there is no redundancy for this backend")
+	}
+
+	byPriority := map[uint16][]ResolvedTarget{}
+	for _, t := range targets {
+		byPriority[t.Priority] = append(byPriority[t.Priority], t)
+	}
+	priorities := make([]int, 0, len(byPriority))
+	for p := range byPriority {
+		priorities = append(priorities, int(p))
+	}
+	sort.Ints(priorities)
+	if len(priorities) > 1 {
+		issues = append(issues, fmt.Sprintf("SRV targets use %d priority levels %v: only the lowest priority group receives traffic while it is available", len(priorities), priorities))
+	}
+
+	for _, priority := range priorities {
+		group := byPriority[uint16(priority)]
+		if len(group) < 2 {
+			continue
+		}
+		minW, maxW := group[0].Weight, group[0].Weight
+		for _, t := range group[1:] {
+			if t.Weight < minW {
+				minW = t.Weight
+			}
+			if t.Weight > maxW {
+				maxW = t.Weight
+			}
+		}
+		if minW == 0 && maxW > 0 {
+			issues = append(issues, fmt.Sprintf("Priority %d has targets with weight 0 alongside weighted targets: zero-weight targets will rarely receive traffic", priority))
+		} else if minW > 0 && maxW >= 2*minW {
+			issues = append(issues, fmt.Sprintf("Priority %d has uneven weights (%d to %d): traffic will be distributed unevenly", priority, minW, maxW))
+		}
+	}
+
+	return issues
+}
+
+// RegisterResolveTools registers DNS and service discovery tools
+func RegisterResolveTools(server *mcp.Server) {
+	toolkit.AddTool(server,
+		&mcp.Tool{
+			Name:        "resolve_backends",
+			Description: "Dry-run DNS and service discovery for every backend host in a KrakenD configuration. Resolves A/AAAA records for static hosts and SRV records when sd is dns, reporting resolved targets, TTLs and issues such as loopback-only hosts, missing redundancy or unbalanced SRV weights.",
+		},
+		ResolveBackends,
+	)
+}
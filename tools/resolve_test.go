@@ -0,0 +1,96 @@
+package tools
+
+import (
+	"context"
+	"encoding/binary"
+	"strings"
+	"testing"
+)
+
+func TestParseDNSAnswerTTL(t *testing.T) {
+	msg, err := buildDNSQuery(42, "api.example.com", dnsTypeA)
+	if err != nil {
+		t.Fatalf("buildDNSQuery() error = %v", err)
+	}
+
+	binary.BigEndian.PutUint16(msg[2:], 0x8180) // Response, no error
+	binary.BigEndian.PutUint16(msg[6:], 2)      // Two answers
+	for _, ttl := range []uint32{300, 60} {
+		msg = append(msg, 0xc0, 0x0c) // Pointer to question name
+		msg = binary.BigEndian.AppendUint16(msg, dnsTypeA)
+		msg = binary.BigEndian.AppendUint16(msg, 1)
+		msg = binary.BigEndian.AppendUint32(msg, ttl)
+		msg = binary.BigEndian.AppendUint16(msg, 4)
+		msg = append(msg, 10, 0, 0, 1)
+	}
+
+	ttl, err := parseDNSAnswerTTL(msg, dnsTypeA)
+	if err != nil {
+		t.Fatalf("parseDNSAnswerTTL() error = %v", err)
+	}
+	if ttl != 60 {
+		t.Errorf("Expected lowest TTL 60, got %d", ttl)
+	}
+
+	if _, err := parseDNSAnswerTTL(msg, dnsTypeSRV); err == nil {
+		t.Error("Expected error when no answer matches the requested type")
+	}
+}
+
+func TestSRVImbalanceIssues(t *testing.T) {
+	tests := []struct {
+		name    string
+		targets []ResolvedTarget
+		want    int
+	}{
+		{"balanced", []ResolvedTarget{{Weight: 10}, {Weight: 10}}, 0},
+		{"single target", []ResolvedTarget{{Weight: 10}}, 1},
+		{"uneven weights", []ResolvedTarget{{Weight: 10}, {Weight: 50}}, 1},
+		{"multiple priorities", []ResolvedTarget{{Priority: 1, Weight: 10}, {Priority: 2, Weight: 10}}, 1},
+		{"no targets", nil, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if issues := srvImbalanceIssues(tt.targets); len(issues) != tt.want {
+				t.Errorf("srvImbalanceIssues() = %v, want %d issue(s)", issues, tt.want)
+			}
+		})
+	}
+}
+
+func TestSRVImbalanceIssues_Order(t *testing.T) {
+	targets := []ResolvedTarget{
+		{Priority: 30, Weight: 0}, {Priority: 30, Weight: 10},
+		{Priority: 10, Weight: 10}, {Priority: 10, Weight: 40},
+		{Priority: 20, Weight: 5}, {Priority: 20, Weight: 50},
+	}
+	want := srvImbalanceIssues(targets)
+	if len(want) != 4 || !strings.HasPrefix(want[1], "Priority 10 ") || !strings.HasPrefix(want[2], "Priority 20 ") || !strings.HasPrefix(want[3], "Priority 30 ") {
+		t.Fatalf("Expected the issues by priority, got %v", want)
+	}
+	for i := 0; i < 20; i++ {
+		if got := srvImbalanceIssues(targets); strings.Join(got, "\n") != strings.Join(want, "\n") {
+			t.Fatalf("Issues changed order between runs: %v, then %v", want, got)
+		}
+	}
+}
+
+func TestResolveBackends_Loopback(t *testing.T) {
+	config := `{"endpoints": [{"backend": [{"host": ["http://127.0.0.1:8000"]}]}]}`
+
+	_, output, err := ResolveBackends(context.Background(), nil, ResolveBackendsInput{Config: config})
+	if err != nil {
+		t.Fatalf("ResolveBackends() error = %v", err)
+	}
+	if len(output.Hosts) != 1 {
+		t.Fatalf("Expected 1 host, got %d", len(output.Hosts))
+	}
+	host := output.Hosts[0]
+	if host.Lookup != "literal" || len(host.Targets) != 1 {
+		t.Errorf("Expected literal IP target, got %+v", host)
+	}
+	if len(host.Issues) == 0 {
+		t.Error("Expected loopback issue to be reported")
+	}
+}
@@ -0,0 +1,145 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+
+	"github.com/krakend/mcp-server/tools/validation"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Stable URIs of the resources. Schemas are addressed by edition (ce or ee) and version
+// (e.g. 2.7, or latest), examples by preset name.
+const (
+	featureCatalogURI = "krakend://features/catalog"
+	editionMatrixURI  = "krakend://features/edition-matrix"
+	schemaURITemplate = "krakend://schemas/{edition}/{version}"
+	schemaURIPrefix   = "krakend://schemas/"
+	exampleURIPrefix  = "krakend://examples/"
+	jsonMIMEType      = "application/json"
+)
+
+var schemaVersionPattern = regexp.MustCompile(`^\d+\.\d+(\.\d+)?$`)
+
+// jsonResource returns the contents of a resource holding a JSON document
+func jsonResource(uri string, value interface{}) (*mcp.ReadResourceResult, error) {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s: %w", uri, err)
+	}
+	return &mcp.ReadResourceResult{Contents: []*mcp.ResourceContents{{URI: uri, MIMEType: jsonMIMEType, Text: string(data)}}}, nil
+}
+
+// readFeatureResource serves the feature catalog and the edition matrix
+func readFeatureResource(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+	if featureCatalog == nil || editionMatrix == nil {
+		if err := LoadFeatureData(); err != nil {
+			return nil, fmt.Errorf("feature data unavailable: %w", err)
+		}
+	}
+	switch uri := req.Params.URI; uri {
+	case featureCatalogURI:
+		return jsonResource(uri, featureCatalog)
+	case editionMatrixURI:
+		return jsonResource(uri, editionMatrix)
+	default:
+		return nil, mcp.ResourceNotFoundError(uri)
+	}
+}
+
+// parseSchemaURI returns the edition and version of a krakend://schemas/{edition}/{version} URI
+func parseSchemaURI(uri string) (enterprise bool, version string, ok bool) {
+	edition, version, found := strings.Cut(strings.TrimPrefix(uri, schemaURIPrefix), "/")
+	if !found || !strings.HasPrefix(uri, schemaURIPrefix) || (edition != "ce" && edition != "ee") {
+		return false, "", false
+	}
+	version = strings.TrimPrefix(version, "v")
+	if version != "latest" && !schemaVersionPattern.MatchString(version) {
+		return false, "", false
+	}
+	return edition == "ee", version, true
+}
+
+// readSchemaResource serves the KrakenD JSON schema of an edition and version, downloaded once
+// and then read from the schema cache
+func readSchemaResource(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+	uri := req.Params.URI
+	enterprise, version, ok := parseSchemaURI(uri)
+	if !ok {
+		return nil, mcp.ResourceNotFoundError(uri)
+	}
+	data, err := validation.SchemaFetcher(ctx, validation.SchemaURL(version, enterprise))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch schema %s: %w", uri, err)
+	}
+	return &mcp.ReadResourceResult{Contents: []*mcp.ResourceContents{{URI: uri, MIMEType: jsonMIMEType, Text: string(data)}}}, nil
+}
+
+// readExampleResource serves the starter configuration of a generate_basic_config preset
+func readExampleResource(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+	uri := req.Params.URI
+	preset, ok := configPresets[strings.TrimPrefix(uri, exampleURIPrefix)]
+	if !ok {
+		return nil, mcp.ResourceNotFoundError(uri)
+	}
+	return jsonResource(uri, buildPresetConfig(preset, defaultConfigName, defaultConfigPort, defaultConfigBackend))
+}
+
+// RegisterResources registers the read-only resources clients can fetch without tool calls: the
+// feature catalog, the edition matrix, the JSON schemas of every version, example
+// configurations and the documentation pages. It returns the number of resources and templates
+// registered.
+func RegisterResources(server *mcp.Server) int {
+	server.AddResource(&mcp.Resource{
+		URI:         featureCatalogURI,
+		Name:        "feature-catalog",
+		Title:       "KrakenD feature catalog",
+		Description: "Every KrakenD feature with its namespace, edition, category and documentation URL",
+		MIMEType:    jsonMIMEType,
+	}, readFeatureResource)
+	server.AddResource(&mcp.Resource{
+		URI:         editionMatrixURI,
+		Name:        "edition-matrix",
+		Title:       "KrakenD edition matrix",
+		Description: "Namespaces available in the Community Edition and those exclusive to the Enterprise Edition",
+		MIMEType:    jsonMIMEType,
+	}, readFeatureResource)
+	count := 2
+
+	server.AddResourceTemplate(&mcp.ResourceTemplate{
+		URITemplate: schemaURITemplate,
+		Name:        "schema",
+		Title:       "KrakenD JSON schema",
+		Description: "JSON schema of a KrakenD version: edition is ce or ee, version is e.g. 2.7 or latest",
+		MIMEType:    jsonMIMEType,
+	}, readSchemaResource)
+	for _, edition := range []string{"ce", "ee"} {
+		server.AddResource(&mcp.Resource{
+			URI:         schemaURIPrefix + edition + "/latest",
+			Name:        "schema-" + edition + "-latest",
+			Title:       fmt.Sprintf("KrakenD %s JSON schema (latest)", strings.ToUpper(edition)),
+			Description: "JSON schema of the latest KrakenD version",
+			MIMEType:    jsonMIMEType,
+		}, readSchemaResource)
+	}
+	count += 3
+
+	for _, name := range PresetNames() {
+		server.AddResource(&mcp.Resource{
+			URI:         exampleURIPrefix + name,
+			Name:        "example-" + name,
+			Title:       "Example configuration: " + name,
+			Description: configPresets[name].Description,
+			MIMEType:    jsonMIMEType,
+		}, readExampleResource)
+		count++
+	}
+	count += registerDocResources(server)
+
+	slog.Info("Resources registered", "resources", count)
+	return count
+}
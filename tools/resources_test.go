@@ -0,0 +1,84 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func connectResources(t *testing.T) *mcp.ClientSession {
+	t.Helper()
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "0.0.1"}, nil)
+	RegisterResources(server)
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	if _, err := server.Connect(context.Background(), serverTransport, nil); err != nil {
+		t.Fatal(err)
+	}
+	client := mcp.NewClient(&mcp.Implementation{Name: "test", Version: "0.0.1"}, nil)
+	session, err := client.Connect(context.Background(), clientTransport, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { session.Close() })
+	return session
+}
+
+func TestResources_List(t *testing.T) {
+	session := connectResources(t)
+	resources, err := session.ListResources(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("ListResources() error = %v", err)
+	}
+	uris := map[string]bool{}
+	for _, r := range resources.Resources {
+		uris[r.URI] = true
+	}
+	for _, uri := range []string{featureCatalogURI, editionMatrixURI, "krakend://schemas/ee/latest", "krakend://examples/mobile-bff", docIndexURI} {
+		if !uris[uri] {
+			t.Errorf("Expected resource %s, got %v", uri, uris)
+		}
+	}
+	templates, err := session.ListResourceTemplates(context.Background(), nil)
+	if err != nil || len(templates.ResourceTemplates) != 4 {
+		t.Errorf("Expected the schema and documentation templates, got %+v (%v)", templates, err)
+	}
+}
+
+func TestResources_Read(t *testing.T) {
+	setMockFeatureFetcher(t, minimalFeatureYAML)
+	requested := setMockSchemaFetcher(t)
+	session := connectResources(t)
+
+	tests := []struct {
+		uri  string
+		want string
+	}{
+		{featureCatalogURI, `"features"`},
+		{editionMatrixURI, `"ee_only_features"`},
+		{"krakend://examples/partner-api", `"https://partner.example.com"`},
+		{"krakend://schemas/ee/2.7", `"$schema"`},
+	}
+	for _, tt := range tests {
+		res, err := session.ReadResource(context.Background(), &mcp.ReadResourceParams{URI: tt.uri})
+		if err != nil {
+			t.Errorf("ReadResource(%s) error = %v", tt.uri, err)
+			continue
+		}
+		text := res.Contents[0].Text
+		if res.Contents[0].MIMEType != jsonMIMEType || !json.Valid([]byte(text)) || !strings.Contains(text, tt.want) {
+			t.Errorf("ReadResource(%s) = %s, want %s", tt.uri, text, tt.want)
+		}
+	}
+	if len(*requested) != 1 || (*requested)[0] != "https://www.krakend.io/schema/ee/v2.7/krakend.json" {
+		t.Errorf("Unexpected schema downloads: %v", *requested)
+	}
+
+	for _, uri := range []string{"krakend://schemas/xx/2.7", "krakend://schemas/ce/../../etc", "krakend://examples/unknown"} {
+		if _, err := session.ReadResource(context.Background(), &mcp.ReadResourceParams{URI: uri}); err == nil {
+			t.Errorf("ReadResource(%s) expected an error", uri)
+		}
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"strings"
 
 	"github.com/krakend/mcp-server/internal/runtime"
+	"github.com/krakend/mcp-server/internal/toolkit"
+	"github.com/krakend/mcp-server/tools/validation"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
@@ -39,7 +41,7 @@ func DetectRuntimeEnvironment(ctx context.Context, req *mcp.CallToolRequest, inp
 
 // RegisterRuntimeTools registers runtime-related tools with the MCP server
 func RegisterRuntimeTools(server *mcp.Server) {
-	mcp.AddTool(server,
+	toolkit.AddTool(server,
 		&mcp.Tool{
 			Name:        "detect_runtime_environment",
 			Description: "Detects the optimal runtime environment for KrakenD (native binary vs Docker), checks version compatibility, and provides execution recommendations. Useful for determining how to run KrakenD commands (check, audit, run, etc.) based on available tools and configuration requirements.",
@@ -65,3 +67,13 @@ func readConfigContent(config string) (string, error) {
 
 	return string(content), nil
 }
+
+// readEncodedConfig reads a configuration sent with a config_encoding (base64 or gzip+base64),
+// decoded first, or a JSON string or file path without encoding
+func readEncodedConfig(config, encoding string) (string, error) {
+	decoded, err := validation.DecodeConfig(config, encoding)
+	if err != nil {
+		return "", err
+	}
+	return readConfigContent(decoded)
+}
@@ -0,0 +1,233 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/krakend/mcp-server/internal/toolkit"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const redactedValue = "<REDACTED>"
+
+// secretKeyPattern matches configuration keys whose values must never be shared
+var secretKeyPattern = regexp.MustCompile(`(?i)(password|passwd|secret|token|api_?key|private_key|credential|auth_header|signing_key|encryption_key|^key$|^k$|dsn|connection_string)`)
+
+// urlPattern matches absolute URLs embedded in string values
+var urlPattern = regexp.MustCompile(`[a-zA-Z][a-zA-Z0-9+.-]*://[^\s"'<>]+`)
+
+// SanitizeConfigInput defines input for sanitize_config_for_sharing tool
+type SanitizeConfigInput struct {
+	Config    string `json:"config" jsonschema:"KrakenD configuration (JSON string or file path)"`
+	OutputDir string `json:"output_dir,omitempty" jsonschema:"Directory where krakend.sanitized.json and the host mapping file are written (optional)"`
+}
+
+// SanitizeConfigOutput defines output for sanitize_config_for_sharing tool
+type SanitizeConfigOutput struct {
+	Config      map[string]interface{} `json:"config"`
+	HostMapping map[string]string      `json:"host_mapping"` // Placeholder → original host
+	Redacted    []string               `json:"redacted"`     // JSON paths of redacted secrets
+	ConfigFile  string                 `json:"config_file,omitempty"`
+	MappingFile string                 `json:"mapping_file,omitempty"`
+	Message     string                 `json:"message"`
+}
+
+// configSanitizer keeps track of the placeholders assigned while sanitizing a config
+type configSanitizer struct {
+	hosts    map[string]string // Original host → placeholder
+	mapping  map[string]string // Placeholder → original host
+	redacted []string
+}
+
+// SanitizeConfigForSharing strips secrets and replaces hosts with placeholders so a config can be shared
+func SanitizeConfigForSharing(ctx context.Context, req *mcp.CallToolRequest, input SanitizeConfigInput) (*mcp.CallToolResult, SanitizeConfigOutput, error) {
+	configContent, err := readConfigContent(input.Config)
+	if err != nil {
+		return nil, SanitizeConfigOutput{}, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	var config map[string]interface{}
+	if err := json.Unmarshal([]byte(configContent), &config); err != nil {
+		return nil, SanitizeConfigOutput{}, fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	s := &configSanitizer{
+		hosts:    map[string]string{},
+		mapping:  map[string]string{},
+		redacted: []string{},
+	}
+	sanitized := s.sanitize(config, "$", "").(map[string]interface{})
+	sort.Strings(s.redacted)
+
+	output := SanitizeConfigOutput{
+		Config:      sanitized,
+		HostMapping: s.mapping,
+		Redacted:    s.redacted,
+	}
+
+	if input.OutputDir != "" {
+		if err := os.MkdirAll(input.OutputDir, 0o755); err != nil {
+			return nil, SanitizeConfigOutput{}, fmt.Errorf("failed to create output directory: %w", err)
+		}
+		output.ConfigFile = filepath.Join(input.OutputDir, "krakend.sanitized.json")
+		output.MappingFile = filepath.Join(input.OutputDir, "krakend.sanitized.mapping.json")
+		if err := writeJSONFile(output.ConfigFile, sanitized, 0o644); err != nil {
+			return nil, SanitizeConfigOutput{}, err
+		}
+		// The mapping reveals real hosts: keep it private to the user
+		if err := writeJSONFile(output.MappingFile, s.mapping, 0o600); err != nil {
+			return nil, SanitizeConfigOutput{}, err
+		}
+	}
+
+	output.Message = fmt.Sprintf("Redacted %d secret(s) and replaced %d host(s) with placeholders. Share only the sanitized config; keep the host mapping private.", len(s.redacted), len(s.mapping))
+	return nil, output, nil
+}
+
+// sanitize returns a sanitized copy of value located at path under key
+func (s *configSanitizer) sanitize(value interface{}, path, key string) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for k, child := range v {
+			childPath := path + "." + k
+			if strings.Contains(k, "/") || strings.Contains(k, ".") {
+				childPath = fmt.Sprintf("%s['%s']", path, k)
+			}
+			// Namespaces (keys with "/") are preserved; only their values are inspected
+			if isSecretKey(k) && !isEmptyValue(child) {
+				out[k] = redactValue(child)
+				s.redacted = append(s.redacted, childPath)
+				continue
+			}
+			out[k] = s.sanitize(child, childPath, k)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, child := range v {
+			out[i] = s.sanitize(child, fmt.Sprintf("%s[%d]", path, i), key)
+		}
+		return out
+	case string:
+		if key == "host" || key == "hosts" {
+			return s.placeholderURL(v)
+		}
+		return urlPattern.ReplaceAllStringFunc(v, s.placeholderURL)
+	default:
+		return v
+	}
+}
+
+// placeholderURL replaces the host part of raw, keeping scheme, port and path
+func (s *configSanitizer) placeholderURL(raw string) string {
+	withScheme := raw
+	if !strings.Contains(raw, "://") {
+		withScheme = "http://" + raw
+	}
+	u, err := url.Parse(withScheme)
+	if err != nil || u.Hostname() == "" {
+		return raw
+	}
+
+	hostname := u.Hostname()
+	if hostname == "localhost" || hostname == "127.0.0.1" || hostname == "::1" || strings.HasSuffix(hostname, "krakend.io") {
+		return raw
+	}
+
+	placeholder, ok := s.hosts[hostname]
+	if !ok {
+		placeholder = fmt.Sprintf("host-%d.example.invalid", len(s.hosts)+1)
+		s.hosts[hostname] = placeholder
+		s.mapping[placeholder] = hostname
+	}
+
+	if u.User != nil {
+		u.User = url.User(redactedValue)
+	}
+	if port := u.Port(); port != "" {
+		u.Host = placeholder + ":" + port
+	} else {
+		u.Host = placeholder
+	}
+
+	result := u.String()
+	if !strings.Contains(raw, "://") {
+		result = strings.TrimPrefix(result, "http://")
+	}
+	return result
+}
+
+// isSecretKey reports whether a configuration key holds a secret.
+// Namespaces and URL fields (e.g. token_url) are never considered secrets.
+func isSecretKey(key string) bool {
+	if strings.Contains(key, "/") || strings.HasSuffix(strings.ToLower(key), "url") {
+		return false
+	}
+	return secretKeyPattern.MatchString(key)
+}
+
+// redactValue replaces a secret keeping its JSON type so the structure remains valid
+func redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i := range v {
+			out[i] = redactedValue
+		}
+		return out
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for k := range v {
+			out[k] = redactedValue
+		}
+		return out
+	case bool, float64:
+		return v
+	default:
+		return redactedValue
+	}
+}
+
+// isEmptyValue reports whether a secret-looking value has nothing to hide
+func isEmptyValue(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case bool, float64:
+		return true
+	}
+	return false
+}
+
+// writeJSONFile writes value as indented JSON to path
+func writeJSONFile(path string, value interface{}, perm os.FileMode) error {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", path, err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), perm); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// RegisterSanitizeTools registers config sharing tools
+func RegisterSanitizeTools(server *mcp.Server) {
+	toolkit.AddTool(server,
+		&mcp.Tool{
+			Name:        "sanitize_config_for_sharing",
+			Description: "Produce a shareable copy of a KrakenD configuration for community or support tickets: secrets are redacted, hosts and URLs are replaced with placeholders, and structure and namespaces are preserved. Returns the placeholder-to-host mapping (optionally written to a private file) so answers can be mapped back.",
+		},
+		SanitizeConfigForSharing,
+	)
+}
@@ -0,0 +1,76 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+)
+
+const sanitizeTestConfig = `{
+	"version": 3,
+	"extra_config": {
+		"auth/signer": {"keys_to_sign": ["sub"], "jwk_url": "https://auth.corp.internal/jwks.json"},
+		"telemetry/influx": {"address": "http://influx.corp.internal:8086", "username": "admin", "password": "hunter2"}
+	},
+	"endpoints": [{
+		"endpoint": "/users",
+		"backend": [{
+			"host": ["https://users.corp.internal:8443", "orders.corp.internal"],
+			"extra_config": {"auth/client-credentials": {"client_id": "gw", "client_secret": "s3cr3t", "token_url": "https://auth.corp.internal/token"}}
+		}]
+	}]
+}`
+
+func TestSanitizeConfigForSharing(t *testing.T) {
+	_, output, err := SanitizeConfigForSharing(context.Background(), nil, SanitizeConfigInput{Config: sanitizeTestConfig})
+	if err != nil {
+		t.Fatalf("SanitizeConfigForSharing() error = %v", err)
+	}
+
+	data, _ := json.Marshal(output.Config)
+	sanitized := string(data)
+	for _, leaked := range []string{"hunter2", "s3cr3t", "corp.internal"} {
+		if strings.Contains(sanitized, leaked) {
+			t.Errorf("Sanitized config still contains %q: %s", leaked, sanitized)
+		}
+	}
+
+	// Namespaces and structure are preserved
+	for _, ns := range []string{"auth/signer", "telemetry/influx", "auth/client-credentials", "keys_to_sign"} {
+		if !strings.Contains(sanitized, ns) {
+			t.Errorf("Expected %q to be preserved", ns)
+		}
+	}
+	if !strings.Contains(sanitized, ":8443") {
+		t.Error("Expected ports to be preserved")
+	}
+
+	// Same host maps to the same placeholder
+	if len(output.HostMapping) != 4 {
+		t.Errorf("Expected 4 distinct hosts in mapping, got %v", output.HostMapping)
+	}
+	if len(output.Redacted) != 2 {
+		t.Errorf("Expected 2 redacted secrets, got %v", output.Redacted)
+	}
+}
+
+func TestSanitizeConfigForSharing_WritesFiles(t *testing.T) {
+	dir := t.TempDir()
+	_, output, err := SanitizeConfigForSharing(context.Background(), nil, SanitizeConfigInput{Config: sanitizeTestConfig, OutputDir: dir})
+	if err != nil {
+		t.Fatalf("SanitizeConfigForSharing() error = %v", err)
+	}
+
+	info, err := os.Stat(output.MappingFile)
+	if err != nil {
+		t.Fatalf("Expected mapping file: %v", err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf("Expected private mapping file, got %v", info.Mode().Perm())
+	}
+	if _, err := os.Stat(output.ConfigFile); err != nil {
+		t.Errorf("Expected sanitized config file: %v", err)
+	}
+}
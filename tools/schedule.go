@@ -0,0 +1,254 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// scheduleValidator and scheduleAuditor run the scheduled validations and audits. They can be
+// replaced in tests.
+var (
+	scheduleValidator = ValidateConfig
+	scheduleAuditor   = AuditSecurity
+)
+
+// AuditSchedule re-validates and re-audits a configuration periodically, every given duration
+// or following a cron expression
+type AuditSchedule struct {
+	Name   string `yaml:"name"`
+	Config string `yaml:"config"` // Configuration file path
+	Every  string `yaml:"every"`  // Go duration, e.g. 6h
+	Cron   string `yaml:"cron"`   // Five-field cron expression (minute hour day-of-month month day-of-week), e.g. "0 3 * * 1-5"
+}
+
+// auditSchedule is a validated AuditSchedule
+type auditSchedule struct {
+	AuditSchedule
+	every time.Duration
+	cron  *cronSchedule
+}
+
+// next returns the first run strictly after t
+func (s auditSchedule) next(t time.Time) time.Time {
+	if s.cron != nil {
+		return s.cron.next(t)
+	}
+	return t.Add(s.every)
+}
+
+// cronSchedule is a parsed five-field cron expression
+type cronSchedule struct {
+	minute, hour, dom, month, dow [64]bool
+	domAny, dowAny                bool // Field was *: with both restricted a day matches either of them
+}
+
+// cronFields are the bounds of every field of a cron expression
+var cronFields = []struct {
+	name     string
+	min, max int
+}{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	{"day of week", 0, 7},
+}
+
+// parseCron parses a five-field cron expression supporting *, lists, ranges and steps
+func parseCron(expr string) (*cronSchedule, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != len(cronFields) {
+		return nil, fmt.Errorf("invalid cron %q: expected 5 fields (minute hour day-of-month month day-of-week)", expr)
+	}
+	c := &cronSchedule{domAny: fields[2] == "*", dowAny: fields[4] == "*"}
+	targets := []*[64]bool{&c.minute, &c.hour, &c.dom, &c.month, &c.dow}
+	for i, field := range fields {
+		if err := parseCronField(field, cronFields[i].min, cronFields[i].max, targets[i]); err != nil {
+			return nil, fmt.Errorf("invalid cron %q: %s: %w", expr, cronFields[i].name, err)
+		}
+	}
+	if c.dow[7] {
+		c.dow[0] = true // Both 0 and 7 are Sunday
+	}
+	return c, nil
+}
+
+// parseCronField sets the values matched by a comma-separated list of *, n, a-b and */step items
+func parseCronField(field string, min, max int, values *[64]bool) error {
+	for _, item := range strings.Split(field, ",") {
+		rangeExpr, stepExpr, hasStep := strings.Cut(item, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepExpr)
+			if err != nil || n <= 0 {
+				return fmt.Errorf("invalid step %q", stepExpr)
+			}
+			step = n
+		}
+		from, to := min, max
+		if rangeExpr != "*" {
+			start, end, isRange := strings.Cut(rangeExpr, "-")
+			var err error
+			if from, err = strconv.Atoi(start); err != nil {
+				return fmt.Errorf("invalid value %q", start)
+			}
+			to = from
+			if isRange {
+				if to, err = strconv.Atoi(end); err != nil {
+					return fmt.Errorf("invalid value %q", end)
+				}
+			} else if hasStep {
+				to = max
+			}
+		}
+		if from < min || to > max || from > to {
+			return fmt.Errorf("%q out of range %d-%d", item, min, max)
+		}
+		for v := from; v <= to; v += step {
+			values[v] = true
+		}
+	}
+	return nil
+}
+
+// matchesDay reports whether the day of t matches the day-of-month and day-of-week fields
+func (c *cronSchedule) matchesDay(t time.Time) bool {
+	dom, dow := c.dom[t.Day()], c.dow[int(t.Weekday())]
+	switch {
+	case c.domAny && c.dowAny:
+		return true
+	case c.domAny:
+		return dow
+	case c.dowAny:
+		return dom
+	}
+	return dom || dow
+}
+
+// next returns the first minute matching the expression strictly after t, or the zero time
+// when none matches within five years (e.g. February 30th)
+func (c *cronSchedule) next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		if !c.month[int(t.Month())] {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !c.matchesDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !c.hour[t.Hour()] {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if !c.minute[t.Minute()] {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// parseAuditSchedules validates the schedules of the server configuration
+func parseAuditSchedules(schedules []AuditSchedule) ([]auditSchedule, error) {
+	parsed := make([]auditSchedule, 0, len(schedules))
+	for i, s := range schedules {
+		if s.Name == "" {
+			s.Name = fmt.Sprintf("schedule-%d", i+1)
+		}
+		if s.Config == "" {
+			return nil, fmt.Errorf("schedule %s: config is required", s.Name)
+		}
+		schedule := auditSchedule{AuditSchedule: s}
+		switch {
+		case s.Every != "" && s.Cron != "":
+			return nil, fmt.Errorf("schedule %s: set either every or cron, not both", s.Name)
+		case s.Every != "":
+			every, err := time.ParseDuration(s.Every)
+			if err != nil || every < time.Minute {
+				return nil, fmt.Errorf("schedule %s: every must be a duration of at least 1m, got %q", s.Name, s.Every)
+			}
+			schedule.every = every
+		case s.Cron != "":
+			cron, err := parseCron(s.Cron)
+			if err != nil {
+				return nil, fmt.Errorf("schedule %s: %w", s.Name, err)
+			}
+			if cron.next(time.Now()).IsZero() {
+				return nil, fmt.Errorf("schedule %s: cron %q never runs", s.Name, s.Cron)
+			}
+			schedule.cron = cron
+		default:
+			return nil, fmt.Errorf("schedule %s: every or cron is required", s.Name)
+		}
+		parsed = append(parsed, schedule)
+	}
+	return parsed, nil
+}
+
+// runScheduledAudit validates and audits the configuration of a schedule, recording the
+// results in the audit history
+func runScheduledAudit(ctx context.Context, schedule auditSchedule) AuditHistoryEntry {
+	entry := AuditHistoryEntry{Time: time.Now().UTC(), Schedule: schedule.Name, Config: schedule.Config}
+	_, validationResult, err := scheduleValidator(ctx, nil, ValidateConfigInput{Config: schedule.Config})
+	if err != nil {
+		entry.Error = "validation: " + err.Error()
+	} else {
+		entry.Valid = validationResult.Valid
+		entry.ValidationMethod = validationResult.Method
+		entry.Errors, entry.Warnings = len(validationResult.Errors), len(validationResult.Warnings)
+	}
+	_, auditResult, err := scheduleAuditor(ctx, nil, AuditSecurityInput{Config: schedule.Config})
+	if err != nil {
+		if entry.Error != "" {
+			entry.Error += "; "
+		}
+		entry.Error += "audit: " + err.Error()
+	} else {
+		entry.AuditMethod, entry.Score = auditResult.Method, auditResult.Score
+		entry.Issues = map[string]int{}
+		for _, issue := range auditResult.Issues {
+			entry.Issues[issue.Severity]++
+		}
+	}
+	if err := appendAuditHistory(entry); err != nil {
+		slog.Error("Failed to record scheduled audit", "schedule", schedule.Name, "error", err)
+	}
+	return entry
+}
+
+// StartAuditScheduler validates the audit schedules of the server configuration and runs them
+// in the background until the context is canceled
+func StartAuditScheduler(ctx context.Context, schedules []AuditSchedule) error {
+	parsed, err := parseAuditSchedules(schedules)
+	if err != nil {
+		return err
+	}
+	for _, schedule := range parsed {
+		go func() {
+			next := schedule.next(time.Now())
+			for {
+				timer := time.NewTimer(time.Until(next))
+				select {
+				case <-ctx.Done():
+					timer.Stop()
+					return
+				case <-timer.C:
+				}
+				entry := runScheduledAudit(ctx, schedule)
+				if entry.Error != "" {
+					slog.Error("Scheduled audit failed", "schedule", schedule.Name, "config", schedule.Config, "error", entry.Error)
+				}
+				next = schedule.next(time.Now())
+			}
+		}()
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestCronNext(t *testing.T) {
+	from := time.Date(2026, time.October, 16, 10, 7, 30, 0, time.UTC) // Friday
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2026, time.October, 16, 10, 8, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2026, time.October, 16, 10, 15, 0, 0, time.UTC)},
+		{"0 3 * * *", time.Date(2026, time.October, 17, 3, 0, 0, 0, time.UTC)},
+		{"0 3 * * 1-5", time.Date(2026, time.October, 19, 3, 0, 0, 0, time.UTC)},
+		{"30 8 1 * *", time.Date(2026, time.November, 1, 8, 30, 0, 0, time.UTC)},
+		{"0 0 1 1 *", time.Date(2027, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 12 * * 7", time.Date(2026, time.October, 18, 12, 0, 0, 0, time.UTC)},
+		// Both day fields restricted: either of them matches
+		{"0 0 20 * 6", time.Date(2026, time.October, 17, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		cron, err := parseCron(tt.expr)
+		if err != nil {
+			t.Fatalf("parseCron(%q) error = %v", tt.expr, err)
+		}
+		if got := cron.next(from); !got.Equal(tt.want) {
+			t.Errorf("next(%q) = %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+
+	for _, expr := range []string{"* * * *", "60 * * * *", "*/0 * * * *", "5-1 * * * *", "a * * * *"} {
+		if _, err := parseCron(expr); err == nil {
+			t.Errorf("parseCron(%q) expected an error", expr)
+		}
+	}
+}
+
+func TestParseAuditSchedules(t *testing.T) {
+	parsed, err := parseAuditSchedules([]AuditSchedule{{Config: "a.json", Every: "1h"}, {Name: "nightly", Config: "b.json", Cron: "0 3 * * *"}})
+	if err != nil {
+		t.Fatalf("parseAuditSchedules() error = %v", err)
+	}
+	from := time.Date(2026, time.October, 16, 10, 0, 0, 0, time.UTC)
+	if parsed[0].Name != "schedule-1" || !parsed[0].next(from).Equal(from.Add(time.Hour)) {
+		t.Errorf("Unexpected every schedule: %+v", parsed[0])
+	}
+
+	for _, schedules := range [][]AuditSchedule{
+		{{Every: "1h"}},
+		{{Config: "a.json"}},
+		{{Config: "a.json", Every: "1h", Cron: "* * * * *"}},
+		{{Config: "a.json", Every: "10s"}},
+		{{Config: "a.json", Cron: "0 0 30 2 *"}},
+	} {
+		if _, err := parseAuditSchedules(schedules); err == nil {
+			t.Errorf("Expected an error for %+v", schedules)
+		}
+	}
+}
+
+func TestScheduledAuditHistory(t *testing.T) {
+	oldDataDir := dataDir
+	dataDir = t.TempDir()
+	defer func() { dataDir = oldDataDir }()
+	validator, auditor := scheduleValidator, scheduleAuditor
+	t.Cleanup(func() { scheduleValidator, scheduleAuditor = validator, auditor })
+
+	scores := []int{90, 70}
+	run := 0
+	scheduleValidator = func(ctx context.Context, req *mcp.CallToolRequest, input ValidateConfigInput) (*mcp.CallToolResult, ValidateConfigOutput, error) {
+		var output ValidateConfigOutput
+		output.Valid, output.Method = run == 0, "schema"
+		return nil, output, nil
+	}
+	scheduleAuditor = func(ctx context.Context, req *mcp.CallToolRequest, input AuditSecurityInput) (*mcp.CallToolResult, AuditSecurityOutput, error) {
+		if input.Config == "broken.json" {
+			return nil, AuditSecurityOutput{}, fmt.Errorf("failed to read config")
+		}
+		return nil, AuditSecurityOutput{Method: "basic", Score: scores[run], Issues: []SecurityIssue{{Severity: "high"}}}, nil
+	}
+
+	schedules, err := parseAuditSchedules([]AuditSchedule{{Name: "gw", Config: "gw.json", Every: "1h"}, {Name: "broken", Config: "broken.json", Every: "1h"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for run = 0; run < len(scores); run++ {
+		runScheduledAudit(context.Background(), schedules[0])
+	}
+	run = 0
+	if entry := runScheduledAudit(context.Background(), schedules[1]); entry.Error == "" {
+		t.Errorf("Expected the failed audit in the entry, got %+v", entry)
+	}
+
+	_, output, err := GetAuditHistory(context.Background(), nil, GetAuditHistoryInput{})
+	if err != nil {
+		t.Fatalf("GetAuditHistory() error = %v", err)
+	}
+	if output.Total != 3 || len(output.Entries) != 3 || output.Entries[0].Config != "broken.json" {
+		t.Fatalf("Expected 3 runs, newest first, got %+v", output)
+	}
+	if len(output.Trends) != 2 {
+		t.Fatalf("Expected 2 trends, got %+v", output.Trends)
+	}
+	trend := output.Trends[1]
+	if trend.Config != "gw.json" || trend.Runs != 2 || trend.ScoreDelta != -20 || trend.Failures != 1 || trend.LastValid {
+		t.Errorf("Unexpected trend: %+v", trend)
+	}
+	if output.Entries[1].Issues["high"] != 1 {
+		t.Errorf("Expected the issues per severity, got %+v", output.Entries[1])
+	}
+
+	_, output, err = GetAuditHistory(context.Background(), nil, GetAuditHistoryInput{Config: "gw.json", Limit: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if output.Total != 2 || len(output.Entries) != 1 || output.Entries[0].Score != 70 {
+		t.Errorf("Expected the last gw.json run, got %+v", output)
+	}
+	if _, _, err := GetAuditHistory(context.Background(), nil, GetAuditHistoryInput{Since: "yesterday"}); err == nil {
+		t.Error("Expected an error for an invalid since")
+	}
+}
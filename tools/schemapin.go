@@ -0,0 +1,249 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/krakend/mcp-server/internal/toolkit"
+	"github.com/krakend/mcp-server/tools/validation"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// PinSchemaVersionInput defines input for pin_schema_version tool
+type PinSchemaVersionInput struct {
+	Config    string `json:"config,omitempty" jsonschema:"KrakenD configuration as JSON string or file path (optional if directory is given)"`
+	Directory string `json:"directory,omitempty" jsonschema:"Project directory: every configuration root and $schema reference found recursively is rewritten (optional)"`
+	Pattern   string `json:"pattern,omitempty" jsonschema:"Comma-separated file name globs of the project files (optional, defaults to *.json,*.tmpl)"`
+	Version   string `json:"version,omitempty" jsonschema:"Version to pin, e.g. 2.7, or latest to unpin (optional, defaults to the version the latest schema currently resolves to)"`
+	Edition   string `json:"edition,omitempty" jsonschema:"Schema edition: ce or ee (optional, keeps the edition of each file)"`
+	Write     bool   `json:"write,omitempty" jsonschema:"Write the changes to the files (optional, defaults to returning the patch only)"`
+}
+
+// PinnedFile is a file whose $schema was pinned or unpinned
+type PinnedFile struct {
+	Path     string   `json:"path"`
+	Previous []string `json:"previous,omitempty"` // $schema declarations before the change
+	Schema   string   `json:"schema"`
+	Changed  bool     `json:"changed"`
+	Written  bool     `json:"written,omitempty"`
+	Skipped  string   `json:"skipped,omitempty"` // Why the file could not be changed
+}
+
+// PinSchemaVersionOutput defines output for pin_schema_version tool
+type PinSchemaVersionOutput struct {
+	Version      string       `json:"version"`       // Pinned version, or latest when unpinned
+	ResolvedFrom string       `json:"resolved_from"` // input, latest schema or local krakend
+	Files        []PinnedFile `json:"files"`
+	Config       string       `json:"config,omitempty"` // Rewritten configuration when given as a JSON string
+	Patch        string       `json:"patch,omitempty"`  // Unified diff of the changes (git apply --unidiff-zero)
+	Warnings     []string     `json:"warnings"`
+	Summary      string       `json:"summary"`
+}
+
+// latestSchemaVersion returns the version the latest schema currently points to: it is only a
+// $ref to the versioned schema, e.g. "v2.12/krakend.json"
+func latestSchemaVersion(ctx context.Context, enterprise bool) (string, error) {
+	data, err := validation.SchemaFetcher(ctx, SchemaURL("latest", enterprise))
+	if err != nil {
+		return "", err
+	}
+	var schema struct {
+		Ref string `json:"$ref"`
+	}
+	if json.Unmarshal(data, &schema) != nil || !strings.HasPrefix(schema.Ref, "v") {
+		return "", fmt.Errorf("the latest schema does not point to a versioned one")
+	}
+	return strings.TrimPrefix(strings.SplitN(schema.Ref, "/", 2)[0], "v"), nil
+}
+
+// resolveCurrentVersion returns the version the latest schema currently points to, falling back
+// to the version of the local krakend binary
+func resolveCurrentVersion(ctx context.Context, enterprise bool) (version, from string, err error) {
+	if version, err := latestSchemaVersion(ctx, enterprise); err == nil {
+		return version, "latest schema", nil
+	}
+	if version, err := GetLocalKrakenDVersion(); err == nil {
+		return version, "local krakend", nil
+	}
+	return "", "", fmt.Errorf("could not resolve the latest version (offline and no local krakend): pass version")
+}
+
+// pinSchemaWarnings explains the consequences of moving from the previous versions to the new one
+func pinSchemaWarnings(previous []string, version string) []string {
+	var warnings []string
+	if version == "latest" {
+		return []string{
+			"Unpinned configurations are validated against the newest KrakenD release: the same file can pass today and fail after a release",
+			"Editors and validate_config download the latest schema: properties deprecated by a new release start failing without changes in the config",
+			"Deploy with a KrakenD image of the same version the configuration was last validated with, or pin it again before releasing",
+		}
+	}
+	for _, old := range previous {
+		switch {
+		case old == version:
+		case old == "latest":
+			warnings = append(warnings, fmt.Sprintf("Pinning latest to %s: validation stays on %s until the schema is pinned again", version, version))
+		case compareVersions(old, version) < 0:
+			warnings = append(warnings, fmt.Sprintf("Upgrading from %s to %s: run validate_config, newer schemas may reject deprecated properties", old, version))
+		default:
+			warnings = append(warnings, fmt.Sprintf("Downgrading from %s to %s: namespaces and properties added after %s fail validation", old, version, version))
+		}
+	}
+	return warnings
+}
+
+// PinSchemaVersion rewrites the $schema of a configuration, or of every file of a project, to a
+// version-pinned schema so validation is reproducible, or to the latest schema to unpin it
+func PinSchemaVersion(ctx context.Context, req *mcp.CallToolRequest, input PinSchemaVersionInput) (*mcp.CallToolResult, PinSchemaVersionOutput, error) {
+	if input.Config == "" && input.Directory == "" {
+		return nil, PinSchemaVersionOutput{}, fmt.Errorf("config or directory is required")
+	}
+	if input.Edition != "" && input.Edition != "ce" && input.Edition != "ee" {
+		return nil, PinSchemaVersionOutput{}, fmt.Errorf("invalid edition %q (expected ce or ee)", input.Edition)
+	}
+	inline := strings.HasPrefix(strings.TrimSpace(input.Config), "{")
+	if input.Write && inline && input.Directory == "" {
+		return nil, PinSchemaVersionOutput{}, fmt.Errorf("write needs a config file path or a directory")
+	}
+
+	// The files to rewrite with their content
+	var paths []string
+	contents := map[string]string{}
+	if input.Config != "" {
+		content, err := readConfigContent(input.Config)
+		if err != nil {
+			return nil, PinSchemaVersionOutput{}, fmt.Errorf("failed to read config: %w", err)
+		}
+		name := input.Config
+		if inline {
+			name = "config.json"
+		}
+		paths = append(paths, name)
+		contents[name] = content
+	}
+	if input.Directory != "" {
+		files, _, err := projectFiles(input.Directory, envOrDefault(input.Pattern, "*.json,*.tmpl"))
+		if err != nil {
+			return nil, PinSchemaVersionOutput{}, err
+		}
+		for _, path := range files {
+			if _, ok := contents[path]; ok {
+				continue
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return nil, PinSchemaVersionOutput{}, fmt.Errorf("failed to read %s: %w", path, err)
+			}
+			if findSchemaRefs(path, string(data)) == nil && !rootConfigPattern.Match(data) {
+				continue // Settings and partials do not declare a schema
+			}
+			paths = append(paths, path)
+			contents[path] = string(data)
+		}
+	}
+
+	previous := map[string][]SchemaReference{}
+	enterprise := input.Edition == "ee"
+	for _, path := range paths {
+		previous[path] = findSchemaRefs(path, contents[path])
+		for _, ref := range previous[path] {
+			enterprise = enterprise || (input.Edition == "" && ref.Edition == "ee")
+		}
+	}
+
+	output := PinSchemaVersionOutput{Version: strings.TrimPrefix(input.Version, "v"), ResolvedFrom: "input", Files: []PinnedFile{}, Warnings: []string{}}
+	if output.Version == "" {
+		version, from, err := resolveCurrentVersion(ctx, enterprise)
+		if err != nil {
+			return nil, PinSchemaVersionOutput{}, err
+		}
+		output.Version, output.ResolvedFrom = version, from
+	}
+
+	var patch strings.Builder
+	var previousVersions []string
+	for _, path := range paths {
+		edition := input.Edition
+		if edition == "" {
+			edition = "ce"
+			if len(previous[path]) > 0 {
+				edition = previous[path][0].Edition
+			}
+		}
+		file := PinnedFile{Path: path, Schema: SchemaURL(output.Version, edition == "ee")}
+		for _, ref := range previous[path] {
+			file.Previous = append(file.Previous, ref.Schema)
+			if !slices.Contains(previousVersions, ref.Version) {
+				previousVersions = append(previousVersions, ref.Version)
+			}
+		}
+		edits, ok := schemaEdits(contents[path], file.Schema)
+		switch {
+		case !ok:
+			file.Skipped = fmt.Sprintf("the file does not open with a brace: add \"$schema\": %q manually", file.Schema)
+		case len(edits) > 0:
+			file.Changed = true
+			updated := applySchemaEdits(contents[path], edits)
+			name := path
+			if input.Directory != "" {
+				if rel, err := filepath.Rel(input.Directory, path); err == nil && !strings.HasPrefix(rel, "..") {
+					name = rel
+				}
+			}
+			patch.WriteString(schemaDiff(filepath.ToSlash(name), edits))
+			if inline && path == "config.json" {
+				output.Config = updated
+			} else if input.Write {
+				info, err := os.Stat(path)
+				if err != nil {
+					return nil, PinSchemaVersionOutput{}, fmt.Errorf("failed to write %s: %w", path, err)
+				}
+				if err := os.WriteFile(path, []byte(updated), info.Mode().Perm()); err != nil {
+					return nil, PinSchemaVersionOutput{}, fmt.Errorf("failed to write %s: %w", path, err)
+				}
+				file.Written = true
+			}
+		}
+		output.Files = append(output.Files, file)
+	}
+	output.Patch = patch.String()
+	output.Warnings = append(output.Warnings, pinSchemaWarnings(previousVersions, output.Version)...)
+
+	changed, written := 0, 0
+	for _, file := range output.Files {
+		if file.Changed {
+			changed++
+		}
+		if file.Written {
+			written++
+		}
+	}
+	target := "pinned to " + output.Version
+	if output.Version == "latest" {
+		target = "unpinned (latest)"
+	}
+	output.Summary = fmt.Sprintf("%d of %d file(s) %s", changed, len(output.Files), target)
+	switch {
+	case written > 0:
+		output.Summary += fmt.Sprintf(", %d written", written)
+	case changed > 0 && output.Config == "":
+		output.Summary += ": apply the patch or call again with write"
+	}
+	return nil, output, nil
+}
+
+// RegisterSchemaPinTools registers the schema pinning tool
+func RegisterSchemaPinTools(server *mcp.Server) {
+	toolkit.AddTool(server,
+		&mcp.Tool{
+			Name:        "pin_schema_version",
+			Description: "Make validation reproducible: rewrite the $schema of a configuration, or of every configuration root and template of a project, to a version-pinned schema (by default the version latest currently resolves to). Use version latest to unpin, with warnings on what changes. Returns the patch, and writes the files with write.",
+		},
+		PinSchemaVersion,
+	)
+}
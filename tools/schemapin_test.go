@@ -0,0 +1,98 @@
+package tools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/krakend/mcp-server/tools/validation"
+)
+
+// mockLatestSchema serves a latest schema pointing to version
+func mockLatestSchema(t *testing.T, version string) {
+	orig := validation.SchemaFetcher
+	validation.SchemaFetcher = func(ctx context.Context, url string) ([]byte, error) {
+		return []byte(`{"$ref": "v` + version + `/krakend.json"}`), nil
+	}
+	t.Cleanup(func() { validation.SchemaFetcher = orig })
+}
+
+func TestPinSchemaVersion(t *testing.T) {
+	mockLatestSchema(t, "2.12")
+
+	config := `{"$schema": "https://www.krakend.io/schema/krakend.json", "version": 3}`
+	_, output, err := PinSchemaVersion(context.Background(), nil, PinSchemaVersionInput{Config: config})
+	if err != nil {
+		t.Fatalf("PinSchemaVersion() error = %v", err)
+	}
+	if output.Version != "2.12" || output.ResolvedFrom != "latest schema" {
+		t.Errorf("Expected the resolved latest version, got %+v", output)
+	}
+	if output.Config != `{"$schema": "https://www.krakend.io/schema/v2.12/krakend.json", "version": 3}` {
+		t.Errorf("Unexpected config: %s", output.Config)
+	}
+	if len(output.Warnings) != 1 || !strings.Contains(output.Warnings[0], "Pinning latest to 2.12") {
+		t.Errorf("Expected a pinning warning, got %v", output.Warnings)
+	}
+
+	// Unpin an Enterprise configuration
+	config = `{"$schema": "https://www.krakend.io/schema/ee/v2.7/krakend.json", "version": 3}`
+	_, output, err = PinSchemaVersion(context.Background(), nil, PinSchemaVersionInput{Config: config, Version: "latest"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(output.Config, `"https://www.krakend.io/schema/ee/krakend.json"`) || len(output.Warnings) != 3 {
+		t.Errorf("Expected the unpinned EE schema with warnings, got %+v", output)
+	}
+
+	if _, _, err := PinSchemaVersion(context.Background(), nil, PinSchemaVersionInput{Config: config, Write: true}); err == nil {
+		t.Error("Expected an error writing an inline config")
+	}
+}
+
+func TestPinSchemaVersionProject(t *testing.T) {
+	dir := writeProject(t, map[string]string{
+		"krakend.json":          "{\n  \"$schema\": \"https://www.krakend.io/schema/v2.5/krakend.json\",\n  \"version\": 3\n}\n",
+		"staging/krakend.json":  "{\n  \"version\": 3\n}\n",
+		"settings/service.json": "{\"port\": 8080}\n",
+	})
+
+	_, output, err := PinSchemaVersion(context.Background(), nil, PinSchemaVersionInput{Directory: dir, Version: "2.7"})
+	if err != nil {
+		t.Fatalf("PinSchemaVersion() error = %v", err)
+	}
+	if len(output.Files) != 2 || output.Patch == "" || !strings.Contains(output.Summary, "apply the patch") {
+		t.Fatalf("Expected 2 configuration roots and a patch, got %+v", output)
+	}
+	if !strings.Contains(output.Warnings[0], "Upgrading from 2.5 to 2.7") {
+		t.Errorf("Expected an upgrade warning, got %v", output.Warnings)
+	}
+	data, _ := os.ReadFile(filepath.Join(dir, "krakend.json"))
+	if !strings.Contains(string(data), "v2.5") {
+		t.Error("Expected no file written without write")
+	}
+
+	_, output, err = PinSchemaVersion(context.Background(), nil, PinSchemaVersionInput{Directory: dir, Version: "2.7", Write: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range output.Files {
+		if !file.Written {
+			t.Errorf("Expected %s written", file.Path)
+		}
+	}
+	data, _ = os.ReadFile(filepath.Join(dir, "staging", "krakend.json"))
+	if string(data) != "{\n  \"$schema\": \"https://www.krakend.io/schema/v2.7/krakend.json\",\n  \"version\": 3\n}\n" {
+		t.Errorf("Unexpected staging config:\n%s", data)
+	}
+
+	_, check, err := CheckSchemaVersions(context.Background(), nil, CheckSchemaVersionsInput{Directory: dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !check.Consistent {
+		t.Errorf("Expected a consistent project after pinning, got %+v", check.Issues)
+	}
+}
@@ -0,0 +1,336 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/krakend/mcp-server/internal/toolkit"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+var (
+	// schemaRefPattern matches a $schema declaration, in JSON files and in templates alike
+	schemaRefPattern = regexp.MustCompile(`"\$schema"\s*:\s*"([^"]*)"`)
+	// schemaURLVersion extracts the edition and version of a KrakenD schema URL
+	schemaURLVersion = regexp.MustCompile(`/schema/(ee/)?v(\d+\.\d+)/`)
+	// rootConfigPattern matches the root of a KrakenD configuration, the file that must declare $schema
+	rootConfigPattern = regexp.MustCompile(`"version"\s*:\s*3\b`)
+)
+
+// SchemaReference is a $schema declaration in a project file
+type SchemaReference struct {
+	Path    string `json:"path"`
+	Line    int    `json:"line"`
+	Schema  string `json:"schema"`
+	Version string `json:"version"` // "latest" when not pinned
+	Edition string `json:"edition"` // "ce" or "ee"
+}
+
+// SchemaIssue is an inconsistency of the $schema declarations of a project
+type SchemaIssue struct {
+	Path    string `json:"path"`
+	Line    int    `json:"line,omitempty"`
+	Kind    string `json:"kind"` // mismatch, unpinned, missing or edition
+	Message string `json:"message"`
+}
+
+// CheckSchemaVersionsInput defines input for check_schema_versions tool
+type CheckSchemaVersionsInput struct {
+	Directory string `json:"directory" jsonschema:"Project directory (searched recursively), e.g. a Flexible Configuration project"`
+	Pattern   string `json:"pattern,omitempty" jsonschema:"Comma-separated file name globs of the project files (optional, defaults to *.json,*.tmpl)"`
+	Version   string `json:"version,omitempty" jsonschema:"Version every file should pin, e.g. 2.7 (optional, defaults to the version most files pin)"`
+}
+
+// CheckSchemaVersionsOutput defines output for check_schema_versions tool
+type CheckSchemaVersionsOutput struct {
+	Files              int               `json:"files"` // Files scanned
+	References         []SchemaReference `json:"references"`
+	Versions           map[string]int    `json:"versions"` // References per version
+	Issues             []SchemaIssue     `json:"issues"`
+	Consistent         bool              `json:"consistent"`
+	RecommendedVersion string            `json:"recommended_version,omitempty"`
+	RecommendedSchema  string            `json:"recommended_schema,omitempty"`
+	Patch              string            `json:"patch,omitempty"` // Unified diff pinning every file, relative to the directory (git apply --unidiff-zero)
+	Skipped            []SkippedConfig   `json:"skipped,omitempty"`
+	Summary            string            `json:"summary"`
+}
+
+// schemaEdit changes or inserts the $schema line of a file
+type schemaEdit struct {
+	Line   int // 1-based line replaced, or the line after which it is inserted
+	Old    string
+	New    string
+	Insert bool
+}
+
+// schemaRefVersion returns the version and edition of a schema URL
+func schemaRefVersion(url string) (version, edition string) {
+	edition = "ce"
+	if strings.Contains(url, "/schema/ee/") {
+		edition = "ee"
+	}
+	if m := schemaURLVersion.FindStringSubmatch(url); m != nil {
+		return m[2], edition
+	}
+	return "latest", edition
+}
+
+// findSchemaRefs lists the $schema declarations of a file with their line numbers
+func findSchemaRefs(path, content string) []SchemaReference {
+	var refs []SchemaReference
+	for i, line := range strings.Split(content, "\n") {
+		for _, m := range schemaRefPattern.FindAllStringSubmatch(line, -1) {
+			version, edition := schemaRefVersion(m[1])
+			refs = append(refs, SchemaReference{Path: path, Line: i + 1, Schema: m[1], Version: version, Edition: edition})
+		}
+	}
+	return refs
+}
+
+// schemaEdits returns the edits setting every $schema of a file to url, inserting it after the
+// opening brace when the file is a configuration root without one. ok is false when the
+// declaration cannot be inserted safely (the file does not open with a brace, e.g. templates).
+func schemaEdits(content, url string) (edits []schemaEdit, ok bool) {
+	lines := strings.Split(content, "\n")
+	found := false
+	for i, line := range lines {
+		if !schemaRefPattern.MatchString(line) {
+			continue
+		}
+		found = true
+		updated := schemaRefPattern.ReplaceAllLiteralString(line, fmt.Sprintf(`"$schema": %q`, url))
+		if updated != line {
+			edits = append(edits, schemaEdit{Line: i + 1, Old: line, New: updated})
+		}
+	}
+	if found || !rootConfigPattern.MatchString(content) {
+		return edits, true
+	}
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			continue
+		}
+		if !strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "{{") {
+			return nil, false
+		}
+		if trimmed != "{" {
+			// Compact JSON: declare it right after the brace, on the same line
+			updated := strings.Replace(line, "{", fmt.Sprintf(`{"$schema": %q, `, url), 1)
+			return []schemaEdit{{Line: i + 1, Old: line, New: updated}}, true
+		}
+		indent := "  "
+		if i+1 < len(lines) {
+			if next := lines[i+1]; strings.TrimSpace(next) != "" {
+				indent = next[:len(next)-len(strings.TrimLeft(next, " \t"))]
+			}
+		}
+		return []schemaEdit{{Line: i + 1, New: fmt.Sprintf(`%s"$schema": %q,`, indent, url), Insert: true}}, true
+	}
+	return nil, false
+}
+
+// applySchemaEdits returns the content of a file with the edits applied
+func applySchemaEdits(content string, edits []schemaEdit) string {
+	lines := strings.Split(content, "\n")
+	for i := len(edits) - 1; i >= 0; i-- {
+		if edits[i].Insert {
+			lines = slices.Insert(lines, edits[i].Line, edits[i].New)
+			continue
+		}
+		lines[edits[i].Line-1] = edits[i].New
+	}
+	return strings.Join(lines, "\n")
+}
+
+// schemaDiff renders the edits of a file as a zero-context unified diff
+func schemaDiff(path string, edits []schemaEdit) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- a/%s\n+++ b/%s\n", path, path)
+	for _, edit := range edits {
+		if edit.Insert {
+			fmt.Fprintf(&b, "@@ -%d,0 +%d,1 @@\n+%s\n", edit.Line, edit.Line+1, edit.New)
+			continue
+		}
+		fmt.Fprintf(&b, "@@ -%d,1 +%d,1 @@\n-%s\n+%s\n", edit.Line, edit.Line, edit.Old, edit.New)
+	}
+	return b.String()
+}
+
+// compareVersions compares two major.minor versions numerically
+func compareVersions(a, b string) int {
+	pa, pb := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(pa) && i < len(pb); i++ {
+		na, _ := strconv.Atoi(pa[i])
+		nb, _ := strconv.Atoi(pb[i])
+		if na != nb {
+			return na - nb
+		}
+	}
+	return len(pa) - len(pb)
+}
+
+// projectFiles lists the files of a project matching any of the comma-separated globs
+func projectFiles(directory, patterns string) ([]string, []SkippedConfig, error) {
+	globs := strings.Split(patterns, ",")
+	for i, glob := range globs {
+		globs[i] = strings.TrimSpace(glob)
+		if _, err := filepath.Match(globs[i], ""); err != nil {
+			return nil, nil, fmt.Errorf("invalid pattern %q: %w", glob, err)
+		}
+	}
+	var files []string
+	var skipped []SkippedConfig
+	err := filepath.WalkDir(directory, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			skipped = append(skipped, SkippedConfig{Path: path, Reason: err.Error()})
+			return nil
+		}
+		if d.IsDir() {
+			if path != directory && (strings.HasPrefix(d.Name(), ".") || d.Name() == "node_modules") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if slices.ContainsFunc(globs, func(glob string) bool { ok, _ := filepath.Match(glob, d.Name()); return ok }) {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to walk %s: %w", directory, err)
+	}
+	return files, skipped, nil
+}
+
+// CheckSchemaVersions flags the files of a project pinning different $schema versions, leaving
+// it unpinned or missing it, and suggests a patch pinning a single version
+func CheckSchemaVersions(ctx context.Context, req *mcp.CallToolRequest, input CheckSchemaVersionsInput) (*mcp.CallToolResult, CheckSchemaVersionsOutput, error) {
+	if input.Directory == "" {
+		return nil, CheckSchemaVersionsOutput{}, fmt.Errorf("directory is required")
+	}
+	files, skipped, err := projectFiles(input.Directory, envOrDefault(input.Pattern, "*.json,*.tmpl"))
+	if err != nil {
+		return nil, CheckSchemaVersionsOutput{}, err
+	}
+
+	output := CheckSchemaVersionsOutput{References: []SchemaReference{}, Versions: map[string]int{}, Issues: []SchemaIssue{}, Skipped: skipped}
+	contents := map[string]string{}
+	var missing []string
+	for _, path := range files {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			output.Skipped = append(output.Skipped, SkippedConfig{Path: path, Reason: err.Error()})
+			continue
+		}
+		output.Files++
+		content := string(data)
+		refs := findSchemaRefs(path, content)
+		if len(refs) == 0 {
+			if rootConfigPattern.MatchString(content) {
+				missing = append(missing, path)
+				contents[path] = content
+			}
+			continue
+		}
+		contents[path] = content
+		for _, ref := range refs {
+			output.Versions[ref.Version]++
+			output.References = append(output.References, ref)
+		}
+	}
+
+	// The target: the given version, or the version most references pin (the highest on ties)
+	target := strings.TrimPrefix(input.Version, "v")
+	if target == "" {
+		for _, version := range slices.SortedFunc(maps.Keys(output.Versions), compareVersions) {
+			if version == "latest" {
+				continue
+			}
+			if target == "" || output.Versions[version] >= output.Versions[target] {
+				target = version
+			}
+		}
+	}
+	editions := map[string]bool{}
+	for _, ref := range output.References {
+		editions[ref.Edition] = true
+	}
+	if target != "" {
+		output.RecommendedVersion = target
+		output.RecommendedSchema = SchemaURL(target, editions["ee"])
+	}
+
+	for _, ref := range output.References {
+		switch {
+		case ref.Version == "latest":
+			output.Issues = append(output.Issues, SchemaIssue{Path: ref.Path, Line: ref.Line, Kind: "unpinned",
+				Message: "The schema is not pinned: validation follows the newest KrakenD release and is not reproducible"})
+		case target != "" && ref.Version != target:
+			output.Issues = append(output.Issues, SchemaIssue{Path: ref.Path, Line: ref.Line, Kind: "mismatch",
+				Message: fmt.Sprintf("Pins %s while the project targets %s", ref.Version, target)})
+		}
+		if len(editions) > 1 && ref.Edition == "ce" {
+			output.Issues = append(output.Issues, SchemaIssue{Path: ref.Path, Line: ref.Line, Kind: "edition",
+				Message: "Uses the Community Edition schema while other files use the Enterprise one: EE namespaces fail its validation"})
+		}
+	}
+	for _, path := range missing {
+		output.Issues = append(output.Issues, SchemaIssue{Path: path, Kind: "missing",
+			Message: "The configuration declares no $schema: editors cannot validate it and tools assume the latest version"})
+	}
+
+	if output.RecommendedSchema != "" {
+		var patch strings.Builder
+		for _, path := range slices.Sorted(maps.Keys(contents)) {
+			edits, ok := schemaEdits(contents[path], output.RecommendedSchema)
+			rel, err := filepath.Rel(input.Directory, path)
+			if err != nil {
+				rel = path
+			}
+			if !ok {
+				for i, issue := range output.Issues {
+					if issue.Path == path && issue.Kind == "missing" {
+						output.Issues[i].Message += fmt.Sprintf(" (add \"$schema\": %q manually, the file does not open with a brace)", output.RecommendedSchema)
+					}
+				}
+				continue
+			}
+			if len(edits) > 0 {
+				patch.WriteString(schemaDiff(filepath.ToSlash(rel), edits))
+			}
+		}
+		output.Patch = patch.String()
+	}
+
+	output.Consistent = len(output.Issues) == 0
+	output.Summary = fmt.Sprintf("%d file(s) scanned: %d $schema reference(s) in %d version(s), %d issue(s)",
+		output.Files, len(output.References), len(output.Versions), len(output.Issues))
+	switch {
+	case output.Consistent:
+	case target == "":
+		output.Summary += ": pass version to choose the version to pin"
+	default:
+		output.Summary += fmt.Sprintf(": the patch pins every file to %s", target)
+	}
+	return nil, output, nil
+}
+
+// RegisterSchemaVersionTools registers the project schema consistency tool
+func RegisterSchemaVersionTools(server *mcp.Server) {
+	toolkit.AddTool(server,
+		&mcp.Tool{
+			Name:        "check_schema_versions",
+			Description: "Check that the files of a multi-file or Flexible Configuration project agree on the $schema version: flags files pinning different versions, unpinned (latest) schemas, missing $schema in configuration roots and Community/Enterprise schema mixes, and returns a unified diff pinning a single version across the project.",
+		},
+		CheckSchemaVersions,
+	)
+}
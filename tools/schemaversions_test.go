@@ -0,0 +1,105 @@
+package tools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeProject writes the files of a project in a temporary directory
+func writeProject(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestCheckSchemaVersions(t *testing.T) {
+	dir := writeProject(t, map[string]string{
+		"krakend.tmpl":          "{\n  \"$schema\": \"https://www.krakend.io/schema/v2.7/krakend.json\",\n  \"version\": 3,\n  \"endpoints\": [{{ template \"endpoints.tmpl\" . }}]\n}\n",
+		"staging/krakend.json":  "{\n    \"version\": 3,\n    \"endpoints\": []\n}\n",
+		"legacy/krakend.json":   "{\n  \"$schema\": \"https://www.krakend.io/schema/v2.5/krakend.json\",\n  \"version\": 3\n}\n",
+		"next/krakend.json":     "{\n  \"$schema\": \"https://www.krakend.io/schema/krakend.json\",\n  \"version\": 3\n}\n",
+		"mirror/krakend.json":   "{\n  \"$schema\": \"https://www.krakend.io/schema/v2.7/krakend.json\",\n  \"version\": 3\n}\n",
+		"settings/service.json": "{\"port\": 8080}\n",
+	})
+
+	_, output, err := CheckSchemaVersions(context.Background(), nil, CheckSchemaVersionsInput{Directory: dir})
+	if err != nil {
+		t.Fatalf("CheckSchemaVersions() error = %v", err)
+	}
+	if output.Files != 6 || len(output.References) != 4 || output.Versions["2.7"] != 2 {
+		t.Fatalf("Expected 6 files and 4 references, got %+v", output)
+	}
+	if output.Consistent || output.RecommendedVersion != "2.7" || output.RecommendedSchema != "https://www.krakend.io/schema/v2.7/krakend.json" {
+		t.Errorf("Expected 2.7 recommended, got %+v", output)
+	}
+	kinds := map[string]int{}
+	for _, issue := range output.Issues {
+		kinds[issue.Kind]++
+	}
+	if kinds["mismatch"] != 1 || kinds["unpinned"] != 1 || kinds["missing"] != 1 || len(output.Issues) != 3 {
+		t.Errorf("Expected a mismatch, an unpinned and a missing schema, got %+v", output.Issues)
+	}
+
+	for _, want := range []string{
+		"--- a/legacy/krakend.json\n+++ b/legacy/krakend.json\n@@ -2,1 +2,1 @@\n-  \"$schema\": \"https://www.krakend.io/schema/v2.5/krakend.json\",\n+  \"$schema\": \"https://www.krakend.io/schema/v2.7/krakend.json\",\n",
+		"--- a/staging/krakend.json\n+++ b/staging/krakend.json\n@@ -1,0 +2,1 @@\n+    \"$schema\": \"https://www.krakend.io/schema/v2.7/krakend.json\",\n",
+		"+++ b/next/krakend.json\n",
+	} {
+		if !strings.Contains(output.Patch, want) {
+			t.Errorf("Expected the patch to contain %q, got:\n%s", want, output.Patch)
+		}
+	}
+	if strings.Contains(output.Patch, "mirror/") || strings.Contains(output.Patch, "settings/") {
+		t.Errorf("Expected no edit of pinned or non-root files, got:\n%s", output.Patch)
+	}
+}
+
+func TestCheckSchemaVersionsEditions(t *testing.T) {
+	dir := writeProject(t, map[string]string{
+		"a.json": "{\n  \"$schema\": \"https://www.krakend.io/schema/ee/v2.10/krakend.json\",\n  \"version\": 3\n}\n",
+		"b.json": "{\n  \"$schema\": \"https://www.krakend.io/schema/v2.9/krakend.json\",\n  \"version\": 3\n}\n",
+		"c.json": "{\"version\": 3}\n",
+		"d.tmpl": "{{ include \"service.tmpl\" }}\n\"version\": 3\n",
+	})
+	_, output, err := CheckSchemaVersions(context.Background(), nil, CheckSchemaVersionsInput{Directory: dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Tie between 2.9 and 2.10: the highest, with the Enterprise schema
+	if output.RecommendedSchema != "https://www.krakend.io/schema/ee/v2.10/krakend.json" {
+		t.Errorf("Expected the EE 2.10 schema, got %q", output.RecommendedSchema)
+	}
+	var edition, manual bool
+	for _, issue := range output.Issues {
+		edition = edition || issue.Kind == "edition"
+		manual = manual || (issue.Kind == "missing" && strings.Contains(issue.Message, "manually"))
+	}
+	if !edition || !manual {
+		t.Errorf("Expected an edition mix and a manual insertion, got %+v", output.Issues)
+	}
+	if !strings.Contains(output.Patch, "+{\"$schema\": \"https://www.krakend.io/schema/ee/v2.10/krakend.json\", \"version\": 3}\n") {
+		t.Errorf("Expected the compact config pinned on its line, got:\n%s", output.Patch)
+	}
+
+	_, output, err = CheckSchemaVersions(context.Background(), nil, CheckSchemaVersionsInput{Directory: writeProject(t, map[string]string{
+		"krakend.json": "{\n  \"$schema\": \"https://www.krakend.io/schema/v2.7/krakend.json\",\n  \"version\": 3\n}\n",
+	})})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !output.Consistent || output.Patch != "" {
+		t.Errorf("Expected a consistent project, got %+v", output)
+	}
+}
@@ -0,0 +1,262 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/krakend/mcp-server/internal/toolkit"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Secret externalization strategies
+var secretStrategies = []string{"env", "vault-agent", "kms"}
+
+// kmsSchemes are the secret providers KrakenD Enterprise accepts in secret_url
+var kmsSchemes = []string{"base64key", "awskms", "gcpkms", "azurekeyvault", "hashivault"}
+
+// jwkNamespaces hold JWK files that Enterprise can decrypt with a secret_url
+var jwkNamespaces = []string{"auth/signer", "auth/validator"}
+
+var envNameInvalid = regexp.MustCompile(`[^A-Z0-9]+`)
+
+// ExternalizeSecretsInput defines input for externalize_secrets tool
+type ExternalizeSecretsInput struct {
+	Config    string `json:"config" jsonschema:"KrakenD configuration (JSON string or file path)"`
+	Strategy  string `json:"strategy,omitempty" jsonschema:"env (default, Flexible Configuration env references), vault-agent (FC settings rendered by a Vault Agent template) or kms (Enterprise secret_url for encrypted JWK files, env references for the rest)"`
+	Edition   string `json:"edition,omitempty" jsonschema:"Target edition: ce or ee (optional, detected from the config)"`
+	EnvPrefix string `json:"env_prefix,omitempty" jsonschema:"Prefix of the generated environment variables (optional, defaults to KRAKEND_)"`
+	VaultPath string `json:"vault_path,omitempty" jsonschema:"Vault KV v2 secret read by the agent template (vault-agent, optional, defaults to secret/data/krakend)"`
+	KMSURL    string `json:"kms_url,omitempty" jsonschema:"Key used to decrypt JWK files, e.g. hashivault://my-key or awskms://key-id (kms)"`
+}
+
+// SecretReference is a secret field rewritten to an external reference
+type SecretReference struct {
+	Path      string `json:"path"`
+	Namespace string `json:"namespace,omitempty"`
+	Reference string `json:"reference"`         // Value now written in the configuration
+	Env       string `json:"env,omitempty"`     // Environment variable to provide (env and kms)
+	Setting   string `json:"setting,omitempty"` // Key in the Vault secret and the settings file (vault-agent)
+	Edition   string `json:"edition,omitempty"` // "ee" when the namespace is Enterprise only
+}
+
+// SecretFile is a deployment file generated for the secrets
+type SecretFile struct {
+	Path    string `json:"path"`
+	Content string `json:"content"`
+}
+
+// ExternalizeSecretsOutput defines output for externalize_secrets tool
+type ExternalizeSecretsOutput struct {
+	Strategy string                 `json:"strategy"`
+	Edition  string                 `json:"edition"`
+	Config   map[string]interface{} `json:"config"` // Flexible Configuration template: render it with FC_ENABLE=1
+	Secrets  []SecretReference      `json:"secrets"`
+	Files    []SecretFile           `json:"files,omitempty"`
+	Skipped  []string               `json:"skipped,omitempty"` // Secret fields that are not plain strings or already templated
+	Warnings []string               `json:"warnings,omitempty"`
+	Notes    []string               `json:"notes"`
+}
+
+// secretRewriter walks a configuration replacing secret values with references
+type secretRewriter struct {
+	strategy string
+	prefix   string
+	used     map[string]bool
+	secrets  []SecretReference
+	skipped  []string
+}
+
+// name returns a unique variable name for a secret of a namespace
+func (r *secretRewriter) name(namespace, key string) string {
+	base := r.prefix + envNameInvalid.ReplaceAllString(strings.ToUpper(strings.Trim(namespace+"_"+key, "_")), "_")
+	name := base
+	for i := 2; r.used[name]; i++ {
+		name = fmt.Sprintf("%s_%d", base, i)
+	}
+	r.used[name] = true
+	return name
+}
+
+// rewrite replaces the secrets found under value, in place
+func (r *secretRewriter) rewrite(value interface{}, path, namespace string) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for _, k := range sortedKeys(v) {
+			child := v[k]
+			childPath := path + "." + k
+			childNamespace := namespace
+			if strings.Contains(k, "/") || strings.Contains(k, ".") {
+				childPath = fmt.Sprintf("%s['%s']", path, k)
+			}
+			if strings.Contains(k, "/") {
+				childNamespace = k
+			}
+			if !isSecretKey(k) || isEmptyValue(child) {
+				r.rewrite(child, childPath, childNamespace)
+				continue
+			}
+			secret, ok := child.(string)
+			if !ok || strings.Contains(secret, "{{") {
+				r.skipped = append(r.skipped, childPath)
+				continue
+			}
+			ref := SecretReference{Path: childPath, Namespace: namespace}
+			name := r.name(namespace, k)
+			if r.strategy == "vault-agent" {
+				ref.Setting = strings.ToLower(strings.TrimPrefix(name, r.prefix))
+				ref.Reference = "{{ .secrets." + ref.Setting + " }}"
+			} else {
+				ref.Env = name
+				ref.Reference = envTemplate(name)
+			}
+			v[k] = ref.Reference
+			r.secrets = append(r.secrets, ref)
+		}
+	case []interface{}:
+		for i, child := range v {
+			r.rewrite(child, fmt.Sprintf("%s[%d]", path, i), namespace)
+		}
+	}
+}
+
+// sortedKeys returns the keys of a map in alphabetical order
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// ExternalizeSecrets rewrites the secrets of a configuration as references to the environment,
+// a Vault-rendered settings file or an Enterprise secret provider
+func ExternalizeSecrets(ctx context.Context, req *mcp.CallToolRequest, input ExternalizeSecretsInput) (*mcp.CallToolResult, ExternalizeSecretsOutput, error) {
+	strategy := envOrDefault(input.Strategy, "env")
+	if !slices.Contains(secretStrategies, strategy) {
+		return nil, ExternalizeSecretsOutput{}, fmt.Errorf("unknown strategy %q (available: %s)", strategy, strings.Join(secretStrategies, ", "))
+	}
+	configContent, err := readConfigContent(input.Config)
+	if err != nil {
+		return nil, ExternalizeSecretsOutput{}, fmt.Errorf("failed to read config: %w", err)
+	}
+	var config map[string]interface{}
+	if err := json.Unmarshal([]byte(configContent), &config); err != nil {
+		return nil, ExternalizeSecretsOutput{}, fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	// Edition gating through the edition matrix
+	edition := input.Edition
+	if edition == "" {
+		edition = "ce"
+		if DetectEnterpriseFeatures(configContent) {
+			edition = "ee"
+		}
+	}
+	if strategy == "kms" && edition != "ee" {
+		return nil, ExternalizeSecretsOutput{}, fmt.Errorf("the kms strategy uses the Enterprise secret providers: use env or vault-agent with the Community Edition")
+	}
+
+	r := &secretRewriter{strategy: strategy, prefix: envOrDefault(input.EnvPrefix, "KRAKEND_"), used: map[string]bool{}}
+	r.rewrite(config, "$", "")
+	output := ExternalizeSecretsOutput{Strategy: strategy, Edition: edition, Config: config, Secrets: r.secrets, Skipped: r.skipped}
+	if output.Secrets == nil {
+		output.Secrets = []SecretReference{}
+	}
+	for i, s := range output.Secrets {
+		if s.Namespace != "" && eeOnlyNamespace(s.Namespace) {
+			output.Secrets[i].Edition = "ee"
+			if edition != "ee" {
+				output.Warnings = append(output.Warnings, fmt.Sprintf("%s uses %s, which requires KrakenD Enterprise", s.Path, s.Namespace))
+			}
+		}
+	}
+
+	switch strategy {
+	case "env":
+		output.Notes = append(output.Notes,
+			"The configuration is now a Flexible Configuration template: start KrakenD with FC_ENABLE=1 and provide the listed environment variables.",
+			"Inject the variables from your secret store at deploy time (Kubernetes secrets, Vault Agent env templates, ECS secrets...) and never commit their values.")
+
+	case "vault-agent":
+		vaultPath := envOrDefault(input.VaultPath, "secret/data/krakend")
+		var tmpl, put strings.Builder
+		fmt.Fprintf(&tmpl, "{{ with secret %q -}}\n{\n", vaultPath)
+		for i, s := range output.Secrets {
+			sep := ","
+			if i == len(output.Secrets)-1 {
+				sep = ""
+			}
+			fmt.Fprintf(&tmpl, "  %q: {{ .Data.data.%s | toJSON }}%s\n", s.Setting, s.Setting, sep)
+			fmt.Fprintf(&put, " %s=...", s.Setting)
+		}
+		tmpl.WriteString("}\n{{- end }}\n")
+		output.Files = append(output.Files,
+			SecretFile{Path: "vault/secrets.json.ctmpl", Content: tmpl.String()},
+			SecretFile{Path: "vault/agent.hcl", Content: "template {\n  source      = \"vault/secrets.json.ctmpl\"\n  destination = \"config/settings/secrets.json\"\n  perms       = \"0600\"\n}\n"},
+		)
+		output.Notes = append(output.Notes,
+			fmt.Sprintf("Store the values in Vault: vault kv put %s%s", strings.Replace(vaultPath, "/data/", "/", 1), put.String()),
+			"Run Vault Agent with the template stanza of vault/agent.hcl next to KrakenD: it renders config/settings/secrets.json, which KrakenD reads as the secrets settings file.",
+			"Start KrakenD with FC_ENABLE=1 and FC_SETTINGS=config/settings, after the agent has rendered the file. Restart KrakenD when secrets rotate.")
+
+	case "kms":
+		scheme, _, _ := strings.Cut(input.KMSURL, "://")
+		if !slices.Contains(kmsSchemes, scheme) || !strings.Contains(input.KMSURL, "://") {
+			return nil, ExternalizeSecretsOutput{}, fmt.Errorf("kms_url must use one of the %s schemes", strings.Join(kmsSchemes, ", "))
+		}
+		encrypted := 0
+		walkNamespaces(config, "$", func(namespace, path string, settings map[string]interface{}) {
+			if !slices.Contains(jwkNamespaces, namespace) || settings["jwk_local_path"] == nil {
+				return
+			}
+			settings["secret_url"] = input.KMSURL
+			encrypted++
+			output.Secrets = append(output.Secrets, SecretReference{Path: path + ".secret_url", Namespace: namespace, Reference: input.KMSURL, Edition: "ee"})
+		})
+		if encrypted == 0 {
+			output.Warnings = append(output.Warnings, "No auth/signer or auth/validator uses jwk_local_path: secret_url only applies to local JWK files")
+		}
+		output.Notes = append(output.Notes,
+			"Encrypt each local JWK file with the key referenced by secret_url: KrakenD Enterprise decrypts it at startup, so the file can be committed.",
+			"Other secrets became environment references: start KrakenD with FC_ENABLE=1 and provide the listed variables.")
+	}
+	return nil, output, nil
+}
+
+// walkNamespaces calls fn for every namespace found in the extra_config of any level
+func walkNamespaces(value interface{}, path string, fn func(namespace, path string, settings map[string]interface{})) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for _, k := range sortedKeys(v) {
+			childPath := path + "." + k
+			if strings.Contains(k, "/") {
+				childPath = fmt.Sprintf("%s['%s']", path, k)
+				if settings, ok := v[k].(map[string]interface{}); ok {
+					fn(k, childPath, settings)
+				}
+			}
+			walkNamespaces(v[k], childPath, fn)
+		}
+	case []interface{}:
+		for i, child := range v {
+			walkNamespaces(child, fmt.Sprintf("%s[%d]", path, i), fn)
+		}
+	}
+}
+
+// RegisterSecretTools registers secret externalization tools
+func RegisterSecretTools(server *mcp.Server) {
+	toolkit.AddTool(server,
+		&mcp.Tool{
+			Name:        "externalize_secrets",
+			Description: "Rewrite the secret fields of a KrakenD configuration (client secrets, passwords, API keys, tokens) as external references: Flexible Configuration environment variables, a settings file rendered by a Vault Agent template (Community), or Enterprise secret providers (hashivault://, awskms://, gcpkms://, azurekeyvault://) for encrypted JWK files. Returns the rewritten template, the deployment files and notes, with edition checks from the feature matrix.",
+		},
+		ExternalizeSecrets,
+	)
+}
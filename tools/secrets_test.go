@@ -0,0 +1,84 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+const secretsConfig = `{"version": 3,
+	"extra_config": {"auth/api-keys": {"keys": [{"key": "4d2c61e1", "roles": ["user"]}]}},
+	"endpoints": [{"endpoint": "/orders", "backend": [{"url_pattern": "/orders",
+		"extra_config": {"auth/client-credentials": {"client_id": "gw", "client_secret": "s3cret", "token_url": "https://idp/token"}}
+	}]}, {"endpoint": "/stock", "backend": [{"url_pattern": "/stock",
+		"extra_config": {"auth/client-credentials": {"client_id": "gw", "client_secret": "other", "token_url": "https://idp/token"}}
+	}]}]
+}`
+
+func TestExternalizeSecrets_Env(t *testing.T) {
+	setMockFeatureFetcher(t, minimalFeatureYAML)
+
+	_, output, err := ExternalizeSecrets(context.Background(), nil, ExternalizeSecretsInput{Config: secretsConfig})
+	if err != nil {
+		t.Fatalf("ExternalizeSecrets() error = %v", err)
+	}
+	if output.Edition != "ee" {
+		t.Errorf("auth/api-keys makes the config Enterprise, got %s", output.Edition)
+	}
+	envs := []string{}
+	for _, s := range output.Secrets {
+		envs = append(envs, s.Env)
+	}
+	want := "KRAKEND_AUTH_CLIENT_CREDENTIALS_CLIENT_SECRET,KRAKEND_AUTH_CLIENT_CREDENTIALS_CLIENT_SECRET_2,KRAKEND_AUTH_API_KEYS_KEY"
+	if strings.Join(envs, ",") != want {
+		t.Errorf("Secrets env = %v, want %s", envs, want)
+	}
+
+	backend := output.Config["endpoints"].([]interface{})[0].(map[string]interface{})["backend"].([]interface{})[0].(map[string]interface{})
+	settings := backend["extra_config"].(map[string]interface{})["auth/client-credentials"].(map[string]interface{})
+	if settings["client_secret"] != "{{ env `KRAKEND_AUTH_CLIENT_CREDENTIALS_CLIENT_SECRET` }}" || settings["token_url"] != "https://idp/token" {
+		t.Errorf("Unexpected rewritten settings: %v", settings)
+	}
+	for _, s := range output.Secrets {
+		if s.Namespace == "auth/api-keys" && s.Edition != "ee" {
+			t.Errorf("auth/api-keys should be flagged as Enterprise: %+v", s)
+		}
+	}
+}
+
+func TestExternalizeSecrets_VaultAgent(t *testing.T) {
+	setMockFeatureFetcher(t, minimalFeatureYAML)
+
+	_, output, err := ExternalizeSecrets(context.Background(), nil, ExternalizeSecretsInput{Config: secretsConfig, Strategy: "vault-agent", Edition: "ce"})
+	if err != nil {
+		t.Fatalf("ExternalizeSecrets() error = %v", err)
+	}
+	if output.Secrets[0].Reference != "{{ .secrets.auth_client_credentials_client_secret }}" {
+		t.Errorf("Unexpected reference: %+v", output.Secrets[0])
+	}
+	if len(output.Files) != 2 || !strings.Contains(output.Files[0].Content, `"auth_client_credentials_client_secret": {{ .Data.data.auth_client_credentials_client_secret | toJSON }},`) {
+		t.Errorf("Unexpected agent template: %+v", output.Files)
+	}
+	if len(output.Warnings) == 0 {
+		t.Error("Expected a warning for auth/api-keys on the Community Edition")
+	}
+}
+
+func TestExternalizeSecrets_KMS(t *testing.T) {
+	config := `{"version": 3, "endpoints": [{"endpoint": "/token", "extra_config": {"auth/signer": {"alg": "RS256", "jwk_local_path": "./jwk.json"}}, "backend": [{"url_pattern": "/login"}]}]}`
+	if _, _, err := ExternalizeSecrets(context.Background(), nil, ExternalizeSecretsInput{Config: config, Strategy: "kms", Edition: "ce", KMSURL: "hashivault://jwk"}); err == nil {
+		t.Error("Expected kms to require the Enterprise Edition")
+	}
+	if _, _, err := ExternalizeSecrets(context.Background(), nil, ExternalizeSecretsInput{Config: config, Strategy: "kms", Edition: "ee", KMSURL: "vault://jwk"}); err == nil {
+		t.Error("Expected an unsupported scheme to be rejected")
+	}
+
+	_, output, err := ExternalizeSecrets(context.Background(), nil, ExternalizeSecretsInput{Config: config, Strategy: "kms", Edition: "ee", KMSURL: "hashivault://jwk"})
+	if err != nil {
+		t.Fatalf("ExternalizeSecrets() error = %v", err)
+	}
+	signer := output.Config["endpoints"].([]interface{})[0].(map[string]interface{})["extra_config"].(map[string]interface{})["auth/signer"].(map[string]interface{})
+	if signer["secret_url"] != "hashivault://jwk" || len(output.Secrets) != 1 {
+		t.Errorf("Expected secret_url on the signer, got %v %+v", signer, output.Secrets)
+	}
+}
@@ -0,0 +1,65 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/krakend/mcp-server/internal/toolkit"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ServerSetting is a server setting resolved at startup and where its value came from
+type ServerSetting struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Source string `json:"source"` // flag, env or default
+	Flag   string `json:"flag,omitempty"`
+	Env    string `json:"env,omitempty"`
+}
+
+// GetServerConfigInput defines input for get_server_config tool
+type GetServerConfigInput struct{}
+
+// GetServerConfigOutput defines output for get_server_config tool
+type GetServerConfigOutput struct {
+	Settings []ServerSetting `json:"settings"`
+	DataDir  string          `json:"data_dir"` // Data directory in use, after fallbacks
+	DocsURL  string          `json:"docs_url"`
+	CacheTTL string          `json:"cache_ttl"`
+	Summary  string          `json:"summary"`
+}
+
+// getServerConfig returns the handler reporting the resolved server settings
+func getServerConfig(settings []ServerSetting) func(context.Context, *mcp.CallToolRequest, GetServerConfigInput) (*mcp.CallToolResult, GetServerConfigOutput, error) {
+	return func(ctx context.Context, req *mcp.CallToolRequest, input GetServerConfigInput) (*mcp.CallToolResult, GetServerConfigOutput, error) {
+		output := GetServerConfigOutput{
+			Settings: settings,
+			DataDir:  dataDir,
+			DocsURL:  docsURL,
+			CacheTTL: cacheTTL.String(),
+		}
+		if output.Settings == nil {
+			output.Settings = []ServerSetting{}
+		}
+		overridden := 0
+		for _, s := range output.Settings {
+			if s.Source != "default" {
+				overridden++
+			}
+		}
+		output.Summary = fmt.Sprintf("%d setting(s), %d overridden by flags or environment variables", len(output.Settings), overridden)
+		return nil, output, nil
+	}
+}
+
+// RegisterServerConfigTools registers the server configuration tool. Secrets such as admin tokens
+// are never part of the settings.
+func RegisterServerConfigTools(server *mcp.Server, settings []ServerSetting) {
+	toolkit.AddTool(server,
+		&mcp.Tool{
+			Name:        "get_server_config",
+			Description: "Report the settings the server resolved at startup (data directory, documentation URL, cache TTL, Docker registry, log level and format, transport, enabled and disabled toolsets, denied tools), each with the flag or environment variable that set it. Use it to debug why the server behaves differently than expected.",
+		},
+		getServerConfig(settings),
+	)
+}
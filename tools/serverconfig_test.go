@@ -0,0 +1,23 @@
+package tools
+
+import (
+	"context"
+	"testing"
+)
+
+func TestGetServerConfig(t *testing.T) {
+	handler := getServerConfig([]ServerSetting{
+		{Name: "log_level", Value: "warn", Source: "flag", Flag: "--log-level", Env: "KRAKEND_MCP_LOG_LEVEL"},
+		{Name: "transport", Value: "stdio", Source: "default", Flag: "--transport", Env: "KRAKEND_MCP_TRANSPORT"},
+	})
+	_, output, err := handler(context.Background(), nil, GetServerConfigInput{})
+	if err != nil {
+		t.Fatalf("get_server_config error = %v", err)
+	}
+	if len(output.Settings) != 2 || output.DataDir != dataDir || output.DocsURL != docsURL {
+		t.Errorf("Unexpected server config: %+v", output)
+	}
+	if output.Summary != "2 setting(s), 1 overridden by flags or environment variables" {
+		t.Errorf("Unexpected summary: %s", output.Summary)
+	}
+}
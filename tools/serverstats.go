@@ -0,0 +1,109 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/krakend/mcp-server/internal/metrics"
+	"github.com/krakend/mcp-server/internal/toolkit"
+	"github.com/krakend/mcp-server/tools/validation"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// EnableMetrics starts collecting server metrics and returns the middleware recording tool calls.
+// Validation and audit results are counted by execution method.
+func EnableMetrics() toolkit.Middleware {
+	registry := metrics.Enable()
+	validation.AddValidationObserver(func(config string, result validation.ValidationResult) {
+		metrics.Count(metrics.ValidationMethod, validation.ExecutionMethod(result.Method))
+	})
+	validation.AddAuditObserver(func(config string, result validation.AuditSecurityOutput) {
+		metrics.Count(metrics.ValidationMethod, validation.ExecutionMethod(result.Method))
+	})
+	return registry.Middleware()
+}
+
+// GetServerStatsInput defines input for get_server_stats tool
+type GetServerStatsInput struct{}
+
+// CounterStats are the values of a counter with the share of hits
+type CounterStats struct {
+	Counts  map[string]uint64 `json:"counts"`
+	Total   uint64            `json:"total"`
+	HitRate float64           `json:"hit_rate"` // Share of hits (schema cache) or searches with results, 0 to 1
+}
+
+// GetServerStatsOutput defines output for get_server_stats tool
+type GetServerStatsOutput struct {
+	Enabled           bool                `json:"enabled"`
+	UptimeSeconds     int                 `json:"uptime_seconds,omitempty"`
+	TotalCalls        uint64              `json:"total_calls"`
+	Tools             []metrics.ToolStats `json:"tools"`
+	ValidationMethods map[string]uint64   `json:"validation_methods,omitempty"`
+	SchemaCache       *CounterStats       `json:"schema_cache,omitempty"`
+	DocSearch         *CounterStats       `json:"doc_search,omitempty"`
+	Message           string              `json:"message"`
+}
+
+// counterStats summarizes a counter, hits being the sum of the given values
+func counterStats(counts map[string]uint64, hits ...string) *CounterStats {
+	stats := &CounterStats{Counts: counts}
+	if stats.Counts == nil {
+		stats.Counts = map[string]uint64{}
+	}
+	var hit uint64
+	for value, n := range stats.Counts {
+		stats.Total += n
+		for _, h := range hits {
+			if value == h {
+				hit += n
+			}
+		}
+	}
+	if stats.Total > 0 {
+		stats.HitRate = math.Round(float64(hit)/float64(stats.Total)*1000) / 1000
+	}
+	return stats
+}
+
+// GetServerStats reports the metrics collected since the server started
+func GetServerStats(ctx context.Context, req *mcp.CallToolRequest, input GetServerStatsInput) (*mcp.CallToolResult, GetServerStatsOutput, error) {
+	registry := metrics.Default()
+	if registry == nil {
+		return nil, GetServerStatsOutput{
+			Tools:   []metrics.ToolStats{},
+			Message: "Metrics are disabled. Start the server with --metrics=true or KRAKEND_MCP_METRICS=true to collect them.",
+		}, nil
+	}
+
+	snap := registry.Snapshot()
+	output := GetServerStatsOutput{
+		Enabled:           true,
+		UptimeSeconds:     int(time.Since(snap.Started).Seconds()),
+		Tools:             snap.Tools,
+		ValidationMethods: snap.Counters[metrics.ValidationMethod.Name],
+		SchemaCache:       counterStats(snap.Counters[metrics.SchemaCache.Name], "hit", "bundled"),
+		DocSearch:         counterStats(snap.Counters[metrics.DocSearch.Name], "results"),
+	}
+	if output.ValidationMethods == nil {
+		output.ValidationMethods = map[string]uint64{}
+	}
+	for _, tool := range output.Tools {
+		output.TotalCalls += tool.Calls
+	}
+	output.Message = fmt.Sprintf("%d tool call(s) to %d tool(s) in %s", output.TotalCalls, len(output.Tools), time.Duration(output.UptimeSeconds)*time.Second)
+	return nil, output, nil
+}
+
+// RegisterServerStatsTools registers the server metrics tool
+func RegisterServerStatsTools(server *mcp.Server) {
+	toolkit.AddTool(server,
+		&mcp.Tool{
+			Name:        "get_server_stats",
+			Description: "Report the usage metrics collected since the server started: calls, errors and latency of each tool, validations by method (native, docker, schema), JSON Schema cache and documentation search hit rates. Metrics are opt-in (--metrics=true); the tool says so when they are disabled.",
+		},
+		GetServerStats,
+	)
+}
@@ -0,0 +1,45 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/krakend/mcp-server/internal/metrics"
+	"github.com/krakend/mcp-server/internal/toolkit"
+	"github.com/krakend/mcp-server/tools/validation"
+)
+
+func TestGetServerStats(t *testing.T) {
+	metrics.Disable()
+	_, output, err := GetServerStats(context.Background(), nil, GetServerStatsInput{})
+	if err != nil || output.Enabled || !strings.Contains(output.Message, "--metrics=true") {
+		t.Fatalf("GetServerStats() while disabled = %+v, %v", output, err)
+	}
+
+	registry := metrics.Enable()
+	t.Cleanup(metrics.Disable)
+	registry.Observe("validate_config", toolkit.OutcomeSuccess, time.Second)
+	registry.Observe("search_documentation", toolkit.OutcomeSuccess, time.Millisecond)
+	metrics.Count(metrics.ValidationMethod, validation.ExecutionMethod("docker (krakend/krakend:2.10)"))
+	metrics.Count(metrics.SchemaCache, "hit")
+	metrics.Count(metrics.SchemaCache, "hit")
+	metrics.Count(metrics.SchemaCache, "hit")
+	metrics.Count(metrics.SchemaCache, "miss")
+	metrics.Count(metrics.DocSearch, "no_results")
+
+	_, output, err = GetServerStats(context.Background(), nil, GetServerStatsInput{})
+	if err != nil || !output.Enabled || output.TotalCalls != 2 || len(output.Tools) != 2 {
+		t.Fatalf("GetServerStats() = %+v, %v", output, err)
+	}
+	if output.ValidationMethods["docker"] != 1 {
+		t.Errorf("Unexpected validation methods: %v", output.ValidationMethods)
+	}
+	if output.SchemaCache.Total != 4 || output.SchemaCache.HitRate != 0.75 {
+		t.Errorf("Unexpected schema cache stats: %+v", output.SchemaCache)
+	}
+	if output.DocSearch.Total != 1 || output.DocSearch.HitRate != 0 {
+		t.Errorf("Unexpected search stats: %+v", output.DocSearch)
+	}
+}
@@ -0,0 +1,217 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/krakend/mcp-server/internal/runtime"
+	"github.com/krakend/mcp-server/internal/toolkit"
+	"github.com/krakend/mcp-server/tools/validation"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const dockerPullTimeout = 5 * time.Minute
+
+// SetupInput defines input for setup tool
+type SetupInput struct {
+	DataDir    string   `json:"data_dir,omitempty" jsonschema:"Data directory to use (optional, defaults to the current one; set KRAKEND_MCP_DATA_DIR to make it permanent)"`
+	Versions   []string `json:"versions,omitempty" jsonschema:"KrakenD versions whose schemas (and images) are prepared, e.g. [\"2.12\", \"latest\"] (optional, defaults to latest)"`
+	Edition    string   `json:"edition,omitempty" jsonschema:"Edition to prepare: ce, ee or both (optional, defaults to ce)"`
+	PullImages bool     `json:"pull_images,omitempty" jsonschema:"Pre-pull the KrakenD Docker images for the selected versions (optional, can take minutes)"`
+}
+
+// SetupStep is the outcome of one first-run initialization step
+type SetupStep struct {
+	Name       string `json:"name"`
+	Status     string `json:"status"` // "ok", "warning", "skipped" or "failed"
+	Detail     string `json:"detail"`
+	DurationMs int64  `json:"duration_ms"`
+}
+
+// SetupOutput defines output for setup tool
+type SetupOutput struct {
+	DataDir string      `json:"data_dir"`
+	Steps   []SetupStep `json:"steps"`
+	Ready   bool        `json:"ready"` // No step failed
+	Summary string      `json:"summary"`
+}
+
+// RunSetup performs the first-run initialization explicitly instead of lazily inside other tools
+func RunSetup(ctx context.Context, input SetupInput) (SetupOutput, error) {
+	edition := input.Edition
+	if edition == "" {
+		edition = "ce"
+	}
+	var enterprise []bool
+	switch edition {
+	case "ce":
+		enterprise = []bool{false}
+	case "ee":
+		enterprise = []bool{true}
+	case "both":
+		enterprise = []bool{false, true}
+	default:
+		return SetupOutput{}, fmt.Errorf("invalid edition %q (expected ce, ee or both)", edition)
+	}
+	versions := input.Versions
+	if len(versions) == 0 {
+		versions = []string{"latest"}
+	}
+
+	output := SetupOutput{Steps: []SetupStep{}}
+	step := func(name string, run func() (status, detail string)) {
+		start := time.Now()
+		status, detail := run()
+		output.Steps = append(output.Steps, SetupStep{
+			Name:       name,
+			Status:     status,
+			Detail:     detail,
+			DurationMs: time.Since(start).Milliseconds(),
+		})
+	}
+
+	step("data_dir", func() (string, string) {
+		dir := dataDir
+		if input.DataDir != "" {
+			dir = input.DataDir
+		}
+		if err := setDataDir(dir); err != nil {
+			return "failed", err.Error()
+		}
+		probe, err := os.CreateTemp(dataDir, ".setup-*")
+		if err != nil {
+			return "failed", fmt.Sprintf("%s is not writable: %v", dataDir, err)
+		}
+		probe.Close()
+		os.Remove(probe.Name())
+		if input.DataDir != "" && os.Getenv(dataDirEnv) != dataDir {
+			return "ok", fmt.Sprintf("Using %s; set %s=%s to keep using it on next starts", dataDir, dataDirEnv, dataDir)
+		}
+		return "ok", "Using " + dataDir
+	})
+	output.DataDir = dataDir
+
+	step("docs_index", func() (string, string) {
+		if err := InitializeDocSearch(); err != nil {
+			return "failed", err.Error()
+		}
+		detail := fmt.Sprintf("Documentation index ready (schema v%d)", getIndexVersion())
+		if needsRefresh() {
+			return "warning", detail + "; documentation is older than 7 days, run refresh_documentation_index when online"
+		}
+		return "ok", detail
+	})
+
+	step("feature_catalog", func() (string, string) {
+		if err := LoadFeatureData(); err != nil {
+			return "failed", err.Error()
+		}
+		return "ok", fmt.Sprintf("Feature catalog v%s loaded (%d features)", featureCatalog.Version, len(featureCatalog.Features))
+	})
+
+	step("schemas", func() (string, string) {
+		var done, failed []string
+		for _, v := range versions {
+			for _, ee := range enterprise {
+				url := validation.SchemaURL(v, ee)
+				if _, err := validation.SchemaFetcher(ctx, url); err != nil {
+					failed = append(failed, fmt.Sprintf("%s (%v)", url, err))
+					continue
+				}
+				done = append(done, url)
+			}
+		}
+		if len(failed) > 0 {
+			return "failed", fmt.Sprintf("Cached %d schema(s); failed: %s", len(done), strings.Join(failed, ", "))
+		}
+		return "ok", fmt.Sprintf("Cached %d schema(s) in %s", len(done), filepath.Join(dataDir, schemasDir))
+	})
+
+	env := runtime.DetectEnvironment()
+
+	step("docker_images", func() (string, string) {
+		if !input.PullImages {
+			return "skipped", "Not requested (set pull_images to pre-pull images)"
+		}
+		if !env.HasDocker {
+			return "skipped", "Docker is not available"
+		}
+		var pulled, failed []string
+		for _, v := range versions {
+			for _, ee := range enterprise {
+				// The images validations run, as the container engine names them
+				image := runtime.EngineImage(env.ContainerEngine, validation.DockerImage(v, ee))
+				pullCtx, cancel := context.WithTimeout(ctx, dockerPullTimeout)
+				out, err := exec.CommandContext(pullCtx, env.DockerBinary, "pull", image).CombinedOutput()
+				cancel()
+				if err != nil {
+					failed = append(failed, fmt.Sprintf("%s (%s)", image, strings.TrimSpace(string(out))))
+					continue
+				}
+				pulled = append(pulled, image)
+			}
+		}
+		if len(failed) > 0 {
+			return "failed", fmt.Sprintf("Pulled %v; failed: %s", pulled, strings.Join(failed, ", "))
+		}
+		return "ok", fmt.Sprintf("Pulled %s", strings.Join(pulled, ", "))
+	})
+
+	step("environment", func() (string, string) {
+		var parts []string
+		if env.HasNativeKrakenD {
+			native := "krakend found"
+			if v, err := runtime.GetLocalKrakenDVersion(); err == nil {
+				native = "krakend v" + v
+			}
+			parts = append(parts, native)
+		}
+		if env.HasDocker {
+			parts = append(parts, env.DockerVersion)
+		}
+		license := runtime.DetectLicense()
+		if license.Present {
+			parts = append(parts, "EE license at "+license.Source)
+		}
+		if !env.HasNativeKrakenD && !env.HasDocker {
+			return "warning", "Neither krakend nor Docker found: validation is limited to JSON Schema checks"
+		}
+		return "ok", strings.Join(parts, ", ")
+	})
+
+	output.Ready = true
+	counts := map[string]int{}
+	for _, s := range output.Steps {
+		counts[s.Status]++
+		if s.Status == "failed" {
+			output.Ready = false
+		}
+	}
+	output.Summary = fmt.Sprintf("Setup finished: %d ok, %d warning(s), %d skipped, %d failed", counts["ok"], counts["warning"], counts["skipped"], counts["failed"])
+	return output, nil
+}
+
+// Setup is the MCP handler of the setup tool
+func Setup(ctx context.Context, req *mcp.CallToolRequest, input SetupInput) (*mcp.CallToolResult, SetupOutput, error) {
+	output, err := RunSetup(ctx, input)
+	if err != nil {
+		return nil, SetupOutput{}, err
+	}
+	return &mcp.CallToolResult{Meta: map[string]interface{}{"ready": output.Ready}}, output, nil
+}
+
+// RegisterSetupTools registers the first-run setup tool
+func RegisterSetupTools(server *mcp.Server) {
+	toolkit.AddTool(server,
+		&mcp.Tool{
+			Name:        "setup",
+			Description: "Run first-run initialization explicitly: choose the data directory, extract the documentation index, load the feature catalog, cache JSON schemas for selected KrakenD versions, optionally pre-pull Docker images, and verify the environment. Returns a per-step summary report.",
+		},
+		Setup,
+	)
+}
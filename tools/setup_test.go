@@ -0,0 +1,52 @@
+package tools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/krakend/mcp-server/tools/validation"
+)
+
+func TestRunSetup_InvalidEdition(t *testing.T) {
+	if _, err := RunSetup(context.Background(), SetupInput{Edition: "pro"}); err == nil {
+		t.Error("Expected error for invalid edition")
+	}
+}
+
+func TestRunSetup_DataDirAndSchemas(t *testing.T) {
+	requested := setMockSchemaFetcher(t)
+	oldDataDir, oldCacheDir := dataDir, validation.SchemaCacheDir
+	t.Cleanup(func() { dataDir, validation.SchemaCacheDir = oldDataDir, oldCacheDir })
+
+	dir := filepath.Join(t.TempDir(), "krakend-mcp")
+	output, err := RunSetup(context.Background(), SetupInput{
+		DataDir:  dir,
+		Versions: []string{"2.12", "latest"},
+		Edition:  "both",
+	})
+	if err != nil {
+		t.Fatalf("RunSetup() error = %v", err)
+	}
+
+	steps := map[string]SetupStep{}
+	for _, s := range output.Steps {
+		steps[s.Name] = s
+	}
+	if steps["data_dir"].Status != "ok" || output.DataDir != dir {
+		t.Errorf("Expected data dir %s to be initialized, got %+v (%s)", dir, steps["data_dir"], output.DataDir)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "schemas")); err != nil {
+		t.Errorf("Expected schemas directory to be created: %v", err)
+	}
+	if steps["schemas"].Status != "ok" || len(*requested) != 4 {
+		t.Errorf("Expected 4 schemas (2 versions x 2 editions), got %+v %v", steps["schemas"], *requested)
+	}
+	if steps["docker_images"].Status != "skipped" {
+		t.Errorf("Expected image pulls to be skipped unless requested, got %+v", steps["docker_images"])
+	}
+	if len(output.Steps) != 6 || output.Summary == "" {
+		t.Errorf("Expected 6 steps with a summary, got %+v", output)
+	}
+}
@@ -0,0 +1,27 @@
+package tools
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/krakend/mcp-server/internal/tempfiles"
+	"github.com/krakend/mcp-server/tools/validation"
+)
+
+// Shutdown releases the server resources before exiting: running validation subprocesses are
+// aborted (killed after the grace period) and their temporary files removed, the configs being
+// remembered are written, then the documentation index is closed, once its initialization
+// finishes, and its lock file released
+func Shutdown(grace time.Duration) error {
+	if n := validation.AbortRunningCommands(grace); n > 0 {
+		slog.Info("Validation commands aborted", "count", n)
+	}
+	WaitConfigMemory()
+	if n := tempfiles.RemoveAll(); n > 0 {
+		slog.Info("Temporary validation files removed", "count", n)
+	}
+	if !waitDocSearchWarmup(grace) {
+		slog.Warn("Documentation index still initializing, closing it anyway", "grace_ms", grace.Milliseconds())
+	}
+	return CloseDocSearch()
+}
@@ -0,0 +1,432 @@
+package tools
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"log/slog"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/krakend/mcp-server/internal/features"
+	"github.com/krakend/mcp-server/internal/toolkit"
+	"github.com/krakend/mcp-server/tools/validation"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	configMemoryEnv       = "KRAKEND_MCP_CONFIG_MEMORY"
+	configMemoryFile      = "memory/configs.jsonl"
+	configEmbeddingDims   = 256
+	defaultSimilarResults = 5
+	maxSimilarResults     = 20
+)
+
+// configMemoryLimit caps the remembered configs: the least recently checked ones are evicted
+var configMemoryLimit = 200
+
+// configMemoryMu serializes access to the local config memory file
+var configMemoryMu sync.Mutex
+
+// configMemoryWrites tracks the outcomes being remembered in the background
+var configMemoryWrites sync.WaitGroup
+
+// ConfigOutcome is the last known validation or audit outcome of a remembered config
+type ConfigOutcome struct {
+	Valid     bool      `json:"valid"`
+	Method    string    `json:"method"`
+	Summary   string    `json:"summary"`
+	Issues    int       `json:"issues"`
+	Score     int       `json:"score,omitempty"` // Security score, audits only
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// rememberedConfig is a config stored in the local memory with its embedding
+type rememberedConfig struct {
+	ID         string         `json:"id"`
+	Name       string         `json:"name,omitempty"`
+	Source     string         `json:"source,omitempty"` // File path when the config was provided as a path
+	Namespaces []string       `json:"namespaces"`
+	Endpoints  int            `json:"endpoints"`
+	Vector     []float64      `json:"vector"`
+	Validation *ConfigOutcome `json:"validation,omitempty"`
+	Audit      *ConfigOutcome `json:"audit,omitempty"`
+	Config     string         `json:"config"` // Secrets redacted
+	UpdatedAt  time.Time      `json:"updated_at"`
+}
+
+// FindSimilarConfigsInput defines input for find_similar_configs tool
+type FindSimilarConfigsInput struct {
+	Config        string  `json:"config" jsonschema:"KrakenD configuration (JSON string or file path) to compare against remembered configs"`
+	MaxResults    int     `json:"max_results,omitempty" jsonschema:"Maximum number of results (optional, defaults to 5)"`
+	MinScore      float64 `json:"min_score,omitempty" jsonschema:"Minimum cosine similarity between 0 and 1 (optional)"`
+	IncludeConfig bool    `json:"include_config,omitempty" jsonschema:"Include the full remembered configuration in each result (optional)"`
+}
+
+// SimilarConfig is a remembered configuration similar to the input
+type SimilarConfig struct {
+	ID               string         `json:"id"`
+	Name             string         `json:"name,omitempty"`
+	Source           string         `json:"source,omitempty"`
+	Score            float64        `json:"score"`
+	SharedNamespaces []string       `json:"shared_namespaces"`
+	Endpoints        int            `json:"endpoints"`
+	Validation       *ConfigOutcome `json:"validation,omitempty"`
+	Audit            *ConfigOutcome `json:"audit,omitempty"`
+	Config           string         `json:"config,omitempty"`
+}
+
+// FindSimilarConfigsOutput defines output for find_similar_configs tool
+type FindSimilarConfigsOutput struct {
+	Enabled    bool            `json:"enabled"`
+	Remembered int             `json:"remembered"`
+	Results    []SimilarConfig `json:"results"`
+	Message    string          `json:"message"`
+}
+
+// configMemoryEnabled reports whether the user opted in to remembering validated configs
+func configMemoryEnabled() bool {
+	v := strings.ToLower(os.Getenv(configMemoryEnv))
+	return v == "1" || v == "true" || v == "yes"
+}
+
+// configEmbedding builds a normalized feature-hashing vector from the structure of a config.
+// Namespaces, key paths and selected enum-like values (methods, encodings, sd) are the features.
+func configEmbedding(config map[string]interface{}) []float64 {
+	counts := map[string]float64{}
+	var walk func(value interface{}, path string)
+	walk = func(value interface{}, path string) {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			for k, child := range v {
+				if strings.Contains(k, "/") {
+					counts["ns:"+k] += 3 // Namespaces are the strongest signal
+				}
+				childPath := path + "." + k
+				counts["key:"+childPath]++
+				walk(child, childPath)
+			}
+		case []interface{}:
+			for _, child := range v {
+				walk(child, path)
+			}
+		case string:
+			key := path[strings.LastIndex(path, ".")+1:]
+			switch key {
+			case "method", "encoding", "output_encoding", "sd", "alg", "strategy":
+				counts[key+":"+strings.ToLower(v)]++
+			}
+		}
+	}
+	walk(config, "$")
+
+	vector := make([]float64, configEmbeddingDims)
+	for feature, count := range counts {
+		h := fnv.New32a()
+		h.Write([]byte(feature))
+		sum := h.Sum32()
+		sign := 1.0
+		if sum&(1<<31) != 0 {
+			sign = -1.0
+		}
+		vector[sum%configEmbeddingDims] += sign * math.Sqrt(count)
+	}
+
+	var norm float64
+	for _, x := range vector {
+		norm += x * x
+	}
+	if norm > 0 {
+		norm = math.Sqrt(norm)
+		for i := range vector {
+			vector[i] /= norm
+		}
+	}
+	return vector
+}
+
+// cosineSimilarity returns the cosine similarity of two normalized vectors
+func cosineSimilarity(a, b []float64) float64 {
+	if len(a) != len(b) {
+		return 0
+	}
+	var dot float64
+	for i := range a {
+		dot += a[i] * b[i]
+	}
+	return dot
+}
+
+// loadConfigMemory reads every remembered config from the local memory file
+func loadConfigMemory() ([]rememberedConfig, error) {
+	f, err := os.Open(filepath.Join(dataDir, configMemoryFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to open config memory: %w", err)
+	}
+	defer f.Close()
+
+	var entries []rememberedConfig
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var entry rememberedConfig
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue // Skip corrupted lines
+		}
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
+
+// saveConfigMemory rewrites the local memory file atomically
+func saveConfigMemory(entries []rememberedConfig) error {
+	path := filepath.Join(dataDir, configMemoryFile)
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to create config memory directory: %w", err)
+	}
+
+	tmp := path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to write config memory: %w", err)
+	}
+	enc := json.NewEncoder(f)
+	for _, entry := range entries {
+		if err := enc.Encode(entry); err != nil {
+			f.Close()
+			os.Remove(tmp)
+			return fmt.Errorf("failed to encode config memory: %w", err)
+		}
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write config memory: %w", err)
+	}
+	return os.Rename(tmp, path)
+}
+
+// rememberConfig stores or updates a config and its latest outcome in the local memory. Secrets
+// are redacted before storing it, and the least recently checked configs are evicted past
+// configMemoryLimit.
+func rememberConfig(input string, update func(entry *rememberedConfig)) error {
+	configContent, err := readConfigContent(input)
+	if err != nil {
+		return err
+	}
+	var config map[string]interface{}
+	if err := json.Unmarshal([]byte(configContent), &config); err != nil {
+		return err
+	}
+
+	// Configs differing only in their secrets are the same entry
+	redacted := string(RedactJSON(json.RawMessage(configContent)))
+	sum := sha256.Sum256([]byte(redacted))
+	id := hex.EncodeToString(sum[:8])
+
+	configMemoryMu.Lock()
+	defer configMemoryMu.Unlock()
+
+	entries, err := loadConfigMemory()
+	if err != nil {
+		return err
+	}
+
+	idx := -1
+	for i := range entries {
+		if entries[i].ID == id {
+			idx = i
+			break
+		}
+	}
+	if idx == -1 {
+		namespaces := features.FindNamespacesInConfig(config)
+		sort.Strings(namespaces)
+		endpoints, _ := config["endpoints"].([]interface{})
+		name, _ := config["name"].(string)
+		entry := rememberedConfig{
+			ID:         id,
+			Name:       name,
+			Namespaces: namespaces,
+			Endpoints:  len(endpoints),
+			Vector:     configEmbedding(config),
+			Config:     redacted,
+		}
+		if !strings.HasPrefix(strings.TrimSpace(input), "{") {
+			entry.Source = input
+		}
+		entries = append(entries, entry)
+		idx = len(entries) - 1
+	}
+
+	update(&entries[idx])
+	entries[idx].UpdatedAt = time.Now()
+	if len(entries) > configMemoryLimit {
+		sort.SliceStable(entries, func(i, j int) bool { return entries[i].UpdatedAt.After(entries[j].UpdatedAt) })
+		entries = entries[:configMemoryLimit]
+	}
+	return saveConfigMemory(entries)
+}
+
+// rememberConfigAsync remembers a config in the background: observers run while the tool call
+// publishes its result
+func rememberConfigAsync(input string, update func(entry *rememberedConfig), what string) {
+	configMemoryWrites.Add(1)
+	go func() {
+		defer configMemoryWrites.Done()
+		if err := rememberConfig(input, update); err != nil {
+			slog.Warn("Could not remember "+what+" config", "error", err)
+		}
+	}()
+}
+
+// WaitConfigMemory blocks until the outcomes being remembered are written
+func WaitConfigMemory() {
+	configMemoryWrites.Wait()
+}
+
+// EnableConfigMemory records validation and audit outcomes when the user opted in
+func EnableConfigMemory() {
+	if !configMemoryEnabled() {
+		return
+	}
+
+	validation.AddValidationObserver(func(config string, result validation.ValidationResult) {
+		if result.Method == "file_read" {
+			return
+		}
+		checkedAt := time.Now()
+		rememberConfigAsync(config, func(entry *rememberedConfig) {
+			entry.Validation = &ConfigOutcome{
+				Valid:     result.Valid,
+				Method:    result.Method,
+				Summary:   result.Summary,
+				Issues:    len(result.Errors),
+				CheckedAt: checkedAt,
+			}
+		}, "validated")
+	})
+
+	validation.AddAuditObserver(func(config string, result validation.AuditSecurityOutput) {
+		if result.Method == "file_read" {
+			return
+		}
+		checkedAt := time.Now()
+		rememberConfigAsync(config, func(entry *rememberedConfig) {
+			entry.Audit = &ConfigOutcome{
+				Valid:     result.Valid,
+				Method:    result.Method,
+				Summary:   result.Summary,
+				Issues:    len(result.Issues),
+				Score:     result.Score,
+				CheckedAt: checkedAt,
+			}
+		}, "audited")
+	})
+
+	slog.Info("Config memory enabled", "path", filepath.Join(dataDir, configMemoryFile))
+}
+
+// FindSimilarConfigs retrieves remembered configurations similar to the input with their outcomes
+func FindSimilarConfigs(ctx context.Context, req *mcp.CallToolRequest, input FindSimilarConfigsInput) (*mcp.CallToolResult, FindSimilarConfigsOutput, error) {
+	output := FindSimilarConfigsOutput{
+		Enabled: configMemoryEnabled(),
+		Results: []SimilarConfig{},
+	}
+	if !output.Enabled {
+		output.Message = fmt.Sprintf("Config memory is disabled. Set %s=1 to remember validated and audited configs locally.", configMemoryEnv)
+		return nil, output, nil
+	}
+
+	configContent, err := readConfigContent(input.Config)
+	if err != nil {
+		return nil, FindSimilarConfigsOutput{}, fmt.Errorf("failed to read config: %w", err)
+	}
+	var config map[string]interface{}
+	if err := json.Unmarshal([]byte(configContent), &config); err != nil {
+		return nil, FindSimilarConfigsOutput{}, fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	configMemoryMu.Lock()
+	entries, err := loadConfigMemory()
+	configMemoryMu.Unlock()
+	if err != nil {
+		return nil, FindSimilarConfigsOutput{}, err
+	}
+	output.Remembered = len(entries)
+
+	maxResults := input.MaxResults
+	if maxResults <= 0 {
+		maxResults = defaultSimilarResults
+	}
+	if maxResults > maxSimilarResults {
+		maxResults = maxSimilarResults
+	}
+
+	vector := configEmbedding(config)
+	namespaces := map[string]struct{}{}
+	for _, ns := range features.FindNamespacesInConfig(config) {
+		namespaces[ns] = struct{}{}
+	}
+
+	for _, entry := range entries {
+		score := cosineSimilarity(vector, entry.Vector)
+		if score < input.MinScore {
+			continue
+		}
+		shared := []string{}
+		for _, ns := range entry.Namespaces {
+			if _, ok := namespaces[ns]; ok {
+				shared = append(shared, ns)
+			}
+		}
+		result := SimilarConfig{
+			ID:               entry.ID,
+			Name:             entry.Name,
+			Source:           entry.Source,
+			Score:            math.Round(score*1000) / 1000,
+			SharedNamespaces: shared,
+			Endpoints:        entry.Endpoints,
+			Validation:       entry.Validation,
+			Audit:            entry.Audit,
+		}
+		if input.IncludeConfig {
+			result.Config = entry.Config
+		}
+		output.Results = append(output.Results, result)
+	}
+
+	sort.SliceStable(output.Results, func(i, j int) bool {
+		return output.Results[i].Score > output.Results[j].Score
+	})
+	if len(output.Results) > maxResults {
+		output.Results = output.Results[:maxResults]
+	}
+
+	output.Message = fmt.Sprintf("Found %d similar config(s) among %d remembered", len(output.Results), output.Remembered)
+	return &mcp.CallToolResult{Meta: map[string]interface{}{"count": len(output.Results)}}, output, nil
+}
+
+// RegisterSimilarConfigTools registers config memory tools
+func RegisterSimilarConfigTools(server *mcp.Server) {
+	EnableConfigMemory()
+
+	toolkit.AddTool(server,
+		&mcp.Tool{
+			Name:        "find_similar_configs",
+			Description: "Find previously validated or audited KrakenD configurations similar to the given one, with their validation and audit outcomes, to reuse known-good patterns. Opt-in: requires KRAKEND_MCP_CONFIG_MEMORY=1; configs are stored locally only, with their secrets redacted.",
+		},
+		FindSimilarConfigs,
+	)
+}
@@ -0,0 +1,156 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestConfigEmbedding_Similarity(t *testing.T) {
+	parse := func(s string) map[string]interface{} {
+		t.Helper()
+		var config map[string]interface{}
+		if err := json.Unmarshal([]byte(s), &config); err != nil {
+			t.Fatalf("invalid test config: %v", err)
+		}
+		return config
+	}
+
+	jwtA := parse(`{"version": 3, "endpoints": [{"endpoint": "/a", "method": "GET", "extra_config": {"auth/validator": {"alg": "RS256"}}, "backend": [{"host": ["http://a"], "url_pattern": "/a"}]}]}`)
+	jwtB := parse(`{"version": 3, "endpoints": [{"endpoint": "/b", "method": "GET", "extra_config": {"auth/validator": {"alg": "RS256"}}, "backend": [{"host": ["http://b"], "url_pattern": "/b"}]}]}`)
+	other := parse(`{"version": 3, "extra_config": {"telemetry/opentelemetry": {"exporters": {}}}, "async_agent": [{"name": "agent", "consumer": {"topic": "x"}}]}`)
+
+	same := cosineSimilarity(configEmbedding(jwtA), configEmbedding(jwtB))
+	different := cosineSimilarity(configEmbedding(jwtA), configEmbedding(other))
+	if same < 0.99 {
+		t.Errorf("Expected structurally identical configs to score ~1, got %f", same)
+	}
+	if different >= same {
+		t.Errorf("Expected unrelated config to score lower (%f) than similar one (%f)", different, same)
+	}
+}
+
+func TestFindSimilarConfigs_Disabled(t *testing.T) {
+	t.Setenv(configMemoryEnv, "")
+
+	_, output, err := FindSimilarConfigs(context.Background(), nil, FindSimilarConfigsInput{Config: `{"version": 3}`})
+	if err != nil {
+		t.Fatalf("FindSimilarConfigs() error = %v", err)
+	}
+	if output.Enabled || len(output.Results) != 0 {
+		t.Errorf("Expected disabled memory with no results, got %+v", output)
+	}
+}
+
+func TestFindSimilarConfigs_RemembersOutcomes(t *testing.T) {
+	t.Setenv(configMemoryEnv, "1")
+	oldDataDir := dataDir
+	dataDir = t.TempDir()
+	defer func() { dataDir = oldDataDir }()
+
+	remembered := `{"version": 3, "name": "orders", "endpoints": [{"endpoint": "/orders", "extra_config": {"auth/validator": {"alg": "RS256"}}, "backend": [{"host": ["http://orders"], "url_pattern": "/orders"}]}]}`
+	err := rememberConfig(remembered, func(entry *rememberedConfig) {
+		entry.Validation = &ConfigOutcome{Valid: true, Method: "schema"}
+	})
+	if err != nil {
+		t.Fatalf("rememberConfig() error = %v", err)
+	}
+	// Remembering the same config again updates the existing entry
+	err = rememberConfig(remembered, func(entry *rememberedConfig) {
+		entry.Audit = &ConfigOutcome{Valid: true, Method: "basic", Score: 90}
+	})
+	if err != nil {
+		t.Fatalf("rememberConfig() error = %v", err)
+	}
+
+	query := `{"version": 3, "endpoints": [{"endpoint": "/users", "extra_config": {"auth/validator": {"alg": "RS256"}}, "backend": [{"host": ["http://users"], "url_pattern": "/users"}]}]}`
+	_, output, err := FindSimilarConfigs(context.Background(), nil, FindSimilarConfigsInput{Config: query, IncludeConfig: true})
+	if err != nil {
+		t.Fatalf("FindSimilarConfigs() error = %v", err)
+	}
+	if output.Remembered != 1 || len(output.Results) != 1 {
+		t.Fatalf("Expected 1 remembered result, got %+v", output)
+	}
+
+	result := output.Results[0]
+	if result.Name != "orders" || result.Config == "" {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+	if result.Validation == nil || result.Audit == nil || result.Audit.Score != 90 {
+		t.Errorf("Expected both validation and audit outcomes, got %+v / %+v", result.Validation, result.Audit)
+	}
+	if len(result.SharedNamespaces) != 1 || result.SharedNamespaces[0] != "auth/validator" {
+		t.Errorf("Expected shared auth/validator namespace, got %v", result.SharedNamespaces)
+	}
+}
+
+func TestRememberConfig_RedactsSecrets(t *testing.T) {
+	oldDataDir := dataDir
+	dataDir = t.TempDir()
+	defer func() { dataDir = oldDataDir }()
+
+	config := `{"version": 3, "extra_config": {"auth/api-keys": {"keys": [{"key": "s3cr3t-api-key", "roles": ["user"]}]}}, "endpoints": [{"endpoint": "/a", "backend": [{"host": ["http://a"], "url_pattern": "/a", "extra_config": {"auth/client-credentials": {"client_id": "app", "client_secret": "hunter2"}}}]}]}`
+	if err := rememberConfig(config, func(*rememberedConfig) {}); err != nil {
+		t.Fatalf("rememberConfig() error = %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dataDir, configMemoryFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "hunter2") || strings.Contains(string(data), "s3cr3t-api-key") {
+		t.Errorf("Secrets must not be remembered, got %s", data)
+	}
+	if !strings.Contains(string(data), "client_id") {
+		t.Errorf("Expected the rest of the config to be remembered, got %s", data)
+	}
+}
+
+func TestRememberConfig_EvictsLeastRecentlyChecked(t *testing.T) {
+	oldDataDir, oldLimit := dataDir, configMemoryLimit
+	dataDir, configMemoryLimit = t.TempDir(), 2
+	defer func() { dataDir, configMemoryLimit = oldDataDir, oldLimit }()
+
+	remember := func(name string) {
+		t.Helper()
+		if err := rememberConfig(`{"version": 3, "name": "`+name+`"}`, func(*rememberedConfig) {}); err != nil {
+			t.Fatalf("rememberConfig() error = %v", err)
+		}
+	}
+	remember("a")
+	remember("b")
+	remember("a") // Checked again: b is now the least recently checked
+	remember("c")
+
+	entries, err := loadConfigMemory()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name)
+	}
+	sort.Strings(names)
+	if strings.Join(names, ",") != "a,c" {
+		t.Errorf("Expected a and c to be kept, got %v", names)
+	}
+}
+
+func TestRememberConfigAsync(t *testing.T) {
+	oldDataDir := dataDir
+	dataDir = t.TempDir()
+	defer func() { dataDir = oldDataDir }()
+
+	rememberConfigAsync(`{"version": 3, "name": "published"}`, func(entry *rememberedConfig) {
+		entry.Validation = &ConfigOutcome{Valid: true, Method: "schema"}
+	}, "validated")
+	configMemoryWrites.Wait()
+
+	entries, err := loadConfigMemory()
+	if err != nil || len(entries) != 1 || entries[0].Validation == nil || !entries[0].Validation.Valid {
+		t.Errorf("Expected the validation to be remembered, got %+v (%v)", entries, err)
+	}
+}
@@ -0,0 +1,378 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/krakend/mcp-server/internal/toolkit"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// snippetLanguages lists the clients generate_client_snippets writes code for
+var snippetLanguages = []string{"fetch", "axios", "go", "python"}
+
+// GenerateClientSnippetsInput defines input for generate_client_snippets tool
+type GenerateClientSnippetsInput struct {
+	Config         string   `json:"config" jsonschema:"KrakenD configuration (JSON string or file path)"`
+	ConfigEncoding string   `json:"config_encoding,omitempty" jsonschema:"Encoding of config for large configurations: base64 or gzip+base64 (optional, defaults to a plain JSON string or file path)"`
+	Endpoint       string   `json:"endpoint" jsonschema:"Endpoint path as declared in the configuration (e.g. /users/{id})"`
+	Method         string   `json:"method,omitempty" jsonschema:"Endpoint method when the path is declared for several methods (optional)"`
+	GatewayURL     string   `json:"gateway_url,omitempty" jsonschema:"Public base URL of the gateway (optional, defaults to http://localhost:8080)"`
+	Languages      []string `json:"languages,omitempty" jsonschema:"Clients to generate: fetch, axios, go and/or python (optional, defaults to all)"`
+}
+
+// ClientSnippet is the code calling an endpoint with a client library
+type ClientSnippet struct {
+	Language string `json:"language"`
+	Library  string `json:"library"`
+	Code     string `json:"code"`
+}
+
+// GenerateClientSnippetsOutput defines output for generate_client_snippets tool
+type GenerateClientSnippetsOutput struct {
+	Method      string          `json:"method"`
+	Endpoint    string          `json:"endpoint"`
+	PathParams  []string        `json:"path_params"`
+	QueryParams []string        `json:"query_params"` // From input_query_strings
+	Accept      string          `json:"accept"`
+	ContentType string          `json:"content_type,omitempty"` // Request body type, for methods with a body
+	Env         []string        `json:"env"`                    // Environment variables holding credentials
+	Snippets    []ClientSnippet `json:"snippets"`
+	Notes       []string        `json:"notes,omitempty"`
+}
+
+// snippetRequest is the language-neutral description of the call
+type snippetRequest struct {
+	method      string
+	baseURL     string
+	path        string   // With {param} placeholders
+	pathParams  []string // In path order
+	queryParams []string
+	headers     [][2]string // name, value prefix; the credential env var is appended when set
+	headerEnv   []string    // credential env var of each header, "" for literal values
+	body        bool
+	basicAuth   bool
+}
+
+// outputEncodingContentType returns the response content type of an output_encoding
+func outputEncodingContentType(encoding string) string {
+	switch encoding {
+	case "xml":
+		return "application/xml"
+	case "string":
+		return "text/plain"
+	case "no-op":
+		return "*/*"
+	}
+	return "application/json"
+}
+
+// GenerateClientSnippets writes code calling an endpoint with common client libraries
+func GenerateClientSnippets(ctx context.Context, req *mcp.CallToolRequest, input GenerateClientSnippetsInput) (*mcp.CallToolResult, GenerateClientSnippetsOutput, error) {
+	configContent, err := readEncodedConfig(input.Config, input.ConfigEncoding)
+	if err != nil {
+		return nil, GenerateClientSnippetsOutput{}, fmt.Errorf("failed to read config: %w", err)
+	}
+	var config map[string]interface{}
+	if err := json.Unmarshal([]byte(configContent), &config); err != nil {
+		return nil, GenerateClientSnippetsOutput{}, fmt.Errorf("invalid JSON: %w", err)
+	}
+	endpoint, err := findEndpoint(config, input.Endpoint, input.Method)
+	if err != nil {
+		return nil, GenerateClientSnippetsOutput{}, err
+	}
+	languages := input.Languages
+	if len(languages) == 0 {
+		languages = snippetLanguages
+	}
+	for _, l := range languages {
+		if !slices.Contains(snippetLanguages, l) {
+			return nil, GenerateClientSnippetsOutput{}, fmt.Errorf("unknown language %q (available: %s)", l, strings.Join(snippetLanguages, ", "))
+		}
+	}
+	gateway := strings.TrimRight(input.GatewayURL, "/")
+	if gateway == "" {
+		gateway = defaultReplayGateway
+	}
+
+	encoding, _ := endpoint["output_encoding"].(string)
+	r := snippetRequest{method: endpointMethod(endpoint), baseURL: gateway, path: input.Endpoint}
+	output := GenerateClientSnippetsOutput{
+		Method:      r.method,
+		Endpoint:    input.Endpoint,
+		PathParams:  []string{},
+		QueryParams: []string{},
+		Accept:      outputEncodingContentType(encoding),
+		Env:         []string{},
+		Snippets:    []ClientSnippet{},
+	}
+
+	for _, segment := range strings.Split(input.Endpoint, "/") {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			r.pathParams = append(r.pathParams, strings.Trim(segment, "{}"))
+		}
+	}
+	output.PathParams = append(output.PathParams, r.pathParams...)
+	if params, ok := endpoint["input_query_strings"].([]interface{}); ok {
+		for _, p := range params {
+			if name, ok := p.(string); ok && name != "*" {
+				r.queryParams = append(r.queryParams, name)
+			}
+		}
+	}
+	output.QueryParams = append(output.QueryParams, r.queryParams...)
+
+	r.headers = append(r.headers, [2]string{"Accept", output.Accept})
+	r.headerEnv = append(r.headerEnv, "")
+	for _, a := range endpointAuth(config, endpoint) {
+		switch {
+		case a.Type == "jwt":
+			r.headers = append(r.headers, [2]string{"Authorization", "Bearer "})
+			r.headerEnv = append(r.headerEnv, "TOKEN")
+			output.Env = append(output.Env, "TOKEN")
+		case a.Type == "api-key" && a.Header != "":
+			prefix := ""
+			if a.Header == "Authorization" {
+				prefix = "Bearer "
+			}
+			r.headers = append(r.headers, [2]string{a.Header, prefix})
+			r.headerEnv = append(r.headerEnv, "API_KEY")
+			output.Env = append(output.Env, "API_KEY")
+		case a.Type == "api-key":
+			output.Notes = append(output.Notes, fmt.Sprintf("Add your API key in the %s query string parameter", a.QueryString))
+			output.Env = append(output.Env, "API_KEY")
+		case a.Type == "basic":
+			r.basicAuth = true
+			output.Env = append(output.Env, "API_USER", "API_PASSWORD")
+		}
+	}
+	if r.method == "POST" || r.method == "PUT" || r.method == "PATCH" {
+		r.body = true
+		output.ContentType = "application/json"
+		r.headers = append(r.headers, [2]string{"Content-Type", output.ContentType})
+		r.headerEnv = append(r.headerEnv, "")
+		headers, _ := endpoint["input_headers"].([]interface{})
+		if !slices.ContainsFunc(headers, func(h interface{}) bool { s, _ := h.(string); return s == "*" || strings.EqualFold(s, "Content-Type") }) {
+			output.Notes = append(output.Notes, "Content-Type is not in input_headers, so backends receive the body without it")
+		}
+	}
+	if len(r.queryParams) > 0 {
+		output.Notes = append(output.Notes, "Query string parameters are optional: the gateway forwards only "+strings.Join(r.queryParams, ", "))
+	}
+
+	for _, l := range languages {
+		switch l {
+		case "fetch":
+			output.Snippets = append(output.Snippets, ClientSnippet{Language: "javascript", Library: "fetch", Code: fetchSnippet(r)})
+		case "axios":
+			output.Snippets = append(output.Snippets, ClientSnippet{Language: "javascript", Library: "axios", Code: axiosSnippet(r)})
+		case "go":
+			output.Snippets = append(output.Snippets, ClientSnippet{Language: "go", Library: "net/http", Code: goSnippet(r)})
+		case "python":
+			output.Snippets = append(output.Snippets, ClientSnippet{Language: "python", Library: "requests", Code: pythonSnippet(r)})
+		}
+	}
+	return nil, output, nil
+}
+
+// quoted renders a string literal valid in JavaScript, Go and Python
+func quoted(s string) string {
+	data, _ := json.Marshal(s)
+	return string(data)
+}
+
+func fetchSnippet(r snippetRequest) string {
+	var b strings.Builder
+	args := strings.Join(r.pathParams, ", ")
+	if r.body {
+		args = strings.TrimPrefix(args+", body", ", ")
+	}
+	fmt.Fprintf(&b, "async function callEndpoint(%s) {\n", args)
+	path := r.path
+	for _, p := range r.pathParams {
+		path = strings.Replace(path, "{"+p+"}", "${encodeURIComponent("+p+")}", 1)
+	}
+	fmt.Fprintf(&b, "  const url = new URL(`%s%s`);\n", r.baseURL, path)
+	if len(r.queryParams) > 0 {
+		fmt.Fprintf(&b, "  // Optional query string: %s\n  // url.searchParams.set(%s, value);\n", strings.Join(r.queryParams, ", "), quoted(r.queryParams[0]))
+	}
+	b.WriteString("  const response = await fetch(url, {\n")
+	fmt.Fprintf(&b, "    method: %s,\n    headers: {\n", quoted(r.method))
+	for i, h := range r.headers {
+		value := quoted(h[1])
+		if r.headerEnv[i] != "" {
+			value = "`" + h[1] + "${process.env." + r.headerEnv[i] + "}`"
+		}
+		fmt.Fprintf(&b, "      %s: %s,\n", quoted(h[0]), value)
+	}
+	if r.basicAuth {
+		b.WriteString("      \"Authorization\": `Basic ${btoa(`${process.env.API_USER}:${process.env.API_PASSWORD}`)}`,\n")
+	}
+	b.WriteString("    },\n")
+	if r.body {
+		b.WriteString("    body: JSON.stringify(body),\n")
+	}
+	b.WriteString("  });\n  if (!response.ok) {\n    throw new Error(`Request failed with ${response.status}`);\n  }\n  return response.json();\n}\n")
+	return b.String()
+}
+
+func axiosSnippet(r snippetRequest) string {
+	var b strings.Builder
+	b.WriteString("import axios from 'axios';\n\n")
+	args := strings.Join(r.pathParams, ", ")
+	if r.body {
+		args = strings.TrimPrefix(args+", body", ", ")
+	}
+	fmt.Fprintf(&b, "export async function callEndpoint(%s) {\n", args)
+	path := r.path
+	for _, p := range r.pathParams {
+		path = strings.Replace(path, "{"+p+"}", "${encodeURIComponent("+p+")}", 1)
+	}
+	b.WriteString("  const response = await axios.request({\n")
+	fmt.Fprintf(&b, "    method: %s,\n    url: `%s%s`,\n    headers: {\n", quoted(strings.ToLower(r.method)), r.baseURL, path)
+	for i, h := range r.headers {
+		value := quoted(h[1])
+		if r.headerEnv[i] != "" {
+			value = "`" + h[1] + "${process.env." + r.headerEnv[i] + "}`"
+		}
+		fmt.Fprintf(&b, "      %s: %s,\n", quoted(h[0]), value)
+	}
+	b.WriteString("    },\n")
+	if r.basicAuth {
+		b.WriteString("    auth: { username: process.env.API_USER, password: process.env.API_PASSWORD },\n")
+	}
+	if len(r.queryParams) > 0 {
+		fmt.Fprintf(&b, "    // Optional query string: %s\n    params: {},\n", strings.Join(r.queryParams, ", "))
+	}
+	if r.body {
+		b.WriteString("    data: body,\n")
+	}
+	b.WriteString("  });\n  return response.data;\n}\n")
+	return b.String()
+}
+
+func goSnippet(r snippetRequest) string {
+	var b strings.Builder
+	b.WriteString("import (\n")
+	if r.body {
+		b.WriteString("\t\"bytes\"\n\t\"encoding/json\"\n")
+	}
+	b.WriteString("\t\"fmt\"\n\t\"io\"\n\t\"net/http\"\n")
+	if len(r.pathParams) > 0 {
+		b.WriteString("\t\"net/url\"\n")
+	}
+	if r.basicAuth || slices.ContainsFunc(r.headerEnv, func(env string) bool { return env != "" }) {
+		b.WriteString("\t\"os\"\n")
+	}
+	b.WriteString(")\n\n")
+
+	params := make([]string, 0, len(r.pathParams)+1)
+	for _, p := range r.pathParams {
+		params = append(params, p+" string")
+	}
+	if r.body {
+		params = append(params, "body any")
+	}
+	fmt.Fprintf(&b, "func callEndpoint(%s) ([]byte, error) {\n", strings.Join(params, ", "))
+
+	format, args := r.path, []string{}
+	for _, p := range r.pathParams {
+		format = strings.Replace(format, "{"+p+"}", "%s", 1)
+		args = append(args, "url.PathEscape("+p+")")
+	}
+	if len(args) > 0 {
+		fmt.Fprintf(&b, "\tendpoint := fmt.Sprintf(%s, %s)\n", quoted(r.baseURL+format), strings.Join(args, ", "))
+	} else {
+		fmt.Fprintf(&b, "\tendpoint := %s\n", quoted(r.baseURL+format))
+	}
+	if len(r.queryParams) > 0 {
+		fmt.Fprintf(&b, "\t// Optional query string: %s\n", strings.Join(r.queryParams, ", "))
+	}
+
+	reader := "nil"
+	if r.body {
+		b.WriteString("\tpayload, err := json.Marshal(body)\n\tif err != nil {\n\t\treturn nil, err\n\t}\n")
+		reader = "bytes.NewReader(payload)"
+	}
+	fmt.Fprintf(&b, "\treq, err := http.NewRequest(%s, endpoint, %s)\n\tif err != nil {\n\t\treturn nil, err\n\t}\n", quoted(r.method), reader)
+	for i, h := range r.headers {
+		value := quoted(h[1])
+		if r.headerEnv[i] != "" {
+			value = fmt.Sprintf("%s+os.Getenv(%s)", quoted(h[1]), quoted(r.headerEnv[i]))
+			value = strings.TrimPrefix(value, `""+`)
+		}
+		fmt.Fprintf(&b, "\treq.Header.Set(%s, %s)\n", quoted(h[0]), value)
+	}
+	if r.basicAuth {
+		b.WriteString("\treq.SetBasicAuth(os.Getenv(\"API_USER\"), os.Getenv(\"API_PASSWORD\"))\n")
+	}
+	b.WriteString("\n\tresp, err := http.DefaultClient.Do(req)\n\tif err != nil {\n\t\treturn nil, err\n\t}\n\tdefer resp.Body.Close()\n")
+	b.WriteString("\tif resp.StatusCode >= 400 {\n\t\treturn nil, fmt.Errorf(\"request failed with %d\", resp.StatusCode)\n\t}\n\treturn io.ReadAll(resp.Body)\n}\n")
+	return b.String()
+}
+
+func pythonSnippet(r snippetRequest) string {
+	var b strings.Builder
+	b.WriteString("import os\n")
+	if len(r.pathParams) > 0 {
+		b.WriteString("from urllib.parse import quote\n")
+	}
+	b.WriteString("\nimport requests\n\n\n")
+	params := append([]string{}, r.pathParams...)
+	if r.body {
+		params = append(params, "body")
+	}
+	fmt.Fprintf(&b, "def call_endpoint(%s):\n", strings.Join(params, ", "))
+	path := r.path
+	for _, p := range r.pathParams {
+		path = strings.Replace(path, "{"+p+"}", "{quote(str("+p+"), safe='')}", 1)
+	}
+	prefix := ""
+	if len(r.pathParams) > 0 {
+		prefix = "f"
+	}
+	fmt.Fprintf(&b, "    url = %s\"%s%s\"\n", prefix, r.baseURL, path)
+	b.WriteString("    headers = {\n")
+	for i, h := range r.headers {
+		value := quoted(h[1])
+		if r.headerEnv[i] != "" {
+			value = fmt.Sprintf("%s + os.environ[%s]", quoted(h[1]), quoted(r.headerEnv[i]))
+			value = strings.TrimPrefix(value, `"" + `)
+		}
+		fmt.Fprintf(&b, "        %s: %s,\n", quoted(h[0]), value)
+	}
+	b.WriteString("    }\n")
+	args := []string{quoted(r.method), "url", "headers=headers"}
+	if r.body {
+		args = append(args, "json=body")
+	}
+	if r.basicAuth {
+		args = append(args, "auth=(os.environ[\"API_USER\"], os.environ[\"API_PASSWORD\"])")
+	}
+	if len(r.queryParams) > 0 {
+		fmt.Fprintf(&b, "    # Optional query string: %s\n    params = {}\n", strings.Join(r.queryParams, ", "))
+		args = append(args, "params=params")
+	}
+	args = append(args, "timeout=10")
+	fmt.Fprintf(&b, "    response = requests.request(%s)\n    response.raise_for_status()\n", strings.Join(args, ", "))
+	if r.headers[0][1] == "application/json" { // Accept header
+		b.WriteString("    return response.json()\n")
+	} else {
+		b.WriteString("    return response.content\n")
+	}
+	return b.String()
+}
+
+// RegisterSnippetTools registers client code generation tools
+func RegisterSnippetTools(server *mcp.Server) {
+	toolkit.AddTool(server,
+		&mcp.Tool{
+			Name:        "generate_client_snippets",
+			Description: "Generate code calling a configured endpoint with JavaScript fetch, axios, Go net/http and Python requests. Path parameters, forwarded query strings, the authentication header (JWT, API key or basic auth, read from environment variables) and content types are derived from the endpoint definition.",
+		},
+		GenerateClientSnippets,
+	)
+}
@@ -0,0 +1,78 @@
+package tools
+
+import (
+	"context"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+)
+
+func TestGenerateClientSnippets(t *testing.T) {
+	_, output, err := GenerateClientSnippets(context.Background(), nil, GenerateClientSnippetsInput{
+		Config:     endpointDocsTestConfig,
+		Endpoint:   "/users/{id}",
+		Method:     "GET",
+		GatewayURL: "https://api.example.com/",
+	})
+	if err != nil {
+		t.Fatalf("GenerateClientSnippets() error = %v", err)
+	}
+	if len(output.Snippets) != 4 {
+		t.Fatalf("Expected 4 snippets, got %d", len(output.Snippets))
+	}
+	if len(output.PathParams) != 1 || output.PathParams[0] != "id" || len(output.QueryParams) != 1 {
+		t.Errorf("Unexpected params %v %v", output.PathParams, output.QueryParams)
+	}
+	if len(output.Env) != 1 || output.Env[0] != "TOKEN" {
+		t.Errorf("Expected the JWT in TOKEN, got %v", output.Env)
+	}
+
+	want := map[string][]string{
+		"fetch":    {"https://api.example.com/users/${encodeURIComponent(id)}", "`Bearer ${process.env.TOKEN}`"},
+		"axios":    {"method: \"get\"", "params: {}"},
+		"net/http": {`fmt.Sprintf("https://api.example.com/users/%s", url.PathEscape(id))`, `"Bearer "+os.Getenv("TOKEN")`},
+		"requests": {`f"https://api.example.com/users/{quote(str(id), safe='')}"`, `"Bearer " + os.environ["TOKEN"]`, "response.json()"},
+	}
+	for _, s := range output.Snippets {
+		for _, w := range want[s.Library] {
+			if !strings.Contains(s.Code, w) {
+				t.Errorf("%s snippet missing %q:\n%s", s.Library, w, s.Code)
+			}
+		}
+	}
+}
+
+func TestGenerateClientSnippets_GoCompiles(t *testing.T) {
+	config := `{"version": 3, "endpoints": [
+		{"endpoint": "/orders/{tenant}/{id}", "method": "PUT", "extra_config": {"auth/basic": {}}, "backend": [{"host": ["http://orders"], "url_pattern": "/"}]},
+		{"endpoint": "/health", "backend": [{"host": ["http://h"], "url_pattern": "/"}]}
+	]}`
+	for _, endpoint := range []string{"/orders/{tenant}/{id}", "/health"} {
+		_, output, err := GenerateClientSnippets(context.Background(), nil, GenerateClientSnippetsInput{Config: config, Endpoint: endpoint, Languages: []string{"go"}})
+		if err != nil {
+			t.Fatalf("GenerateClientSnippets(%s) error = %v", endpoint, err)
+		}
+		if _, err := parser.ParseFile(token.NewFileSet(), "snippet.go", "package client\n\n"+output.Snippets[0].Code, 0); err != nil {
+			t.Errorf("Go snippet for %s does not parse: %v\n%s", endpoint, err, output.Snippets[0].Code)
+		}
+		if endpoint == "/health" {
+			if strings.Contains(output.Snippets[0].Code, `"os"`) {
+				t.Errorf("os is unused without credentials:\n%s", output.Snippets[0].Code)
+			}
+			continue
+		}
+		if !strings.Contains(output.Snippets[0].Code, "req.SetBasicAuth") || output.ContentType != "application/json" {
+			t.Errorf("Expected basic auth and a JSON body:\n%s", output.Snippets[0].Code)
+		}
+		if len(output.Notes) == 0 {
+			t.Error("Expected a note about Content-Type not being forwarded")
+		}
+	}
+}
+
+func TestGenerateClientSnippets_UnknownLanguage(t *testing.T) {
+	if _, _, err := GenerateClientSnippets(context.Background(), nil, GenerateClientSnippetsInput{Config: endpointDocsTestConfig, Endpoint: "/files", Languages: []string{"cobol"}}); err == nil {
+		t.Error("Expected an error for an unknown language")
+	}
+}
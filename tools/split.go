@@ -0,0 +1,357 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/krakend/mcp-server/internal/toolkit"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Groupings of split_config
+const (
+	SplitByPrefix = "prefix" // The first literal path segments, without api and version prefixes
+	SplitByTag    = "tag"    // The first tag of the endpoint, from the sidecar names file or suggested
+)
+
+// Layout of the Flexible Configuration project generated by split_config
+const (
+	splitBaseTemplate = "krakend.tmpl"
+	splitSettingsDir  = "config/settings"
+	splitPartialsDir  = "config/partials"
+)
+
+// SplitConfigInput defines input for split_config tool
+type SplitConfigInput struct {
+	Config    string `json:"config" jsonschema:"KrakenD configuration to split (JSON string or file path)"`
+	GroupBy   string `json:"group_by,omitempty" jsonschema:"How endpoints are grouped into files: prefix (default, by the first path segments without api and version prefixes) or tag (by the first tag of the endpoint names sidecar file, or the suggested resource tag)"`
+	Depth     int    `json:"depth,omitempty" jsonschema:"Path segments making the group with group_by prefix (default 1)"`
+	OutputDir string `json:"output_dir,omitempty" jsonschema:"Directory where the Flexible Configuration project is written (optional, files are only returned without it)"`
+}
+
+// SplitGroup is a set of endpoints moved to the same partial
+type SplitGroup struct {
+	Name      string   `json:"name"`
+	File      string   `json:"file"`
+	Endpoints []string `json:"endpoints"` // METHOD /path
+}
+
+// SplitFile is a file of the generated Flexible Configuration project
+type SplitFile struct {
+	Path    string `json:"path"` // Relative to output_dir
+	Content string `json:"content"`
+}
+
+// SplitVerification compares the rendered Flexible Configuration with the original configuration
+type SplitVerification struct {
+	ByteEquivalent bool   `json:"byte_equivalent"`
+	Equivalent     bool   `json:"equivalent"` // Same settings and endpoints, maybe in another order
+	Difference     string `json:"difference,omitempty"`
+}
+
+// SplitConfigOutput defines output for split_config tool
+type SplitConfigOutput struct {
+	Config       string            `json:"config"`
+	GroupBy      string            `json:"group_by"`
+	Groups       []SplitGroup      `json:"groups"`
+	Files        []SplitFile       `json:"files"`
+	OutputDir    string            `json:"output_dir,omitempty"`
+	Command      string            `json:"command"` // Renders and checks the project
+	Verification SplitVerification `json:"verification"`
+	Notes        []string          `json:"notes,omitempty"`
+	Summary      string            `json:"summary"`
+}
+
+// endpointSpan is the byte range of an endpoint in the configuration
+type endpointSpan struct {
+	start, end int64
+}
+
+// endpointSpans locates the elements of the endpoints list of a JSON configuration in its bytes
+func endpointSpans(data []byte) ([]endpointSpan, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if token, err := dec.Token(); err != nil || token != json.Delim('{') {
+		return nil, fmt.Errorf("the configuration is not a JSON object")
+	}
+	var spans []endpointSpan
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		if key != "endpoints" {
+			var skipped json.RawMessage
+			if err := dec.Decode(&skipped); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if token, err := dec.Token(); err != nil || token != json.Delim('[') {
+			return nil, fmt.Errorf("endpoints is not a list")
+		}
+		spans = nil
+		for dec.More() {
+			start := dec.InputOffset()
+			for start < int64(len(data)) && strings.IndexByte(" \t\r\n,", data[start]) >= 0 {
+				start++
+			}
+			var endpoint json.RawMessage
+			if err := dec.Decode(&endpoint); err != nil {
+				return nil, err
+			}
+			spans = append(spans, endpointSpan{start: start, end: dec.InputOffset()})
+		}
+		if _, err := dec.Token(); err != nil {
+			return nil, err
+		}
+	}
+	return spans, nil
+}
+
+// splitPrefixGroup names the group of a path by its first literal segments, skipping api and
+// version prefixes like suggestEndpointName does: /api/v1/users/{id} → users
+func splitPrefixGroup(path string, depth int) string {
+	var literals []string
+	for i, segment := range strings.Split(strings.Trim(path, "/"), "/") {
+		switch {
+		case len(literals) == depth:
+		case segment == "" || strings.HasPrefix(segment, "{") || segment == "*":
+		case len(literals) == 0 && apiVersionSegment.MatchString(strings.ToLower(segment)):
+		case len(literals) == 0 && i == 0 && strings.EqualFold(segment, "api"):
+		default:
+			if literal := strings.Trim(nameUnsafeChars.ReplaceAllString(strings.ToLower(segment), "-"), "-"); literal != "" {
+				literals = append(literals, literal)
+			}
+		}
+	}
+	if len(literals) == 0 {
+		return "root"
+	}
+	return strings.Join(literals, "-")
+}
+
+// escapeTemplateText keeps the actions delimiters of the configuration literal in a template
+func escapeTemplateText(text string) string {
+	return strings.ReplaceAll(text, "{{", `{{ "{{" }}`)
+}
+
+// renderSplit renders a base template including partials like the Flexible Configuration does
+func renderSplit(base string, partials map[string]string) (string, error) {
+	tmpl, err := template.New(splitBaseTemplate).Funcs(template.FuncMap{
+		"include": func(name string) (string, error) {
+			content, ok := partials[name]
+			if !ok {
+				return "", fmt.Errorf("partial %s not found", name)
+			}
+			return content, nil
+		},
+	}).Parse(base)
+	if err != nil {
+		return "", err
+	}
+	var rendered strings.Builder
+	if err := tmpl.Execute(&rendered, nil); err != nil {
+		return "", err
+	}
+	return rendered.String(), nil
+}
+
+// splitEquivalent compares two configurations ignoring the order of their endpoints
+func splitEquivalent(original, rendered string) (bool, string) {
+	var a, b map[string]interface{}
+	if err := json.Unmarshal([]byte(original), &a); err != nil {
+		return false, err.Error()
+	}
+	if err := json.Unmarshal([]byte(rendered), &b); err != nil {
+		return false, "the rendered configuration is not valid JSON: " + err.Error()
+	}
+	endpoints := func(config map[string]interface{}) []string {
+		list, _ := config["endpoints"].([]interface{})
+		values := make([]string, 0, len(list))
+		for _, endpoint := range list {
+			values = append(values, clusterValue(endpoint))
+		}
+		slices.Sort(values)
+		return values
+	}
+	if !slices.Equal(endpoints(a), endpoints(b)) {
+		return false, "the rendered endpoints differ from the original ones"
+	}
+	delete(a, "endpoints")
+	delete(b, "endpoints")
+	if clusterValue(a) != clusterValue(b) {
+		return false, "the rendered service-level settings differ from the original ones"
+	}
+	return true, ""
+}
+
+// SplitConfig splits a configuration into per-domain partials recomposed by Flexible Configuration
+func SplitConfig(ctx context.Context, req *mcp.CallToolRequest, input SplitConfigInput) (*mcp.CallToolResult, SplitConfigOutput, error) {
+	groupBy := envOrDefault(input.GroupBy, SplitByPrefix)
+	if groupBy != SplitByPrefix && groupBy != SplitByTag {
+		return nil, SplitConfigOutput{}, fmt.Errorf("invalid group_by %q: use prefix or tag", input.GroupBy)
+	}
+	depth := input.Depth
+	if depth <= 0 {
+		depth = 1
+	}
+	name := dependencyConfigName(input.Config, 0)
+	configContent, err := readConfigContent(input.Config)
+	if err != nil {
+		return nil, SplitConfigOutput{}, fmt.Errorf("failed to read config: %w", err)
+	}
+	var config map[string]interface{}
+	if err := json.Unmarshal([]byte(configContent), &config); err != nil {
+		return nil, SplitConfigOutput{}, fmt.Errorf("invalid JSON in %s (only JSON configurations can be split): %w", name, err)
+	}
+	data := []byte(configContent)
+	spans, err := endpointSpans(data)
+	if err != nil {
+		return nil, SplitConfigOutput{}, fmt.Errorf("failed to locate the endpoints of %s: %w", name, err)
+	}
+	endpoints, _ := config["endpoints"].([]interface{})
+	if len(spans) == 0 || len(spans) != len(endpoints) {
+		return nil, SplitConfigOutput{}, fmt.Errorf("%s declares no endpoints to split", name)
+	}
+
+	// Group every endpoint, groups in order of first appearance
+	var names map[string]EndpointName
+	if groupBy == SplitByTag {
+		names = loadEndpointNames(input.Config)
+	}
+	output := SplitConfigOutput{Config: name, GroupBy: groupBy, Groups: []SplitGroup{}, Files: []SplitFile{}}
+	groupOf := make([]int, len(endpoints))
+	index := map[string]int{}
+	for i, ep := range endpoints {
+		endpoint, _ := ep.(map[string]interface{})
+		method := endpointMethod(endpoint)
+		path, _ := endpoint["endpoint"].(string)
+		group := splitPrefixGroup(path, depth)
+		if groupBy == SplitByTag {
+			if named, ok := names[endpointKey(method, path)]; ok && len(named.Tags) > 0 {
+				group = named.Tags[0]
+			} else {
+				suggested, version := suggestEndpointName(method, path)
+				group = suggestEndpointTags(config, endpoint, suggested, version)[0]
+			}
+			if group = strings.Trim(nameUnsafeChars.ReplaceAllString(strings.ToLower(group), "-"), "-"); group == "" {
+				group = "root"
+			}
+		}
+		if _, ok := index[group]; !ok {
+			index[group] = len(output.Groups)
+			output.Groups = append(output.Groups, SplitGroup{Name: group, File: filepath.ToSlash(filepath.Join(splitPartialsDir, group+".json"))})
+		}
+		groupOf[i] = index[group]
+		output.Groups[groupOf[i]].Endpoints = append(output.Groups[groupOf[i]].Endpoints, endpointKey(method, path))
+	}
+
+	// Endpoints keep their original text. When every group is contiguous, the separators between
+	// groups are kept too and the rendered configuration is identical to the original.
+	contiguous := true
+	for i := 1; i < len(groupOf); i++ {
+		if groupOf[i] < groupOf[i-1] {
+			contiguous = false
+		}
+	}
+	separator := ",\n"
+	if len(spans) > 1 {
+		separator = string(data[spans[0].end:spans[1].start])
+	}
+	partials := map[string]string{}
+	var base strings.Builder
+	base.WriteString(escapeTemplateText(string(data[:spans[0].start])))
+	for g, group := range output.Groups {
+		var members []endpointSpan
+		for i, span := range spans {
+			if groupOf[i] == g {
+				members = append(members, span)
+			}
+		}
+		var partial string
+		if contiguous {
+			partial = string(data[members[0].start:members[len(members)-1].end])
+		} else {
+			texts := make([]string, 0, len(members))
+			for _, span := range members {
+				texts = append(texts, string(data[span.start:span.end]))
+			}
+			partial = strings.Join(texts, separator)
+		}
+		partials[group.Name+".json"] = partial
+		output.Files = append(output.Files, SplitFile{Path: group.File, Content: partial})
+
+		if g > 0 {
+			between := separator
+			if contiguous {
+				between = string(data[spans[slices.Index(groupOf, g)-1].end:members[0].start])
+			}
+			base.WriteString(between)
+		}
+		fmt.Fprintf(&base, "{{ include %q }}", group.Name+".json")
+	}
+	base.WriteString(escapeTemplateText(string(data[spans[len(spans)-1].end:])))
+	output.Files = append([]SplitFile{{Path: splitBaseTemplate, Content: base.String()}}, output.Files...)
+
+	// include inserts the partials as they are: they have no trailing newline of their own
+	rendered, err := renderSplit(base.String(), partials)
+	if err != nil {
+		return nil, SplitConfigOutput{}, fmt.Errorf("failed to render the generated templates: %w", err)
+	}
+	output.Verification.ByteEquivalent = rendered == configContent
+	output.Verification.Equivalent = output.Verification.ByteEquivalent
+	if !output.Verification.ByteEquivalent {
+		output.Verification.Equivalent, output.Verification.Difference = splitEquivalent(configContent, rendered)
+	}
+	if !contiguous {
+		output.Notes = append(output.Notes, "Endpoints of the same group were not contiguous: the rendered configuration lists them group by group, so it is equivalent but not byte-identical to the original. The router does not depend on the endpoints order.")
+	}
+
+	output.Command = fmt.Sprintf("FC_ENABLE=1 FC_SETTINGS=%s FC_PARTIALS=%s FC_OUT=krakend.json krakend check -c %s", splitSettingsDir, splitPartialsDir, splitBaseTemplate)
+	output.Notes = append(output.Notes,
+		fmt.Sprintf("%s is empty: move environment-specific values there as JSON settings files and reference them from the templates.", splitSettingsDir),
+		"Add new endpoints to the partial of their domain. A new domain needs its own partial and an include in "+splitBaseTemplate+".")
+
+	if input.OutputDir != "" {
+		for _, dir := range []string{splitSettingsDir, splitPartialsDir} {
+			if err := os.MkdirAll(filepath.Join(input.OutputDir, dir), 0o755); err != nil {
+				return nil, SplitConfigOutput{}, fmt.Errorf("failed to create %s: %w", dir, err)
+			}
+		}
+		for _, file := range output.Files {
+			if err := os.WriteFile(filepath.Join(input.OutputDir, file.Path), []byte(file.Content), 0o644); err != nil {
+				return nil, SplitConfigOutput{}, fmt.Errorf("failed to write %s: %w", file.Path, err)
+			}
+		}
+		output.OutputDir = input.OutputDir
+	}
+
+	verdict := "byte-identical to the original"
+	switch {
+	case !output.Verification.ByteEquivalent && output.Verification.Equivalent:
+		verdict = "equivalent to the original with the endpoints reordered"
+	case !output.Verification.Equivalent:
+		verdict = "DIFFERENT from the original: " + output.Verification.Difference
+	}
+	output.Summary = fmt.Sprintf("Split %d endpoint(s) of %s into %d partial(s) by %s: the rendered configuration is %s",
+		len(endpoints), name, len(output.Groups), groupBy, verdict)
+	return nil, output, nil
+}
+
+// RegisterSplitTools registers the configuration splitting tool
+func RegisterSplitTools(server *mcp.Server) {
+	toolkit.AddTool(server,
+		&mcp.Tool{
+			Name:        "split_config",
+			Description: "Split a monolithic KrakenD configuration into per-domain partials: groups the endpoints by path prefix or tag, generates the Flexible Configuration base template including them, and renders it to verify the result is byte-identical (or, when groups interleave, equivalent) to the original configuration.",
+		},
+		SplitConfig,
+	)
+}
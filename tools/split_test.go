@@ -0,0 +1,151 @@
+package tools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const splitConfig = `{
+    "$schema": "https://www.krakend.io/schema/v2.9/krakend.json",
+    "version": 3,
+    "endpoints": [
+        {"endpoint": "/api/v1/users", "backend": [{"url_pattern": "/users"}]},
+        {
+            "endpoint": "/api/v1/users/{id}",
+            "backend": [{"url_pattern": "/users/{id}"}]
+        },
+        {"endpoint": "/orders", "method": "POST", "backend": [{"url_pattern": "/orders", "extra_config": {"modifier/body-generator": {"template": "{{ .req_body }}"}}}]},
+        {"endpoint": "/", "backend": [{"url_pattern": "/"}]}
+    ],
+    "extra_config": {"router": {"return_error_msg": true}}
+}
+`
+
+func TestSplitConfig(t *testing.T) {
+	dir := t.TempDir()
+	_, output, err := SplitConfig(context.Background(), nil, SplitConfigInput{Config: splitConfig, OutputDir: dir})
+	if err != nil {
+		t.Fatalf("SplitConfig() error = %v", err)
+	}
+	if !output.Verification.ByteEquivalent || !output.Verification.Equivalent {
+		t.Fatalf("Expected a byte-identical split, got %+v (%s)", output.Verification, output.Summary)
+	}
+	var groups []string
+	for _, group := range output.Groups {
+		groups = append(groups, group.Name)
+	}
+	if strings.Join(groups, " ") != "users orders root" || len(output.Groups[0].Endpoints) != 2 || output.Groups[1].Endpoints[0] != "POST /orders" {
+		t.Errorf("Unexpected groups %+v", output.Groups)
+	}
+
+	base, err := os.ReadFile(filepath.Join(dir, "krakend.tmpl"))
+	if err != nil {
+		t.Fatalf("Expected the base template written: %v", err)
+	}
+	if !strings.Contains(string(base), `{{ include "users.json" }},
+        {{ include "orders.json" }}`) {
+		t.Errorf("Expected the includes with the original separators, got:\n%s", base)
+	}
+	orders, err := os.ReadFile(filepath.Join(dir, "config", "partials", "orders.json"))
+	if err != nil || !strings.Contains(string(orders), `"template": "{{ .req_body }}"`) {
+		t.Errorf("Expected the orders partial with its template kept as is, got %q (%v)", orders, err)
+	}
+	if info, err := os.Stat(filepath.Join(dir, "config", "settings")); err != nil || !info.IsDir() {
+		t.Errorf("Expected the settings directory, got %v", err)
+	}
+	if !strings.Contains(output.Command, "FC_PARTIALS=config/partials") || !strings.Contains(output.Command, "-c krakend.tmpl") {
+		t.Errorf("Unexpected command %q", output.Command)
+	}
+}
+
+func TestSplitConfig_Interleaved(t *testing.T) {
+	config := `{"version": 3, "endpoints": [
+		{"endpoint": "/users", "backend": [{"url_pattern": "/users"}]},
+		{"endpoint": "/orders", "backend": [{"url_pattern": "/orders"}]},
+		{"endpoint": "/users/{id}", "backend": [{"url_pattern": "/users/{id}"}]}
+	], "timeout": "{{ not a template }}"}`
+	_, output, err := SplitConfig(context.Background(), nil, SplitConfigInput{Config: config})
+	if err != nil {
+		t.Fatalf("SplitConfig() error = %v", err)
+	}
+	if output.Verification.ByteEquivalent || !output.Verification.Equivalent {
+		t.Errorf("Expected an equivalent split with the endpoints reordered, got %+v", output.Verification)
+	}
+	if !strings.Contains(output.Files[0].Content, `"timeout": "{{ "{{" }} not a template }}"`) {
+		t.Errorf("Expected the literal delimiters escaped in the base template, got:\n%s", output.Files[0].Content)
+	}
+	if !strings.Contains(strings.Join(output.Notes, " "), "not contiguous") {
+		t.Errorf("Expected a note about the reordered endpoints, got %v", output.Notes)
+	}
+	if output.OutputDir != "" {
+		t.Errorf("Nothing should be written without output_dir, got %q", output.OutputDir)
+	}
+}
+
+func TestSplitConfig_ByTag(t *testing.T) {
+	dir := t.TempDir()
+	config := filepath.Join(dir, "krakend.json")
+	content := `{"version": 3, "endpoints": [
+		{"endpoint": "/users", "backend": [{"url_pattern": "/users"}]},
+		{"endpoint": "/profiles/{id}", "backend": [{"url_pattern": "/profiles/{id}"}]},
+		{"endpoint": "/orders", "backend": [{"url_pattern": "/orders"}]}
+	]}`
+	if err := os.WriteFile(config, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	sidecar := `{"endpoints": {"GET /profiles/{id}": {"name": "profile-detail", "tags": ["Users Domain"]}}}`
+	if err := os.WriteFile(filepath.Join(dir, "krakend.endpoints.json"), []byte(sidecar), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, output, err := SplitConfig(context.Background(), nil, SplitConfigInput{Config: config, GroupBy: SplitByTag})
+	if err != nil {
+		t.Fatalf("SplitConfig() error = %v", err)
+	}
+	var groups []string
+	for _, group := range output.Groups {
+		groups = append(groups, group.Name+":"+group.File)
+	}
+	want := "users:config/partials/users.json users-domain:config/partials/users-domain.json orders:config/partials/orders.json"
+	if strings.Join(groups, " ") != want {
+		t.Errorf("Expected the sidecar tag to win over the suggested one, got %v", groups)
+	}
+	if !output.Verification.ByteEquivalent {
+		t.Errorf("Expected a byte-identical split, got %+v", output.Verification)
+	}
+}
+
+func TestSplitPrefixGroup(t *testing.T) {
+	tests := []struct {
+		path  string
+		depth int
+		want  string
+	}{
+		{"/api/v1/users/{id}", 1, "users"},
+		{"/v2.1/orders/{id}/items", 2, "orders-items"},
+		{"/Admin_Panel/stats", 1, "admin-panel"},
+		{"/", 1, "root"},
+		{"/{tenant}/*", 1, "root"},
+	}
+	for _, tt := range tests {
+		if got := splitPrefixGroup(tt.path, tt.depth); got != tt.want {
+			t.Errorf("splitPrefixGroup(%q, %d) = %q, want %q", tt.path, tt.depth, got, tt.want)
+		}
+	}
+}
+
+func TestSplitConfig_Errors(t *testing.T) {
+	for _, input := range []SplitConfigInput{
+		{Config: `{"version": 3, "endpoints": []}`},
+		{Config: `{"version": 3}`},
+		{Config: `{"version": 3, "endpoints": [{"endpoint": "/a"}]}`, GroupBy: "team"},
+		{Config: `{"version": 3,`},
+	} {
+		if _, _, err := SplitConfig(context.Background(), nil, input); err == nil {
+			t.Errorf("Expected an error for %+v", input)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package tools
+
+// Prometheus names of the metrics exported by telemetry/opentelemetry (OpenTelemetry names with
+// dots replaced by underscores, as the Prometheus exporter does). Histograms expose _bucket,
+// _sum and _count series.
+const (
+	metricServerDuration     = "http_server_duration"      // Global layer: end-to-end request duration
+	metricServerResponseSize = "http_server_response_size" // Global layer
+	metricProxyDuration      = "krakend_proxy_duration"    // Proxy layer: endpoint pipeline duration
+	metricBackendDuration    = "krakend_backend_duration"  // Backend layer: stage duration
+	metricClientDuration     = "http_client_duration"      // Backend layer: round trip to the backend
+	metricClientReadSize     = "http_client_response_read_size"
+	metricClientGetConn      = "http_client_request_get_conn_duration"
+	metricClientDNS          = "http_client_request_dns_duration"
+	metricClientTLS          = "http_client_request_tls_duration"
+
+	// Labels set by the KrakenD OpenTelemetry instrumentation
+	labelRoute      = "url_path"
+	labelStatusCode = "http_response_status_code"
+	labelBackend    = "server_address"
+)
+
+// telemetrySettings summarizes which metrics a configuration exports
+type telemetrySettings struct {
+	ServiceName        string // OpenTelemetry service name, or the config name
+	OpenTelemetry      bool
+	LegacyMetrics      bool // telemetry/metrics (stats endpoint, no Prometheus series)
+	PrometheusExporter bool
+	PrometheusPort     int
+	Global             bool
+	Proxy              bool
+	BackendStage       bool
+	BackendRoundTrip   bool
+	BackendReadPayload bool
+	BackendConnection  bool
+	ProcessMetrics     bool
+	GoMetrics          bool
+}
+
+// detectTelemetry reads the telemetry namespaces of a configuration
+func detectTelemetry(config map[string]interface{}) telemetrySettings {
+	var t telemetrySettings
+	t.ServiceName, _ = config["name"].(string)
+	extra, _ := config["extra_config"].(map[string]interface{})
+	if _, ok := extra["telemetry/metrics"]; ok {
+		t.LegacyMetrics = true
+	}
+	otel, ok := extra["telemetry/opentelemetry"].(map[string]interface{})
+	if !ok {
+		return t
+	}
+	t.OpenTelemetry = true
+	if name, ok := otel["service_name"].(string); ok && name != "" {
+		t.ServiceName = name
+	}
+
+	exporters, _ := otel["exporters"].(map[string]interface{})
+	prometheus, _ := exporters["prometheus"].([]interface{})
+	for _, p := range prometheus {
+		exporter, ok := p.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		t.PrometheusExporter = true
+		if port, ok := exporter["port"].(float64); ok && t.PrometheusPort == 0 {
+			t.PrometheusPort = int(port)
+		}
+		t.ProcessMetrics = t.ProcessMetrics || exporter["process_metrics"] == true
+		t.GoMetrics = t.GoMetrics || exporter["go_metrics"] == true
+	}
+	if t.PrometheusExporter && t.PrometheusPort == 0 {
+		t.PrometheusPort = 9090
+	}
+
+	// Every layer reports metrics unless disabled
+	layers, _ := otel["layers"].(map[string]interface{})
+	global, _ := layers["global"].(map[string]interface{})
+	proxy, _ := layers["proxy"].(map[string]interface{})
+	backend, _ := layers["backend"].(map[string]interface{})
+	backendMetrics, _ := backend["metrics"].(map[string]interface{})
+
+	t.Global = global["disable_metrics"] != true
+	t.Proxy = proxy["disable_metrics"] != true
+	t.BackendStage = backendMetrics["disable_stage"] != true
+	t.BackendRoundTrip = backendMetrics["round_trip"] == true
+	t.BackendReadPayload = backendMetrics["read_payload"] == true
+	t.BackendConnection = backendMetrics["detailed_connection"] == true
+	return t
+}
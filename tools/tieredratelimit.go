@@ -0,0 +1,227 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/krakend/mcp-server/internal/toolkit"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	tieredRateLimitNamespace = "qos/ratelimit/tiered"
+	defaultTierHeader        = "X-Tier"
+	defaultClientHeader      = "X-User-Id"
+)
+
+// RateLimitTier defines the limits of a tier
+type RateLimitTier struct {
+	Value    string  `json:"value" jsonschema:"Tier value, e.g. gold (empty for the default tier applying to any other value)"`
+	MaxRate  float64 `json:"max_rate" jsonschema:"Requests allowed per client every period"`
+	Capacity int     `json:"capacity,omitempty" jsonschema:"Burst size (optional, defaults to max_rate)"`
+	Every    string  `json:"every,omitempty" jsonschema:"Period of max_rate, e.g. 1s, 1m or 1h (optional, defaults to 1s)"`
+}
+
+// GenerateTieredRateLimitInput defines input for generate_tiered_rate_limit tool
+type GenerateTieredRateLimitInput struct {
+	Tiers          []RateLimitTier `json:"tiers" jsonschema:"Tier definitions with their limits"`
+	Header         string          `json:"header,omitempty" jsonschema:"Header selecting the tier (defaults to X-Tier when the tier comes from a claim)"`
+	Claim          string          `json:"claim,omitempty" jsonschema:"JWT claim selecting the tier, propagated to the tier header by auth/validator (optional)"`
+	ClientHeader   string          `json:"client_header,omitempty" jsonschema:"Header identifying each client (optional, defaults to the client IP, or X-User-Id with client_claim)"`
+	ClientClaim    string          `json:"client_claim,omitempty" jsonschema:"JWT claim identifying each client, e.g. sub (optional)"`
+	Config         string          `json:"config,omitempty" jsonschema:"KrakenD configuration to update (optional, JSON string or file path)"`
+	ConfigEncoding string          `json:"config_encoding,omitempty" jsonschema:"Encoding of config for large configurations: base64 or gzip+base64 (optional, defaults to a plain JSON string or file path)"`
+	Endpoints      []string        `json:"endpoints,omitempty" jsonschema:"Endpoints that get the tiered limits (optional, defaults to every endpoint of the config)"`
+	Edition        string          `json:"edition,omitempty" jsonschema:"Target edition: ce or ee (optional, detected from the config)"`
+}
+
+// GenerateTieredRateLimitOutput defines output for generate_tiered_rate_limit tool
+type GenerateTieredRateLimitOutput struct {
+	Namespace   string                 `json:"namespace"`
+	Edition     string                 `json:"edition"`
+	ExtraConfig map[string]interface{} `json:"extra_config"`               // Endpoint extra_config entry
+	Propagate   [][]string             `json:"propagate_claims,omitempty"` // auth/validator propagate_claims entries the tiers need
+	Config      map[string]interface{} `json:"config,omitempty"`
+	Changes     []string               `json:"changes,omitempty"` // JSON paths modified
+	Warnings    []string               `json:"warnings,omitempty"`
+	Notes       []string               `json:"notes"`
+}
+
+// tieredRateLimit builds the tiered rate limit settings
+func tieredRateLimit(tiers []RateLimitTier, tierHeader, clientHeader string) (map[string]interface{}, error) {
+	if len(tiers) == 0 {
+		return nil, fmt.Errorf("at least one tier is required")
+	}
+	var seen []string
+	var entries, fallback []interface{}
+	for _, tier := range tiers {
+		if slices.Contains(seen, tier.Value) {
+			return nil, fmt.Errorf("tier %q is defined twice", tier.Value)
+		}
+		seen = append(seen, tier.Value)
+		if tier.MaxRate <= 0 {
+			return nil, fmt.Errorf("tier %q needs a max_rate greater than 0", tier.Value)
+		}
+		limit := map[string]interface{}{
+			"client_max_rate": tier.MaxRate,
+			"client_capacity": tier.Capacity,
+			"every":           envOrDefault(tier.Every, "1s"),
+			"strategy":        "ip",
+		}
+		if tier.Capacity <= 0 {
+			limit["client_capacity"] = int(tier.MaxRate)
+		}
+		if clientHeader != "" {
+			limit["strategy"] = "header"
+			limit["key"] = clientHeader
+		}
+		entry := map[string]interface{}{
+			"tier_value":    tier.Value,
+			"tier_value_as": "literal",
+			"ratelimits":    []interface{}{limit},
+		}
+		if tier.Value == "" {
+			// The default tier matches any value: declared last so named tiers match first
+			entry["tier_value_as"] = "*"
+			fallback = append(fallback, entry)
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return map[string]interface{}{
+		"tier_key": tierHeader,
+		"tiers":    append(entries, fallback...),
+	}, nil
+}
+
+// propagatesClaim reports whether auth/validator copies a claim to a header
+func propagatesClaim(validator map[string]interface{}, claim, header string) bool {
+	propagated, _ := validator["propagate_claims"].([]interface{})
+	for _, p := range propagated {
+		pair, ok := p.([]interface{})
+		if ok && len(pair) == 2 && pair[0] == claim && strings.EqualFold(fmt.Sprint(pair[1]), header) {
+			return true
+		}
+	}
+	return false
+}
+
+// GenerateTieredRateLimit generates Enterprise tiered rate limits, wiring the JWT claims that
+// select the tier and identify the client through auth/validator
+func GenerateTieredRateLimit(ctx context.Context, req *mcp.CallToolRequest, input GenerateTieredRateLimitInput) (*mcp.CallToolResult, GenerateTieredRateLimitOutput, error) {
+	if input.Edition == "ce" {
+		return nil, GenerateTieredRateLimitOutput{}, fmt.Errorf("tiered rate limits require KrakenD Enterprise (%s is not available in the Community Edition): use qos/ratelimit/router with a single limit instead", tieredRateLimitNamespace)
+	}
+	if input.Header == "" && input.Claim == "" {
+		return nil, GenerateTieredRateLimitOutput{}, fmt.Errorf("header or claim is required to select the tier")
+	}
+	tierHeader := envOrDefault(input.Header, defaultTierHeader)
+	clientHeader := input.ClientHeader
+	if input.ClientClaim != "" {
+		clientHeader = envOrDefault(clientHeader, defaultClientHeader)
+	}
+	settings, err := tieredRateLimit(input.Tiers, tierHeader, clientHeader)
+	if err != nil {
+		return nil, GenerateTieredRateLimitOutput{}, err
+	}
+
+	output := GenerateTieredRateLimitOutput{
+		Namespace:   tieredRateLimitNamespace,
+		Edition:     "ee",
+		ExtraConfig: map[string]interface{}{tieredRateLimitNamespace: settings},
+		Notes: []string{
+			fmt.Sprintf("The tier is read from the %s header; requests whose tier matches no definition get the default tier, or are not limited when there is none.", tierHeader),
+		},
+	}
+	if input.Claim != "" {
+		output.Propagate = append(output.Propagate, []string{input.Claim, tierHeader})
+	} else {
+		output.Warnings = append(output.Warnings, fmt.Sprintf("Clients can choose their own tier by sending %s: select the tier from a JWT claim, or make sure only a trusted proxy sets the header", tierHeader))
+	}
+	if input.ClientClaim != "" {
+		output.Propagate = append(output.Propagate, []string{input.ClientClaim, clientHeader})
+	}
+	if clientHeader == "" {
+		output.Notes = append(output.Notes, "Clients are identified by their IP address: clients behind the same NAT or proxy share their limits.")
+	}
+	if len(output.Propagate) > 0 {
+		output.Notes = append(output.Notes, "Propagated claims overwrite the headers sent by the client, so the tier and client identity cannot be spoofed once auth/validator validates the token.")
+	}
+
+	if input.Config == "" {
+		return nil, output, nil
+	}
+	configContent, err := readEncodedConfig(input.Config, input.ConfigEncoding)
+	if err != nil {
+		return nil, GenerateTieredRateLimitOutput{}, fmt.Errorf("failed to read config: %w", err)
+	}
+	var config map[string]interface{}
+	if err := json.Unmarshal([]byte(configContent), &config); err != nil {
+		return nil, GenerateTieredRateLimitOutput{}, fmt.Errorf("invalid JSON: %w", err)
+	}
+	if input.Edition == "" && !DetectEnterpriseFeatures(configContent) {
+		output.Warnings = append(output.Warnings, fmt.Sprintf("%s is an Enterprise feature and the configuration uses no other Enterprise feature: run it with the krakend-ee image", tieredRateLimitNamespace))
+	}
+
+	endpoints, _ := config["endpoints"].([]interface{})
+	for i, ep := range endpoints {
+		endpoint, ok := ep.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		path, _ := endpoint["endpoint"].(string)
+		if len(input.Endpoints) > 0 && !slices.Contains(input.Endpoints, path) {
+			continue
+		}
+		extra, _ := endpoint["extra_config"].(map[string]interface{})
+		if extra == nil {
+			extra = map[string]interface{}{}
+			endpoint["extra_config"] = extra
+		}
+		location := fmt.Sprintf("$.endpoints[%d].extra_config['%s']", i, tieredRateLimitNamespace)
+		if _, exists := extra[tieredRateLimitNamespace]; exists {
+			output.Warnings = append(output.Warnings, fmt.Sprintf("%s already existed and was replaced", location))
+		}
+		extra[tieredRateLimitNamespace] = settings
+		output.Changes = append(output.Changes, location)
+
+		if len(output.Propagate) == 0 {
+			continue
+		}
+		// Cross-check the claims against the JWT validation of the endpoint
+		validator, ok := extra["auth/validator"].(map[string]interface{})
+		if !ok {
+			output.Warnings = append(output.Warnings, fmt.Sprintf("%s %s has no auth/validator: the claims cannot be read and every request gets the default tier", endpointMethod(endpoint), path))
+			continue
+		}
+		propagated, _ := validator["propagate_claims"].([]interface{})
+		for _, pair := range output.Propagate {
+			if propagatesClaim(validator, pair[0], pair[1]) {
+				continue
+			}
+			propagated = append(propagated, []interface{}{pair[0], pair[1]})
+			output.Changes = append(output.Changes, fmt.Sprintf("$.endpoints[%d].extra_config['auth/validator'].propagate_claims", i))
+		}
+		validator["propagate_claims"] = propagated
+	}
+	if len(input.Endpoints) > 0 && len(output.Changes) == 0 {
+		return nil, GenerateTieredRateLimitOutput{}, fmt.Errorf("none of the endpoints %s was found in the configuration", strings.Join(input.Endpoints, ", "))
+	}
+	output.Changes = slices.Compact(output.Changes)
+	output.Config = config
+	return nil, output, nil
+}
+
+// RegisterTieredRateLimitTools registers the tiered rate limit generation tool
+func RegisterTieredRateLimitTools(server *mcp.Server) {
+	toolkit.AddTool(server,
+		&mcp.Tool{
+			Name:        "generate_tiered_rate_limit",
+			Description: "Generate Enterprise tiered rate limits (qos/ratelimit/tiered): per-tier client limits selected by a header or a JWT claim, with clients identified by IP, header or claim. Applied to the endpoints of a configuration, it cross-checks the auth/validator of each endpoint and adds the propagate_claims entries the tiers need.",
+		},
+		GenerateTieredRateLimit,
+	)
+}
@@ -0,0 +1,81 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestGenerateTieredRateLimit(t *testing.T) {
+	setMockFeatureFetcher(t, minimalFeatureYAML)
+	config := `{"version": 3, "endpoints": [
+		{"endpoint": "/orders", "extra_config": {"auth/validator": {"alg": "RS256", "jwk_url": "https://idp/jwks", "propagate_claims": [["sub", "X-User-Id"]]}}},
+		{"endpoint": "/public"}
+	]}`
+	_, output, err := GenerateTieredRateLimit(context.Background(), nil, GenerateTieredRateLimitInput{
+		Tiers: []RateLimitTier{
+			{Value: "", MaxRate: 1},
+			{Value: "gold", MaxRate: 100, Every: "1m"},
+		},
+		Claim:       "plan",
+		ClientClaim: "sub",
+		Config:      config,
+	})
+	if err != nil {
+		t.Fatalf("GenerateTieredRateLimit() error = %v", err)
+	}
+
+	tiers := output.ExtraConfig[tieredRateLimitNamespace].(map[string]interface{})["tiers"].([]interface{})
+	if len(tiers) != 2 || tiers[0].(map[string]interface{})["tier_value"] != "gold" || tiers[1].(map[string]interface{})["tier_value_as"] != "*" {
+		t.Errorf("Expected the gold tier first and the default tier last, got %v", tiers)
+	}
+	limit := tiers[0].(map[string]interface{})["ratelimits"].([]interface{})[0].(map[string]interface{})
+	if limit["strategy"] != "header" || limit["key"] != "X-User-Id" || limit["every"] != "1m" {
+		t.Errorf("Unexpected limit: %v", limit)
+	}
+
+	validator := output.Config["endpoints"].([]interface{})[0].(map[string]interface{})["extra_config"].(map[string]interface{})["auth/validator"].(map[string]interface{})
+	if propagated := validator["propagate_claims"].([]interface{}); len(propagated) != 2 {
+		t.Errorf("Expected the plan claim to be propagated next to sub, got %v", propagated)
+	}
+	warnings := strings.Join(output.Warnings, "\n")
+	if !strings.Contains(warnings, "GET /public has no auth/validator") || !strings.Contains(warnings, "Enterprise feature") {
+		t.Errorf("Unexpected warnings: %v", output.Warnings)
+	}
+}
+
+func TestGenerateTieredRateLimit_Errors(t *testing.T) {
+	setMockFeatureFetcher(t, minimalFeatureYAML)
+	tiers := []RateLimitTier{{Value: "gold", MaxRate: 10}}
+	tests := []struct {
+		name  string
+		input GenerateTieredRateLimitInput
+		want  string
+	}{
+		{"community edition", GenerateTieredRateLimitInput{Tiers: tiers, Header: "X-Plan", Edition: "ce"}, "Enterprise"},
+		{"no tier selector", GenerateTieredRateLimitInput{Tiers: tiers}, "header or claim"},
+		{"duplicated tier", GenerateTieredRateLimitInput{Tiers: append(tiers, tiers[0]), Header: "X-Plan"}, "defined twice"},
+		{"unknown endpoint", GenerateTieredRateLimitInput{Tiers: tiers, Header: "X-Plan", Config: `{"version": 3, "endpoints": []}`, Endpoints: []string{"/x"}}, "was found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := GenerateTieredRateLimit(context.Background(), nil, tt.input)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected an error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestGenerateTieredRateLimit_HeaderSpoofing(t *testing.T) {
+	_, output, err := GenerateTieredRateLimit(context.Background(), nil, GenerateTieredRateLimitInput{
+		Tiers:  []RateLimitTier{{Value: "gold", MaxRate: 10}},
+		Header: "X-Plan",
+	})
+	if err != nil {
+		t.Fatalf("GenerateTieredRateLimit() error = %v", err)
+	}
+	if len(output.Warnings) != 1 || !strings.Contains(output.Warnings[0], "choose their own tier") {
+		t.Errorf("Expected a spoofing warning, got %v", output.Warnings)
+	}
+}
@@ -0,0 +1,314 @@
+package tools
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	// TraceEnv enables the trace recorder: "1" writes to the data directory, any other value is a file path
+	TraceEnv  = "KRAKEND_MCP_TRACE"
+	tracesDir = "traces"
+
+	maxReplayDifferences = 10
+)
+
+// TraceEntry is a recorded tool call. Secrets in arguments and results are redacted.
+type TraceEntry struct {
+	Seq           int             `json:"seq"`
+	Time          time.Time       `json:"time"`
+	ServerVersion string          `json:"server_version"`
+	Tool          string          `json:"tool"`
+	Arguments     json.RawMessage `json:"arguments"`
+	Result        json.RawMessage `json:"result,omitempty"` // Structured output
+	IsError       bool            `json:"is_error,omitempty"`
+	Error         string          `json:"error,omitempty"`
+	DurationMs    int64           `json:"duration_ms"`
+}
+
+// TracePath returns the session file the recorder writes to, or "" when tracing is disabled
+func TracePath() string {
+	v := os.Getenv(TraceEnv)
+	switch strings.ToLower(v) {
+	case "", "0", "false", "no":
+		return ""
+	case "1", "true", "yes":
+		name := fmt.Sprintf("session-%s-%d.jsonl", time.Now().Format("20060102-150405"), os.Getpid())
+		return filepath.Join(dataDir, tracesDir, name)
+	}
+	return v
+}
+
+// traceRecorder appends tool calls to a session file
+type traceRecorder struct {
+	mu      sync.Mutex
+	path    string
+	version string
+	seq     int
+}
+
+func (r *traceRecorder) record(entry TraceEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.seq++
+	entry.Seq = r.seq
+	entry.ServerVersion = r.version
+
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o700); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(r.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return json.NewEncoder(f).Encode(entry)
+}
+
+// NewTraceMiddleware records every tool call (redacted) to the session file at path
+func NewTraceMiddleware(path, version string) mcp.Middleware {
+	recorder := &traceRecorder{path: path, version: version}
+
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			if method != "tools/call" {
+				return next(ctx, method, req)
+			}
+			params, ok := req.GetParams().(*mcp.CallToolParamsRaw)
+			if !ok {
+				return next(ctx, method, req)
+			}
+
+			start := time.Now()
+			result, err := next(ctx, method, req)
+
+			entry := TraceEntry{
+				Time:       start,
+				Tool:       params.Name,
+				Arguments:  RedactJSON(params.Arguments),
+				DurationMs: time.Since(start).Milliseconds(),
+			}
+			if err != nil {
+				entry.IsError = true
+				entry.Error = err.Error()
+			} else if res, ok := result.(*mcp.CallToolResult); ok {
+				entry.IsError = res.IsError
+				if res.IsError {
+					entry.Error = toolResultText(res)
+				} else if res.StructuredContent != nil {
+					if data, err := json.Marshal(res.StructuredContent); err == nil {
+						entry.Result = RedactJSON(data)
+					}
+				}
+			}
+
+			if err := recorder.record(entry); err != nil {
+				// Tracing must never break a tool call
+				fmt.Fprintf(os.Stderr, "Warning: could not record trace: %v\n", err)
+			}
+			return result, err
+		}
+	}
+}
+
+// RedactJSON redacts secrets in a JSON document, including configurations passed inline as strings
+func RedactJSON(data json.RawMessage) json.RawMessage {
+	if len(data) == 0 {
+		return data
+	}
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return data
+	}
+	redacted, err := json.Marshal(redactTraceValue(v))
+	if err != nil {
+		return data
+	}
+	return redacted
+}
+
+func redactTraceValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for k, child := range v {
+			if isSecretKey(k) && !isEmptyValue(child) {
+				out[k] = redactValue(child)
+				continue
+			}
+			out[k] = redactTraceValue(child)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, child := range v {
+			out[i] = redactTraceValue(child)
+		}
+		return out
+	case string:
+		// Inline configurations are JSON strings: redact their content too
+		if trimmed := strings.TrimSpace(v); strings.HasPrefix(trimmed, "{") {
+			var inner interface{}
+			if json.Unmarshal([]byte(trimmed), &inner) == nil {
+				if data, err := json.Marshal(redactTraceValue(inner)); err == nil {
+					return string(data)
+				}
+			}
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// toolResultText joins the text contents of a tool result
+func toolResultText(res *mcp.CallToolResult) string {
+	var parts []string
+	for _, c := range res.Content {
+		if text, ok := c.(*mcp.TextContent); ok {
+			parts = append(parts, text.Text)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+// LoadTrace reads a recorded session file
+func LoadTrace(path string) ([]TraceEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open trace: %w", err)
+	}
+	defer f.Close()
+
+	var entries []TraceEntry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(strings.TrimSpace(scanner.Text())) == 0 {
+			continue
+		}
+		var entry TraceEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("invalid trace entry at line %d: %w", line, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
+
+// ReplayResult compares a recorded tool call with its re-execution
+type ReplayResult struct {
+	Seq         int      `json:"seq"`
+	Tool        string   `json:"tool"`
+	Status      string   `json:"status"` // "same", "changed" or "error"
+	Differences []string `json:"differences,omitempty"`
+	Error       string   `json:"error,omitempty"`
+}
+
+// ReplayTrace re-executes every recorded call with call and reports which results changed.
+// Timing and timestamp fields (*_ms, *_at) are ignored in the comparison.
+func ReplayTrace(ctx context.Context, entries []TraceEntry, call func(ctx context.Context, tool string, args map[string]any) (*mcp.CallToolResult, error)) []ReplayResult {
+	results := make([]ReplayResult, 0, len(entries))
+	for _, entry := range entries {
+		r := ReplayResult{Seq: entry.Seq, Tool: entry.Tool}
+
+		args := map[string]any{}
+		if len(entry.Arguments) > 0 {
+			json.Unmarshal(entry.Arguments, &args)
+		}
+		res, err := call(ctx, entry.Tool, args)
+		switch {
+		case err != nil:
+			r.Status, r.Error = "error", err.Error()
+		case res.IsError != entry.IsError:
+			r.Status = "changed"
+			r.Differences = []string{fmt.Sprintf("is_error: %t → %t", entry.IsError, res.IsError)}
+			if res.IsError {
+				r.Error = toolResultText(res)
+			}
+		case res.IsError:
+			r.Status = "same"
+		default:
+			var recorded, replayed interface{}
+			json.Unmarshal(entry.Result, &recorded)
+			if data, err := json.Marshal(res.StructuredContent); err == nil {
+				json.Unmarshal(RedactJSON(data), &replayed)
+			}
+			r.Differences = diffTraceValues("$", recorded, replayed, nil)
+			r.Status = "same"
+			if len(r.Differences) > 0 {
+				r.Status = "changed"
+			}
+		}
+		results = append(results, r)
+	}
+	return results
+}
+
+// diffTraceValues lists the JSON paths where a and b differ
+func diffTraceValues(path string, a, b interface{}, diffs []string) []string {
+	if len(diffs) >= maxReplayDifferences {
+		return diffs
+	}
+	am, aIsMap := a.(map[string]interface{})
+	bm, bIsMap := b.(map[string]interface{})
+	if aIsMap && bIsMap {
+		keys := map[string]struct{}{}
+		for k := range am {
+			keys[k] = struct{}{}
+		}
+		for k := range bm {
+			keys[k] = struct{}{}
+		}
+		sorted := make([]string, 0, len(keys))
+		for k := range keys {
+			if strings.HasSuffix(k, "_ms") || strings.HasSuffix(k, "_at") {
+				continue
+			}
+			sorted = append(sorted, k)
+		}
+		sort.Strings(sorted)
+		for _, k := range sorted {
+			diffs = diffTraceValues(path+"."+k, am[k], bm[k], diffs)
+		}
+		return diffs
+	}
+
+	as, aIsSlice := a.([]interface{})
+	bs, bIsSlice := b.([]interface{})
+	if aIsSlice && bIsSlice && len(as) == len(bs) {
+		for i := range as {
+			diffs = diffTraceValues(fmt.Sprintf("%s[%d]", path, i), as[i], bs[i], diffs)
+		}
+		return diffs
+	}
+
+	if !reflect.DeepEqual(a, b) {
+		diffs = append(diffs, fmt.Sprintf("%s: %s → %s", path, compactJSON(a), compactJSON(b)))
+	}
+	return diffs
+}
+
+// compactJSON renders a value for difference reports, truncating long values
+func compactJSON(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	if len(data) > 80 {
+		return string(data[:77]) + "..."
+	}
+	return string(data)
+}
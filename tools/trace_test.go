@@ -0,0 +1,90 @@
+package tools
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+type traceTestInput struct {
+	Config string `json:"config"`
+}
+
+type traceTestOutput struct {
+	Endpoints int    `json:"endpoints"`
+	Password  string `json:"password"`
+}
+
+func TestTraceMiddleware_RecordsRedactedCalls(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "0.0.1"}, nil)
+	server.AddReceivingMiddleware(NewTraceMiddleware(path, "1.0.0"))
+	mcp.AddTool(server, &mcp.Tool{Name: "count"}, func(ctx context.Context, req *mcp.CallToolRequest, input traceTestInput) (*mcp.CallToolResult, traceTestOutput, error) {
+		return nil, traceTestOutput{Endpoints: strings.Count(input.Config, "endpoint"), Password: "hunter2"}, nil
+	})
+
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	if _, err := server.Connect(context.Background(), serverTransport, nil); err != nil {
+		t.Fatal(err)
+	}
+	client := mcp.NewClient(&mcp.Implementation{Name: "test", Version: "0.0.1"}, nil)
+	session, err := client.Connect(context.Background(), clientTransport, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer session.Close()
+
+	config := `{"endpoints": [{"endpoint": "/a"}], "extra_config": {"auth/signer": {"secret": "s3cr3t"}}}`
+	if _, err := session.CallTool(context.Background(), &mcp.CallToolParams{Name: "count", Arguments: map[string]any{"config": config}}); err != nil {
+		t.Fatalf("CallTool() error = %v", err)
+	}
+
+	entries, err := LoadTrace(path)
+	if err != nil {
+		t.Fatalf("LoadTrace() error = %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 trace entry, got %d", len(entries))
+	}
+	entry := entries[0]
+	if entry.Tool != "count" || entry.Seq != 1 || entry.ServerVersion != "1.0.0" {
+		t.Errorf("Unexpected entry: %+v", entry)
+	}
+	if strings.Contains(string(entry.Arguments), "s3cr3t") || strings.Contains(string(entry.Result), "hunter2") {
+		t.Errorf("Expected secrets to be redacted, got %s / %s", entry.Arguments, entry.Result)
+	}
+
+	// Replaying against the same implementation reports no change; a different output is reported
+	same := ReplayTrace(context.Background(), entries, func(ctx context.Context, tool string, args map[string]any) (*mcp.CallToolResult, error) {
+		return session.CallTool(ctx, &mcp.CallToolParams{Name: tool, Arguments: args})
+	})
+	if len(same) != 1 || same[0].Status != "same" {
+		t.Errorf("Expected unchanged replay, got %+v", same)
+	}
+
+	changed := ReplayTrace(context.Background(), entries, func(ctx context.Context, tool string, args map[string]any) (*mcp.CallToolResult, error) {
+		return &mcp.CallToolResult{StructuredContent: map[string]any{"endpoints": 5, "password": "x"}}, nil
+	})
+	if len(changed) != 1 || changed[0].Status != "changed" || len(changed[0].Differences) != 1 {
+		t.Errorf("Expected one difference, got %+v", changed)
+	}
+}
+
+func TestTracePath(t *testing.T) {
+	t.Setenv(TraceEnv, "")
+	if TracePath() != "" {
+		t.Error("Expected tracing to be disabled by default")
+	}
+	t.Setenv(TraceEnv, "1")
+	if p := TracePath(); !strings.HasPrefix(p, filepath.Join(dataDir, tracesDir)) {
+		t.Errorf("Expected trace in data directory, got %s", p)
+	}
+	t.Setenv(TraceEnv, "/tmp/custom.jsonl")
+	if TracePath() != "/tmp/custom.jsonl" {
+		t.Errorf("Expected custom trace path, got %s", TracePath())
+	}
+}
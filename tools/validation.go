@@ -1,6 +1,9 @@
 package tools
 
 import (
+	"time"
+
+	"github.com/krakend/mcp-server/internal/runtime"
 	"github.com/krakend/mcp-server/tools/validation"
 )
 
@@ -21,6 +24,7 @@ type (
 // Re-export constants
 const (
 	ValidationGuidance = validation.ValidationGuidance
+	DefaultValidationTimeout = validation.DefaultCommandTimeout
 )
 
 // Re-export functions from validation subpackage
@@ -32,5 +36,25 @@ var (
 	ValidateConfig                = validation.ValidateConfig
 	AuditSecurity                 = validation.AuditSecurity
 	RegisterValidationTools       = validation.RegisterValidationTools
+	SchemaURL                     = validation.SchemaURL
+	DockerImage                   = validation.DockerImage
+	WatchValidationEnvironment    = validation.WatchEnvironment
 )
 
+// ConfigureValidationTimeout bounds every krakend and Docker command run by the validation tools,
+// 0 disables the limit
+func ConfigureValidationTimeout(timeout time.Duration) {
+	validation.CommandTimeout = timeout
+}
+
+// ConfigureStrictValidation sets the default of the strict parameter of validate_config,
+// validate_configs and audit_security: fail rather than fall back to heuristic checks
+func ConfigureStrictValidation(strict bool) {
+	validation.StrictDefault = strict
+}
+
+// ConfigureDockerRegistry sets the registry prefix of the official KrakenD images run by the
+// Docker validation tier, "" keeps KRAKEND_MCP_DOCKER_REGISTRY
+func ConfigureDockerRegistry(registry string) {
+	runtime.ConfigureRegistry(registry)
+}
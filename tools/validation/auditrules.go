@@ -0,0 +1,142 @@
+package validation
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os/exec"
+	"strings"
+)
+
+const (
+	// auditFormat renders the recommendations of krakend audit one per line, tab separated after
+	// auditLinePrefix, instead of the colored text meant for terminals
+	auditFormat     = "{{range .Recommendations}}" + auditLinePrefix + "{{.Rule}}\t{{.Severity}}\t{{.Message}}\n{{end}}"
+	auditLinePrefix = "krakend-audit\t"
+
+	// auditDocsURL lists every rule of krakend audit
+	auditDocsURL = "https://www.krakend.io/docs/configuration/audit/"
+)
+
+// auditRuleGroup describes the rules of krakend audit sharing an ID prefix
+type auditRuleGroup struct {
+	Category    string
+	Remediation string
+	DocsURL     string
+}
+
+// auditRuleGroups are the groups of krakend audit rules, by the prefix of their ID. The longest
+// prefix wins.
+var auditRuleGroups = map[string]auditRuleGroup{
+	"1":   {"authentication", "Protect the endpoints with a stronger authentication method", "https://www.krakend.io/docs/authorization/"},
+	"1.1": {"authentication", "Replace basic authentication and weak JWT algorithms with stronger alternatives", "https://www.krakend.io/docs/authorization/jwt-validation/"},
+	"2":   {"security", "Harden the service settings", "https://www.krakend.io/docs/service-settings/"},
+	"2.1": {"ssl", "Enable TLS, or terminate it in front of KrakenD, and avoid insecure connections to backends", "https://www.krakend.io/docs/service-settings/tls/"},
+	"2.2": {"exposure", "Restrict what clients can see and send: CORS, security headers, forwarded headers and query strings", "https://www.krakend.io/docs/service-settings/security/"},
+	"3":   {"traffic", "Protect the gateway and its backends from excess traffic", "https://www.krakend.io/docs/throttling/"},
+	"3.1": {"traffic", "Add rate limiting, circuit breakers or bot detection", "https://www.krakend.io/docs/throttling/"},
+	"3.3": {"timeouts", "Lower the timeouts of the service and its endpoints", "https://www.krakend.io/docs/throttling/timeouts/"},
+	"4":   {"telemetry", "Enable metrics, tracing and structured logging to monitor the gateway", "https://www.krakend.io/docs/telemetry/"},
+	"5":   {"endpoints", "Declare explicit endpoints with their backends, avoiding catch-all and wildcard routes", "https://www.krakend.io/docs/endpoints/"},
+	"7":   {"exposure", "Disable the debug and echo endpoints in production", "https://www.krakend.io/docs/service-settings/debug-endpoint/"},
+}
+
+// runAudit runs krakend audit with its structured format. Versions without the format flag
+// reject it: the audit then runs again with the default output, parsed as text.
+func runAudit(ctx context.Context, build checkCommand) (string, error) {
+	output, err := runAuditCommand(ctx, build, "-f", auditFormat)
+	if err != nil || !strings.Contains(output, "unknown shorthand flag") {
+		return output, err
+	}
+	return runAuditCommand(ctx, build)
+}
+
+func runAuditCommand(ctx context.Context, build checkCommand, flags ...string) (string, error) {
+	cmd, err := build(flags...)
+	if err != nil {
+		return "", err
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err = runCommand(ctx, cmd)
+	output := stdout.String() + stderr.String()
+	// krakend audit returns non-zero if issues found
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && output != "" {
+		err = nil
+	}
+	return output, err
+}
+
+// parseAudit turns krakend audit output into issues: the structured lines when the format was
+// applied, the lines mentioning a severity otherwise
+func parseAudit(output string) []SecurityIssue {
+	var issues []SecurityIssue
+	structured := false
+	for _, line := range strings.Split(output, "\n") {
+		fields, ok := strings.CutPrefix(strings.TrimRight(line, "\r"), auditLinePrefix)
+		if !ok {
+			continue
+		}
+		structured = true
+		parts := strings.SplitN(fields, "\t", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		issues = append(issues, auditIssue(parts[0], parts[1], parts[2]))
+	}
+	if !structured {
+		return parseAuditOutput(output)
+	}
+	return issues
+}
+
+// auditIssue is the issue of a krakend audit recommendation, documented by its rule group
+func auditIssue(rule, severity, message string) SecurityIssue {
+	rule, message = strings.TrimSpace(rule), strings.TrimSpace(message)
+	issue := SecurityIssue{
+		Rule:        rule,
+		Severity:    strings.ToLower(strings.TrimSpace(severity)),
+		Category:    "security",
+		Title:       message,
+		Description: message,
+		Remediation: "Review KrakenD security documentation",
+		References:  []string{auditDocsURL},
+	}
+	if _, known := sarifSecuritySeverities[issue.Severity]; !known {
+		issue.Severity = parseSeverity(severity)
+	}
+	if group, ok := auditRuleGroupOf(rule); ok {
+		issue.Category = group.Category
+		issue.Remediation = group.Remediation
+		issue.References = []string{group.DocsURL, auditDocsURL}
+	}
+	return issue
+}
+
+// auditRuleGroupOf finds the group of a rule ID by its longest known prefix
+func auditRuleGroupOf(rule string) (auditRuleGroup, bool) {
+	for prefix := rule; prefix != ""; {
+		if group, ok := auditRuleGroups[prefix]; ok {
+			return group, true
+		}
+		i := strings.LastIndex(prefix, ".")
+		if i < 0 {
+			break
+		}
+		prefix = prefix[:i]
+	}
+	return auditRuleGroup{}, false
+}
+
+// auditValid reports whether an audit found no critical or high issue, besides the ones
+// suppressed by the baseline
+func auditValid(issues []SecurityIssue) bool {
+	for _, issue := range issues {
+		if !issue.Suppressed && (issue.Severity == "critical" || issue.Severity == "high") {
+			return false
+		}
+	}
+	return true
+}
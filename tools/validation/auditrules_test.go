@@ -0,0 +1,70 @@
+package validation
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseAudit_Structured(t *testing.T) {
+	output := strings.Join([]string{
+		auditLinePrefix + "2.1.2\tHIGH\tEnable TLS or use a terminator in front of KrakenD.",
+		auditLinePrefix + "3.3.1\tLOW\tSet timeouts to below 3 seconds for improved performance.",
+		auditLinePrefix + "9.9.9\tMEDIUM\tA rule this server does not know.",
+		"some log line mentioning CRITICAL",
+	}, "\n")
+	issues := parseAudit(output)
+
+	if len(issues) != 3 {
+		t.Fatalf("Expected 3 issues, got %+v", issues)
+	}
+	tls := issues[0]
+	if tls.Rule != "2.1.2" || tls.Severity != "high" || tls.Category != "ssl" || tls.Title != "Enable TLS or use a terminator in front of KrakenD." {
+		t.Errorf("Unexpected issue %+v", tls)
+	}
+	if len(tls.References) != 2 || tls.References[0] != "https://www.krakend.io/docs/service-settings/tls/" || tls.References[1] != auditDocsURL {
+		t.Errorf("Unexpected references %v", tls.References)
+	}
+	if issues[1].Category != "timeouts" || issues[1].Severity != "low" {
+		t.Errorf("Unexpected issue %+v", issues[1])
+	}
+	if unknown := issues[2]; unknown.Category != "security" || !slices.Equal(unknown.References, []string{auditDocsURL}) {
+		t.Errorf("Expected the generic documentation for an unknown rule, got %+v", unknown)
+	}
+	if auditValid(issues) {
+		t.Error("Expected a high issue to invalidate the audit")
+	}
+}
+
+func TestParseAudit_FallsBackToText(t *testing.T) {
+	issues := parseAudit("2.1.2\t[HIGH]   \tEnable TLS\nAudit completed")
+	if len(issues) != 1 || issues[0].Rule != "2.1.2" || issues[0].Severity != "high" {
+		t.Errorf("Expected the text output to be parsed, got %+v", issues)
+	}
+}
+
+func TestAuditNativeKrakenD_StructuredFormat(t *testing.T) {
+	withFakeKrakend(t, auditLinePrefix+"1.1.1\tCRITICAL\tImplement more secure alternatives than Basic Auth.", func() {
+		result, err := auditWithNativeKrakenD(context.Background(), `{"version":3,"endpoints":[]}`, "", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.Valid || len(result.Issues) != 1 || result.Issues[0].Rule != "1.1.1" || result.Issues[0].Category != "authentication" {
+			t.Errorf("Unexpected audit %+v", result)
+		}
+	})
+}
+
+func TestRunAudit_RetriesWithoutFormat(t *testing.T) {
+	// An older krakend rejecting the format flag
+	build := fakeKrakenD(t, `case "$*" in *" -f "*) echo "Error: unknown shorthand flag: 'f' in -f"; exit 1;; esac
+printf '2.1.2\t[HIGH]   \tEnable TLS\n'; exit 1`)
+	output, err := runAudit(context.Background(), build)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if issues := parseAudit(output); len(issues) != 1 || issues[0].Rule != "2.1.2" {
+		t.Errorf("Expected the audit to run again without the format flag, got %q", output)
+	}
+}
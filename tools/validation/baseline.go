@@ -0,0 +1,129 @@
+package validation
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// BaselineFileName is the audit baseline looked up next to the configuration and in the
+// working directory
+const BaselineFileName = ".krakend-audit-baseline.json"
+
+// BaselineFinding is an audit finding accepted by the baseline, by rule and location. Findings
+// without a rule ID are identified by their title.
+type BaselineFinding struct {
+	Rule     string `json:"rule,omitempty"`
+	Location string `json:"location,omitempty"`
+	Severity string `json:"severity,omitempty"` // For reviewers, not matched
+	Title    string `json:"title,omitempty"`
+}
+
+// baselineFile is the content of an audit baseline
+type baselineFile struct {
+	Findings []BaselineFinding `json:"findings"`
+}
+
+// BaselineReport tells what the audit baseline suppressed
+type BaselineReport struct {
+	File       string            `json:"file"`
+	Suppressed int               `json:"suppressed"`
+	Fixed      []BaselineFinding `json:"fixed,omitempty"`   // No longer found: they can be removed from the baseline
+	Updated    bool              `json:"updated,omitempty"` // Written by this audit with update_baseline
+}
+
+// matches tells whether a baseline entry accepts an issue
+func (f BaselineFinding) matches(issue SecurityIssue) bool {
+	if f.Rule != issue.Rule || f.Location != issue.Location {
+		return false
+	}
+	return f.Rule != "" || f.Title == issue.Title
+}
+
+// baselinePath returns the baseline to use: the explicit one, or the default file next to the
+// configuration or in the working directory. An empty result means none. The baseline written by
+// update need not exist: it defaults to the file next to the configuration.
+func baselinePath(config, explicit string, update bool) (string, error) {
+	if !update {
+		return findConfigCompanion(config, explicit, BaselineFileName, "baseline file")
+	}
+	if explicit != "" {
+		return explicit, nil
+	}
+	if existing, _ := findConfigCompanion(config, "", BaselineFileName, "baseline file"); existing != "" {
+		return existing, nil
+	}
+	if isFilePath(config) {
+		return filepath.Join(filepath.Dir(config), BaselineFileName), nil
+	}
+	return BaselineFileName, nil
+}
+
+// loadBaseline reads the findings of an audit baseline
+func loadBaseline(file string) ([]BaselineFinding, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read baseline file: %w", err)
+	}
+	var parsed baselineFile
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		return nil, fmt.Errorf("invalid baseline file %s: %w", file, err)
+	}
+	for i, finding := range parsed.Findings {
+		if finding.Rule == "" && finding.Title == "" {
+			return nil, fmt.Errorf("invalid baseline file %s: finding %d has neither rule nor title", file, i)
+		}
+	}
+	return parsed.Findings, nil
+}
+
+// writeBaseline records the issues of an audit as its baseline
+func writeBaseline(file string, issues []SecurityIssue) ([]BaselineFinding, error) {
+	findings := []BaselineFinding{}
+	for _, issue := range issues {
+		finding := BaselineFinding{Rule: issue.Rule, Location: issue.Location, Severity: issue.Severity, Title: issue.Title}
+		if len(findings) > 0 && findings[len(findings)-1] == finding {
+			continue
+		}
+		findings = append(findings, finding)
+	}
+	data, err := json.MarshalIndent(baselineFile{Findings: findings}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(file, append(data, '\n'), 0o644); err != nil {
+		return nil, fmt.Errorf("failed to write baseline file: %w", err)
+	}
+	return findings, nil
+}
+
+// applyBaseline marks the issues of an audit accepted by its baseline as suppressed: they are
+// still reported, but no longer fail the audit
+func applyBaseline(output *AuditSecurityOutput, file string, findings []BaselineFinding) {
+	report := &BaselineReport{File: file}
+	matched := make([]bool, len(findings))
+	for i := range output.Issues {
+		issue := &output.Issues[i]
+		for j, finding := range findings {
+			if finding.matches(*issue) {
+				issue.Suppressed = true
+				matched[j] = true
+			}
+		}
+		if issue.Suppressed {
+			report.Suppressed++
+		}
+	}
+	for j, finding := range findings {
+		if !matched[j] {
+			report.Fixed = append(report.Fixed, finding)
+		}
+	}
+	output.Baseline = report
+	if report.Suppressed == 0 {
+		return
+	}
+	output.Valid = auditValid(output.Issues)
+	output.Summary += fmt.Sprintf(". %d issue(s) suppressed by the baseline %s", report.Suppressed, file)
+}
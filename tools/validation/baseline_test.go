@@ -0,0 +1,100 @@
+package validation
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAuditSecurity_Baseline(t *testing.T) {
+	dir := t.TempDir()
+	config := filepath.Join(dir, "krakend.json")
+	if err := os.WriteFile(config, []byte(`{"version": 3, "endpoints": []}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	legacy := "HIGH: no rate limiting configured\nMEDIUM: CORS policy too broad"
+
+	withFakeKrakend(t, legacy, func() {
+		_, output, err := AuditSecurity(context.Background(), nil, AuditSecurityInput{Config: config, UpdateBaseline: true})
+		if err != nil {
+			t.Fatalf("AuditSecurity() error = %v", err)
+		}
+		baseline := filepath.Join(dir, BaselineFileName)
+		if output.Baseline == nil || output.Baseline.File != baseline || !output.Baseline.Updated || output.Baseline.Suppressed != 2 {
+			t.Fatalf("Expected the baseline written next to the config, got %+v", output.Baseline)
+		}
+		findings, err := loadBaseline(baseline)
+		if err != nil || len(findings) != 2 || findings[0].Title != "HIGH: no rate limiting configured" {
+			t.Fatalf("Unexpected baseline findings %+v (%v)", findings, err)
+		}
+
+		_, output, err = AuditSecurity(context.Background(), nil, AuditSecurityInput{Config: config, FailOn: "medium", OutputFormat: "sarif"})
+		if err != nil {
+			t.Fatalf("AuditSecurity() error = %v", err)
+		}
+		if !output.Valid || len(output.Issues) != 2 || !output.Issues[0].Suppressed || output.Baseline.Updated {
+			t.Errorf("Expected the baseline issues reported as suppressed, got %+v", output.Issues)
+		}
+		if !output.Gate.Passed || output.Gate.Counts["high"] != 0 {
+			t.Errorf("Suppressed issues must not count for the gate, got %+v", output.Gate)
+		}
+		if results := output.SARIF.Runs[0].Results; len(results) != 2 || len(results[0].Suppressions) != 1 || results[0].Suppressions[0].Kind != "external" {
+			t.Errorf("Expected suppressed SARIF results, got %+v", results)
+		}
+		if !strings.Contains(output.Summary, "2 issue(s) suppressed by the baseline") {
+			t.Errorf("Summary should mention the suppressed issues, got %q", output.Summary)
+		}
+	})
+
+	// A new finding fails the audit, a fixed one is reported
+	withFakeKrakend(t, "HIGH: no rate limiting configured\n[CRITICAL] TLS is disabled", func() {
+		_, output, err := AuditSecurity(context.Background(), nil, AuditSecurityInput{Config: config})
+		if err != nil {
+			t.Fatalf("AuditSecurity() error = %v", err)
+		}
+		if output.Valid || output.Baseline.Suppressed != 1 {
+			t.Errorf("Expected the new critical issue to fail the audit, got %+v %+v", output.Issues, output.Baseline)
+		}
+		if len(output.Baseline.Fixed) != 1 || output.Baseline.Fixed[0].Title != "MEDIUM: CORS policy too broad" {
+			t.Errorf("Expected the CORS finding to be fixed, got %+v", output.Baseline.Fixed)
+		}
+	})
+}
+
+func TestBaselineFindingMatches(t *testing.T) {
+	issue := SecurityIssue{Rule: "2.1.3", Location: "$.endpoints[0]", Title: "TLS is disabled"}
+	tests := []struct {
+		finding BaselineFinding
+		want    bool
+	}{
+		{BaselineFinding{Rule: "2.1.3", Location: "$.endpoints[0]"}, true},
+		{BaselineFinding{Rule: "2.1.3", Location: "$.endpoints[1]"}, false},
+		{BaselineFinding{Rule: "2.1.3"}, false},
+		{BaselineFinding{Title: "TLS is disabled", Location: "$.endpoints[0]"}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.finding.matches(issue); got != tt.want {
+			t.Errorf("%+v matches = %v, want %v", tt.finding, got, tt.want)
+		}
+	}
+	untitled := SecurityIssue{Title: "HIGH: no rate limiting configured"}
+	if !(BaselineFinding{Title: untitled.Title}).matches(untitled) || (BaselineFinding{Title: "other"}).matches(untitled) {
+		t.Error("Findings without a rule should match by title")
+	}
+}
+
+func TestLoadBaseline_Errors(t *testing.T) {
+	dir := t.TempDir()
+	if _, _, err := AuditSecurity(context.Background(), nil, AuditSecurityInput{Config: `{"version": 3}`, BaselineFile: filepath.Join(dir, "missing.json")}); err == nil {
+		t.Error("Expected an error for a missing explicit baseline file")
+	}
+	file := filepath.Join(dir, BaselineFileName)
+	if err := os.WriteFile(file, []byte(`{"findings": [{"location": "$.endpoints[0]"}]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadBaseline(file); err == nil || !strings.Contains(err.Error(), "neither rule nor title") {
+		t.Errorf("Expected an error for a finding without rule nor title, got %v", err)
+	}
+}
@@ -0,0 +1,149 @@
+package validation
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/krakend/mcp-server/internal/pages"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ValidateConfigsInput defines input for validate_configs tool
+type ValidateConfigsInput struct {
+	Paths           []string `json:"paths,omitempty" jsonschema:"Configuration files to validate (optional when glob is set)"`
+	Glob            string   `json:"glob,omitempty" jsonschema:"Glob selecting configuration files, e.g. services/*/krakend.json (optional when paths is set)"`
+	TempDir         string   `json:"temp_dir,omitempty" jsonschema:"Temporary directory for validation (optional)"`
+	MinWarningLevel string   `json:"min_warning_level,omitempty" jsonschema:"Lowest warning level reported: info (default, everything) or warning (actionable issues only)"`
+	IgnoreFile      string   `json:"ignore_file,omitempty" jsonschema:"Exceptions file accepting findings for every file (optional, defaults to .krakend-mcp-ignore next to each config or in the working directory)"`
+	Strict          *bool    `json:"strict,omitempty" jsonschema:"Report a file as failed instead of falling back to heuristic checks when KrakenD cannot validate its version (optional, defaults to the server setting)"`
+	PageSize        int      `json:"page_size,omitempty" jsonschema:"Files per page (optional, pages are otherwise only split to stay under client message limits)"`
+	PageToken       string   `json:"page_token,omitempty" jsonschema:"next_page_token of a previous call, retrieving the following page (the other arguments are then ignored)"`
+}
+
+// FileValidation is the validation result of one file of the batch
+type FileValidation struct {
+	File string `json:"file"`
+	ValidateConfigOutput
+	Error string `json:"error,omitempty"` // The file could not be validated at all
+}
+
+// ValidateConfigsOutput defines output for validate_configs tool
+type ValidateConfigsOutput struct {
+	Files       []FileValidation       `json:"files"`
+	Valid       int                    `json:"valid"`
+	Invalid     int                    `json:"invalid"`
+	Failed      int                    `json:"failed"`  // Files that could not be validated
+	Methods     map[string]int         `json:"methods"` // Files per validation method
+	Environment *ValidationEnvironment `json:"environment"`
+	Summary     string                 `json:"summary"`
+	Page        *pages.Info            `json:"page,omitempty"` // Files are paginated, the counts cover the whole batch
+}
+
+// ValidateConfigs validates every configuration file selected by paths and glob, sharing one
+// environment detection. Files are validated in order: explicit paths first, then glob matches.
+func ValidateConfigs(ctx context.Context, req *mcp.CallToolRequest, input ValidateConfigsInput) (*mcp.CallToolResult, ValidateConfigsOutput, error) {
+	if input.PageToken != "" {
+		output, files, page, err := pages.Continue[ValidateConfigsOutput, FileValidation](pages.Session(req), "validate_configs", input.PageToken)
+		if err != nil {
+			return nil, ValidateConfigsOutput{}, err
+		}
+		output.Files, output.Page = files, page
+		return nil, output, nil
+	}
+	files, err := batchFiles(input.Paths, input.Glob)
+	if err != nil {
+		return nil, ValidateConfigsOutput{}, err
+	}
+
+	env := DetectEnvironment()
+	output := ValidateConfigsOutput{
+		Files:       make([]FileValidation, 0, len(files)),
+		Methods:     map[string]int{},
+		Environment: env,
+	}
+	for _, file := range files {
+		if err := ctx.Err(); err != nil {
+			return nil, ValidateConfigsOutput{}, err
+		}
+		entry := FileValidation{File: file}
+		_, result, err := validateConfigIn(ctx, req, ValidateConfigInput{
+			Config:          batchConfigPath(file),
+			TempDir:         input.TempDir,
+			MinWarningLevel: input.MinWarningLevel,
+			IgnoreFile:      input.IgnoreFile,
+			Strict:          input.Strict,
+		}, env)
+		switch {
+		case err != nil:
+			entry.Error = err.Error()
+			output.Failed++
+		case result.Valid:
+			output.Valid++
+		default:
+			output.Invalid++
+		}
+		if err == nil {
+			result.Environment = nil // Reported once for the batch
+			entry.ValidateConfigOutput = result
+			output.Methods[ExecutionMethod(result.Method)]++
+		}
+		output.Files = append(output.Files, entry)
+	}
+
+	output.Summary = fmt.Sprintf("%d of %d configuration files are valid", output.Valid, len(files))
+	if output.Invalid > 0 {
+		output.Summary += fmt.Sprintf(", %d with errors", output.Invalid)
+	}
+	if output.Failed > 0 {
+		output.Summary += fmt.Sprintf(", %d could not be validated", output.Failed)
+	}
+	output.Files, output.Page = pages.Paginate(pages.Session(req), "validate_configs", output, output.Files, input.PageSize)
+	return nil, output, nil
+}
+
+// batchFiles lists the files selected by paths and glob, without duplicates
+func batchFiles(paths []string, glob string) ([]string, error) {
+	if len(paths) == 0 && glob == "" {
+		return nil, fmt.Errorf("paths or glob is required")
+	}
+	files := append([]string{}, paths...)
+	if glob != "" {
+		matches, err := filepath.Glob(glob)
+		if err != nil {
+			return nil, fmt.Errorf("invalid glob %q: %w", glob, err)
+		}
+		sort.Strings(matches)
+		files = append(files, matches...)
+	}
+
+	seen := map[string]bool{}
+	result := make([]string, 0, len(files))
+	for _, file := range files {
+		key := filepath.Clean(file)
+		if abs, err := filepath.Abs(file); err == nil {
+			key = abs
+		}
+		if file == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		result = append(result, file)
+	}
+	if len(result) == 0 {
+		return nil, fmt.Errorf("no configuration files match %q", glob)
+	}
+	return result, nil
+}
+
+// batchConfigPath makes sure validate_config reads file as a path, whatever its name
+func batchConfigPath(file string) string {
+	if isFilePath(file) {
+		return file
+	}
+	if abs, err := filepath.Abs(file); err == nil {
+		return abs
+	}
+	return "./" + file
+}
@@ -0,0 +1,77 @@
+package validation
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestValidateConfigs(t *testing.T) {
+	orig := SchemaFetcher
+	SchemaFetcher = func(ctx context.Context, url string) ([]byte, error) { return nil, errors.New("offline") }
+	t.Cleanup(func() { SchemaFetcher = orig })
+
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"a/krakend.json": `{"version":3,"endpoints":[]}`,
+		"b/krakend.json": `{"version":3,`,
+		"c/krakend.tmpl": `{"version":3,"endpoints":[]}`,
+	} {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	_, output, err := ValidateConfigs(context.Background(), nil, ValidateConfigsInput{
+		Paths: []string{filepath.Join(dir, "c/krakend.tmpl"), filepath.Join(dir, "a/krakend.json")},
+		Glob:  filepath.Join(dir, "*/krakend.json"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Explicit paths first, then glob matches, without duplicates
+	if len(output.Files) != 3 || output.Files[0].File != filepath.Join(dir, "c/krakend.tmpl") || output.Files[2].File != filepath.Join(dir, "b/krakend.json") {
+		t.Fatalf("Unexpected files: %+v", output.Files)
+	}
+	if output.Valid != 2 || output.Invalid != 1 || output.Failed != 0 {
+		t.Errorf("Unexpected totals: %+v", output)
+	}
+	if output.Files[2].Method != "syntax" || output.Methods["syntax"] != 1 {
+		t.Errorf("Expected a syntax error in b, got %+v", output.Files[2].ValidationResult)
+	}
+	if output.Environment == nil || output.Files[0].Environment != nil {
+		t.Error("Expected the environment once for the batch")
+	}
+	if output.Summary != "2 of 3 configuration files are valid, 1 with errors" {
+		t.Errorf("Unexpected summary: %q", output.Summary)
+	}
+
+	_, first, err := ValidateConfigs(context.Background(), nil, ValidateConfigsInput{Glob: filepath.Join(dir, "*/krakend.*"), PageSize: 2})
+	if err != nil || len(first.Files) != 2 || first.Page == nil || first.Page.NextToken == "" || first.Valid != 2 {
+		t.Fatalf("Expected a first page of 2 files with the totals of the batch, got %+v, %v", first, err)
+	}
+	_, next, err := ValidateConfigs(context.Background(), nil, ValidateConfigsInput{PageToken: first.Page.NextToken})
+	if err != nil || len(next.Files) != 1 || next.Page.Offset != 2 || next.Page.NextToken != "" || next.Summary != first.Summary {
+		t.Errorf("Expected the last file, got %+v, %v", next, err)
+	}
+
+	_, output, err = ValidateConfigs(context.Background(), nil, ValidateConfigsInput{Paths: []string{filepath.Join(dir, "a/krakend.json")}, IgnoreFile: filepath.Join(dir, "missing")})
+	if err != nil || output.Failed != 1 || output.Files[0].Error == "" {
+		t.Errorf("Expected the file to fail, got %+v, %v", output, err)
+	}
+}
+
+func TestValidateConfigs_NoFiles(t *testing.T) {
+	if _, _, err := ValidateConfigs(context.Background(), nil, ValidateConfigsInput{}); err == nil {
+		t.Error("Expected an error without paths nor glob")
+	}
+	if _, _, err := ValidateConfigs(context.Background(), nil, ValidateConfigsInput{Glob: filepath.Join(t.TempDir(), "*.json")}); err == nil {
+		t.Error("Expected an error when the glob matches nothing")
+	}
+}
@@ -0,0 +1,403 @@
+package validation
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// celToken kinds
+const (
+	celEOF = iota
+	celIdent
+	celNumber
+	celString
+	celPunct
+)
+
+// celToken is a lexical token of a CEL expression
+type celToken struct {
+	kind int
+	text string
+	pos  int // Byte offset in the expression
+}
+
+// celReserved are the words CEL reserves and rejects as identifiers
+var celReserved = map[string]bool{
+	"as": true, "break": true, "const": true, "continue": true, "else": true, "for": true,
+	"function": true, "if": true, "import": true, "let": true, "loop": true, "package": true,
+	"namespace": true, "return": true, "var": true, "void": true,
+}
+
+// celOperators are the punctuation tokens, longest first
+var celOperators = []string{"&&", "||", "==", "!=", "<=", ">=", "(", ")", "[", "]", "{", "}", ".", ",", ":", "?", "!", "-", "+", "*", "/", "%", "<", ">"}
+
+// lexCEL splits a CEL expression into tokens
+func lexCEL(expr string) ([]celToken, error) {
+	var tokens []celToken
+	for i := 0; i < len(expr); {
+		c := expr[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case strings.HasPrefix(expr[i:], "//"):
+			for i < len(expr) && expr[i] != '\n' {
+				i++
+			}
+		case isCELStringStart(expr[i:]):
+			end, err := lexCELString(expr, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, celToken{celString, expr[i:end], i})
+			i = end
+		case c == '_' || unicode.IsLetter(rune(c)):
+			start := i
+			for i < len(expr) && (expr[i] == '_' || unicode.IsLetter(rune(expr[i])) || unicode.IsDigit(rune(expr[i]))) {
+				i++
+			}
+			tokens = append(tokens, celToken{celIdent, expr[start:i], start})
+		case unicode.IsDigit(rune(c)) || (c == '.' && i+1 < len(expr) && unicode.IsDigit(rune(expr[i+1]))):
+			end, err := lexCELNumber(expr, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, celToken{celNumber, expr[i:end], i})
+			i = end
+		default:
+			matched := false
+			for _, op := range celOperators {
+				if strings.HasPrefix(expr[i:], op) {
+					tokens = append(tokens, celToken{celPunct, op, i})
+					i += len(op)
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, fmt.Errorf("position %d: unexpected character %q", i, c)
+			}
+		}
+	}
+	return append(tokens, celToken{celEOF, "", len(expr)}), nil
+}
+
+// isCELStringStart reports whether a string literal, with its optional r/b prefixes, starts here
+func isCELStringStart(s string) bool {
+	for n := 0; n < 2 && len(s) > 0 && strings.ContainsRune("rRbB", rune(s[0])); n++ {
+		s = s[1:]
+	}
+	return len(s) > 0 && (s[0] == '"' || s[0] == '\'')
+}
+
+// lexCELString returns the end of the string literal starting at start
+func lexCELString(expr string, start int) (int, error) {
+	i, raw := start, false
+	for expr[i] != '"' && expr[i] != '\'' {
+		raw = raw || expr[i] == 'r' || expr[i] == 'R'
+		i++
+	}
+	quote := expr[i : i+1]
+	if strings.HasPrefix(expr[i:], strings.Repeat(quote, 3)) {
+		quote = strings.Repeat(quote, 3)
+	}
+	for i += len(quote); i < len(expr); i++ {
+		switch {
+		case strings.HasPrefix(expr[i:], quote):
+			return i + len(quote), nil
+		case expr[i] == '\n' && len(quote) == 1:
+			return 0, fmt.Errorf("position %d: newline in string literal", i)
+		case expr[i] == '\\' && !raw:
+			if i+1 >= len(expr) || !strings.ContainsRune(`abfnrtv\'"?`+"`"+`xuU01234567`, rune(expr[i+1])) {
+				return 0, fmt.Errorf("position %d: invalid escape sequence in string literal", i)
+			}
+			i++
+		}
+	}
+	return 0, fmt.Errorf("position %d: unterminated string literal", start)
+}
+
+// lexCELNumber returns the end of the int, uint or double literal starting at start
+func lexCELNumber(expr string, start int) (int, error) {
+	i := start
+	digits := func() {
+		for i < len(expr) && unicode.IsDigit(rune(expr[i])) {
+			i++
+		}
+	}
+	if strings.HasPrefix(expr[i:], "0x") || strings.HasPrefix(expr[i:], "0X") {
+		i += 2
+		hexStart := i
+		for i < len(expr) && strings.ContainsRune("0123456789abcdefABCDEF", rune(expr[i])) {
+			i++
+		}
+		if i == hexStart {
+			return 0, fmt.Errorf("position %d: invalid hex literal", start)
+		}
+	} else {
+		digits()
+		float := false
+		if i+1 < len(expr) && expr[i] == '.' && unicode.IsDigit(rune(expr[i+1])) {
+			i++
+			digits()
+			float = true
+		}
+		if i < len(expr) && (expr[i] == 'e' || expr[i] == 'E') {
+			i++
+			if i < len(expr) && (expr[i] == '+' || expr[i] == '-') {
+				i++
+			}
+			expStart := i
+			digits()
+			if i == expStart {
+				return 0, fmt.Errorf("position %d: invalid exponent in number literal", start)
+			}
+			float = true
+		}
+		if float {
+			return i, nil
+		}
+	}
+	if i < len(expr) && (expr[i] == 'u' || expr[i] == 'U') {
+		i++
+	}
+	return i, nil
+}
+
+// celParser is a recursive descent parser of the CEL grammar. It only checks the syntax:
+// variables and functions are resolved by KrakenD at runtime.
+type celParser struct {
+	tokens []celToken
+	pos    int
+}
+
+// parseCEL reports the syntax errors of a CEL expression
+func parseCEL(expr string) error {
+	if strings.TrimSpace(expr) == "" {
+		return fmt.Errorf("empty expression")
+	}
+	tokens, err := lexCEL(expr)
+	if err != nil {
+		return err
+	}
+	p := &celParser{tokens: tokens}
+	if err := p.expr(); err != nil {
+		return err
+	}
+	if tok := p.peek(); tok.kind != celEOF {
+		return p.unexpected(tok, "end of expression")
+	}
+	return nil
+}
+
+func (p *celParser) peek() celToken {
+	return p.tokens[p.pos]
+}
+
+// accept consumes the next token when it is the given punctuation
+func (p *celParser) accept(punct string) bool {
+	if tok := p.peek(); tok.kind == celPunct && tok.text == punct {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *celParser) expect(punct string) error {
+	if !p.accept(punct) {
+		return p.unexpected(p.peek(), fmt.Sprintf("%q", punct))
+	}
+	return nil
+}
+
+func (p *celParser) unexpected(tok celToken, expected string) error {
+	if tok.kind == celEOF {
+		return fmt.Errorf("position %d: unexpected end of expression, expected %s", tok.pos, expected)
+	}
+	return fmt.Errorf("position %d: unexpected %q, expected %s", tok.pos, tok.text, expected)
+}
+
+// expr = or ["?" or ":" expr]
+func (p *celParser) expr() error {
+	if err := p.binary(0); err != nil {
+		return err
+	}
+	if !p.accept("?") {
+		return nil
+	}
+	if err := p.binary(0); err != nil {
+		return err
+	}
+	if err := p.expect(":"); err != nil {
+		return err
+	}
+	return p.expr()
+}
+
+// celPrecedence lists the binary operators from lowest to highest precedence
+var celPrecedence = [][]string{
+	{"||"},
+	{"&&"},
+	{"<", "<=", ">", ">=", "==", "!=", "in"},
+	{"+", "-"},
+	{"*", "/", "%"},
+}
+
+// binary parses the operators of a precedence level and the levels above it
+func (p *celParser) binary(level int) error {
+	if level == len(celPrecedence) {
+		return p.unary()
+	}
+	if err := p.binary(level + 1); err != nil {
+		return err
+	}
+	for {
+		tok := p.peek()
+		isOp := (tok.kind == celPunct || (tok.kind == celIdent && tok.text == "in")) && slices.Contains(celPrecedence[level], tok.text)
+		if !isOp {
+			return nil
+		}
+		p.pos++
+		if err := p.binary(level + 1); err != nil {
+			return err
+		}
+	}
+}
+
+// unary = member | "!" {"!"} member | "-" {"-"} member
+func (p *celParser) unary() error {
+	for p.accept("!") || p.accept("-") {
+	}
+	return p.member()
+}
+
+// member = primary {"." IDENT ["(" args ")"] | "[" expr "]" | "{" fields "}"}
+func (p *celParser) member() error {
+	if err := p.primary(); err != nil {
+		return err
+	}
+	for {
+		switch {
+		case p.accept("."):
+			if err := p.ident(); err != nil {
+				return err
+			}
+			if p.accept("(") {
+				if err := p.list(")"); err != nil {
+					return err
+				}
+			}
+		case p.accept("["):
+			if err := p.expr(); err != nil {
+				return err
+			}
+			if err := p.expect("]"); err != nil {
+				return err
+			}
+		default:
+			return nil
+		}
+	}
+}
+
+// primary = ["."] IDENT ["(" args ")" | "{" fields "}"] | "(" expr ")" | list | map | literal
+func (p *celParser) primary() error {
+	tok := p.peek()
+	switch {
+	case tok.kind == celNumber || tok.kind == celString:
+		p.pos++
+		return nil
+	case tok.kind == celIdent || (tok.kind == celPunct && tok.text == "."):
+		p.accept(".")
+		if err := p.ident(); err != nil {
+			return err
+		}
+		if p.accept("(") {
+			return p.list(")")
+		}
+		// Message construction with a qualified name: a.b.Message{field: value}
+		next := p.pos
+		for p.tokens[next].kind == celPunct && p.tokens[next].text == "." && p.tokens[next+1].kind == celIdent {
+			next += 2
+		}
+		if p.tokens[next].kind == celPunct && p.tokens[next].text == "{" {
+			p.pos = next + 1
+			return p.fields()
+		}
+		return nil
+	case p.accept("("):
+		if err := p.expr(); err != nil {
+			return err
+		}
+		return p.expect(")")
+	case p.accept("["):
+		return p.list("]")
+	case p.accept("{"):
+		return p.mapEntries()
+	}
+	return p.unexpected(tok, "an expression")
+}
+
+func (p *celParser) ident() error {
+	tok := p.peek()
+	if tok.kind != celIdent {
+		return p.unexpected(tok, "an identifier")
+	}
+	if celReserved[tok.text] {
+		return fmt.Errorf("position %d: %q is a reserved word", tok.pos, tok.text)
+	}
+	p.pos++
+	return nil
+}
+
+// list parses comma separated expressions (trailing comma allowed) up to the closing token
+func (p *celParser) list(closing string) error {
+	for !p.accept(closing) {
+		if err := p.expr(); err != nil {
+			return err
+		}
+		if !p.accept(",") {
+			return p.expect(closing)
+		}
+	}
+	return nil
+}
+
+// mapEntries parses "key: value" pairs up to the closing brace
+func (p *celParser) mapEntries() error {
+	for !p.accept("}") {
+		if err := p.expr(); err != nil {
+			return err
+		}
+		if err := p.expect(":"); err != nil {
+			return err
+		}
+		if err := p.expr(); err != nil {
+			return err
+		}
+		if !p.accept(",") {
+			return p.expect("}")
+		}
+	}
+	return nil
+}
+
+// fields parses the "field: value" initializers of a message up to the closing brace
+func (p *celParser) fields() error {
+	for !p.accept("}") {
+		if err := p.ident(); err != nil {
+			return err
+		}
+		if err := p.expect(":"); err != nil {
+			return err
+		}
+		if err := p.expr(); err != nil {
+			return err
+		}
+		if !p.accept(",") {
+			return p.expect("}")
+		}
+	}
+	return nil
+}
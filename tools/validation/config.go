@@ -1,7 +1,6 @@
 package validation
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
@@ -15,6 +14,9 @@ import (
 	"time"
 
 	"github.com/krakend/mcp-server/internal/features"
+	"github.com/krakend/mcp-server/internal/metrics"
+	"github.com/krakend/mcp-server/internal/runtime"
+	"github.com/krakend/mcp-server/internal/tempfiles"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/santhosh-tekuri/jsonschema/v6"
 )
@@ -24,15 +26,13 @@ const (
 	ValidationGuidance = "IMPORTANT: The errors and warnings listed above are the COMPLETE and AUTHORITATIVE validation results from KrakenD. Do NOT suggest additional fixes based on assumptions, patterns, or intuition. ONLY fix the errors explicitly listed in this output. If you are unsure about correct KrakenD syntax or configuration, use the search_documentation tool to verify against official documentation before making any suggestions."
 )
 
-// buildKrakenDCommand constructs a KrakenD command with FC support if detected
-func buildKrakenDCommand(env *ValidationEnvironment, command string, configFile string) *exec.Cmd {
+// buildKrakenDCommand constructs a KrakenD command with FC support if detected.
+// CE FC compiles the configuration into fcOut when set (see newFCOutDir).
+// Flags are appended after the config file (e.g. the check pass flags).
+func buildKrakenDCommand(env *ValidationEnvironment, command string, configFile string, fcOut string, flags ...string) *exec.Cmd {
 	fc := env.FlexibleConfig
 
-	// Build base arguments with linting for check command
-	args := []string{command, "-c", configFile}
-	if command == "check" {
-		args = append(args, "-l") // Enable linting for detailed error locations
-	}
+	args := append([]string{command, "-c", configFile}, flags...)
 
 	// If FC not detected, use normal command
 	if fc == nil || !fc.Detected {
@@ -66,29 +66,30 @@ func buildKrakenDCommand(env *ValidationEnvironment, command string, configFile
 		cmd.Env = append(cmd.Env, "FC_PARTIALS="+fc.PartialsDir)
 	}
 
-	// Add FC_OUT for debugging
-	cmd.Env = append(cmd.Env, "FC_OUT=out.json")
+	// Compile the configuration out of the project, to return it
+	if fcOut != "" {
+		cmd.Env = append(cmd.Env, "FC_OUT="+filepath.Join(fcOut, fcOutFile))
+	}
 
 	return cmd
 }
 
-// buildDockerKrakenDCommand constructs a Docker KrakenD command with FC support
-func buildDockerKrakenDCommand(env *ValidationEnvironment, command string, configFile string, dockerImage string) *exec.Cmd {
+// buildDockerKrakenDCommand constructs a Docker KrakenD command with FC support.
+// CE FC compiles the configuration into fcOut when set, mounted in the container.
+func buildDockerKrakenDCommand(env *ValidationEnvironment, command string, configFile string, dockerImage string, fcOut string, flags ...string) *exec.Cmd {
 	fc := env.FlexibleConfig
 
-	// Base Docker args
-	dockerArgs := []string{
-		"run", "--rm",
-		"-v", fmt.Sprintf("%s:/etc/krakend", filepath.Dir(configFile)),
-	}
+	dockerImage = containerImage(env, dockerImage)
+
+	// Base Docker args, hardened by the sandbox profile
+	dockerArgs := append([]string{"run", "--rm"}, Sandbox.dockerRunArgs()...)
+	dockerArgs = append(dockerArgs, "-v", fmt.Sprintf("%s:/etc/krakend", filepath.Dir(configFile)))
 
 	// If FC not detected or EE FC, use simple Docker command
 	if fc == nil || !fc.Detected || fc.Type == "ee" {
 		dockerArgs = append(dockerArgs, dockerImage, command, "-c", "/etc/krakend/"+filepath.Base(configFile))
-		if command == "check" {
-			dockerArgs = append(dockerArgs, "-l") // Enable linting for detailed error locations
-		}
-		return exec.Command("docker", dockerArgs...)
+		dockerArgs = append(dockerArgs, flags...)
+		return containerCommand(env, dockerArgs...)
 	}
 
 	// CE FC: add environment variables
@@ -102,15 +103,15 @@ func buildDockerKrakenDCommand(env *ValidationEnvironment, command string, confi
 	if fc.PartialsDir != "" {
 		dockerArgs = append(dockerArgs, "-e", "FC_PARTIALS=/etc/krakend/"+fc.PartialsDir)
 	}
-	dockerArgs = append(dockerArgs, "-e", "FC_OUT=/etc/krakend/out.json")
+	if fcOut != "" {
+		dockerArgs = append(dockerArgs, "-v", fmt.Sprintf("%s:%s", fcOut, fcOutMount), "-e", "FC_OUT="+fcOutMount+"/"+fcOutFile)
+	}
 
 	// Add image and command
 	dockerArgs = append(dockerArgs, dockerImage, command, "-c", "/etc/krakend/"+filepath.Base(configFile))
-	if command == "check" {
-		dockerArgs = append(dockerArgs, "-l") // Enable linting for detailed error locations
-	}
+	dockerArgs = append(dockerArgs, flags...)
 
-	return exec.Command("docker", dockerArgs...)
+	return containerCommand(env, dockerArgs...)
 }
 
 // ValidationResult represents the result of configuration validation
@@ -119,41 +120,126 @@ type ValidationResult struct {
 	Method      string                 `json:"method"`       // "native", "docker", or "schema"
 	Errors      []ValidationError      `json:"errors"`
 	Warnings    []ValidationWarning    `json:"warnings"`
+	Passes      []CheckPass            `json:"passes,omitempty"` // krakend check passes that ran (native and Docker)
 	Summary     string                 `json:"summary"`
 	Guidance    string                 `json:"guidance,omitempty"` // Instructions for LLM to prevent hallucinations
 	Environment *ValidationEnvironment `json:"environment,omitempty"`
+	Exceptions  *ExceptionsReport      `json:"exceptions,omitempty"`      // Findings accepted by the exceptions file
+	Compiled    *CompiledConfig        `json:"compiled_config,omitempty"` // Output of CE Flexible Configuration
 }
 
 // ValidationError represents a validation error with location
 type ValidationError struct {
-	Path    string `json:"path"`
-	Message string `json:"message"`
-	Line    int    `json:"line,omitempty"`
-	Column  int    `json:"column,omitempty"`
-	Code    string `json:"code"`
+	Path    string           `json:"path"`
+	Message string           `json:"message"`
+	Line    int              `json:"line,omitempty"`
+	Column  int              `json:"column,omitempty"`
+	Code    string           `json:"code"`
+	Pass    string           `json:"pass,omitempty"`    // krakend check pass reporting the error: "check" blocks startup, "lint" does not
+	Sources []SourceLocation `json:"sources,omitempty"` // CE Flexible Configuration: templates and partials the error comes from
+	Doc     *ErrorDoc        `json:"doc,omitempty"`     // Documentation explaining the error, when known
 }
 
 // ValidationWarning represents a validation warning
 type ValidationWarning struct {
-	Path    string `json:"path"`
-	Message string `json:"message"`
-	Level   string `json:"level"` // "warning", "info"
+	Code       string `json:"code"`
+	Path       string `json:"path"`
+	Message    string `json:"message"`
+	Level      string `json:"level"`      // "warning", "info"
+	Category   string `json:"category"`   // "config", "environment" or "context"
+	Actionable bool   `json:"actionable"` // The user should act on it
 }
 
 // ValidateConfigInput defines input for validate_config tool
 type ValidateConfigInput struct {
-	Config  string `json:"config" jsonschema:"KrakenD configuration as JSON string or file path"`
-	TempDir string `json:"temp_dir,omitempty" jsonschema:"Temporary directory for validation (optional)"`
+	Config          string `json:"config" jsonschema:"KrakenD configuration as JSON string or file path (.json, .yaml, .yml or .toml)"`
+	ConfigEncoding  string `json:"config_encoding,omitempty" jsonschema:"Encoding of config for large configurations: base64 or gzip+base64 (optional, defaults to a plain JSON string or file path)"`
+	TempDir         string `json:"temp_dir,omitempty" jsonschema:"Temporary directory for validation (optional)"`
+	MinWarningLevel string `json:"min_warning_level,omitempty" jsonschema:"Lowest warning level reported: info (default, everything) or warning (actionable issues only)"`
+	IgnoreFile      string `json:"ignore_file,omitempty" jsonschema:"Exceptions file accepting findings (optional, defaults to .krakend-mcp-ignore next to the config or in the working directory)"`
+	IncludeTimings  bool   `json:"include_timings,omitempty" jsonschema:"Attach where the time went: environment detection, temp files, subprocesses and parsing, per validation tier (optional)"`
+	DockerImage     string `json:"docker_image,omitempty" jsonschema:"Image of the Docker tier, e.g. registry.corp/krakend-ee:2.10 (optional, defaults to the official image of the config version and edition)"`
+	Registry        string `json:"registry,omitempty" jsonschema:"Registry mirroring the official KrakenD images, e.g. registry.corp/mirror: the version and edition are still resolved (optional)"`
+	SaveCompiled    string `json:"save_compiled,omitempty" jsonschema:"File where the configuration compiled by CE Flexible Configuration is written (optional, it is otherwise only returned as compiled_config)"`
+	Strict          *bool  `json:"strict,omitempty" jsonschema:"Fail instead of falling back to a mismatching krakend version, the latest Docker image or JSON Schema validation when KrakenD cannot validate the configuration version (optional, defaults to the server setting)"`
+	OutputFormat    string `json:"output_format,omitempty" jsonschema:"Output format: json (default) or sarif, which also renders errors and warnings as a SARIF 2.1.0 log for GitHub code scanning (optional)"`
 }
 
 // ValidateConfigOutput defines output for validate_config tool
 type ValidateConfigOutput struct {
 	ValidationResult
+	Timings     *TimingReport      `json:"timings,omitempty"`     // With include_timings
+	Provisional *ProvisionalResult `json:"provisional,omitempty"` // Schema verdict computed while KrakenD was checking
+	SARIF       *SarifLog          `json:"sarif,omitempty"`       // With output_format sarif
 }
 
 // ValidateConfig performs complete validation using three-tier fallback
 func ValidateConfig(ctx context.Context, req *mcp.CallToolRequest, input ValidateConfigInput) (*mcp.CallToolResult, ValidateConfigOutput, error) {
-	env := DetectEnvironment()
+	config, err := DecodeConfig(input.Config, input.ConfigEncoding)
+	if err != nil {
+		return nil, ValidateConfigOutput{}, err
+	}
+	input.Config, input.ConfigEncoding = config, ""
+	return validateConfigIn(ctx, req, input, nil)
+}
+
+// images returns the Docker image override of the input
+func (in ValidateConfigInput) images() runtime.ImageOverride {
+	return runtime.ImageOverride{Image: in.DockerImage, Registry: in.Registry}
+}
+
+// validateConfigIn validates a configuration in the given environment, detected when nil
+func validateConfigIn(ctx context.Context, req *mcp.CallToolRequest, input ValidateConfigInput, env *ValidationEnvironment) (*mcp.CallToolResult, ValidateConfigOutput, error) {
+	if err := input.images().Validate(); err != nil {
+		return nil, ValidateConfigOutput{}, err
+	}
+	format, err := parseOutputFormat(input.OutputFormat)
+	if err != nil {
+		return nil, ValidateConfigOutput{}, err
+	}
+	exceptions, err := resolveExceptions(input.Config, input.IgnoreFile)
+	if err != nil {
+		return nil, ValidateConfigOutput{}, err
+	}
+	var sw *stopwatch
+	if input.IncludeTimings {
+		sw = newStopwatch()
+	}
+	res, output, err := validateConfig(ctx, req, input, env, sw)
+	if err == nil && ctx.Err() != nil {
+		// Nobody waits for a cancelled validation, and it may have been cut short
+		return nil, ValidateConfigOutput{}, ctx.Err()
+	}
+	if err == nil {
+		done := sw.stage(StageEmbeddedChecks, "")
+		applyEmbeddedChecks(&output.ValidationResult, input.Config)
+		done()
+		output.Timings = sw.report()
+		if input.SaveCompiled != "" {
+			saveCompiled(&output.ValidationResult, input.SaveCompiled)
+		}
+		output.Warnings = dedupeWarnings(output.Warnings)
+		if exceptions != nil {
+			applyValidationExceptions(&output.ValidationResult, exceptions)
+		}
+		output.Warnings = filterWarnings(output.Warnings, input.MinWarningLevel)
+		attachErrorDocs(&output.ValidationResult)
+		if format == OutputFormatSARIF {
+			output.SARIF = validationSARIF(output.ValidationResult, sarifArtifact(input.Config))
+		}
+		notifyValidation(input.Config, output.ValidationResult)
+	}
+	return res, output, err
+}
+
+// validateConfig runs the version-aware validation fallback chain, recording its stages in sw.
+// env is detected when nil.
+func validateConfig(ctx context.Context, req *mcp.CallToolRequest, input ValidateConfigInput, env *ValidationEnvironment, sw *stopwatch) (*mcp.CallToolResult, ValidateConfigOutput, error) {
+	if env == nil {
+		done := sw.stage(StageEnvDetection, "")
+		env = DetectEnvironment()
+		done()
+	}
 
 	result := ValidationResult{
 		Valid:       false,
@@ -165,7 +251,9 @@ func ValidateConfig(ctx context.Context, req *mcp.CallToolRequest, input Validat
 	// Check if input.Config is a file path and read it
 	configContent := input.Config
 	if isFilePath(input.Config) {
+		done := sw.stage(StageFileRead, input.Config)
 		fileContent, err := os.ReadFile(input.Config)
+		done()
 		if err != nil {
 			result.Method = "file_read"
 			// Provide a clear, specific error message
@@ -188,11 +276,34 @@ func ValidateConfig(ctx context.Context, req *mcp.CallToolRequest, input Validat
 			return nil, ValidateConfigOutput{ValidationResult: result}, nil
 		}
 		configContent = string(fileContent)
+
+		// YAML and TOML configurations go through the same tiers as JSON
+		if format := configFormat(input.Config); format != ConfigFormatJSON {
+			converted, convErr := configToJSON(fileContent, format)
+			if convErr != nil {
+				result.Method = "syntax"
+				result.Errors = append(result.Errors, *convErr)
+				result.Summary = fmt.Sprintf("Configuration has %s syntax errors", strings.ToUpper(format))
+				return nil, ValidateConfigOutput{ValidationResult: result}, nil
+			}
+			configContent = converted
+			result.Warnings = append(result.Warnings, newWarning(WarningConvertedConfig, "",
+				fmt.Sprintf("%s configuration converted to JSON for validation: positions reported by KrakenD refer to the converted JSON, paths still apply", strings.ToUpper(format))))
+		}
 	}
 
+	// CE Flexible Configuration templates are not JSON until KrakenD renders them
+	template := isFilePath(input.Config) && isFlexibleConfigTemplate(env, input.Config)
+
 	// First, validate JSON syntax
+	done := sw.stage(StageParsing, "configuration")
 	var config map[string]interface{}
-	if err := json.Unmarshal([]byte(configContent), &config); err != nil {
+	err := json.Unmarshal([]byte(configContent), &config)
+	done()
+	if template {
+		err = nil
+	}
+	if err != nil {
 		result.Method = "syntax"
 		result.Errors = append(result.Errors, ValidationError{
 			Message: fmt.Sprintf("Invalid JSON: %s", err.Error()),
@@ -205,24 +316,50 @@ func ValidateConfig(ctx context.Context, req *mcp.CallToolRequest, input Validat
 	// Extract target version from config
 	targetVersion := ExtractVersionFromConfig(configContent)
 
-	// Version-aware validation with smart fallback
-
-	// Priority 1: Native KrakenD (if version matches or config uses latest)
-	if env.HasNativeKrakenD {
+	// Version-aware validation with smart fallback, without its heuristic tiers in strict mode
+	strict := strictMode(input.Strict)
+
+	// The cheap schema validation runs concurrently with the authoritative tiers: it is early
+	// feedback when they succeed and the result when they all fail, without running it again.
+	// It stops with the call, and reports no progress once the call returned.
+	var provisional *provisionalRun
+	if (env.HasNativeKrakenD || env.HasDocker) && !template && !strict {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithCancel(ctx)
+		defer cancel()
+		provisional = startProvisional(ctx, req, env, configContent, sw != nil)
+		defer provisional.finish()
+	}
+	var lastErr error
+
+	images := input.images()
+	overridden := images != runtime.ImageOverride{}
+	if overridden && !env.HasDocker {
+		result.Warnings = append(result.Warnings, newWarning(WarningImageOverrideUnused, "",
+			"docker_image and registry only apply to the Docker tier, which is not available"))
+	}
+
+	// Priority 1: Native KrakenD (if version matches or config uses latest), unless a Docker
+	// image was explicitly requested
+	if env.HasNativeKrakenD && !(overridden && env.HasDocker) {
+		done := sw.stage(StageVersionDetection, "krakend version")
 		localVersion, err := GetLocalKrakenDVersion()
+		done()
 		if err == nil {
 			if targetVersion == "latest" || localVersion == targetVersion {
 				// Version matches or config uses latest - use native
-				if nativeResult, err := validateWithNativeKrakenD(configContent, input.TempDir); err == nil {
-					result = *nativeResult
-					return nil, ValidateConfigOutput{ValidationResult: result}, nil
+				tierDone := sw.tryTier("native")
+				nativeResult, err := validateWithNativeKrakenD(ctx, env, configContent, input.TempDir, sw)
+				tierDone(err)
+				if err == nil {
+					result = withPriorWarnings(*nativeResult, result.Warnings)
+					return nil, withProvisional(result, provisional, sw), nil
 				}
+				lastErr = err
 			} else {
 				// Version mismatch - add warning and skip to Docker
-				result.Warnings = append(result.Warnings, ValidationWarning{
-					Message: fmt.Sprintf("Local KrakenD is v%s but config targets v%s. Using Docker for accurate validation.", localVersion, targetVersion),
-					Level:   "info",
-				})
+				result.Warnings = append(result.Warnings, newWarning(WarningVersionMismatch, "",
+					fmt.Sprintf("Local KrakenD is v%s but config targets v%s. Using Docker for accurate validation.", localVersion, targetVersion)))
 			}
 		}
 	}
@@ -230,38 +367,72 @@ func ValidateConfig(ctx context.Context, req *mcp.CallToolRequest, input Validat
 	// Priority 2: Docker with correct version
 	if env.HasDocker {
 		// Try version-specific image
-		if dockerResult, err := validateWithDockerVersion(configContent, input.TempDir, targetVersion); err == nil {
-			result = *dockerResult
-			return nil, ValidateConfigOutput{ValidationResult: result}, nil
+		tierDone := sw.tryTier("docker")
+		dockerResult, err := validateWithDockerVersion(ctx, env, configContent, input.TempDir, targetVersion, images, sw)
+		tierDone(err)
+		if err == nil {
+			result = withPriorWarnings(*dockerResult, result.Warnings)
+			return nil, withProvisional(result, provisional, sw), nil
 		}
-
-		// If version-specific failed, try latest
-		if targetVersion != "latest" {
-			result.Warnings = append(result.Warnings, ValidationWarning{
-				Message: fmt.Sprintf("Docker image for v%s not available, trying latest", targetVersion),
-				Level:   "info",
-			})
-			if dockerResult, err := validateWithDockerVersion(configContent, input.TempDir, "latest"); err == nil {
-				result = *dockerResult
-				return nil, ValidateConfigOutput{ValidationResult: result}, nil
+		lastErr = err
+
+		// If version-specific failed, try latest (an explicit image has no other version)
+		if images.Fixed() {
+			result.Warnings = append(result.Warnings, newWarning(WarningImageUnavailable, "",
+				fmt.Sprintf("Docker image %s not available", images.Image)))
+		} else if targetVersion != "latest" && !strict {
+			result.Warnings = append(result.Warnings, newWarning(WarningImageUnavailable, "",
+				fmt.Sprintf("Docker image for v%s not available, trying latest", targetVersion)))
+			tierDone := sw.tryTier("docker")
+			dockerResult, err := validateWithDockerVersion(ctx, env, configContent, input.TempDir, "latest", images, sw)
+			tierDone(err)
+			if err == nil {
+				result = withPriorWarnings(*dockerResult, result.Warnings)
+				return nil, withProvisional(result, provisional, sw), nil
 			}
 		}
 	}
 
 	// Priority 3: Fallback to native even if version mismatch (with warning)
-	if env.HasNativeKrakenD {
-		if nativeResult, err := validateWithNativeKrakenD(configContent, input.TempDir); err == nil {
-			nativeResult.Warnings = append(nativeResult.Warnings, ValidationWarning{
-				Message: fmt.Sprintf("Config targets v%s but validating with local version (Docker unavailable)", targetVersion),
-				Level:   "warning",
-			})
-			result = *nativeResult
-			return nil, ValidateConfigOutput{ValidationResult: result}, nil
+	if env.HasNativeKrakenD && !strict {
+		tierDone := sw.tryTier("native")
+		nativeResult, err := validateWithNativeKrakenD(ctx, env, configContent, input.TempDir, sw)
+		tierDone(err)
+		if err == nil {
+			nativeResult.Warnings = append(nativeResult.Warnings, newWarning(WarningVersionNotVerified, "",
+				fmt.Sprintf("Config targets v%s but validating with local version (Docker unavailable)", targetVersion)))
+			result = withPriorWarnings(*nativeResult, result.Warnings)
+			return nil, withProvisional(result, provisional, sw), nil
 		}
 	}
 
-	// Priority 4: Go-based schema validation (last resort)
-	schemaResult, err := validateWithSchema(configContent)
+	if strict {
+		return nil, ValidateConfigOutput{}, strictError(env, targetVersion, lastErr)
+	}
+
+	if template {
+		result.Method = "schema"
+		result.Errors = append(result.Errors, ValidationError{
+			Message: "Flexible Configuration templates can only be validated once rendered by KrakenD, and neither krakend nor Docker could render them",
+			Code:    "FC_TEMPLATE_NOT_RENDERED",
+			Path:    input.Config,
+		})
+		result.Summary = "Validation failed (templates could not be rendered without KrakenD or Docker)"
+		return nil, ValidateConfigOutput{ValidationResult: result}, nil
+	}
+
+	// Priority 4: Go-based schema validation (last resort), already running when KrakenD or Docker were tried
+	var schemaResult *ValidationResult
+	if provisional != nil {
+		schemaResult, err = provisional.wait(ctx)
+		if provisional.ready() {
+			sw.merge(provisional.sw, tierOutcome(err), provisional.elapsed)
+		}
+	} else {
+		tierDone := sw.tryTier("schema")
+		schemaResult, err = validateWithSchema(ctx, env, configContent, SchemaFetcher, sw)
+		tierDone(err)
+	}
 	if err != nil {
 		result.Method = "schema"
 		result.Errors = append(result.Errors, ValidationError{
@@ -272,14 +443,12 @@ func ValidateConfig(ctx context.Context, req *mcp.CallToolRequest, input Validat
 		return nil, ValidateConfigOutput{ValidationResult: result}, nil
 	}
 
-	result = *schemaResult
+	result = withPriorWarnings(*schemaResult, result.Warnings)
 	return nil, ValidateConfigOutput{ValidationResult: result}, nil
 }
 
 // validateWithNativeKrakenD validates using native krakend binary
-func validateWithNativeKrakenD(configJSON string, tempDir string) (*ValidationResult, error) {
-	env := DetectEnvironment()
-
+func validateWithNativeKrakenD(ctx context.Context, env *ValidationEnvironment, configJSON string, tempDir string, sw *stopwatch) (*ValidationResult, error) {
 	var configFile string
 
 	// If Flexible Configuration is detected, use base template directly
@@ -287,34 +456,17 @@ func validateWithNativeKrakenD(configJSON string, tempDir string) (*ValidationRe
 		configFile = env.FlexibleConfig.BaseTemplate
 	} else {
 		// Create temporary file for standard config
-		if tempDir == "" {
-			tempDir = os.TempDir()
-		}
-
-		tmpFile, err := os.CreateTemp(tempDir, "krakend-*.json")
+		ioDone := sw.stage(StageTempFileIO, "")
+		tempFilePath, remove, err := tempfiles.File(tempDir, "krakend-*.json", []byte(configJSON))
 		if err != nil {
-			return nil, fmt.Errorf("failed to create temp file: %w", err)
-		}
-		tempFilePath := tmpFile.Name()
-		defer os.Remove(tempFilePath)
-
-		if _, err := tmpFile.Write([]byte(configJSON)); err != nil {
-			tmpFile.Close()
-			return nil, fmt.Errorf("failed to write temp file: %w", err)
-		}
-		if err := tmpFile.Close(); err != nil {
-			return nil, fmt.Errorf("failed to close temp file: %w", err)
+			return nil, err
 		}
+		defer remove()
+		ioDone()
 
 		configFile = tempFilePath
 	}
 
-	// Run krakend check with FC support
-	cmd := buildKrakenDCommand(env, "check", configFile)
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
-
 	result := &ValidationResult{
 		Method:      "native",
 		Errors:      []ValidationError{},
@@ -324,20 +476,35 @@ func validateWithNativeKrakenD(configJSON string, tempDir string) (*ValidationRe
 	}
 
 	// Add FC info to result if detected
-	if env.FlexibleConfig != nil && env.FlexibleConfig.Detected {
-		result.Warnings = append(result.Warnings, ValidationWarning{
-			Message: env.FlexibleConfig.Explanation,
-			Level:   "info",
-		})
-		for _, implication := range env.FlexibleConfig.Implications {
-			result.Warnings = append(result.Warnings, ValidationWarning{
-				Message: implication,
-				Level:   "info",
-			})
+	result.Warnings = append(result.Warnings, flexibleConfigWarnings(env)...)
+
+	fcOut, cleanup, err := newFCOutDir(env, tempDir)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	build := func(flags ...string) (*exec.Cmd, error) {
+		return buildKrakenDCommand(env, "check", configFile, fcOut, flags...), nil
+	}
+
+	// Render the CE FC templates first: their errors point at the templates, not at a compiled config
+	var passes []CheckPass
+	var renderErrs []ValidationError
+	var render *CheckPass
+	if usesFCOut(env) {
+		render, renderErrs, err = renderFlexibleConfig(ctx, env, build, fcOut)
+		if render != nil {
+			passes = []CheckPass{*render}
 		}
 	}
 
-	err := cmd.Run()
+	// Run the krakend check passes with FC support
+	if err == nil && render == nil {
+		passes, err = runCheckPasses(ctx, build)
+	}
+	sw.passes(passes)
+	result.Compiled = readCompiled(fcOut)
 	if err != nil {
 		result.Valid = false
 
@@ -351,6 +518,12 @@ func validateWithNativeKrakenD(configJSON string, tempDir string) (*ValidationRe
 			return result, fmt.Errorf("krakend binary not found: %w", err)
 		}
 
+		if errors.Is(err, ErrCommandTimeout) {
+			result.Errors = append(result.Errors, timeoutError(err))
+			result.Summary = "KrakenD validation timed out (native)"
+			return result, nil
+		}
+
 		var pathErr *os.PathError
 		if errors.As(err, &pathErr) {
 			result.Errors = append(result.Errors, ValidationError{
@@ -361,45 +534,39 @@ func validateWithNativeKrakenD(configJSON string, tempDir string) (*ValidationRe
 			return result, fmt.Errorf("krakend execution error: %w", err)
 		}
 
-		// Config validation error (krakend ran but found issues)
-		output := stderr.String() + stdout.String()
 		result.Errors = append(result.Errors, ValidationError{
-			Message: output,
+			Message: err.Error(),
 			Code:    "KRAKEND_CHECK_FAILED",
 		})
 		result.Summary = "KrakenD validation failed (native)"
 		return result, nil
 	}
 
-	result.Valid = true
-	result.Summary = "Configuration is valid (validated with native KrakenD)"
+	done := sw.stage(StageParsing, "check output")
+	if render != nil {
+		applyRenderFailure(result, *render, renderErrs, "native")
+	} else {
+		applyCheckPasses(result, passes, "native")
+		mapCompiledErrors(result, env)
+	}
+	done()
+	if result.Valid {
+		result.Summary = "Configuration is valid (validated with native KrakenD)"
+	}
 	return result, nil
 }
 
 // validateWithDockerVersion validates using Docker with specific KrakenD version
-func validateWithDockerVersion(configJSON string, tempDir string, targetVersion string) (*ValidationResult, error) {
-	env := DetectEnvironment()
-
+func validateWithDockerVersion(ctx context.Context, env *ValidationEnvironment, configJSON string, tempDir string, targetVersion string, images runtime.ImageOverride, sw *stopwatch) (*ValidationResult, error) {
 	// Detect if EE features are used (reuses existing edition detection)
 	// Pass nil to use CommonEEFeatures from internal/features
 	isEE := features.DetectEnterpriseFeatures(configJSON, nil)
 
 	// Determine Docker image based on version and edition
-	var dockerImage string
-	if isEE {
-		dockerImage = fmt.Sprintf("krakend/krakend-ee:%s", targetVersion)
-		if targetVersion == "latest" {
-			dockerImage = "krakend/krakend-ee:latest"
-		}
-	} else {
-		dockerImage = fmt.Sprintf("krakend:%s", targetVersion)
-		if targetVersion == "latest" {
-			dockerImage = "krakend:latest"
-		}
-	}
+	dockerImage := images.Resolve(targetVersion, isEE)
 
-	var configFile string
-	var cmd *exec.Cmd
+	var configFile, fcOut string
+	var build checkCommand
 
 	// If Flexible Configuration is detected, mount project directory
 	if env.FlexibleConfig != nil && env.FlexibleConfig.Detected && env.FlexibleConfig.BaseTemplate != "" {
@@ -410,39 +577,43 @@ func validateWithDockerVersion(configJSON string, tempDir string, targetVersion
 		}
 
 		configFile = env.FlexibleConfig.BaseTemplate
-		cmd = buildDockerKrakenDCommand(env, "check", filepath.Join(cwd, configFile), dockerImage)
-	} else {
-		// Create temporary file for standard config
-		if tempDir == "" {
-			tempDir = os.TempDir()
+		if _, err := dockerKrakenDCommand(env, "check", filepath.Join(cwd, configFile), configJSON, dockerImage, ""); err != nil {
+			return nil, err
 		}
-
-		tmpFile, err := os.CreateTemp(tempDir, "krakend-*.json")
+		out, cleanup, err := newFCOutDir(env, tempDir)
 		if err != nil {
-			return nil, fmt.Errorf("failed to create temp file: %w", err)
+			return nil, err
 		}
-		tempFilePath := tmpFile.Name()
-		defer os.Remove(tempFilePath)
-
-		if _, err := tmpFile.Write([]byte(configJSON)); err != nil {
-			tmpFile.Close()
-			return nil, fmt.Errorf("failed to write temp file: %w", err)
+		defer cleanup()
+		fcOut = out
+		build = func(flags ...string) (*exec.Cmd, error) {
+			return dockerKrakenDCommand(env, "check", filepath.Join(cwd, configFile), configJSON, dockerImage, fcOut, flags...)
 		}
-		if err := tmpFile.Close(); err != nil {
-			return nil, fmt.Errorf("failed to close temp file: %w", err)
+	} else if env.DockerMode == runtime.DockerModeStdin {
+		build = func(flags ...string) (*exec.Cmd, error) {
+			return buildDockerStdinCommand(env, "check", configJSON, dockerImage, flags...), nil
 		}
+	} else {
+		// Create temporary file for standard config
+		ioDone := sw.stage(StageTempFileIO, "")
+		tempFilePath, remove, err := tempfiles.File(tempDir, "krakend-*.json", []byte(configJSON))
+		if err != nil {
+			return nil, err
+		}
+		defer remove()
+		ioDone()
 
 		// Run docker with krakend check using version-specific image
-		cmd = exec.Command("docker", "run", "--rm",
-			"-v", fmt.Sprintf("%s:/etc/krakend/krakend.json:ro", tempFilePath),
-			dockerImage,
-			"check", "-c", "/etc/krakend/krakend.json", "-l")
+		build = func(flags ...string) (*exec.Cmd, error) {
+			dockerArgs := append([]string{"run", "--rm"}, Sandbox.dockerRunArgs()...)
+			dockerArgs = append(dockerArgs,
+				"-v", fmt.Sprintf("%s:/etc/krakend/krakend.json:ro", tempFilePath),
+				containerImage(env, dockerImage),
+				"check", "-c", "/etc/krakend/krakend.json")
+			return containerCommand(env, append(dockerArgs, flags...)...), nil
+		}
 	}
 
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
-
 	result := &ValidationResult{
 		Method:      fmt.Sprintf("docker (%s)", dockerImage),
 		Errors:      []ValidationError{},
@@ -452,49 +623,57 @@ func validateWithDockerVersion(configJSON string, tempDir string, targetVersion
 	}
 
 	// Add FC info to result if detected
-	if env.FlexibleConfig != nil && env.FlexibleConfig.Detected {
-		result.Warnings = append(result.Warnings, ValidationWarning{
-			Message: env.FlexibleConfig.Explanation,
-			Level:   "info",
-		})
-		for _, implication := range env.FlexibleConfig.Implications {
-			result.Warnings = append(result.Warnings, ValidationWarning{
-				Message: implication,
-				Level:   "info",
-			})
-		}
-	}
+	result.Warnings = append(result.Warnings, flexibleConfigWarnings(env)...)
 
 	// Add edition info if EE features detected
 	if isEE {
-		result.Warnings = append(result.Warnings, ValidationWarning{
-			Message: fmt.Sprintf("Enterprise Edition features detected, using %s image", dockerImage),
-			Level:   "info",
-		})
+		result.Warnings = append(result.Warnings, newWarning(WarningEnterpriseImage, "",
+			fmt.Sprintf("Enterprise Edition features detected, using %s image", dockerImage)))
+	}
+
+	// Render the CE FC templates first: their errors point at the templates, not at a compiled config
+	var passes []CheckPass
+	var renderErrs []ValidationError
+	var render *CheckPass
+	var err error
+	if usesFCOut(env) && fcOut != "" {
+		render, renderErrs, err = renderFlexibleConfig(ctx, env, build, fcOut)
+		if render != nil {
+			passes = []CheckPass{*render}
+		}
 	}
-
-	err := cmd.Run()
-	if err != nil {
-		// Parse docker/krakend output
-		output := stderr.String() + stdout.String()
-		result.Valid = false
-		result.Errors = append(result.Errors, ValidationError{
-			Message: output,
-			Code:    "KRAKEND_CHECK_FAILED",
-		})
-		result.Summary = fmt.Sprintf("KrakenD validation failed (Docker %s)", dockerImage)
+	if err == nil && render == nil {
+		passes, err = runCheckPasses(ctx, build)
+	}
+	sw.passes(passes)
+	if errors.Is(err, ErrCommandTimeout) {
+		// Another image would most likely hang too
+		result.Errors = append(result.Errors, timeoutError(err))
+		result.Summary = fmt.Sprintf("KrakenD validation timed out (%s)", dockerImage)
 		return result, nil
 	}
+	if err != nil {
+		// Docker could not run the image: let the caller fall back
+		return nil, fmt.Errorf("failed to run %s: %w", dockerImage, err)
+	}
+	result.Compiled = readCompiled(fcOut)
 
-	result.Valid = true
-	result.Summary = fmt.Sprintf("Configuration is valid (validated with %s)", dockerImage)
+	done := sw.stage(StageParsing, "check output")
+	if render != nil {
+		applyRenderFailure(result, *render, renderErrs, "Docker "+dockerImage)
+	} else {
+		applyCheckPasses(result, passes, "Docker "+dockerImage)
+		mapCompiledErrors(result, env)
+	}
+	done()
+	if result.Valid {
+		result.Summary = fmt.Sprintf("Configuration is valid (validated with %s)", dockerImage)
+	}
 	return result, nil
 }
 
-// validateWithSchema validates using version-specific JSON Schema (fallback)
-func validateWithSchema(configJSON string) (*ValidationResult, error) {
-	env := DetectEnvironment()
-
+// validateWithSchema validates using version-specific JSON Schema (fallback), downloaded with fetch
+func validateWithSchema(ctx context.Context, env *ValidationEnvironment, configJSON string, fetch func(ctx context.Context, url string) ([]byte, error), sw *stopwatch) (*ValidationResult, error) {
 	result := &ValidationResult{
 		Method:      "schema",
 		Errors:      []ValidationError{},
@@ -512,57 +691,51 @@ func validateWithSchema(configJSON string) (*ValidationResult, error) {
 	targetVersion := ExtractVersionFromConfig(configJSON)
 
 	// Build schema URL
-	schemaURL := "https://www.krakend.io/schema/krakend.json"
-	if targetVersion != "latest" {
-		schemaURL = fmt.Sprintf("https://www.krakend.io/schema/v%s/krakend.json", targetVersion)
-	}
+	schemaURL := SchemaURL(targetVersion, false)
 
 	// Try to download schema
-	schemaContent, err := downloadSchema(schemaURL)
+	done := sw.stage(StageSchemaFetch, schemaURL)
+	schemaContent, err := fetch(ctx, schemaURL)
+	done()
 	if err != nil {
 		// Fallback to basic validation if schema download fails
-		result.Warnings = append(result.Warnings, ValidationWarning{
-			Message: fmt.Sprintf("Could not download schema from %s, using basic validation", schemaURL),
-			Level:   "warning",
-		})
-		return validateBasicSchema(configJSON)
+		result.Warnings = append(result.Warnings, newWarning(WarningSchemaUnavailable, "", fmt.Sprintf("Could not download schema from %s, using basic validation", schemaURL)))
+		return basicSchemaFallback(configJSON, result.Warnings)
 	}
 
 	// Compile the JSON schema
+	done = sw.stage(StageSchemaCompile, "")
 	compiler := jsonschema.NewCompiler()
 	// Note: Compiler auto-detects draft version from $schema field in the schema
+	// Referenced schemas (e.g. the versioned one the latest schema points to) are fetched the same way
+	compiler.UseLoader(schemaLoader{ctx: ctx, fetch: fetch})
 
 	// Parse schema
 	var schemaDoc interface{}
 	if err := json.Unmarshal(schemaContent, &schemaDoc); err != nil {
-		result.Warnings = append(result.Warnings, ValidationWarning{
-			Message: fmt.Sprintf("Downloaded schema is invalid: %s, using basic validation", err.Error()),
-			Level:   "warning",
-		})
-		return validateBasicSchema(configJSON)
+		result.Warnings = append(result.Warnings, newWarning(WarningSchemaUnavailable, "", fmt.Sprintf("Downloaded schema is invalid: %s, using basic validation", err.Error())))
+		return basicSchemaFallback(configJSON, result.Warnings)
 	}
 
 	// Add schema to compiler
 	if err := compiler.AddResource(schemaURL, schemaDoc); err != nil {
-		result.Warnings = append(result.Warnings, ValidationWarning{
-			Message: fmt.Sprintf("Failed to compile schema: %s, using basic validation", err.Error()),
-			Level:   "warning",
-		})
-		return validateBasicSchema(configJSON)
+		result.Warnings = append(result.Warnings, newWarning(WarningSchemaUnavailable, "", fmt.Sprintf("Failed to compile schema: %s, using basic validation", err.Error())))
+		return basicSchemaFallback(configJSON, result.Warnings)
 	}
 
 	// Compile the schema
 	schema, err := compiler.Compile(schemaURL)
+	done()
 	if err != nil {
-		result.Warnings = append(result.Warnings, ValidationWarning{
-			Message: fmt.Sprintf("Schema compilation error: %s, using basic validation", err.Error()),
-			Level:   "warning",
-		})
-		return validateBasicSchema(configJSON)
+		result.Warnings = append(result.Warnings, newWarning(WarningSchemaUnavailable, "", fmt.Sprintf("Schema compilation error: %s, using basic validation", err.Error())))
+		return basicSchemaFallback(configJSON, result.Warnings)
 	}
 
 	// Validate config against schema
-	if err := schema.Validate(config); err != nil {
+	done = sw.stage(StageSchemaValidation, "")
+	err = schema.Validate(config)
+	done()
+	if err != nil {
 		// Schema validation failed - parse errors
 		result.Valid = false
 
@@ -582,10 +755,8 @@ func validateWithSchema(configJSON string) (*ValidationResult, error) {
 		// Validation passed
 		result.Valid = true
 		result.Summary = fmt.Sprintf("Configuration is valid according to JSON Schema v%s (fallback mode - install KrakenD for runtime validation)", targetVersion)
-		result.Warnings = append(result.Warnings, ValidationWarning{
-			Message: "Using JSON Schema validation. Install KrakenD binary or Docker for comprehensive runtime validation.",
-			Level:   "info",
-		})
+		result.Warnings = append(result.Warnings, newWarning(WarningSchemaOnly, "",
+			"Using JSON Schema validation. Install KrakenD binary or Docker for comprehensive runtime validation."))
 	}
 
 	return result, nil
@@ -619,10 +790,89 @@ func parseSchemaValidationErrors(validationErr *jsonschema.ValidationError) []Va
 	return errors
 }
 
-// downloadSchema downloads JSON schema with timeout
-func downloadSchema(url string) ([]byte, error) {
+// SchemaFetcher is the function used to download JSON schemas. It can be replaced in tests.
+var SchemaFetcher func(ctx context.Context, url string) ([]byte, error) = downloadSchema
+
+// SchemaURL returns the KrakenD JSON Schema URL for a version ("latest" for the unpinned schema).
+// Enterprise schemas include the EE-only namespaces on top of the Community Edition ones.
+func SchemaURL(version string, enterprise bool) string {
+	base := "https://www.krakend.io/schema/"
+	if enterprise {
+		base += "ee/"
+	}
+	if version == "" || version == "latest" {
+		return base + "krakend.json"
+	}
+	return fmt.Sprintf("%sv%s/krakend.json", base, version)
+}
+
+// DockerImage returns the KrakenD Docker image for a version ("latest" for the newest one),
+// taking registry mirrors and pinned digests into account
+func DockerImage(version string, enterprise bool) string {
+	return runtime.DockerImage(version, enterprise)
+}
+
+// SchemaCacheDir is the directory where downloaded schemas are kept for offline use (disabled when empty)
+var SchemaCacheDir string
+
+// schemaCachePath maps a schema URL to its location in SchemaCacheDir
+func schemaCachePath(url string) string {
+	if SchemaCacheDir == "" || !strings.HasPrefix(url, schemaBaseURL) {
+		return ""
+	}
+	return filepath.Join(SchemaCacheDir, filepath.FromSlash(strings.TrimPrefix(url, schemaBaseURL)))
+}
+
+// downloadSchema downloads JSON schema with timeout.
+// Versioned schemas never change and are served from the cache or the embedded bundle when
+// present; the latest schema is refreshed and only read from them when offline.
+func downloadSchema(ctx context.Context, url string) ([]byte, error) {
+	cachePath := schemaCachePath(url)
+	versioned := strings.Contains(url, "/schema/v") || strings.Contains(url, "/schema/ee/v")
+	if versioned {
+		if cachePath != "" {
+			if data, err := os.ReadFile(cachePath); err == nil {
+				metrics.Count(metrics.SchemaCache, "hit")
+				return data, nil
+			}
+		}
+		if data, ok := bundledSchema(url); ok {
+			metrics.Count(metrics.SchemaCache, "bundled")
+			return data, nil
+		}
+	}
+
+	data, err := fetchSchema(ctx, url)
+	if err != nil {
+		if cachePath != "" {
+			if data, cacheErr := os.ReadFile(cachePath); cacheErr == nil {
+				metrics.Count(metrics.SchemaCache, "stale")
+				return data, nil
+			}
+		}
+		if data, ok := bundledSchema(url); ok {
+			metrics.Count(metrics.SchemaCache, "bundled")
+			return data, nil
+		}
+		return nil, err
+	}
+	metrics.Count(metrics.SchemaCache, "miss")
+	if cachePath != "" && json.Valid(data) {
+		if err := os.MkdirAll(filepath.Dir(cachePath), 0o755); err == nil {
+			os.WriteFile(cachePath, data, 0o644)
+		}
+	}
+	return data, nil
+}
+
+// fetchSchema downloads a schema from the KrakenD website
+func fetchSchema(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
 	client := &http.Client{Timeout: 5 * time.Second}
-	resp, err := client.Get(url)
+	resp, err := client.Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -631,7 +881,6 @@ func downloadSchema(url string) ([]byte, error) {
 	if resp.StatusCode != 200 {
 		return nil, fmt.Errorf("schema download failed: HTTP %d", resp.StatusCode)
 	}
-
 	return io.ReadAll(resp.Body)
 }
 
@@ -664,11 +913,7 @@ func validateBasicSchema(configJSON string) (*ValidationResult, error) {
 	// Check version field type
 	if version, ok := config["version"]; ok {
 		if _, isInt := version.(float64); !isInt {
-			result.Warnings = append(result.Warnings, ValidationWarning{
-				Path:    "$.version",
-				Message: "Version should be a number",
-				Level:   "warning",
-			})
+			result.Warnings = append(result.Warnings, newWarning(WarningVersionNotNumber, "$.version", "Version should be a number"))
 		}
 	}
 
@@ -687,10 +932,8 @@ func validateBasicSchema(configJSON string) (*ValidationResult, error) {
 	if len(result.Errors) == 0 {
 		result.Valid = true
 		result.Summary = "Basic schema validation passed (fallback mode - install KrakenD for complete validation)"
-		result.Warnings = append(result.Warnings, ValidationWarning{
-			Message: "Using fallback validation. Install KrakenD binary or Docker for comprehensive validation.",
-			Level:   "info",
-		})
+		result.Warnings = append(result.Warnings, newWarning(WarningBasicOnly, "",
+			"Using fallback validation. Install KrakenD binary or Docker for comprehensive validation."))
 	} else {
 		result.Summary = fmt.Sprintf("Schema validation failed with %d error(s)", len(result.Errors))
 	}
@@ -0,0 +1,138 @@
+package validation
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// RulesFileName is the custom rules file looked up next to the configuration and in the
+// working directory
+const RulesFileName = ".krakend-mcp-rules"
+
+// CustomRule is a house policy checked by audit_security: every node matched by its
+// condition is an issue
+type CustomRule struct {
+	ID          string   `yaml:"id" json:"id"`
+	Condition   string   `yaml:"condition" json:"condition"` // JSONPath matching the offending nodes, e.g. $.endpoints[?(!@.input_headers)]
+	Severity    string   `yaml:"severity" json:"severity"`   // critical, high, medium, low or info
+	Message     string   `yaml:"message" json:"message"`
+	Category    string   `yaml:"category,omitempty" json:"category,omitempty"` // Defaults to policy
+	Remediation string   `yaml:"remediation,omitempty" json:"remediation,omitempty"`
+	References  []string `yaml:"references,omitempty" json:"references,omitempty"`
+}
+
+// rulesFile is the content of a custom rules file, in YAML or JSON
+type rulesFile struct {
+	Rules []CustomRule `yaml:"rules"`
+}
+
+// CustomRulesReport tells which custom rules file an audit evaluated
+type CustomRulesReport struct {
+	File   string `json:"file"`
+	Rules  int    `json:"rules"`
+	Issues int    `json:"issues"` // Before exceptions
+}
+
+// customRules are the compiled rules of a custom rules file
+type customRules struct {
+	file       string
+	rules      []CustomRule
+	conditions []jsonPath
+}
+
+// loadCustomRules reads and compiles a custom rules file
+func loadCustomRules(file string) (*customRules, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rules file: %w", err)
+	}
+	var parsed rulesFile
+	if err := yaml.Unmarshal(data, &parsed); err != nil {
+		return nil, fmt.Errorf("invalid rules file %s: %w", file, err)
+	}
+
+	result := &customRules{file: file}
+	ids := map[string]bool{}
+	for i, rule := range parsed.Rules {
+		if rule.ID == "" {
+			return nil, fmt.Errorf("invalid rules file %s: rule %d has no id", file, i)
+		}
+		if ids[rule.ID] {
+			return nil, fmt.Errorf("invalid rules file %s: rule id %s is used twice", file, rule.ID)
+		}
+		ids[rule.ID] = true
+		if rule.Message == "" {
+			return nil, fmt.Errorf("invalid rules file %s: rule %s has no message", file, rule.ID)
+		}
+		rule.Severity = strings.ToLower(rule.Severity)
+		if _, ok := sarifSecuritySeverities[rule.Severity]; !ok {
+			return nil, fmt.Errorf("invalid rules file %s: rule %s needs a severity among critical, high, medium, low and info", file, rule.ID)
+		}
+		condition, err := compileJSONPath(rule.Condition)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rules file %s: rule %s: %w", file, rule.ID, err)
+		}
+		result.rules = append(result.rules, rule)
+		result.conditions = append(result.conditions, condition)
+	}
+	return result, nil
+}
+
+// resolveCustomRules finds and loads the custom rules applying to a configuration
+func resolveCustomRules(config, explicit string) (*customRules, error) {
+	file, err := findConfigCompanion(config, explicit, RulesFileName, "rules file")
+	if err != nil || file == "" {
+		return nil, err
+	}
+	return loadCustomRules(file)
+}
+
+// issues evaluates the rules against a configuration, one issue per matched node
+func (r *customRules) issues(config map[string]interface{}) []SecurityIssue {
+	issues := []SecurityIssue{}
+	for i, rule := range r.rules {
+		category := rule.Category
+		if category == "" {
+			category = "policy"
+		}
+		remediation := rule.Remediation
+		if remediation == "" {
+			remediation = fmt.Sprintf("Comply with the policy defined in %s", r.file)
+		}
+		for _, match := range r.conditions[i].find(config) {
+			issues = append(issues, SecurityIssue{
+				Rule:        rule.ID,
+				Severity:    rule.Severity,
+				Category:    category,
+				Title:       rule.Message,
+				Description: fmt.Sprintf("%s (custom rule %s of %s)", rule.Message, rule.ID, r.file),
+				Location:    match.Location,
+				Remediation: remediation,
+				References:  rule.References,
+			})
+		}
+	}
+	return issues
+}
+
+// applyCustomRules adds the issues of the custom rules to an audit, alongside the built-in checks
+func applyCustomRules(output *AuditSecurityOutput, configInput string, r *customRules) {
+	if output.Method == "file_read" {
+		return
+	}
+	config, ok := readConfigObject(configInput)
+	if !ok {
+		return
+	}
+	issues := r.issues(config)
+	output.CustomRules = &CustomRulesReport{File: r.file, Rules: len(r.rules), Issues: len(issues)}
+	if len(issues) == 0 {
+		return
+	}
+	output.Issues = append(output.Issues, issues...)
+	output.Valid = output.Valid && auditValid(issues)
+	output.Summary += fmt.Sprintf(". %d issue(s) found by the custom rules of %s", len(issues), r.file)
+}
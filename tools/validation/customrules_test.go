@@ -0,0 +1,118 @@
+package validation
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeRulesFile(t *testing.T, dir, content string) string {
+	t.Helper()
+	file := filepath.Join(dir, RulesFileName)
+	if err := os.WriteFile(file, []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to write rules file: %v", err)
+	}
+	return file
+}
+
+func TestLoadCustomRules(t *testing.T) {
+	file := writeRulesFile(t, t.TempDir(), `rules:
+  - id: endpoint-input-headers
+    severity: MEDIUM
+    message: Every endpoint must set input_headers explicitly
+    condition: $.endpoints[?(!@.input_headers)]
+`)
+	rules, err := loadCustomRules(file)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rules.rules) != 1 || rules.rules[0].Severity != "medium" {
+		t.Errorf("Expected one medium rule, got %+v", rules.rules)
+	}
+
+	invalid := []struct {
+		content string
+		want    string
+	}{
+		{`{"rules": [{"severity": "high", "message": "m", "condition": "$"}]}`, "no id"},
+		{`{"rules": [{"id": "a", "severity": "high", "message": "m", "condition": "$"}, {"id": "a", "severity": "low", "message": "m", "condition": "$"}]}`, "used twice"},
+		{`{"rules": [{"id": "a", "severity": "severe", "message": "m", "condition": "$"}]}`, "severity"},
+		{`{"rules": [{"id": "a", "severity": "high", "condition": "$"}]}`, "no message"},
+		{`{"rules": [{"id": "a", "severity": "high", "message": "m", "condition": "$.endpoints[?("}]}`, "rule a: invalid JSONPath"},
+	}
+	for _, tt := range invalid {
+		file := writeRulesFile(t, t.TempDir(), tt.content)
+		if _, err := loadCustomRules(file); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Expected an error about %q for %s, got %v", tt.want, tt.content, err)
+		}
+	}
+}
+
+func TestAuditSecurity_CustomRules(t *testing.T) {
+	dir := t.TempDir()
+	config := filepath.Join(dir, "krakend.json")
+	content := `{"version": 3, "endpoints": [
+		{"endpoint": "/users", "input_headers": ["Authorization"], "backend": [{"url_pattern": "/users"}]},
+		{"endpoint": "/orders", "backend": [{"url_pattern": "/orders"}]},
+		{"endpoint": "/legacy", "input_headers": ["*"], "backend": [{"url_pattern": "/legacy"}]}
+	]}`
+	if err := os.WriteFile(config, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	rulesFile := writeRulesFile(t, dir, `rules:
+  - id: endpoint-input-headers
+    severity: medium
+    message: Every endpoint must set input_headers explicitly
+    condition: $.endpoints[?(!@.input_headers)]
+    references: [https://www.krakend.io/docs/endpoints/parameter-forwarding/]
+  - id: no-wildcard-headers
+    severity: high
+    category: headers
+    message: Endpoints must not forward every header
+    condition: $.endpoints[?(@.input_headers[*] == '*')]
+`)
+
+	withFakeKrakend(t, "MEDIUM: CORS policy too broad", func() {
+		_, output, err := AuditSecurity(context.Background(), nil, AuditSecurityInput{Config: config})
+		if err != nil {
+			t.Fatalf("AuditSecurity() error = %v", err)
+		}
+		if output.CustomRules == nil || output.CustomRules.File != rulesFile || output.CustomRules.Rules != 2 || output.CustomRules.Issues != 2 {
+			t.Fatalf("Expected the rules next to the config to be evaluated, got %+v", output.CustomRules)
+		}
+		custom := map[string]SecurityIssue{}
+		for _, issue := range output.Issues {
+			custom[issue.Rule] = issue
+		}
+		if issue := custom["endpoint-input-headers"]; issue.Location != "$.endpoints[1]" || issue.Category != "policy" || len(issue.References) != 1 {
+			t.Errorf("Unexpected input_headers issue: %+v", issue)
+		}
+		if issue := custom["no-wildcard-headers"]; issue.Location != "$.endpoints[2]" || issue.Severity != "high" || issue.Category != "headers" {
+			t.Errorf("Unexpected wildcard issue: %+v", issue)
+		}
+		if output.Valid || !strings.Contains(output.Summary, "2 issue(s) found by the custom rules") {
+			t.Errorf("Expected the high custom issue to fail the audit, got %v %q", output.Valid, output.Summary)
+		}
+
+		// Custom findings can be accepted like the built-in ones
+		writeIgnoreFile(t, dir, `exceptions:
+  - rule: no-wildcard-headers
+    path: $.endpoints[2]
+    reason: Legacy proxy, removed next quarter
+    expires: 2099-12-31
+`)
+		_, output, err = AuditSecurity(context.Background(), nil, AuditSecurityInput{Config: config})
+		if err != nil {
+			t.Fatalf("AuditSecurity() error = %v", err)
+		}
+		if !output.Valid || len(output.Exceptions.AcceptedRisks) != 1 {
+			t.Errorf("Expected the wildcard issue to be accepted, got %+v", output.Exceptions)
+		}
+	})
+
+	if _, _, err := AuditSecurity(context.Background(), nil, AuditSecurityInput{Config: config, RulesFile: filepath.Join(dir, "missing.yaml")}); err == nil {
+		t.Error("Expected an error for a missing explicit rules file")
+	}
+}
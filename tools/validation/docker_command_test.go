@@ -1,9 +1,13 @@
 package validation
 
 import (
+	"context"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
+
+	"github.com/krakend/mcp-server/internal/testutil"
 )
 
 func argsContainsPair(args []string, key, val string) bool {
@@ -24,9 +28,9 @@ func argsContainsStr(args []string, s string) bool {
 	return false
 }
 
-func dockerArgsFrom(t *testing.T, env *ValidationEnvironment, command, configFile, image string) []string {
+func dockerArgsFrom(t *testing.T, env *ValidationEnvironment, command, configFile, image string, flags ...string) []string {
 	t.Helper()
-	cmd := buildDockerKrakenDCommand(env, command, configFile, image)
+	cmd := buildDockerKrakenDCommand(env, command, configFile, image, "/tmp/krakend-fc", flags...)
 	if filepath.Base(cmd.Path) != "docker" {
 		t.Fatalf("expected docker binary, got %s", cmd.Path)
 	}
@@ -35,7 +39,7 @@ func dockerArgsFrom(t *testing.T, env *ValidationEnvironment, command, configFil
 
 func TestBuildDockerKrakenDCommandWithImage_NoFC(t *testing.T) {
 	env := &ValidationEnvironment{FlexibleConfig: nil}
-	args := dockerArgsFrom(t, env, "check", "/project/krakend.json", "krakend:2.0")
+	args := dockerArgsFrom(t, env, "check", "/project/krakend.json", "krakend:2.0", checkPassFlags[PassLint]...)
 
 	if args[0] != "run" {
 		t.Errorf("first arg must be 'run', got %q", args[0])
@@ -49,9 +53,9 @@ func TestBuildDockerKrakenDCommandWithImage_NoFC(t *testing.T) {
 	if !argsContainsStr(args, "/etc/krakend/krakend.json") {
 		t.Errorf("expected -c /etc/krakend/krakend.json, args=%v", args)
 	}
-	// check command gets -l for lint
+	// the lint pass gets -l
 	if !argsContainsStr(args, "-l") {
-		t.Errorf("expected -l for check command, args=%v", args)
+		t.Errorf("expected -l for the lint pass, args=%v", args)
 	}
 	// no FC_ env vars
 	for _, a := range args {
@@ -115,8 +119,8 @@ func TestBuildDockerKrakenDCommandWithImage_CEFC_WithSettingsDir(t *testing.T) {
 	if !argsContainsPair(args, "-e", "FC_SETTINGS=/etc/krakend/settings/") {
 		t.Errorf("expected FC_SETTINGS=/etc/krakend/settings/, args=%v", args)
 	}
-	if !argsContainsPair(args, "-e", "FC_OUT=/etc/krakend/out.json") {
-		t.Errorf("expected FC_OUT, args=%v", args)
+	if !argsContainsPair(args, "-e", "FC_OUT=/krakend-out/out.json") || !argsContainsPair(args, "-v", "/tmp/krakend-fc:/krakend-out") {
+		t.Errorf("expected FC_OUT out of the project, args=%v", args)
 	}
 }
 
@@ -144,8 +148,8 @@ func TestBuildDockerKrakenDCommandWithImage_CEFC_WithAllDirs(t *testing.T) {
 	if !argsContainsPair(args, "-e", "FC_PARTIALS=/etc/krakend/partials/") {
 		t.Errorf("expected FC_PARTIALS, args=%v", args)
 	}
-	if !argsContainsPair(args, "-e", "FC_OUT=/etc/krakend/out.json") {
-		t.Errorf("expected FC_OUT, args=%v", args)
+	if !argsContainsPair(args, "-e", "FC_OUT=/krakend-out/out.json") || !argsContainsPair(args, "-v", "/tmp/krakend-fc:/krakend-out") {
+		t.Errorf("expected FC_OUT out of the project, args=%v", args)
 	}
 }
 
@@ -213,3 +217,178 @@ func TestBuildDockerKrakenDCommandWithImage_CEFC_NoSettingsDir_FCEnableBug(t *te
 		t.Errorf("FC_ENABLE=1 must be present for CE FC regardless of SettingsDir; args=%v", args)
 	}
 }
+
+func TestBuildDockerKrakenDCommand_DefaultSandbox(t *testing.T) {
+	saved := Sandbox
+	t.Cleanup(func() { Sandbox = saved })
+	Sandbox = DefaultContainerSandbox
+
+	args := dockerArgsFrom(t, &ValidationEnvironment{}, "check", "/project/krakend.json", "krakend:latest")
+	for _, want := range []string{"--network=none", "--memory=512m", "--cpus=1", "--pids-limit=256", "--read-only", "--tmpfs=/tmp", "--cap-drop=ALL", "--security-opt=no-new-privileges"} {
+		if !argsContainsStr(args, want) {
+			t.Errorf("expected %s in args, args=%v", want, args)
+		}
+	}
+	// Sandbox flags must precede the image, otherwise they are passed to krakend
+	image := -1
+	for i, a := range args {
+		if a == "krakend:latest" {
+			image = i
+		}
+		if a == "--network=none" && image != -1 {
+			t.Errorf("sandbox flags after the image, args=%v", args)
+		}
+	}
+}
+
+func TestSandboxFromEnv(t *testing.T) {
+	t.Setenv(dockerNetworkEnv, "default")
+	t.Setenv(dockerMemoryEnv, "0")
+	t.Setenv(dockerCPUsEnv, "2")
+	t.Setenv(dockerPidsEnv, "invalid")
+	t.Setenv(dockerReadOnlyEnv, "false")
+	t.Setenv(dockerUserEnv, "1000:1000")
+
+	s := sandboxFromEnv()
+	if s.Network != "" || s.Memory != "" || s.CPUs != "2" || s.ReadOnly || s.User != "1000:1000" {
+		t.Errorf("unexpected sandbox %+v", s)
+	}
+	if s.PidsLimit != DefaultContainerSandbox.PidsLimit {
+		t.Errorf("invalid pids limit must keep the default, got %d", s.PidsLimit)
+	}
+
+	args := s.dockerRunArgs()
+	for _, a := range args {
+		if strings.HasPrefix(a, "--network") || strings.HasPrefix(a, "--memory") || a == "--read-only" {
+			t.Errorf("unexpected disabled flag %q", a)
+		}
+	}
+	if !argsContainsStr(args, "--user=1000:1000") {
+		t.Errorf("expected --user flag, args=%v", args)
+	}
+}
+
+func TestBuildDockerStdinCommand(t *testing.T) {
+	cmd := buildDockerStdinCommand(&ValidationEnvironment{}, "check", `{"version": 3}`, "krakend:2.12", "-l")
+	args := cmd.Args[1:]
+
+	if !argsContainsStr(args, "-i") || !argsContainsPair(args, "--entrypoint", "/bin/sh") {
+		t.Errorf("expected interactive shell entrypoint, args=%v", args)
+	}
+	for _, a := range args {
+		if a == "-v" {
+			t.Errorf("stdin mode must not mount local files, args=%v", args)
+		}
+	}
+	if script := args[len(args)-1]; !strings.Contains(script, "krakend check -c /tmp/krakend.json -l") {
+		t.Errorf("unexpected script %q", script)
+	}
+	if cmd.Stdin == nil {
+		t.Error("expected the configuration on stdin")
+	}
+}
+
+func TestDockerKrakenDCommand_StdinModeRejectsFC(t *testing.T) {
+	env := &ValidationEnvironment{
+		DockerMode:     "stdin",
+		FlexibleConfig: &FlexibleConfigInfo{Detected: true, Type: "ce"},
+	}
+	if _, err := dockerKrakenDCommand(env, "audit", "/project/krakend.tmpl", "{}", "krakend:latest", ""); err == nil {
+		t.Error("expected an error for flexible configuration without local mounts")
+	}
+
+	env.FlexibleConfig = nil
+	cmd, err := dockerKrakenDCommand(env, "audit", "/tmp/krakend.json", "{}", "krakend:latest", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if argsContainsStr(cmd.Args, "-v") {
+		t.Errorf("stdin mode must not mount local files, args=%v", cmd.Args)
+	}
+}
+
+func TestBuildDockerKrakenDCommand_Podman(t *testing.T) {
+	env := &ValidationEnvironment{DockerBinary: "podman", ContainerEngine: "podman"}
+	cmd := buildDockerKrakenDCommand(env, "check", "/project/krakend.json", "krakend:2.12", "")
+	if cmd.Args[0] != "podman" || filepath.Base(cmd.Path) != "podman" {
+		t.Fatalf("expected the podman binary, got %v", cmd.Args)
+	}
+	args := cmd.Args[1:]
+	if args[0] != "run" || args[1] != "--security-opt=label=disable" {
+		t.Errorf("expected SELinux labeling disabled for the bind mounts, args=%v", args)
+	}
+	if !argsContainsStr(args, "docker.io/library/krakend:2.12") {
+		t.Errorf("expected a fully qualified image, args=%v", args)
+	}
+
+	// The docker command of podman-docker
+	env.DockerBinary = "docker"
+	stdin := buildDockerStdinCommand(env, "audit", "{}", "krakend/krakend-ee:2.12")
+	if stdin.Args[0] != "docker" || !argsContainsStr(stdin.Args, "docker.io/krakend/krakend-ee:2.12") || !argsContainsStr(stdin.Args, "--security-opt=label=disable") {
+		t.Errorf("unexpected podman-docker command %v", stdin.Args)
+	}
+}
+
+// withFakeDocker runs f with a docker CLI (and no krakend) recording its calls
+func withFakeDocker(t *testing.T, f func(calls *testutil.Calls)) {
+	t.Helper()
+	testutil.Isolate(t)
+	f(testutil.Docker{}.Install(t))
+}
+
+func TestValidateConfig_DockerImageOverride(t *testing.T) {
+	withFakeDocker(t, func(calls *testutil.Calls) {
+		_, output, err := ValidateConfig(context.Background(), nil, ValidateConfigInput{
+			Config:      `{"version":3,"endpoints":[]}`,
+			DockerImage: "registry.corp/krakend-ee:2.10",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !output.Valid || output.Method != "docker (registry.corp/krakend-ee:2.10)" {
+			t.Fatalf("Expected a validation with the requested image, got %+v", output.ValidationResult)
+		}
+
+		_, audit, err := AuditSecurity(context.Background(), nil, AuditSecurityInput{Config: `{"version":3,"endpoints":[]}`, Registry: "registry.corp/mirror/"})
+		if err != nil || audit.Method != "docker (registry.corp/mirror/krakend:latest)" {
+			t.Fatalf("Expected a Docker audit, got %+v, %v", audit, err)
+		}
+		if !calls.Contains("registry.corp/mirror/krakend:latest", "audit") {
+			t.Errorf("Expected the official image from the mirror, got %q", calls.Args())
+		}
+	})
+
+	for _, input := range []ValidateConfigInput{
+		{Config: "{}", DockerImage: "--privileged"},
+		{Config: "{}", DockerImage: "krakend:2.10", Registry: "registry.corp"},
+		{Config: "{}", Registry: "registry.corp/krakend@sha256"},
+	} {
+		if _, _, err := ValidateConfig(context.Background(), nil, input); err == nil {
+			t.Errorf("Expected an error for %+v", input)
+		}
+	}
+}
+
+func TestValidateConfig_GoldenEnterprise(t *testing.T) {
+	withFakeDocker(t, func(calls *testutil.Calls) {
+		_, output, err := ValidateConfig(context.Background(), nil, ValidateConfigInput{Config: testutil.WriteConfig(t, testutil.EnterpriseConfig)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !output.Valid || output.Method != "docker (krakend/krakend-ee:2.12)" {
+			t.Fatalf("Expected a Docker validation with the Enterprise image, got %+v", output.ValidationResult)
+		}
+		if !calls.Contains("krakend/krakend-ee:2.12", "check") {
+			t.Errorf("Expected a check with the Enterprise image, got %q", calls.Args())
+		}
+
+		before := len(calls.Args())
+		_, output, err = ValidateConfig(context.Background(), nil, ValidateConfigInput{Config: testutil.Config(t, testutil.MalformedConfig)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if output.Valid || output.Method != "syntax" || slices.ContainsFunc(calls.Args()[before:], func(args []string) bool { return args[0] == "run" }) {
+			t.Errorf("Malformed JSON must be rejected before running Docker, got %+v", output.ValidationResult)
+		}
+	})
+}
@@ -0,0 +1,195 @@
+package validation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+// embeddedSchemaURL is the location the embedded schemas are compiled under
+const embeddedSchemaURL = "embedded-schema.json"
+
+// embeddedDocument is a document embedded in an extra_config value, with its JSON path
+type embeddedDocument struct {
+	kind  string // "schema" or "cel"
+	path  string
+	value interface{}
+}
+
+// embeddedDocuments returns the documents a namespace embeds in its configuration: JSON
+// Schemas of the payload validators and CEL expressions of the CEL validator and security policies
+func embeddedDocuments(namespace string, value interface{}, path string) []embeddedDocument {
+	var docs []embeddedDocument
+	switch namespace {
+	case "validation/json-schema":
+		docs = append(docs, embeddedDocument{"schema", path, value})
+	case "validation/response-json-schema":
+		if cfg, ok := value.(map[string]interface{}); ok && cfg["schema"] != nil {
+			docs = append(docs, embeddedDocument{"schema", path + ".schema", cfg["schema"]})
+		}
+	case "validation/cel":
+		checks, _ := value.([]interface{})
+		for i, check := range checks {
+			if cfg, ok := check.(map[string]interface{}); ok {
+				docs = append(docs, embeddedDocument{"cel", fmt.Sprintf("%s[%d].check_expr", path, i), cfg["check_expr"]})
+			}
+		}
+	case "security/policies":
+		cfg, _ := value.(map[string]interface{})
+		for _, scope := range []string{"req", "resp", "jwt"} {
+			scopeCfg, _ := cfg[scope].(map[string]interface{})
+			policies, _ := scopeCfg["policies"].([]interface{})
+			for i, policy := range policies {
+				docs = append(docs, embeddedDocument{"cel", fmt.Sprintf("%s.%s.policies[%d]", path, scope, i), policy})
+			}
+		}
+	}
+	return docs
+}
+
+// collectEmbeddedDocuments walks the configuration looking for extra_config namespaces at
+// any level (service, endpoints, backends, async agents)
+func collectEmbeddedDocuments(value interface{}, path string) []embeddedDocument {
+	var docs []embeddedDocument
+	switch v := value.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if extra, ok := v[key].(map[string]interface{}); ok && key == "extra_config" {
+				namespaces := make([]string, 0, len(extra))
+				for namespace := range extra {
+					namespaces = append(namespaces, namespace)
+				}
+				sort.Strings(namespaces)
+				for _, namespace := range namespaces {
+					docs = append(docs, embeddedDocuments(namespace, extra[namespace], path+".extra_config."+namespace)...)
+				}
+				continue
+			}
+			docs = append(docs, collectEmbeddedDocuments(v[key], path+"."+key)...)
+		}
+	case []interface{}:
+		for i, item := range v {
+			docs = append(docs, collectEmbeddedDocuments(item, fmt.Sprintf("%s[%d]", path, i))...)
+		}
+	}
+	return docs
+}
+
+// CompileEmbeddedSchema compiles a JSON Schema as KrakenD does (draft-07 unless $schema says
+// otherwise), which also checks it against its meta-schema
+func CompileEmbeddedSchema(schema interface{}) error {
+	if _, ok := schema.(map[string]interface{}); !ok {
+		if _, isBool := schema.(bool); !isBool {
+			return fmt.Errorf("the schema must be a JSON object")
+		}
+	}
+	compiler := jsonschema.NewCompiler()
+	compiler.DefaultDraft(jsonschema.Draft7)
+	if err := compiler.AddResource(embeddedSchemaURL, schema); err != nil {
+		return err
+	}
+	_, err := compiler.Compile(embeddedSchemaURL)
+	return err
+}
+
+// checkEmbeddedDocuments compiles the JSON Schemas and parses the CEL expressions embedded in
+// extra_config values. Schemas referencing remote documents cannot be compiled offline and
+// only get a warning.
+func checkEmbeddedDocuments(config map[string]interface{}) ([]ValidationError, []ValidationWarning) {
+	var errs []ValidationError
+	var warnings []ValidationWarning
+	for _, doc := range collectEmbeddedDocuments(config, "$") {
+		switch doc.kind {
+		case "schema":
+			err := CompileEmbeddedSchema(doc.value)
+			var loadErr *jsonschema.LoadURLError
+			var schemeErr *jsonschema.UnsupportedURLSchemeError
+			switch {
+			case err == nil:
+			case errors.As(err, &loadErr), errors.As(err, &schemeErr):
+				warnings = append(warnings, newWarning(WarningEmbeddedSchemaRemoteRef, doc.path,
+					fmt.Sprintf("Embedded JSON Schema references a remote document and was not compiled: %v", err)))
+			default:
+				errs = append(errs, ValidationError{
+					Path:    doc.path,
+					Message: fmt.Sprintf("Invalid embedded JSON Schema: %v", err),
+					Code:    "INVALID_EMBEDDED_SCHEMA",
+				})
+			}
+		case "cel":
+			expr, ok := doc.value.(string)
+			if !ok {
+				errs = append(errs, ValidationError{Path: doc.path, Message: "CEL expression must be a string", Code: "INVALID_CEL_EXPRESSION"})
+				continue
+			}
+			if err := parseCEL(expr); err != nil {
+				errs = append(errs, ValidationError{
+					Path:    doc.path,
+					Message: fmt.Sprintf("Invalid CEL expression %q: %v", expr, err),
+					Code:    "INVALID_CEL_EXPRESSION",
+				})
+			}
+		}
+	}
+	return errs, warnings
+}
+
+// applyEmbeddedChecks adds the errors of the documents embedded in extra_config values to a
+// validation result: krakend check and the configuration schema treat them as opaque values,
+// and they only fail when KrakenD builds the endpoints or serves a request
+func applyEmbeddedChecks(result *ValidationResult, configInput string) {
+	if result.Method == "file_read" || result.Method == "syntax" {
+		return
+	}
+	config, ok := readConfigObject(configInput)
+	if !ok {
+		return
+	}
+
+	errs, warnings := checkEmbeddedDocuments(config)
+	result.Warnings = append(result.Warnings, warnings...)
+	if len(errs) == 0 {
+		return
+	}
+	result.Errors = append(result.Errors, errs...)
+	if result.Valid {
+		result.Valid = false
+		result.Summary = fmt.Sprintf("%s, but %d embedded JSON Schema or CEL expression(s) are invalid", result.Summary, len(errs))
+	} else {
+		result.Summary = fmt.Sprintf("%s; %d embedded JSON Schema or CEL expression(s) are also invalid", result.Summary, len(errs))
+	}
+}
+
+// readConfigObject decodes a configuration passed as JSON or as a file path, YAML and TOML files
+// converted. It reports false when the configuration cannot be read.
+func readConfigObject(configInput string) (map[string]interface{}, bool) {
+	content := configInput
+	if isFilePath(configInput) {
+		data, err := os.ReadFile(configInput)
+		if err != nil {
+			return nil, false
+		}
+		content = string(data)
+		if format := configFormat(configInput); format != ConfigFormatJSON {
+			converted, convErr := configToJSON(data, format)
+			if convErr != nil {
+				return nil, false
+			}
+			content = converted
+		}
+	}
+	var config map[string]interface{}
+	if err := json.Unmarshal([]byte(content), &config); err != nil {
+		return nil, false
+	}
+	return config, true
+}
@@ -0,0 +1,112 @@
+package validation
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestParseCEL(t *testing.T) {
+	valid := []string{
+		`has(JWT.user_id) && JWT.user_id == req_params.Id`,
+		`'Bearer' in req_headers['Authorization'][0]`,
+		`req_method == "POST" ? size(req_headers) > 0 : true`,
+		`timestamp(req_querystring.from[0]) < now - duration("24h")`,
+		`resp_data.items.all(i, i.price >= 0.5e1 && i.qty > 1u) || !resp_completed`,
+		`{'a': [1, 2, 3,], "b": -0x1F}.size() == 2 // trailing comment`,
+		`r"raw \d+" != b'\x00' && """multi
+line""" != ""`,
+		`google.protobuf.Duration{seconds: 10}.seconds == 10`,
+	}
+	for _, expr := range valid {
+		if err := parseCEL(expr); err != nil {
+			t.Errorf("parseCEL(%q) unexpected error: %v", expr, err)
+		}
+	}
+
+	invalid := map[string]string{
+		``:                                "empty expression",
+		`req_method == `:                  "unexpected end of expression",
+		`has(JWT.user_id`:                 "unexpected end of expression",
+		`req_headers['X-Id'] = "1"`:       "unexpected character '='",
+		`req_method == 'GET`:              "unterminated string",
+		`JWT.roles.exists(r, r == "a") )`: `unexpected ")"`,
+		`a && || b`:                       `unexpected "||"`,
+		`if == 1`:                         "reserved word",
+		`"bad \escape"`:                   "invalid escape",
+		`x # y`:                           "unexpected character",
+	}
+	for expr, want := range invalid {
+		err := parseCEL(expr)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("parseCEL(%q) = %v, want error containing %q", expr, err, want)
+		}
+	}
+}
+
+func TestCheckEmbeddedDocuments(t *testing.T) {
+	configJSON := `{
+		"version": 3,
+		"endpoints": [{
+			"endpoint": "/users",
+			"extra_config": {
+				"validation/json-schema": {"type": "object", "required": ["name"], "properties": {"name": {"type": "string"}}},
+				"validation/cel": [{"check_expr": "has(JWT.sub)"}, {"check_expr": "req_method == "}]
+			},
+			"backend": [{
+				"url_pattern": "/users",
+				"extra_config": {
+					"validation/response-json-schema": {"schema": {"type": "strng"}},
+					"security/policies": {"req": {"policies": ["hasHeader('X-Id')", "req.headers[)"]}}
+				}
+			}]
+		}, {
+			"endpoint": "/orders",
+			"extra_config": {
+				"validation/json-schema": {"$ref": "https://schemas.example.com/order.json"}
+			}
+		}]
+	}`
+	var config map[string]interface{}
+	if err := json.Unmarshal([]byte(configJSON), &config); err != nil {
+		t.Fatal(err)
+	}
+
+	errs, warnings := checkEmbeddedDocuments(config)
+	got := map[string]string{}
+	for _, e := range errs {
+		got[e.Path] = e.Code
+	}
+	want := map[string]string{
+		"$.endpoints[0].extra_config.validation/cel[1].check_expr":                      "INVALID_CEL_EXPRESSION",
+		"$.endpoints[0].backend[0].extra_config.validation/response-json-schema.schema": "INVALID_EMBEDDED_SCHEMA",
+		"$.endpoints[0].backend[0].extra_config.security/policies.req.policies[1]":      "INVALID_CEL_EXPRESSION",
+	}
+	if len(got) != len(want) {
+		t.Errorf("Expected %d errors, got %v", len(want), errs)
+	}
+	for path, code := range want {
+		if got[path] != code {
+			t.Errorf("Expected %s at %s, got %v", code, path, errs)
+		}
+	}
+
+	if len(warnings) != 1 || warnings[0].Code != WarningEmbeddedSchemaRemoteRef || warnings[0].Path != "$.endpoints[1].extra_config.validation/json-schema" {
+		t.Errorf("Expected a remote reference warning for the orders schema, got %v", warnings)
+	}
+}
+
+func TestApplyEmbeddedChecks(t *testing.T) {
+	configJSON := `{"version": 3, "endpoints": [{"endpoint": "/a", "extra_config": {"validation/cel": [{"check_expr": "1 +"}]}}]}`
+	result := ValidationResult{Valid: true, Method: "native", Summary: "Configuration is valid"}
+	applyEmbeddedChecks(&result, configJSON)
+	if result.Valid || len(result.Errors) != 1 || !strings.Contains(result.Summary, "1 embedded") {
+		t.Errorf("Expected the invalid CEL expression to fail the validation, got %+v", result)
+	}
+
+	syntax := ValidationResult{Method: "syntax"}
+	applyEmbeddedChecks(&syntax, configJSON)
+	if len(syntax.Errors) != 0 {
+		t.Errorf("Embedded documents should not be checked when the JSON is invalid, got %v", syntax.Errors)
+	}
+}
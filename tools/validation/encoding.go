@@ -0,0 +1,64 @@
+package validation
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Config encodings accepted by config_encoding, for configurations some MCP clients would truncate or mangle
+const (
+	ConfigEncodingBase64     = "base64"
+	ConfigEncodingGzipBase64 = "gzip+base64"
+)
+
+// maxDecodedConfigBytes caps a decoded configuration, so a small gzip payload cannot exhaust the memory
+const maxDecodedConfigBytes = 64 << 20
+
+// DecodeConfig returns the configuration sent with the given config_encoding. Without encoding
+// config is returned as is, still a JSON string or a file path. A decoded configuration must be
+// JSON content: file paths are never decoded.
+func DecodeConfig(config, encoding string) (string, error) {
+	encoding = strings.ToLower(strings.TrimSpace(encoding))
+	if encoding == "" || encoding == "none" {
+		return config, nil
+	}
+	if encoding != ConfigEncodingBase64 && encoding != ConfigEncodingGzipBase64 {
+		return "", fmt.Errorf("unknown config_encoding %q (expected %s or %s)", encoding, ConfigEncodingBase64, ConfigEncodingGzipBase64)
+	}
+
+	// Clients wrap long base64 lines and may drop the padding
+	payload := strings.Map(func(r rune) rune {
+		if r == ' ' || r == '\n' || r == '\r' || r == '\t' {
+			return -1
+		}
+		return r
+	}, config)
+	data, err := base64.RawStdEncoding.DecodeString(strings.TrimRight(payload, "="))
+	if err != nil {
+		return "", fmt.Errorf("config is not valid base64: %w", err)
+	}
+
+	if encoding == ConfigEncodingGzipBase64 {
+		zr, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return "", fmt.Errorf("config is not gzip compressed: %w", err)
+		}
+		defer zr.Close()
+		if data, err = io.ReadAll(io.LimitReader(zr, maxDecodedConfigBytes+1)); err != nil {
+			return "", fmt.Errorf("failed to decompress config: %w", err)
+		}
+	}
+	if len(data) > maxDecodedConfigBytes {
+		return "", fmt.Errorf("decoded config exceeds %d MB", maxDecodedConfigBytes>>20)
+	}
+
+	decoded := string(data)
+	if trimmed := strings.TrimSpace(decoded); !strings.HasPrefix(trimmed, "{") && !strings.HasPrefix(trimmed, "[") {
+		return "", fmt.Errorf("decoded config is not a JSON configuration: encode the configuration content, not its path")
+	}
+	return decoded, nil
+}
@@ -0,0 +1,86 @@
+package validation
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+func gzipBase64(t *testing.T, data []byte) string {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return base64.StdEncoding.EncodeToString(buf.Bytes())
+}
+
+func TestDecodeConfig(t *testing.T) {
+	config := `{"version":3,"endpoints":[]}`
+	encoded := base64.StdEncoding.EncodeToString([]byte(config))
+	compressed := gzipBase64(t, []byte(config))
+	for _, tc := range []struct {
+		name, config, encoding string
+	}{
+		{"plain", config, ""},
+		{"base64", encoded, "base64"},
+		{"unpadded and wrapped", strings.TrimRight(encoded[:10]+"\n"+encoded[10:], "="), "base64"},
+		{"gzip", compressed, "GZIP+BASE64"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			decoded, err := DecodeConfig(tc.config, tc.encoding)
+			if err != nil || decoded != config {
+				t.Errorf("DecodeConfig() = %q, %v", decoded, err)
+			}
+		})
+	}
+
+	if path, err := DecodeConfig("./krakend.json", ""); err != nil || path != "./krakend.json" {
+		t.Errorf("Expected file paths to be kept without encoding, got %q, %v", path, err)
+	}
+	for _, tc := range []struct {
+		name, config, encoding, want string
+	}{
+		{"unknown encoding", config, "zstd", "unknown config_encoding"},
+		{"invalid base64", "not base64!", "base64", "not valid base64"},
+		{"not gzip", encoded, "gzip+base64", "not gzip"},
+		{"encoded path", base64.StdEncoding.EncodeToString([]byte("/etc/passwd")), "base64", "not a JSON configuration"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := DecodeConfig(tc.config, tc.encoding); err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("Expected an error containing %q, got %v", tc.want, err)
+			}
+		})
+	}
+}
+
+func TestDecodeConfig_Limit(t *testing.T) {
+	bomb := gzipBase64(t, bytes.Repeat([]byte(" "), maxDecodedConfigBytes+1))
+	if _, err := DecodeConfig(bomb, ConfigEncodingGzipBase64); err == nil || !strings.Contains(err.Error(), "exceeds") {
+		t.Errorf("Expected the decoded size to be capped, got %v", err)
+	}
+}
+
+func TestAuditSecurity_ConfigEncoding(t *testing.T) {
+	withFakeKrakend(t, "HIGH: no rate limiting configured", func() {
+		input := AuditSecurityInput{Config: gzipBase64(t, []byte(`{"version":3,"endpoints":[]}`)), ConfigEncoding: ConfigEncodingGzipBase64}
+		_, output, err := AuditSecurity(context.Background(), nil, input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if output.Method != "native" || len(output.Issues) == 0 {
+			t.Errorf("Expected the decoded configuration to be audited, got %+v", output)
+		}
+	})
+
+	if _, _, err := ValidateConfig(context.Background(), nil, ValidateConfigInput{Config: "{}", ConfigEncoding: "base64"}); err == nil {
+		t.Error("Expected an error for an invalid payload")
+	}
+}
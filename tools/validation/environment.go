@@ -8,13 +8,20 @@ import (
 	"path/filepath"
 	"regexp"
 	"strings"
+
+	"github.com/krakend/mcp-server/internal/runtime"
 )
 
 // ValidationEnvironment represents the available validation methods
 type ValidationEnvironment struct {
 	HasNativeKrakenD   bool
 	HasDocker          bool
+	DockerBinary       string // CLI of the Docker tier: "docker" or "podman"
+	ContainerEngine    string // "docker" or "podman", also behind the docker command of podman-docker
 	DockerVersion      string
+	DockerMode         string // "local", "stdin" or "off"
+	DockerModeReason   string
+	InContainer        bool
 	FlexibleConfig     *FlexibleConfigInfo
 }
 
@@ -63,8 +70,8 @@ func isFilePath(s string) bool {
 		return true
 	}
 
-	// File name with .json extension (no newlines, looks like a filename)
-	if strings.HasSuffix(s, ".json") && !strings.Contains(s, "\n") {
+	// File name with a .json, .yaml, .yml or .toml extension (no newlines, looks like a filename)
+	if hasConfigExtension(s) && !strings.Contains(s, "\n") {
 		return true
 	}
 
@@ -80,10 +87,12 @@ func DetectEnvironment() *ValidationEnvironment {
 		env.HasNativeKrakenD = true
 	}
 
-	// Check for Docker
-	if output, err := exec.Command("docker", "--version").CombinedOutput(); err == nil {
-		env.HasDocker = true
-		env.DockerVersion = strings.TrimSpace(string(output))
+	// Check for Docker, or Podman without it (skipped when no daemon is reachable, e.g. inside CI containers)
+	env.InContainer = runtime.InContainer()
+	env.DockerMode, env.DockerModeReason = runtime.ResolveDockerMode()
+	if binary, engine, version, ok := runtime.DetectContainerCLI(); ok {
+		env.HasDocker = env.DockerMode != runtime.DockerModeOff
+		env.DockerBinary, env.ContainerEngine, env.DockerVersion = binary, engine, version
 	}
 
 	// Check for Flexible Configuration
@@ -219,7 +228,7 @@ func DetectFlexibleConfiguration() *FlexibleConfigInfo {
 			fc.Implications = append(fc.Implications, "Partials directory: "+fc.PartialsDir)
 		}
 
-		fc.Implications = append(fc.Implications, "Use FC_OUT=out.json to generate compiled configuration for debugging")
+		fc.Implications = append(fc.Implications, "The compiled configuration is returned as compiled_config (pass save_compiled to write it to a file)")
 
 		return fc
 	}
@@ -243,11 +252,12 @@ func ExtractVersionFromConfig(configJSON string) string {
 
 	// Parse: https://www.krakend.io/schema/v2.12/krakend.json → "2.12"
 	// Parse: https://www.krakend.io/schema/krakend.json → "latest"
+	// The version ends up in Docker image tags, so anything but a version number is ignored
 	if strings.Contains(schema, "/v") {
 		parts := strings.Split(schema, "/")
 		for i, part := range parts {
-			if strings.HasPrefix(part, "v") && i+1 < len(parts) {
-				return strings.TrimPrefix(part, "v")
+			if strings.HasPrefix(part, "v") && i+1 < len(parts) && schemaVersionPattern.MatchString(part[1:]) {
+				return part[1:]
 			}
 		}
 	}
@@ -255,6 +265,9 @@ func ExtractVersionFromConfig(configJSON string) string {
 	return "latest"
 }
 
+// schemaVersionPattern matches the version of a versioned schema URL, e.g. "2.12"
+var schemaVersionPattern = regexp.MustCompile(`^\d+(\.\d+){0,2}$`)
+
 // GetLocalKrakenDVersion gets the version of local krakend binary
 func GetLocalKrakenDVersion() (string, error) {
 	cmd := exec.Command("krakend", "version")
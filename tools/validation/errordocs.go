@@ -0,0 +1,148 @@
+package validation
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Sources of an ErrorDoc
+const (
+	ErrorDocCurated   = "curated"   // Known error, mapped to its documentation page
+	ErrorDocNamespace = "namespace" // Documentation of the namespace the error is about
+)
+
+// ErrorDoc is the documentation explaining how to fix a validation error
+type ErrorDoc struct {
+	Title    string `json:"title"`
+	URL      string `json:"url"`
+	Resource string `json:"resource,omitempty"` // Resource with the whole documentation page, when found in the local index
+	Source   string `json:"source"`             // "curated" or "namespace"
+}
+
+// DocLookup finds the documentation of a namespace in the local documentation index. It is set by
+// the documentation tools and must not block: nil while the index is not ready.
+var DocLookup func(namespace string) *ErrorDoc
+
+// errorDoc maps a recurring error to its documentation, by code or by the text of the message
+type errorDoc struct {
+	Codes   []string
+	Pattern *regexp.Regexp // Matches the message as returned by krakend check, or nil
+	Title   string
+	URL     string
+}
+
+// errorDocs are the errors krakend check and the other tiers report most often. The first match wins:
+// specific messages come before the generic ones.
+var errorDocs = []errorDoc{
+	{
+		Codes: []string{"FC_TEMPLATE_ERROR", "FC_TEMPLATE_NOT_RENDERED"},
+		Title: "Flexible Configuration",
+		URL:   "https://www.krakend.io/docs/configuration/flexible-config/",
+	},
+	{
+		Codes: []string{"INVALID_CEL_EXPRESSION"},
+		Title: "Conditional requests and responses with CEL",
+		URL:   "https://www.krakend.io/docs/endpoints/common-expression-language-cel/",
+	},
+	{
+		Codes: []string{"INVALID_EMBEDDED_SCHEMA"},
+		Title: "JSON Schema validation",
+		URL:   "https://www.krakend.io/docs/endpoints/json-schema/",
+	},
+	{
+		Pattern: regexp.MustCompile(`(?i)unsupported version|version.*must be one of`),
+		Title:   "Configuration file structure",
+		URL:     "https://www.krakend.io/docs/configuration/structure/",
+	},
+	{
+		Pattern: regexp.MustCompile(`(?i)conflicts with existing wildcard|wildcard route|already registered`),
+		Title:   "Endpoint configuration",
+		URL:     "https://www.krakend.io/docs/endpoints/",
+	},
+	{
+		Pattern: regexp.MustCompile(`(?i)unsupported protocol scheme|no hosts? (defined|declared)|url_pattern|'backend'`),
+		Title:   "Backend configuration",
+		URL:     "https://www.krakend.io/docs/backends/",
+	},
+	{
+		Pattern: regexp.MustCompile(`(?i)public_key|private_key|certificate|\btls\b`),
+		Title:   "TLS settings",
+		URL:     "https://www.krakend.io/docs/service-settings/tls/",
+	},
+	{
+		Pattern: regexp.MustCompile(`(?i)timeout|duration`),
+		Title:   "Timeouts",
+		URL:     "https://www.krakend.io/docs/throttling/timeouts/",
+	},
+	{
+		Pattern: regexp.MustCompile(`(?i)\bplugins?\b`),
+		Title:   "Extending KrakenD with plugins",
+		URL:     "https://www.krakend.io/docs/extending/",
+	},
+	{
+		Codes:   []string{"INVALID_JSON", "INVALID_YAML", "INVALID_TOML", "MISSING_REQUIRED_FIELD", "INVALID_TYPE"},
+		Pattern: regexp.MustCompile(`(?i)invalid character|unexpected end of JSON input|cannot unmarshal|additional ?propert|missing propert`),
+		Title:   "Configuration file structure",
+		URL:     "https://www.krakend.io/docs/configuration/structure/",
+	},
+	{
+		Codes: []string{"KRAKEND_LINT_FAILED"},
+		Title: "Checking and linting the configuration",
+		URL:   "https://www.krakend.io/docs/configuration/check/",
+	},
+}
+
+var (
+	// namespacePathPattern matches the namespace of a JSON path, e.g. $.endpoints[0].extra_config.qos/ratelimit/router.max_rate
+	namespacePathPattern = regexp.MustCompile(`extra_config\.([a-z0-9_-]+(?:/[a-z0-9_-]+)+)`)
+	// namespacePointerPattern matches the namespace of a JSON pointer, e.g. '/extra_config/qos~1ratelimit~1router'
+	namespacePointerPattern = regexp.MustCompile(`/extra_config/([a-z0-9_-]+(?:~1[a-z0-9_-]+)+)`)
+)
+
+// attachErrorDocs links every error to the documentation explaining it, when known: the
+// documentation of the namespace it is about, or of the recurring error it matches
+func attachErrorDocs(result *ValidationResult) {
+	for i := range result.Errors {
+		e := &result.Errors[i]
+		if e.Doc != nil {
+			continue
+		}
+		if namespace := errorNamespace(*e); namespace != "" && DocLookup != nil {
+			if doc := DocLookup(namespace); doc != nil {
+				doc.Source = ErrorDocNamespace
+				e.Doc = doc
+				continue
+			}
+		}
+		if known := matchErrorDoc(*e); known != nil {
+			e.Doc = &ErrorDoc{Title: known.Title, URL: known.URL, Source: ErrorDocCurated}
+		}
+	}
+}
+
+// errorNamespace is the extra_config namespace an error is about, from its path or from the first
+// JSON pointer of its message
+func errorNamespace(e ValidationError) string {
+	if m := namespacePathPattern.FindStringSubmatch(e.Path); m != nil {
+		return m[1]
+	}
+	if m := namespacePointerPattern.FindStringSubmatch(e.Message); m != nil {
+		return strings.ReplaceAll(m[1], "~1", "/")
+	}
+	return ""
+}
+
+// matchErrorDoc finds the documentation of a recurring error
+func matchErrorDoc(e ValidationError) *errorDoc {
+	for i, known := range errorDocs {
+		for _, code := range known.Codes {
+			if code == e.Code {
+				return &errorDocs[i]
+			}
+		}
+		if known.Pattern != nil && known.Pattern.MatchString(e.Message) {
+			return &errorDocs[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package validation
+
+import "testing"
+
+func TestAttachErrorDocs(t *testing.T) {
+	result := ValidationResult{Errors: []ValidationError{
+		{Message: "ERROR parsing the configuration file: unsupported version: 2", Code: "KRAKEND_CHECK_FAILED", Pass: PassCheck},
+		{Message: "panic: wildcard route ':id' conflicts with existing wildcard ':name'", Code: "KRAKEND_CHECK_FAILED", Pass: PassCheck},
+		{Message: "Template error in templates/endpoints.tmpl line 3: unexpected EOF", Code: "FC_TEMPLATE_ERROR"},
+		{Path: "$.endpoints[0].extra_config.qos/ratelimit/router.max_rate", Message: "expected number, but got string", Code: "SCHEMA_VALIDATION_ERROR"},
+		{Message: "something nobody has seen before", Code: "KRAKEND_CHECK_FAILED"},
+	}}
+	attachErrorDocs(&result)
+
+	want := []string{
+		"https://www.krakend.io/docs/configuration/structure/",
+		"https://www.krakend.io/docs/endpoints/",
+		"https://www.krakend.io/docs/configuration/flexible-config/",
+		"",
+		"",
+	}
+	for i, url := range want {
+		doc := result.Errors[i].Doc
+		if url == "" {
+			if doc != nil {
+				t.Errorf("errors[%d]: expected no documentation, got %+v", i, doc)
+			}
+			continue
+		}
+		if doc == nil || doc.URL != url || doc.Source != ErrorDocCurated {
+			t.Errorf("errors[%d]: expected curated %s, got %+v", i, url, doc)
+		}
+	}
+}
+
+func TestAttachErrorDocs_Namespace(t *testing.T) {
+	var looked []string
+	DocLookup = func(namespace string) *ErrorDoc {
+		looked = append(looked, namespace)
+		return &ErrorDoc{Title: "Rate limiting", URL: "https://www.krakend.io/docs/endpoints/rate-limit/"}
+	}
+	defer func() { DocLookup = nil }()
+
+	result := ValidationResult{Errors: []ValidationError{
+		{Path: "$.endpoints[0].extra_config.qos/ratelimit/router.max_rate", Message: "expected number", Code: "SCHEMA_VALIDATION_ERROR"},
+		{Message: "jsonschema: '/endpoints/0/extra_config/qos~1ratelimit~1router/max_rate' does not validate", Code: "KRAKEND_CHECK_FAILED"},
+		{Message: "unsupported version: 2", Code: "KRAKEND_CHECK_FAILED"},
+	}}
+	attachErrorDocs(&result)
+
+	if len(looked) != 2 || looked[0] != "qos/ratelimit/router" || looked[1] != "qos/ratelimit/router" {
+		t.Errorf("Unexpected namespaces looked up: %v", looked)
+	}
+	for i := 0; i < 2; i++ {
+		if doc := result.Errors[i].Doc; doc == nil || doc.Source != ErrorDocNamespace {
+			t.Errorf("errors[%d]: expected the namespace documentation, got %+v", i, doc)
+		}
+	}
+	if doc := result.Errors[2].Doc; doc == nil || doc.Source != ErrorDocCurated {
+		t.Errorf("Expected curated documentation without namespace, got %+v", doc)
+	}
+}
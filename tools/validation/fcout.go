@@ -0,0 +1,84 @@
+package validation
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/krakend/mcp-server/internal/tempfiles"
+)
+
+const (
+	// fcOutFile is the name of the file CE Flexible Configuration compiles the configuration into
+	fcOutFile = "out.json"
+	// fcOutMount is where the FC_OUT directory is mounted in validation containers
+	fcOutMount = "/krakend-out"
+	// maxCompiledBytes caps the compiled configuration returned inline, larger ones are only saved
+	maxCompiledBytes = 256 << 10
+)
+
+// CompiledConfig is the configuration compiled by CE Flexible Configuration during the validation
+type CompiledConfig struct {
+	Content   string `json:"content,omitempty"` // Compiled configuration, omitted when truncated
+	Bytes     int    `json:"bytes"`
+	ValidJSON bool   `json:"valid_json"`
+	Truncated bool   `json:"truncated,omitempty"` // Too large to be returned: use save_compiled
+	SavedTo   string `json:"saved_to,omitempty"`  // With save_compiled
+
+	data []byte
+}
+
+// usesFCOut reports whether krakend compiles the configuration into FC_OUT (CE Flexible Configuration)
+func usesFCOut(env *ValidationEnvironment) bool {
+	fc := env.FlexibleConfig
+	return fc != nil && fc.Detected && fc.Type != "ee"
+}
+
+// newFCOutDir creates the private directory receiving FC_OUT, outside the project so validations do
+// not leave an out.json behind. It is empty (and there is nothing to clean) without CE Flexible Configuration.
+func newFCOutDir(env *ValidationEnvironment, tempDir string) (dir string, cleanup func(), err error) {
+	if !usesFCOut(env) {
+		return "", func() {}, nil
+	}
+	return tempfiles.Dir(tempDir, "krakend-fc-*")
+}
+
+// readCompiled reads the configuration compiled into dir, nil when krakend did not write it
+func readCompiled(dir string) *CompiledConfig {
+	if dir == "" {
+		return nil
+	}
+	data, err := os.ReadFile(filepath.Join(dir, fcOutFile))
+	if err != nil {
+		return nil
+	}
+	compiled := &CompiledConfig{Bytes: len(data), ValidJSON: json.Valid(data), data: data}
+	if len(data) > maxCompiledBytes {
+		compiled.Truncated = true
+	} else {
+		compiled.Content = string(data)
+	}
+	return compiled
+}
+
+// save writes the compiled configuration to path
+func (c *CompiledConfig) save(path string) error {
+	if err := os.WriteFile(path, c.data, 0o644); err != nil {
+		return fmt.Errorf("failed to save the compiled configuration: %w", err)
+	}
+	c.SavedTo = path
+	return nil
+}
+
+// saveCompiled writes the compiled configuration of a validation to path, warning when there is none
+func saveCompiled(result *ValidationResult, path string) {
+	if result.Compiled == nil {
+		result.Warnings = append(result.Warnings, newWarning(WarningCompiledNotSaved, path,
+			"No compiled configuration to save: only CE Flexible Configuration validated with krakend or Docker produces one"))
+		return
+	}
+	if err := result.Compiled.save(path); err != nil {
+		result.Warnings = append(result.Warnings, newWarning(WarningCompiledNotSaved, path, err.Error()))
+	}
+}
@@ -0,0 +1,65 @@
+package validation
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/krakend/mcp-server/internal/testutil"
+)
+
+// withFCProject runs f in a CE Flexible Configuration project, with a krakend binary compiling
+// compiled into FC_OUT
+func withFCProject(t *testing.T, compiled string, f func(project string)) {
+	t.Helper()
+	project := t.TempDir()
+	if err := os.WriteFile(filepath.Join(project, "krakend.tmpl"), []byte(`{"version":3,"endpoints":[]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	testutil.KrakenD{Compiled: compiled}.Install(t)
+	t.Chdir(project)
+	f(project)
+}
+
+func TestValidateConfig_CompiledConfig(t *testing.T) {
+	withFCProject(t, `{"version":3,"endpoints":[{"endpoint":"/a"}]}`, func(project string) {
+		saved := filepath.Join(t.TempDir(), "compiled.json")
+		_, output, err := ValidateConfig(context.Background(), nil, ValidateConfigInput{Config: "./krakend.tmpl", SaveCompiled: saved})
+		if err != nil {
+			t.Fatal(err)
+		}
+		compiled := output.Compiled
+		if compiled == nil || !compiled.ValidJSON || compiled.Content != `{"version":3,"endpoints":[{"endpoint":"/a"}]}` || compiled.SavedTo != saved {
+			t.Fatalf("Unexpected compiled configuration: %+v", compiled)
+		}
+		if data, err := os.ReadFile(saved); err != nil || string(data) != compiled.Content {
+			t.Errorf("Expected the compiled configuration in %s, got %q, %v", saved, data, err)
+		}
+		if _, err := os.Stat(filepath.Join(project, "out.json")); !os.IsNotExist(err) {
+			t.Error("The validation must not write out.json in the project")
+		}
+	})
+}
+
+func TestReadCompiled(t *testing.T) {
+	if readCompiled("") != nil || readCompiled(t.TempDir()) != nil {
+		t.Error("Expected no compiled configuration")
+	}
+
+	dir := t.TempDir()
+	large := make([]byte, maxCompiledBytes+1)
+	if err := os.WriteFile(filepath.Join(dir, fcOutFile), large, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	compiled := readCompiled(dir)
+	if compiled == nil || !compiled.Truncated || compiled.Content != "" || compiled.ValidJSON || compiled.Bytes != len(large) {
+		t.Errorf("Unexpected compiled configuration: %+v", compiled)
+	}
+
+	result := &ValidationResult{}
+	saveCompiled(result, filepath.Join(dir, "saved.json"))
+	if len(result.Warnings) != 1 || result.Warnings[0].Code != WarningCompiledNotSaved {
+		t.Errorf("Expected a warning without compiled configuration, got %+v", result.Warnings)
+	}
+}
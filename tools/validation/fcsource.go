@@ -0,0 +1,306 @@
+package validation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Matches of a SourceLocation
+const (
+	SourceMatchTemplate = "template" // Reported by the template engine
+	SourceMatchContent  = "content"  // The compiled line was found in the file
+)
+
+// SourceLocation points an error of a CE Flexible Configuration project at the template or partial
+// it comes from, rather than at the compiled configuration
+type SourceLocation struct {
+	File         string `json:"file"`
+	Line         int    `json:"line,omitempty"`
+	Column       int    `json:"column,omitempty"`
+	CompiledLine int    `json:"compiled_line,omitempty"` // Line of the compiled configuration, when the error was reported against it
+	Match        string `json:"match"`                   // "template" or "content"
+}
+
+var (
+	// templateErrorPattern matches Go template errors, e.g.
+	// template: endpoints.tmpl:12:5: executing "endpoints.tmpl" at <.service.host>: map has no entry for key "host"
+	templateErrorPattern = regexp.MustCompile(`template: ([^:\s]+):(\d+)(?::(\d+))?: ([^\n]*)`)
+	// compiledPointerPattern matches the JSON pointers of schema errors, e.g. at '/endpoints/0/backend'
+	compiledPointerPattern = regexp.MustCompile(`(?:at |jsonschema: )'(/[^']*)'`)
+	// compiledRowPattern matches the position of KrakenD parsing errors, e.g. offset: 245, row: 12, col: 3
+	compiledRowPattern = regexp.MustCompile(`row: (\d+), col: (\d+)`)
+)
+
+// maxMappedSources caps the source locations reported per error
+const maxMappedSources = 10
+
+// isFlexibleConfigTemplate reports whether a configuration file is a CE Flexible Configuration
+// template: the base template, or any .tmpl file of the project
+func isFlexibleConfigTemplate(env *ValidationEnvironment, path string) bool {
+	if !usesFCOut(env) {
+		return false
+	}
+	if filepath.Ext(path) == ".tmpl" {
+		return true
+	}
+	abs, err := filepath.Abs(path)
+	base, baseErr := filepath.Abs(env.FlexibleConfig.BaseTemplate)
+	return err == nil && baseErr == nil && abs == base
+}
+
+// renderFlexibleConfig renders the CE Flexible Configuration templates into fcOut before the check
+// passes. There is nothing to check when the templates cannot be rendered: the failed pass is then
+// returned with the template errors, mapped to their files. Both are nil when the templates rendered
+// (or the errors are not template errors, left to the check passes).
+func renderFlexibleConfig(ctx context.Context, env *ValidationEnvironment, build checkCommand, fcOut string) (*CheckPass, []ValidationError, error) {
+	pass, err := runCheckPass(ctx, PassCheck, build)
+	if err != nil {
+		return nil, nil, err
+	}
+	if pass.Passed || readCompiled(fcOut) != nil {
+		return nil, nil, nil
+	}
+	errs := templateErrors(pass.Output, env.FlexibleConfig)
+	if len(errs) == 0 {
+		return nil, nil, nil
+	}
+	return &pass, errs, nil
+}
+
+// applyRenderFailure records templates that could not be rendered: KrakenD cannot start
+func applyRenderFailure(result *ValidationResult, pass CheckPass, errs []ValidationError, validatedWith string) {
+	result.Passes = []CheckPass{pass}
+	result.Valid = false
+	result.Errors = append(result.Errors, errs...)
+	result.Summary = fmt.Sprintf("Flexible Configuration templates could not be rendered (%s): KrakenD cannot start with this configuration", validatedWith)
+}
+
+// templateErrors parses the template errors of a krakend output, one per error line
+func templateErrors(output string, fc *FlexibleConfigInfo) []ValidationError {
+	var errs []ValidationError
+	seen := map[string]bool{}
+	for _, m := range templateErrorPattern.FindAllStringSubmatch(output, -1) {
+		if seen[m[0]] {
+			continue
+		}
+		seen[m[0]] = true
+		source := SourceLocation{File: templateFile(fc, m[1]), Match: SourceMatchTemplate}
+		source.Line, _ = strconv.Atoi(m[2])
+		source.Column, _ = strconv.Atoi(m[3])
+		errs = append(errs, ValidationError{
+			Path:    source.File,
+			Message: fmt.Sprintf("Template error in %s line %d: %s", source.File, source.Line, m[4]),
+			Line:    source.Line,
+			Column:  source.Column,
+			Code:    "FC_TEMPLATE_ERROR",
+			Pass:    PassCheck,
+			Sources: []SourceLocation{source},
+		})
+	}
+	return errs
+}
+
+// templateFile resolves the name of a template, as the template engine reports it, to its file
+func templateFile(fc *FlexibleConfigInfo, name string) string {
+	if filepath.Base(fc.BaseTemplate) == name {
+		return fc.BaseTemplate
+	}
+	for _, dir := range []string{fc.TemplatesDir, fc.PartialsDir} {
+		if dir == "" {
+			continue
+		}
+		if path := filepath.Join(dir, name); fileExists(path) {
+			return path
+		}
+	}
+	return name
+}
+
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
+
+// mapCompiledErrors points the errors KrakenD reported against the compiled configuration at the
+// templates and partials containing the offending lines
+func mapCompiledErrors(result *ValidationResult, env *ValidationEnvironment) {
+	if !usesFCOut(env) || result.Compiled == nil {
+		return
+	}
+	compiled := result.Compiled.data
+	var pointers map[string]int
+	var files []sourceFile
+	for i := range result.Errors {
+		e := &result.Errors[i]
+		if len(e.Sources) > 0 {
+			continue
+		}
+		lines := compiledErrorLines(e.Message, compiled, &pointers)
+		if len(lines) == 0 {
+			continue
+		}
+		if files == nil {
+			files = flexibleConfigSources(env.FlexibleConfig)
+		}
+		for _, line := range lines {
+			source := findCompiledLine(compiled, line, files)
+			if source == nil {
+				continue
+			}
+			e.Sources = append(e.Sources, *source)
+			if len(e.Sources) == maxMappedSources {
+				break
+			}
+		}
+	}
+}
+
+// compiledErrorLines finds the lines of the compiled configuration an error message refers to:
+// by JSON pointer, by row or, when the compiled configuration is not even JSON, where it breaks.
+// pointers caches the lines of the compiled JSON pointers.
+func compiledErrorLines(message string, compiled []byte, pointers *map[string]int) []int {
+	var lines []int
+	seen := map[int]bool{}
+	add := func(line int) {
+		if line > 0 && !seen[line] {
+			seen[line] = true
+			lines = append(lines, line)
+		}
+	}
+	if matches := compiledPointerPattern.FindAllStringSubmatch(message, -1); len(matches) > 0 {
+		if *pointers == nil {
+			*pointers = pointerLines(compiled)
+		}
+		for _, m := range matches {
+			add((*pointers)[m[1]])
+		}
+	}
+	for _, m := range compiledRowPattern.FindAllStringSubmatch(message, -1) {
+		line, _ := strconv.Atoi(m[1])
+		add(line)
+	}
+	if len(lines) == 0 {
+		var syntaxErr *json.SyntaxError
+		if err := json.Unmarshal(compiled, new(interface{})); errors.As(err, &syntaxErr) {
+			add(lineAt(compiled, syntaxErr.Offset-1))
+		}
+	}
+	return lines
+}
+
+// pointerLines maps the JSON pointers of a JSON document to the line where their value starts
+func pointerLines(data []byte) map[string]int {
+	lines := map[string]int{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	var walk func(pointer string) error
+	walk = func(pointer string) error {
+		lines[pointer] = lineAt(data, dec.InputOffset())
+		token, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		switch token {
+		case json.Delim('{'):
+			for dec.More() {
+				key, err := dec.Token()
+				if err != nil {
+					return err
+				}
+				name, _ := key.(string)
+				name = strings.NewReplacer("~", "~0", "/", "~1").Replace(name)
+				if err := walk(pointer + "/" + name); err != nil {
+					return err
+				}
+			}
+			_, err = dec.Token()
+		case json.Delim('['):
+			for i := 0; dec.More(); i++ {
+				if err := walk(pointer + "/" + strconv.Itoa(i)); err != nil {
+					return err
+				}
+			}
+			_, err = dec.Token()
+		}
+		return err
+	}
+	walk("")
+	if line, ok := lines[""]; ok {
+		lines["/"] = line
+	}
+	return lines
+}
+
+// lineAt is the line of the first token at or after offset, skipping separators
+func lineAt(data []byte, offset int64) int {
+	if offset < 0 {
+		offset = 0
+	}
+	for offset < int64(len(data)) && strings.IndexByte(" \t\r\n,:", data[offset]) >= 0 {
+		offset++
+	}
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	return bytes.Count(data[:offset], []byte("\n")) + 1
+}
+
+// sourceFile is a template or partial of a Flexible Configuration project
+type sourceFile struct {
+	path  string
+	lines []string
+}
+
+// flexibleConfigSources reads the base template, then the templates and partials of a project
+func flexibleConfigSources(fc *FlexibleConfigInfo) []sourceFile {
+	var files []sourceFile
+	read := func(path string) {
+		if data, err := os.ReadFile(path); err == nil {
+			files = append(files, sourceFile{path: path, lines: strings.Split(string(data), "\n")})
+		}
+	}
+	if fc.BaseTemplate != "" {
+		read(fc.BaseTemplate)
+	}
+	for _, dir := range []string{fc.TemplatesDir, fc.PartialsDir} {
+		if dir == "" {
+			continue
+		}
+		// WalkDir visits the files in lexical order
+		filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+			if err == nil && !d.IsDir() {
+				read(path)
+			}
+			return nil
+		})
+	}
+	return files
+}
+
+// findCompiledLine looks for a line of the compiled configuration in the project files. Lines
+// without content of their own (brackets) are searched from the closest meaningful line above.
+func findCompiledLine(compiled []byte, line int, files []sourceFile) *SourceLocation {
+	lines := strings.Split(string(compiled), "\n")
+	for n := line; n >= 1 && n > line-5 && n <= len(lines); n-- {
+		text := strings.TrimSpace(lines[n-1])
+		if strings.Trim(text, "{}[],") == "" {
+			continue
+		}
+		for _, file := range files {
+			for i, l := range file.lines {
+				if strings.TrimSpace(l) == text {
+					return &SourceLocation{File: file.path, Line: i + 1 + line - n, CompiledLine: line, Match: SourceMatchContent}
+				}
+			}
+		}
+		return nil
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package validation
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/krakend/mcp-server/internal/testutil"
+)
+
+// writeFCProject writes a CE Flexible Configuration project with an endpoints partial and makes it
+// the working directory
+func writeFCProject(t *testing.T) {
+	t.Helper()
+	project := t.TempDir()
+	files := map[string]string{
+		"krakend.tmpl":                   "{\n  \"version\": 3,\n  \"endpoints\": [\n    {{ include \"endpoints.json\" }}\n  ]\n}\n",
+		"config/partials/endpoints.json": "{\n  \"endpoint\": \"/users\",\n  \"backend\": [\n    {\"host\": [\"http://users\"]}\n  ]\n}\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(project, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Chdir(project)
+}
+
+func TestValidateConfig_FlexibleConfigTemplateError(t *testing.T) {
+	writeFCProject(t)
+	calls := testutil.KrakenD{Output: `template: krakend.tmpl:4:8: executing "krakend.tmpl" at <include "missing.json">: error calling include`, ExitCode: 1}.Install(t)
+
+	_, output, err := ValidateConfig(context.Background(), nil, ValidateConfigInput{Config: "./krakend.tmpl"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if output.Valid || len(output.Errors) != 1 {
+		t.Fatalf("Expected a template error, got %+v", output.Errors)
+	}
+	e := output.Errors[0]
+	if e.Code != "FC_TEMPLATE_ERROR" || len(e.Sources) != 1 {
+		t.Fatalf("Unexpected error %+v", e)
+	}
+	if source := e.Sources[0]; source.File != "krakend.tmpl" || source.Line != 4 || source.Column != 8 || source.Match != SourceMatchTemplate {
+		t.Errorf("Unexpected source %+v", source)
+	}
+	if checks := len(calls.Args()) - 1; checks != 1 {
+		t.Errorf("Expected the check passes to be skipped, got %d checks", checks)
+	}
+}
+
+func TestValidateConfig_FlexibleConfigCompiledErrorMapped(t *testing.T) {
+	writeFCProject(t)
+	compiled := "{\n  \"version\": 3,\n  \"endpoints\": [\n    {\n  \"endpoint\": \"/users\",\n  \"backend\": [\n    {\"host\": [\"http://users\"]}\n  ]\n}\n  ]\n}\n"
+	testutil.KrakenD{Output: "jsonschema validation failed at '/endpoints/0/backend/0': missing property 'url_pattern'", ExitCode: 1, Compiled: compiled}.Install(t)
+
+	_, output, err := ValidateConfig(context.Background(), nil, ValidateConfigInput{Config: "./krakend.tmpl"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(output.Errors) != 1 || len(output.Errors[0].Sources) != 1 {
+		t.Fatalf("Expected a mapped error, got %+v", output.Errors)
+	}
+	source := output.Errors[0].Sources[0]
+	want := SourceLocation{File: filepath.Join("config", "partials", "endpoints.json"), Line: 4, CompiledLine: 7, Match: SourceMatchContent}
+	if source != want {
+		t.Errorf("Source = %+v, want %+v", source, want)
+	}
+}
+
+func TestPointerLines(t *testing.T) {
+	lines := pointerLines([]byte("{\n  \"a\": {\n    \"b/c\": [\n      1,\n      2\n    ]\n  }\n}"))
+	for pointer, want := range map[string]int{"": 1, "/a": 2, "/a/b~1c": 3, "/a/b~1c/1": 5} {
+		if lines[pointer] != want {
+			t.Errorf("Line of %q = %d, want %d", pointer, lines[pointer], want)
+		}
+	}
+}
+
+func TestCompiledErrorLines_SyntaxError(t *testing.T) {
+	var pointers map[string]int
+	lines := compiledErrorLines("invalid configuration", []byte("{\n  \"a\": 1,\n}\n"), &pointers)
+	if len(lines) != 1 || lines[0] != 3 {
+		t.Errorf("Expected the syntax error on line 3, got %v", lines)
+	}
+	lines = compiledErrorLines("offset: 12, row: 2, col: 5", []byte("{}"), &pointers)
+	if len(lines) != 1 || lines[0] != 2 {
+		t.Errorf("Expected row 2, got %v", lines)
+	}
+}
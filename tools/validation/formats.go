@@ -0,0 +1,117 @@
+package validation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Configuration formats KrakenD reads, by file extension
+const (
+	ConfigFormatJSON = "json"
+	ConfigFormatYAML = "yaml"
+	ConfigFormatTOML = "toml"
+)
+
+// configFormatExtensions maps the extensions of YAML and TOML configurations to their format
+var configFormatExtensions = map[string]string{
+	".yaml": ConfigFormatYAML,
+	".yml":  ConfigFormatYAML,
+	".toml": ConfigFormatTOML,
+}
+
+// yamlLinePattern matches the line of YAML errors, e.g. "yaml: line 3: did not find expected key"
+var yamlLinePattern = regexp.MustCompile(`line (\d+)`)
+
+// configFormat is the format of a configuration file by its extension, JSON unless YAML or TOML
+func configFormat(path string) string {
+	if format, ok := configFormatExtensions[strings.ToLower(filepath.Ext(path))]; ok {
+		return format
+	}
+	return ConfigFormatJSON
+}
+
+// hasConfigExtension reports whether s ends with the extension of a configuration file
+func hasConfigExtension(s string) bool {
+	if strings.HasSuffix(s, ".json") {
+		return true
+	}
+	_, ok := configFormatExtensions[strings.ToLower(filepath.Ext(s))]
+	return ok
+}
+
+// configToJSON converts a YAML or TOML configuration into the JSON the validation tiers check.
+// The error is a ValidationError locating the syntax error when possible.
+func configToJSON(data []byte, format string) (string, *ValidationError) {
+	var config interface{}
+	var err error
+	line := 0
+	switch format {
+	case ConfigFormatYAML:
+		if err = yaml.Unmarshal(data, &config); err != nil {
+			if m := yamlLinePattern.FindStringSubmatch(err.Error()); m != nil {
+				line, _ = strconv.Atoi(m[1])
+			}
+		}
+	case ConfigFormatTOML:
+		var table map[string]interface{}
+		if _, err = toml.Decode(string(data), &table); err != nil {
+			var parseErr toml.ParseError
+			if errors.As(err, &parseErr) {
+				line = parseErr.Position.Line
+			}
+		}
+		config = table
+	default:
+		return string(data), nil
+	}
+	code := "INVALID_" + strings.ToUpper(format)
+	if err != nil {
+		return "", &ValidationError{Message: fmt.Sprintf("Invalid %s: %s", strings.ToUpper(format), err.Error()), Line: line, Code: code}
+	}
+	if _, ok := config.(map[string]interface{}); !ok {
+		return "", &ValidationError{Message: fmt.Sprintf("Invalid %s: the configuration must be a mapping of settings", strings.ToUpper(format)), Code: code}
+	}
+	converted, err := json.Marshal(jsonCompatible(config))
+	if err != nil {
+		return "", &ValidationError{Message: fmt.Sprintf("%s configuration cannot be converted to JSON: %s", strings.ToUpper(format), err.Error()), Code: code}
+	}
+	return string(converted), nil
+}
+
+// jsonCompatible turns the non-string keys YAML allows (e.g. status codes) into strings
+func jsonCompatible(v interface{}) interface{} {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		for k, item := range value {
+			value[k] = jsonCompatible(item)
+		}
+		return value
+	case map[interface{}]interface{}:
+		converted := make(map[string]interface{}, len(value))
+		for k, item := range value {
+			converted[fmt.Sprint(k)] = jsonCompatible(item)
+		}
+		return converted
+	case []interface{}:
+		for i, item := range value {
+			value[i] = jsonCompatible(item)
+		}
+		return value
+	case []map[string]interface{}:
+		// TOML arrays of tables
+		converted := make([]interface{}, len(value))
+		for i, item := range value {
+			converted[i] = jsonCompatible(item)
+		}
+		return converted
+	}
+	return v
+}
@@ -0,0 +1,136 @@
+package validation
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/krakend/mcp-server/internal/testutil"
+)
+
+const yamlConfig = `version: 3
+endpoints:
+  - endpoint: /users
+    backend:
+      - host: ["http://users:8080"]
+        url_pattern: /users
+extra_config:
+  qos/http-cache:
+    shared: true
+  status_codes:
+    404: not found
+`
+
+const tomlConfig = `version = 3
+
+[[endpoints]]
+endpoint = "/users"
+
+[[endpoints.backend]]
+host = ["http://users:8080"]
+url_pattern = "/users"
+`
+
+func TestConfigFormat(t *testing.T) {
+	for path, want := range map[string]string{
+		"krakend.json":        ConfigFormatJSON,
+		"krakend.yaml":        ConfigFormatYAML,
+		"config/krakend.YML":  ConfigFormatYAML,
+		"/etc/krakend.toml":   ConfigFormatTOML,
+		"templates/base.tmpl": ConfigFormatJSON,
+	} {
+		if got := configFormat(path); got != want {
+			t.Errorf("configFormat(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestConfigToJSON(t *testing.T) {
+	for format, data := range map[string]string{ConfigFormatYAML: yamlConfig, ConfigFormatTOML: tomlConfig} {
+		t.Run(format, func(t *testing.T) {
+			converted, verr := configToJSON([]byte(data), format)
+			if verr != nil {
+				t.Fatalf("Unexpected error %+v", verr)
+			}
+			var config struct {
+				Version   int `json:"version"`
+				Endpoints []struct {
+					Endpoint string `json:"endpoint"`
+					Backend  []struct {
+						Host []string `json:"host"`
+					} `json:"backend"`
+				} `json:"endpoints"`
+			}
+			if err := json.Unmarshal([]byte(converted), &config); err != nil {
+				t.Fatalf("Converted configuration is not JSON: %v", err)
+			}
+			if config.Version != 3 || len(config.Endpoints) != 1 || config.Endpoints[0].Endpoint != "/users" ||
+				len(config.Endpoints[0].Backend) != 1 || config.Endpoints[0].Backend[0].Host[0] != "http://users:8080" {
+				t.Errorf("Unexpected conversion %s", converted)
+			}
+		})
+	}
+}
+
+func TestConfigToJSON_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		data   string
+		code   string
+		line   int
+	}{
+		{"yaml syntax", ConfigFormatYAML, "version: 3\nendpoints:\n  - endpoint: /a\n   backend: []\n", "INVALID_YAML", 2},
+		{"yaml not a mapping", ConfigFormatYAML, "- version: 3\n", "INVALID_YAML", 0},
+		{"toml syntax", ConfigFormatTOML, "version = 3\nendpoints = [\n", "INVALID_TOML", 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, verr := configToJSON([]byte(tt.data), tt.format)
+			if verr == nil {
+				t.Fatal("Expected an error")
+			}
+			if verr.Code != tt.code || verr.Line != tt.line {
+				t.Errorf("Got code %q line %d, want %q line %d: %s", verr.Code, verr.Line, tt.code, tt.line, verr.Message)
+			}
+		})
+	}
+}
+
+func TestValidateConfig_YAMLFile(t *testing.T) {
+	testutil.Isolate(t)
+	testutil.KrakenD{}.Install(t)
+	path := filepath.Join(t.TempDir(), "krakend.yaml")
+	os.WriteFile(path, []byte(yamlConfig), 0o644)
+
+	_, output, err := ValidateConfig(context.Background(), nil, ValidateConfigInput{Config: path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if output.Method != "native" || !output.Valid {
+		t.Errorf("Expected a native validation of the converted configuration, got %+v", output.ValidationResult)
+	}
+	converted := false
+	for _, w := range output.Warnings {
+		converted = converted || w.Code == WarningConvertedConfig
+	}
+	if !converted {
+		t.Errorf("Expected a %s warning, got %+v", WarningConvertedConfig, output.Warnings)
+	}
+}
+
+func TestValidateConfig_InvalidTOMLFile(t *testing.T) {
+	testutil.Isolate(t)
+	path := filepath.Join(t.TempDir(), "krakend.toml")
+	os.WriteFile(path, []byte("version = \n"), 0o644)
+
+	_, output, err := ValidateConfig(context.Background(), nil, ValidateConfigInput{Config: path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if output.Method != "syntax" || len(output.Errors) != 1 || output.Errors[0].Code != "INVALID_TOML" {
+		t.Errorf("Expected an INVALID_TOML syntax error, got %+v", output.ValidationResult)
+	}
+}
@@ -0,0 +1,58 @@
+package validation
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// FuzzIsFilePath checks config inputs sent by the LLM are never taken as a path when they are JSON
+func FuzzIsFilePath(f *testing.F) {
+	f.Add(`{"version": 3}`)
+	f.Add("  \n[1, 2]")
+	f.Add("/etc/krakend/krakend.json")
+	f.Add("./krakend.json")
+	f.Add(`C:\krakend\krakend.json`)
+	f.Add("C:")
+	f.Add("krakend.json")
+	f.Add("{\n}.json")
+	f.Fuzz(func(t *testing.T, s string) {
+		trimmed := strings.TrimSpace(s)
+		if isFilePath(s) && (strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[")) {
+			t.Errorf("isFilePath(%q) = true for JSON content", s)
+		}
+	})
+}
+
+func FuzzExtractVersionFromConfig(f *testing.F) {
+	f.Add(`{"$schema": "https://www.krakend.io/schema/v2.12/krakend.json", "version": 3}`)
+	f.Add(`{"$schema": "https://www.krakend.io/schema/krakend.json"}`)
+	f.Add(`{"$schema": "https://www.krakend.io/schema/v/krakend.json"}`)
+	f.Add(`{"$schema": "https://example.com/vendor/schema.json"}`)
+	f.Add(`{"$schema": 2.12}`)
+	f.Add(`{invalid`)
+	f.Fuzz(func(t *testing.T, schema string) {
+		config, _ := json.Marshal(map[string]string{"$schema": schema})
+		version := ExtractVersionFromConfig(string(config))
+		if version != "latest" && !schemaVersionPattern.MatchString(version) {
+			t.Errorf("ExtractVersionFromConfig(%q) = %q, want a version number or latest", schema, version)
+		}
+	})
+}
+
+// FuzzParseAuditOutput parses arbitrary krakend audit output: it echoes parts of the audited configuration
+func FuzzParseAuditOutput(f *testing.F) {
+	f.Add("[CRITICAL] 2.1.1 TLS is disabled\nHIGH: no rate limiting configured\ninformational line")
+	f.Add("\n\n\r\n")
+	f.Add("LOW 1.2.3.4.5 debug endpoint")
+	f.Fuzz(func(t *testing.T, output string) {
+		for _, issue := range parseAuditOutput(output) {
+			if issue.Severity == "info" || issue.Title == "" {
+				t.Errorf("Unexpected issue %+v", issue)
+			}
+			if issue.Rule != "" && !strings.Contains(issue.Title, issue.Rule) {
+				t.Errorf("Rule %q not found in %q", issue.Rule, issue.Title)
+			}
+		}
+	})
+}
@@ -0,0 +1,65 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+)
+
+// severityRanks orders finding severities, from least to most severe
+var severityRanks = map[string]int{
+	"info":     0,
+	"low":      1,
+	"medium":   2,
+	"high":     3,
+	"critical": 4,
+}
+
+// GateDecision is the pass/fail verdict of findings against a severity threshold,
+// meant to be used as a CI exit criterion
+type GateDecision struct {
+	FailOn   string         `json:"fail_on"` // Lowest severity failing the gate
+	Passed   bool           `json:"passed"`
+	Failing  int            `json:"failing"` // Findings at or above the threshold
+	Counts   map[string]int `json:"counts"`  // Findings per severity
+	Decision string         `json:"decision"`
+}
+
+// parseFailOn validates a severity threshold
+func parseFailOn(failOn string) (string, error) {
+	failOn = strings.ToLower(strings.TrimSpace(failOn))
+	if _, ok := severityRanks[failOn]; !ok {
+		return "", fmt.Errorf("invalid fail_on %q: use critical, high, medium, low or info", failOn)
+	}
+	return failOn, nil
+}
+
+// evaluateGate compares the severities of the findings with a threshold
+func evaluateGate(failOn string, severities []string) *GateDecision {
+	gate := &GateDecision{FailOn: failOn, Counts: map[string]int{}}
+	threshold := severityRanks[failOn]
+	for _, severity := range severities {
+		gate.Counts[severity]++
+		if rank, ok := severityRanks[severity]; ok && rank >= threshold {
+			gate.Failing++
+		}
+	}
+	gate.Passed = gate.Failing == 0
+	if gate.Passed {
+		gate.Decision = fmt.Sprintf("PASS: no finding at or above %s", failOn)
+	} else {
+		gate.Decision = fmt.Sprintf("FAIL: %d finding(s) at or above %s", gate.Failing, failOn)
+	}
+	return gate
+}
+
+// auditGate evaluates the remaining issues of an audit; accepted risks and the issues suppressed
+// by the baseline do not count
+func auditGate(failOn string, output AuditSecurityOutput) *GateDecision {
+	severities := make([]string, 0, len(output.Issues))
+	for _, issue := range output.Issues {
+		if !issue.Suppressed {
+			severities = append(severities, issue.Severity)
+		}
+	}
+	return evaluateGate(failOn, severities)
+}
@@ -0,0 +1,48 @@
+package validation
+
+import (
+	"context"
+	"testing"
+)
+
+func TestEvaluateGate(t *testing.T) {
+	severities := []string{"critical", "high", "medium", "medium", "low"}
+	tests := []struct {
+		failOn  string
+		passed  bool
+		failing int
+	}{
+		{"critical", false, 1},
+		{"high", false, 2},
+		{"medium", false, 4},
+		{"info", false, 5},
+	}
+	for _, tt := range tests {
+		gate := evaluateGate(tt.failOn, severities)
+		if gate.Passed != tt.passed || gate.Failing != tt.failing {
+			t.Errorf("evaluateGate(%s) = %+v", tt.failOn, gate)
+		}
+		if gate.Counts["medium"] != 2 {
+			t.Errorf("Expected 2 medium findings, got %v", gate.Counts)
+		}
+	}
+	if gate := evaluateGate("high", []string{"medium", "low"}); !gate.Passed {
+		t.Errorf("Expected the gate to pass below the threshold, got %+v", gate)
+	}
+}
+
+func TestParseFailOn(t *testing.T) {
+	if got, err := parseFailOn(" HIGH "); err != nil || got != "high" {
+		t.Errorf("parseFailOn(HIGH) = %q, %v", got, err)
+	}
+	if _, err := parseFailOn("severe"); err == nil {
+		t.Error("Expected an error for an unknown severity")
+	}
+}
+
+func TestAuditSecurity_InvalidFailOn(t *testing.T) {
+	_, _, err := AuditSecurity(context.Background(), nil, AuditSecurityInput{Config: `{"version": 3}`, FailOn: "severe"})
+	if err == nil {
+		t.Error("Expected an error for an invalid fail_on")
+	}
+}
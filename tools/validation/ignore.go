@@ -0,0 +1,246 @@
+package validation
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// IgnoreFileName is the exceptions file looked up next to the configuration and in the
+// working directory
+const IgnoreFileName = ".krakend-mcp-ignore"
+
+// Findings that can never be accepted: the configuration cannot be read or KrakenD cannot start
+var unsuppressibleCodes = map[string]bool{
+	"FILE_READ_ERROR":      true,
+	"INVALID_JSON":         true,
+	"INVALID_YAML":         true,
+	"INVALID_TOML":         true,
+	"FC_TEMPLATE_ERROR":    true,
+	"KRAKEND_CHECK_FAILED": true,
+}
+
+// auditRulePattern matches the rule IDs of krakend audit (e.g. 2.1.3)
+var auditRulePattern = regexp.MustCompile(`\b\d+\.\d+\.\d+\b`)
+
+// IgnoreRule is an exception accepting the findings of a rule, optionally under a path
+type IgnoreRule struct {
+	Rule    string `yaml:"rule" json:"rule"`                     // Audit rule ID or validation code
+	Path    string `yaml:"path,omitempty" json:"path,omitempty"` // JSON path prefix, * matches anything
+	Reason  string `yaml:"reason" json:"reason"`
+	Expires string `yaml:"expires" json:"expires"` // YYYY-MM-DD, the exception applies through that day
+}
+
+// ignoreFile is the content of an exceptions file, in YAML or JSON
+type ignoreFile struct {
+	Exceptions []IgnoreRule `yaml:"exceptions"`
+}
+
+// AcceptedRisk is a finding suppressed by an exception
+type AcceptedRisk struct {
+	Rule     string `json:"rule"`
+	Severity string `json:"severity,omitempty"`
+	Location string `json:"location,omitempty"`
+	Finding  string `json:"finding"`
+	Reason   string `json:"reason"`
+	Expires  string `json:"expires"`
+}
+
+// ExceptionsReport lists what an exceptions file suppressed
+type ExceptionsReport struct {
+	File          string         `json:"file"`
+	AcceptedRisks []AcceptedRisk `json:"accepted_risks"`
+	Expired       []IgnoreRule   `json:"expired,omitempty"` // No longer applied, review or renew them
+}
+
+// exceptions are the loaded entries of an exceptions file
+type exceptions struct {
+	file    string
+	active  []IgnoreRule
+	expired []IgnoreRule
+}
+
+// findIgnoreFile returns the exceptions file to use: the explicit one, or the default
+// file next to the configuration or in the working directory. An empty result means none.
+func findIgnoreFile(config, explicit string) (string, error) {
+	return findConfigCompanion(config, explicit, IgnoreFileName, "ignore file")
+}
+
+// findConfigCompanion returns the explicit file, or the file called name next to the
+// configuration or in the working directory. An empty result means none.
+func findConfigCompanion(config, explicit, name, kind string) (string, error) {
+	if explicit != "" {
+		if _, err := os.Stat(explicit); err != nil {
+			return "", fmt.Errorf("%s not found: %s", kind, explicit)
+		}
+		return explicit, nil
+	}
+	candidates := []string{name}
+	if isFilePath(config) {
+		candidates = append([]string{filepath.Join(filepath.Dir(config), name)}, candidates...)
+	}
+	for _, candidate := range candidates {
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate, nil
+		}
+	}
+	return "", nil
+}
+
+// loadExceptions reads an exceptions file, splitting active and expired entries
+func loadExceptions(file string, now time.Time) (*exceptions, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ignore file: %w", err)
+	}
+	var parsed ignoreFile
+	if err := yaml.Unmarshal(data, &parsed); err != nil {
+		return nil, fmt.Errorf("invalid ignore file %s: %w", file, err)
+	}
+
+	result := &exceptions{file: file}
+	today := now.Format(time.DateOnly)
+	for i, entry := range parsed.Exceptions {
+		if entry.Rule == "" {
+			return nil, fmt.Errorf("invalid ignore file %s: exception %d has no rule", file, i)
+		}
+		// Exceptions must be revisited: an expiry date is mandatory
+		if _, err := time.Parse(time.DateOnly, entry.Expires); err != nil {
+			return nil, fmt.Errorf("invalid ignore file %s: exception %d (%s) needs an expires date as YYYY-MM-DD", file, i, entry.Rule)
+		}
+		if entry.Expires < today {
+			result.expired = append(result.expired, entry)
+			continue
+		}
+		result.active = append(result.active, entry)
+	}
+	return result, nil
+}
+
+// match returns the active exception covering a finding, if any
+func (e *exceptions) match(rule, location string) (IgnoreRule, bool) {
+	if rule == "" || unsuppressibleCodes[rule] {
+		return IgnoreRule{}, false
+	}
+	for _, entry := range e.active {
+		if entry.Rule == rule && locationMatches(entry.Path, location) {
+			return entry, true
+		}
+	}
+	return IgnoreRule{}, false
+}
+
+// report starts the report of an exceptions file
+func (e *exceptions) report() *ExceptionsReport {
+	return &ExceptionsReport{File: e.file, AcceptedRisks: []AcceptedRisk{}, Expired: e.expired}
+}
+
+// locationMatches tells whether a JSON path is under an exception path. An empty pattern
+// matches everything, * matches any characters and $.endpoints[0] covers $.endpoints[0].backend[1].
+func locationMatches(pattern, location string) bool {
+	if pattern == "" {
+		return true
+	}
+	parts := strings.Split(pattern, "*")
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+	re := regexp.MustCompile(`^` + strings.Join(parts, `.*`) + `([.\[].*)?$`)
+	return re.MatchString(location)
+}
+
+// resolveExceptions finds and loads the exceptions applying to a configuration
+func resolveExceptions(config, explicit string) (*exceptions, error) {
+	file, err := findIgnoreFile(config, explicit)
+	if err != nil || file == "" {
+		return nil, err
+	}
+	return loadExceptions(file, time.Now())
+}
+
+// applyAuditExceptions moves the accepted issues of an audit to its exceptions report
+func applyAuditExceptions(output *AuditSecurityOutput, e *exceptions) {
+	report := e.report()
+	issues := []SecurityIssue{}
+	for _, issue := range output.Issues {
+		entry, ok := e.match(issue.Rule, issue.Location)
+		if !ok {
+			issues = append(issues, issue)
+			continue
+		}
+		report.AcceptedRisks = append(report.AcceptedRisks, AcceptedRisk{
+			Rule:     issue.Rule,
+			Severity: issue.Severity,
+			Location: issue.Location,
+			Finding:  issue.Title,
+			Reason:   entry.Reason,
+			Expires:  entry.Expires,
+		})
+	}
+	output.Exceptions = report
+	if len(report.AcceptedRisks) == 0 {
+		return
+	}
+	output.Issues = issues
+	output.Valid = true
+	for _, issue := range issues {
+		if issue.Severity == "critical" || issue.Severity == "high" {
+			output.Valid = false
+			break
+		}
+	}
+	output.Summary += fmt.Sprintf(". %d issue(s) accepted as risks by %s", len(report.AcceptedRisks), e.file)
+}
+
+// applyValidationExceptions moves the accepted errors and warnings of a validation to its
+// exceptions report. Errors preventing KrakenD from starting are never accepted.
+func applyValidationExceptions(result *ValidationResult, e *exceptions) {
+	report := e.report()
+	errs := []ValidationError{}
+	for _, ve := range result.Errors {
+		entry, ok := e.match(ve.Code, ve.Path)
+		if !ok || ve.Pass == PassCheck {
+			errs = append(errs, ve)
+			continue
+		}
+		report.AcceptedRisks = append(report.AcceptedRisks, AcceptedRisk{
+			Rule:     ve.Code,
+			Severity: "error",
+			Location: ve.Path,
+			Finding:  ve.Message,
+			Reason:   entry.Reason,
+			Expires:  entry.Expires,
+		})
+	}
+	warnings := []ValidationWarning{}
+	for _, w := range result.Warnings {
+		entry, ok := e.match(w.Code, w.Path)
+		if !ok {
+			warnings = append(warnings, w)
+			continue
+		}
+		report.AcceptedRisks = append(report.AcceptedRisks, AcceptedRisk{
+			Rule:     w.Code,
+			Severity: w.Level,
+			Location: w.Path,
+			Finding:  w.Message,
+			Reason:   entry.Reason,
+			Expires:  entry.Expires,
+		})
+	}
+	result.Exceptions = report
+	if len(report.AcceptedRisks) == 0 {
+		return
+	}
+	if len(errs) == 0 && len(result.Errors) > 0 {
+		result.Valid = true
+	}
+	result.Errors = errs
+	result.Warnings = warnings
+	result.Summary += fmt.Sprintf(". %d finding(s) accepted as risks by %s", len(report.AcceptedRisks), e.file)
+}
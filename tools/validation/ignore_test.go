@@ -0,0 +1,151 @@
+package validation
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeIgnoreFile(t *testing.T, dir, content string) string {
+	t.Helper()
+	file := filepath.Join(dir, IgnoreFileName)
+	if err := os.WriteFile(file, []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to write ignore file: %v", err)
+	}
+	return file
+}
+
+func TestLoadExceptions(t *testing.T) {
+	file := writeIgnoreFile(t, t.TempDir(), `exceptions:
+  - rule: cors-missing
+    reason: Server-to-server API
+    expires: 2026-06-30
+  - rule: "2.1.3"
+    path: $.endpoints[*]
+    reason: Internal network only
+    expires: 2026-05-31
+`)
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	e, err := loadExceptions(file, now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(e.active) != 1 || e.active[0].Rule != "cors-missing" {
+		t.Errorf("Expected cors-missing to be active, got %+v", e.active)
+	}
+	if len(e.expired) != 1 || e.expired[0].Rule != "2.1.3" {
+		t.Errorf("Expected 2.1.3 to be expired, got %+v", e.expired)
+	}
+
+	// The expiry day itself is still covered
+	e, _ = loadExceptions(file, time.Date(2026, 6, 30, 23, 0, 0, 0, time.UTC))
+	if len(e.active) != 1 {
+		t.Errorf("Expected the exception to apply on its expiry day, got %+v", e.active)
+	}
+}
+
+func TestLoadExceptions_RequiresExpiry(t *testing.T) {
+	file := writeIgnoreFile(t, t.TempDir(), `{"exceptions": [{"rule": "cors-missing", "reason": "forever"}]}`)
+	if _, err := loadExceptions(file, time.Now()); err == nil || !strings.Contains(err.Error(), "expires") {
+		t.Errorf("Expected a missing expiry error, got %v", err)
+	}
+}
+
+func TestLocationMatches(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		location string
+		want     bool
+	}{
+		{"", "$.endpoints[0]", true},
+		{"", "", true},
+		{"$.endpoints[1]", "$.endpoints[1]", true},
+		{"$.endpoints[1]", "$.endpoints[1].backend[0]", true},
+		{"$.endpoints[1]", "$.endpoints[10]", false},
+		{"$.endpoints[*]", "$.endpoints[7]", true},
+		{"$.endpoints[*]", "$.extra_config", false},
+		{"$.extra_config", "", false},
+	}
+	for _, tt := range tests {
+		if got := locationMatches(tt.pattern, tt.location); got != tt.want {
+			t.Errorf("locationMatches(%q, %q) = %v, want %v", tt.pattern, tt.location, got, tt.want)
+		}
+	}
+}
+
+func TestApplyAuditExceptions(t *testing.T) {
+	config := `{"version": 3, "debug_endpoint": true, "endpoints": [
+		{"endpoint": "/a", "method": "POST", "backend": [{"url_pattern": "/a"}]},
+		{"endpoint": "/b", "method": "DELETE", "backend": [{"url_pattern": "/b"}]}
+	]}`
+	output, err := auditWithBasicChecks(config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	e := &exceptions{file: IgnoreFileName, active: []IgnoreRule{
+		{Rule: "endpoint-no-auth", Path: "$.endpoints[0]", Reason: "Protected by the network", Expires: "2099-01-01"},
+		{Rule: "debug-endpoint-enabled", Reason: "Staging only", Expires: "2099-01-01"},
+	}}
+	applyAuditExceptions(output, e)
+
+	if output.Exceptions == nil || len(output.Exceptions.AcceptedRisks) != 2 {
+		t.Fatalf("Expected 2 accepted risks, got %+v", output.Exceptions)
+	}
+	for _, issue := range output.Issues {
+		if issue.Rule == "debug-endpoint-enabled" || issue.Location == "$.endpoints[0]" {
+			t.Errorf("Accepted issue still reported: %+v", issue)
+		}
+	}
+	// The DELETE endpoint is still unauthenticated
+	if output.Valid {
+		t.Error("Expected the audit to stay invalid with a remaining high issue")
+	}
+	if !strings.Contains(output.Summary, "2 issue(s) accepted") {
+		t.Errorf("Summary should mention the accepted risks, got %q", output.Summary)
+	}
+}
+
+func TestApplyValidationExceptions(t *testing.T) {
+	e := &exceptions{file: IgnoreFileName, active: []IgnoreRule{
+		{Rule: "KRAKEND_LINT_FAILED", Reason: "Custom plugin settings", Expires: "2099-01-01"},
+		{Rule: "KRAKEND_CHECK_FAILED", Reason: "Not allowed", Expires: "2099-01-01"},
+		{Rule: WarningVersionNotNumber, Reason: "Legacy", Expires: "2099-01-01"},
+	}}
+
+	lint := ValidationResult{
+		Errors:   []ValidationError{{Code: "KRAKEND_LINT_FAILED", Pass: PassLint, Message: "unknown property"}},
+		Warnings: []ValidationWarning{newWarning(WarningVersionNotNumber, "$.version", "Version should be a number")},
+	}
+	applyValidationExceptions(&lint, e)
+	if !lint.Valid || len(lint.Errors) != 0 || len(lint.Warnings) != 0 {
+		t.Errorf("Expected lint findings to be accepted, got %+v", lint)
+	}
+	if len(lint.Exceptions.AcceptedRisks) != 2 {
+		t.Errorf("Expected 2 accepted risks, got %+v", lint.Exceptions.AcceptedRisks)
+	}
+
+	check := ValidationResult{Errors: []ValidationError{{Code: "KRAKEND_CHECK_FAILED", Pass: PassCheck, Message: "cannot start"}}}
+	applyValidationExceptions(&check, e)
+	if check.Valid || len(check.Errors) != 1 {
+		t.Errorf("Blocking errors must never be accepted, got %+v", check)
+	}
+}
+
+func TestFindIgnoreFile(t *testing.T) {
+	dir := t.TempDir()
+	config := filepath.Join(dir, "krakend.json")
+	if err := os.WriteFile(config, []byte(`{"version": 3}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	want := writeIgnoreFile(t, dir, "exceptions: []\n")
+
+	got, err := findIgnoreFile(config, "")
+	if err != nil || got != want {
+		t.Errorf("Expected %s next to the config, got %q (%v)", want, got, err)
+	}
+	if _, err := findIgnoreFile(config, filepath.Join(dir, "missing")); err == nil {
+		t.Error("Expected an error for a missing explicit ignore file")
+	}
+}
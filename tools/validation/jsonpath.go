@@ -0,0 +1,560 @@
+package validation
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// jsonPath is a compiled JSONPath expression. The supported subset covers the usual configuration
+// queries: children (.name, ['name', 'other']), wildcards, indexes (negative from the end),
+// descendants (..) and filters ([?(@.field == 'value' && !@.other)]) with the ==, !=, <, <=, >, >=
+// and =~ /regexp/ operators.
+type jsonPath []pathSegment
+
+// Kinds of a pathSegment
+const (
+	segmentChild = iota
+	segmentWildcard
+	segmentIndex
+	segmentFilter
+)
+
+// pathSegment is a step of a JSONPath, applied to every node matched by the previous one
+type pathSegment struct {
+	kind       int
+	names      []string
+	index      int
+	filter     filterExpr
+	descendant bool // Applied to the node and all its descendants (..)
+}
+
+// jsonPathMatch is a node matched by a JSONPath, with its normalized location
+type jsonPathMatch struct {
+	Location string
+	Value    interface{}
+}
+
+// identifierPattern matches the keys written with dot notation in locations
+var identifierPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// compileJSONPath parses an absolute JSONPath expression
+func compileJSONPath(expr string) (jsonPath, error) {
+	p := &jsonPathParser{s: strings.TrimSpace(expr)}
+	if !p.consume("$") {
+		return nil, fmt.Errorf("JSONPath %q must start with $", expr)
+	}
+	path, err := p.segments()
+	if err == nil && p.pos < len(p.s) {
+		err = p.errorf("unexpected %q", p.s[p.pos:])
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSONPath %q: %w", expr, err)
+	}
+	return path, nil
+}
+
+// find returns the nodes of root matched by the path, in document order (object keys sorted)
+func (path jsonPath) find(root interface{}) []jsonPathMatch {
+	return path.eval(root, jsonPathMatch{Location: "$", Value: root})
+}
+
+// eval applies the path from a node, root resolving the $ of filters
+func (path jsonPath) eval(root interface{}, from jsonPathMatch) []jsonPathMatch {
+	current := []jsonPathMatch{from}
+	for _, segment := range path {
+		var next []jsonPathMatch
+		for _, node := range current {
+			if segment.descendant {
+				for _, d := range descendants(node) {
+					next = append(next, segment.apply(root, d)...)
+				}
+				continue
+			}
+			next = append(next, segment.apply(root, node)...)
+		}
+		current = next
+	}
+	return current
+}
+
+// apply selects the children of a node matching the segment
+func (s pathSegment) apply(root interface{}, node jsonPathMatch) []jsonPathMatch {
+	var matches []jsonPathMatch
+	switch s.kind {
+	case segmentChild:
+		if object, ok := node.Value.(map[string]interface{}); ok {
+			for _, name := range s.names {
+				if value, ok := object[name]; ok {
+					matches = append(matches, jsonPathMatch{childLocation(node.Location, name), value})
+				}
+			}
+		}
+	case segmentIndex:
+		if array, ok := node.Value.([]interface{}); ok {
+			i := s.index
+			if i < 0 {
+				i += len(array)
+			}
+			if i >= 0 && i < len(array) {
+				matches = append(matches, jsonPathMatch{fmt.Sprintf("%s[%d]", node.Location, i), array[i]})
+			}
+		}
+	case segmentWildcard, segmentFilter:
+		for _, child := range children(node) {
+			if s.kind == segmentWildcard || s.filter.eval(root, child) {
+				matches = append(matches, child)
+			}
+		}
+	}
+	return matches
+}
+
+// children lists the members of an object, by key, or the elements of an array
+func children(node jsonPathMatch) []jsonPathMatch {
+	var result []jsonPathMatch
+	switch value := node.Value.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(value))
+		for k := range value {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			result = append(result, jsonPathMatch{childLocation(node.Location, k), value[k]})
+		}
+	case []interface{}:
+		for i, item := range value {
+			result = append(result, jsonPathMatch{fmt.Sprintf("%s[%d]", node.Location, i), item})
+		}
+	}
+	return result
+}
+
+// descendants lists a node followed by all the nodes under it
+func descendants(node jsonPathMatch) []jsonPathMatch {
+	result := []jsonPathMatch{node}
+	for _, child := range children(node) {
+		result = append(result, descendants(child)...)
+	}
+	return result
+}
+
+// childLocation is the location of a member, $.name or $['namespace/name']
+func childLocation(parent, name string) string {
+	if identifierPattern.MatchString(name) {
+		return parent + "." + name
+	}
+	return parent + "['" + strings.ReplaceAll(name, "'", `\'`) + "']"
+}
+
+// filterExpr is a filter condition, evaluated on every child of the filtered node
+type filterExpr interface {
+	eval(root interface{}, node jsonPathMatch) bool
+}
+
+// filterOperand is a side of a comparison: a path from the current node (@) or the root ($), or a literal
+type filterOperand struct {
+	path     jsonPath
+	relative bool
+	literal  interface{}
+	regexp   *regexp.Regexp
+}
+
+// values are the values of an operand for a node, none when its path matches nothing
+func (o filterOperand) values(root interface{}, node jsonPathMatch) []interface{} {
+	if o.path == nil {
+		return []interface{}{o.literal}
+	}
+	from := jsonPathMatch{Location: "$", Value: root}
+	if o.relative {
+		from = node
+	}
+	var values []interface{}
+	for _, match := range o.path.eval(root, from) {
+		values = append(values, match.Value)
+	}
+	return values
+}
+
+type (
+	existsExpr  struct{ operand filterOperand }
+	notExpr     struct{ expr filterExpr }
+	logicalExpr struct {
+		and         bool
+		left, right filterExpr
+	}
+	compareExpr struct {
+		op          string
+		left, right filterOperand
+	}
+)
+
+func (e existsExpr) eval(root interface{}, node jsonPathMatch) bool {
+	return len(e.operand.values(root, node)) > 0
+}
+
+func (e notExpr) eval(root interface{}, node jsonPathMatch) bool {
+	return !e.expr.eval(root, node)
+}
+
+func (e logicalExpr) eval(root interface{}, node jsonPathMatch) bool {
+	if e.and {
+		return e.left.eval(root, node) && e.right.eval(root, node)
+	}
+	return e.left.eval(root, node) || e.right.eval(root, node)
+}
+
+// eval compares the values of both sides: true when any pair satisfies the operator. Missing
+// values are only equal to each other, so != holds when a single side is missing.
+func (e compareExpr) eval(root interface{}, node jsonPathMatch) bool {
+	left := e.left.values(root, node)
+	if e.op == "=~" {
+		for _, l := range left {
+			if s, ok := l.(string); ok && e.right.regexp.MatchString(s) {
+				return true
+			}
+		}
+		return false
+	}
+	right := e.right.values(root, node)
+	if e.op == "==" || e.op == "!=" {
+		equal := len(left) == 0 && len(right) == 0
+		for _, l := range left {
+			for _, r := range right {
+				equal = equal || reflect.DeepEqual(l, r)
+			}
+		}
+		return equal == (e.op == "==")
+	}
+	for _, l := range left {
+		for _, r := range right {
+			if c, ok := compareOrdered(l, r); ok && orderSatisfies(e.op, c) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// compareOrdered compares two numbers or two strings
+func compareOrdered(a, b interface{}) (int, bool) {
+	switch x := a.(type) {
+	case float64:
+		if y, ok := b.(float64); ok {
+			switch {
+			case x < y:
+				return -1, true
+			case x > y:
+				return 1, true
+			}
+			return 0, true
+		}
+	case string:
+		if y, ok := b.(string); ok {
+			return strings.Compare(x, y), true
+		}
+	}
+	return 0, false
+}
+
+func orderSatisfies(op string, c int) bool {
+	switch op {
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	case ">":
+		return c > 0
+	case ">=":
+		return c >= 0
+	}
+	return false
+}
+
+// jsonPathParser reads a JSONPath expression, filters included
+type jsonPathParser struct {
+	s   string
+	pos int
+}
+
+func (p *jsonPathParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("position %d: %s", p.pos, fmt.Sprintf(format, args...))
+}
+
+func (p *jsonPathParser) skipSpaces() {
+	for p.pos < len(p.s) && p.s[p.pos] == ' ' {
+		p.pos++
+	}
+}
+
+func (p *jsonPathParser) peek(prefix string) bool {
+	return strings.HasPrefix(p.s[p.pos:], prefix)
+}
+
+func (p *jsonPathParser) consume(prefix string) bool {
+	if p.peek(prefix) {
+		p.pos += len(prefix)
+		return true
+	}
+	return false
+}
+
+// segments reads the steps of a path, up to the first character that cannot continue it
+func (p *jsonPathParser) segments() (jsonPath, error) {
+	var path jsonPath
+	for {
+		var segment pathSegment
+		var err error
+		switch {
+		case p.consume(".."):
+			if p.peek("[") {
+				segment, err = p.bracket()
+			} else {
+				segment, err = p.dotted()
+			}
+			segment.descendant = true
+		case p.consume("."):
+			segment, err = p.dotted()
+		case p.peek("["):
+			segment, err = p.bracket()
+		default:
+			return path, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		path = append(path, segment)
+	}
+}
+
+// dotted reads the member name or wildcard following a dot
+func (p *jsonPathParser) dotted() (pathSegment, error) {
+	if p.consume("*") {
+		return pathSegment{kind: segmentWildcard}, nil
+	}
+	start := p.pos
+	for p.pos < len(p.s) && isNameChar(p.s[p.pos]) {
+		p.pos++
+	}
+	if p.pos == start {
+		return pathSegment{}, p.errorf("expected a member name, use ['name'] for names with other characters")
+	}
+	return pathSegment{kind: segmentChild, names: []string{p.s[start:p.pos]}}, nil
+}
+
+func isNameChar(c byte) bool {
+	return c == '_' || c == '-' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// bracket reads a [...] segment: names, an index, a wildcard or a filter
+func (p *jsonPathParser) bracket() (pathSegment, error) {
+	p.consume("[")
+	p.skipSpaces()
+	var segment pathSegment
+	switch {
+	case p.consume("*"):
+		segment.kind = segmentWildcard
+	case p.consume("?"):
+		p.skipSpaces()
+		filter, err := p.orExpr()
+		if err != nil {
+			return pathSegment{}, err
+		}
+		segment = pathSegment{kind: segmentFilter, filter: filter}
+	case p.peek("'") || p.peek(`"`):
+		segment.kind = segmentChild
+		for {
+			name, err := p.quoted()
+			if err != nil {
+				return pathSegment{}, err
+			}
+			segment.names = append(segment.names, name)
+			p.skipSpaces()
+			if !p.consume(",") {
+				break
+			}
+			p.skipSpaces()
+		}
+	default:
+		start := p.pos
+		p.consume("-")
+		for p.pos < len(p.s) && p.s[p.pos] >= '0' && p.s[p.pos] <= '9' {
+			p.pos++
+		}
+		index, err := strconv.Atoi(p.s[start:p.pos])
+		if err != nil {
+			return pathSegment{}, p.errorf("expected a name, an index, * or a ?filter")
+		}
+		segment = pathSegment{kind: segmentIndex, index: index}
+	}
+	p.skipSpaces()
+	if !p.consume("]") {
+		return pathSegment{}, p.errorf("expected ]")
+	}
+	return segment, nil
+}
+
+// quoted reads a single or double quoted string
+func (p *jsonPathParser) quoted() (string, error) {
+	quote := p.s[p.pos]
+	p.pos++
+	var b strings.Builder
+	for p.pos < len(p.s) {
+		c := p.s[p.pos]
+		p.pos++
+		switch {
+		case c == '\\' && p.pos < len(p.s):
+			b.WriteByte(p.s[p.pos])
+			p.pos++
+		case c == quote:
+			return b.String(), nil
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", p.errorf("unterminated string")
+}
+
+func (p *jsonPathParser) orExpr() (filterExpr, error) {
+	left, err := p.andExpr()
+	for err == nil {
+		p.skipSpaces()
+		if !p.consume("||") {
+			return left, nil
+		}
+		var right filterExpr
+		if right, err = p.andExpr(); err == nil {
+			left = logicalExpr{and: false, left: left, right: right}
+		}
+	}
+	return nil, err
+}
+
+func (p *jsonPathParser) andExpr() (filterExpr, error) {
+	left, err := p.unaryExpr()
+	for err == nil {
+		p.skipSpaces()
+		if !p.consume("&&") {
+			return left, nil
+		}
+		var right filterExpr
+		if right, err = p.unaryExpr(); err == nil {
+			left = logicalExpr{and: true, left: left, right: right}
+		}
+	}
+	return nil, err
+}
+
+func (p *jsonPathParser) unaryExpr() (filterExpr, error) {
+	p.skipSpaces()
+	switch {
+	case p.consume("!"):
+		expr, err := p.unaryExpr()
+		if err != nil {
+			return nil, err
+		}
+		return notExpr{expr}, nil
+	case p.consume("("):
+		expr, err := p.orExpr()
+		if err != nil {
+			return nil, err
+		}
+		p.skipSpaces()
+		if !p.consume(")") {
+			return nil, p.errorf("expected )")
+		}
+		return expr, nil
+	}
+	left, err := p.operand()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpaces()
+	for _, op := range []string{"==", "!=", "<=", ">=", "=~", "<", ">"} {
+		if !p.consume(op) {
+			continue
+		}
+		p.skipSpaces()
+		var right filterOperand
+		if op == "=~" {
+			right, err = p.regexpLiteral()
+		} else {
+			right, err = p.operand()
+		}
+		if err != nil {
+			return nil, err
+		}
+		return compareExpr{op: op, left: left, right: right}, nil
+	}
+	if left.path == nil {
+		return nil, p.errorf("a literal needs a comparison")
+	}
+	return existsExpr{left}, nil
+}
+
+// operand reads a path from @ or $, or a string, number, true, false or null literal
+func (p *jsonPathParser) operand() (filterOperand, error) {
+	switch {
+	case p.peek("@") || p.peek("$"):
+		relative := p.s[p.pos] == '@'
+		p.pos++
+		path, err := p.segments()
+		if err != nil {
+			return filterOperand{}, err
+		}
+		if path == nil {
+			path = jsonPath{}
+		}
+		return filterOperand{path: path, relative: relative}, nil
+	case p.peek("'") || p.peek(`"`):
+		s, err := p.quoted()
+		return filterOperand{literal: s}, err
+	case p.consume("true"):
+		return filterOperand{literal: true}, nil
+	case p.consume("false"):
+		return filterOperand{literal: false}, nil
+	case p.consume("null"):
+		return filterOperand{literal: nil}, nil
+	}
+	start := p.pos
+	for p.pos < len(p.s) && strings.IndexByte("+-.0123456789eE", p.s[p.pos]) >= 0 {
+		p.pos++
+	}
+	number, err := strconv.ParseFloat(p.s[start:p.pos], 64)
+	if err != nil {
+		p.pos = start
+		return filterOperand{}, p.errorf("expected @, $ or a literal")
+	}
+	return filterOperand{literal: number}, nil
+}
+
+// regexpLiteral reads the /pattern/ of =~, with an optional i flag
+func (p *jsonPathParser) regexpLiteral() (filterOperand, error) {
+	if !p.consume("/") {
+		return filterOperand{}, p.errorf("expected /regexp/ after =~")
+	}
+	var b strings.Builder
+	for p.pos < len(p.s) && p.s[p.pos] != '/' {
+		if p.s[p.pos] == '\\' && p.pos+1 < len(p.s) && p.s[p.pos+1] == '/' {
+			p.pos++
+		}
+		b.WriteByte(p.s[p.pos])
+		p.pos++
+	}
+	if !p.consume("/") {
+		return filterOperand{}, p.errorf("unterminated regexp")
+	}
+	pattern := b.String()
+	if p.consume("i") {
+		pattern = "(?i)" + pattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return filterOperand{}, p.errorf("invalid regexp: %v", err)
+	}
+	return filterOperand{regexp: re}, nil
+}
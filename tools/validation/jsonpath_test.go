@@ -0,0 +1,81 @@
+package validation
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const jsonPathConfig = `{
+	"version": 3,
+	"timeout": "3s",
+	"extra_config": {"security/cors": {"allow_origins": ["*"]}},
+	"endpoints": [
+		{"endpoint": "/users", "input_headers": ["Authorization"], "backend": [{"url_pattern": "/users", "host": ["http://users"]}]},
+		{"endpoint": "/orders", "method": "POST", "timeout": "10s", "backend": [{"url_pattern": "/orders", "host": ["http://orders"]}]},
+		{"endpoint": "/admin", "input_headers": ["*"], "backend": [{"url_pattern": "/admin"}]}
+	]
+}`
+
+func TestJSONPathFind(t *testing.T) {
+	var config map[string]interface{}
+	if err := json.Unmarshal([]byte(jsonPathConfig), &config); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		path string
+		want []string
+	}{
+		{"$", []string{"$"}},
+		{"$.timeout", []string{"$.timeout"}},
+		{"$.extra_config['security/cors'].allow_origins[0]", []string{"$.extra_config['security/cors'].allow_origins[0]"}},
+		{"$.endpoints[-1].endpoint", []string{"$.endpoints[2].endpoint"}},
+		{"$.endpoints[*].method", []string{"$.endpoints[1].method"}},
+		{"$..host", []string{"$.endpoints[0].backend[0].host", "$.endpoints[1].backend[0].host"}},
+		{"$.endpoints[0]['endpoint', 'method']", []string{"$.endpoints[0].endpoint"}},
+		{"$.endpoints[?(!@.input_headers)]", []string{"$.endpoints[1]"}},
+		{"$.endpoints[?(@.input_headers[*] == '*')]", []string{"$.endpoints[2]"}},
+		{"$.endpoints[?(@.method != 'POST' && @.endpoint =~ /^\\/ADM/i)]", []string{"$.endpoints[2]"}},
+		{"$.endpoints[?(@.timeout == $.timeout || @.method == 'POST')].endpoint", []string{"$.endpoints[1].endpoint"}},
+		{"$.endpoints[?(@.backend[0].host)].endpoint", []string{"$.endpoints[0].endpoint", "$.endpoints[1].endpoint"}},
+		{"$.endpoints[?(@.endpoint > '/p')]", []string{"$.endpoints[0]"}},
+		{"$.version", []string{"$.version"}},
+		{"$.missing[*]", nil},
+	}
+	for _, tt := range tests {
+		path, err := compileJSONPath(tt.path)
+		if err != nil {
+			t.Errorf("compileJSONPath(%q) error = %v", tt.path, err)
+			continue
+		}
+		var got []string
+		for _, match := range path.find(config) {
+			got = append(got, match.Location)
+		}
+		if strings.Join(got, " ") != strings.Join(tt.want, " ") {
+			t.Errorf("%s matched %v, want %v", tt.path, got, tt.want)
+		}
+	}
+
+	numbers, _ := compileJSONPath("$[?(@ >= 2)]")
+	if got := numbers.find([]interface{}{1.0, 2.0, 3.0}); len(got) != 2 || got[0].Location != "$[1]" {
+		t.Errorf("Expected the numbers from 2, got %+v", got)
+	}
+}
+
+func TestCompileJSONPath_Errors(t *testing.T) {
+	for _, path := range []string{
+		"endpoints[*]",
+		"$.endpoints[",
+		"$.extra_config.security/cors",
+		"$.endpoints[?(@.method == )]",
+		"$.endpoints[?(@.endpoint =~ 'users')]",
+		"$.endpoints[?(@.endpoint =~ /[/)]",
+		"$.endpoints[?('GET')]",
+		"$.endpoints['unterminated]",
+	} {
+		if _, err := compileJSONPath(path); err == nil {
+			t.Errorf("compileJSONPath(%q) should fail", path)
+		}
+	}
+}
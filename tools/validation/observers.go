@@ -0,0 +1,51 @@
+package validation
+
+import (
+	"strings"
+
+	"github.com/krakend/mcp-server/internal/events"
+)
+
+// ValidationObserver is notified with the tool input (JSON string or file path) and the result
+// of every successful validate_config call.
+type ValidationObserver func(config string, result ValidationResult)
+
+// AuditObserver is notified with the tool input (JSON string or file path) and the result
+// of every successful audit_security call.
+type AuditObserver func(config string, result AuditSecurityOutput)
+
+// AddValidationObserver registers an observer for validation results, delivered with the
+// events.ConfigValidated events
+func AddValidationObserver(o ValidationObserver) {
+	events.Subscribe(events.ConfigValidated, func(e events.Event) {
+		if result, ok := e.Data.(ValidationResult); ok {
+			o(e.Subject, result)
+		}
+	})
+}
+
+// AddAuditObserver registers an observer for security audit results, delivered with the
+// events.AuditFinished events
+func AddAuditObserver(o AuditObserver) {
+	events.Subscribe(events.AuditFinished, func(e events.Event) {
+		if result, ok := e.Data.(AuditSecurityOutput); ok {
+			o(e.Subject, result)
+		}
+	})
+}
+
+func notifyValidation(config string, result ValidationResult) {
+	events.Publish(events.ConfigValidated, config, result)
+}
+
+func notifyAudit(config string, result AuditSecurityOutput) {
+	events.Publish(events.AuditFinished, config, result)
+}
+
+// ExecutionMethod drops the details of a validation method, e.g. the image of "docker (image)"
+func ExecutionMethod(method string) string {
+	if fields := strings.Fields(method); len(fields) > 0 {
+		return fields[0]
+	}
+	return method
+}
@@ -0,0 +1,128 @@
+package validation
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// krakend check passes. A failing check pass means KrakenD cannot start with the
+// configuration; a failing lint pass means the configuration starts but does not
+// match the JSON schema of its version (unknown or misplaced settings, wrong types).
+const (
+	PassCheck = "check"
+	PassLint  = "lint"
+	PassDebug = "debug"
+)
+
+// checkPassFlags are the krakend check flags of each pass
+var checkPassFlags = map[string][]string{
+	PassCheck: nil,
+	PassLint:  {"-l"},
+	PassDebug: {"-d"},
+}
+
+// maxPassOutput caps the output kept per pass (debug output prints the whole parsed config)
+const maxPassOutput = 16 * 1024
+
+// CheckPass is the outcome of a krakend check invocation
+type CheckPass struct {
+	Name     string `json:"name"` // "check", "lint" or "debug"
+	Command  string `json:"command"`
+	Passed   bool   `json:"passed"`
+	Blocking bool   `json:"blocking"` // A failure prevents KrakenD from starting
+	Output   string `json:"output,omitempty"`
+
+	elapsed time.Duration // Runtime of the command, reported with include_timings
+}
+
+// checkCommand builds a krakend check command with the flags of a pass
+type checkCommand func(flags ...string) (*exec.Cmd, error)
+
+// runCheckPass runs a pass. Errors are only returned when the command could not run;
+// a non-zero exit status is a failed pass.
+func runCheckPass(ctx context.Context, name string, build checkCommand) (CheckPass, error) {
+	flags := checkPassFlags[name]
+	pass := CheckPass{
+		Name:     name,
+		Command:  strings.TrimSpace("krakend check " + strings.Join(flags, " ")),
+		Blocking: name == PassCheck,
+	}
+	cmd, err := build(flags...)
+	if err != nil {
+		return pass, err
+	}
+
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	start := time.Now()
+	err = runCommand(ctx, cmd)
+	pass.elapsed = time.Since(start)
+
+	var exitErr *exec.ExitError
+	if err != nil && !errors.As(err, &exitErr) {
+		return pass, err
+	}
+	pass.Passed = err == nil
+	pass.Output = output.String()
+	if len(pass.Output) > maxPassOutput {
+		pass.Output = pass.Output[:maxPassOutput] + "\n... (truncated)"
+	}
+	return pass, nil
+}
+
+// runCheckPasses lints the configuration. Lint includes the check, so when it fails a
+// plain check tells whether the failure blocks startup, and a debug check adds details
+// about blocking failures. The passes share a single CommandTimeout.
+func runCheckPasses(ctx context.Context, build checkCommand) ([]CheckPass, error) {
+	ctx, cancel := withCommandDeadline(ctx)
+	defer cancel()
+
+	lint, err := runCheckPass(ctx, PassLint, build)
+	if err != nil {
+		return nil, err
+	}
+	if lint.Passed {
+		check := CheckPass{Name: PassCheck, Command: "krakend check", Passed: true, Blocking: true}
+		return []CheckPass{check, lint}, nil
+	}
+
+	check, err := runCheckPass(ctx, PassCheck, build)
+	if err != nil {
+		return nil, err
+	}
+	passes := []CheckPass{check, lint}
+	if !check.Passed {
+		if debug, err := runCheckPass(ctx, PassDebug, build); err == nil {
+			passes = append(passes, debug)
+		}
+	}
+	return passes, nil
+}
+
+// applyCheckPasses records the passes in a result, attributing errors to the pass that
+// found them, and summarizes the outcome
+func applyCheckPasses(result *ValidationResult, passes []CheckPass, validatedWith string) {
+	result.Passes = passes
+	result.Valid = true
+	for _, pass := range passes {
+		if pass.Name == PassCheck && !pass.Passed {
+			result.Valid = false
+			result.Errors = append(result.Errors, ValidationError{Message: pass.Output, Code: "KRAKEND_CHECK_FAILED", Pass: PassCheck})
+			result.Summary = fmt.Sprintf("KrakenD validation failed (%s): KrakenD cannot start with this configuration", validatedWith)
+			return
+		}
+	}
+	for _, pass := range passes {
+		if pass.Name == PassLint && !pass.Passed {
+			result.Valid = false
+			result.Errors = append(result.Errors, ValidationError{Message: pass.Output, Code: "KRAKEND_LINT_FAILED", Pass: PassLint})
+			result.Summary = fmt.Sprintf("KrakenD lint failed (%s): KrakenD starts, but the configuration does not match the schema", validatedWith)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package validation
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+// fakeKrakenD returns a check command running script in place of krakend
+func fakeKrakenD(t *testing.T, script string) checkCommand {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("shell script fake")
+	}
+	path := filepath.Join(t.TempDir(), "krakend")
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return func(flags ...string) (*exec.Cmd, error) {
+		return exec.Command(path, append([]string{"check", "-c", "krakend.json"}, flags...)...), nil
+	}
+}
+
+func TestRunCheckPasses_Valid(t *testing.T) {
+	passes, err := runCheckPasses(context.Background(), fakeKrakenD(t, "echo Syntax OK!"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := &ValidationResult{}
+	applyCheckPasses(result, passes, "native")
+	if !result.Valid || len(passes) != 2 || !passes[0].Passed || !passes[1].Passed {
+		t.Errorf("Expected both passes to succeed, got %+v", passes)
+	}
+}
+
+func TestRunCheckPasses_LintOnly(t *testing.T) {
+	// Fails only when linting
+	passes, err := runCheckPasses(context.Background(), fakeKrakenD(t, `for a in "$@"; do [ "$a" = "-l" ] && echo "unknown property" && exit 1; done; echo Syntax OK!`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := &ValidationResult{}
+	applyCheckPasses(result, passes, "native")
+
+	if result.Valid || len(result.Errors) != 1 || result.Errors[0].Pass != PassLint || result.Errors[0].Code != "KRAKEND_LINT_FAILED" {
+		t.Errorf("Expected a non-blocking lint error, got %+v", result.Errors)
+	}
+	if len(passes) != 2 {
+		t.Errorf("No debug pass expected for lint failures, got %+v", passes)
+	}
+}
+
+func TestRunCheckPasses_Blocking(t *testing.T) {
+	passes, err := runCheckPasses(context.Background(), fakeKrakenD(t, `for a in "$@"; do [ "$a" = "-d" ] && echo "debug details"; done; echo "invalid character"; exit 1`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := &ValidationResult{}
+	applyCheckPasses(result, passes, "native")
+
+	if len(result.Errors) != 1 || result.Errors[0].Pass != PassCheck || !passes[0].Blocking {
+		t.Errorf("Expected a single blocking check error, got %+v", result.Errors)
+	}
+	if len(passes) != 3 || passes[2].Name != PassDebug || passes[2].Output == "" {
+		t.Errorf("Expected debug output for blocking failures, got %+v", passes)
+	}
+}
+
+func TestRunCheckPasses_CommandNotFound(t *testing.T) {
+	_, err := runCheckPasses(context.Background(), func(flags ...string) (*exec.Cmd, error) {
+		return exec.Command(filepath.Join(t.TempDir(), "missing")), nil
+	})
+	if err == nil {
+		t.Error("Expected an error when the command cannot run")
+	}
+}
+
+func TestRunCheckPasses_SingleDeadline(t *testing.T) {
+	withCommandTimeout(t, 500*time.Millisecond)
+	// Every pass fails after 300ms: lint and check fit in one timeout each, not together
+	start := time.Now()
+	_, err := runCheckPasses(context.Background(), fakeKrakenD(t, "sleep 0.3; exit 1"))
+	if !errors.Is(err, ErrCommandTimeout) {
+		t.Fatalf("Expected the passes to time out together, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("The passes took %v", elapsed)
+	}
+	if runningCommands() != 0 {
+		t.Error("The command must not be tracked anymore")
+	}
+}
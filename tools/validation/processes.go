@@ -0,0 +1,168 @@
+package validation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrShuttingDown is returned when a command is started after AbortRunningCommands
+var ErrShuttingDown = errors.New("server is shutting down")
+
+// ErrCommandTimeout is returned when a command runs longer than CommandTimeout
+var ErrCommandTimeout = errors.New("command timed out")
+
+// DefaultCommandTimeout bounds a krakend or Docker command, pulling the image included
+const DefaultCommandTimeout = 2 * time.Minute
+
+// CommandTimeout bounds every validation and audit command, 0 only bounds them by the request
+var CommandTimeout = DefaultCommandTimeout
+
+// commandStopGrace is how long an interrupted command may take to stop before being killed
+const commandStopGrace = 5 * time.Second
+
+// running tracks the krakend and Docker subprocesses in flight, so shutdown can stop them
+// instead of leaving orphaned processes and containers behind
+var running = struct {
+	mu      sync.Mutex
+	cmds    map[*exec.Cmd]struct{}
+	aborted bool
+}{cmds: map[*exec.Cmd]struct{}{}}
+
+// commandDeadlineKey marks a context already bounded by CommandTimeout
+type commandDeadlineKey struct{}
+
+// withCommandDeadline bounds the commands run with the returned context by CommandTimeout. A
+// sequence of commands sharing the context shares a single deadline: commands run with it do not
+// get one of their own.
+func withCommandDeadline(ctx context.Context) (context.Context, context.CancelFunc) {
+	if CommandTimeout <= 0 || ctx.Value(commandDeadlineKey{}) != nil {
+		return ctx, func() {}
+	}
+	ctx, cancel := context.WithTimeoutCause(ctx, CommandTimeout, ErrCommandTimeout)
+	return context.WithValue(ctx, commandDeadlineKey{}, true), cancel
+}
+
+// contextError returns ErrCommandTimeout when ctx is done because of CommandTimeout, else ctx's error
+func contextError(ctx context.Context, cmd *exec.Cmd) error {
+	if errors.Is(context.Cause(ctx), ErrCommandTimeout) {
+		return fmt.Errorf("%w after %s: %s", ErrCommandTimeout, CommandTimeout, strings.Join(cmd.Args, " "))
+	}
+	return ctx.Err()
+}
+
+// runCommand runs a command like cmd.Run, tracking it while it runs. The command is stopped
+// when ctx is done or after CommandTimeout, returning ctx's error or ErrCommandTimeout.
+func runCommand(ctx context.Context, cmd *exec.Cmd) error {
+	if ctx.Err() != nil {
+		return contextError(ctx, cmd)
+	}
+	runCtx, cancel := withCommandDeadline(ctx)
+	defer cancel()
+	// Children keeping the output open (e.g. docker run plugins) must not block Wait
+	if cmd.WaitDelay == 0 {
+		cmd.WaitDelay = commandStopGrace
+	}
+
+	running.mu.Lock()
+	if running.aborted {
+		running.mu.Unlock()
+		return ErrShuttingDown
+	}
+	if err := cmd.Start(); err != nil {
+		running.mu.Unlock()
+		return err
+	}
+	running.cmds[cmd] = struct{}{}
+	running.mu.Unlock()
+
+	waited := make(chan error, 1)
+	go func() { waited <- cmd.Wait() }()
+
+	var err error
+	select {
+	case err = <-waited:
+	case <-runCtx.Done():
+		stopCommand(cmd, waited)
+		err = contextError(runCtx, cmd)
+	}
+
+	running.mu.Lock()
+	delete(running.cmds, cmd)
+	running.mu.Unlock()
+	return err
+}
+
+// stopCommand interrupts a command (docker run forwards the signal to the container, which --rm
+// then removes) and kills it when still running after commandStopGrace
+func stopCommand(cmd *exec.Cmd, waited <-chan error) {
+	// Interrupt is not supported on Windows: kill right away
+	if err := cmd.Process.Signal(os.Interrupt); err != nil {
+		cmd.Process.Kill()
+	}
+	select {
+	case <-waited:
+	case <-time.After(commandStopGrace):
+		cmd.Process.Kill()
+		<-waited
+	}
+}
+
+// runningCommands returns the number of commands in flight
+func runningCommands() int {
+	running.mu.Lock()
+	defer running.mu.Unlock()
+	return len(running.cmds)
+}
+
+// AbortRunningCommands stops the commands in flight and refuses new ones. Commands are
+// interrupted first (docker run forwards the signal to the container, which --rm then
+// removes) and killed when still running after the grace period. It returns the number of
+// commands aborted.
+func AbortRunningCommands(grace time.Duration) int {
+	running.mu.Lock()
+	running.aborted = true
+	cmds := make([]*exec.Cmd, 0, len(running.cmds))
+	for cmd := range running.cmds {
+		cmds = append(cmds, cmd)
+	}
+	running.mu.Unlock()
+	if len(cmds) == 0 {
+		return 0
+	}
+
+	slog.Info("Aborting running validation commands", "count", len(cmds))
+	for _, cmd := range cmds {
+		// Interrupt is not supported on Windows: kill right away
+		if err := cmd.Process.Signal(os.Interrupt); err != nil {
+			cmd.Process.Kill()
+		}
+	}
+
+	deadline := time.Now().Add(grace)
+	for runningCommands() > 0 && time.Now().Before(deadline) {
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	running.mu.Lock()
+	defer running.mu.Unlock()
+	for cmd := range running.cmds {
+		slog.Warn("Command did not stop, killing it", "command", cmd.Path, "grace_ms", grace.Milliseconds())
+		cmd.Process.Kill()
+	}
+	return len(cmds)
+}
+
+// timeoutError reports a command stopped by CommandTimeout
+func timeoutError(err error) ValidationError {
+	return ValidationError{
+		Message: fmt.Sprintf("%s. Raise the limit with --validation-timeout (KRAKEND_MCP_VALIDATION_TIMEOUT), e.g. when Docker pulls the KrakenD image for the first time.", err.Error()),
+		Code:    "TIMEOUT",
+	}
+}
@@ -0,0 +1,106 @@
+package validation
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// abortRunning starts a fake krakend check and aborts it once running
+func abortRunning(t *testing.T, script string, grace time.Duration) (aborted int, elapsed time.Duration) {
+	t.Helper()
+	t.Cleanup(func() {
+		running.mu.Lock()
+		running.aborted = false
+		running.mu.Unlock()
+	})
+	build := fakeKrakenD(t, script)
+	done := make(chan error, 1)
+	go func() {
+		_, err := runCheckPass(context.Background(), PassCheck, build)
+		done <- err
+	}()
+	for deadline := time.Now().Add(5 * time.Second); runningCommands() == 0; time.Sleep(10 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatal("The command did not start")
+		}
+	}
+
+	start := time.Now()
+	aborted = AbortRunningCommands(grace)
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("An aborted command is a failed pass, got error %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("The command was not aborted")
+	}
+	return aborted, time.Since(start)
+}
+
+func TestAbortRunningCommands(t *testing.T) {
+	aborted, elapsed := abortRunning(t, "exec sleep 30", 5*time.Second)
+	if aborted != 1 || elapsed >= 5*time.Second {
+		t.Errorf("Expected the command to stop on interrupt, aborted %d in %v", aborted, elapsed)
+	}
+	if _, err := runCheckPass(context.Background(), PassCheck, fakeKrakenD(t, "echo Syntax OK!")); !errors.Is(err, ErrShuttingDown) {
+		t.Errorf("Expected new commands to be refused, got %v", err)
+	}
+}
+
+func TestAbortRunningCommands_Kill(t *testing.T) {
+	// The interrupt is ignored: the command is killed after the grace period
+	aborted, elapsed := abortRunning(t, "trap '' INT; exec sleep 30", 200*time.Millisecond)
+	if aborted != 1 || elapsed < 200*time.Millisecond {
+		t.Errorf("Expected the command to be killed after the grace period, aborted %d in %v", aborted, elapsed)
+	}
+}
+
+// withCommandTimeout sets CommandTimeout for a test
+func withCommandTimeout(t *testing.T, timeout time.Duration) {
+	orig := CommandTimeout
+	CommandTimeout = timeout
+	t.Cleanup(func() { CommandTimeout = orig })
+}
+
+func TestRunCheckPass_Timeout(t *testing.T) {
+	withCommandTimeout(t, 100*time.Millisecond)
+	start := time.Now()
+	_, err := runCheckPass(context.Background(), PassCheck, fakeKrakenD(t, "exec sleep 30"))
+	if !errors.Is(err, ErrCommandTimeout) || time.Since(start) > 5*time.Second {
+		t.Fatalf("Expected a timeout, got %v after %v", err, time.Since(start))
+	}
+	if runningCommands() != 0 {
+		t.Error("The command must not be tracked anymore")
+	}
+	if code := timeoutError(err).Code; code != "TIMEOUT" {
+		t.Errorf("Unexpected error code %s", code)
+	}
+}
+
+func TestRunCheckPass_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(100*time.Millisecond, cancel)
+	_, err := runCheckPass(ctx, PassCheck, fakeKrakenD(t, "exec sleep 30"))
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected the cancellation, got %v", err)
+	}
+	if _, err := runCheckPass(ctx, PassCheck, fakeKrakenD(t, "echo Syntax OK!")); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected no command to start once cancelled, got %v", err)
+	}
+}
+
+func TestValidateConfig_Timeout(t *testing.T) {
+	withSlowKrakend(t, func() {
+		withCommandTimeout(t, 100*time.Millisecond)
+		_, output, err := ValidateConfig(context.Background(), nil, ValidateConfigInput{Config: `{"version":3,"endpoints":[]}`})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if output.Valid || len(output.Errors) != 1 || output.Errors[0].Code != "TIMEOUT" {
+			t.Fatalf("Expected a TIMEOUT error, got %+v", output.ValidationResult)
+		}
+	})
+}
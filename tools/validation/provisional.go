@@ -0,0 +1,122 @@
+package validation
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ProvisionalResult is the JSON Schema verdict computed while the authoritative tier (native
+// krakend or Docker) was running, reported as early feedback next to the authoritative result
+type ProvisionalResult struct {
+	Valid      bool    `json:"valid"`
+	Errors     int     `json:"errors"`
+	Summary    string  `json:"summary"`
+	DurationMs float64 `json:"duration_ms"`
+	Agrees     bool    `json:"agrees"` // Same verdict as the authoritative result
+}
+
+// provisionalRun is a JSON Schema validation running concurrently with the authoritative tiers.
+// It is cheap, so the chain never waits for a slow Docker pull to fall back to it.
+type provisionalRun struct {
+	done    chan struct{}
+	result  *ValidationResult
+	err     error
+	elapsed time.Duration
+	sw      *stopwatch // Own stopwatch: the chain's one is not safe for concurrent use
+
+	mu       sync.Mutex
+	finished bool // The call returned its result: progress can no longer be reported
+}
+
+// startProvisional starts the schema validation of a configuration. When the client asked for
+// progress, the provisional verdict is sent as a progress notification as soon as it is ready.
+// ctx must be cancelled when the call returns, and finish called before returning its result.
+func startProvisional(ctx context.Context, req *mcp.CallToolRequest, env *ValidationEnvironment, configJSON string, timed bool) *provisionalRun {
+	run := &provisionalRun{done: make(chan struct{})}
+	if timed {
+		run.sw = newStopwatch()
+		run.sw.tier = "schema"
+	}
+	fetch := SchemaFetcher // The run may outlive the call
+	go func() {
+		defer close(run.done)
+		start := time.Now()
+		run.result, run.err = validateWithSchema(ctx, env, configJSON, fetch, run.sw)
+		run.elapsed = time.Since(start)
+		notifyProvisional(ctx, req, run)
+	}()
+	return run
+}
+
+// finish marks the call as returned, so a verdict computed later is not reported as progress
+func (p *provisionalRun) finish() {
+	p.mu.Lock()
+	p.finished = true
+	p.mu.Unlock()
+}
+
+// notifyProvisional sends the provisional verdict to clients that asked for progress, unless the
+// call already returned its result
+func notifyProvisional(ctx context.Context, req *mcp.CallToolRequest, run *provisionalRun) {
+	if req == nil || req.Session == nil || req.Params == nil || run.err != nil {
+		return
+	}
+	token := req.Params.GetProgressToken()
+	if token == nil {
+		return
+	}
+	// Held while sending, so the result cannot be sent before the notification
+	run.mu.Lock()
+	defer run.mu.Unlock()
+	if run.finished || ctx.Err() != nil {
+		return
+	}
+	message := fmt.Sprintf("Provisional JSON Schema result: %s. Waiting for the KrakenD check.", run.result.Summary)
+	if err := req.Session.NotifyProgress(ctx, &mcp.ProgressNotificationParams{ProgressToken: token, Message: message, Progress: 1, Total: 2}); err != nil {
+		slog.Debug("Could not send the provisional validation result", "error", err)
+	}
+}
+
+// ready reports whether the provisional validation finished
+func (p *provisionalRun) ready() bool {
+	select {
+	case <-p.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// wait returns the provisional result once ready, used as the final result when every
+// authoritative tier failed
+func (p *provisionalRun) wait(ctx context.Context) (*ValidationResult, error) {
+	select {
+	case <-p.done:
+		return p.result, p.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// withProvisional builds the output of an authoritative result, with the provisional verdict when
+// it is ready. The authoritative tier never waits for it.
+func withProvisional(result ValidationResult, p *provisionalRun, sw *stopwatch) ValidateConfigOutput {
+	output := ValidateConfigOutput{ValidationResult: result}
+	if p == nil || !p.ready() || p.err != nil {
+		return output
+	}
+	sw.merge(p.sw, "provisional", p.elapsed)
+	output.Provisional = &ProvisionalResult{
+		Valid:      p.result.Valid,
+		Errors:     len(p.result.Errors),
+		Summary:    p.result.Summary,
+		DurationMs: toMs(p.elapsed),
+		Agrees:     p.result.Valid == result.Valid,
+	}
+	return output
+}
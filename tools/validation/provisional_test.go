@@ -0,0 +1,148 @@
+package validation
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/krakend/mcp-server/internal/testutil"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// withSlowKrakend runs f with a krakend binary that accepts every configuration after a delay,
+// long enough for the provisional schema validation to finish first
+func withSlowKrakend(t *testing.T, f func()) {
+	t.Helper()
+	testutil.KrakenD{Delay: 300 * time.Millisecond}.Install(t)
+
+	orig := SchemaFetcher
+	SchemaFetcher = func(ctx context.Context, url string) ([]byte, error) { return nil, errors.New("offline") }
+	t.Cleanup(func() { SchemaFetcher = orig })
+	f()
+}
+
+func TestValidateConfig_ProvisionalAttached(t *testing.T) {
+	withSlowKrakend(t, func() {
+		_, output, err := ValidateConfig(context.Background(), nil, ValidateConfigInput{Config: `{"version":3,"endpoints":[]}`, IncludeTimings: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if output.Method != "native" || !output.Valid {
+			t.Fatalf("Expected the native result, got %+v", output.ValidationResult)
+		}
+		if output.Provisional == nil || !output.Provisional.Valid || !output.Provisional.Agrees {
+			t.Fatalf("Expected an agreeing provisional result, got %+v", output.Provisional)
+		}
+		tiers := output.Timings.Tiers
+		if len(tiers) != 2 || tiers[0].Tier != "native" || tiers[1].Tier != "schema" || tiers[1].Outcome != "provisional" {
+			t.Errorf("Unexpected tiers: %+v", tiers)
+		}
+	})
+}
+
+func TestValidateConfig_ProvisionalUsedAsFallback(t *testing.T) {
+	withSlowKrakend(t, func() {
+		// The temp file for krakend cannot be written, so every authoritative tier fails
+		input := ValidateConfigInput{Config: `{"version":3}`, TempDir: filepath.Join(t.TempDir(), "missing"), IncludeTimings: true}
+		_, output, err := ValidateConfig(context.Background(), nil, input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if output.Method != "schema" || output.Valid || output.Provisional != nil {
+			t.Fatalf("Expected the schema result, got %+v", output)
+		}
+		tiers := output.Timings.Tiers
+		if len(tiers) == 0 || tiers[len(tiers)-1].Tier != "schema" || tiers[len(tiers)-1].Outcome != "used" {
+			t.Errorf("Unexpected tiers: %+v", tiers)
+		}
+	})
+}
+
+func TestValidateConfig_NoProgressAfterResult(t *testing.T) {
+	testutil.KrakenD{}.Install(t)
+	// The schema outlasts the native check, and is only abandoned once the call returned
+	cancelled := make(chan bool, 1)
+	orig := SchemaFetcher
+	SchemaFetcher = func(ctx context.Context, url string) ([]byte, error) {
+		time.Sleep(200 * time.Millisecond)
+		cancelled <- ctx.Err() != nil
+		return nil, errors.New("offline")
+	}
+	t.Cleanup(func() { SchemaFetcher = orig })
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "0.0.1"}, nil)
+	RegisterValidationTools(server)
+	var returned, late atomic.Bool
+	client := mcp.NewClient(&mcp.Implementation{Name: "test", Version: "0.0.1"}, &mcp.ClientOptions{
+		ProgressNotificationHandler: func(context.Context, *mcp.ProgressNotificationClientRequest) {
+			if returned.Load() {
+				late.Store(true)
+			}
+		},
+	})
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	serverSession, err := server.Connect(context.Background(), serverTransport, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	session, err := client.Connect(context.Background(), clientTransport, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer session.Close()
+
+	// SetProgressToken drops the token without metadata
+	params := &mcp.CallToolParams{Meta: mcp.Meta{"progressToken": "validation"}, Name: "validate_config", Arguments: map[string]any{"config": `{"version":3,"endpoints":[]}`}}
+	if _, err := session.CallTool(context.Background(), params); err != nil {
+		t.Fatal(err)
+	}
+	returned.Store(true)
+
+	select {
+	case wasCancelled := <-cancelled:
+		if !wasCancelled {
+			t.Error("The schema download must be cancelled with the call")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("The provisional validation did not finish")
+	}
+	time.Sleep(100 * time.Millisecond)
+	if late.Load() {
+		t.Error("No progress must be reported once the result was sent")
+	}
+
+	// A verdict computed after the call returned is dropped even with a live context
+	req := &mcp.CallToolRequest{Session: serverSession, Params: &mcp.CallToolParamsRaw{Meta: mcp.Meta{"progressToken": "validation"}, Name: "validate_config"}}
+	run := &provisionalRun{result: &ValidationResult{Summary: "Configuration is valid"}}
+	run.finish()
+	notifyProvisional(context.Background(), req, run)
+	time.Sleep(100 * time.Millisecond)
+	if late.Load() {
+		t.Error("No progress must be reported by a finished run")
+	}
+	run.finished = false
+	notifyProvisional(context.Background(), req, run)
+	deadline := time.Now().Add(5 * time.Second)
+	for !late.Load() && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if !late.Load() {
+		t.Error("Expected the progress of a running call")
+	}
+}
+
+func TestValidateConfig_NoProvisionalInStrictMode(t *testing.T) {
+	withSlowKrakend(t, func() {
+		strict := true
+		_, output, err := ValidateConfig(context.Background(), nil, ValidateConfigInput{Config: `{"version":3,"endpoints":[]}`, Strict: &strict})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if output.Method != "native" || output.Provisional != nil {
+			t.Errorf("Expected the native result without a provisional one, got %+v", output)
+		}
+	})
+}
@@ -0,0 +1,164 @@
+package validation
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/krakend/mcp-server/internal/runtime"
+)
+
+// Environment variables that tune the validation container sandbox
+const (
+	dockerNetworkEnv  = "KRAKEND_MCP_DOCKER_NETWORK"    // Docker network ("none" by default, "default" keeps Docker's default)
+	dockerMemoryEnv   = "KRAKEND_MCP_DOCKER_MEMORY"     // Memory limit, e.g. "512m" ("0" disables)
+	dockerCPUsEnv     = "KRAKEND_MCP_DOCKER_CPUS"       // CPU limit, e.g. "1" ("0" disables)
+	dockerPidsEnv     = "KRAKEND_MCP_DOCKER_PIDS_LIMIT" // Maximum processes ("0" disables)
+	dockerReadOnlyEnv = "KRAKEND_MCP_DOCKER_READ_ONLY"  // Read-only root filesystem ("0" disables)
+	dockerUserEnv     = "KRAKEND_MCP_DOCKER_USER"       // User to run as, e.g. "1000:1000" (image default when empty)
+)
+
+// ContainerSandbox describes how validation containers are isolated. Configurations come from
+// agents and may be untrusted, so containers run hardened by default.
+type ContainerSandbox struct {
+	Network   string // Passed to --network; "" keeps Docker's default network
+	Memory    string // Passed to --memory; "" means no limit
+	CPUs      string // Passed to --cpus; "" means no limit
+	PidsLimit int    // Passed to --pids-limit; 0 means no limit
+	ReadOnly  bool   // Read-only root filesystem with a writable /tmp
+	User      string // Passed to --user; "" keeps the image user
+}
+
+// DefaultContainerSandbox is the hardened profile used unless overridden by environment variables
+var DefaultContainerSandbox = ContainerSandbox{
+	Network:   "none",
+	Memory:    "512m",
+	CPUs:      "1",
+	PidsLimit: 256,
+	ReadOnly:  true,
+}
+
+// Sandbox is the profile applied to every validation and audit container
+var Sandbox = sandboxFromEnv()
+
+// sandboxFromEnv applies the KRAKEND_MCP_DOCKER_* overrides to the default profile
+func sandboxFromEnv() ContainerSandbox {
+	s := DefaultContainerSandbox
+
+	if v, ok := os.LookupEnv(dockerNetworkEnv); ok {
+		s.Network = v
+		if v == "default" {
+			s.Network = ""
+		}
+	}
+	if v, ok := os.LookupEnv(dockerMemoryEnv); ok {
+		s.Memory = disabledIfZero(v)
+	}
+	if v, ok := os.LookupEnv(dockerCPUsEnv); ok {
+		s.CPUs = disabledIfZero(v)
+	}
+	if v, ok := os.LookupEnv(dockerPidsEnv); ok {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			slog.Warn("Ignoring invalid setting", "env", dockerPidsEnv, "value", v)
+		} else {
+			s.PidsLimit = n
+		}
+	}
+	if v, ok := os.LookupEnv(dockerReadOnlyEnv); ok {
+		switch strings.ToLower(v) {
+		case "0", "false", "no":
+			s.ReadOnly = false
+		default:
+			s.ReadOnly = true
+		}
+	}
+	if v, ok := os.LookupEnv(dockerUserEnv); ok {
+		s.User = v
+	}
+	return s
+}
+
+func disabledIfZero(v string) string {
+	if v == "0" {
+		return ""
+	}
+	return v
+}
+
+// dockerRunArgs returns the "docker run" flags enforcing the sandbox
+func (s ContainerSandbox) dockerRunArgs() []string {
+	// KrakenD never needs extra privileges to check or audit a configuration
+	args := []string{"--cap-drop=ALL", "--security-opt=no-new-privileges"}
+	if s.Network != "" {
+		args = append(args, "--network="+s.Network)
+	}
+	if s.Memory != "" {
+		args = append(args, "--memory="+s.Memory)
+	}
+	if s.CPUs != "" {
+		args = append(args, "--cpus="+s.CPUs)
+	}
+	if s.PidsLimit > 0 {
+		args = append(args, "--pids-limit="+strconv.Itoa(s.PidsLimit))
+	}
+	if s.ReadOnly {
+		args = append(args, "--read-only", "--tmpfs=/tmp")
+	}
+	if s.User != "" {
+		args = append(args, "--user="+s.User)
+	}
+	return args
+}
+
+// buildDockerStdinCommand runs a KrakenD command on a configuration streamed through stdin.
+// It is used when the Docker daemon cannot see local files (remote DOCKER_HOST, sibling containers).
+func buildDockerStdinCommand(env *ValidationEnvironment, command string, configJSON string, dockerImage string, flags ...string) *exec.Cmd {
+	script := "cat > /tmp/krakend.json && exec krakend " + command + " -c /tmp/krakend.json"
+	if len(flags) > 0 {
+		script += " " + strings.Join(flags, " ")
+	}
+
+	dockerArgs := append([]string{"run", "--rm", "-i"}, Sandbox.dockerRunArgs()...)
+	if !Sandbox.ReadOnly {
+		dockerArgs = append(dockerArgs, "--tmpfs=/tmp")
+	}
+	dockerArgs = append(dockerArgs, "--entrypoint", "/bin/sh", containerImage(env, dockerImage), "-c", script)
+
+	cmd := containerCommand(env, dockerArgs...)
+	cmd.Stdin = strings.NewReader(configJSON)
+	return cmd
+}
+
+// dockerKrakenDCommand builds the Docker command matching the environment's Docker mode
+func dockerKrakenDCommand(env *ValidationEnvironment, command string, configFile string, configJSON string, dockerImage string, fcOut string, flags ...string) (*exec.Cmd, error) {
+	if env.DockerMode != runtime.DockerModeStdin {
+		return buildDockerKrakenDCommand(env, command, configFile, dockerImage, fcOut, flags...), nil
+	}
+	if env.FlexibleConfig != nil && env.FlexibleConfig.Detected {
+		return nil, fmt.Errorf("flexible configuration needs a Docker daemon able to mount local files (%s)", env.DockerModeReason)
+	}
+	return buildDockerStdinCommand(env, command, configJSON, dockerImage, flags...), nil
+}
+
+// containerCommand runs the container CLI of env: docker, or podman on machines without Docker.
+// Podman containers run without SELinux labeling, so bind mounts stay readable on RHEL and
+// Fedora without relabeling the user's files.
+func containerCommand(env *ValidationEnvironment, args ...string) *exec.Cmd {
+	binary := env.DockerBinary
+	if binary == "" {
+		binary = "docker"
+	}
+	if env.ContainerEngine == runtime.EnginePodman && len(args) > 0 && args[0] == "run" {
+		args = append([]string{"run", "--security-opt=label=disable"}, args[1:]...)
+	}
+	return exec.Command(binary, args...)
+}
+
+// containerImage returns the image as the container engine of env resolves it
+func containerImage(env *ValidationEnvironment, image string) string {
+	return runtime.EngineImage(env.ContainerEngine, image)
+}
@@ -0,0 +1,262 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Output formats of validate_config and audit_security
+const (
+	OutputFormatJSON  = "json"
+	OutputFormatSARIF = "sarif"
+)
+
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+
+	// sarifDefaultArtifact names the configuration when it was sent inline
+	sarifDefaultArtifact = "krakend.json"
+)
+
+// SarifLog is a SARIF 2.1.0 log, uploadable to GitHub code scanning and other SARIF consumers.
+// Only the properties the findings fill are modelled.
+type SarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []SarifRun `json:"runs"`
+}
+
+// SarifRun is a run of one tool
+type SarifRun struct {
+	Tool    SarifTool     `json:"tool"`
+	Results []SarifResult `json:"results"`
+}
+
+// SarifTool describes the tool and the rules it reported
+type SarifTool struct {
+	Driver SarifDriver `json:"driver"`
+}
+
+// SarifDriver is the component of the tool reporting the results
+type SarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri,omitempty"`
+	Rules          []SarifRule `json:"rules"`
+}
+
+// SarifRule is the rule of one or several results
+type SarifRule struct {
+	ID               string                 `json:"id"`
+	ShortDescription *SarifMessage          `json:"shortDescription,omitempty"`
+	Help             *SarifMessage          `json:"help,omitempty"`
+	HelpURI          string                 `json:"helpUri,omitempty"`
+	Properties       map[string]interface{} `json:"properties,omitempty"`
+}
+
+// SarifMessage is a plain text message
+type SarifMessage struct {
+	Text string `json:"text"`
+}
+
+// SarifResult is a finding
+type SarifResult struct {
+	RuleID       string             `json:"ruleId"`
+	Level        string             `json:"level"` // "error", "warning" or "note"
+	Message      SarifMessage       `json:"message"`
+	Locations    []SarifLocation    `json:"locations"`
+	Suppressions []SarifSuppression `json:"suppressions,omitempty"`
+}
+
+// SarifSuppression tells a finding was accepted outside the tool, e.g. by the audit baseline
+type SarifSuppression struct {
+	Kind          string `json:"kind"` // "external"
+	Justification string `json:"justification,omitempty"`
+}
+
+// SarifLocation locates a finding in the configuration file and, when known, by its JSON path
+type SarifLocation struct {
+	PhysicalLocation SarifPhysicalLocation  `json:"physicalLocation"`
+	LogicalLocations []SarifLogicalLocation `json:"logicalLocations,omitempty"`
+}
+
+// SarifPhysicalLocation is a region of a file
+type SarifPhysicalLocation struct {
+	ArtifactLocation SarifArtifactLocation `json:"artifactLocation"`
+	Region           *SarifRegion          `json:"region,omitempty"`
+}
+
+// SarifArtifactLocation is the URI of a file
+type SarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+// SarifRegion is a position in a file, 1-based
+type SarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+// SarifLogicalLocation is the JSON path of a finding
+type SarifLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+}
+
+// sarifSecuritySeverities are the scores GitHub code scanning ranks security findings with
+var sarifSecuritySeverities = map[string]string{
+	"critical": "9.5",
+	"high":     "8.0",
+	"medium":   "5.5",
+	"low":      "3.0",
+	"info":     "0.0",
+}
+
+// parseOutputFormat validates an output format, json when empty
+func parseOutputFormat(format string) (string, error) {
+	switch format = strings.ToLower(strings.TrimSpace(format)); format {
+	case "", OutputFormatJSON:
+		return OutputFormatJSON, nil
+	case OutputFormatSARIF:
+		return OutputFormatSARIF, nil
+	}
+	return "", fmt.Errorf("invalid output_format %q: use json or sarif", format)
+}
+
+// sarifArtifact is the URI of the configuration in the SARIF log: its path when it was read from
+// a file, relative paths with forward slashes as SARIF consumers resolve them against the repository
+func sarifArtifact(config string) string {
+	if !isFilePath(config) {
+		return sarifDefaultArtifact
+	}
+	uri := strings.ReplaceAll(config, `\`, "/")
+	return strings.TrimPrefix(uri, "./")
+}
+
+// sarifBuilder collects results and the rules they reference, in order of appearance
+type sarifBuilder struct {
+	artifact string
+	rules    []SarifRule
+	seen     map[string]bool
+	results  []SarifResult
+}
+
+func newSarifBuilder(artifact string) *sarifBuilder {
+	return &sarifBuilder{artifact: artifact, seen: map[string]bool{}, results: []SarifResult{}}
+}
+
+// add records a finding, and its rule the first time it is seen
+func (b *sarifBuilder) add(rule SarifRule, level, message, path string, line, column int) {
+	if !b.seen[rule.ID] {
+		b.seen[rule.ID] = true
+		b.rules = append(b.rules, rule)
+	}
+	location := SarifLocation{
+		PhysicalLocation: SarifPhysicalLocation{ArtifactLocation: SarifArtifactLocation{URI: b.artifact}},
+	}
+	if line > 0 {
+		location.PhysicalLocation.Region = &SarifRegion{StartLine: line, StartColumn: column}
+	}
+	if path != "" {
+		location.LogicalLocations = []SarifLogicalLocation{{FullyQualifiedName: path}}
+	}
+	b.results = append(b.results, SarifResult{
+		RuleID:    rule.ID,
+		Level:     level,
+		Message:   SarifMessage{Text: message},
+		Locations: []SarifLocation{location},
+	})
+}
+
+func (b *sarifBuilder) log() *SarifLog {
+	rules := b.rules
+	if rules == nil {
+		rules = []SarifRule{}
+	}
+	return &SarifLog{
+		Version: sarifVersion,
+		Schema:  sarifSchema,
+		Runs: []SarifRun{{
+			Tool: SarifTool{Driver: SarifDriver{
+				Name:           "krakend-mcp-server",
+				InformationURI: "https://www.krakend.io/docs/",
+				Rules:          rules,
+			}},
+			Results: b.results,
+		}},
+	}
+}
+
+// validationSARIF renders the errors and warnings of a validation. Errors block KrakenD unless
+// reported by the lint pass; informational warnings become notes.
+func validationSARIF(result ValidationResult, artifact string) *SarifLog {
+	b := newSarifBuilder(artifact)
+	for _, e := range result.Errors {
+		level := "error"
+		if e.Pass == PassLint {
+			level = "warning"
+		}
+		rule := SarifRule{ID: sarifRuleID(e.Code, "VALIDATION_ERROR")}
+		if e.Doc != nil {
+			rule.HelpURI = e.Doc.URL
+		}
+		b.add(rule, level, e.Message, e.Path, e.Line, e.Column)
+	}
+	for _, w := range result.Warnings {
+		level := "warning"
+		if w.Level == "info" {
+			level = "note"
+		}
+		b.add(SarifRule{ID: sarifRuleID(w.Code, "VALIDATION_WARNING")}, level, w.Message, w.Path, 0, 0)
+	}
+	return b.log()
+}
+
+// auditSARIF renders the issues of a security audit, ranked with their security-severity
+func auditSARIF(output AuditSecurityOutput, artifact string) *SarifLog {
+	b := newSarifBuilder(artifact)
+	for _, issue := range output.Issues {
+		rule := SarifRule{
+			ID:               sarifRuleID(issue.Rule, "security/"+issue.Category),
+			ShortDescription: &SarifMessage{Text: issue.Title},
+			Properties: map[string]interface{}{
+				"security-severity": sarifSecuritySeverities[issue.Severity],
+				"tags":              []string{"security"},
+			},
+		}
+		if issue.Remediation != "" {
+			rule.Help = &SarifMessage{Text: issue.Remediation}
+		}
+		if len(issue.References) > 0 {
+			rule.HelpURI = issue.References[0]
+		}
+		message := issue.Description
+		if message == "" {
+			message = issue.Title
+		}
+		b.add(rule, auditSARIFLevel(issue.Severity), message, issue.Location, 0, 0)
+		if issue.Suppressed {
+			b.results[len(b.results)-1].Suppressions = []SarifSuppression{{Kind: "external", Justification: "Accepted by the audit baseline"}}
+		}
+	}
+	return b.log()
+}
+
+// auditSARIFLevel maps a finding severity to a SARIF level
+func auditSARIFLevel(severity string) string {
+	switch severity {
+	case "critical", "high":
+		return "error"
+	case "medium":
+		return "warning"
+	default:
+		return "note"
+	}
+}
+
+// sarifRuleID is the rule of a finding, or fallback for findings without one
+func sarifRuleID(id, fallback string) string {
+	if id != "" {
+		return id
+	}
+	return fallback
+}
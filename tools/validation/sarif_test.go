@@ -0,0 +1,101 @@
+package validation
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+func TestValidationSARIF(t *testing.T) {
+	result := ValidationResult{
+		Errors: []ValidationError{
+			{Path: "endpoints[0].backend", Message: "backend is required", Line: 4, Column: 7, Code: "MISSING_BACKEND", Pass: PassCheck},
+			{Path: "endpoints[1]", Message: "unused header", Code: "LINT", Pass: PassLint},
+		},
+		Warnings: []ValidationWarning{
+			{Code: "NO_TIMEOUT", Path: "timeout", Message: "no timeout", Level: "info"},
+		},
+	}
+	log := validationSARIF(result, sarifArtifact("./config/krakend.json"))
+
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("Unexpected SARIF log %+v", log)
+	}
+	run := log.Runs[0]
+	if len(run.Results) != 3 || len(run.Tool.Driver.Rules) != 3 {
+		t.Fatalf("Expected 3 results and rules, got %+v", run)
+	}
+	levels := []string{"error", "warning", "note"}
+	for i, want := range levels {
+		if run.Results[i].Level != want {
+			t.Errorf("results[%d].Level = %q, want %q", i, run.Results[i].Level, want)
+		}
+	}
+	location := run.Results[0].Locations[0]
+	if location.PhysicalLocation.ArtifactLocation.URI != "config/krakend.json" {
+		t.Errorf("Unexpected artifact %q", location.PhysicalLocation.ArtifactLocation.URI)
+	}
+	if region := location.PhysicalLocation.Region; region == nil || region.StartLine != 4 || region.StartColumn != 7 {
+		t.Errorf("Unexpected region %+v", region)
+	}
+	if location.LogicalLocations[0].FullyQualifiedName != "endpoints[0].backend" {
+		t.Errorf("Unexpected logical location %+v", location.LogicalLocations)
+	}
+	if run.Results[2].Locations[0].PhysicalLocation.Region != nil {
+		t.Error("Expected no region without a line")
+	}
+}
+
+func TestAuditSARIF(t *testing.T) {
+	output := AuditSecurityOutput{Issues: []SecurityIssue{
+		{Rule: "2.1.1", Severity: "critical", Category: "security", Title: "TLS is disabled", Description: "TLS is disabled", Remediation: "Enable TLS"},
+		{Rule: "2.1.1", Severity: "critical", Category: "security", Title: "TLS is disabled", Description: "TLS is disabled again"},
+		{Severity: "medium", Category: "cors", Title: "CORS too broad", Location: "extra_config.security/cors"},
+	}}
+	run := auditSARIF(output, sarifDefaultArtifact).Runs[0]
+
+	if len(run.Results) != 3 || len(run.Tool.Driver.Rules) != 2 {
+		t.Fatalf("Expected 3 results of 2 rules, got %+v", run)
+	}
+	rule := run.Tool.Driver.Rules[0]
+	if rule.ID != "2.1.1" || rule.Properties["security-severity"] != "9.5" || rule.Help == nil {
+		t.Errorf("Unexpected rule %+v", rule)
+	}
+	if run.Results[2].RuleID != "security/cors" || run.Results[2].Level != "warning" || run.Results[2].Message.Text != "CORS too broad" {
+		t.Errorf("Unexpected result %+v", run.Results[2])
+	}
+	if uri := run.Results[0].Locations[0].PhysicalLocation.ArtifactLocation.URI; uri != "krakend.json" {
+		t.Errorf("Unexpected artifact %q", uri)
+	}
+}
+
+func TestAuditSARIF_NoIssues(t *testing.T) {
+	data, err := json.Marshal(auditSARIF(AuditSecurityOutput{}, sarifDefaultArtifact))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var log map[string]interface{}
+	json.Unmarshal(data, &log)
+	run := log["runs"].([]interface{})[0].(map[string]interface{})
+	if results, ok := run["results"].([]interface{}); !ok || len(results) != 0 {
+		t.Errorf("Expected an empty results array, got %s", data)
+	}
+}
+
+func TestParseOutputFormat(t *testing.T) {
+	for input, want := range map[string]string{"": "json", "JSON": "json", " sarif ": "sarif"} {
+		if got, err := parseOutputFormat(input); err != nil || got != want {
+			t.Errorf("parseOutputFormat(%q) = %q, %v", input, got, err)
+		}
+	}
+	if _, err := parseOutputFormat("xml"); err == nil {
+		t.Error("Expected an error for an unknown format")
+	}
+}
+
+func TestValidateConfig_InvalidOutputFormat(t *testing.T) {
+	_, _, err := ValidateConfig(context.Background(), nil, ValidateConfigInput{Config: `{"version": 3}`, OutputFormat: "xml"})
+	if err == nil {
+		t.Error("Expected an error for an invalid output_format")
+	}
+}
@@ -0,0 +1,99 @@
+package validation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/fs"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+// schemaBaseURL is where KrakenD publishes its JSON schemas
+const schemaBaseURL = "https://www.krakend.io/schema/"
+
+// SchemaBundle holds the JSON schemas embedded in the binary, laid out as under schemaBaseURL
+// (krakend.json, v2.12/krakend.json, ee/v2.12/krakend.json...). Schema validation falls back to
+// it when a schema is neither cached nor downloadable, e.g. on airgapped machines.
+var SchemaBundle fs.FS
+
+// bundledSchema returns the schema of a URL from SchemaBundle
+func bundledSchema(url string) ([]byte, bool) {
+	if SchemaBundle == nil || !strings.HasPrefix(url, schemaBaseURL) {
+		return nil, false
+	}
+	name := strings.TrimPrefix(url, schemaBaseURL)
+	if !fs.ValidPath(name) {
+		return nil, false
+	}
+	data, err := fs.ReadFile(SchemaBundle, name)
+	if err != nil || !json.Valid(data) {
+		return nil, false
+	}
+	return data, true
+}
+
+// BundledSchemaVersions returns the versions of an edition whose schema is in SchemaBundle, oldest first
+func BundledSchemaVersions(enterprise bool) []string {
+	if SchemaBundle == nil {
+		return nil
+	}
+	dir := "."
+	if enterprise {
+		dir = "ee"
+	}
+	entries, err := fs.ReadDir(SchemaBundle, dir)
+	if err != nil {
+		return nil
+	}
+	var versions []string
+	for _, entry := range entries {
+		version, ok := strings.CutPrefix(entry.Name(), "v")
+		if !ok || !entry.IsDir() {
+			continue
+		}
+		if _, err := fs.Stat(SchemaBundle, path.Join(dir, entry.Name(), "krakend.json")); err == nil {
+			versions = append(versions, version)
+		}
+	}
+	slices.SortFunc(versions, compareVersions)
+	return versions
+}
+
+// compareVersions orders dotted versions numerically, so 2.10 comes after 2.9
+func compareVersions(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		an, aErr := strconv.Atoi(as[i])
+		bn, bErr := strconv.Atoi(bs[i])
+		if aErr != nil || bErr != nil {
+			if c := strings.Compare(as[i], bs[i]); c != 0 {
+				return c
+			}
+			continue
+		}
+		if an != bn {
+			return an - bn
+		}
+	}
+	return len(as) - len(bs)
+}
+
+// schemaLoader loads the schemas a KrakenD schema references with fetch, so they also come
+// from the cache or the bundle when offline
+type schemaLoader struct {
+	ctx   context.Context
+	fetch func(ctx context.Context, url string) ([]byte, error)
+}
+
+func (l schemaLoader) Load(url string) (any, error) {
+	data, err := l.fetch(l.ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	return jsonschema.UnmarshalJSON(bytes.NewReader(data))
+}
@@ -0,0 +1,78 @@
+package validation
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"testing/fstest"
+)
+
+// withSchemaBundle replaces the embedded schemas with a latest schema pointing to a 2.12 one
+// that requires an integer port, as the KrakenD schemas do
+func withSchemaBundle(t *testing.T) {
+	t.Helper()
+	schema := `{"$schema": "https://json-schema.org/draft/2020-12/schema", "type": "object", "required": ["version"], "properties": {"port": {"$ref": "service.json#/$defs/port"}}}`
+	orig, origCache := SchemaBundle, SchemaCacheDir
+	SchemaBundle = fstest.MapFS{
+		"krakend.json":          {Data: []byte(`{"$ref": "v2.12/krakend.json"}`)},
+		"v2.12/krakend.json":    {Data: []byte(schema)},
+		"v2.12/service.json":    {Data: []byte(`{"$defs": {"port": {"type": "integer"}}}`)},
+		"v2.9/krakend.json":     {Data: []byte(schema)},
+		"ee/v2.12/krakend.json": {Data: []byte(schema)},
+		"v2.13/README":          {Data: []byte("no schema")},
+	}
+	SchemaCacheDir = t.TempDir()
+	t.Cleanup(func() { SchemaBundle, SchemaCacheDir = orig, origCache })
+}
+
+func TestDownloadSchema_Bundled(t *testing.T) {
+	withSchemaBundle(t)
+	// Versioned schemas never change: the bundle answers without going online
+	data, err := downloadSchema(context.Background(), SchemaURL("2.12", false))
+	if err != nil || string(data) != string(SchemaBundle.(fstest.MapFS)["v2.12/krakend.json"].Data) {
+		t.Errorf("downloadSchema() = %s, %v, want the bundled schema", data, err)
+	}
+	if _, ok := bundledSchema(schemaBaseURL + "../krakend.json"); ok {
+		t.Error("Paths out of the bundle must not be served")
+	}
+}
+
+func TestValidateWithSchema_Offline(t *testing.T) {
+	withSchemaBundle(t)
+	offline := func(ctx context.Context, url string) ([]byte, error) {
+		if data, ok := bundledSchema(url); ok {
+			return data, nil
+		}
+		return nil, errors.New("offline")
+	}
+
+	// The latest schema and the schemas it references all come from the bundle
+	result, err := validateWithSchema(context.Background(), &ValidationEnvironment{}, `{"version": 3, "port": "8080"}`, offline, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Valid || len(result.Errors) == 0 {
+		t.Fatalf("Expected the bundled schema to reject the string port, got %+v", result)
+	}
+	for _, w := range result.Warnings {
+		if w.Code == WarningSchemaUnavailable {
+			t.Errorf("Unexpected degradation to basic validation: %+v", w)
+		}
+	}
+
+	result, err = validateWithSchema(context.Background(), &ValidationEnvironment{}, `{"$schema": "https://www.krakend.io/schema/v2.12/krakend.json", "version": 3, "port": 8080}`, offline, nil)
+	if err != nil || !result.Valid {
+		t.Errorf("Expected a valid configuration, got %+v, %v", result, err)
+	}
+}
+
+func TestBundledSchemaVersions(t *testing.T) {
+	withSchemaBundle(t)
+	if got := BundledSchemaVersions(false); !slices.Equal(got, []string{"2.9", "2.12"}) {
+		t.Errorf("BundledSchemaVersions(ce) = %v", got)
+	}
+	if got := BundledSchemaVersions(true); !slices.Equal(got, []string{"2.12"}) {
+		t.Errorf("BundledSchemaVersions(ee) = %v", got)
+	}
+}
@@ -1,21 +1,25 @@
 package validation
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 
 	"github.com/krakend/mcp-server/internal/features"
+	"github.com/krakend/mcp-server/internal/runtime"
+	"github.com/krakend/mcp-server/internal/tempfiles"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
 
 // SecurityIssue represents a security vulnerability or concern
 type SecurityIssue struct {
+	Rule        string   `json:"rule,omitempty"`        // Rule ID, used by exceptions files
 	Severity    string   `json:"severity"`              // "critical", "high", "medium", "low", "info"
 	Category    string   `json:"category"`              // "authentication", "cors", "headers", "ssl", "exposure", etc.
 	Title       string   `json:"title"`
@@ -23,11 +27,23 @@ type SecurityIssue struct {
 	Location    string   `json:"location,omitempty"`    // JSON path if applicable
 	Remediation string   `json:"remediation"`
 	References  []string `json:"references,omitempty"`
+	Suppressed  bool     `json:"suppressed,omitempty"` // Accepted by the audit baseline, does not fail the audit
 }
 
 // AuditSecurityInput defines input for audit_security tool
 type AuditSecurityInput struct {
-	Config string `json:"config" jsonschema:"KrakenD configuration as JSON string or file path"`
+	Config         string `json:"config" jsonschema:"KrakenD configuration as JSON string or file path (.json, .yaml, .yml or .toml)"`
+	ConfigEncoding string `json:"config_encoding,omitempty" jsonschema:"Encoding of config for large configurations: base64 or gzip+base64 (optional, defaults to a plain JSON string or file path)"`
+	IgnoreFile     string `json:"ignore_file,omitempty" jsonschema:"Exceptions file accepting findings (optional, defaults to .krakend-mcp-ignore next to the config or in the working directory)"`
+	BaselineFile   string `json:"baseline_file,omitempty" jsonschema:"Audit baseline listing the accepted findings by rule and location, reported as suppressed instead of failing (optional, defaults to .krakend-audit-baseline.json next to the config or in the working directory)"`
+	UpdateBaseline bool   `json:"update_baseline,omitempty" jsonschema:"Write the findings of this audit as the new baseline, e.g. when adopting the audit on a legacy configuration (optional)"`
+	RulesFile      string `json:"rules_file,omitempty" jsonschema:"Custom rules file with house policies checked alongside the built-in checks (optional, defaults to .krakend-mcp-rules next to the config or in the working directory)"`
+	DockerImage    string `json:"docker_image,omitempty" jsonschema:"Image of the Docker tier, e.g. registry.corp/krakend-ee:2.10 (optional, defaults to the official image of the config version and edition)"`
+	Registry       string `json:"registry,omitempty" jsonschema:"Registry mirroring the official KrakenD images, e.g. registry.corp/mirror: the version and edition are still resolved (optional)"`
+	FailOn         string `json:"fail_on,omitempty" jsonschema:"Severity threshold of the gate decision: critical, high, medium, low or info (optional)"`
+	IncludeTimings bool   `json:"include_timings,omitempty" jsonschema:"Attach where the time went: environment detection, temp files, subprocesses and parsing, per audit tier (optional)"`
+	Strict         *bool  `json:"strict,omitempty" jsonschema:"Fail instead of falling back to a mismatching krakend version, the latest Docker image or basic checks when KrakenD cannot audit the configuration version (optional, defaults to the server setting)"`
+	OutputFormat   string `json:"output_format,omitempty" jsonschema:"Output format: json (default) or sarif, which also renders the issues as a SARIF 2.1.0 log for GitHub code scanning (optional)"`
 }
 
 // AuditSecurityOutput defines output for audit_security tool
@@ -38,19 +54,110 @@ type AuditSecurityOutput struct {
 	Summary     string                 `json:"summary"`
 	Score       int                    `json:"score,omitempty"` // 0-100 security score
 	Environment *ValidationEnvironment `json:"environment,omitempty"`
+	CustomRules *CustomRulesReport     `json:"custom_rules,omitempty"` // Custom rules file evaluated
+	Exceptions  *ExceptionsReport      `json:"exceptions,omitempty"` // Findings accepted by the exceptions file
+	Baseline    *BaselineReport        `json:"baseline,omitempty"`   // Findings suppressed by the audit baseline
+	Gate        *GateDecision          `json:"gate,omitempty"`       // Verdict against fail_on
+	Timings     *TimingReport          `json:"timings,omitempty"`    // With include_timings
+	Errors      []ValidationError      `json:"errors,omitempty"`     // Commands that could not complete, e.g. TIMEOUT: a fallback tier audited
+	SARIF       *SarifLog              `json:"sarif,omitempty"`      // With output_format sarif
 }
 
 // AuditSecurity performs security audit of KrakenD configuration using three-tier fallback
 func AuditSecurity(ctx context.Context, req *mcp.CallToolRequest, input AuditSecurityInput) (*mcp.CallToolResult, AuditSecurityOutput, error) {
+	failOn := ""
+	if input.FailOn != "" {
+		var err error
+		if failOn, err = parseFailOn(input.FailOn); err != nil {
+			return nil, AuditSecurityOutput{}, err
+		}
+	}
+	images := runtime.ImageOverride{Image: input.DockerImage, Registry: input.Registry}
+	if err := images.Validate(); err != nil {
+		return nil, AuditSecurityOutput{}, err
+	}
+	format, err := parseOutputFormat(input.OutputFormat)
+	if err != nil {
+		return nil, AuditSecurityOutput{}, err
+	}
+	config, err := DecodeConfig(input.Config, input.ConfigEncoding)
+	if err != nil {
+		return nil, AuditSecurityOutput{}, err
+	}
+	input.Config, input.ConfigEncoding = config, ""
+	exceptions, err := resolveExceptions(input.Config, input.IgnoreFile)
+	if err != nil {
+		return nil, AuditSecurityOutput{}, err
+	}
+	rules, err := resolveCustomRules(input.Config, input.RulesFile)
+	if err != nil {
+		return nil, AuditSecurityOutput{}, err
+	}
+	baseline, err := baselinePath(input.Config, input.BaselineFile, input.UpdateBaseline)
+	if err != nil {
+		return nil, AuditSecurityOutput{}, err
+	}
+	var accepted []BaselineFinding
+	if baseline != "" && !input.UpdateBaseline {
+		if accepted, err = loadBaseline(baseline); err != nil {
+			return nil, AuditSecurityOutput{}, err
+		}
+	}
+	var sw *stopwatch
+	if input.IncludeTimings {
+		sw = newStopwatch()
+	}
+	res, output, err := auditSecurity(ctx, req, input, sw)
+	if err == nil {
+		output.Timings = sw.report()
+		if rules != nil {
+			applyCustomRules(&output, input.Config, rules)
+		}
+		if exceptions != nil {
+			applyAuditExceptions(&output, exceptions)
+		}
+		updated := input.UpdateBaseline && output.Method != "file_read"
+		if updated {
+			if accepted, err = writeBaseline(baseline, output.Issues); err != nil {
+				return nil, AuditSecurityOutput{}, err
+			}
+		}
+		if baseline != "" {
+			applyBaseline(&output, baseline, accepted)
+			output.Baseline.Updated = updated
+		}
+		if failOn != "" {
+			output.Gate = auditGate(failOn, output)
+		}
+		if format == OutputFormatSARIF {
+			output.SARIF = auditSARIF(output, sarifArtifact(input.Config))
+		}
+		notifyAudit(input.Config, output)
+	}
+	return res, output, err
+}
+
+// auditSecurity runs the version-aware audit fallback chain, recording its stages in sw
+func auditSecurity(ctx context.Context, req *mcp.CallToolRequest, input AuditSecurityInput, sw *stopwatch) (*mcp.CallToolResult, AuditSecurityOutput, error) {
+	done := sw.stage(StageEnvDetection, "")
 	env := DetectEnvironment()
+	done()
 
 	var result *AuditSecurityOutput
 	var err error
+	var timedOut []ValidationError // After a timeout, further commands would most likely hang too
+	noteTimeout := func(err error) {
+		if errors.Is(err, ErrCommandTimeout) {
+			timedOut = append(timedOut, timeoutError(err))
+		}
+	}
 
 	// Check if input.Config is a file path and read it
 	configContent := input.Config
 	if isFilePath(input.Config) {
+		done := sw.stage(StageFileRead, input.Config)
 		fileContent, err := os.ReadFile(input.Config)
+		done()
 		if err != nil {
 			// Provide a clear, specific error message
 			var errMsg string
@@ -77,64 +184,102 @@ func AuditSecurity(ctx context.Context, req *mcp.CallToolRequest, input AuditSec
 			}, nil
 		}
 		configContent = string(fileContent)
+
+		// YAML and TOML configurations are audited as JSON
+		if format := configFormat(input.Config); format != ConfigFormatJSON {
+			converted, convErr := configToJSON(fileContent, format)
+			if convErr != nil {
+				return nil, AuditSecurityOutput{}, fmt.Errorf("cannot audit %s: %s", input.Config, convErr.Message)
+			}
+			configContent = converted
+		}
 	}
 
 	// Extract target version from config
 	targetVersion := ExtractVersionFromConfig(configContent)
 
-	// Version-aware audit with smart fallback
+	images := runtime.ImageOverride{Image: input.DockerImage, Registry: input.Registry}
+	overridden := images != runtime.ImageOverride{}
+
+	// Version-aware audit with smart fallback, without its heuristic tiers in strict mode
+	strict := strictMode(input.Strict)
 
-	// Priority 1: Native KrakenD (if version matches or config uses latest)
-	if env.HasNativeKrakenD {
+	// Priority 1: Native KrakenD (if version matches or config uses latest), unless a Docker
+	// image was explicitly requested
+	if env.HasNativeKrakenD && !(overridden && env.HasDocker) {
+		done := sw.stage(StageVersionDetection, "krakend version")
 		localVersion, verErr := GetLocalKrakenDVersion()
+		done()
 		if verErr == nil {
 			if targetVersion == "latest" || localVersion == targetVersion {
 				// Version matches or config uses latest - use native
-				result, err = auditWithNativeKrakenD(configContent, "")
+				tierDone := sw.tryTier("native")
+				result, err = auditWithNativeKrakenD(ctx, configContent, "", sw)
+				tierDone(err)
 				if err == nil {
 					result.Environment = env
 					return nil, *result, nil
 				}
+				noteTimeout(err)
 			}
 			// Version mismatch - skip to Docker
 		}
 	}
 
 	// Priority 2: Docker with correct version
-	if env.HasDocker {
+	if env.HasDocker && len(timedOut) == 0 {
 		// Try version-specific image
-		result, err = auditWithDockerVersion(configContent, "", targetVersion)
+		tierDone := sw.tryTier("docker")
+		result, err = auditWithDockerVersion(ctx, configContent, "", targetVersion, images, sw)
+		tierDone(err)
 		if err == nil {
 			result.Environment = env
 			return nil, *result, nil
 		}
+		noteTimeout(err)
 
-		// If version-specific failed, try latest
-		if targetVersion != "latest" {
-			result, err = auditWithDockerVersion(configContent, "", "latest")
+		// If version-specific failed, try latest (an explicit image has no other version)
+		if targetVersion != "latest" && !images.Fixed() && len(timedOut) == 0 && !strict {
+			tierDone := sw.tryTier("docker")
+			result, err = auditWithDockerVersion(ctx, configContent, "", "latest", images, sw)
+			tierDone(err)
 			if err == nil {
 				result.Environment = env
 				return nil, *result, nil
 			}
+			noteTimeout(err)
 		}
 	}
 
 	// Priority 3: Fallback to native even if version mismatch
-	if env.HasNativeKrakenD {
-		result, err = auditWithNativeKrakenD(configContent, "")
+	if env.HasNativeKrakenD && len(timedOut) == 0 && !strict {
+		tierDone := sw.tryTier("native")
+		result, err = auditWithNativeKrakenD(ctx, configContent, "", sw)
+		tierDone(err)
 		if err == nil {
 			result.Environment = env
 			return nil, *result, nil
 		}
+		noteTimeout(err)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, AuditSecurityOutput{}, err
+	}
+
+	if strict {
+		return nil, AuditSecurityOutput{}, strictError(env, targetVersion, err)
 	}
 
 	// Priority 4: Use basic security checks (last resort)
+	tierDone := sw.tryTier("basic")
 	result, err = auditWithBasicChecks(configContent)
+	tierDone(err)
 	if err != nil {
 		return nil, AuditSecurityOutput{}, fmt.Errorf("all audit methods failed: %w", err)
 	}
 
 	result.Environment = env
+	result.Errors = timedOut
 	return nil, *result, nil
 }
 
@@ -165,6 +310,7 @@ func parseAuditOutput(output string) []SecurityIssue {
 		}
 		if severity := parseSeverity(line); severity != "info" {
 			issues = append(issues, SecurityIssue{
+				Rule:        auditRulePattern.FindString(line),
 				Severity:    severity,
 				Category:    "security",
 				Title:       line,
@@ -177,8 +323,10 @@ func parseAuditOutput(output string) []SecurityIssue {
 }
 
 // auditWithNativeKrakenD audits using native krakend binary
-func auditWithNativeKrakenD(configJSON string, tempDir string) (*AuditSecurityOutput, error) {
+func auditWithNativeKrakenD(ctx context.Context, configJSON string, tempDir string, sw *stopwatch) (*AuditSecurityOutput, error) {
+	done := sw.stage(StageEnvDetection, "")
 	env := DetectEnvironment()
+	done()
 
 	var configFile string
 
@@ -187,23 +335,20 @@ func auditWithNativeKrakenD(configJSON string, tempDir string) (*AuditSecurityOu
 		configFile = env.FlexibleConfig.BaseTemplate
 	} else {
 		// Create temporary file for standard config
-		if tempDir == "" {
-			tempDir = os.TempDir()
-		}
-
-		tempFile := filepath.Join(tempDir, "krakend-audit-config.json")
-		if err := os.WriteFile(tempFile, []byte(configJSON), 0600); err != nil {
-			return nil, fmt.Errorf("failed to write temp file: %w", err)
+		ioDone := sw.stage(StageTempFileIO, "")
+		tempFile, remove, err := tempfiles.File(tempDir, "krakend-audit-*.json", []byte(configJSON))
+		if err != nil {
+			return nil, err
 		}
+		ioDone()
 		configFile = tempFile
-		defer os.Remove(tempFile)
+		defer remove()
 	}
 
 	// Run krakend audit with FC support
-	cmd := buildKrakenDCommand(env, "audit", configFile)
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
+	build := func(flags ...string) (*exec.Cmd, error) {
+		return buildKrakenDCommand(env, "audit", configFile, "", flags...), nil
+	}
 
 	result := &AuditSecurityOutput{
 		Method:      "native",
@@ -211,30 +356,47 @@ func auditWithNativeKrakenD(configJSON string, tempDir string) (*AuditSecurityOu
 		Environment: env,
 	}
 
-	err := cmd.Run()
-	output := stdout.String() + stderr.String()
+	done = sw.stage(StageSubprocess, "krakend audit")
+	output, err := runAudit(ctx, build)
+	done()
 
-	// krakend audit returns non-zero if issues found
-	if err != nil && output == "" {
+	if errors.Is(err, ErrCommandTimeout) {
+		return nil, err
+	}
+	if err != nil {
 		return nil, fmt.Errorf("krakend audit command failed: %w", err)
 	}
 
 	// Parse krakend audit output
-	result.Issues = parseAuditOutput(output)
-	result.Valid = !strings.Contains(output, "CRITICAL") && !strings.Contains(output, "HIGH")
+	done = sw.stage(StageParsing, "audit output")
+	result.Issues = parseAudit(output)
+	done()
+	result.Valid = auditValid(result.Issues)
 	result.Summary = fmt.Sprintf("Security audit completed with %d issue(s) found (native KrakenD)", len(result.Issues))
 	return result, nil
 }
 
+// auditTempFile writes the configuration audited with Docker in its own temporary directory, as
+// the whole directory of the configuration is mounted in the container
+func auditTempFile(tempDir string, configJSON string) (string, func(), error) {
+	dir, remove, err := tempfiles.Dir(tempDir, "krakend-audit-*")
+	if err != nil {
+		return "", nil, err
+	}
+	file, _, err := tempfiles.File(dir, "krakend-*.json", []byte(configJSON))
+	if err != nil {
+		remove()
+		return "", nil, err
+	}
+	return file, remove, nil
+}
+
 // auditWithDocker audits using Docker container
-func auditWithDocker(configJSON string, tempDir string) (*AuditSecurityOutput, error) {
+func auditWithDocker(ctx context.Context, configJSON string, tempDir string) (*AuditSecurityOutput, error) {
 	env := DetectEnvironment()
 
 	// Determine image
-	dockerImage := "krakend:latest"
-	if env.FlexibleConfig != nil && env.FlexibleConfig.Type == "ee" {
-		dockerImage = "krakend/krakend-ee:latest"
-	}
+	dockerImage := DockerImage("latest", env.FlexibleConfig != nil && env.FlexibleConfig.Type == "ee")
 
 	var configFile string
 
@@ -246,24 +408,18 @@ func auditWithDocker(configJSON string, tempDir string) (*AuditSecurityOutput, e
 		}
 		configFile = filepath.Join(cwd, env.FlexibleConfig.BaseTemplate)
 	} else {
-		// Create temporary file for standard config
-		if tempDir == "" {
-			tempDir = os.TempDir()
-		}
-
-		tempFile := filepath.Join(tempDir, "krakend-audit-config.json")
-		if err := os.WriteFile(tempFile, []byte(configJSON), 0600); err != nil {
-			return nil, fmt.Errorf("failed to write temp file: %w", err)
+		// Create temporary file for standard config, alone in the directory mounted in the container
+		tempFile, remove, err := auditTempFile(tempDir, configJSON)
+		if err != nil {
+			return nil, err
 		}
-		defer os.Remove(tempFile)
+		defer remove()
 		configFile = tempFile
 	}
 
-	cmd := buildDockerKrakenDCommand(env, "audit", configFile, dockerImage)
-
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
+	build := func(flags ...string) (*exec.Cmd, error) {
+		return dockerKrakenDCommand(env, "audit", configFile, configJSON, dockerImage, "", flags...)
+	}
 
 	result := &AuditSecurityOutput{
 		Method:      "docker",
@@ -271,40 +427,33 @@ func auditWithDocker(configJSON string, tempDir string) (*AuditSecurityOutput, e
 		Environment: env,
 	}
 
-	err := cmd.Run()
-	output := stdout.String() + stderr.String()
+	output, err := runAudit(ctx, build)
 
-	if err != nil && output == "" {
+	if errors.Is(err, ErrCommandTimeout) {
+		return nil, err
+	}
+	if err != nil {
 		return nil, fmt.Errorf("docker audit command failed: %w", err)
 	}
 
-	result.Issues = parseAuditOutput(output)
-	result.Valid = !strings.Contains(output, "CRITICAL") && !strings.Contains(output, "HIGH")
+	result.Issues = parseAudit(output)
+	result.Valid = auditValid(result.Issues)
 	result.Summary = fmt.Sprintf("Security audit completed with %d issue(s) found (Docker)", len(result.Issues))
 	return result, nil
 }
 
 // auditWithDockerVersion audits using Docker with specific KrakenD version
-func auditWithDockerVersion(configJSON string, tempDir string, targetVersion string) (*AuditSecurityOutput, error) {
+func auditWithDockerVersion(ctx context.Context, configJSON string, tempDir string, targetVersion string, images runtime.ImageOverride, sw *stopwatch) (*AuditSecurityOutput, error) {
+	done := sw.stage(StageEnvDetection, "")
 	env := DetectEnvironment()
+	done()
 
 	// Detect if EE features are used
 	// Pass nil to use CommonEEFeatures from internal/features
 	isEE := features.DetectEnterpriseFeatures(configJSON, nil)
 
 	// Determine Docker image based on version and edition
-	var dockerImage string
-	if isEE {
-		dockerImage = fmt.Sprintf("krakend/krakend-ee:%s", targetVersion)
-		if targetVersion == "latest" {
-			dockerImage = "krakend/krakend-ee:latest"
-		}
-	} else {
-		dockerImage = fmt.Sprintf("krakend:%s", targetVersion)
-		if targetVersion == "latest" {
-			dockerImage = "krakend:latest"
-		}
-	}
+	dockerImage := images.Resolve(targetVersion, isEE)
 
 	var configFile string
 
@@ -317,24 +466,20 @@ func auditWithDockerVersion(configJSON string, tempDir string, targetVersion str
 
 		configFile = filepath.Join(cwd, env.FlexibleConfig.BaseTemplate)
 	} else {
-		// Create temporary file for standard config
-		if tempDir == "" {
-			tempDir = os.TempDir()
-		}
-
-		tempFile := filepath.Join(tempDir, "krakend-audit-config.json")
-		if err := os.WriteFile(tempFile, []byte(configJSON), 0600); err != nil {
-			return nil, fmt.Errorf("failed to write temp file: %w", err)
+		// Create temporary file for standard config, alone in the directory mounted in the container
+		ioDone := sw.stage(StageTempFileIO, "")
+		tempFile, remove, err := auditTempFile(tempDir, configJSON)
+		if err != nil {
+			return nil, err
 		}
-		defer os.Remove(tempFile)
+		ioDone()
+		defer remove()
 		configFile = tempFile
 	}
 
-	cmd := buildDockerKrakenDCommand(env, "audit", configFile, dockerImage)
-
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
+	build := func(flags ...string) (*exec.Cmd, error) {
+		return dockerKrakenDCommand(env, "audit", configFile, configJSON, dockerImage, "", flags...)
+	}
 
 	result := &AuditSecurityOutput{
 		Method:      fmt.Sprintf("docker (%s)", dockerImage),
@@ -342,15 +487,21 @@ func auditWithDockerVersion(configJSON string, tempDir string, targetVersion str
 		Environment: env,
 	}
 
-	err := cmd.Run()
-	output := stdout.String() + stderr.String()
+	done = sw.stage(StageSubprocess, "krakend audit")
+	output, err := runAudit(ctx, build)
+	done()
 
-	if err != nil && output == "" {
+	if errors.Is(err, ErrCommandTimeout) {
+		return nil, err
+	}
+	if err != nil {
 		return nil, fmt.Errorf("docker audit command failed: %w", err)
 	}
 
-	result.Issues = parseAuditOutput(output)
-	result.Valid = !strings.Contains(output, "CRITICAL") && !strings.Contains(output, "HIGH")
+	done = sw.stage(StageParsing, "audit output")
+	result.Issues = parseAudit(output)
+	done()
+	result.Valid = auditValid(result.Issues)
 	result.Summary = fmt.Sprintf("Security audit completed with %d issue(s) found (Docker %s)", len(result.Issues), dockerImage)
 	return result, nil
 }
@@ -372,6 +523,7 @@ func auditWithBasicChecks(configJSON string) (*AuditSecurityOutput, error) {
 	extraConfig, hasExtraConfig := config["extra_config"].(map[string]interface{})
 	if !hasExtraConfig || extraConfig["security/cors"] == nil {
 		result.Issues = append(result.Issues, SecurityIssue{
+			Rule:        "cors-missing",
 			Severity:    "medium",
 			Category:    "cors",
 			Title:       "Missing CORS configuration",
@@ -403,6 +555,7 @@ func auditWithBasicChecks(configJSON string) (*AuditSecurityOutput, error) {
 				// Warn especially for non-GET endpoints
 				if method != "GET" {
 					result.Issues = append(result.Issues, SecurityIssue{
+						Rule:        "endpoint-no-auth",
 						Severity:    "high",
 						Category:    "authentication",
 						Title:       fmt.Sprintf("No authentication on %s endpoint", method),
@@ -439,6 +592,7 @@ func auditWithBasicChecks(configJSON string) (*AuditSecurityOutput, error) {
 
 	if !hasRateLimitGlobal && !hasRateLimitEndpoint {
 		result.Issues = append(result.Issues, SecurityIssue{
+			Rule:        "ratelimit-missing",
 			Severity:    "medium",
 			Category:    "rate-limiting",
 			Title:       "No rate limiting configured",
@@ -451,6 +605,7 @@ func auditWithBasicChecks(configJSON string) (*AuditSecurityOutput, error) {
 	// Check 4: Debug endpoint enabled
 	if debug, ok := config["debug_endpoint"].(bool); ok && debug {
 		result.Issues = append(result.Issues, SecurityIssue{
+			Rule:        "debug-endpoint-enabled",
 			Severity:    "high",
 			Category:    "exposure",
 			Title:       "Debug endpoint enabled",
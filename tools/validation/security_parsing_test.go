@@ -1,35 +1,19 @@
 package validation
 
 import (
+	"context"
 	"os"
-	"path/filepath"
 	"strings"
 	"testing"
-)
 
-// makeFakeKrakend writes a shell script to dir that acts as a fake krakend binary.
-// When invoked with the "audit" subcommand it prints output to stdout and exits 1
-// (non-zero with non-empty output is the success path in auditWithNativeKrakenD).
-func makeFakeKrakend(t *testing.T, dir, output string) {
-	t.Helper()
-	script := "#!/bin/sh\necho '" + output + "'\nexit 1\n"
-	path := filepath.Join(dir, "krakend")
-	if err := os.WriteFile(path, []byte(script), 0o755); err != nil {
-		t.Fatalf("failed to write fake krakend: %v", err)
-	}
-}
+	"github.com/krakend/mcp-server/internal/testutil"
+)
 
-// withFakeKrakend runs f with a fake krakend binary (that prints output) injected
-// into PATH. It restores the original PATH after f returns.
+// withFakeKrakend runs f with a fake krakend binary injected into PATH. Its commands print
+// output and exit 1 (non-zero with non-empty output is the success path in auditWithNativeKrakenD).
 func withFakeKrakend(t *testing.T, output string, f func()) {
 	t.Helper()
-	dir := t.TempDir()
-	makeFakeKrakend(t, dir, output)
-
-	origPath := os.Getenv("PATH")
-	t.Cleanup(func() { os.Setenv("PATH", origPath) })
-	os.Setenv("PATH", dir+string(os.PathListSeparator)+origPath)
-
+	testutil.KrakenD{Output: output, ExitCode: 1}.Install(t)
 	f()
 }
 
@@ -44,7 +28,7 @@ func TestAuditNativeKrakenD_ParsesSeverities(t *testing.T) {
 	output := strings.Join(lines, "\n")
 
 	withFakeKrakend(t, output, func() {
-		result, err := auditWithNativeKrakenD(`{"version":3,"endpoints":[]}`, "")
+		result, err := auditWithNativeKrakenD(context.Background(), `{"version":3,"endpoints":[]}`, "", nil)
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -63,11 +47,23 @@ func TestAuditNativeKrakenD_ParsesSeverities(t *testing.T) {
 	})
 }
 
+func TestAuditNativeKrakenD_RemovesTempFile(t *testing.T) {
+	withFakeKrakend(t, "HIGH: no rate limiting configured", func() {
+		tempDir := t.TempDir()
+		if _, err := auditWithNativeKrakenD(context.Background(), `{"version":3,"endpoints":[]}`, tempDir, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if entries, _ := os.ReadDir(tempDir); len(entries) != 0 {
+			t.Errorf("expected no temp files left, got %v", entries)
+		}
+	})
+}
+
 func TestAuditNativeKrakenD_OnlyInfoLines_NoIssues(t *testing.T) {
 	output := "Audit completed\nNo issues found\nAll checks passed"
 
 	withFakeKrakend(t, output, func() {
-		result, err := auditWithNativeKrakenD(`{"version":3,"endpoints":[]}`, "")
+		result, err := auditWithNativeKrakenD(context.Background(), `{"version":3,"endpoints":[]}`, "", nil)
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -80,7 +76,7 @@ func TestAuditNativeKrakenD_OnlyInfoLines_NoIssues(t *testing.T) {
 func TestAuditNativeKrakenD_IssueFieldsPopulated(t *testing.T) {
 	line := "HIGH: no authentication configured"
 	withFakeKrakend(t, line, func() {
-		result, err := auditWithNativeKrakenD(`{"version":3,"endpoints":[]}`, "")
+		result, err := auditWithNativeKrakenD(context.Background(), `{"version":3,"endpoints":[]}`, "", nil)
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -119,7 +115,7 @@ func TestAuditNativeKrakenD_ValidFlag_FalseWhenCriticalOrHigh(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			withFakeKrakend(t, tt.output, func() {
-				result, err := auditWithNativeKrakenD(`{"version":3,"endpoints":[]}`, "")
+				result, err := auditWithNativeKrakenD(context.Background(), `{"version":3,"endpoints":[]}`, "", nil)
 				if err != nil {
 					t.Fatalf("unexpected error: %v", err)
 				}
@@ -135,7 +131,7 @@ func TestAuditNativeKrakenD_BlankLinesIgnored(t *testing.T) {
 	output := "\n\nHIGH: finding one\n\nHIGH: finding two\n\n"
 
 	withFakeKrakend(t, output, func() {
-		result, err := auditWithNativeKrakenD(`{"version":3,"endpoints":[]}`, "")
+		result, err := auditWithNativeKrakenD(context.Background(), `{"version":3,"endpoints":[]}`, "", nil)
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -150,7 +146,7 @@ func TestAuditNativeKrakenD_CaseInsensitiveSeverity(t *testing.T) {
 	output := "Critical issue\nhigh risk\nMedium concern\nlow priority"
 
 	withFakeKrakend(t, output, func() {
-		result, err := auditWithNativeKrakenD(`{"version":3,"endpoints":[]}`, "")
+		result, err := auditWithNativeKrakenD(context.Background(), `{"version":3,"endpoints":[]}`, "", nil)
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -168,7 +164,7 @@ func TestAuditNativeKrakenD_CaseInsensitiveSeverity(t *testing.T) {
 
 func TestAuditNativeKrakenD_MethodAndSummarySet(t *testing.T) {
 	withFakeKrakend(t, "all good", func() {
-		result, err := auditWithNativeKrakenD(`{"version":3,"endpoints":[]}`, "")
+		result, err := auditWithNativeKrakenD(context.Background(), `{"version":3,"endpoints":[]}`, "", nil)
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -0,0 +1,42 @@
+package validation
+
+import (
+	"errors"
+	"fmt"
+)
+
+// StrictDefault is the strict mode of the calls that do not set strict. In strict mode, only
+// KrakenD itself (a matching local krakend or the Docker image of the configuration version)
+// decides: the tools return ErrNoAuthoritativeTier rather than degrade to heuristic checks.
+var StrictDefault bool
+
+// ErrNoAuthoritativeTier is returned in strict mode when neither krakend nor Docker could validate
+// or audit the configuration for its version
+var ErrNoAuthoritativeTier = errors.New("strict mode: no authoritative KrakenD tier available")
+
+// strictMode resolves the strict parameter of a call against the server default
+func strictMode(strict *bool) bool {
+	if strict != nil {
+		return *strict
+	}
+	return StrictDefault
+}
+
+// strictError explains why no authoritative tier could handle a configuration targeting version.
+// lastErr is the failure of the last tier tried, if any.
+func strictError(env *ValidationEnvironment, version string, lastErr error) error {
+	target := "the latest version"
+	if version != "latest" {
+		target = "v" + version
+	}
+	var reason string
+	switch {
+	case !env.HasNativeKrakenD && !env.HasDocker:
+		reason = "neither a krakend binary nor Docker is available"
+	case lastErr != nil:
+		reason = fmt.Sprintf("krakend and Docker failed for %s: %v", target, lastErr)
+	default:
+		reason = fmt.Sprintf("no krakend binary or Docker image matches %s", target)
+	}
+	return fmt.Errorf("%w: %s (heuristic fallbacks are disabled, set strict to false to allow them)", ErrNoAuthoritativeTier, reason)
+}
@@ -0,0 +1,64 @@
+package validation
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/krakend/mcp-server/internal/testutil"
+)
+
+const strictVersionedConfig = `{"$schema": "https://www.krakend.io/schema/v2.12/krakend.json", "version": 3, "endpoints": []}`
+
+func strictFlag(strict bool) *bool {
+	return &strict
+}
+
+func TestValidateConfig_StrictWithoutKrakenD(t *testing.T) {
+	testutil.Isolate(t)
+	_, _, err := ValidateConfig(context.Background(), nil, ValidateConfigInput{Config: strictVersionedConfig, Strict: strictFlag(true)})
+	if !errors.Is(err, ErrNoAuthoritativeTier) {
+		t.Errorf("Expected ErrNoAuthoritativeTier, got %v", err)
+	}
+}
+
+func TestValidateConfig_StrictRejectsVersionMismatch(t *testing.T) {
+	testutil.Isolate(t)
+	testutil.KrakenD{Version: "2.11.0"}.Install(t)
+
+	_, _, err := ValidateConfig(context.Background(), nil, ValidateConfigInput{Config: strictVersionedConfig, Strict: strictFlag(true)})
+	if !errors.Is(err, ErrNoAuthoritativeTier) {
+		t.Errorf("Expected ErrNoAuthoritativeTier, got %v", err)
+	}
+
+	_, output, err := ValidateConfig(context.Background(), nil, ValidateConfigInput{Config: strictVersionedConfig})
+	if err != nil || output.Method != "native" {
+		t.Errorf("Expected the mismatching krakend without strict mode, got %q, %v", output.Method, err)
+	}
+}
+
+func TestValidateConfig_StrictUsesMatchingKrakenD(t *testing.T) {
+	testutil.Isolate(t)
+	testutil.KrakenD{}.Install(t)
+
+	_, output, err := ValidateConfig(context.Background(), nil, ValidateConfigInput{Config: strictVersionedConfig, Strict: strictFlag(true)})
+	if err != nil || output.Method != "native" || !output.Valid {
+		t.Errorf("Expected a native validation, got %+v, %v", output.ValidationResult, err)
+	}
+}
+
+func TestAuditSecurity_StrictDefault(t *testing.T) {
+	testutil.Isolate(t)
+	StrictDefault = true
+	defer func() { StrictDefault = false }()
+
+	_, _, err := AuditSecurity(context.Background(), nil, AuditSecurityInput{Config: strictVersionedConfig})
+	if !errors.Is(err, ErrNoAuthoritativeTier) {
+		t.Errorf("Expected ErrNoAuthoritativeTier, got %v", err)
+	}
+
+	_, output, err := AuditSecurity(context.Background(), nil, AuditSecurityInput{Config: strictVersionedConfig, Strict: strictFlag(false)})
+	if err != nil || output.Method != "basic" {
+		t.Errorf("Expected basic checks when the call disables strict mode, got %q, %v", output.Method, err)
+	}
+}
@@ -0,0 +1,138 @@
+package validation
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// Stages of a validation or audit reported with include_timings
+const (
+	StageEnvDetection     = "env_detection"     // Looking for krakend, Docker and Flexible Configuration
+	StageVersionDetection = "version_detection" // krakend version, to pick the tier
+	StageFileRead         = "file_read"         // Reading the configuration file
+	StageParsing          = "parsing"           // Parsing the configuration or the tool output
+	StageTempFileIO       = "temp_file_io"      // Writing the configuration for the subprocess
+	StageSubprocess       = "subprocess"        // krakend or docker run
+	StageSchemaFetch      = "schema_fetch"      // Downloading or reading the cached JSON Schema
+	StageSchemaCompile    = "schema_compile"
+	StageSchemaValidation = "schema_validation"
+	StageEmbeddedChecks   = "embedded_checks" // Checks of the server on top of KrakenD's
+)
+
+// Timing is the duration of a stage
+type Timing struct {
+	Stage      string  `json:"stage"`
+	Tier       string  `json:"tier,omitempty"`   // Tier running the stage: native, docker, schema or basic
+	Detail     string  `json:"detail,omitempty"` // E.g. the command run
+	DurationMs float64 `json:"duration_ms"`
+}
+
+// TierTiming is the duration of a tier of the fallback chain
+type TierTiming struct {
+	Tier       string  `json:"tier"`
+	Outcome    string  `json:"outcome"` // "used" or why the chain fell back to the next tier
+	DurationMs float64 `json:"duration_ms"`
+}
+
+// TimingReport breaks down where the time of a validation or audit went
+type TimingReport struct {
+	TotalMs float64      `json:"total_ms"`
+	Tiers   []TierTiming `json:"tiers"` // Tiers tried, in order
+	Stages  []Timing     `json:"stages"`
+	Slowest string       `json:"slowest,omitempty"` // Stage taking the most time
+}
+
+// stopwatch records the stages of a single validation or audit. A nil stopwatch records nothing.
+type stopwatch struct {
+	started time.Time
+	tier    string // Tier running the stages being recorded
+	stages  []Timing
+	tiers   []TierTiming
+}
+
+func newStopwatch() *stopwatch {
+	return &stopwatch{started: time.Now(), tiers: []TierTiming{}, stages: []Timing{}}
+}
+
+// stage starts measuring a stage, recorded when the returned function is called
+func (s *stopwatch) stage(name, detail string) func() {
+	if s == nil {
+		return func() {}
+	}
+	start, tier := time.Now(), s.tier
+	return func() {
+		s.stages = append(s.stages, Timing{Stage: name, Tier: tier, Detail: detail, DurationMs: toMs(time.Since(start))})
+	}
+}
+
+// tryTier starts measuring a tier of the fallback chain. The returned function records the tier
+// as used when err is nil, as failed otherwise.
+func (s *stopwatch) tryTier(tier string) func(err error) {
+	if s == nil {
+		return func(error) {}
+	}
+	start := time.Now()
+	s.tier = tier
+	return func(err error) {
+		s.tiers = append(s.tiers, TierTiming{Tier: tier, Outcome: tierOutcome(err), DurationMs: toMs(time.Since(start))})
+		s.tier = ""
+	}
+}
+
+// passes records the subprocess time of the krakend check passes
+func (s *stopwatch) passes(passes []CheckPass) {
+	if s == nil {
+		return
+	}
+	for _, pass := range passes {
+		if pass.elapsed > 0 {
+			s.stages = append(s.stages, Timing{Stage: StageSubprocess, Tier: s.tier, Detail: pass.Command, DurationMs: toMs(pass.elapsed)})
+		}
+	}
+}
+
+// merge records the stages of a concurrent run, measured with its own stopwatch, and the tier it
+// ran with the given outcome. Call it once the concurrent run finished.
+func (s *stopwatch) merge(other *stopwatch, outcome string, elapsed time.Duration) {
+	if s == nil || other == nil {
+		return
+	}
+	s.stages = append(s.stages, other.stages...)
+	s.tiers = append(s.tiers, TierTiming{Tier: other.tier, Outcome: outcome, DurationMs: toMs(elapsed)})
+}
+
+// report summarizes the stages recorded so far
+func (s *stopwatch) report() *TimingReport {
+	if s == nil {
+		return nil
+	}
+	report := &TimingReport{TotalMs: toMs(time.Since(s.started)), Tiers: s.tiers, Stages: s.stages}
+	slowest := -1
+	for i, stage := range s.stages {
+		if slowest < 0 || stage.DurationMs > s.stages[slowest].DurationMs {
+			slowest = i
+		}
+	}
+	if slowest >= 0 {
+		stage := s.stages[slowest]
+		report.Slowest = stage.Stage
+		if stage.Tier != "" {
+			report.Slowest += " (" + stage.Tier + ")"
+		}
+		report.Slowest += fmt.Sprintf(": %.0fms", stage.DurationMs)
+	}
+	return report
+}
+
+// tierOutcome is the outcome of a tier that ended with err
+func tierOutcome(err error) string {
+	if err != nil {
+		return "failed: " + err.Error()
+	}
+	return "used"
+}
+
+func toMs(d time.Duration) float64 {
+	return math.Round(float64(d.Microseconds())/10) / 100
+}
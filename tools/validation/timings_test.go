@@ -0,0 +1,70 @@
+package validation
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStopwatch(t *testing.T) {
+	var disabled *stopwatch
+	disabled.stage(StageParsing, "")()
+	disabled.tryTier("native")(nil)
+	if disabled.report() != nil {
+		t.Error("A nil stopwatch must not report")
+	}
+
+	sw := newStopwatch()
+	sw.stage(StageEnvDetection, "")()
+	tierDone := sw.tryTier("docker")
+	done := sw.stage(StageTempFileIO, "")
+	time.Sleep(5 * time.Millisecond)
+	done()
+	sw.passes([]CheckPass{{Name: PassLint, Command: "krakend check -l", elapsed: 40 * time.Millisecond}, {Name: PassCheck, Command: "krakend check"}})
+	tierDone(errors.New("image not found"))
+	sw.tryTier("schema")(nil)
+
+	report := sw.report()
+	if len(report.Tiers) != 2 || report.Tiers[0].Outcome != "failed: image not found" || report.Tiers[1].Outcome != "used" {
+		t.Errorf("Unexpected tiers: %+v", report.Tiers)
+	}
+	// Skipped passes are not reported
+	if len(report.Stages) != 3 || report.Stages[0].Tier != "" || report.Stages[1].Tier != "docker" || report.Stages[2].Detail != "krakend check -l" {
+		t.Errorf("Unexpected stages: %+v", report.Stages)
+	}
+	if report.Slowest != "subprocess (docker): 40ms" || report.TotalMs < 5 {
+		t.Errorf("Unexpected report: %+v", report)
+	}
+}
+
+func TestAuditSecurity_IncludeTimings(t *testing.T) {
+	withFakeKrakend(t, "HIGH: no rate limiting configured", func() {
+		_, output, err := AuditSecurity(context.Background(), nil, AuditSecurityInput{Config: `{"version":3,"endpoints":[]}`})
+		if err != nil || output.Timings != nil {
+			t.Fatalf("AuditSecurity() without include_timings = %+v, %v", output.Timings, err)
+		}
+
+		_, output, err = AuditSecurity(context.Background(), nil, AuditSecurityInput{Config: `{"version":3,"endpoints":[]}`, IncludeTimings: true})
+		if err != nil || output.Timings == nil {
+			t.Fatalf("AuditSecurity() = %+v, %v", output, err)
+		}
+		tiers := output.Timings.Tiers
+		if len(tiers) == 0 || tiers[len(tiers)-1].Tier != "native" || tiers[len(tiers)-1].Outcome != "used" {
+			t.Errorf("Expected the native tier to be used, got %+v", tiers)
+		}
+		stages := map[string]bool{}
+		for _, stage := range output.Timings.Stages {
+			stages[stage.Stage+"/"+stage.Tier] = true
+		}
+		for _, want := range []string{"env_detection/", "version_detection/", "temp_file_io/native", "subprocess/native", "parsing/native"} {
+			if !stages[want] {
+				t.Errorf("Expected stage %s in %+v", want, output.Timings.Stages)
+			}
+		}
+		if !strings.Contains(output.Timings.Slowest, "ms") {
+			t.Errorf("Unexpected slowest stage: %q", output.Timings.Slowest)
+		}
+	})
+}
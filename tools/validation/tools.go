@@ -1,28 +1,121 @@
 package validation
 
 import (
+	"context"
+	"log/slog"
+	"os/exec"
+	"sync/atomic"
+	"time"
+
+	"github.com/krakend/mcp-server/internal/events"
+	"github.com/krakend/mcp-server/internal/runtime"
+	"github.com/krakend/mcp-server/internal/toolkit"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
-// RegisterValidationTools registers all validation tools with the MCP server
-func RegisterValidationTools(server *mcp.Server) error {
+const (
+	validateConfigDescription  = "Complete KrakenD configuration validation with JSON syntax check, version-aware validation (matches $schema field), and linting. Uses smart 4-tier fallback: native krakend check -l (if version matches) → Docker with version-specific image → native with warning → JSON Schema validation. Automatically detects CE vs EE features.\n\nIMPORTANT: The output contains a 'guidance' field with explicit instructions. The errors and warnings returned are AUTHORITATIVE - do NOT suggest additional fixes based on assumptions or patterns. Only fix errors explicitly listed. For unclear syntax, use search_documentation tool to verify against official docs."
+	auditSecurityDescription   = "Perform security audit of KrakenD configuration using smart three-tier fallback (native KrakenD audit → Docker → basic security checks)"
+	validateConfigsDescription = "Validate several KrakenD configuration files in one call, e.g. the krakend.json variants of a monorepo. Accepts a list of file paths and/or a glob (e.g. services/*/krakend.json). Every file gets the same validation as validate_config, with the environment (krakend, Docker, Flexible Configuration) detected once, and the results are grouped by file."
+
+	// environmentCheckInterval is how often the watcher looks for krakend or Docker appearing or disappearing
+	environmentCheckInterval = time.Minute
+)
+
+// executionBackends records which validation executors are available
+type executionBackends struct {
+	native bool
+	docker bool
+}
+
+// detectExecutionBackends is a lightweight DetectEnvironment used to watch for changes
+var detectExecutionBackends = func() executionBackends {
+	var b executionBackends
+	if _, err := exec.LookPath("krakend"); err == nil {
+		b.native = true
+	}
+	if mode, _ := runtime.ResolveDockerMode(); mode != runtime.DockerModeOff {
+		_, _, _, b.docker = runtime.DetectContainerCLI()
+	}
+	return b
+}
+
+// detectedBackends holds the executors found by the last detection, reported by health checks
+var detectedBackends atomic.Pointer[executionBackends]
+
+// DetectedBackends reports whether the last detection found the krakend binary and Docker.
+// detected is false until the validation tools are registered.
+func DetectedBackends() (native, docker, detected bool) {
+	b := detectedBackends.Load()
+	if b == nil {
+		return false, false, false
+	}
+	return b.native, b.docker, true
+}
+
+// validationTools returns the validation tool definitions adjusted to the available executors,
+// so clients know up front when results come from a degraded fallback
+func validationTools(b executionBackends) (validate, audit *mcp.Tool) {
+	validate = &mcp.Tool{Name: "validate_config", Description: validateConfigDescription}
+	audit = &mcp.Tool{Name: "audit_security", Description: auditSecurityDescription}
+
+	if !b.native && !b.docker {
+		validate.Description += "\n\nDEGRADED: neither the krakend binary nor Docker is available on this server. Validation is limited to JSON syntax and JSON Schema checks (no krakend check linting); do not treat a passing result as a full validation."
+		audit.Description += "\n\nDEGRADED: neither the krakend binary nor Docker is available on this server. Only basic security checks run (no krakend audit)."
+	}
+	return validate, audit
+}
+
+// registerValidationTools registers (or replaces) the validation tools for the given executors
+func registerValidationTools(server *mcp.Server, b executionBackends) {
+	validate, audit := validationTools(b)
+
 	// Tool 1: validate_config
-	mcp.AddTool(server,
-		&mcp.Tool{
-			Name:        "validate_config",
-			Description: "Complete KrakenD configuration validation with JSON syntax check, version-aware validation (matches $schema field), and linting. Uses smart 4-tier fallback: native krakend check -l (if version matches) → Docker with version-specific image → native with warning → JSON Schema validation. Automatically detects CE vs EE features.\n\nIMPORTANT: The output contains a 'guidance' field with explicit instructions. The errors and warnings returned are AUTHORITATIVE - do NOT suggest additional fixes based on assumptions or patterns. Only fix errors explicitly listed. For unclear syntax, use search_documentation tool to verify against official docs.",
-		},
-		ValidateConfig,
-	)
+	toolkit.AddTool(server, validate, ValidateConfig)
 
 	// Tool 2: audit_security
-	mcp.AddTool(server,
-		&mcp.Tool{
-			Name:        "audit_security",
-			Description: "Perform security audit of KrakenD configuration using smart three-tier fallback (native KrakenD audit → Docker → basic security checks)",
-		},
-		AuditSecurity,
-	)
+	toolkit.AddTool(server, audit, AuditSecurity)
+
+	// Tool 3: validate_configs
+	toolkit.AddTool(server, &mcp.Tool{Name: "validate_configs", Description: validateConfigsDescription}, ValidateConfigs)
+}
 
+// RegisterValidationTools registers all validation tools with the MCP server
+func RegisterValidationTools(server *mcp.Server) error {
+	b := detectExecutionBackends()
+	if !b.native && !b.docker {
+		slog.Warn("Neither krakend nor Docker found, validation tools registered in degraded mode")
+	}
+	detectedBackends.Store(&b)
+	registerValidationTools(server, b)
 	return nil
 }
+
+// WatchEnvironment re-registers the validation tools when krakend or Docker become available
+// (or go away), which notifies connected clients that the tool list changed, and publishes
+// the change as an events.EnvironmentChanged event. It blocks until ctx is done.
+func WatchEnvironment(ctx context.Context, server *mcp.Server, interval time.Duration) {
+	if interval <= 0 {
+		interval = environmentCheckInterval
+	}
+	current := detectExecutionBackends()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			b := detectExecutionBackends()
+			if b == current {
+				continue
+			}
+			slog.Info("Validation environment changed, updating tools", "krakend", b.native, "docker", b.docker)
+			current = b
+			detectedBackends.Store(&b)
+			registerValidationTools(server, b)
+			events.Publish(events.EnvironmentChanged, "", events.Environment{KrakenD: b.native, Docker: b.docker})
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package validation
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/krakend/mcp-server/internal/events"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestValidationTools_Degraded(t *testing.T) {
+	tests := []struct {
+		name     string
+		backends executionBackends
+		degraded bool
+	}{
+		{"native", executionBackends{native: true}, false},
+		{"docker", executionBackends{docker: true}, false},
+		{"none", executionBackends{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			validate, audit := validationTools(tt.backends)
+			if got := strings.Contains(validate.Description, "DEGRADED"); got != tt.degraded {
+				t.Errorf("validate_config degraded = %v, want %v", got, tt.degraded)
+			}
+			if got := strings.Contains(audit.Description, "DEGRADED"); got != tt.degraded {
+				t.Errorf("audit_security degraded = %v, want %v", got, tt.degraded)
+			}
+		})
+	}
+}
+
+func TestWatchEnvironment_NotifiesToolListChanged(t *testing.T) {
+	var docker atomic.Bool
+	original := detectExecutionBackends
+	detectExecutionBackends = func() executionBackends { return executionBackends{docker: docker.Load()} }
+	defer func() { detectExecutionBackends = original }()
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "0.0.1"}, nil)
+	RegisterValidationTools(server)
+	if native, hasDocker, detected := DetectedBackends(); !detected || native || hasDocker {
+		t.Errorf("DetectedBackends() = %v, %v, %v, want no executor detected", native, hasDocker, detected)
+	}
+
+	changed := make(chan struct{}, 1)
+	client := mcp.NewClient(&mcp.Implementation{Name: "test", Version: "0.0.1"}, &mcp.ClientOptions{
+		ToolListChangedHandler: func(context.Context, *mcp.ToolListChangedRequest) {
+			select {
+			case changed <- struct{}{}:
+			default:
+			}
+		},
+	})
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	if _, err := server.Connect(context.Background(), serverTransport, nil); err != nil {
+		t.Fatalf("server.Connect() error = %v", err)
+	}
+	session, err := client.Connect(context.Background(), clientTransport, nil)
+	if err != nil {
+		t.Fatalf("client.Connect() error = %v", err)
+	}
+	defer session.Close()
+
+	published := make(chan events.Environment, 1)
+	defer events.Subscribe(events.EnvironmentChanged, func(e events.Event) {
+		published <- e.Data.(events.Environment)
+	})()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		WatchEnvironment(ctx, server, 10*time.Millisecond)
+		close(done)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	time.Sleep(30 * time.Millisecond)
+	docker.Store(true)
+
+	select {
+	case <-changed:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected tools/list_changed notification after Docker became available")
+	}
+	if env := <-published; !env.Docker || env.KrakenD {
+		t.Errorf("Published environment = %+v, want only Docker", env)
+	}
+
+	// Both tools are replaced one after the other: wait until the second one is updated
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		tools, err := session.ListTools(context.Background(), nil)
+		if err != nil {
+			t.Fatalf("ListTools() error = %v", err)
+		}
+		degraded := 0
+		for _, tool := range tools.Tools {
+			if strings.Contains(tool.Description, "DEGRADED") {
+				degraded++
+			}
+		}
+		if degraded == 0 {
+			if _, hasDocker, _ := DetectedBackends(); !hasDocker {
+				t.Error("Expected DetectedBackends() to report Docker")
+			}
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected tools to no longer be degraded, %d still are", degraded)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestDownloadSchema_ServesVersionedSchemaFromCache(t *testing.T) {
+	old := SchemaCacheDir
+	SchemaCacheDir = t.TempDir()
+	defer func() { SchemaCacheDir = old }()
+
+	url := SchemaURL("2.12", true)
+	path := schemaCachePath(url)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(`{"type": "object"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := downloadSchema(context.Background(), url)
+	if err != nil {
+		t.Fatalf("downloadSchema() error = %v", err)
+	}
+	if string(data) != `{"type": "object"}` {
+		t.Errorf("Expected cached schema, got %s", data)
+	}
+}
@@ -1,6 +1,7 @@
 package validation
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"testing"
@@ -136,6 +137,9 @@ func TestIsFilePath(t *testing.T) {
 		{"absolute path", "/path/to/config.json", true},
 		{"relative path", "./config.json", true},
 		{"relative path 2", "config.json", true},
+		{"yaml path", "./krakend.yaml", true},
+		{"yml path", "krakend.yml", true},
+		{"toml path", "/etc/krakend/krakend.toml", true},
 		{"json string", `{"version": 3}`, false},
 		{"json object", `{`, false},
 	}
@@ -219,7 +223,7 @@ func TestCreateTempFileRaceCondition(t *testing.T) {
 func TestValidateWithSchema_InvalidJSON(t *testing.T) {
 	config := `{invalid json`
 
-	result, err := validateWithSchema(config)
+	result, err := validateWithSchema(context.Background(), DetectEnvironment(), config, SchemaFetcher, nil)
 
 	if err == nil {
 		t.Error("Expected error for invalid JSON")
@@ -237,7 +241,7 @@ func TestValidateWithSchema_ValidBasicConfig(t *testing.T) {
 		"endpoints": []
 	}`
 
-	result, err := validateWithSchema(config)
+	result, err := validateWithSchema(context.Background(), DetectEnvironment(), config, SchemaFetcher, nil)
 	if err != nil {
 		t.Fatalf("Unexpected error: %v", err)
 	}
@@ -255,7 +259,7 @@ func TestBuildKrakenDCommand(t *testing.T) {
 		FlexibleConfig:   nil,
 	}
 
-	cmd := buildKrakenDCommand(env, "check", "/path/to/config.json")
+	cmd := buildKrakenDCommand(env, "check", "/path/to/config.json", "")
 
 	if cmd == nil {
 		t.Fatal("Expected command, got nil")
@@ -284,7 +288,7 @@ func TestBuildKrakenDCommand_WithFlexibleConfig(t *testing.T) {
 		},
 	}
 
-	cmd := buildKrakenDCommand(env, "check", "/path/to/config.json")
+	cmd := buildKrakenDCommand(env, "check", "/path/to/config.json", "")
 
 	if cmd == nil {
 		t.Fatal("Expected command, got nil")
@@ -0,0 +1,127 @@
+package validation
+
+// Warning codes identify what a validation warning is about, independently of its message
+const (
+	WarningFlexibleConfig            = "FLEXIBLE_CONFIG"
+	WarningFlexibleConfigImplication = "FLEXIBLE_CONFIG_IMPLICATION"
+	WarningEnterpriseImage           = "ENTERPRISE_IMAGE"
+	WarningVersionMismatch           = "VERSION_MISMATCH"
+	WarningImageUnavailable          = "DOCKER_IMAGE_UNAVAILABLE"
+	WarningVersionNotVerified        = "VERSION_NOT_VERIFIED"
+	WarningSchemaUnavailable         = "SCHEMA_UNAVAILABLE"
+	WarningSchemaOnly                = "SCHEMA_ONLY_VALIDATION"
+	WarningBasicOnly                 = "BASIC_VALIDATION"
+	WarningVersionNotNumber          = "VERSION_NOT_NUMBER"
+	WarningEmbeddedSchemaRemoteRef   = "EMBEDDED_SCHEMA_REMOTE_REF"
+	WarningCompiledNotSaved          = "COMPILED_CONFIG_NOT_SAVED"
+	WarningImageOverrideUnused       = "DOCKER_IMAGE_OVERRIDE_UNUSED"
+	WarningConvertedConfig           = "CONVERTED_CONFIG"
+)
+
+// Warning categories separate problems of the configuration from notes about how it was validated
+const (
+	WarningCategoryConfig      = "config"      // Problem in the configuration the user should fix
+	WarningCategoryEnvironment = "environment" // Fallbacks and tooling limits affecting the validation
+	WarningCategoryContext     = "context"     // Explanations of how the configuration is processed
+)
+
+// Warning levels, from most to least severe
+const (
+	WarningLevelWarning = "warning"
+	WarningLevelInfo    = "info"
+)
+
+// warningClass is the severity and category of a warning code
+type warningClass struct {
+	level    string
+	category string
+}
+
+var warningClasses = map[string]warningClass{
+	WarningFlexibleConfig:            {WarningLevelInfo, WarningCategoryContext},
+	WarningFlexibleConfigImplication: {WarningLevelInfo, WarningCategoryContext},
+	WarningEnterpriseImage:           {WarningLevelInfo, WarningCategoryContext},
+	WarningVersionMismatch:           {WarningLevelInfo, WarningCategoryEnvironment},
+	WarningImageUnavailable:          {WarningLevelInfo, WarningCategoryEnvironment},
+	WarningVersionNotVerified:        {WarningLevelWarning, WarningCategoryEnvironment},
+	WarningSchemaUnavailable:         {WarningLevelWarning, WarningCategoryEnvironment},
+	WarningSchemaOnly:                {WarningLevelInfo, WarningCategoryEnvironment},
+	WarningBasicOnly:                 {WarningLevelInfo, WarningCategoryEnvironment},
+	WarningVersionNotNumber:          {WarningLevelWarning, WarningCategoryConfig},
+	WarningEmbeddedSchemaRemoteRef:   {WarningLevelInfo, WarningCategoryEnvironment},
+	WarningCompiledNotSaved:          {WarningLevelWarning, WarningCategoryEnvironment},
+	WarningImageOverrideUnused:       {WarningLevelWarning, WarningCategoryEnvironment},
+	WarningConvertedConfig:           {WarningLevelInfo, WarningCategoryContext},
+}
+
+// newWarning builds a warning with the level and category of its code
+func newWarning(code, path, message string) ValidationWarning {
+	class, ok := warningClasses[code]
+	if !ok {
+		class = warningClass{WarningLevelWarning, WarningCategoryConfig}
+	}
+	return ValidationWarning{
+		Code:       code,
+		Path:       path,
+		Message:    message,
+		Level:      class.level,
+		Category:   class.category,
+		Actionable: class.category == WarningCategoryConfig || class.level == WarningLevelWarning,
+	}
+}
+
+// dedupeWarnings removes repeated warnings, keeping the first occurrence of each
+func dedupeWarnings(warnings []ValidationWarning) []ValidationWarning {
+	seen := map[ValidationWarning]bool{}
+	result := make([]ValidationWarning, 0, len(warnings))
+	for _, w := range warnings {
+		if seen[w] {
+			continue
+		}
+		seen[w] = true
+		result = append(result, w)
+	}
+	return result
+}
+
+// filterWarnings keeps the warnings at least as severe as minLevel ("info" keeps everything)
+func filterWarnings(warnings []ValidationWarning, minLevel string) []ValidationWarning {
+	if minLevel != WarningLevelWarning {
+		return warnings
+	}
+	result := make([]ValidationWarning, 0, len(warnings))
+	for _, w := range warnings {
+		if w.Level == WarningLevelWarning {
+			result = append(result, w)
+		}
+	}
+	return result
+}
+
+// flexibleConfigWarnings explains how Flexible Configuration affects the validation
+func flexibleConfigWarnings(env *ValidationEnvironment) []ValidationWarning {
+	if env == nil || env.FlexibleConfig == nil || !env.FlexibleConfig.Detected {
+		return nil
+	}
+	warnings := []ValidationWarning{newWarning(WarningFlexibleConfig, "", env.FlexibleConfig.Explanation)}
+	for _, implication := range env.FlexibleConfig.Implications {
+		warnings = append(warnings, newWarning(WarningFlexibleConfigImplication, "", implication))
+	}
+	return warnings
+}
+
+// withPriorWarnings keeps the notes gathered by previous validation tiers in the final result
+func withPriorWarnings(result ValidationResult, prior []ValidationWarning) ValidationResult {
+	result.Warnings = append(append([]ValidationWarning{}, prior...), result.Warnings...)
+	return result
+}
+
+// basicSchemaFallback runs the basic validation keeping the reason why the schema was not used
+func basicSchemaFallback(configJSON string, prior []ValidationWarning) (*ValidationResult, error) {
+	result, err := validateBasicSchema(configJSON)
+	if err != nil {
+		return nil, err
+	}
+	*result = withPriorWarnings(*result, prior)
+	return result, nil
+}
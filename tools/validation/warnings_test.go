@@ -0,0 +1,56 @@
+package validation
+
+import "testing"
+
+func TestNewWarning_Classification(t *testing.T) {
+	tests := []struct {
+		code       string
+		level      string
+		category   string
+		actionable bool
+	}{
+		{WarningFlexibleConfig, WarningLevelInfo, WarningCategoryContext, false},
+		{WarningImageUnavailable, WarningLevelInfo, WarningCategoryEnvironment, false},
+		{WarningSchemaUnavailable, WarningLevelWarning, WarningCategoryEnvironment, true},
+		{WarningVersionNotNumber, WarningLevelWarning, WarningCategoryConfig, true},
+		{"UNKNOWN_CODE", WarningLevelWarning, WarningCategoryConfig, true},
+	}
+	for _, tt := range tests {
+		w := newWarning(tt.code, "", "message")
+		if w.Code != tt.code || w.Level != tt.level || w.Category != tt.category || w.Actionable != tt.actionable {
+			t.Errorf("newWarning(%s) = %+v", tt.code, w)
+		}
+	}
+}
+
+func TestDedupeAndFilterWarnings(t *testing.T) {
+	warnings := []ValidationWarning{
+		newWarning(WarningFlexibleConfig, "", "FC detected"),
+		newWarning(WarningVersionNotNumber, "$.version", "Version should be a number"),
+		newWarning(WarningFlexibleConfig, "", "FC detected"),
+		newWarning(WarningFlexibleConfig, "", "FC detected with settings"),
+	}
+
+	deduped := dedupeWarnings(warnings)
+	if len(deduped) != 3 || deduped[2].Message != "FC detected with settings" {
+		t.Errorf("Expected 3 warnings in original order, got %+v", deduped)
+	}
+
+	if got := filterWarnings(deduped, WarningLevelInfo); len(got) != 3 {
+		t.Errorf("info level must keep everything, got %d", len(got))
+	}
+	if got := filterWarnings(deduped, WarningLevelWarning); len(got) != 1 || got[0].Code != WarningVersionNotNumber {
+		t.Errorf("warning level must keep only warnings, got %+v", got)
+	}
+}
+
+func TestBasicSchemaFallback_KeepsPriorWarnings(t *testing.T) {
+	prior := []ValidationWarning{newWarning(WarningSchemaUnavailable, "", "Could not download schema")}
+	result, err := basicSchemaFallback(`{"version": 3, "endpoints": []}`, prior)
+	if err != nil {
+		t.Fatalf("basicSchemaFallback() error = %v", err)
+	}
+	if len(result.Warnings) != 2 || result.Warnings[0].Code != WarningSchemaUnavailable || result.Warnings[1].Code != WarningBasicOnly {
+		t.Errorf("Expected the schema warning before the fallback note, got %+v", result.Warnings)
+	}
+}
@@ -0,0 +1,280 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/krakend/mcp-server/internal/toolkit"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const responseHeadersNamespace = "modifier/response-headers"
+
+// VersionAPIInput defines input for version_api tool
+type VersionAPIInput struct {
+	Config          string   `json:"config" jsonschema:"KrakenD configuration as JSON string or file path"`
+	To              string   `json:"to" jsonschema:"Prefix of the new version, e.g. /v2"`
+	From            string   `json:"from,omitempty" jsonschema:"Prefix of the current version replaced by the new one, e.g. /v1 (optional: the new prefix is prepended to unversioned paths)"`
+	Endpoints       []string `json:"endpoints,omitempty" jsonschema:"Endpoint paths to clone (optional, defaults to every endpoint under the current prefix)"`
+	Hosts           []string `json:"hosts,omitempty" jsonschema:"Backend hosts of the new version (optional, defaults to the current hosts)"`
+	PreviousHosts   []string `json:"previous_hosts,omitempty" jsonschema:"Backend hosts of the current version, when they change too (optional)"`
+	Sunset          string   `json:"sunset,omitempty" jsonschema:"Date the current version is removed, as YYYY-MM-DD, announced in the Sunset header (optional)"`
+	DeprecationLink string   `json:"deprecation_link,omitempty" jsonschema:"URL of the migration guide, announced in a Link header (optional)"`
+	KeepCurrent     bool     `json:"keep_current,omitempty" jsonschema:"Do not mark the current version as deprecated (optional)"`
+	Edition         string   `json:"edition,omitempty" jsonschema:"Target edition: ce or ee (optional, detected from the config). Enterprise adds the deprecation headers to every endpoint"`
+}
+
+// VersionAPIOutput defines output for version_api tool
+type VersionAPIOutput struct {
+	Config     map[string]interface{} `json:"config"`
+	Created    []string               `json:"created"`    // New endpoints, "METHOD /path"
+	Deprecated []string               `json:"deprecated"` // Endpoints answering with deprecation headers
+	Skipped    []string               `json:"skipped"`    // Endpoints whose new version already existed
+	Changes    []string               `json:"changes"`    // JSON paths modified
+	Warnings   []string               `json:"warnings"`
+	Notes      []string               `json:"notes"`
+	Summary    string                 `json:"summary"`
+}
+
+// normalizePrefix makes a prefix start with a slash and end without one
+func normalizePrefix(prefix string) string {
+	prefix = strings.Trim(strings.TrimSpace(prefix), "/")
+	if prefix == "" {
+		return ""
+	}
+	return "/" + prefix
+}
+
+// versionedPath moves a path from one version prefix to another. It reports false for paths
+// outside the from prefix.
+func versionedPath(path, from, to string) (string, bool) {
+	if from == "" {
+		return to + path, true
+	}
+	rest, ok := strings.CutPrefix(path, from)
+	if !ok || (rest != "" && !strings.HasPrefix(rest, "/")) {
+		return "", false
+	}
+	return to + rest, true
+}
+
+// deprecationHeaders are the response headers announcing that a version is deprecated
+// (RFC 9745), when it is removed (RFC 8594) and what replaces it
+func deprecationHeaders(successor, sunset, link string, since time.Time) [][2]string {
+	headers := [][2]string{{"Deprecation", fmt.Sprintf("@%d", since.Unix())}}
+	if sunset != "" {
+		date, _ := time.Parse(time.DateOnly, sunset)
+		headers = append(headers, [2]string{"Sunset", date.UTC().Format(http.TimeFormat)})
+	}
+	links := []string{fmt.Sprintf("<%s>; rel=\"successor-version\"", successor)}
+	if link != "" {
+		links = append(links, fmt.Sprintf("<%s>; rel=\"deprecation\"; type=\"text/html\"", link))
+	}
+	return append(headers, [2]string{"Link", strings.Join(links, ", ")})
+}
+
+// deprecationModifier adds the deprecation headers with martian, for no-op endpoints
+func deprecationModifier(headers [][2]string) map[string]interface{} {
+	modifiers := make([]interface{}, 0, len(headers))
+	for _, h := range headers {
+		modifiers = append(modifiers, map[string]interface{}{"header.Modifier": map[string]interface{}{
+			"scope": []interface{}{"response"},
+			"name":  h[0],
+			"value": h[1],
+		}})
+	}
+	return map[string]interface{}{"fifo.Group": map[string]interface{}{
+		"scope":           []interface{}{"response"},
+		"aggregateErrors": true,
+		"modifiers":       modifiers,
+	}}
+}
+
+// setsDeprecation tells whether a configuration value already adds a Deprecation header
+func setsDeprecation(value interface{}) bool {
+	data, _ := json.Marshal(value)
+	return strings.Contains(string(data), `"Deprecation"`)
+}
+
+// VersionAPI clones endpoints under a new version prefix, pointing them to the new backend hosts,
+// and marks the current version as deprecated with Deprecation, Sunset and Link headers
+func VersionAPI(ctx context.Context, req *mcp.CallToolRequest, input VersionAPIInput) (*mcp.CallToolResult, VersionAPIOutput, error) {
+	from, to := normalizePrefix(input.From), normalizePrefix(input.To)
+	if to == "" {
+		return nil, VersionAPIOutput{}, fmt.Errorf("the prefix of the new version is required, e.g. /v2")
+	}
+	if to == from {
+		return nil, VersionAPIOutput{}, fmt.Errorf("the new version must use a prefix other than %s", from)
+	}
+	if input.Sunset != "" {
+		if _, err := time.Parse(time.DateOnly, input.Sunset); err != nil {
+			return nil, VersionAPIOutput{}, fmt.Errorf("sunset must be a YYYY-MM-DD date, got %q", input.Sunset)
+		}
+	}
+	configContent, err := readConfigContent(input.Config)
+	if err != nil {
+		return nil, VersionAPIOutput{}, fmt.Errorf("failed to read config: %w", err)
+	}
+	var config map[string]interface{}
+	if err := json.Unmarshal([]byte(configContent), &config); err != nil {
+		return nil, VersionAPIOutput{}, fmt.Errorf("invalid JSON: %w", err)
+	}
+	enterprise := input.Edition == "ee" || (input.Edition == "" && DetectEnterpriseFeatures(configContent))
+
+	output := VersionAPIOutput{Config: config, Created: []string{}, Deprecated: []string{}, Skipped: []string{}, Changes: []string{}, Warnings: []string{}}
+	endpoints, _ := config["endpoints"].([]interface{})
+	declared := map[string]bool{}
+	for _, ep := range endpoints {
+		endpoint, _ := ep.(map[string]interface{})
+		path, _ := endpoint["endpoint"].(string)
+		declared[endpointKey(endpointMethod(endpoint), path)] = true
+	}
+	setHosts := func(endpoint map[string]interface{}, hosts []string) {
+		backends, _ := endpoint["backend"].([]interface{})
+		for _, b := range backends {
+			if backend, ok := b.(map[string]interface{}); ok {
+				backend["host"] = toInterfaceSlice(hosts)
+			}
+		}
+	}
+	extraConfig := func(parent map[string]interface{}) map[string]interface{} {
+		extra, _ := parent["extra_config"].(map[string]interface{})
+		if extra == nil {
+			extra = map[string]interface{}{}
+			parent["extra_config"] = extra
+		}
+		return extra
+	}
+
+	since := time.Now().UTC().Truncate(24 * time.Hour)
+	var added []interface{}
+	var encoded []string // Endpoints whose responses cannot get the headers in the Community Edition
+	matched := 0
+	for i, ep := range endpoints {
+		endpoint, ok := ep.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		path, _ := endpoint["endpoint"].(string)
+		if len(input.Endpoints) > 0 && !slices.Contains(input.Endpoints, path) {
+			continue
+		}
+		newPath, ok := versionedPath(path, from, to)
+		if !ok || (from == "" && strings.HasPrefix(path, to+"/")) {
+			continue
+		}
+		matched++
+		method := endpointMethod(endpoint)
+		if declared[endpointKey(method, newPath)] {
+			output.Skipped = append(output.Skipped, endpointKey(method, newPath))
+		} else {
+			clone, _ := normalizeJSON(endpoint).(map[string]interface{})
+			clone["endpoint"] = newPath
+			if len(input.Hosts) > 0 {
+				setHosts(clone, input.Hosts)
+			}
+			added = append(added, clone)
+			output.Created = append(output.Created, endpointKey(method, newPath))
+			if len(input.PreviousHosts) > 0 {
+				setHosts(endpoint, input.PreviousHosts)
+				output.Changes = append(output.Changes, fmt.Sprintf("$.endpoints[%d].backend[*].host", i))
+			}
+		}
+		if input.KeepCurrent {
+			continue
+		}
+
+		headers := deprecationHeaders(newPath, input.Sunset, input.DeprecationLink, since)
+		label := endpointKey(method, path)
+		switch {
+		case endpoint["output_encoding"] == "no-op":
+			backends, _ := endpoint["backend"].([]interface{})
+			for j, b := range backends {
+				backend, ok := b.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				extra := extraConfig(backend)
+				existing, ok := extra[martianNamespace].(map[string]interface{})
+				switch {
+				case ok && setsDeprecation(existing):
+					continue
+				case ok:
+					extra[martianNamespace] = map[string]interface{}{"fifo.Group": map[string]interface{}{
+						"scope":           []interface{}{"request", "response"},
+						"aggregateErrors": true,
+						"modifiers":       []interface{}{existing, deprecationModifier(headers)},
+					}}
+				default:
+					extra[martianNamespace] = deprecationModifier(headers)
+				}
+				output.Changes = append(output.Changes, fmt.Sprintf("$.endpoints[%d].backend[%d].extra_config['%s']", i, j, martianNamespace))
+			}
+		case enterprise:
+			extra := extraConfig(endpoint)
+			modifier, _ := extra[responseHeadersNamespace].(map[string]interface{})
+			if setsDeprecation(modifier) {
+				break
+			}
+			if modifier == nil {
+				modifier = map[string]interface{}{}
+				extra[responseHeadersNamespace] = modifier
+			}
+			add, _ := modifier["add"].(map[string]interface{})
+			if add == nil {
+				add = map[string]interface{}{}
+				modifier["add"] = add
+			}
+			for _, h := range headers {
+				add[h[0]] = []interface{}{h[1]}
+			}
+			output.Changes = append(output.Changes, fmt.Sprintf("$.endpoints[%d].extra_config['%s']", i, responseHeadersNamespace))
+		default:
+			encoded = append(encoded, label)
+			continue
+		}
+		output.Deprecated = append(output.Deprecated, label)
+	}
+	if matched == 0 {
+		return nil, VersionAPIOutput{}, fmt.Errorf("no endpoint to version: none is under the %q prefix or in the endpoints given", from)
+	}
+	if len(added) > 0 {
+		config["endpoints"] = append(endpoints, added...)
+		output.Changes = append(output.Changes, "$.endpoints")
+	}
+	if len(encoded) > 0 {
+		output.Warnings = append(output.Warnings, fmt.Sprintf("The deprecation headers cannot be added to %d endpoint(s) without the no-op encoding in the Community Edition (%s is Enterprise): announce the deprecation in your API documentation instead: %s",
+			len(encoded), responseHeadersNamespace, strings.Join(encoded, ", ")))
+	}
+	if len(output.Skipped) > 0 {
+		output.Warnings = append(output.Warnings, fmt.Sprintf("%d endpoint(s) of the new version already existed and were left unchanged", len(output.Skipped)))
+	}
+
+	output.Notes = []string{
+		fmt.Sprintf("New endpoints are copies of the current ones: change their backends, url_pattern or validation as the %s contract evolves.", to),
+	}
+	if len(input.Hosts) == 0 {
+		output.Notes = append(output.Notes, "The new version calls the same backend hosts: set hosts when it is served by a new deployment.")
+	}
+	if len(output.Deprecated) > 0 {
+		output.Notes = append(output.Notes, "Deprecated endpoints answer with Deprecation (RFC 9745) and a Link to their successor, and with Sunset (RFC 8594) when a removal date is given: clients and API tooling can warn their users.")
+	}
+	output.Summary = fmt.Sprintf("%d endpoint(s) created under %s, %d marked deprecated", len(output.Created), to, len(output.Deprecated))
+	return nil, output, nil
+}
+
+// RegisterVersionAPITools registers the API versioning tool
+func RegisterVersionAPITools(server *mcp.Server) {
+	toolkit.AddTool(server,
+		&mcp.Tool{
+			Name:        "version_api",
+			Description: "Clone a set of endpoints under a new version prefix (e.g. /v1/... to /v2/...) with their own backend hosts, and mark the current version as deprecated with Deprecation, Sunset and Link response headers (martian on no-op endpoints, modifier/response-headers in Enterprise). Endpoints already versioned are left unchanged.",
+		},
+		VersionAPI,
+	)
+}
@@ -0,0 +1,109 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const versionAPIConfig = `{"version": 3, "endpoints": [
+	{"endpoint": "/v1/orders", "backend": [{"host": ["http://orders:8080"], "url_pattern": "/orders"}]},
+	{"endpoint": "/v1/files", "output_encoding": "no-op", "backend": [{"host": ["http://files:8080"], "url_pattern": "/files"}]},
+	{"endpoint": "/health", "backend": [{"host": ["http://orders:8080"], "url_pattern": "/health"}]}
+]}`
+
+func TestVersionAPI(t *testing.T) {
+	input := VersionAPIInput{
+		Config:          versionAPIConfig,
+		From:            "v1",
+		To:              "/v2/",
+		Hosts:           []string{"http://orders-v2:8080"},
+		Sunset:          "2027-01-31",
+		DeprecationLink: "https://docs.example.com/migrate-v2",
+		Edition:         "ce",
+	}
+	_, output, err := VersionAPI(context.Background(), nil, input)
+	if err != nil {
+		t.Fatalf("VersionAPI() error = %v", err)
+	}
+	if strings.Join(output.Created, ",") != "GET /v2/orders,GET /v2/files" {
+		t.Errorf("Unexpected endpoints created: %v", output.Created)
+	}
+	endpoints := output.Config["endpoints"].([]interface{})
+	if len(endpoints) != 5 {
+		t.Fatalf("Expected 5 endpoints, got %d", len(endpoints))
+	}
+	clone := endpoints[3].(map[string]interface{})
+	host := clone["backend"].([]interface{})[0].(map[string]interface{})["host"].([]interface{})
+	if clone["endpoint"] != "/v2/orders" || host[0] != "http://orders-v2:8080" {
+		t.Errorf("Unexpected clone: %v", clone)
+	}
+	current := endpoints[0].(map[string]interface{})["backend"].([]interface{})[0].(map[string]interface{})["host"].([]interface{})
+	if current[0] != "http://orders:8080" {
+		t.Errorf("The current version must keep its hosts, got %v", current)
+	}
+
+	// The no-op endpoint gets the headers with martian, the other one cannot in the Community Edition
+	if len(output.Deprecated) != 1 || output.Deprecated[0] != "GET /v1/files" {
+		t.Errorf("Unexpected deprecated endpoints: %v", output.Deprecated)
+	}
+	martian, _ := json.Marshal(endpoints[1].(map[string]interface{})["backend"].([]interface{})[0].(map[string]interface{})["extra_config"])
+	for _, want := range []string{`"Deprecation"`, `"value":"@`, `Sun, 31 Jan 2027 00:00:00 GMT`, `/v2/files\u003e; rel=\"successor-version\"`, `rel=\"deprecation\"`} {
+		if !strings.Contains(string(martian), want) {
+			t.Errorf("Expected %s in the martian modifiers: %s", want, martian)
+		}
+	}
+	if !strings.Contains(strings.Join(output.Warnings, "\n"), "GET /v1/orders") {
+		t.Errorf("Expected a warning for the encoded endpoint, got %v", output.Warnings)
+	}
+
+	// Running it again changes nothing
+	data, _ := json.Marshal(output.Config)
+	input.Config = string(data)
+	_, again, err := VersionAPI(context.Background(), nil, input)
+	if err != nil || len(again.Created) != 0 || len(again.Skipped) != 2 || len(again.Changes) != 0 {
+		t.Errorf("Expected no changes on a versioned config, got %+v (%v)", again, err)
+	}
+}
+
+func TestVersionAPI_Enterprise(t *testing.T) {
+	_, output, err := VersionAPI(context.Background(), nil, VersionAPIInput{
+		Config:    versionAPIConfig,
+		To:        "/v2",
+		Endpoints: []string{"/health"},
+		Edition:   "ee",
+	})
+	if err != nil {
+		t.Fatalf("VersionAPI() error = %v", err)
+	}
+	if len(output.Created) != 1 || output.Created[0] != "GET /v2/health" {
+		t.Errorf("Unexpected endpoints created: %v", output.Created)
+	}
+	health := output.Config["endpoints"].([]interface{})[2].(map[string]interface{})
+	add := health["extra_config"].(map[string]interface{})[responseHeadersNamespace].(map[string]interface{})["add"].(map[string]interface{})
+	if add["Link"].([]interface{})[0] != `</v2/health>; rel="successor-version"` || add["Deprecation"] == nil || add["Sunset"] != nil {
+		t.Errorf("Unexpected response headers: %v", add)
+	}
+}
+
+func TestVersionAPI_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input VersionAPIInput
+		want  string
+	}{
+		{"no target", VersionAPIInput{Config: versionAPIConfig}, "required"},
+		{"same prefix", VersionAPIInput{Config: versionAPIConfig, From: "/v1", To: "v1"}, "other than"},
+		{"invalid sunset", VersionAPIInput{Config: versionAPIConfig, To: "/v2", Sunset: "next year"}, "YYYY-MM-DD"},
+		{"no endpoint", VersionAPIInput{Config: versionAPIConfig, From: "/v3", To: "/v4", Edition: "ce"}, "no endpoint"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := VersionAPI(context.Background(), nil, tt.input)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected an error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,227 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/krakend/mcp-server/internal/toolkit"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// VisualizeConfigInput defines input for visualize_config tool
+type VisualizeConfigInput struct {
+	Config   string `json:"config" jsonschema:"KrakenD configuration (JSON string or file path)"`
+	Endpoint string `json:"endpoint" jsonschema:"Endpoint path to draw, as declared in the configuration (e.g. /users/{id})"`
+	Method   string `json:"method,omitempty" jsonschema:"Endpoint method when the path is declared for several methods (optional)"`
+	Diagram  string `json:"diagram,omitempty" jsonschema:"Diagram type: sequence (default) or flowchart"`
+}
+
+// VisualizeConfigOutput defines output for visualize_config tool
+type VisualizeConfigOutput struct {
+	Diagram   string   `json:"diagram"`
+	Mermaid   string   `json:"mermaid"`
+	Steps     []string `json:"steps"`               // Middlewares in execution order
+	Unordered []string `json:"unordered,omitempty"` // Namespaces missing from the ordering dataset, not drawn
+}
+
+// requestPath splits the steps of an endpoint around the backend calls: before them, per
+// backend, and after the merge (response phase of the endpoint proxy pipe)
+type requestPath struct {
+	before   []pipelineStep
+	backends map[int][]pipelineStep
+	after    []pipelineStep
+}
+
+// splitRequestPath groups the steps of an endpoint pipeline for drawing
+func splitRequestPath(steps []pipelineStep) requestPath {
+	path := requestPath{backends: map[int][]pipelineStep{}}
+	for _, step := range steps {
+		switch {
+		case step.Stage == stageBackend:
+			path.backends[step.Backend] = append(path.backends[step.Backend], step)
+		case step.Stage == stageProxy && step.Phase == "response":
+			path.after = append(path.after, step)
+		case step.Stage == stageProxy && step.Phase == "both":
+			path.before = append(path.before, step)
+			path.after = append(path.after, step)
+		default:
+			path.before = append(path.before, step)
+		}
+	}
+	return path
+}
+
+// backendLabel names a backend by its host and url_pattern
+func backendLabel(config, backend map[string]interface{}) string {
+	hosts, _ := backend["host"].([]interface{})
+	if len(hosts) == 0 {
+		hosts, _ = config["host"].([]interface{})
+	}
+	label, _ := backend["url_pattern"].(string)
+	if len(hosts) > 0 {
+		if host, ok := hosts[0].(string); ok {
+			if _, hostname, _, err := splitBackendHost(host); err == nil {
+				label = hostname + label
+			}
+		}
+	}
+	return mermaidText(label)
+}
+
+// mermaidText escapes the characters Mermaid reads as syntax in labels and notes
+func mermaidText(s string) string {
+	return strings.NewReplacer(`"`, "#quot;", ";", "#59;", "#", "#35;").Replace(s)
+}
+
+// VisualizeConfig draws the request path of an endpoint as a Mermaid diagram
+func VisualizeConfig(ctx context.Context, req *mcp.CallToolRequest, input VisualizeConfigInput) (*mcp.CallToolResult, VisualizeConfigOutput, error) {
+	diagram := input.Diagram
+	if diagram == "" {
+		diagram = "sequence"
+	}
+	if diagram != "sequence" && diagram != "flowchart" {
+		return nil, VisualizeConfigOutput{}, fmt.Errorf("unsupported diagram %q: use sequence or flowchart", diagram)
+	}
+	configContent, err := readConfigContent(input.Config)
+	if err != nil {
+		return nil, VisualizeConfigOutput{}, fmt.Errorf("failed to read config: %w", err)
+	}
+	var config map[string]interface{}
+	if err := json.Unmarshal([]byte(configContent), &config); err != nil {
+		return nil, VisualizeConfigOutput{}, fmt.Errorf("invalid JSON: %w", err)
+	}
+	endpoint, err := findEndpoint(config, input.Endpoint, input.Method)
+	if err != nil {
+		return nil, VisualizeConfigOutput{}, err
+	}
+
+	steps, unordered := endpointPipeline(config, endpoint)
+	output := VisualizeConfigOutput{Diagram: diagram, Steps: []string{}, Unordered: unordered}
+	for _, step := range steps {
+		name := step.Namespace
+		if step.Backend >= 0 {
+			name = fmt.Sprintf("%s (backend %d)", name, step.Backend)
+		}
+		output.Steps = append(output.Steps, name)
+	}
+
+	if diagram == "flowchart" {
+		output.Mermaid = renderPipelineFlowchart(config, endpoint, splitRequestPath(steps))
+	} else {
+		output.Mermaid = renderPipelineSequence(config, endpoint, splitRequestPath(steps))
+	}
+	return nil, output, nil
+}
+
+// renderPipelineSequence renders the request path as a sequence diagram
+func renderPipelineSequence(config, endpoint map[string]interface{}, path requestPath) string {
+	method := endpointMethod(endpoint)
+	backends, _ := endpoint["backend"].([]interface{})
+	extra, _ := endpoint["extra_config"].(map[string]interface{})
+	proxy, _ := extra["proxy"].(map[string]interface{})
+	// Sequential backends are drawn one after the other, concurrent ones in a par block
+	concurrent := len(backends) > 1 && proxy["sequential"] != true
+
+	var b strings.Builder
+	b.WriteString("sequenceDiagram\n  participant C as Client\n  participant K as KrakenD\n")
+	for i, bk := range backends {
+		backend, _ := bk.(map[string]interface{})
+		fmt.Fprintf(&b, "  participant B%d as %s\n", i, backendLabel(config, backend))
+	}
+	fmt.Fprintf(&b, "  C->>K: %s %s\n", method, mermaidText(fmt.Sprint(endpoint["endpoint"])))
+	for _, step := range path.before {
+		fmt.Fprintf(&b, "  Note over K: %s: %s\n", step.Namespace, mermaidText(step.Description))
+	}
+
+	if len(backends) > 1 && !concurrent {
+		b.WriteString("  Note over K: proxy.sequential: backends are called in order\n")
+	}
+	for i, bk := range backends {
+		backend, _ := bk.(map[string]interface{})
+		if concurrent {
+			if i == 0 {
+				fmt.Fprintf(&b, "  par backend %d\n", i)
+			} else {
+				fmt.Fprintf(&b, "  and backend %d\n", i)
+			}
+		}
+		for _, step := range path.backends[i] {
+			fmt.Fprintf(&b, "  Note over K,B%d: %s\n", i, step.Namespace)
+		}
+		backendMethod, _ := backend["method"].(string)
+		if backendMethod == "" {
+			backendMethod = method
+		}
+		urlPattern, _ := backend["url_pattern"].(string)
+		fmt.Fprintf(&b, "  K->>B%d: %s %s\n  B%d-->>K: response\n", i, strings.ToUpper(backendMethod), mermaidText(urlPattern), i)
+	}
+	if concurrent {
+		b.WriteString("  end\n")
+	}
+	if len(backends) > 1 {
+		b.WriteString("  Note over K: Merge backend responses\n")
+	}
+	for _, step := range path.after {
+		fmt.Fprintf(&b, "  Note over K: %s (response)\n", step.Namespace)
+	}
+	b.WriteString("  K-->>C: response\n")
+	return b.String()
+}
+
+// renderPipelineFlowchart renders the request path as a flowchart
+func renderPipelineFlowchart(config, endpoint map[string]interface{}, path requestPath) string {
+	backends, _ := endpoint["backend"].([]interface{})
+
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+	fmt.Fprintf(&b, "  client([\"Client: %s %s\"])\n", endpointMethod(endpoint), mermaidText(fmt.Sprint(endpoint["endpoint"])))
+	prev := "client"
+	for i, step := range path.before {
+		id := fmt.Sprintf("s%d", i)
+		fmt.Fprintf(&b, "  %s[\"%s\"]\n  %s --> %s\n", id, step.Namespace, prev, id)
+		prev = id
+	}
+
+	b.WriteString("  merge{{\"Merge responses\"}}\n")
+	for i, bk := range backends {
+		backend, _ := bk.(map[string]interface{})
+		from := prev
+		fmt.Fprintf(&b, "  subgraph backend%d[\"Backend %d\"]\n", i, i)
+		for j, step := range path.backends[i] {
+			id := fmt.Sprintf("b%ds%d", i, j)
+			fmt.Fprintf(&b, "    %s[\"%s\"]\n", id, step.Namespace)
+		}
+		fmt.Fprintf(&b, "    b%d[(\"%s\")]\n  end\n", i, backendLabel(config, backend))
+		for j := range path.backends[i] {
+			id := fmt.Sprintf("b%ds%d", i, j)
+			fmt.Fprintf(&b, "  %s --> %s\n", from, id)
+			from = id
+		}
+		fmt.Fprintf(&b, "  %s --> b%d --> merge\n", from, i)
+	}
+	if len(backends) == 0 {
+		fmt.Fprintf(&b, "  %s --> merge\n", prev)
+	}
+
+	prev = "merge"
+	for i, step := range path.after {
+		id := fmt.Sprintf("r%d", i)
+		fmt.Fprintf(&b, "  %s[\"%s (response)\"]\n  %s --> %s\n", id, step.Namespace, prev, id)
+		prev = id
+	}
+	fmt.Fprintf(&b, "  response([\"Response\"])\n  %s --> response\n", prev)
+	return b.String()
+}
+
+// RegisterVisualizeTools registers configuration diagram tools
+func RegisterVisualizeTools(server *mcp.Server) {
+	toolkit.AddTool(server,
+		&mcp.Tool{
+			Name:        "visualize_config",
+			Description: "Draw the request path of one endpoint as a Mermaid sequence diagram or flowchart: client → service and endpoint middlewares in execution order → each backend with its own middlewares → merge → response middlewares. Helps teams see at a glance in which order authentication, rate limits, Lua, circuit breakers and other middlewares apply.",
+		},
+		VisualizeConfig,
+	)
+}
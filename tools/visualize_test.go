@@ -0,0 +1,67 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+const visualizeConfig = `{"version": 3,
+	"extra_config": {"security/cors": {"allow_origins": ["*"]}, "router": {"return_error_msg": true}},
+	"endpoints": [{
+		"endpoint": "/users/{id}",
+		"extra_config": {
+			"auth/validator": {"alg": "RS256"},
+			"qos/ratelimit/router": {"max_rate": 10},
+			"security/bot-detector": {},
+			"modifier/jmespath": {"expr": "user"},
+			"custom/plugin": {}
+		},
+		"backend": [
+			{"host": ["http://users:8080"], "url_pattern": "/users/{id}", "extra_config": {"qos/circuit-breaker": {"max_errors": 3}}},
+			{"host": ["http://prefs:8080"], "url_pattern": "/prefs/{id}"}
+		]
+	}]
+}`
+
+func TestVisualizeConfig_Sequence(t *testing.T) {
+	_, output, err := VisualizeConfig(context.Background(), nil, VisualizeConfigInput{Config: visualizeConfig, Endpoint: "/users/{id}"})
+	if err != nil {
+		t.Fatalf("VisualizeConfig() error = %v", err)
+	}
+	want := []string{"security/cors", "security/bot-detector", "auth/validator", "qos/ratelimit/router", "modifier/jmespath", "qos/circuit-breaker (backend 0)"}
+	if strings.Join(output.Steps, ",") != strings.Join(want, ",") {
+		t.Errorf("Steps = %v, want %v", output.Steps, want)
+	}
+	if len(output.Unordered) != 1 || output.Unordered[0] != "custom/plugin" {
+		t.Errorf("Expected custom/plugin to be unordered, got %v", output.Unordered)
+	}
+
+	m := output.Mermaid
+	for _, s := range []string{"sequenceDiagram", "participant B0 as users/users/{id}", "par backend 0", "and backend 1", "Merge backend responses", "modifier/jmespath (response)"} {
+		if !strings.Contains(m, s) {
+			t.Errorf("Mermaid missing %q:\n%s", s, m)
+		}
+	}
+	// Authentication is drawn before the rate limit and both before the backends
+	auth, limit, call := strings.Index(m, "auth/validator"), strings.Index(m, "qos/ratelimit/router"), strings.Index(m, "K->>B0")
+	if auth > limit || limit > call {
+		t.Errorf("Middlewares out of order:\n%s", m)
+	}
+}
+
+func TestVisualizeConfig_Flowchart(t *testing.T) {
+	_, output, err := VisualizeConfig(context.Background(), nil, VisualizeConfigInput{Config: visualizeConfig, Endpoint: "/users/{id}", Diagram: "flowchart"})
+	if err != nil {
+		t.Fatalf("VisualizeConfig() error = %v", err)
+	}
+	for _, s := range []string{"flowchart LR", `subgraph backend0["Backend 0"]`, "b0s0[\"qos/circuit-breaker\"]", "b1 --> merge", "r0 --> response"} {
+		if !strings.Contains(output.Mermaid, s) {
+			t.Errorf("Mermaid missing %q:\n%s", s, output.Mermaid)
+		}
+	}
+
+	if _, _, err := VisualizeConfig(context.Background(), nil, VisualizeConfigInput{Config: visualizeConfig, Endpoint: "/users/{id}", Diagram: "gantt"}); err == nil {
+		t.Error("Expected an error for an unsupported diagram")
+	}
+}
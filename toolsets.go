@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// knownToolsets lists the tool groups that can be selected at startup
+var knownToolsets = []string{
+	"validation",
+	"runtime",
+	"search",
+	"features",
+	"generation",
+	"fleet",
+	"memory",
+	"diagnostics",
+	"live",
+}
+
+// toolsetFilter decides which tool groups get registered
+type toolsetFilter struct {
+	enabled  map[string]bool // nil means every toolset is enabled
+	disabled map[string]bool
+}
+
+// parseToolsetFilter builds the filter from --toolsets/--disable-toolsets flags, falling back
+// to the KRAKEND_MCP_TOOLSETS and KRAKEND_MCP_DISABLE_TOOLSETS environment variables
+func parseToolsetFilter(args []string) (*toolsetFilter, error) {
+	enabled, _, err := lookupSetting(args, "--toolsets", "KRAKEND_MCP_TOOLSETS")
+	if err != nil {
+		return nil, err
+	}
+	disabled, _, err := lookupSetting(args, "--disable-toolsets", "KRAKEND_MCP_DISABLE_TOOLSETS")
+	if err != nil {
+		return nil, err
+	}
+
+	f := &toolsetFilter{disabled: map[string]bool{}}
+	if strings.TrimSpace(enabled) != "" {
+		names, err := parseToolsetList(enabled)
+		if err != nil {
+			return nil, err
+		}
+		f.enabled = map[string]bool{}
+		for _, n := range names {
+			f.enabled[n] = true
+		}
+	}
+	if strings.TrimSpace(disabled) != "" {
+		names, err := parseToolsetList(disabled)
+		if err != nil {
+			return nil, err
+		}
+		for _, n := range names {
+			f.disabled[n] = true
+		}
+	}
+	return f, nil
+}
+
+// parseToolsetList splits a comma-separated list, rejecting unknown toolsets
+func parseToolsetList(list string) ([]string, error) {
+	var names []string
+	for _, n := range strings.Split(list, ",") {
+		n = strings.ToLower(strings.TrimSpace(n))
+		if n == "" {
+			continue
+		}
+		if !isKnownToolset(n) {
+			known := append([]string(nil), knownToolsets...)
+			sort.Strings(known)
+			return nil, fmt.Errorf("unknown toolset %q (available: %s)", n, strings.Join(known, ", "))
+		}
+		names = append(names, n)
+	}
+	return names, nil
+}
+
+func isKnownToolset(name string) bool {
+	for _, k := range knownToolsets {
+		if k == name {
+			return true
+		}
+	}
+	return false
+}
+
+// allows reports whether a toolset must be registered
+func (f *toolsetFilter) allows(name string) bool {
+	if f == nil {
+		return true
+	}
+	if f.disabled[name] {
+		return false
+	}
+	return f.enabled == nil || f.enabled[name]
+}
@@ -0,0 +1,55 @@
+package main
+
+import "testing"
+
+func TestParseToolsetFilter(t *testing.T) {
+	t.Setenv("KRAKEND_MCP_TOOLSETS", "")
+	t.Setenv("KRAKEND_MCP_DISABLE_TOOLSETS", "")
+
+	tests := []struct {
+		name    string
+		args    []string
+		allowed []string
+		denied  []string
+		wantErr bool
+	}{
+		{"default enables all", nil, knownToolsets, nil, false},
+		{"allow list", []string{"--toolsets=validation,search"}, []string{"validation", "search"}, []string{"diagnostics", "generation"}, false},
+		{"separate value", []string{"--http", "--toolsets", "generation"}, []string{"generation"}, []string{"validation"}, false},
+		{"deny list", []string{"--disable-toolsets=diagnostics"}, []string{"validation", "fleet"}, []string{"diagnostics"}, false},
+		{"unknown toolset", []string{"--toolsets=validation,bogus"}, nil, nil, true},
+		{"missing value", []string{"--toolsets"}, nil, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := parseToolsetFilter(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseToolsetFilter() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			for _, n := range tt.allowed {
+				if !f.allows(n) {
+					t.Errorf("Expected %s to be allowed", n)
+				}
+			}
+			for _, n := range tt.denied {
+				if f.allows(n) {
+					t.Errorf("Expected %s to be denied", n)
+				}
+			}
+		})
+	}
+}
+
+func TestParseToolsetFilter_Env(t *testing.T) {
+	t.Setenv("KRAKEND_MCP_TOOLSETS", "validation, features")
+	t.Setenv("KRAKEND_MCP_DISABLE_TOOLSETS", "features")
+
+	f, err := parseToolsetFilter(nil)
+	if err != nil {
+		t.Fatalf("parseToolsetFilter() error = %v", err)
+	}
+	if !f.allows("validation") || f.allows("features") || f.allows("search") {
+		t.Errorf("Unexpected filter from environment: %+v", f)
+	}
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Transports serving the MCP protocol
+const (
+	transportStdio = "stdio"
+	transportHTTP  = "http" // Streamable HTTP on a single endpoint
+	transportSSE   = "sse"  // Legacy HTTP+SSE transport (2024-11-05) for older clients
+)
+
+// defaultServeSessionTimeout closes the sessions of the daemon mode idle for longer, so clients
+// that went away without closing their session do not accumulate
+const defaultServeSessionTimeout = 30 * time.Minute
+
+// transportConfig is how the server talks to its clients
+type transportConfig struct {
+	name           string
+	listen         string        // Listen address of the HTTP transports
+	serve          bool          // Long-lived daemon shared by several clients (--serve)
+	sessionTimeout time.Duration // Idle streamable HTTP sessions are closed after it, 0 keeps them
+}
+
+// parseTransport builds the transport from --transport/--listen flags (--http is a shortcut for
+// --transport=http), falling back to the KRAKEND_MCP_TRANSPORT and KRAKEND_MCP_LISTEN environment
+// variables, then to PORT for the listen address. --serve runs a daemon on the HTTP transport
+// unless --transport selects SSE.
+func parseTransport(args []string) (*transportConfig, error) {
+	name, source, err := lookupSetting(args, "--transport", "KRAKEND_MCP_TRANSPORT")
+	if err != nil {
+		return nil, err
+	}
+	serve := hasFlag(args, "--serve")
+	if source != sourceFlag && (hasFlag(args, "--http") || serve) {
+		name = transportHTTP
+	}
+	listen, _, err := lookupSetting(args, "--listen", "KRAKEND_MCP_LISTEN")
+	if err != nil {
+		return nil, err
+	}
+
+	config := &transportConfig{name: strings.ToLower(strings.TrimSpace(name)), listen: listen, serve: serve}
+	switch config.name {
+	case "":
+		config.name = transportStdio
+	case transportStdio, transportHTTP, transportSSE:
+	default:
+		return nil, fmt.Errorf("unknown transport %q (expected stdio, http or sse)", name)
+	}
+	if serve && config.name == transportStdio {
+		return nil, fmt.Errorf("--serve accepts several clients and requires the http or sse transport")
+	}
+
+	timeout, source, err := lookupSetting(args, "--session-timeout", "KRAKEND_MCP_SESSION_TIMEOUT")
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case source != sourceDefault:
+		if config.sessionTimeout, err = time.ParseDuration(timeout); err != nil || config.sessionTimeout < 0 {
+			return nil, fmt.Errorf("session timeout must be a duration such as 30m (0 keeps idle sessions), got %q", timeout)
+		}
+	case serve:
+		config.sessionTimeout = defaultServeSessionTimeout
+	}
+	if config.listen == "" {
+		port := os.Getenv("PORT")
+		if port == "" {
+			port = defaultHttpPort
+		}
+		config.listen = ":" + port
+	} else if !strings.Contains(config.listen, ":") {
+		config.listen = ":" + config.listen // A bare port
+	}
+	return config, nil
+}
+
+// handler returns the HTTP handler serving the MCP protocol with the transport
+func (c *transportConfig) handler(server *mcp.Server) http.Handler {
+	getServer := func(_ *http.Request) *mcp.Server {
+		return server
+	}
+	if c.name == transportSSE {
+		return mcp.NewSSEHandler(getServer, nil)
+	}
+	return mcp.NewStreamableHTTPHandler(getServer, &mcp.StreamableHTTPOptions{
+		Stateless:      false,
+		JSONResponse:   true,
+		SessionTimeout: c.sessionTimeout,
+	})
+}
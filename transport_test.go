@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/krakend/mcp-server/tools"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestParseTransport(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		env        map[string]string
+		wantName   string
+		wantListen string
+		wantErr    bool
+	}{
+		{name: "default stdio", wantName: "stdio", wantListen: ":8090"},
+		{name: "http shortcut", args: []string{"--http"}, wantName: "http", wantListen: ":8090"},
+		{name: "transport flag", args: []string{"--transport=sse", "--listen", "127.0.0.1:9000"}, wantName: "sse", wantListen: "127.0.0.1:9000"},
+		{name: "separate value", args: []string{"--transport", "HTTP", "--listen=9100"}, wantName: "http", wantListen: ":9100"},
+		{name: "environment", env: map[string]string{"KRAKEND_MCP_TRANSPORT": "http", "PORT": "9200"}, wantName: "http", wantListen: ":9200"},
+		{name: "flag overrides environment", args: []string{"--transport=stdio"}, env: map[string]string{"KRAKEND_MCP_TRANSPORT": "http", "KRAKEND_MCP_LISTEN": "0.0.0.0:80"}, wantName: "stdio", wantListen: "0.0.0.0:80"},
+		{name: "unknown transport", args: []string{"--transport=grpc"}, wantErr: true},
+		{name: "missing value", args: []string{"--listen"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, key := range []string{"KRAKEND_MCP_TRANSPORT", "KRAKEND_MCP_LISTEN", "PORT"} {
+				t.Setenv(key, tt.env[key])
+			}
+			config, err := parseTransport(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseTransport() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if config.name != tt.wantName || config.listen != tt.wantListen {
+				t.Errorf("parseTransport() = %s on %s, want %s on %s", config.name, config.listen, tt.wantName, tt.wantListen)
+			}
+		})
+	}
+}
+
+func TestParseTransport_Serve(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        []string
+		env         string
+		wantName    string
+		wantTimeout time.Duration
+		wantErr     bool
+	}{
+		{name: "serve", args: []string{"--serve"}, wantName: "http", wantTimeout: defaultServeSessionTimeout},
+		{name: "serve over sse", args: []string{"--serve", "--transport=sse"}, wantName: "sse", wantTimeout: defaultServeSessionTimeout},
+		{name: "custom timeout", args: []string{"--serve", "--session-timeout=2h"}, wantName: "http", wantTimeout: 2 * time.Hour},
+		{name: "sessions kept", args: []string{"--serve"}, env: "0", wantName: "http"},
+		{name: "http keeps sessions", args: []string{"--http"}, wantName: "http"},
+		{name: "serve over stdio", args: []string{"--serve", "--transport=stdio"}, wantErr: true},
+		{name: "invalid timeout", args: []string{"--serve", "--session-timeout=-1m"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("KRAKEND_MCP_TRANSPORT", "")
+			t.Setenv("KRAKEND_MCP_SESSION_TIMEOUT", tt.env)
+			config, err := parseTransport(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseTransport() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if config.name != tt.wantName || config.sessionTimeout != tt.wantTimeout || config.serve != hasFlag(tt.args, "--serve") {
+				t.Errorf("parseTransport() = %+v", config)
+			}
+		})
+	}
+}
+
+func TestTransportHandler_ConcurrentSessions(t *testing.T) {
+	server := mcp.NewServer(&mcp.Implementation{Name: serverName, Version: version}, nil)
+	tools.RegisterServerConfigTools(server, []tools.ServerSetting{{Name: "transport", Value: "http", Source: "flag"}})
+	config := &transportConfig{name: transportHTTP, serve: true, sessionTimeout: time.Minute}
+	httpServer := httptest.NewServer(config.handler(server))
+	defer httpServer.Close()
+
+	const clients = 4
+	var wg sync.WaitGroup
+	errs := make(chan error, clients)
+	for i := 0; i < clients; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			client := mcp.NewClient(&mcp.Implementation{Name: fmt.Sprintf("client-%d", i), Version: "1.0.0"}, nil)
+			session, err := client.Connect(context.Background(), &mcp.StreamableClientTransport{Endpoint: httpServer.URL}, nil)
+			if err != nil {
+				errs <- err
+				return
+			}
+			defer session.Close()
+			for j := 0; j < 3; j++ {
+				res, err := session.CallTool(context.Background(), &mcp.CallToolParams{Name: "get_server_config"})
+				if err != nil || res.IsError {
+					errs <- fmt.Errorf("client %d: %v %+v", i, err, res)
+					return
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+
+	sessions := 0
+	for range server.Sessions() {
+		sessions++
+	}
+	if sessions != 0 {
+		t.Errorf("Expected the closed sessions to be released, %d left", sessions)
+	}
+}